@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+// API keys let services reach the admin endpoints without a user session.
+// adminctl creates and revokes them, and internal/maintenance holds the key
+// operations both share; each key is limited to its scopes and may expire.
+// Only the key's hash is stored.
+
+// apiKeyHeader is where services send their key.
+const apiKeyHeader = "X-API-Key"
+
+// Scopes an API key can be granted, each covering a group of admin routes.
+const (
+	apiKeyScopeModeration = maintenance.APIKeyScopeModeration
+	apiKeyScopeWebhooks   = maintenance.APIKeyScopeWebhooks
+	apiKeyScopeDebug      = maintenance.APIKeyScopeDebug
+)
+
+var ErrInvalidAPIKey = maintenance.ErrInvalidAPIKey
+
+// APIKey is a credential a service, like an analytics exporter or moderation
+// worker, sends as X-API-Key. The key itself is only shown when created.
+type APIKey = maintenance.APIKey
+
+const createTableAPIKeys = `
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME,
+    last_used_at DATETIME
+);
+`
+
+func (r *EventRepository) initAPIKeys(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableAPIKeys); err != nil {
+		return fmt.Errorf("create api keys table: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves a key to its record, recording the use at most
+// once a minute. It returns ErrInvalidAPIKey for unknown and expired keys.
+func (r *EventRepository) AuthenticateAPIKey(ctx context.Context, secret string, now time.Time) (*APIKey, error) {
+	return maintenance.AuthenticateAPIKey(ctx, r.db, secret, now)
+}
+
+// apiKeyMiddleware admits services sending an X-API-Key granted scope. No
+// session is involved: gin.AuthUserKey is set to the key's name, as basic
+// auth sets it to the admin's username, so admin handlers treat the service
+// as an admin.
+func apiKeyMiddleware(repo APIKeyStore, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := strings.TrimSpace(c.GetHeader(apiKeyHeader))
+		if secret == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing api key"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+		key, err := repo.AuthenticateAPIKey(ctx, secret, time.Now())
+		cancel()
+		if err != nil {
+			if errors.Is(err, ErrInvalidAPIKey) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired api key"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to verify api key"})
+			return
+		}
+		if !slices.Contains(key.Scopes, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key lacks the " + scope + " scope"})
+			return
+		}
+
+		c.Set(gin.AuthUserKey, "api-key:"+key.Name)
+		c.Next()
+	}
+}
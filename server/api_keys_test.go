@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+// mustCreateAPIKey stores a key and returns its secret, failing the test on error.
+func mustCreateAPIKey(t *testing.T, repo *EventRepository, name string, expiresAt *time.Time, scopes ...string) string {
+	t.Helper()
+	_, secret, err := maintenance.CreateAPIKey(context.Background(), repo.db, name, scopes, expiresAt)
+	if err != nil {
+		t.Fatalf("create api key %s: %v", name, err)
+	}
+	return secret
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	repo := newTestRepository(t)
+	expired := time.Now().Add(-time.Minute)
+	moderation := mustCreateAPIKey(t, repo, "moderation-bot", nil, apiKeyScopeModeration)
+	everything := mustCreateAPIKey(t, repo, "ops", nil, maintenance.APIKeyScopes...)
+	revoked := mustCreateAPIKey(t, repo, "retired-bot", nil, apiKeyScopeModeration)
+	stale := mustCreateAPIKey(t, repo, "stale-bot", &expired, apiKeyScopeModeration)
+	if err := maintenance.RevokeAPIKey(context.Background(), repo.db, "retired-bot"); err != nil {
+		t.Fatalf("revoke api key: %v", err)
+	}
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.String(http.StatusOK, c.GetString(gin.AuthUserKey)) }
+	router.GET("/admin/flags", apiKeyMiddleware(repo, apiKeyScopeModeration), ok)
+	router.GET("/debug/hub", apiKeyMiddleware(repo, apiKeyScopeDebug), ok)
+
+	tests := []struct {
+		name string
+		path string
+		key  string
+		want int
+	}{
+		{"in scope", "/admin/flags", moderation, http.StatusOK},
+		{"every scope", "/debug/hub", everything, http.StatusOK},
+		{"out of scope", "/debug/hub", moderation, http.StatusForbidden},
+		{"revoked", "/admin/flags", revoked, http.StatusUnauthorized},
+		{"expired", "/admin/flags", stale, http.StatusUnauthorized},
+		{"unknown", "/admin/flags", maintenance.APIKeyPrefix + strings.Repeat("0", 48), http.StatusUnauthorized},
+		{"no prefix", "/admin/flags", strings.TrimPrefix(moderation, maintenance.APIKeyPrefix), http.StatusUnauthorized},
+		{"missing", "/admin/flags", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assertStatus(t, rec, tt.want)
+			if tt.want == http.StatusOK && !strings.HasPrefix(rec.Body.String(), "api-key:") {
+				t.Fatalf("auth user = %q, want the key's name", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyStoresOnlyHash(t *testing.T) {
+	repo := newTestRepository(t)
+	secret := mustCreateAPIKey(t, repo, "moderation-bot", nil, apiKeyScopeModeration)
+	if !strings.HasPrefix(secret, maintenance.APIKeyPrefix) {
+		t.Fatalf("secret %q lacks the %s prefix", secret, maintenance.APIKeyPrefix)
+	}
+
+	rows, err := repo.db.Query(`SELECT * FROM api_keys`)
+	if err != nil {
+		t.Fatalf("read api keys: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("read columns: %v", err)
+	}
+	stored := 0
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			t.Fatalf("scan api key: %v", err)
+		}
+		for i, value := range values {
+			var text string
+			switch v := value.(type) {
+			case string:
+				text = v
+			case []byte:
+				text = string(v)
+			}
+			if strings.Contains(text, strings.TrimPrefix(secret, maintenance.APIKeyPrefix)) {
+				t.Fatalf("column %s stores the plaintext key", columns[i])
+			}
+		}
+		stored++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterate api keys: %v", err)
+	}
+	if stored != 1 {
+		t.Fatalf("stored keys = %d, want 1", stored)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM api_keys WHERE key_hash = ?`, hashSecretToken(secret)); got != 1 {
+		t.Fatalf("keys stored under the key's hash = %d, want 1", got)
+	}
+
+	// The key still authenticates, by its hash.
+	if _, err := repo.AuthenticateAPIKey(context.Background(), secret, time.Now()); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+}
@@ -4,21 +4,44 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
-    repo   *EventRepository
-    signer *tokenSigner
+    repo     UserStore
+    signer   *tokenSigner
+    mailer   EmailSender // nil disables password reset and magic links
+    sms      SMSSender   // nil disables phone sign-in
+    identity map[string]*identityProvider
+    resetURL string
+    magicURL string
+    geoIP    string // header a fronting proxy puts the caller's city in
 }
 
-func NewAuthHandler(repo *EventRepository, signer *tokenSigner) *AuthHandler {
-    return &AuthHandler{repo: repo, signer: signer}
+func NewAuthHandler(repo UserStore, signer *tokenSigner, mailer EmailSender, sms SMSSender) *AuthHandler {
+    return &AuthHandler{
+        repo:     repo,
+        signer:   signer,
+        mailer:   mailer,
+        sms:      sms,
+        identity: newIdentityProvidersFromEnv(),
+        resetURL: passwordResetURLFromEnv(),
+        magicURL: magicLinkURLFromEnv(),
+        geoIP:    sessionLocationHeaderFromEnv(),
+    }
 }
 
 func (h *AuthHandler) RegisterRoutes(group *gin.RouterGroup) {
 	group.POST("/login", h.login)
+	group.POST("/login/magic", h.requestMagicLink)
+	group.POST("/login/magic/verify", h.signInWithMagicLink)
+	group.POST("/password-reset", h.requestPasswordReset)
+	group.POST("/password-reset/confirm", h.confirmPasswordReset)
+	group.POST("/otp/request", h.requestOTP)
+	group.POST("/otp/verify", h.verifyOTP)
+	group.POST("/oauth/:provider", h.signInWithProvider)
 }
 
 type loginRequest struct {
@@ -47,7 +70,26 @@ func (h *AuthHandler) login(c *gin.Context) {
 		return
 	}
 
-	token, claims, err := h.signer.issue(user.ID, user.Email)
+	h.respondWithSession(c, ctx, user)
+}
+
+// respondWithSession stores a session for the signing-in device, issues its
+// token and answers with it, the same way for every sign-in method.
+func (h *AuthHandler) respondWithSession(c *gin.Context, ctx context.Context, user *User) {
+	roles, err := h.repo.UserRoles(ctx, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	expiresAt := time.Now().Add(h.signer.ttl)
+	sessionID, err := h.repo.CreateSession(ctx, user.ID, sessionDeviceName(c.Request), c.ClientIP(), sessionLocation(c.Request, h.geoIP), expiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	token, claims, err := h.signer.issue(user.ID, user.Email, sessionID, roles, expiresAt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue session token"})
 		return
@@ -60,6 +102,7 @@ func (h *AuthHandler) login(c *gin.Context) {
 			"email": user.Email,
 		},
 		"token":      token,
+		"roles":      claims.Roles,
 		"expires_at": claims.ExpiresAt,
 	})
 }
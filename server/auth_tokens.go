@@ -4,11 +4,12 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,6 +17,17 @@ import (
 // defaultSessionTTL controls how long issued chat tokens remain valid.
 const defaultSessionTTL = 12 * time.Hour
 
+// sessionTokenIssuer is the iss claim of every session token.
+const sessionTokenIssuer = "who-else-is-free"
+
+// Roles a session token can carry. Every signed-in person is a user, admins
+// are users too, and bot tokens carry only the bot role.
+const (
+	roleUser  = "user"
+	roleAdmin = "admin"
+	roleBot   = "bot"
+)
+
 var (
 	errMissingSecret  = errors.New("chat session secret is not configured")
 	errInvalidToken   = errors.New("invalid session token")
@@ -23,77 +35,176 @@ var (
 	errMalformedToken = errors.New("malformed session token")
 )
 
-// sessionClaims is serialized into the token payload so both REST and WebSocket
-// layers can identify the caller without re-querying the database.
+// sessionClaims identifies the caller so both REST and WebSocket layers can
+// trust a token without re-querying the database. Session tokens carry them
+// as standard JWT claims; see jwtSessionClaims.
 type sessionClaims struct {
-	UserID    int64     `json:"user_id"`
-	Email     string    `json:"email"`
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+	// SessionID is the stored session the token belongs to, which can be
+	// revoked. Tokens issued before sessions were stored have none.
+	SessionID int64 `json:"session_id,omitempty"`
+	// Roles are granted at sign-in and checked by requireRole. Tokens
+	// issued before roles existed have none and count as user tokens.
+	Roles     []string  `json:"roles,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
-// tokenSigner is a lightweight HMAC-based signer/validator for session tokens.
+// hasRole reports whether the token was granted role.
+func (c *sessionClaims) hasRole(role string) bool {
+	if len(c.Roles) == 0 {
+		return role == roleUser
+	}
+	return slices.Contains(c.Roles, role)
+}
+
+// jwtHeader is the JOSE header of a session token. Kid names the key that
+// signed it, so tokens keep verifying after the signing key rotates.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// jwtSessionClaims is the JWT payload of a session token.
+type jwtSessionClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+	Email     string   `json:"email"`
+	SessionID int64    `json:"sid,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// tokenSigner issues and verifies HS256 session tokens. New tokens are signed
+// with the current key; tokens signed with any retired key still verify until
+// they expire, so rotating the secret logs nobody out.
 type tokenSigner struct {
-	secret []byte
-	ttl    time.Duration
+	keys    map[string][]byte // kid -> HMAC secret, current and retired
+	current string            // kid new tokens are signed with
+	ttl     time.Duration
 }
 
-// newTokenSignerFromEnv loads the secret from server/.env (or falls back to a
-// noisy dev default) so both CLI and production processes share the same token key.
-func newTokenSignerFromEnv() (*tokenSigner, error) {
-	secret := strings.TrimSpace(os.Getenv("CHAT_SESSION_SECRET"))
-	if secret == "" {
-		log.Println("CHAT_SESSION_SECRET not set; using development fallback secret")
-		secret = "local-dev-secret"
+// newTokenSigner signs with the configured secret so both CLI and production
+// processes share the same token key, and accepts the previous secrets.
+func newTokenSigner(config Config) *tokenSigner {
+	s := &tokenSigner{
+		keys:    map[string][]byte{},
+		current: sessionKeyID(config.SessionSecret),
+		ttl:     config.SessionTTL,
+	}
+	s.keys[s.current] = []byte(config.SessionSecret)
+	for _, secret := range config.PreviousSessionSecrets {
+		if kid := sessionKeyID(secret); kid != s.current {
+			s.keys[kid] = []byte(secret)
+		}
 	}
-	ttl := defaultSessionTTL
-	return &tokenSigner{secret: []byte(secret), ttl: ttl}, nil
+	return s
+}
+
+// sessionKeyID derives a key's kid from its secret, so operators rotate by
+// moving the old secret to CHAT_SESSION_PREVIOUS_SECRETS without naming keys.
+// The hash is truncated and reveals nothing useful about the secret.
+func sessionKeyID(secret string) string {
+	sum := sha256.Sum256([]byte("kid:" + secret))
+	return hex.EncodeToString(sum[:8])
 }
 
 // issue creates a signed token describing the current user; callers return both
-// the opaque token string and the structured claims for convenience.
-func (s *tokenSigner) issue(userID int64, email string) (string, *sessionClaims, error) {
-	now := time.Now().UTC()
+// the opaque token string and the structured claims for convenience. JWT times
+// are whole seconds, so the returned claims are truncated to match.
+func (s *tokenSigner) issue(userID int64, email string, sessionID int64, roles []string, expiresAt time.Time) (string, *sessionClaims, error) {
 	claims := sessionClaims{
 		UserID:    userID,
 		Email:     email,
-		IssuedAt:  now,
-		ExpiresAt: now.Add(s.ttl),
+		SessionID: sessionID,
+		Roles:     roles,
+		IssuedAt:  time.Now().UTC().Truncate(time.Second),
+		ExpiresAt: expiresAt.UTC().Truncate(time.Second),
 	}
 
-	payloadBytes, err := json.Marshal(claims)
+	headerBytes, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT", Kid: s.current})
+	if err != nil {
+		return "", nil, fmt.Errorf("encode token header: %w", err)
+	}
+	payloadBytes, err := json.Marshal(jwtSessionClaims{
+		Issuer:    sessionTokenIssuer,
+		Subject:   strconv.FormatInt(userID, 10),
+		IssuedAt:  claims.IssuedAt.Unix(),
+		ExpiresAt: claims.ExpiresAt.Unix(),
+		Email:     email,
+		SessionID: sessionID,
+		Roles:     roles,
+	})
 	if err != nil {
 		return "", nil, fmt.Errorf("encode claims: %w", err)
 	}
 
-	payload := base64.RawURLEncoding.EncodeToString(payloadBytes)
-	signature := s.sign([]byte(payload))
-	token := fmt.Sprintf("%s.%s", payload, signature)
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(payloadBytes)
+	token := signingInput + "." + s.sign([]byte(signingInput))
 	return token, &claims, nil
 }
 
 // verify checks signature + expiry and rebuilds the claims for downstream use.
 func (s *tokenSigner) verify(token string) (*sessionClaims, error) {
 	parts := strings.Split(token, ".")
-	if len(parts) != 2 {
+	if len(parts) == 2 {
+		return s.verifyLegacy(parts[0], parts[1])
+	}
+	if len(parts) != 3 {
 		return nil, errMalformedToken
 	}
 
-	payloadPart := parts[0]
-	signaturePart := parts[1]
-
-	expected := s.sign([]byte(payloadPart))
-	if !hmac.Equal([]byte(signaturePart), []byte(expected)) {
+	var header jwtHeader
+	if err := decodeTokenSegment(parts[0], &header); err != nil {
+		return nil, errMalformedToken
+	}
+	// Only HS256 is issued today; an RS256 case would look its public key up
+	// by kid here. Anything else, "none" included, is rejected outright.
+	if header.Alg != "HS256" {
+		return nil, errInvalidToken
+	}
+	secret, ok := s.keys[header.Kid]
+	if !ok || !hmac.Equal([]byte(parts[2]), []byte(signHMAC(secret, []byte(parts[0]+"."+parts[1])))) {
 		return nil, errInvalidToken
 	}
 
-	payloadBytes, err := base64.RawURLEncoding.DecodeString(payloadPart)
-	if err != nil {
+	var payload jwtSessionClaims
+	if err := decodeTokenSegment(parts[1], &payload); err != nil || payload.Issuer != sessionTokenIssuer {
 		return nil, errMalformedToken
 	}
+	userID, err := strconv.ParseInt(payload.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, errMalformedToken
+	}
+
+	claims := sessionClaims{
+		UserID:    userID,
+		Email:     payload.Email,
+		SessionID: payload.SessionID,
+		Roles:     payload.Roles,
+		IssuedAt:  time.Unix(payload.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(payload.ExpiresAt, 0).UTC(),
+	}
+	if time.Now().UTC().After(claims.ExpiresAt) {
+		return nil, errExpiredToken
+	}
+
+	return &claims, nil
+}
+
+// verifyLegacy accepts the payload.signature tokens issued before session
+// tokens became JWTs, until they expire. It can go once SESSION_TTL has
+// passed since every server issues JWTs.
+func (s *tokenSigner) verifyLegacy(payloadPart, signaturePart string) (*sessionClaims, error) {
+	if !s.validSignature([]byte(payloadPart), signaturePart) {
+		return nil, errInvalidToken
+	}
 
 	var claims sessionClaims
-	if err := json.Unmarshal(payloadBytes, &claims); err != nil {
+	if err := decodeTokenSegment(payloadPart, &claims); err != nil {
 		return nil, errMalformedToken
 	}
 
@@ -104,9 +215,34 @@ func (s *tokenSigner) verify(token string) (*sessionClaims, error) {
 	return &claims, nil
 }
 
+// sign signs payload with the current key. Invites, check-in codes and magic
+// links use it under their own prefixes.
 func (s *tokenSigner) sign(payload []byte) string {
-	mac := hmac.New(sha256.New, s.secret)
+	return signHMAC(s.keys[s.current], payload)
+}
+
+// validSignature reports whether signature is payload signed with any known
+// key. Signatures made by sign carry no kid, so each key is tried in turn;
+// this keeps long-lived codes working across a rotation.
+func (s *tokenSigner) validSignature(payload []byte, signature string) bool {
+	for _, secret := range s.keys {
+		if hmac.Equal([]byte(signature), []byte(signHMAC(secret, payload))) {
+			return true
+		}
+	}
+	return false
+}
+
+func signHMAC(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
 	mac.Write(payload)
 	sum := mac.Sum(nil)
 	return base64.RawURLEncoding.EncodeToString(sum)
 }
+
+// hashSecretToken is how single-use tokens, codes and keys are looked up
+// without storing them: password resets, OTPs, magic link nonces and API keys.
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
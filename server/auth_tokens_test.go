@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSessionSecret = "current-secret"
+	testRetiredSecret = "retired-secret"
+)
+
+func newTestSigner(secret string, previous ...string) *tokenSigner {
+	return newTokenSigner(Config{SessionSecret: secret, PreviousSessionSecrets: previous, SessionTTL: time.Hour})
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encode segment: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// craftToken signs header and claims with secret the way issue does, so cases
+// can vary one field at a time.
+func craftToken(t *testing.T, secret string, header jwtHeader, claims jwtSessionClaims) string {
+	t.Helper()
+	signingInput := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	return signingInput + "." + signHMAC([]byte(secret), []byte(signingInput))
+}
+
+// craftLegacyToken builds a payload.signature token as issued before JWTs.
+func craftLegacyToken(t *testing.T, secret string, claims sessionClaims) string {
+	t.Helper()
+	payload := encodeSegment(t, claims)
+	return payload + "." + signHMAC([]byte(secret), []byte(payload))
+}
+
+func TestTokenSignerVerify(t *testing.T) {
+	now := time.Now().UTC()
+	signer := newTestSigner(testSessionSecret, testRetiredSecret)
+	header := jwtHeader{Alg: "HS256", Typ: "JWT", Kid: sessionKeyID(testSessionSecret)}
+	claims := jwtSessionClaims{
+		Issuer:    sessionTokenIssuer,
+		Subject:   "42",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		Email:     "ana@example.com",
+		SessionID: 7,
+	}
+	// with signs a copy of the valid header and claims after edit changes them.
+	with := func(edit func(*jwtHeader, *jwtSessionClaims)) string {
+		h, c := header, claims
+		edit(&h, &c)
+		return craftToken(t, testSessionSecret, h, c)
+	}
+
+	valid, _, err := signer.issue(42, "ana@example.com", 7, nil, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	retired, _, err := newTestSigner(testRetiredSecret).issue(42, "ana@example.com", 7, nil, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("issue with the retired key: %v", err)
+	}
+	expired, _, err := signer.issue(42, "ana@example.com", 7, nil, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("issue expired: %v", err)
+	}
+	parts := strings.Split(valid, ".")
+	otherPayload := encodeSegment(t, jwtSessionClaims{Issuer: sessionTokenIssuer, Subject: "1", ExpiresAt: claims.ExpiresAt})
+	legacyClaims := sessionClaims{UserID: 42, Email: "ana@example.com", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"valid", valid, nil},
+		{"retired kid", retired, nil},
+		{"unknown kid", with(func(h *jwtHeader, _ *jwtSessionClaims) { h.Kid = "unknown" }), errInvalidToken},
+		{"unknown key, known kid", craftToken(t, "stolen-secret", header, claims), errInvalidToken},
+		{"alg none", encodeSegment(t, jwtHeader{Alg: "none", Typ: "JWT"}) + "." + encodeSegment(t, claims) + ".", errInvalidToken},
+		{"alg HS512", with(func(h *jwtHeader, _ *jwtSessionClaims) { h.Alg = "HS512" }), errInvalidToken},
+		{"alg RS256", with(func(h *jwtHeader, _ *jwtSessionClaims) { h.Alg = "RS256" }), errInvalidToken},
+		{"expired", expired, errExpiredToken},
+		{"tampered signature", parts[0] + "." + parts[1] + "." + signHMAC([]byte(testSessionSecret), []byte("other")), errInvalidToken},
+		{"tampered payload", parts[0] + "." + otherPayload + "." + parts[2], errInvalidToken},
+		{"wrong issuer", with(func(_ *jwtHeader, c *jwtSessionClaims) { c.Issuer = "someone-else" }), errMalformedToken},
+		{"bad subject", with(func(_ *jwtHeader, c *jwtSessionClaims) { c.Subject = "ana" }), errMalformedToken},
+		{"too many parts", valid + ".extra", errMalformedToken},
+		{"one part", "not-a-token", errMalformedToken},
+		{"header not base64", "%%%." + parts[1] + "." + parts[2], errMalformedToken},
+		{"legacy", craftLegacyToken(t, testSessionSecret, legacyClaims), nil},
+		{"legacy, retired key", craftLegacyToken(t, testRetiredSecret, legacyClaims), nil},
+		{"legacy, unknown key", craftLegacyToken(t, "stolen-secret", legacyClaims), errInvalidToken},
+		{"legacy, expired", craftLegacyToken(t, testSessionSecret, sessionClaims{UserID: 42, ExpiresAt: now.Add(-time.Minute)}), errExpiredToken},
+		{"legacy, not json", "bm90LWpzb24." + signHMAC([]byte(testSessionSecret), []byte("bm90LWpzb24")), errMalformedToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signer.verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.UserID != 42 || got.Email != "ana@example.com" {
+				t.Fatalf("claims = %+v, want user 42 ana@example.com", got)
+			}
+		})
+	}
+}
+
+func TestTokenSignerDropsRemovedKeys(t *testing.T) {
+	token, _, err := newTestSigner(testRetiredSecret).issue(42, "ana@example.com", 7, nil, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	// Once the retired secret leaves CHAT_SESSION_PREVIOUS_SECRETS, its tokens stop verifying.
+	if _, err := newTestSigner(testSessionSecret).verify(token); !errors.Is(err, errInvalidToken) {
+		t.Fatalf("verify error = %v, want %v", err, errInvalidToken)
+	}
+}
+
+func TestTokenSignerIssueRoundTrip(t *testing.T) {
+	signer := newTestSigner(testSessionSecret)
+	expiresAt := time.Now().Add(time.Hour)
+	token, issued, err := signer.issue(42, "ana@example.com", 7, []string{roleUser, roleAdmin}, expiresAt)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	got, err := signer.verify(token)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if got.SessionID != 7 || !got.hasRole(roleAdmin) || !got.ExpiresAt.Equal(issued.ExpiresAt) || !got.IssuedAt.Equal(issued.IssuedAt) {
+		t.Fatalf("verified claims = %+v, want %+v", got, issued)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrAvailabilityNotFound = errors.New("availability not found")
+
+// maxAvailabilityWindow bounds a single "I'm free" broadcast.
+const maxAvailabilityWindow = 24 * time.Hour
+
+// Each user has at most one availability window; setting a new one replaces it.
+const createTableUserAvailability = `
+CREATE TABLE IF NOT EXISTS user_availability (
+    user_id INTEGER PRIMARY KEY,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    note TEXT,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexUserAvailabilityEnds = `
+CREATE INDEX IF NOT EXISTS idx_user_availability_ends_at
+ON user_availability(ends_at);
+`
+
+const upsertUserAvailability = `
+INSERT INTO user_availability (user_id, starts_at, ends_at, note, updated_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(user_id) DO UPDATE SET
+    starts_at = excluded.starts_at,
+    ends_at = excluded.ends_at,
+    note = excluded.note,
+    updated_at = CURRENT_TIMESTAMP;
+`
+
+const selectAvailabilityColumns = `
+SELECT a.user_id, u.name, a.starts_at, a.ends_at, a.note, a.updated_at
+FROM user_availability a
+JOIN users u ON u.id = a.user_id
+`
+
+const selectUserAvailability = selectAvailabilityColumns + `
+WHERE a.user_id = ? AND a.ends_at > ?;
+`
+
+// selectAvailabilityFeed only shows windows from the viewer's accepted
+// connections; the viewer id is bound twice.
+const selectAvailabilityFeed = selectAvailabilityColumns + `
+JOIN connections c ON c.status = 'accepted'
+    AND ((c.requester_id = ? AND c.addressee_id = a.user_id)
+      OR (c.addressee_id = ? AND c.requester_id = a.user_id))
+WHERE a.ends_at > ? AND u.deleted_at IS NULL
+ORDER BY a.starts_at ASC, a.user_id ASC;
+`
+
+const deleteUserAvailability = `
+DELETE FROM user_availability
+WHERE user_id = ?;
+`
+
+func (r *EventRepository) initAvailability(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableUserAvailability); err != nil {
+		return fmt.Errorf("create user availability table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexUserAvailabilityEnds); err != nil {
+		return fmt.Errorf("create user availability index: %w", err)
+	}
+	return nil
+}
+
+// SetAvailability replaces the user's availability window.
+func (r *EventRepository) SetAvailability(ctx context.Context, userID int64, params SetAvailabilityParams) (*Availability, error) {
+	var note sql.NullString
+	if params.Note != nil && strings.TrimSpace(*params.Note) != "" {
+		note = sql.NullString{String: strings.TrimSpace(*params.Note), Valid: true}
+	}
+	if _, err := r.db.ExecContext(ctx, upsertUserAvailability,
+		userID,
+		sqliteTime(params.StartsAt),
+		sqliteTime(params.EndsAt),
+		note,
+	); err != nil {
+		return nil, fmt.Errorf("set availability: %w", err)
+	}
+	return r.GetAvailability(ctx, userID)
+}
+
+// GetAvailability returns the user's window if it hasn't ended yet.
+func (r *EventRepository) GetAvailability(ctx context.Context, userID int64) (*Availability, error) {
+	availability, err := scanAvailability(r.db.QueryRowContext(ctx, selectUserAvailability, userID, sqliteTime(time.Now())))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAvailabilityNotFound
+		}
+		return nil, fmt.Errorf("fetch availability: %w", err)
+	}
+	return &availability, nil
+}
+
+// ClearAvailability removes the user's window, if any.
+func (r *EventRepository) ClearAvailability(ctx context.Context, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, deleteUserAvailability, userID); err != nil {
+		return fmt.Errorf("clear availability: %w", err)
+	}
+	return nil
+}
+
+// ListAvailabilityFeed returns the viewer's connections who are free now or
+// later, soonest first.
+func (r *EventRepository) ListAvailabilityFeed(ctx context.Context, viewerID int64) ([]Availability, error) {
+	rows, err := r.db.QueryContext(ctx, selectAvailabilityFeed, viewerID, viewerID, sqliteTime(time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("list availability: %w", err)
+	}
+	defer rows.Close()
+
+	feed := []Availability{}
+	for rows.Next() {
+		availability, err := scanAvailability(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan availability: %w", err)
+		}
+		feed = append(feed, availability)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate availability: %w", err)
+	}
+	return feed, nil
+}
+
+func scanAvailability(row rowScanner) (Availability, error) {
+	var availability Availability
+	var note sql.NullString
+	err := row.Scan(
+		&availability.UserID,
+		&availability.UserName,
+		&availability.StartsAt,
+		&availability.EndsAt,
+		&note,
+		&availability.UpdatedAt,
+	)
+	if note.Valid {
+		value := note.String
+		availability.Note = &value
+	}
+	return availability, err
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrBannedFromEvent = errors.New("user is banned from the event")
+
+const createTableEventBans = `
+CREATE TABLE IF NOT EXISTS event_bans (
+    event_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    banned_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, user_id),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (banned_by) REFERENCES users(id)
+);
+`
+
+const insertEventBan = `
+INSERT OR IGNORE INTO event_bans (event_id, user_id, banned_by)
+VALUES (?, ?, ?);
+`
+
+const deleteEventBan = `
+DELETE FROM event_bans
+WHERE event_id = ? AND user_id = ?;
+`
+
+const checkEventBan = `
+SELECT 1
+FROM event_bans
+WHERE event_id = ? AND user_id = ?
+LIMIT 1;
+`
+
+const selectEventBans = `
+SELECT b.user_id, u.name, b.banned_by, b.created_at
+FROM event_bans b
+JOIN users u ON u.id = b.user_id
+WHERE b.event_id = ?
+ORDER BY b.created_at DESC, b.user_id;
+`
+
+func (r *EventRepository) initEventBans(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventBans); err != nil {
+		return fmt.Errorf("create event bans table: %w", err)
+	}
+	return nil
+}
+
+// isBannedFromEvent reports whether userID has been banned from the event.
+func isBannedFromEvent(ctx context.Context, q rowQuery, eventID, userID int64) (bool, error) {
+	var banned int
+	if err := q.QueryRowContext(ctx, checkEventBan, eventID, userID).Scan(&banned); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("check event ban: %w", err)
+	}
+	return true, nil
+}
+
+// ListEventBans returns the users banned from an event, most recent first.
+// Only the host and co-hosts may see it.
+func (r *EventRepository) ListEventBans(ctx context.Context, eventID, actorID int64) ([]EventBan, error) {
+	if err := r.RequireEventModerator(ctx, eventID, actorID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectEventBans, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("list event bans: %w", err)
+	}
+	defer rows.Close()
+
+	bans := []EventBan{}
+	for rows.Next() {
+		var ban EventBan
+		if err := rows.Scan(&ban.UserID, &ban.Name, &ban.BannedBy, &ban.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event ban: %w", err)
+		}
+		bans = append(bans, ban)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate event bans: %w", err)
+	}
+	return bans, nil
+}
+
+// UnbanEventUser lifts a ban so the user can request to join again. Lifting a
+// ban that doesn't exist is a no-op.
+func (r *EventRepository) UnbanEventUser(ctx context.Context, eventID, actorID, userID int64) error {
+	if err := r.RequireEventModerator(ctx, eventID, actorID); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, deleteEventBan, eventID, userID); err != nil {
+		return fmt.Errorf("delete event ban: %w", err)
+	}
+	return nil
+}
+
+// RequireEventModerator loads the event and its chat, then applies
+// checkEventModerator.
+func (r *EventRepository) RequireEventModerator(ctx context.Context, eventID, userID int64) error {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	return checkEventModerator(ctx, r.db, event, convo.ID, userID)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMaxBodyBytes   = 64 << 10
+	defaultMaxUploadBytes = 10 << 20
+	defaultMaxJSONDepth   = 32
+)
+
+// bodyLimitMiddleware caps request bodies at maxBody bytes, or maxUpload for
+// multipart uploads, answering 413 past it. Every other body is read up front
+// and rejected with 422 when nested deeper than maxDepth, before any handler
+// decodes it. That includes bodies not labelled application/json, since
+// ShouldBindJSON decodes whatever it is given.
+func bodyLimitMiddleware(maxBody, maxUpload int64, maxDepth int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		limit := maxBody
+		if mediaType == "multipart/form-data" {
+			limit = maxUpload
+		}
+		if c.Request.ContentLength > limit {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		if mediaType == "multipart/form-data" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		if jsonDepthExceeds(body, maxDepth) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "request body is nested too deeply", "max_depth": maxDepth})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large", "limit": limit})
+}
+
+// jsonDepthExceeds reports whether body nests objects or arrays more than
+// maxDepth levels deep. Brackets inside strings don't count; malformed JSON
+// is left for the handler's decoder to reject.
+func jsonDepthExceeds(body []byte, maxDepth int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, b := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if b == '\\' {
+				escaped = true
+			} else if b == '"' {
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	const maxDepth = 4
+	deep := strings.Repeat("[", maxDepth+1) + strings.Repeat("]", maxDepth+1)
+	shallow := `{"title":"Evening run","tags":["Running"]}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"shallow json", "application/json", shallow, http.StatusOK},
+		{"deep json", "application/json", deep, http.StatusUnprocessableEntity},
+		{"deep json with charset", "application/json; charset=utf-8", deep, http.StatusUnprocessableEntity},
+		// ShouldBindJSON decodes these too, so the label must not skip the check.
+		{"deep text/plain", "text/plain", deep, http.StatusUnprocessableEntity},
+		{"deep without content type", "", deep, http.StatusUnprocessableEntity},
+		{"brackets inside a string", "application/json", `{"body":"` + deep + `"}`, http.StatusOK},
+		{"too large", "application/json", `{"body":"` + strings.Repeat("a", 128) + `"}`, http.StatusRequestEntityTooLarge},
+		{"too large without content type", "", strings.Repeat("a", 200), http.StatusRequestEntityTooLarge},
+		{"multipart upload", "multipart/form-data; boundary=x", deep + strings.Repeat("a", 200), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(bodyLimitMiddleware(100, 1000, maxDepth))
+			var received string
+			router.POST("/echo", func(c *gin.Context) {
+				body, _ := io.ReadAll(c.Request.Body)
+				received = string(body)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			// Streamed bodies have no Content-Length, so the cap applies while reading.
+			req.ContentLength = -1
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assertStatus(t, rec, tt.want)
+			if tt.want == http.StatusOK && received != tt.body {
+				t.Fatalf("handler read %q, want the original body", received)
+			}
+		})
+	}
+}
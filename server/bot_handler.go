@@ -0,0 +1,459 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BotHandler manages bot accounts and accepts the messages they post. Bots
+// authenticate with their API key or a bot session token exchanged for it,
+// and are held to the same length and rate limits as socket senders.
+type BotHandler struct {
+	repo Store
+	hub  *ChatHub
+
+	mu      sync.Mutex
+	history map[int64][]time.Time // bot ID -> recent post times
+}
+
+func NewBotHandler(repo Store, hub *ChatHub) *BotHandler {
+	return &BotHandler{repo: repo, hub: hub, history: make(map[int64][]time.Time)}
+}
+
+// RegisterRoutes mounts the endpoints bots call with their own credentials.
+func (h *BotHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.POST("/bots/token", h.issueToken)
+	group.POST("/bots/:id/messages", h.postMessage)
+}
+
+func (h *BotHandler) RegisterProtectedRoutes(group *gin.RouterGroup) {
+	group.GET("/bots", h.listBots)
+	group.POST("/bots", h.createBot)
+	group.DELETE("/bots/:id", h.deleteBot)
+	group.POST("/conversations/:id/bots", h.addBot)
+	group.DELETE("/conversations/:id/bots/:botId", h.removeBot)
+}
+
+// listBots returns the caller's bots and the conversations each is in. API
+// keys are not included.
+func (h *BotHandler) listBots(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bots, err := h.repo.ListBots(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load bots"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": bots})
+}
+
+// createBot makes a bot account owned by the caller. The API key it posts
+// with is returned only here.
+//
+// Responses:
+//  - 201 with the bot and its api_key
+//  - 400 for invalid JSON or a missing name
+//  - 401 if the caller has no session
+//  - 409 if the caller already has the maximum number of bots
+//  - 500 for repository/database failures
+func (h *BotHandler) createBot(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload CreateBotParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(payload.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bot, key, err := h.repo.CreateBot(ctx, claims.UserID, payload.Name)
+	if err != nil {
+		if errors.Is(err, ErrBotLimitReached) {
+			c.JSON(http.StatusConflict, gin.H{"error": "bot limit reached", "limit": maxBotsPerOwner})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create bot"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"bot": bot, "api_key": key})
+}
+
+// deleteBot removes one of the caller's bots. Its key stops working and its
+// past messages show as from a deleted user.
+//
+// Responses:
+//  - 204 on success
+//  - 400 for an invalid id
+//  - 401 if the caller has no session
+//  - 404 if the caller has no such bot
+//  - 500 for repository/database failures
+func (h *BotHandler) deleteBot(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	botID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || botID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bot id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.DeleteBot(ctx, claims.UserID, botID); err != nil {
+		if errors.Is(err, ErrBotNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "bot not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete bot"})
+		return
+	}
+
+	h.mu.Lock()
+	delete(h.history, botID)
+	h.mu.Unlock()
+
+	c.Status(http.StatusNoContent)
+}
+
+// addBot lets one of the caller's bots post in a conversation. Any member
+// can add bots to a plain conversation; event chats need the host or a
+// co-host.
+//
+// Responses:
+//  - 200 with the bot
+//  - 400 for invalid ids or JSON
+//  - 401 if the caller has no session
+//  - 403 if the caller doesn't own the bot or can't manage an event chat
+//  - 404 if the bot or conversation doesn't exist, or the caller isn't a member
+//  - 409 if the bot is already in the conversation
+//  - 500 for repository/database failures
+func (h *BotHandler) addBot(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	convoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || convoID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var payload AddBotParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bot, err := h.repo.AddBotToConversation(ctx, convoID, payload.BotID, claims.UserID)
+	if err != nil {
+		h.writeBotError(c, err, "failed to add bot")
+		return
+	}
+
+	h.hub.Announce(ctx, convoID, claims.UserID, "%s added the bot %s", claims.UserID, bot.UserID)
+	c.JSON(http.StatusOK, gin.H{"bot": bot})
+}
+
+// removeBot takes a bot out of a conversation. Its owner can always do so;
+// anyone else needs the rights addBot requires. Responses match addBot, with
+// 204 on success and 404 if the bot isn't in the conversation.
+func (h *BotHandler) removeBot(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	convoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || convoID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	botID, err := strconv.ParseInt(c.Param("botId"), 10, 64)
+	if err != nil || botID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bot id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bot, err := h.repo.RemoveBotFromConversation(ctx, convoID, botID, claims.UserID)
+	if err != nil {
+		h.writeBotError(c, err, "failed to remove bot")
+		return
+	}
+
+	h.hub.Announce(ctx, convoID, claims.UserID, "%s removed the bot %s", claims.UserID, bot.UserID)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BotHandler) writeBotError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrBotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "bot not found"})
+	case errors.Is(err, ErrConversationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+	case errors.Is(err, ErrNotConversationMember):
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+	case errors.Is(err, ErrBotNotInChat):
+		c.JSON(http.StatusNotFound, gin.H{"error": "bot is not in this conversation"})
+	case errors.Is(err, ErrNotBotOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only add your own bots"})
+	case errors.Is(err, ErrNotEventHost):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the host or a co-host can manage bots in an event chat"})
+	case errors.Is(err, ErrBotAlreadyInChat):
+		c.JSON(http.StatusConflict, gin.H{"error": "bot is already in this conversation"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// issueToken exchanges the bot's API key, sent as `Authorization: Bearer
+// <key>`, for a session token with only the bot role. The token can post in
+// place of the key, and stops working when the bot is deleted.
+//
+// Responses:
+//  - 200 with the token and its expiry
+//  - 401 if the API key is missing or invalid
+//  - 500 for repository/database failures
+func (h *BotHandler) issueToken(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bot, err := h.repo.AuthenticateBot(ctx, bearerTokenFromHeader(c.GetHeader("Authorization")))
+	if err != nil {
+		if errors.Is(err, ErrInvalidBotKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authenticate bot"})
+		return
+	}
+
+	expiresAt := time.Now().Add(h.hub.signer.ttl)
+	sessionID, err := h.repo.CreateSession(ctx, bot.UserID, sessionDeviceName(c.Request), c.ClientIP(), "", expiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue bot token"})
+		return
+	}
+	token, claims, err := h.hub.signer.issue(bot.UserID, "", sessionID, []string{roleBot}, expiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue bot token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "roles": claims.Roles, "expires_at": claims.ExpiresAt})
+}
+
+// authenticateBot resolves the bearer credential to a bot: an API key, or a
+// live session token with the bot role. It returns ErrInvalidBotKey for
+// anything else.
+func (h *BotHandler) authenticateBot(ctx context.Context, c *gin.Context) (*Bot, error) {
+	credential := bearerTokenFromHeader(c.GetHeader("Authorization"))
+	if strings.HasPrefix(credential, botKeyPrefix) {
+		return h.repo.AuthenticateBot(ctx, credential)
+	}
+
+	claims, err := h.hub.signer.verify(credential)
+	if err != nil || !claims.hasRole(roleBot) {
+		return nil, ErrInvalidBotKey
+	}
+	active, err := sessionActive(ctx, h.repo, claims, c.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrInvalidBotKey
+	}
+	bot, err := h.repo.GetBotByUserID(ctx, claims.UserID)
+	if errors.Is(err, ErrBotNotFound) {
+		return nil, ErrInvalidBotKey
+	}
+	return bot, err
+}
+
+// postMessage posts `body` to `conversation_id` as the bot. It takes the
+// bot's API key or bot token as `Authorization: Bearer <key>` and an optional
+// Idempotency-Key header; the message reaches sockets as `message:new` and
+// pushes and webhooks go out as for any member's message.
+//
+// Responses:
+//  - 201 with the message
+//  - 400 for invalid JSON, an invalid id, or a body over the length limit
+//  - 401 if the API key or token is missing or isn't this bot's
+//  - 403 if the bot hasn't been added to the conversation
+//  - 409 with code `conversation_locked` if the conversation is read-only
+//  - 429 if the bot is posting faster than the rate limit
+//  - 500 for repository/database failures
+func (h *BotHandler) postMessage(c *gin.Context) {
+	botID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || botID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bot id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bot, err := h.authenticateBot(ctx, c)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBotKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authenticate bot"})
+		return
+	}
+	if bot.ID != botID {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+		return
+	}
+
+	var payload BotMessageParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(payload.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body is required"})
+		return
+	}
+	if utf8.RuneCountInString(payload.Body) > h.hub.config.MaxMessageLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message is too long", "maxLength": h.hub.config.MaxMessageLength})
+		return
+	}
+
+	key, ok := normalizeIdempotencyKey(c.GetHeader(idempotencyKeyHeader))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+		return
+	}
+
+	allowed, err := h.repo.IsBotInConversation(ctx, bot.ID, payload.ConversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to post message"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "bot is not in this conversation"})
+		return
+	}
+
+	// Keyed per conversation like socket tempIds, so one key can't replay a
+	// message into another chat.
+	var idempotencyKey string
+	if key != "" {
+		idempotencyKey = fmt.Sprintf("%d:%s", payload.ConversationID, key)
+		if h.replayBotMessage(ctx, c, bot.UserID, idempotencyKey) {
+			return
+		}
+	}
+
+	if !h.allowMessage(bot.ID, time.Now()) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limited"})
+		return
+	}
+
+	msg, err := h.repo.CreateMessage(ctx, CreateMessageParams{
+		ConversationID: payload.ConversationID,
+		SenderID:       bot.UserID,
+		Body:           payload.Body,
+		DeliveryStatus: "sent",
+		Notify:         true,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		if errors.Is(err, ErrIdempotencyKeyUsed) && h.replayBotMessage(ctx, c, bot.UserID, idempotencyKey) {
+			return
+		}
+		if errors.Is(err, ErrConversationLocked) {
+			respondConversationLocked(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to post message"})
+		return
+	}
+
+	h.hub.BroadcastMessage(*msg)
+	h.hub.notifyMentions(*msg)
+	c.JSON(http.StatusCreated, gin.H{"message": newMessagePayload(*msg)})
+}
+
+// replayBotMessage answers a retried post with the message the first attempt
+// stored. It reports whether it wrote a response.
+func (h *BotHandler) replayBotMessage(ctx context.Context, c *gin.Context, userID int64, key string) bool {
+	messageID, found, err := h.repo.LookupIdempotencyKey(ctx, userID, idempotencyScopeMessage, key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to post message"})
+		return true
+	}
+	if !found {
+		return false
+	}
+	msg, err := h.repo.GetMessageByID(ctx, messageID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to post message"})
+		return true
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.JSON(http.StatusCreated, gin.H{"message": newMessagePayload(*msg)})
+	return true
+}
+
+// allowMessage is ChatClient.allowMessage's sliding window, kept per bot
+// since bots have no socket to hang it on.
+func (h *BotHandler) allowMessage(botID int64, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	windowStart := now.Add(-h.hub.config.MessageRateWindow)
+	filtered := h.history[botID][:0]
+	for _, ts := range h.history[botID] {
+		if ts.After(windowStart) {
+			filtered = append(filtered, ts)
+		}
+	}
+	if len(filtered) >= h.hub.config.MessageRateLimit {
+		h.history[botID] = filtered
+		return false
+	}
+	h.history[botID] = append(filtered, now)
+	return true
+}
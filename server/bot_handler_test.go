@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func botRoutes(store Store) func(*gin.RouterGroup) {
+	handler := NewBotHandler(store, newTestHub(store))
+	return func(group *gin.RouterGroup) {
+		handler.RegisterRoutes(group)
+		handler.RegisterProtectedRoutes(group)
+	}
+}
+
+func TestCreateBotStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"created", `{"name":"Standup bot"}`, nil, http.StatusCreated},
+		{"missing name", `{}`, nil, http.StatusBadRequest},
+		{"blank name", `{"name":"   "}`, nil, http.StatusBadRequest},
+		{"limit reached", `{"name":"Standup bot"}`, ErrBotLimitReached, http.StatusConflict},
+		{"store failure", `{"name":"Standup bot"}`, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.CreateBotFunc = func(_ context.Context, ownerID int64, name string) (*Bot, string, error) {
+				if tt.err != nil {
+					return nil, "", tt.err
+				}
+				return &Bot{ID: 1, UserID: 50, Name: name}, botKeyPrefix + "secret", nil
+			}
+			rec := serveAs(t, botRoutes(store), testHostID, http.MethodPost, "/api/bots", tt.body)
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestDeleteBotStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"deleted", "/api/bots/1", nil, http.StatusNoContent},
+		{"invalid id", "/api/bots/abc", nil, http.StatusBadRequest},
+		{"not the owner's", "/api/bots/1", ErrBotNotFound, http.StatusNotFound},
+		{"store failure", "/api/bots/1", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.DeleteBotFunc = func(context.Context, int64, int64) error { return tt.err }
+			rec := serveAs(t, botRoutes(store), testHostID, http.MethodDelete, tt.path, "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestAddBotStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		err  error
+		want int
+	}{
+		{"added", "/api/conversations/10/bots", `{"bot_id":1}`, nil, http.StatusOK},
+		{"invalid id", "/api/conversations/abc/bots", `{"bot_id":1}`, nil, http.StatusBadRequest},
+		{"missing bot", "/api/conversations/10/bots", `{}`, nil, http.StatusBadRequest},
+		{"unknown bot", "/api/conversations/10/bots", `{"bot_id":1}`, ErrBotNotFound, http.StatusNotFound},
+		{"unknown conversation", "/api/conversations/10/bots", `{"bot_id":1}`, ErrConversationNotFound, http.StatusNotFound},
+		{"not a member", "/api/conversations/10/bots", `{"bot_id":1}`, ErrNotConversationMember, http.StatusNotFound},
+		{"someone else's bot", "/api/conversations/10/bots", `{"bot_id":1}`, ErrNotBotOwner, http.StatusForbidden},
+		{"event chat, not host", "/api/conversations/1/bots", `{"bot_id":1}`, ErrNotEventHost, http.StatusForbidden},
+		{"already added", "/api/conversations/10/bots", `{"bot_id":1}`, ErrBotAlreadyInChat, http.StatusConflict},
+		{"store failure", "/api/conversations/10/bots", `{"bot_id":1}`, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.AddBotToConversationFunc = func(_ context.Context, _, botID, _ int64) (*Bot, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &Bot{ID: botID, UserID: 50, Name: "Standup bot"}, nil
+			}
+			rec := serveAs(t, botRoutes(store), testHostID, http.MethodPost, tt.path, tt.body)
+			assertStatus(t, rec, tt.want)
+
+			wantNotices := 0
+			if tt.want == http.StatusOK {
+				wantNotices = 1
+			}
+			if got := len(store.CreateSystemMessageCalls()); got != wantNotices {
+				t.Fatalf("system messages = %d, want %d", got, wantNotices)
+			}
+		})
+	}
+}
+
+func TestRemoveBotStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"removed", "/api/conversations/10/bots/1", nil, http.StatusNoContent},
+		{"invalid bot id", "/api/conversations/10/bots/abc", nil, http.StatusBadRequest},
+		{"not in the chat", "/api/conversations/10/bots/1", ErrBotNotInChat, http.StatusNotFound},
+		{"event chat, not host", "/api/conversations/1/bots/1", ErrNotEventHost, http.StatusForbidden},
+		{"store failure", "/api/conversations/10/bots/1", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.RemoveBotFromConversationFunc = func(_ context.Context, _, botID, _ int64) (*Bot, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &Bot{ID: botID, UserID: 50, Name: "Standup bot"}, nil
+			}
+			rec := serveAs(t, botRoutes(store), testHostID, http.MethodDelete, tt.path, "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestIssueBotTokenRejectsBadKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid key", ErrInvalidBotKey, http.StatusUnauthorized},
+		{"store failure", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.AuthenticateBotFunc = func(context.Context, string) (*Bot, error) { return nil, tt.err }
+			rec := serveAs(t, botRoutes(store), 0, http.MethodPost, "/api/bots/token", "")
+			assertStatus(t, rec, tt.want)
+			if got := len(store.CreateSessionCalls()); got != 0 {
+				t.Fatalf("sessions created = %d, want 0", got)
+			}
+		})
+	}
+}
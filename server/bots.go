@@ -0,0 +1,463 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxBotsPerOwner caps how many bots one account can create.
+	maxBotsPerOwner = 5
+	// botKeyPrefix marks bot API keys so they're recognisable in config files.
+	botKeyPrefix = "bot_"
+)
+
+var (
+	ErrBotNotFound      = errors.New("bot not found")
+	ErrBotLimitReached  = errors.New("bot limit reached")
+	ErrInvalidBotKey    = errors.New("invalid bot api key")
+	ErrBotNotInChat     = errors.New("bot is not in the conversation")
+	ErrNotBotOwner      = errors.New("user does not own the bot")
+	ErrBotAlreadyInChat = errors.New("bot is already in the conversation")
+)
+
+const createTableBots = `
+CREATE TABLE IF NOT EXISTS bots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL UNIQUE,
+    owner_id INTEGER NOT NULL,
+    api_key_hash TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+`
+
+const createTableBotConversations = `
+CREATE TABLE IF NOT EXISTS bot_conversations (
+    bot_id INTEGER NOT NULL,
+    conversation_id INTEGER NOT NULL,
+    added_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bot_id, conversation_id),
+    FOREIGN KEY (bot_id) REFERENCES bots(id),
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id)
+);
+`
+
+const insertBot = `
+INSERT INTO bots (user_id, owner_id, api_key_hash)
+VALUES (?, ?, ?);
+`
+
+const countBotsByOwner = `
+SELECT COUNT(*) FROM bots WHERE owner_id = ?;
+`
+
+const selectBotsByOwner = `
+SELECT b.id, b.user_id, b.owner_id, u.name, b.created_at
+FROM bots b
+JOIN users u ON u.id = b.user_id
+WHERE b.owner_id = ?
+ORDER BY b.id;
+`
+
+const selectBotByID = `
+SELECT b.id, b.user_id, b.owner_id, u.name, b.created_at
+FROM bots b
+JOIN users u ON u.id = b.user_id
+WHERE b.id = ?;
+`
+
+const selectBotByKeyHash = `
+SELECT b.id, b.user_id, b.owner_id, u.name, b.created_at
+FROM bots b
+JOIN users u ON u.id = b.user_id
+WHERE b.api_key_hash = ?;
+`
+
+const selectBotByUserID = `
+SELECT b.id, b.user_id, b.owner_id, u.name, b.created_at
+FROM bots b
+JOIN users u ON u.id = b.user_id
+WHERE b.user_id = ?;
+`
+
+const selectBotConversationIDs = `
+SELECT conversation_id FROM bot_conversations WHERE bot_id = ? ORDER BY conversation_id;
+`
+
+const selectBotInConversation = `
+SELECT 1 FROM bot_conversations WHERE bot_id = ? AND conversation_id = ?;
+`
+
+const insertBotConversation = `
+INSERT OR IGNORE INTO bot_conversations (bot_id, conversation_id, added_by)
+VALUES (?, ?, ?);
+`
+
+const deleteBotConversation = `
+DELETE FROM bot_conversations WHERE bot_id = ? AND conversation_id = ?;
+`
+
+const deleteBotConversations = `
+DELETE FROM bot_conversations WHERE bot_id = ?;
+`
+
+const deleteBot = `
+DELETE FROM bots WHERE id = ?;
+`
+
+const selectBotIDsByOwner = `
+SELECT id, user_id FROM bots WHERE owner_id = ?;
+`
+
+// botRecord is a bot row plus who owns it, which Bot doesn't expose.
+type botRecord struct {
+	Bot
+	ownerID int64
+}
+
+func (r *EventRepository) initBots(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableBots); err != nil {
+		return fmt.Errorf("create bots table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableBotConversations); err != nil {
+		return fmt.Errorf("create bot conversations table: %w", err)
+	}
+	return nil
+}
+
+func hashBotKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newBotKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate bot api key: %w", err)
+	}
+	return botKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+func scanBot(row rowScanner) (*botRecord, error) {
+	var bot botRecord
+	if err := row.Scan(&bot.ID, &bot.UserID, &bot.ownerID, &bot.Name, &bot.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
+func (r *EventRepository) attachBotConversations(ctx context.Context, bot *Bot) error {
+	rows, err := r.db.QueryContext(ctx, selectBotConversationIDs, bot.ID)
+	if err != nil {
+		return fmt.Errorf("list bot conversations: %w", err)
+	}
+	defer rows.Close()
+
+	bot.ConversationIDs = []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("scan bot conversation: %w", err)
+		}
+		bot.ConversationIDs = append(bot.ConversationIDs, id)
+	}
+	return rows.Err()
+}
+
+// CreateBot makes a bot owned by ownerID. Its user row has no password, so
+// it can never sign in; the returned API key is its only credential and is
+// not stored in the clear.
+func (r *EventRepository) CreateBot(ctx context.Context, ownerID int64, name string) (*Bot, string, error) {
+	key, err := newBotKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("begin create bot tx: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, countBotsByOwner, ownerID).Scan(&count); err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("count bots: %w", err)
+	}
+	if count >= maxBotsPerOwner {
+		tx.Rollback()
+		return nil, "", ErrBotLimitReached
+	}
+
+	// The key's hash is unique, so it doubles as a placeholder address.
+	hash := hashBotKey(key)
+	res, err := tx.ExecContext(ctx, insertUser, strings.TrimSpace(name), "bot-"+hash[:16]+"@bots.invalid", "")
+	if err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("insert bot user: %w", err)
+	}
+	userID, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("fetch bot user id: %w", err)
+	}
+	res, err = tx.ExecContext(ctx, insertBot, userID, ownerID, hash)
+	if err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("insert bot: %w", err)
+	}
+	botID, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("fetch bot id: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("commit bot: %w", err)
+	}
+
+	bot, err := r.getBot(ctx, botID)
+	if err != nil {
+		return nil, "", err
+	}
+	return &bot.Bot, key, nil
+}
+
+// ListBots returns ownerID's bots with the conversations each is in.
+func (r *EventRepository) ListBots(ctx context.Context, ownerID int64) ([]Bot, error) {
+	rows, err := r.db.QueryContext(ctx, selectBotsByOwner, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list bots: %w", err)
+	}
+	defer rows.Close()
+
+	bots := []Bot{}
+	for rows.Next() {
+		bot, err := scanBot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan bot: %w", err)
+		}
+		bots = append(bots, bot.Bot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bots: %w", err)
+	}
+	rows.Close()
+
+	for i := range bots {
+		if err := r.attachBotConversations(ctx, &bots[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bots, nil
+}
+
+func (r *EventRepository) getBot(ctx context.Context, botID int64) (*botRecord, error) {
+	bot, err := scanBot(r.db.QueryRowContext(ctx, selectBotByID, botID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBotNotFound
+		}
+		return nil, fmt.Errorf("fetch bot: %w", err)
+	}
+	if err := r.attachBotConversations(ctx, &bot.Bot); err != nil {
+		return nil, err
+	}
+	return bot, nil
+}
+
+// AuthenticateBot resolves an API key to its bot.
+func (r *EventRepository) AuthenticateBot(ctx context.Context, key string) (*Bot, error) {
+	if !strings.HasPrefix(key, botKeyPrefix) {
+		return nil, ErrInvalidBotKey
+	}
+	bot, err := scanBot(r.db.QueryRowContext(ctx, selectBotByKeyHash, hashBotKey(key)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidBotKey
+		}
+		return nil, fmt.Errorf("authenticate bot: %w", err)
+	}
+	return &bot.Bot, nil
+}
+
+// GetBotByUserID returns the bot whose messages are sent as userID, which is
+// who a bot session token names.
+func (r *EventRepository) GetBotByUserID(ctx context.Context, userID int64) (*Bot, error) {
+	bot, err := scanBot(r.db.QueryRowContext(ctx, selectBotByUserID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBotNotFound
+		}
+		return nil, fmt.Errorf("fetch bot: %w", err)
+	}
+	return &bot.Bot, nil
+}
+
+// DeleteBot removes one of ownerID's bots from its conversations and
+// anonymizes its user row, so past messages read as from a deleted user.
+func (r *EventRepository) DeleteBot(ctx context.Context, ownerID, botID int64) error {
+	bot, err := r.getBot(ctx, botID)
+	if err != nil {
+		return err
+	}
+	if bot.ownerID != ownerID {
+		return ErrBotNotFound
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin delete bot tx: %w", err)
+	}
+	if err := deleteBotInTx(ctx, tx, bot.ID, bot.UserID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete bot: %w", err)
+	}
+	return nil
+}
+
+func deleteBotInTx(ctx context.Context, tx *sql.Tx, botID, userID int64) error {
+	if _, err := tx.ExecContext(ctx, deleteBotConversations, botID); err != nil {
+		return fmt.Errorf("delete bot conversations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteBot, botID); err != nil {
+		return fmt.Errorf("delete bot: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteSessionsForUser, userID); err != nil {
+		return fmt.Errorf("delete bot sessions: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, anonymizeUser, fmt.Sprintf("deleted-bot-%d@deleted.invalid", botID), userID); err != nil {
+		return fmt.Errorf("anonymize bot user: %w", err)
+	}
+	return nil
+}
+
+// deleteBotsOwnedBy deletes every bot of an account being deleted.
+func deleteBotsOwnedBy(ctx context.Context, tx *sql.Tx, ownerID int64) error {
+	rows, err := tx.QueryContext(ctx, selectBotIDsByOwner, ownerID)
+	if err != nil {
+		return fmt.Errorf("list owned bots: %w", err)
+	}
+	type ownedBot struct{ id, userID int64 }
+	var bots []ownedBot
+	for rows.Next() {
+		var bot ownedBot
+		if err := rows.Scan(&bot.id, &bot.userID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan owned bot: %w", err)
+		}
+		bots = append(bots, bot)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("iterate owned bots: %w", err)
+	}
+	rows.Close()
+
+	for _, bot := range bots {
+		if err := deleteBotInTx(ctx, tx, bot.id, bot.userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBotManager allows actorID to add or remove bots in a conversation:
+// any member of a plain conversation, but only the host or a co-host of an
+// event chat.
+func (r *EventRepository) checkBotManager(ctx context.Context, conversationID, actorID int64) error {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	isMember, err := r.IsConversationMember(ctx, conversationID, actorID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return ErrNotConversationMember
+	}
+	if convo.EventID == nil {
+		return nil
+	}
+	event, err := r.GetEventByID(ctx, *convo.EventID)
+	if err != nil {
+		return err
+	}
+	return checkEventModerator(ctx, r.db, event, conversationID, actorID)
+}
+
+// AddBotToConversation lets one of actorID's bots post in a conversation they
+// manage.
+func (r *EventRepository) AddBotToConversation(ctx context.Context, conversationID, botID, actorID int64) (*Bot, error) {
+	bot, err := r.getBot(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	if bot.ownerID != actorID {
+		return nil, ErrNotBotOwner
+	}
+	if err := r.checkBotManager(ctx, conversationID, actorID); err != nil {
+		return nil, err
+	}
+
+	res, err := r.db.ExecContext(ctx, insertBotConversation, botID, conversationID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("add bot to conversation: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("add bot rows affected: %w", err)
+	} else if affected == 0 {
+		return nil, ErrBotAlreadyInChat
+	}
+	bot.ConversationIDs = append(bot.ConversationIDs, conversationID)
+	return &bot.Bot, nil
+}
+
+// RemoveBotFromConversation takes a bot out of a conversation. Its owner can
+// always do so; otherwise actorID must manage the conversation.
+func (r *EventRepository) RemoveBotFromConversation(ctx context.Context, conversationID, botID, actorID int64) (*Bot, error) {
+	bot, err := r.getBot(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	if bot.ownerID != actorID {
+		if err := r.checkBotManager(ctx, conversationID, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, deleteBotConversation, botID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("remove bot from conversation: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("remove bot rows affected: %w", err)
+	} else if affected == 0 {
+		return nil, ErrBotNotInChat
+	}
+	return &bot.Bot, nil
+}
+
+// IsBotInConversation reports whether the bot may post in the conversation.
+func (r *EventRepository) IsBotInConversation(ctx context.Context, botID, conversationID int64) (bool, error) {
+	var one int
+	if err := r.db.QueryRowContext(ctx, selectBotInConversation, botID, conversationID).Scan(&one); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("check bot conversation: %w", err)
+	}
+	return true, nil
+}
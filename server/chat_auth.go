@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// socketAuthTimeout is how long an unauthenticated socket may take to send
+// its `auth` frame before it is dropped.
+const socketAuthTimeout = 5 * time.Second
+
+// tokenExpiryWarning is how long before its token expires a socket is sent
+// `token:expiring`.
+const tokenExpiryWarning = 10 * time.Minute
+
+// bearerProtocol is the Sec-WebSocket-Protocol name that precedes a token.
+const bearerProtocol = "bearer"
+
+var errSocketUnauthorized = errors.New("invalid or expired socket token")
+
+// sessionRefresh extends a live socket's session after a `token:refresh`.
+type sessionRefresh struct {
+	client    *ChatClient
+	sessionID int64
+	expiresAt time.Time
+}
+
+type socketAuthFrame struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+}
+
+// bearerSubprotocol extracts the token from a `bearer, <token>` subprotocol
+// list, which browsers can send where they can't set Authorization headers.
+func bearerSubprotocol(r *http.Request) (string, bool) {
+	protocols := websocket.Subprotocols(r)
+	for i, protocol := range protocols {
+		if strings.EqualFold(protocol, bearerProtocol) && i+1 < len(protocols) {
+			token := strings.TrimSpace(protocols[i+1])
+			return token, token != ""
+		}
+	}
+	return "", false
+}
+
+// authenticateSocket resolves a session token to a live session of a user
+// that still exists. Bot tokens can't open sockets; bots only post.
+func (h *ChatHub) authenticateSocket(ctx context.Context, token, remoteIP string) (*sessionClaims, error) {
+	claims, err := h.signer.verify(token)
+	if err != nil || !claims.hasRole(roleUser) {
+		return nil, errSocketUnauthorized
+	}
+	active, err := sessionActive(ctx, h.repo, claims, remoteIP)
+	if err != nil {
+		return nil, fmt.Errorf("check session: %w", err)
+	}
+	if !active {
+		return nil, errSocketUnauthorized
+	}
+	return claims, nil
+}
+
+// authenticateFirstFrame waits for an `auth` frame on a freshly upgraded
+// socket. On failure it closes the socket with a policy-violation reason.
+func (h *ChatHub) authenticateFirstFrame(conn *websocket.Conn, remoteIP string) (*sessionClaims, bool) {
+	reject := func(reason string) (*sessionClaims, bool) {
+		closeFrame := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+		_ = conn.WriteControl(websocket.CloseMessage, closeFrame, time.Now().Add(time.Second))
+		if err := conn.Close(); err != nil {
+			log.Printf("chat client close error: %v", err)
+		}
+		return nil, false
+	}
+
+	conn.SetReadLimit(1024)
+	_ = conn.SetReadDeadline(time.Now().Add(socketAuthTimeout))
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		return reject("authentication required")
+	}
+
+	var frame socketAuthFrame
+	if err := json.Unmarshal(payload, &frame); err != nil || frame.Type != "auth" || strings.TrimSpace(frame.Token) == "" {
+		return reject("authentication required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	claims, err := h.authenticateSocket(ctx, strings.TrimSpace(frame.Token), remoteIP)
+	if err != nil {
+		if !errors.Is(err, errSocketUnauthorized) {
+			log.Printf("socket auth failed: %v", err)
+		}
+		return reject("invalid or expired token")
+	}
+
+	// The write pump isn't running yet, so writing here can't race it.
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"type":"auth:ok","userId":%d}`, claims.UserID))); err != nil {
+		_ = conn.Close()
+		return nil, false
+	}
+	return claims, true
+}
+
+// handleTokenRefresh swaps in a newer token for the same user so the socket
+// outlives the token it connected with.
+func (c *ChatClient) handleTokenRefresh(inbound inboundEnvelope) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	claims, err := c.hub.authenticateSocket(ctx, strings.TrimSpace(inbound.Token), c.remoteIP)
+	if err != nil || claims.UserID != c.userID {
+		if err != nil && !errors.Is(err, errSocketUnauthorized) {
+			log.Printf("socket token refresh failed: %v", err)
+		}
+		c.send <- []byte(`{"type":"system:error","code":"invalid_token"}`)
+		return
+	}
+	c.hub.refresh <- sessionRefresh{client: c, sessionID: claims.SessionID, expiresAt: claims.ExpiresAt}
+}
+
+// applySessionRefresh records a socket's new expiry and confirms it.
+func (h *ChatHub) applySessionRefresh(req sessionRefresh) {
+	client := req.client
+	if _, live := h.clientsByUser[client.userID][client]; !live {
+		return
+	}
+	if req.expiresAt.After(client.expiresAt) {
+		client.expiresAt = req.expiresAt
+		client.expiryWarned = false
+	}
+	client.sessionID = req.sessionID
+	h.deliverDirect(client, tokenEvent("token:refreshed", client.expiresAt))
+}
+
+// checkSessions warns sockets whose token is about to expire and closes the
+// ones whose token already has.
+func (h *ChatHub) checkSessions(now time.Time) {
+	for _, clients := range h.clientsByUser {
+		for client := range clients {
+			switch {
+			case now.After(client.expiresAt):
+				h.dropClient(client, "session expired")
+			case !client.expiryWarned && client.expiresAt.Sub(now) <= tokenExpiryWarning:
+				client.expiryWarned = true
+				h.deliverDirect(client, tokenEvent("token:expiring", client.expiresAt))
+			}
+		}
+	}
+}
+
+func tokenEvent(eventType string, expiresAt time.Time) []byte {
+	return []byte(fmt.Sprintf(`{"type":%q,"expiresAt":%q}`, eventType, expiresAt.UTC().Format(time.RFC3339)))
+}
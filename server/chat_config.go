@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChatConfig holds the chat hub's tunables. It is read once at startup and
+// never changes afterwards, so clients may read it from any goroutine.
+type ChatConfig struct {
+	MessageRateLimit  int           // messages a socket may send per MessageRateWindow
+	MessageRateWindow time.Duration // sliding window for MessageRateLimit
+	ReadTimeout       time.Duration // socket is dropped after this long without a frame or pong
+	PingInterval      time.Duration // server pings; must be shorter than ReadTimeout
+	MaxConnsPerUser   int           // oldest socket is evicted past this; 0 disables the cap
+	MaxMessageLength  int           // longest message body accepted, in characters
+	Filters           MessageFilterConfig
+}
+
+func defaultChatConfig() ChatConfig {
+	return ChatConfig{
+		MessageRateLimit:  30,
+		MessageRateWindow: 10 * time.Second,
+		ReadTimeout:       60 * time.Second,
+		PingInterval:      50 * time.Second,
+		MaxConnsPerUser:   5,
+		MaxMessageLength:  2000,
+		Filters:           defaultMessageFilterConfig(),
+	}
+}
+
+// newChatConfigFromEnv overrides the defaults with CHAT_MESSAGE_RATE_LIMIT,
+// CHAT_MESSAGE_RATE_WINDOW, CHAT_READ_TIMEOUT, CHAT_PING_INTERVAL,
+// CHAT_MAX_CONNECTIONS_PER_USER and CHAT_MAX_MESSAGE_LENGTH, and the filter
+// settings with newMessageFilterConfigFromEnv. Invalid values are logged and
+// ignored.
+func newChatConfigFromEnv() ChatConfig {
+	config := defaultChatConfig()
+	config.MessageRateLimit = envPositiveInt("CHAT_MESSAGE_RATE_LIMIT", config.MessageRateLimit, false)
+	config.MessageRateWindow = envPositiveDuration("CHAT_MESSAGE_RATE_WINDOW", config.MessageRateWindow)
+	config.ReadTimeout = envPositiveDuration("CHAT_READ_TIMEOUT", config.ReadTimeout)
+	config.PingInterval = envPositiveDuration("CHAT_PING_INTERVAL", config.PingInterval)
+	config.MaxConnsPerUser = envPositiveInt("CHAT_MAX_CONNECTIONS_PER_USER", config.MaxConnsPerUser, true)
+	config.MaxMessageLength = envPositiveInt("CHAT_MAX_MESSAGE_LENGTH", config.MaxMessageLength, false)
+	config.Filters = newMessageFilterConfigFromEnv()
+
+	if config.PingInterval >= config.ReadTimeout {
+		adjusted := config.ReadTimeout * 5 / 6
+		log.Printf("CHAT_PING_INTERVAL %s is not shorter than CHAT_READ_TIMEOUT %s; using %s", config.PingInterval, config.ReadTimeout, adjusted)
+		config.PingInterval = adjusted
+	}
+	return config
+}
+
+func envPositiveInt(name string, fallback int, allowZero bool) int {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil || parsed < 0 || (parsed == 0 && !allowZero) {
+		log.Printf("invalid %s %q; using %d", name, raw, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+func envPositiveDuration(name string, fallback time.Duration) time.Duration {
+	parsed, err := envDuration(name, fallback, false)
+	if err != nil {
+		log.Printf("%v; using %s", err, fallback)
+	}
+	return parsed
+}
+
+// envDuration reads a Go duration such as 90s, returning fallback when name is
+// unset. A malformed or negative value (or zero, unless allowZero) returns
+// fallback and an error naming the variable, for callers that fail startup
+// rather than log.
+func envDuration(name string, fallback time.Duration, allowZero bool) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil || parsed < 0 || (parsed == 0 && !allowZero) {
+		if allowZero {
+			return fallback, fmt.Errorf("invalid %s %q: want a duration such as 5m, or 0", name, raw)
+		}
+		return fallback, fmt.Errorf("invalid %s %q: want a positive duration such as 5m", name, raw)
+	}
+	return parsed, nil
+}
+
+// helloFrame tells a freshly connected client the limits it is held to.
+type helloFrame struct {
+	Type   string      `json:"type"`
+	UserID int64       `json:"userId"`
+	Limits helloLimits `json:"limits"`
+}
+
+type helloLimits struct {
+	MessageRateLimit         int     `json:"messageRateLimit"`
+	MessageRateWindowSeconds float64 `json:"messageRateWindowSeconds"`
+	ReadTimeoutSeconds       float64 `json:"readTimeoutSeconds"`
+	PingIntervalSeconds      float64 `json:"pingIntervalSeconds"`
+	MaxConnections           int     `json:"maxConnections"`
+	ReplayBufferSize         int     `json:"replayBufferSize"`
+	MaxMessageLength         int     `json:"maxMessageLength"`
+}
+
+// queueHello puts the hello frame first in a new client's send buffer, before
+// the hub can queue anything else for it.
+func (h *ChatHub) queueHello(client *ChatClient) {
+	payload, err := json.Marshal(h.config.hello(client.userID))
+	if err != nil {
+		log.Printf("marshal hello failed: %v", err)
+		return
+	}
+	client.send <- payload
+}
+
+// frameLimit is the largest inbound frame a socket accepts: room for a
+// maximum-length body even if every character is JSON-escaped, plus the
+// envelope. Bigger frames close the socket.
+func (cfg ChatConfig) frameLimit() int64 {
+	return int64(cfg.MaxMessageLength)*6 + 1024
+}
+
+func (cfg ChatConfig) hello(userID int64) helloFrame {
+	return helloFrame{
+		Type:   "hello",
+		UserID: userID,
+		Limits: helloLimits{
+			MessageRateLimit:         cfg.MessageRateLimit,
+			MessageRateWindowSeconds: cfg.MessageRateWindow.Seconds(),
+			ReadTimeoutSeconds:       cfg.ReadTimeout.Seconds(),
+			PingIntervalSeconds:      cfg.PingInterval.Seconds(),
+			MaxConnections:           cfg.MaxConnsPerUser,
+			ReplayBufferSize:         replayBufferSize,
+			MaxMessageLength:         cfg.MaxMessageLength,
+		},
+	}
+}
@@ -4,11 +4,15 @@ import (
     "context"
     "encoding/json"
     "errors"
+    "fmt"
     "log"
+    "math"
     "net/http"
     "strconv"
     "strings"
+    "sync"
     "time"
+    "unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -18,14 +22,27 @@ import (
 // It keeps long-lived goroutines, channels, and lookup tables that decide
 // which connected clients should receive which conversation events.
 type ChatHub struct {
-	repo          *EventRepository
+	repo          Store
 	signer        *tokenSigner
 	register      chan *ChatClient            // fan-in of freshly upgraded sockets
 	unregister    chan *ChatClient            // fan-in of disconnecting sockets
 	broadcast     chan chatBroadcast          // queue of conversation payloads to fan back out
 	membership    chan membershipUpdate       // join/leave notifications from the HTTP layer
+	disconnect    chan disconnectRequest      // forced closes for every socket of a user
+	direct        chan directMessage          // payloads addressed to one user's sockets
+	control       chan streamControl          // acks and resume requests from sequenced sockets
+	subscribe     chan subscriptionRequest    // per-socket room joins/leaves requested by clients
+	refresh       chan sessionRefresh         // token refreshes from live sockets
+	ping          chan chan struct{}          // readiness probes; closed by Run to prove it is looping
+	stats         chan chan HubStats          // diagnostics snapshots taken on the hub goroutine
+	pusher        PushSender                  // nil disables mobile push
+	mailer        EmailSender                 // nil disables email
+	config        ChatConfig
+	filters       messageFilterChain          // run on every socket send before it is stored
 	subscriptions map[int64]map[*ChatClient]struct{} // conversationID -> live clients in that room
 	clientsByUser map[int64]map[*ChatClient]struct{} // userID -> live sockets for that user
+	streams       map[int64]map[string]*replayStream  // userID -> clientId -> replay stream
+	parked        map[int64]map[*replayStream]struct{} // conversationID -> streams awaiting resume
 }
 
 // chatBroadcast represents a message that should be fanned out to listeners.
@@ -40,6 +57,63 @@ type membershipUpdate struct {
 	action         string
 }
 
+// disconnectRequest asks the hub to drop every live socket owned by a user,
+// sending a close frame with the given reason first.
+type disconnectRequest struct {
+	userID    int64
+	sessionID int64 // when set, only that session's sockets are closed
+	reason    string
+}
+
+// subscriptionRequest attaches or detaches one socket from a conversation room
+// after the read pump has checked membership.
+type subscriptionRequest struct {
+	client         *ChatClient
+	conversationID int64
+	subscribe      bool
+}
+
+// directMessage is delivered to every socket of a single user regardless of
+// conversation subscriptions.
+type directMessage struct {
+	userID   int64
+	payload  []byte
+	offline  *PushNotification    // sent to the user's devices instead when no socket is connected
+	category notificationCategory // the setting that lets the user turn offline off
+}
+
+// joinRequestEvent tells a user about a change to a join request, e.g. a
+// waitlist promotion.
+type joinRequestEvent struct {
+	Type           string                  `json:"type"`
+	EventID        int64                   `json:"eventId"`
+	ConversationID int64                   `json:"conversationId"`
+	Request        ConversationJoinRequest `json:"request"`
+	Requester      *joinRequester          `json:"requester,omitempty"` // only on join_request:new
+	Decision       string                  `json:"decision,omitempty"`  // only on join_request:decided
+}
+
+// joinRequester is the profile summary hosts see on a new join request.
+type joinRequester struct {
+	UserID int64   `json:"userId"`
+	Name   string  `json:"name"`
+	Gender *string `json:"gender,omitempty"`
+	Age    *int    `json:"age,omitempty"`
+}
+
+// connectionEvent tells a user that someone sent or accepted a connection
+// request.
+type connectionEvent struct {
+	Type       string     `json:"type"`
+	Connection Connection `json:"connection"`
+}
+
+// subscriptionEvent confirms a socket's `subscribe`/`unsubscribe` frame.
+type subscriptionEvent struct {
+	Type           string `json:"type"`
+	ConversationID int64  `json:"conversationId"`
+}
+
 type membershipEvent struct {
 	Type           string `json:"type"`
 	ConversationID int64  `json:"conversationId"`
@@ -48,28 +122,53 @@ type membershipEvent struct {
 }
 
 // ChatClient wraps a single WebSocket connection and bookkeeping that helps the
-// hub keep track of which conversations this socket should hear about.
+// hub keep track of which conversations this socket should hear about. SSE
+// listeners are clients without a conn; closing them closes done instead.
 type ChatClient struct {
     hub             *ChatHub
     conn            *websocket.Conn
+    done            chan struct{}
+    closeOnce       sync.Once
     send            chan []byte
     userID          int64
     subscriptions   map[int64]struct{}
     messageHistory  []time.Time
+    clientID        string        // optional `clientId` that makes the socket resumable
+    stream          *replayStream // hub-owned; nil for unsequenced sockets
+    evicted         bool          // hub already closed the socket
+    connectedAt     time.Time     // picks the oldest socket when a user is over the cap
+    expiresAt       time.Time     // session expiry; extended by `token:refresh`
+    expiryWarned    bool          // `token:expiring` already sent for expiresAt
+    remoteIP        string        // client IP as resolved through trusted proxies
+    sessionID       int64         // stored session of the token last presented
 }
 
-const (
-	// messageRateWindow/messageRateLimit implement a simple anti-spam window.
-	messageRateWindow      = 10 * time.Second
-	messageRateLimit       = 30
-	messageHistoryCapacity = 64
-)
+// close ends the client's transport, sending a policy-violation close frame
+// first when reason is set.
+func (c *ChatClient) close(reason string) {
+	if c.conn == nil {
+		c.closeOnce.Do(func() { close(c.done) })
+		return
+	}
+	if reason != "" {
+		closeFrame := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+		_ = c.conn.WriteControl(websocket.CloseMessage, closeFrame, time.Now().Add(time.Second))
+	}
+	if err := c.conn.Close(); err != nil {
+		log.Printf("chat client close error: %v", err)
+	}
+}
 
 type inboundEnvelope struct {
-	Type           string `json:"type"`
-	ConversationID int64  `json:"conversationId"`
-	Body           string `json:"body"`
-	TempID         string `json:"tempId"`
+	Type           string          `json:"type"`
+	ConversationID int64           `json:"conversationId"`
+	Body           string          `json:"body"`
+	AttachmentURL  string          `json:"attachmentUrl"` // for `message:send`; an https image URL
+	TempID         string          `json:"tempId"`
+	MessageType    string          `json:"messageType"` // for `message:send`; empty means text
+	Metadata       json.RawMessage `json:"metadata"`    // for `message:send` with a messageType
+	Seq            int64           `json:"seq"`         // for `ack` and `resume`
+	Token          string          `json:"token"`       // for `token:refresh`
 }
 
 type outboundMessage struct {
@@ -83,7 +182,29 @@ type messagePayload struct {
 	ConversationID int64  `json:"conversationId"`
 	SenderID       int64  `json:"senderId"`
 	Body           string `json:"body"`
-	CreatedAt      string `json:"createdAt"`
+	AttachmentURL  *string          `json:"attachmentUrl,omitempty"`
+	Kind           string           `json:"kind"`
+	MessageType    string           `json:"messageType"`
+	Metadata       json.RawMessage  `json:"metadata,omitempty"`
+	Poll           *Poll            `json:"poll,omitempty"`
+	Mentions       []MessageMention `json:"mentions,omitempty"`
+	CreatedAt      string           `json:"createdAt"`
+}
+
+func newMessagePayload(msg Message) messagePayload {
+	return messagePayload{
+		ID:             msg.ID,
+		ConversationID: msg.ConversationID,
+		SenderID:       msg.SenderID,
+		Body:           msg.Body,
+		AttachmentURL:  msg.AttachmentURL,
+		Kind:           msg.Kind,
+		MessageType:    msg.MessageType,
+		Metadata:       msg.Metadata,
+		Poll:           msg.Poll,
+		Mentions:       msg.Mentions,
+		CreatedAt:      msg.CreatedAt.Format(time.RFC3339Nano),
+	}
 }
 
 var upgrader = websocket.Upgrader{
@@ -94,21 +215,37 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-func NewChatHub(repo *EventRepository, signer *tokenSigner) *ChatHub {
+func NewChatHub(repo Store, signer *tokenSigner, pusher PushSender, mailer EmailSender, images ImageModerator, config ChatConfig) *ChatHub {
 	return &ChatHub{
 		repo:          repo,
 		signer:        signer,
+		pusher:        pusher,
+		mailer:        mailer,
+		config:        config,
+		filters:       newMessageFilterChain(repo, config.Filters, images),
 		register:      make(chan *ChatClient),
 		unregister:    make(chan *ChatClient),
 		broadcast:     make(chan chatBroadcast),
 		membership:    make(chan membershipUpdate, 16),
+		disconnect:    make(chan disconnectRequest, 16),
+		direct:        make(chan directMessage, 16),
+		control:       make(chan streamControl, 16),
+		subscribe:     make(chan subscriptionRequest, 16),
+		refresh:       make(chan sessionRefresh, 16),
+		ping:          make(chan chan struct{}),
+		stats:         make(chan chan HubStats),
 		subscriptions: make(map[int64]map[*ChatClient]struct{}),
 		clientsByUser: make(map[int64]map[*ChatClient]struct{}),
+		streams:       make(map[int64]map[string]*replayStream),
+		parked:        make(map[int64]map[*replayStream]struct{}),
 	}
 }
 
 // Run processes register/unregister/broadcast events on the hub.
 func (h *ChatHub) Run() {
+	housekeeping := time.NewTicker(time.Minute)
+	defer housekeeping.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -121,13 +258,15 @@ func (h *ChatHub) Run() {
 				h.subscriptions[conversationID][client] = struct{}{}
 			}
 			h.attachClient(client)
+			h.attachStream(client)
 		case client := <-h.unregister:
 			// A connection has gone away: close it if needed and remove every
 			// pointer to it so the GC can reclaim the client.
-			if err := client.conn.Close(); err != nil {
-				log.Printf("chat client close error: %v", err)
+			if !client.evicted {
+				client.close("")
 			}
 			h.detachClient(client)
+			h.parkStream(client)
 			for conversationID := range client.subscriptions {
                 if subs, ok := h.subscriptions[conversationID]; ok {
                     delete(subs, client)
@@ -143,8 +282,84 @@ func (h *ChatHub) Run() {
 			// HTTP handlers report membership churn through this channel so the hub
 			// can update live sockets and emit `conversation:membership` events.
 			h.applyMembershipUpdate(update)
+		case req := <-h.disconnect:
+			// Account-level changes (deletion, revocation) evict the user's sockets.
+			h.disconnectUser(req)
+		case msg := <-h.direct:
+			// User-addressed notifications bypass conversation rooms.
+			if msg.offline != nil && len(h.clientsByUser[msg.userID]) == 0 {
+				h.pushToDevices(msg.userID, msg.category, *msg.offline)
+			}
+			h.pushToUser(msg.userID, msg.payload)
+		case req := <-h.subscribe:
+			// A socket asked to join or leave a room it is allowed into.
+			h.applySubscription(req)
+		case ctl := <-h.control:
+			// Sequenced sockets ack frames or ask for what they missed.
+			h.handleStreamControl(ctl)
+		case req := <-h.refresh:
+			h.applySessionRefresh(req)
+		case reply := <-h.ping:
+			close(reply)
+		case reply := <-h.stats:
+			reply <- h.collectStats()
+		case now := <-housekeeping.C:
+			// Expire sessions and replay streams nobody came back for.
+			h.checkSessions(now)
+			h.expireStreams(now)
+		}
+	}
+}
+
+// Ping waits for Run to pick up a probe, proving the hub loop is alive and
+// not wedged, or fails when ctx expires first.
+func (h *ChatHub) Ping(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case h.ping <- reply:
+	case <-ctx.Done():
+		return fmt.Errorf("chat hub unresponsive: %w", ctx.Err())
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("chat hub unresponsive: %w", ctx.Err())
+	}
+}
+
+// disconnectUser writes a close frame to each of the user's sockets and closes
+// the connection. The read pump then fails and unregisters the client through
+// the normal path, so the send channel is never closed twice.
+func (h *ChatHub) disconnectUser(req disconnectRequest) {
+	clients, ok := h.clientsByUser[req.userID]
+	if !ok {
+		return
+	}
+	for client := range clients {
+		if req.sessionID != 0 && client.sessionID != req.sessionID {
+			continue
+		}
+		for conversationID := range client.subscriptions {
+			if subs, ok := h.subscriptions[conversationID]; ok {
+				delete(subs, client)
+				if len(subs) == 0 {
+					delete(h.subscriptions, conversationID)
+				}
+			}
 		}
+		client.evicted = true
+		client.close(req.reason)
+		delete(clients, client)
 	}
+	if req.sessionID != 0 {
+		if len(clients) == 0 {
+			delete(h.clientsByUser, req.userID)
+		}
+		return
+	}
+	delete(h.clientsByUser, req.userID)
+	h.dropStreams(req.userID)
 }
 
 func (h *ChatHub) attachClient(client *ChatClient) {
@@ -152,6 +367,26 @@ func (h *ChatHub) attachClient(client *ChatClient) {
 		h.clientsByUser[client.userID] = make(map[*ChatClient]struct{})
 	}
 	h.clientsByUser[client.userID][client] = struct{}{}
+	h.enforceConnectionLimit(client.userID)
+}
+
+// enforceConnectionLimit closes a user's oldest sockets until they are back
+// under the configured cap.
+func (h *ChatHub) enforceConnectionLimit(userID int64) {
+	limit := h.config.MaxConnsPerUser
+	if limit <= 0 {
+		return
+	}
+	for len(h.clientsByUser[userID]) > limit {
+		var oldest *ChatClient
+		for client := range h.clientsByUser[userID] {
+			if oldest == nil || client.connectedAt.Before(oldest.connectedAt) {
+				oldest = client
+			}
+		}
+		log.Printf("user %d over %d connections; closing oldest", userID, limit)
+		h.dropClient(oldest, "too many connections")
+	}
 }
 
 func (h *ChatHub) detachClient(client *ChatClient) {
@@ -164,22 +399,109 @@ func (h *ChatHub) detachClient(client *ChatClient) {
 }
 
 func (h *ChatHub) pushToConversation(conversationID int64, payload []byte) {
-	subs := h.subscriptions[conversationID]
-	if subs == nil {
+	for client := range h.subscriptions[conversationID] {
+		h.deliver(client, payload)
+	}
+	h.recordParked(conversationID, payload)
+}
+
+// deliver queues a room payload for one socket, evicting sockets whose buffer
+// is full. Sequenced sockets keep the frame buffered for a later resume.
+func (h *ChatHub) deliver(client *ChatClient, payload []byte) {
+	frame := payload
+	if client.stream != nil {
+		frame = client.stream.record(payload)
+	}
+	select {
+	case client.send <- frame:
+	default:
+		h.evict(client)
+	}
+}
+
+// deliverDirect queues a payload for one socket, dropping it when the buffer
+// is full. Sequenced sockets can still recover it with `resume`.
+func (h *ChatHub) deliverDirect(client *ChatClient, payload []byte) {
+	frame := payload
+	if client.stream != nil {
+		frame = client.stream.record(payload)
+	}
+	select {
+	case client.send <- frame:
+	default:
+		log.Printf("dropping direct payload for user %d: send buffer full", client.userID)
+	}
+}
+
+// evict closes a socket that can't keep up.
+func (h *ChatHub) evict(client *ChatClient) {
+	log.Printf("evicting slow chat client for user %d", client.userID)
+	h.dropClient(client, "")
+}
+
+// dropClient removes a socket from the hub and closes it, with a close frame
+// when reason is set. The read pump then fails and unregisters the client
+// through the normal path.
+func (h *ChatHub) dropClient(client *ChatClient, reason string) {
+	for conversationID := range client.subscriptions {
+		if subs, ok := h.subscriptions[conversationID]; ok {
+			delete(subs, client)
+			if len(subs) == 0 {
+				delete(h.subscriptions, conversationID)
+			}
+		}
+	}
+	h.detachClient(client)
+	h.parkStream(client)
+	client.evicted = true
+	client.close(reason)
+}
+
+// pushToUser delivers a payload to every socket of a user. Slow sockets drop
+// the payload instead of being closed; the room fan-out handles eviction.
+func (h *ChatHub) pushToUser(userID int64, payload []byte) {
+	for client := range h.clientsByUser[userID] {
+		h.deliverDirect(client, payload)
+	}
+	for _, stream := range h.streams[userID] {
+		if stream.client == nil {
+			stream.record(payload)
+		}
+	}
+}
+
+// applySubscription updates a single socket's rooms and confirms the change.
+// Sockets the hub has already let go of are ignored.
+func (h *ChatHub) applySubscription(req subscriptionRequest) {
+	client := req.client
+	if _, live := h.clientsByUser[client.userID][client]; !live {
 		return
 	}
-	for client := range subs {
-		select {
-		case client.send <- payload:
-		default:
-			close(client.send)
+
+	eventType := "conversation:subscribed"
+	if req.subscribe {
+		client.subscriptions[req.conversationID] = struct{}{}
+		if _, ok := h.subscriptions[req.conversationID]; !ok {
+			h.subscriptions[req.conversationID] = make(map[*ChatClient]struct{})
+		}
+		h.subscriptions[req.conversationID][client] = struct{}{}
+	} else {
+		eventType = "conversation:unsubscribed"
+		delete(client.subscriptions, req.conversationID)
+		if subs, ok := h.subscriptions[req.conversationID]; ok {
 			delete(subs, client)
-			h.detachClient(client)
+			if len(subs) == 0 {
+				delete(h.subscriptions, req.conversationID)
+			}
 		}
 	}
-	if len(subs) == 0 {
-		delete(h.subscriptions, conversationID)
+
+	payload, err := json.Marshal(subscriptionEvent{Type: eventType, ConversationID: req.conversationID})
+	if err != nil {
+		log.Printf("marshal subscription event failed: %v", err)
+		return
 	}
+	h.deliverDirect(client, payload)
 }
 
 func (h *ChatHub) applyMembershipUpdate(update membershipUpdate) {
@@ -196,6 +518,11 @@ func (h *ChatHub) applyMembershipUpdate(update membershipUpdate) {
                 h.subscriptions[update.conversationID][client] = struct{}{}
             }
         }
+		for _, stream := range h.streams[update.userID] {
+			if stream.client == nil {
+				h.parkInRoom(stream, update.conversationID)
+			}
+		}
 	case "removed":
 		// Remove the conversation from each socket owned by the departing user
 		// and drop any room set that becomes empty.
@@ -210,6 +537,11 @@ func (h *ChatHub) applyMembershipUpdate(update membershipUpdate) {
                 }
             }
         }
+		for _, stream := range h.streams[update.userID] {
+			if stream.client == nil {
+				h.unparkFromRoom(stream, update.conversationID)
+			}
+		}
 	default:
 		log.Printf("unknown membership action: %s", update.action)
 		return
@@ -244,37 +576,226 @@ func (h *ChatHub) NotifyMembership(conversationID, userID int64, action string)
 	}
 }
 
-// handleWebSocket authenticates via token query param and upgrades to WS.
-func (h *ChatHub) handleWebSocket(c *gin.Context) {
-	token := c.Query("token")
-	if strings.TrimSpace(token) == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "token is required"})
+// NotifyUser queues a raw payload for every socket owned by userID.
+func (h *ChatHub) NotifyUser(userID int64, payload []byte) {
+	h.notifyUser(directMessage{userID: userID, payload: payload})
+}
+
+func (h *ChatHub) notifyUser(msg directMessage) {
+	select {
+	case h.direct <- msg:
+	default:
+		go func() {
+			h.direct <- msg
+		}()
+	}
+}
+
+// NotifyJoinRequest tells a user's sockets about a join request change.
+func (h *ChatHub) NotifyJoinRequest(userID int64, eventType string, conversationID int64, req ConversationJoinRequest) {
+	payload, err := json.Marshal(joinRequestEvent{
+		Type:           eventType,
+		EventID:        req.EventID,
+		ConversationID: conversationID,
+		Request:        req,
+	})
+	if err != nil {
+		log.Printf("marshal join request event failed: %v", err)
+		return
+	}
+	h.NotifyUser(userID, payload)
+}
+
+// NotifyJoinDecision sends `join_request:decided` to the requester's sockets
+// once the host approves or denies, so their Join button updates without
+// polling. Decisions reach it through the outbox dispatcher.
+func (h *ChatHub) NotifyJoinDecision(conversationID int64, req ConversationJoinRequest) {
+	payload, err := json.Marshal(joinRequestEvent{
+		Type:           "join_request:decided",
+		EventID:        req.EventID,
+		ConversationID: conversationID,
+		Request:        req,
+		Decision:       req.Status,
+	})
+	if err != nil {
+		log.Printf("marshal join request event failed: %v", err)
 		return
 	}
+	h.NotifyUser(req.UserID, payload)
+}
 
-	claims, err := h.signer.verify(token)
+// NotifyNewJoinRequest sends `join_request:new` to the event host's sockets so
+// their requests badge updates live, or a push notification when the host has
+// no socket connected.
+func (h *ChatHub) NotifyNewJoinRequest(hostID int64, event *Event, conversationID int64, req ConversationJoinRequest, requester joinRequester) {
+	payload, err := json.Marshal(joinRequestEvent{
+		Type:           "join_request:new",
+		EventID:        req.EventID,
+		ConversationID: conversationID,
+		Request:        req,
+		Requester:      &requester,
+	})
 	if err != nil {
-		status := http.StatusUnauthorized
-		if err == errExpiredToken {
-			status = http.StatusUnauthorized
-		}
-		c.JSON(status, gin.H{"error": "invalid or expired token"})
+		log.Printf("marshal join request event failed: %v", err)
 		return
 	}
+	h.notifyUser(directMessage{
+		userID:   hostID,
+		payload:  payload,
+		category: notifyJoinRequests,
+		offline: &PushNotification{
+			Title: "New join request",
+			Body:  requester.Name + " wants to join " + event.Title,
+			Data: map[string]any{
+				"type":      "join_request:new",
+				"eventId":   req.EventID,
+				"requestId": req.ID,
+			},
+		},
+	})
+}
 
-	userID := claims.UserID
+// NotifyConnection tells a user's sockets about a connection change, where
+// connection is described from that user's side.
+func (h *ChatHub) NotifyConnection(userID int64, eventType string, connection Connection) {
+	payload, err := json.Marshal(connectionEvent{Type: eventType, Connection: connection})
+	if err != nil {
+		log.Printf("marshal connection event failed: %v", err)
+		return
+	}
+	h.NotifyUser(userID, payload)
+}
+
+// BroadcastMessage fans a stored message out to the conversation's sockets as
+// `message:new`, the same way socket-sent messages are delivered.
+func (h *ChatHub) BroadcastMessage(msg Message) {
+	payload, err := json.Marshal(outboundMessage{Type: "message:new", Message: newMessagePayload(msg)})
+	if err != nil {
+		log.Printf("marshal outbound failed: %v", err)
+		return
+	}
+	h.broadcast <- chatBroadcast{conversationID: msg.ConversationID, payload: payload}
+}
+
+// EchoHiddenMessage sends a hidden message to its sender's sockets only, as
+// if it had gone out to the whole conversation.
+func (h *ChatHub) EchoHiddenMessage(msg Message) {
+	payload, err := json.Marshal(outboundMessage{Type: "message:new", Message: newMessagePayload(msg)})
+	if err != nil {
+		log.Printf("marshal outbound failed: %v", err)
+		return
+	}
+	h.NotifyUser(msg.SenderID, payload)
+}
+
+// PostSystemMessage stores a system notice in the conversation and broadcasts
+// it. The change it describes has already happened, so failures are only
+// logged.
+func (h *ChatHub) PostSystemMessage(ctx context.Context, conversationID, actorID int64, body string) {
+	msg, err := h.repo.CreateSystemMessage(ctx, conversationID, actorID, body)
+	if err != nil {
+		log.Printf("create system message failed: %v", err)
+		return
+	}
+	h.BroadcastMessage(*msg)
+}
+
+// Announce posts a system notice whose %s verbs are filled with the names of
+// userIDs, in order, e.g. Announce(ctx, convo, host, "%s removed %s", host, user).
+func (h *ChatHub) Announce(ctx context.Context, conversationID, actorID int64, format string, userIDs ...int64) {
+	names, err := h.repo.GetUserNames(ctx, userIDs)
+	if err != nil {
+		log.Printf("resolve names for system message failed: %v", err)
+		return
+	}
+	args := make([]any, 0, len(userIDs))
+	for _, userID := range userIDs {
+		args = append(args, names[userID])
+	}
+	h.PostSystemMessage(ctx, conversationID, actorID, fmt.Sprintf(format, args...))
+}
+
+// DisconnectUser closes every live socket owned by userID with the given reason.
+func (h *ChatHub) DisconnectUser(userID int64, reason string) {
+	h.queueDisconnect(disconnectRequest{userID: userID, reason: reason})
+}
+
+// DisconnectSession closes the user's live sockets signed in with sessionID.
+func (h *ChatHub) DisconnectSession(userID, sessionID int64, reason string) {
+	h.queueDisconnect(disconnectRequest{userID: userID, sessionID: sessionID, reason: reason})
+}
+
+func (h *ChatHub) queueDisconnect(req disconnectRequest) {
+	select {
+	case h.disconnect <- req:
+	default:
+		go func() {
+			h.disconnect <- req
+		}()
+	}
+}
+
+// handleWebSocket authenticates the socket and upgrades to WS. The session
+// token can come from a `bearer, <token>` Sec-WebSocket-Protocol pair or, so it
+// stays out of proxy and access logs, an `{"type":"auth","token":...}` first
+// frame sent within socketAuthTimeout. The `?token=` query param still works
+// for older clients.
+//
+// With `clientId` (a stable per-device key) the socket is sequenced: frames
+// carry `seq`, the client sends `{"type":"ack","seq":N}` as it processes them,
+// and after reconnecting with the same clientId sends `{"type":"resume","seq":N}`
+// to get everything after N, or `replay:gap` if that is no longer possible.
+func (h *ChatHub) handleWebSocket(c *gin.Context) {
+	clientID := strings.TrimSpace(c.Query("clientId"))
+	if len(clientID) > maxClientIDLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "clientId is too long"})
+		return
+	}
+
+	token := strings.TrimSpace(c.Query("token"))
+	var responseHeader http.Header
+	if token == "" {
+		if protocolToken, ok := bearerSubprotocol(c.Request); ok {
+			token = protocolToken
+			responseHeader = http.Header{"Sec-Websocket-Protocol": {bearerProtocol}}
+		}
+	}
+
+	// A token offered with the handshake is checked before upgrading so bad
+	// credentials get a plain 401.
+	var claims *sessionClaims
+	if token != "" {
+		var err error
+		claims, err = h.authenticateSocket(c.Request.Context(), token, c.ClientIP())
+		if err != nil {
+			if errors.Is(err, errSocketUnauthorized) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify session"})
+			return
+		}
+	}
 
 	// Upgrade the HTTP request into a WebSocket connection. From here on the
 	// client and server communicate using frames handled by read/write pumps.
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, responseHeader)
 	if err != nil {
 		log.Printf("websocket upgrade failed: %v", err)
 		return
 	}
 
+	if claims == nil {
+		var ok bool
+		if claims, ok = h.authenticateFirstFrame(conn, c.ClientIP()); !ok {
+			return
+		}
+	}
+	userID := claims.UserID
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
-	conversations, err := h.repo.ListConversations(ctx, userID)
+	conversations, _, err := h.repo.ListConversations(ctx, userID, ConversationListOptions{IncludeArchived: true})
 	if err != nil {
 		log.Printf("list conversations failed: %v", err)
 		conn.Close()
@@ -287,6 +808,11 @@ func (h *ChatHub) handleWebSocket(c *gin.Context) {
 		send:          make(chan []byte, 8),
 		userID:        userID,
 		subscriptions: make(map[int64]struct{}),
+		clientID:      clientID,
+		connectedAt:   time.Now(),
+		expiresAt:     claims.ExpiresAt,
+		remoteIP:      c.ClientIP(),
+		sessionID:     claims.SessionID,
 	}
 
 	for _, convo := range conversations {
@@ -295,6 +821,7 @@ func (h *ChatHub) handleWebSocket(c *gin.Context) {
 
 	// Registration hands the client to the hub goroutine. From this point the
 	// hub owns the lifecycle and the pumps keep the socket alive.
+	h.queueHello(client)
 	h.register <- client
 
 	go client.writePump()
@@ -306,10 +833,11 @@ func (c *ChatClient) readPump() {
 	defer func() {
 		c.hub.unregister <- c
 	}()
-	c.conn.SetReadLimit(1024)
-	_ = c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	c.conn.SetReadLimit(c.hub.config.frameLimit())
+	readTimeout := c.hub.config.ReadTimeout
+	_ = c.conn.SetReadDeadline(time.Now().Add(readTimeout))
 	c.conn.SetPongHandler(func(string) error {
-		return c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		return c.conn.SetReadDeadline(time.Now().Add(readTimeout))
 	})
 
 	// Loop forever until the client disconnects or an unrecoverable error occurs.
@@ -333,6 +861,12 @@ func (c *ChatClient) readPump() {
 			c.handleSend(inbound)
 		case "ping":
 			c.send <- []byte(`{"type":"pong"}`)
+		case "subscribe", "unsubscribe":
+			c.handleSubscription(inbound)
+		case "token:refresh":
+			c.handleTokenRefresh(inbound)
+		case "ack", "resume":
+			c.hub.control <- streamControl{client: c, kind: inbound.Type, seq: inbound.Seq}
 		default:
 			log.Printf("unknown message type: %s", inbound.Type)
 		}
@@ -341,7 +875,7 @@ func (c *ChatClient) readPump() {
 
 // writePump forwards outbound chat events and keep-alive pings.
 func (c *ChatClient) writePump() {
-	ticker := time.NewTicker(50 * time.Second)
+	ticker := time.NewTicker(c.hub.config.PingInterval)
 	defer func() {
 		ticker.Stop()
 		if err := c.conn.Close(); err != nil {
@@ -374,12 +908,57 @@ func (c *ChatClient) writePump() {
 
 // handleSend validates membership, stores, and broadcasts a message.
 func (c *ChatClient) handleSend(inbound inboundEnvelope) {
-	if inbound.ConversationID == 0 || strings.TrimSpace(inbound.Body) == "" {
+	if inbound.ConversationID == 0 {
+		return
+	}
+	messageType, metadata, body, err := normalizeMessageContent(inbound.MessageType, inbound.Metadata, inbound.Body)
+	if err != nil {
+		payload, err := json.Marshal(gin.H{
+			"type":   "system:error",
+			"code":   "invalid_message",
+			"tempId": inbound.TempID,
+			"error":  err.Error(),
+		})
+		if err == nil {
+			c.send <- payload
+		}
+		return
+	}
+	attachmentURL, err := normalizeAttachmentURL(inbound.AttachmentURL)
+	if err != nil {
+		payload, err := json.Marshal(gin.H{
+			"type":   "system:error",
+			"code":   "invalid_attachment",
+			"tempId": inbound.TempID,
+			"error":  err.Error(),
+		})
+		if err == nil {
+			c.send <- payload
+		}
+		return
+	}
+	if attachmentURL != "" && strings.TrimSpace(body) == "" {
+		body = "Sent a photo"
+	}
+	inbound.Body = body
+	if strings.TrimSpace(inbound.Body) == "" {
+		return
+	}
+	if utf8.RuneCountInString(inbound.Body) > c.hub.config.MaxMessageLength {
+		payload, err := json.Marshal(gin.H{
+			"type":      "system:error",
+			"code":      "message_too_long",
+			"tempId":    inbound.TempID,
+			"maxLength": c.hub.config.MaxMessageLength,
+		})
+		if err == nil {
+			c.send <- payload
+		}
 		return
 	}
 	now := time.Now()
 	if !c.allowMessage(now) {
-		log.Printf("user %d exceeded message rate limit", c.userID)
+		log.Printf("user %d (%s) exceeded message rate limit", c.userID, c.remoteIP)
 		c.send <- []byte(`{"type":"system:error","code":"rate_limited"}`)
 		return
 	}
@@ -399,15 +978,104 @@ func (c *ChatClient) handleSend(inbound inboundEnvelope) {
         log.Printf("user %d attempted to send to conversation %d without membership", c.userID, inbound.ConversationID)
         return
     }
+	locked, err := c.hub.repo.IsConversationLocked(ctx, inbound.ConversationID)
+	if err != nil {
+		log.Printf("conversation lock check failed: %v", err)
+		return
+	}
+	if locked {
+		payload, err := json.Marshal(gin.H{
+			"type":           "system:error",
+			"code":           conversationLockedCode,
+			"tempId":         inbound.TempID,
+			"conversationId": inbound.ConversationID,
+		})
+		if err == nil {
+			c.send <- payload
+		}
+		return
+	}
+
+	// A resend with a tempId we already stored (the first ack got lost) is
+	// answered with the original message instead of posting it twice.
+	var idempotencyKey string
+	if inbound.TempID != "" {
+		if key, ok := normalizeIdempotencyKey(inbound.TempID); ok {
+			idempotencyKey = fmt.Sprintf("%d:%s", inbound.ConversationID, key)
+			if c.replaySend(ctx, inbound.TempID, idempotencyKey) {
+				return
+			}
+		}
+	}
+
+	wait, err := c.hub.repo.SlowModeWait(ctx, inbound.ConversationID, c.userID, now)
+	if err != nil {
+		log.Printf("slow mode check failed: %v", err)
+		return
+	}
+	if wait > 0 {
+		payload, err := json.Marshal(gin.H{
+			"type":              "system:error",
+			"code":              "slow_mode",
+			"tempId":            inbound.TempID,
+			"conversationId":    inbound.ConversationID,
+			"retryAfterSeconds": int(math.Ceil(wait.Seconds())),
+		})
+		if err == nil {
+			c.send <- payload
+		}
+		return
+	}
+
+	verdict := c.hub.filters.Run(ctx, MessageFilterInput{ConversationID: inbound.ConversationID, SenderID: c.userID, Body: inbound.Body, AttachmentURL: attachmentURL, Now: now})
+	if verdict != nil && verdict.Action == filterActionReject {
+		log.Printf("user %d message to conversation %d rejected by %s filter: %s", c.userID, inbound.ConversationID, verdict.Filter, verdict.Reason)
+		reply := gin.H{
+			"type":           "system:error",
+			"code":           verdict.Code,
+			"tempId":         inbound.TempID,
+			"conversationId": inbound.ConversationID,
+		}
+		if verdict.RetryAfter > 0 {
+			reply["retryAfterSeconds"] = int(math.Ceil(verdict.RetryAfter.Seconds()))
+		}
+		payload, err := json.Marshal(reply)
+		if err == nil {
+			c.send <- payload
+		}
+		return
+	}
+	hidden := verdict != nil && verdict.Action == filterActionHide
+	shadowBanned, err := c.hub.repo.IsShadowBanned(ctx, inbound.ConversationID, c.userID)
+	if err != nil {
+		log.Printf("shadow ban check failed: %v", err)
+		return
+	}
+	if shadowBanned {
+		hidden = true
+	}
 
+    var attachment *string
+    if attachmentURL != "" {
+        attachment = &attachmentURL
+    }
     params := CreateMessageParams{
         ConversationID: inbound.ConversationID,
         SenderID:       c.userID,
         Body:           inbound.Body,
+        AttachmentURL:  attachment,
+        MessageType:    messageType,
+        Metadata:       metadata,
         DeliveryStatus: "sent",
+        Notify:         true,
+        Hidden:         hidden,
+        IdempotencyKey: idempotencyKey,
     }
 
 	msg, err := c.hub.repo.CreateMessage(ctx, params)
+	if errors.Is(err, ErrIdempotencyKeyUsed) && c.replaySend(ctx, inbound.TempID, idempotencyKey) {
+		return
+	}
 	if err != nil {
 		log.Printf("create message failed: %v", err)
 		return
@@ -416,17 +1084,16 @@ func (c *ChatClient) handleSend(inbound inboundEnvelope) {
 	if err := c.hub.repo.UpdateReadState(ctx, msg.ConversationID, c.userID, msg.ID); err != nil {
 		log.Printf("update read state after send failed: %v", err)
 	}
+	if verdict != nil {
+		if err := c.hub.repo.FlagMessage(ctx, msg.ID, verdict.Filter, verdict.Action, verdict.Reason); err != nil {
+			log.Printf("flag message %d failed: %v", msg.ID, err)
+		}
+	}
 
 	envelope := outboundMessage{
 		Type:   "message:new",
 		TempID: inbound.TempID,
-		Message: messagePayload{
-			ID:             msg.ID,
-			ConversationID: msg.ConversationID,
-			SenderID:       msg.SenderID,
-			Body:           msg.Body,
-			CreatedAt:      msg.CreatedAt.Format(time.RFC3339Nano),
-		},
+		Message: newMessagePayload(*msg),
 	}
 
 	payload, err := json.Marshal(envelope)
@@ -435,48 +1102,148 @@ func (c *ChatClient) handleSend(inbound inboundEnvelope) {
 		return
 	}
 
+	// A hidden message is acked like any other, but only to the sender.
+	if hidden {
+		c.hub.NotifyUser(c.userID, payload)
+		return
+	}
 	c.hub.broadcast <- chatBroadcast{conversationID: msg.ConversationID, payload: payload}
+	c.hub.notifyMentions(*msg)
 }
 
-// allowMessage implements a sliding window limiter to curb rapid sends.
-func (c *ChatClient) allowMessage(now time.Time) bool {
-	windowStart := now.Add(-messageRateWindow)
-	filtered := c.messageHistory[:0]
-	for _, ts := range c.messageHistory {
-		if ts.After(windowStart) {
-			filtered = append(filtered, ts)
-		}
+// replaySend acks a resent message to this socket only, reusing the message
+// stored under key. It reports whether one was found.
+func (c *ChatClient) replaySend(ctx context.Context, tempID, key string) bool {
+	messageID, found, err := c.hub.repo.LookupIdempotencyKey(ctx, c.userID, idempotencyScopeMessage, key)
+	if err != nil {
+		log.Printf("idempotency lookup failed: %v", err)
+		return false
 	}
-	c.messageHistory = filtered
-
-	if len(c.messageHistory) >= messageRateLimit {
+	if !found {
 		return false
 	}
-
-	c.messageHistory = append(c.messageHistory, now)
-	if len(c.messageHistory) > messageHistoryCapacity {
-		c.messageHistory = c.messageHistory[len(c.messageHistory)-messageHistoryCapacity:]
+	msg, err := c.hub.repo.GetMessageByID(ctx, messageID)
+	if err != nil {
+		log.Printf("load replayed message failed: %v", err)
+		return false
+	}
+	payload, err := json.Marshal(outboundMessage{
+		Type:    "message:new",
+		TempID:  tempID,
+		Message: newMessagePayload(*msg),
+	})
+	if err != nil {
+		log.Printf("marshal outbound failed: %v", err)
+		return true
 	}
+	c.send <- payload
+	return true
+}
+
+// handleSubscription lets a socket pick up a conversation it joined after
+// connecting, or stop listening to one, without reconnecting. Subscribing is
+// checked against DB membership; unsubscribing never needs to be.
+func (c *ChatClient) handleSubscription(inbound inboundEnvelope) {
+	if inbound.ConversationID <= 0 {
+		return
+	}
+	subscribe := inbound.Type == "subscribe"
+	if subscribe {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		allowed, err := c.hub.repo.IsConversationMember(ctx, inbound.ConversationID, c.userID)
+		if err != nil {
+			log.Printf("membership check failed: %v", err)
+			return
+		}
+		if !allowed {
+			c.send <- []byte(fmt.Sprintf(`{"type":"system:error","code":"not_member","conversationId":%d}`, inbound.ConversationID))
+			return
+		}
+	}
+	c.hub.subscribe <- subscriptionRequest{client: c, conversationID: inbound.ConversationID, subscribe: subscribe}
+}
+
+// allowMessage implements a sliding window limiter to curb rapid sends. The
+// history never holds more than the configured limit.
+func (c *ChatClient) allowMessage(now time.Time) bool {
+	windowStart := now.Add(-c.hub.config.MessageRateWindow)
+	filtered := c.messageHistory[:0]
+	for _, ts := range c.messageHistory {
+		if ts.After(windowStart) {
+			filtered = append(filtered, ts)
+		}
+	}
+	c.messageHistory = filtered
+
+	if len(c.messageHistory) >= c.hub.config.MessageRateLimit {
+		return false
+	}
+
+	c.messageHistory = append(c.messageHistory, now)
 	return true
 }
 
 // RegisterChatRoutes mounts all chat-related REST endpoints under the provided
 // router group. The caller is expected to attach authentication middleware
 // before invoking this so that handlers can read the session from context.
-func RegisterChatRoutes(router *gin.RouterGroup, repo *EventRepository, hub *ChatHub) {
+func RegisterChatRoutes(router *gin.RouterGroup, repo Store, hub *ChatHub) {
 	handler := &ChatHTTPHandler{repo: repo, hub: hub}
 
 	router.GET("/conversations", handler.listConversations)
+	router.GET("/conversations/:id", handler.getConversation)
 	router.GET("/conversations/:id/messages", handler.listMessages)
+	router.POST("/conversations/:id/messages/schedule", handler.scheduleMessage)
+	router.GET("/conversations/:id/messages/scheduled", handler.listScheduledMessages)
+	router.DELETE("/conversations/:id/messages/scheduled/:scheduledId", handler.cancelScheduledMessage)
+	router.GET("/conversations/:id/shadow-bans", handler.listShadowBans)
+	router.PUT("/conversations/:id/shadow-bans/:userId", handler.shadowBanUser)
+	router.DELETE("/conversations/:id/shadow-bans/:userId", handler.liftShadowBan)
+	router.POST("/conversations/:id/polls", handler.createPoll)
+	router.GET("/conversations/:id/polls/:pollId", handler.getPoll)
+	router.PUT("/conversations/:id/polls/:pollId/votes", handler.votePoll)
+	router.DELETE("/conversations/:id/polls/:pollId/votes", handler.retractPollVote)
+	router.POST("/conversations/:id/polls/:pollId/close", handler.closePoll)
 	router.POST("/conversations", handler.createConversation)
+	router.POST("/conversations/direct", handler.createDirectConversation)
+	router.POST("/conversations/:id/members", handler.addConversationMembers)
+	router.PUT("/conversations/:id/title", handler.renameConversation)
+	router.POST("/conversations/:id/read", handler.markRead)
+	router.POST("/conversations/:id/archive", handler.archiveConversation)
+	router.DELETE("/conversations/:id/archive", handler.unarchiveConversation)
+	router.POST("/conversations/:id/mute", handler.muteConversation)
+	router.DELETE("/conversations/:id/mute", handler.unmuteConversation)
+	router.PUT("/conversations/:id/slow-mode", handler.setSlowMode)
+	router.GET("/conversations/unread", handler.unreadTotals)
+	router.GET("/conversations/search", handler.searchConversations)
 	router.POST("/events/:id/chat/requests", handler.requestJoin)
+	router.POST("/events/:id/chat/requests/batch", handler.decideJoins)
 	router.POST("/events/:id/chat/requests/:userId/approve", handler.approveJoin)
 	router.POST("/events/:id/chat/requests/:userId/deny", handler.denyJoin)
 	router.DELETE("/events/:id/chat/members/:userId", handler.removeMember)
+	router.POST("/events/:id/invites", handler.createInvite)
+	router.POST("/invites/:token/accept", handler.acceptInvite)
+	router.GET("/links/resolve", handler.resolveLink)
+	router.GET("/events/:id/chat/bans", handler.listBans)
+	router.DELETE("/events/:id/chat/bans/:userId", handler.unbanUser)
+	router.POST("/events/:id/chat/co-hosts/:userId", handler.addCoHost)
+	router.DELETE("/events/:id/chat/co-hosts/:userId", handler.removeCoHost)
+	router.GET("/events/:id/members", handler.listEventMembers)
+	router.GET("/events/:id/chat/requests", handler.listJoinRequests)
+	router.POST("/events/:id/chat/waitlist/:userId/promote", handler.promoteWaitlisted)
+	router.POST("/events/:id/time-poll", handler.createTimePoll)
+	router.GET("/events/:id/time-poll", handler.getTimePoll)
+	router.PUT("/events/:id/time-poll/availability", handler.setTimePollAvailability)
+	router.POST("/events/:id/time-poll/confirm", handler.confirmTimeSlot)
+	router.DELETE("/events/:id/time-poll", handler.deleteTimePoll)
+	router.GET("/events/:id/checkin-code", handler.getCheckinCode)
+	router.POST("/events/:id/checkin", handler.checkIn)
+	router.GET("/events/:id/checkins", handler.listCheckins)
 }
 
 type ChatHTTPHandler struct {
-    repo *EventRepository
+    repo Store
     hub  *ChatHub
 }
 
@@ -485,30 +1252,57 @@ type createConversationRequest struct {
 	MemberIDs []int64 `json:"memberIds"`
 }
 
+type createDirectConversationRequest struct {
+	UserID int64 `json:"userId" binding:"required,gte=1"`
+}
+
+type addConversationMembersRequest struct {
+	MemberIDs []int64 `json:"memberIds" binding:"required,min=1"`
+}
+
+type renameConversationRequest struct {
+	Title string `json:"title" binding:"required,min=1,max=80"`
+}
+
+type markReadRequest struct {
+	LastReadMessageID int64 `json:"last_read_message_id" binding:"required,gte=1"`
+}
+
 type createConversationResponse struct {
 	Conversation ConversationSummary `json:"conversation"`
 }
 
 type listConversationResponse struct {
 	Conversations []ConversationSummary `json:"conversations"`
+	NextCursor    string                `json:"next_cursor,omitempty"`
 }
 
 type listMessagesResponse struct {
 	Messages []messagePayload `json:"messages"`
 }
 
+type eventMembersResponse struct {
+	Members []EventMember `json:"members"`
+}
+
 type joinRequestResponse struct {
 	Request ConversationJoinRequest `json:"request"`
 }
 
+type listJoinRequestsResponse struct {
+	Requests []ConversationJoinRequest `json:"requests"`
+}
+
 // createConversation provisions a new conversation (optionally titled) and
 // ensures the creator is a member. The request body accepts an optional title
 // and a list of member IDs. The creator is automatically included if omitted.
+// Conversations outside an event may only include the caller's connections.
 //
 // Responses:
 //  - 201 with a hydrated ConversationSummary on success
 //  - 401 if the caller has no session
 //  - 400 for invalid JSON
+//  - 403 if a member is not connected with the caller
 //  - 500 for repository/database failures
 func (h *ChatHTTPHandler) createConversation(c *gin.Context) {
 	claims, ok := sessionFromContext(c)
@@ -526,13 +1320,28 @@ func (h *ChatHTTPHandler) createConversation(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
+	for _, memberID := range payload.MemberIDs {
+		if memberID == claims.UserID {
+			continue
+		}
+		connected, err := h.repo.AreConnected(ctx, claims.UserID, memberID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check connections"})
+			return
+		}
+		if !connected {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you can only start conversations with your connections"})
+			return
+		}
+	}
+
 	convo, err := h.repo.CreateConversation(ctx, payload.Title, claims.UserID, payload.MemberIDs, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create conversation"})
 		return
 	}
 
-	summary, err := h.repo.hydrateConversationSummary(ctx, *convo, claims.UserID)
+	summary, err := h.repo.HydrateConversationSummary(ctx, *convo, claims.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation details"})
 		return
@@ -541,306 +1350,1054 @@ func (h *ChatHTTPHandler) createConversation(c *gin.Context) {
 	c.JSON(http.StatusCreated, createConversationResponse{Conversation: summary})
 }
 
-// listConversations returns all conversations visible to the current user,
-// enriched with participants, last message preview, unread counts, and
-// optional event metadata.
+// createDirectConversation returns the caller's one-to-one conversation with
+// `userId`, creating it only if the pair doesn't already have one. The target
+// must be one of the caller's connections.
 //
 // Responses:
-//  - 200 with a list of ConversationSummary items
+//  - 200 with the existing ConversationSummary
+//  - 201 with a newly created ConversationSummary
 //  - 401 if the caller has no session
+//  - 400 for invalid JSON or a request to message oneself
+//  - 403 if the target is not connected with the caller
 //  - 500 for repository/database failures
-func (h *ChatHTTPHandler) listConversations(c *gin.Context) {
+func (h *ChatHTTPHandler) createDirectConversation(c *gin.Context) {
 	claims, ok := sessionFromContext(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
-	defer cancel()
-
-	conversations, err := h.repo.ListConversations(ctx, claims.UserID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversations"})
-		return
-	}
-
-	c.JSON(http.StatusOK, listConversationResponse{Conversations: conversations})
-}
-
-// listMessages returns the most recent messages for a conversation the user
-// can access. It validates membership, supports basic limit/offset paging, and
-// advances the caller's read cursor to the newest returned message.
-//
-// Query params: `limit` (default 20), `offset` (default 0).
-// Responses:
-//  - 200 with a chronologically ordered message list
-//  - 401 if the caller has no session
-//  - 400 for invalid conversation id
-//  - 403 if the user is not a member of the conversation
-//  - 500 for repository/database failures
-func (h *ChatHTTPHandler) listMessages(c *gin.Context) {
-	claims, ok := sessionFromContext(c)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+	var payload createDirectConversationRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	conversationIDParam := c.Param("id")
-	conversationID, err := strconv.ParseInt(conversationIDParam, 10, 64)
-	if err != nil || conversationID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+	if payload.UserID == claims.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot start a conversation with yourself"})
 		return
 	}
 
-	limitParam := c.DefaultQuery("limit", "20")
-	offsetParam := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitParam)
-	if err != nil {
-		limit = 20
-	}
-	offset, err := strconv.Atoi(offsetParam)
-	if err != nil {
-		offset = 0
-	}
-
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
-	isMember, err := h.repo.IsConversationMember(ctx, conversationID, claims.UserID)
+	connected, err := h.repo.AreConnected(ctx, claims.UserID, payload.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify membership"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check connections"})
 		return
 	}
-	if !isMember {
-		c.JSON(http.StatusForbidden, gin.H{"error": "conversation access denied"})
+	if !connected {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only start conversations with your connections"})
 		return
 	}
 
-	messages, err := h.repo.ListMessages(ctx, conversationID, limit, offset)
+	convo, created, err := h.repo.FindOrCreateDirectConversation(ctx, claims.UserID, payload.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load messages"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open conversation"})
 		return
 	}
 
-	if len(messages) > 0 {
-		latest := messages[0]
-		if err := h.repo.UpdateReadState(ctx, conversationID, claims.UserID, latest.ID); err != nil {
-			log.Printf("update read state failed: %v", err)
-		}
+	summary, err := h.repo.HydrateConversationSummary(ctx, *convo, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation details"})
+		return
 	}
 
-	payloads := make([]messagePayload, 0, len(messages))
-	for _, msg := range messages {
-		payloads = append(payloads, messagePayload{
-			ID:             msg.ID,
-			ConversationID: msg.ConversationID,
-			SenderID:       msg.SenderID,
-			Body:           msg.Body,
-			CreatedAt:      msg.CreatedAt.Format(time.RFC3339Nano),
-		})
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
 	}
-
-	c.JSON(http.StatusOK, listMessagesResponse{Messages: payloads})
+	c.JSON(status, createConversationResponse{Conversation: summary})
 }
 
-// requestJoin creates a pending request for the current user to join an event's
-// group conversation. The event must exist and have a chat conversation. If the
-// user is already a member or a request is pending, a conflict is returned.
+// addConversationMembers lets the owner of a non-event conversation add more
+// of their connections. New members' sockets are subscribed and a system
+// message announcing who joined is posted to the chat.
 //
 // Responses:
-//  - 201 with the created join request
+//  - 200 with the updated ConversationSummary and the `addedIds`
 //  - 401 if the caller has no session
-//  - 400 for invalid event id
-//  - 404 if the event or its conversation is missing
-//  - 409 if a request already exists or the user is already a member
+//  - 400 for invalid JSON, conversation id, or an event conversation
+//  - 403 if the caller is not the owner or a member is not a connection
+//  - 404 if the conversation does not exist
 //  - 500 for repository/database failures
-func (h *ChatHTTPHandler) requestJoin(c *gin.Context) {
+func (h *ChatHTTPHandler) addConversationMembers(c *gin.Context) {
 	claims, ok := sessionFromContext(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
 		return
 	}
 
-	eventIDParam := c.Param("id")
-	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
-	if err != nil || eventID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var payload addConversationMembersRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
-	req, err := h.repo.CreateJoinRequest(ctx, eventID, claims.UserID)
+	for _, memberID := range payload.MemberIDs {
+		if memberID == claims.UserID {
+			continue
+		}
+		connected, err := h.repo.AreConnected(ctx, claims.UserID, memberID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check connections"})
+			return
+		}
+		if !connected {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you can only add your connections"})
+			return
+		}
+	}
+
+	added, err := h.repo.AddConversationMembers(ctx, conversationID, claims.UserID, payload.MemberIDs)
 	if err != nil {
 		switch {
-		case errors.Is(err, ErrEventNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
-		case errors.Is(err, ErrAlreadyConversationMember):
-			c.JSON(http.StatusConflict, gin.H{"error": "already a member of this chat"})
-		case errors.Is(err, ErrJoinRequestExists):
-			c.JSON(http.StatusConflict, gin.H{"error": "a pending request already exists"})
 		case errors.Is(err, ErrConversationNotFound):
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "chat conversation missing for event"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+		case errors.Is(err, ErrEventConversation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "event chats are joined through requests"})
+		case errors.Is(err, ErrNotConversationMember), errors.Is(err, ErrNotConversationOwner):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the conversation owner can add members"})
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create join request"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add members"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, joinRequestResponse{Request: *req})
+	if len(added) > 0 {
+		for _, userID := range added {
+			h.hub.NotifyMembership(conversationID, userID, "added")
+		}
+		names, err := h.repo.GetUserNames(ctx, append([]int64{claims.UserID}, added...))
+		if err != nil {
+			log.Printf("resolve member names failed: %v", err)
+		} else {
+			addedNames := make([]string, 0, len(added))
+			for _, userID := range added {
+				addedNames = append(addedNames, names[userID])
+			}
+			h.hub.PostSystemMessage(ctx, conversationID, claims.UserID, fmt.Sprintf("%s added %s", names[claims.UserID], joinNames(addedNames)))
+		}
+	}
+
+	convo, err := h.repo.GetConversation(ctx, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation"})
+		return
+	}
+	summary, err := h.repo.HydrateConversationSummary(ctx, *convo, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation details"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversation": summary,
+		"addedIds":     added,
+	})
 }
 
-// approveJoin allows the event host to approve a user's pending join request.
-// On success, the user is added to the event's conversation and the hub is
-// notified so any active sockets for that user start receiving events.
+// renameConversation lets the owner of a non-event conversation change its
+// title and posts a system message about it. Event chats are renamed by
+// editing the event.
 //
 // Responses:
-//  - 200 with the approved request and `conversationId`
+//  - 200 with the updated ConversationSummary
 //  - 401 if the caller has no session
-//  - 400 for invalid path params
-//  - 403 if the caller is not the event host
-//  - 404 if the event or pending request is not found
-//  - 409 if the user is already a member
+//  - 400 for invalid JSON, conversation id, or an event conversation
+//  - 403 if the caller is not the owner
+//  - 404 if the conversation does not exist
 //  - 500 for repository/database failures
-func (h *ChatHTTPHandler) approveJoin(c *gin.Context) {
+func (h *ChatHTTPHandler) renameConversation(c *gin.Context) {
 	claims, ok := sessionFromContext(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
 		return
 	}
 
-	eventIDParam := c.Param("id")
-	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
-	if err != nil || eventID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
 		return
 	}
 
-	userIDParam := c.Param("userId")
-	userID, err := strconv.ParseInt(userIDParam, 10, 64)
-	if err != nil || userID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+	var payload renameConversationRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	title := strings.TrimSpace(payload.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
-	req, err := h.repo.ApproveJoinRequest(ctx, eventID, userID, claims.UserID)
-	if err != nil {
+	if err := h.repo.RenameConversation(ctx, conversationID, claims.UserID, title); err != nil {
 		switch {
-		case errors.Is(err, ErrEventNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
-		case errors.Is(err, ErrNotEventHost):
-			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can approve requests"})
-		case errors.Is(err, ErrJoinRequestNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "pending request not found"})
-		case errors.Is(err, ErrAlreadyConversationMember):
-			c.JSON(http.StatusConflict, gin.H{"error": "user already a member"})
+		case errors.Is(err, ErrConversationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+		case errors.Is(err, ErrEventConversation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "event chats are renamed by editing the event"})
+		case errors.Is(err, ErrNotConversationMember), errors.Is(err, ErrNotConversationOwner):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the conversation owner can rename it"})
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to approve join request"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rename conversation"})
 		}
 		return
 	}
 
-	convo, err := h.repo.GetConversationByEventID(ctx, eventID)
+	if names, err := h.repo.GetUserNames(ctx, []int64{claims.UserID}); err == nil {
+		h.hub.PostSystemMessage(ctx, conversationID, claims.UserID, fmt.Sprintf("%s renamed the chat to \"%s\"", names[claims.UserID], title))
+	}
+
+	convo, err := h.repo.GetConversation(ctx, conversationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation"})
 		return
 	}
+	summary, err := h.repo.HydrateConversationSummary(ctx, *convo, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation details"})
+		return
+	}
 
-	h.hub.NotifyMembership(convo.ID, userID, "added")
-
-	c.JSON(http.StatusOK, gin.H{
-		"request":        req,
-		"conversationId": convo.ID,
-	})
+	c.JSON(http.StatusOK, createConversationResponse{Conversation: summary})
 }
 
-// denyJoin allows the event host to deny a user's pending join request.
-// This does not alter conversation membership and simply records the denial.
+// markRead advances the caller's read cursor to `last_read_message_id`.
+// Cursors only move forward, so an older ID is accepted but has no effect.
 //
 // Responses:
-//  - 200 with the updated (denied) request
+//  - 200 with `conversationId` and the remaining `unreadCount`
 //  - 401 if the caller has no session
-//  - 400 for invalid path params
-//  - 403 if the caller is not the event host
-//  - 404 if the event or pending request is not found
+//  - 400 for invalid JSON, conversation id, or a message from another conversation
+//  - 403 if the caller is not a member
 //  - 500 for repository/database failures
-func (h *ChatHTTPHandler) denyJoin(c *gin.Context) {
+func (h *ChatHTTPHandler) markRead(c *gin.Context) {
 	claims, ok := sessionFromContext(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
 		return
 	}
 
-	eventIDParam := c.Param("id")
-	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
-	if err != nil || eventID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
 		return
 	}
 
-	userIDParam := c.Param("userId")
-	userID, err := strconv.ParseInt(userIDParam, 10, 64)
-	if err != nil || userID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+	var payload markReadRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
-	req, err := h.repo.DenyJoinRequest(ctx, eventID, userID, claims.UserID)
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	unread, err := h.repo.MarkConversationRead(ctx, conversationID, claims.UserID, payload.LastReadMessageID)
 	if err != nil {
-		switch {
-		case errors.Is(err, ErrEventNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
-		case errors.Is(err, ErrNotEventHost):
-			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can deny requests"})
-		case errors.Is(err, ErrJoinRequestNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "pending request not found"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to deny join request"})
+		if errors.Is(err, ErrMessageNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "message not found in conversation"})
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mark conversation read"})
 		return
 	}
 
-	c.JSON(http.StatusOK, joinRequestResponse{Request: *req})
+	c.JSON(http.StatusOK, gin.H{"conversationId": conversationID, "unreadCount": unread})
 }
 
-// removeMember removes a user from an event's group conversation. Only the
-// event host can remove others; any user can remove themselves (leave). The
-// hub is notified so live sockets stop receiving that conversation's events.
+// archiveConversation hides a conversation from the caller's default list.
+// It comes back on its own when someone else sends a message.
 //
 // Responses:
 //  - 204 on success
 //  - 401 if the caller has no session
-//  - 400 for invalid path params or trying to remove the host
-//  - 403 if not authorized to update membership
-//  - 404 if the event or target membership is not found
+//  - 400 for invalid conversation id
+//  - 403 if the caller is not a member
 //  - 500 for repository/database failures
-func (h *ChatHTTPHandler) removeMember(c *gin.Context) {
+func (h *ChatHTTPHandler) archiveConversation(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// unarchiveConversation returns an archived conversation to the caller's
+// default list; same responses as archiveConversation.
+func (h *ChatHTTPHandler) unarchiveConversation(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *ChatHTTPHandler) setArchived(c *gin.Context, archived bool) {
 	claims, ok := sessionFromContext(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
 		return
 	}
 
-	eventIDParam := c.Param("id")
-	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
-	if err != nil || eventID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
 		return
 	}
 
-	userIDParam := c.Param("userId")
-	userID, err := strconv.ParseInt(userIDParam, 10, 64)
-	if err != nil || userID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	if archived {
+		err = h.repo.ArchiveConversation(ctx, conversationID, claims.UserID)
+	} else {
+		err = h.repo.UnarchiveConversation(ctx, conversationID, claims.UserID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update conversation"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// muteConversation silences a conversation for the caller until `until`
+// (RFC 3339), or indefinitely if omitted. Muted chats are left out of the
+// unread badge total and don't send push notifications.
+//
+// Responses:
+//  - 200 with `mutedUntil`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON, conversation id, or an `until` in the past
+//  - 403 if the caller is not a member
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) muteConversation(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var payload MuteConversationParams
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if payload.Until != nil && !payload.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	mutedUntil, err := h.repo.MuteConversation(ctx, conversationID, claims.UserID, payload.Until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mute conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conversationId": conversationID, "mutedUntil": mutedUntil})
+}
+
+// unmuteConversation clears the caller's mute on a conversation.
+//
+// Responses:
+//  - 204 on success (also when it wasn't muted)
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation id
+//  - 403 if the caller is not a member
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) unmuteConversation(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	if err := h.repo.UnmuteConversation(ctx, conversationID, claims.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unmute conversation"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// setSlowMode limits each member of an event chat to one message every
+// `seconds` (up to an hour); 0 turns it off. Hosts and co-hosts are exempt.
+// The chat gets a system message announcing the change.
+//
+// Responses:
+//  - 200 with the updated conversation
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON, conversation id, seconds, or a non-event chat
+//  - 403 if the caller is not the event's host or a co-host
+//  - 404 if the conversation or its event doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) setSlowMode(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var payload SlowModeParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	seconds := *payload.Seconds
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.SetSlowMode(ctx, conversationID, claims.UserID, seconds); err != nil {
+		switch {
+		case errors.Is(err, ErrConversationNotFound), errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+		case errors.Is(err, ErrNotEventConversation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "slow mode is only available in event chats"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the host or a co-host can change slow mode"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update slow mode"})
+		}
+		return
+	}
+
+	if names, err := h.repo.GetUserNames(ctx, []int64{claims.UserID}); err == nil {
+		body := fmt.Sprintf("%s turned off slow mode", names[claims.UserID])
+		if seconds > 0 {
+			body = fmt.Sprintf("%s turned on slow mode: one message every %d seconds", names[claims.UserID], seconds)
+		}
+		h.hub.PostSystemMessage(ctx, conversationID, claims.UserID, body)
+	}
+
+	convo, err := h.repo.GetConversation(ctx, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation"})
+		return
+	}
+	summary, err := h.repo.HydrateConversationSummary(ctx, *convo, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation details"})
+		return
+	}
+
+	c.JSON(http.StatusOK, createConversationResponse{Conversation: summary})
+}
+
+// unreadTotals backs the app's unread badge: total unread messages and the
+// number of conversations they're in, leaving out muted conversations.
+func (h *ChatHTTPHandler) unreadTotals(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	messages, conversations, err := h.repo.CountUnread(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count unread messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unreadMessages": messages, "unreadConversations": conversations})
+}
+
+// requireMember writes a 403/500 and returns false unless userID belongs to
+// the conversation.
+func (h *ChatHTTPHandler) requireMember(c *gin.Context, ctx context.Context, conversationID, userID int64) bool {
+	isMember, err := h.repo.IsConversationMember(ctx, conversationID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify membership"})
+		return false
+	}
+	if !isMember {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not a conversation member"})
+		return false
+	}
+	return true
+}
+
+// listConversations returns the conversations visible to the current user,
+// most recently active first, enriched with participants, last message
+// preview, unread counts, and optional event metadata. Archived
+// conversations are left out unless `archived=true`, which lists only those.
+//
+// Query params: `limit` (1-100; everything when omitted) and `cursor` (the
+// previous page's `next_cursor`).
+// Responses:
+//  - 200 with a list of ConversationSummary items and `next_cursor` when more follow
+//  - 401 if the caller has no session
+//  - 400 for an invalid limit or cursor
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listConversations(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	opts := ConversationListOptions{ArchivedOnly: c.Query("archived") == "true", Cursor: c.Query("cursor")}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxConversationPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		opts.Limit = limit
+	}
+	conversations, next, err := h.repo.ListConversations(ctx, claims.UserID, opts)
+	if err != nil {
+		if errors.Is(err, ErrInvalidConversationCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, listConversationResponse{Conversations: conversations, NextCursor: next})
+}
+
+// searchConversations finds the caller's conversations, archived ones
+// included, whose title, other participants' names or linked event title
+// contain `q` (case-insensitive). Results are ordered and paged like
+// listConversations.
+//
+// Query params: `q` (required), `limit` (1-100, default 20) and `cursor`.
+// Responses:
+//  - 200 with a list of ConversationSummary items and `next_cursor` when more follow
+//  - 401 if the caller has no session
+//  - 400 for a missing or too long query, or an invalid limit or cursor
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) searchConversations(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	term := strings.TrimSpace(c.Query("q"))
+	if term == "" || utf8.RuneCountInString(term) > maxConversationSearchLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be 1-100 characters"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxConversationPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	opts := ConversationListOptions{IncludeArchived: true, Search: term, Limit: limit, Cursor: c.Query("cursor")}
+	conversations, next, err := h.repo.ListConversations(ctx, claims.UserID, opts)
+	if err != nil {
+		if errors.Is(err, ErrInvalidConversationCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search conversations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, listConversationResponse{Conversations: conversations, NextCursor: next})
+}
+
+// getConversation returns one conversation the caller belongs to, including
+// each member's role, joined_at and last_read_message_id.
+//
+// Responses:
+//  - 200 with `conversation`
+//  - 401 if the caller has no session
+//  - 400 for an invalid conversation id
+//  - 403 if the caller is not a member
+//  - 404 if the conversation does not exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) getConversation(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	detail, err := h.repo.GetConversationDetail(ctx, conversationID, claims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conversation": detail})
+}
+
+// listMessages returns the most recent messages for a conversation the user
+// can access. It validates membership, supports basic limit/offset paging, and
+// advances the caller's read cursor to the newest returned message unless
+// `mark_read=false` (for prefetching; use POST /read afterwards).
+//
+// Query params: `limit` (default 20), `offset` (default 0), `mark_read` (default true).
+// Responses:
+//  - 200 with a chronologically ordered message list
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation id
+//  - 403 if the user is not a member of the conversation
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listMessages(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationIDParam := c.Param("id")
+	conversationID, err := strconv.ParseInt(conversationIDParam, 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	limitParam := c.DefaultQuery("limit", "20")
+	offsetParam := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(offsetParam)
+	if err != nil {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	isMember, err := h.repo.IsConversationMember(ctx, conversationID, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify membership"})
+		return
+	}
+	if !isMember {
+		c.JSON(http.StatusForbidden, gin.H{"error": "conversation access denied"})
+		return
+	}
+
+	messages, err := h.repo.ListMessages(ctx, conversationID, claims.UserID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load messages"})
+		return
+	}
+
+	if len(messages) > 0 && c.Query("mark_read") != "false" {
+		latest := messages[0]
+		if err := h.repo.UpdateReadState(ctx, conversationID, claims.UserID, latest.ID); err != nil {
+			log.Printf("update read state failed: %v", err)
+		}
+	}
+
+	payloads := make([]messagePayload, 0, len(messages))
+	for _, msg := range messages {
+		payloads = append(payloads, newMessagePayload(msg))
+	}
+
+	c.JSON(http.StatusOK, listMessagesResponse{Messages: payloads})
+}
+
+// requestJoin creates a pending request for the current user to join an event's
+// group conversation. The event must exist and have a chat conversation. If the
+// user is already a member or a request is pending, a conflict is returned.
+// When the event is at capacity the request is created as `waitlisted`.
+// Requesters outside the event's gender/age filters get `ineligible_reason` on
+// the request, or a 403 with code `ineligible` when the event is strict. The
+// optional body `{"note": "..."}` (up to 280 characters) is shown to the host.
+//
+// Responses:
+//  - 201 with the created join request
+//  - 401 if the caller has no session
+//  - 400 for invalid event id or note
+//  - 403 with `code: ineligible` and `reason` on a strict event
+//  - 403 with `code: banned` if the host banned the caller
+//  - 404 if the event or its conversation is missing, or the event is private
+//  - 409 if a request already exists, the user is already a member, or the
+//    event was cancelled
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) requestJoin(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload JoinRequestParams
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	req, err := h.repo.CreateJoinRequest(ctx, eventID, claims.UserID, strings.TrimSpace(payload.Note))
+	if err != nil {
+		var ineligible *IneligibleError
+		switch {
+		case errors.As(err, &ineligible):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":  "you don't meet this event's " + ineligible.Reason + " requirement",
+				"code":   "ineligible",
+				"reason": ineligible.Reason,
+			})
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrBannedFromEvent):
+			c.JSON(http.StatusForbidden, gin.H{"error": "you can't join this event", "code": "banned"})
+		case errors.Is(err, ErrAlreadyConversationMember):
+			c.JSON(http.StatusConflict, gin.H{"error": "already a member of this chat"})
+		case errors.Is(err, ErrJoinRequestExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "a pending request already exists"})
+		case errors.Is(err, ErrEventCancelled):
+			c.JSON(http.StatusConflict, gin.H{"error": "event was cancelled"})
+		case errors.Is(err, ErrConversationNotFound):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "chat conversation missing for event"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create join request"})
+		}
+		return
+	}
+
+	h.notifyHostOfRequest(ctx, *req)
+
+	c.JSON(http.StatusCreated, joinRequestResponse{Request: *req})
+}
+
+// notifyHostOfRequest tells the event host about a new join request along
+// with the requester's profile. The request is already stored, so lookup
+// failures are only logged.
+func (h *ChatHTTPHandler) notifyHostOfRequest(ctx context.Context, req ConversationJoinRequest) {
+	event, err := h.repo.GetEventByID(ctx, req.EventID)
+	if err != nil {
+		log.Printf("join request notify: load event %d: %v", req.EventID, err)
+		return
+	}
+	convo, err := h.repo.GetConversationByEventID(ctx, req.EventID)
+	if err != nil {
+		log.Printf("join request notify: load conversation for event %d: %v", req.EventID, err)
+		return
+	}
+	profile, err := h.repo.GetUserProfile(ctx, req.UserID)
+	if err != nil {
+		log.Printf("join request notify: load requester %d: %v", req.UserID, err)
+		return
+	}
+
+	requester := joinRequester{UserID: profile.ID, Name: profile.Name, Gender: profile.Gender}
+	if age, ok := profile.Age(time.Now()); ok {
+		requester.Age = &age
+	}
+	h.hub.NotifyNewJoinRequest(event.UserID, event, convo.ID, req, requester)
+}
+
+// approveJoin allows the event host to approve a user's pending join request.
+// On success, the user is added to the event's conversation and the hub is
+// notified so any active sockets for that user start receiving events and get
+// a `join_request:decided` event.
+//
+// Responses:
+//  - 200 with the approved request and `conversationId`
+//  - 401 if the caller has no session
+//  - 400 for invalid path params
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event or pending request is not found
+//  - 409 if the user is already a member or the event is full
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) approveJoin(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	userIDParam := c.Param("userId")
+	userID, err := strconv.ParseInt(userIDParam, 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	req, err := h.repo.ApproveJoinRequest(ctx, eventID, userID, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can approve requests"})
+		case errors.Is(err, ErrJoinRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "pending request not found"})
+		case errors.Is(err, ErrAlreadyConversationMember):
+			c.JSON(http.StatusConflict, gin.H{"error": "user already a member"})
+		case errors.Is(err, ErrEventFull):
+			c.JSON(http.StatusConflict, gin.H{"error": "event is full"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to approve join request"})
+		}
+		return
+	}
+
+	convo, err := h.repo.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation"})
+		return
+	}
+
+	h.hub.NotifyMembership(convo.ID, userID, "added")
+	h.hub.Announce(ctx, convo.ID, claims.UserID, "%s joined the chat", userID)
+	h.hub.WelcomeMember(ctx, eventID, convo.ID, userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"request":        req,
+		"conversationId": convo.ID,
+	})
+}
+
+// denyJoin allows the event host to deny a user's pending join request.
+// This does not alter conversation membership; it records the denial and
+// sends the requester a `join_request:decided` event.
+//
+// Responses:
+//  - 200 with the updated (denied) request
+//  - 401 if the caller has no session
+//  - 400 for invalid path params
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event or pending request is not found
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) denyJoin(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	userIDParam := c.Param("userId")
+	userID, err := strconv.ParseInt(userIDParam, 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	req, err := h.repo.DenyJoinRequest(ctx, eventID, userID, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can deny requests"})
+		case errors.Is(err, ErrJoinRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "pending request not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to deny join request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, joinRequestResponse{Request: *req})
+}
+
+// decideJoins applies several approve/deny decisions for an event in one
+// transaction, so hosts of busy events can clear their queue in one call.
+// Decisions run in order; one that cannot be applied (no pending request,
+// already a member, event full) is reported in its result without stopping
+// the rest. Approved users are added to the room and announced as usual.
+//
+// Responses:
+//  - 200 with `results` in request order and `conversationId`
+//  - 401 if the caller has no session
+//  - 400 for an invalid event id or body
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures (nothing is applied)
+func (h *ChatHTTPHandler) decideJoins(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var params JoinDecisionsParams
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	results, convoID, err := h.repo.DecideJoinRequests(ctx, eventID, claims.UserID, params.Decisions)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can decide requests"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to apply join decisions"})
+		}
+		return
+	}
+
+	for _, result := range results {
+		if result.OK && result.Action == "approve" {
+			h.hub.NotifyMembership(convoID, result.UserID, "added")
+			h.hub.Announce(ctx, convoID, claims.UserID, "%s joined the chat", result.UserID)
+			h.hub.WelcomeMember(ctx, eventID, convoID, result.UserID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":        results,
+		"conversationId": convoID,
+	})
+}
+
+// removeMember removes a user from an event's group conversation. The event
+// host can remove others, co-hosts can remove regular members, and any user
+// can remove themselves (leave). The hub is notified so live sockets stop
+// receiving that conversation's events. If the freed spot promotes someone off
+// the waitlist, they are added to the room and sent a `join_request:promoted`
+// event. With `?ban=true` the removed user also can't request to join again
+// until unbanned.
+//
+// Responses:
+//  - 204 on success
+//  - 401 if the caller has no session
+//  - 400 for invalid path params, trying to remove the host, or banning yourself
+//  - 403 if not authorized to update membership
+//  - 404 if the event or target membership is not found
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) removeMember(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	userIDParam := c.Param("userId")
+	userID, err := strconv.ParseInt(userIDParam, 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
 		return
 	}
 
@@ -857,13 +2414,17 @@ func (h *ChatHTTPHandler) removeMember(c *gin.Context) {
 		return
 	}
 
-	if claims.UserID != event.UserID && claims.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized to update membership"})
+	ban := c.Query("ban") == "true"
+	if ban && userID == claims.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot ban yourself"})
 		return
 	}
 
-	if err := h.repo.RemoveEventMember(ctx, eventID, userID); err != nil {
+	promoted, err := h.repo.RemoveEventMember(ctx, eventID, userID, claims.UserID, ban)
+	if err != nil {
 		switch {
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized to update membership"})
 		case errors.Is(err, ErrCannotRemoveHost):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "event host cannot leave the event chat"})
 		case errors.Is(err, ErrNotConversationMember):
@@ -879,12 +2440,359 @@ func (h *ChatHTTPHandler) removeMember(c *gin.Context) {
 	convo, err := h.repo.GetConversationByEventID(ctx, eventID)
 	if err == nil {
 		h.hub.NotifyMembership(convo.ID, userID, "removed")
+		if claims.UserID == userID {
+			h.hub.Announce(ctx, convo.ID, userID, "%s left the chat", userID)
+		} else if ban {
+			h.hub.Announce(ctx, convo.ID, claims.UserID, "%s removed and banned %s", claims.UserID, userID)
+		} else {
+			h.hub.Announce(ctx, convo.ID, claims.UserID, "%s removed %s", claims.UserID, userID)
+		}
+		if promoted != nil {
+			h.hub.NotifyMembership(convo.ID, promoted.UserID, "added")
+			h.hub.NotifyJoinRequest(promoted.UserID, "join_request:promoted", convo.ID, *promoted)
+			h.hub.Announce(ctx, convo.ID, event.UserID, "%s joined from the waitlist", promoted.UserID)
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// listBans returns the users banned from an event's chat.
+//
+// Responses:
+//  - 200 with `bans`, most recent first
+//  - 401 if the caller has no session
+//  - 400 for an invalid event id
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listBans(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bans, err := h.repo.ListEventBans(ctx, eventID, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can view bans"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load bans"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+// unbanUser lifts a ban so the user can request to join the event again.
+//
+// Responses:
+//  - 204 on success, including when the user wasn't banned
+//  - 401 if the caller has no session
+//  - 400 for invalid path params
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) unbanUser(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	userIDParam := c.Param("userId")
+	userID, err := strconv.ParseInt(userIDParam, 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.UnbanEventUser(ctx, eventID, claims.UserID, userID); err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can lift bans"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to lift ban"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// addCoHost lets the event host make a chat member a co-host. Co-hosts can
+// review, approve and deny join requests, promote waitlisted users, and
+// remove regular members.
+//
+// Responses:
+//  - 204 on success
+//  - 401 if the caller has no session
+//  - 400 for invalid path params or targeting the host
+//  - 403 if the caller is not the event host
+//  - 404 if the event is not found or the user is not in its chat
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) addCoHost(c *gin.Context) {
+	h.setCoHost(c, true)
+}
+
+// removeCoHost demotes a co-host back to a regular member. Responses match
+// addCoHost.
+func (h *ChatHTTPHandler) removeCoHost(c *gin.Context) {
+	h.setCoHost(c, false)
+}
+
+func (h *ChatHTTPHandler) setCoHost(c *gin.Context, coHost bool) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	userIDParam := c.Param("userId")
+	userID, err := strconv.ParseInt(userIDParam, 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	convoID, err := h.repo.SetEventCoHost(ctx, eventID, claims.UserID, userID, coHost)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can manage co-hosts"})
+		case errors.Is(err, ErrCannotRemoveHost):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "the host cannot be a co-host"})
+		case errors.Is(err, ErrNotConversationMember):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user is not part of this chat"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update co-hosts"})
+		}
+		return
+	}
+
+	if coHost {
+		h.hub.Announce(ctx, convoID, claims.UserID, "%s made %s a co-host", claims.UserID, userID)
+	} else {
+		h.hub.Announce(ctx, convoID, claims.UserID, "%s is no longer a co-host", userID)
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// listJoinRequests returns the host's queue of join requests for an event.
+//
+// Query params: `status` (`pending` by default, or `waitlisted`).
+// Responses:
+//  - 200 with requests ordered oldest first
+//  - 401 if the caller has no session
+//  - 400 for invalid event id or status
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listJoinRequests(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	status := c.DefaultQuery("status", "pending")
+	if status != "pending" && status != "waitlisted" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending or waitlisted"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	requests, err := h.repo.ListJoinRequests(ctx, eventID, claims.UserID, status)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can view requests"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load join requests"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, listJoinRequestsResponse{Requests: requests})
+}
+
+// promoteWaitlisted lets the host admit a waitlisted user into the event chat
+// once a spot is free. The promoted user's sockets join the room and receive a
+// `join_request:promoted` event.
+//
+// Responses:
+//  - 200 with the approved request and `conversationId`
+//  - 401 if the caller has no session
+//  - 400 for invalid path params
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event or waitlisted request is not found
+//  - 409 if the event is still full
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) promoteWaitlisted(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	userIDParam := c.Param("userId")
+	userID, err := strconv.ParseInt(userIDParam, 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	req, err := h.repo.PromoteWaitlistedUser(ctx, eventID, userID, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrConversationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "chat conversation missing for event"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can promote waitlisted users"})
+		case errors.Is(err, ErrJoinRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "waitlisted request not found"})
+		case errors.Is(err, ErrEventFull):
+			c.JSON(http.StatusConflict, gin.H{"error": "event is full"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to promote waitlisted user"})
+		}
+		return
+	}
+
+	convo, err := h.repo.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load conversation"})
+		return
+	}
+
+	h.hub.NotifyMembership(convo.ID, userID, "added")
+	h.hub.NotifyJoinRequest(userID, "join_request:promoted", convo.ID, *req)
+	h.hub.Announce(ctx, convo.ID, claims.UserID, "%s joined from the waitlist", userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"request":        req,
+		"conversationId": convo.ID,
+	})
+}
+// listEventMembers returns who is going to an event: every member of the
+// event's group chat with their name, role, and join time. Only the host and
+// existing members can see the roster.
+//
+// Responses:
+//  - 200 with the member list ordered by join time
+//  - 401 if the caller has no session
+//  - 400 for invalid event id
+//  - 403 if the caller is not a member of the event chat
+//  - 404 if the event or its conversation is missing
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listEventMembers(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	members, err := h.repo.ListEventMembers(ctx, eventID, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrConversationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "chat conversation missing for event"})
+		case errors.Is(err, ErrNotConversationMember):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only event members can view attendees"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load event members"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, eventMembersResponse{Members: members})
+}
+
 // containsInt64 reports whether target is present in values. Small helper used
 // when constructing membership lists.
+// joinNames renders names as "A", "A and B", or "A, B and C".
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
 func containsInt64(values []int64, target int64) bool {
 	for _, v := range values {
 		if v == target {
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func chatRoutes(store Store) func(*gin.RouterGroup) {
+	hub := newTestHub(store)
+	return func(group *gin.RouterGroup) {
+		RegisterChatRoutes(group, store, hub)
+	}
+}
+
+// membership answers IsConversationMember with member, or err.
+func membership(member bool, err error) func(context.Context, int64, int64) (bool, error) {
+	return func(context.Context, int64, int64) (bool, error) { return member, err }
+}
+
+func TestGetConversationStatuses(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		member    bool
+		memberErr error
+		detailErr error
+		want      int
+	}{
+		{"member", "/api/conversations/10", true, nil, nil, http.StatusOK},
+		{"invalid id", "/api/conversations/abc", true, nil, nil, http.StatusBadRequest},
+		{"not a member", "/api/conversations/10", false, nil, nil, http.StatusForbidden},
+		{"membership failure", "/api/conversations/10", false, errStoreDown, nil, http.StatusInternalServerError},
+		// A member whose chat row has gone, as after a purge racing the request.
+		{"missing conversation", "/api/conversations/10", true, nil, ErrConversationNotFound, http.StatusNotFound},
+		{"detail failure", "/api/conversations/10", true, nil, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.IsConversationMemberFunc = membership(tt.member, tt.memberErr)
+			store.GetConversationDetailFunc = func(_ context.Context, conversationID, _ int64) (*ConversationDetail, error) {
+				if tt.detailErr != nil {
+					return nil, tt.detailErr
+				}
+				return &ConversationDetail{ConversationSummary: ConversationSummary{Conversation: Conversation{ID: conversationID}}}, nil
+			}
+			rec := serveAs(t, chatRoutes(store), testMemberID, http.MethodGet, tt.path, "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestListMessagesStatuses(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		member    bool
+		memberErr error
+		listErr   error
+		want      int
+		wantRead  bool
+	}{
+		{"member", "/api/conversations/10/messages", true, nil, nil, http.StatusOK, true},
+		{"prefetch", "/api/conversations/10/messages?mark_read=false", true, nil, nil, http.StatusOK, false},
+		{"invalid id", "/api/conversations/0/messages", true, nil, nil, http.StatusBadRequest, false},
+		{"not a member", "/api/conversations/10/messages", false, nil, nil, http.StatusForbidden, false},
+		{"membership failure", "/api/conversations/10/messages", false, errStoreDown, nil, http.StatusInternalServerError, false},
+		{"message failure", "/api/conversations/10/messages", true, nil, errStoreDown, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.IsConversationMemberFunc = membership(tt.member, tt.memberErr)
+			store.ListMessagesFunc = func(_ context.Context, conversationID, _ int64, _, _ int) ([]Message, error) {
+				if tt.listErr != nil {
+					return nil, tt.listErr
+				}
+				return []Message{
+					{ID: 2, ConversationID: conversationID, SenderID: testMemberID, Body: "See you there"},
+					{ID: 1, ConversationID: conversationID, SenderID: testHostID, Body: "Who's in?"},
+				}, nil
+			}
+			store.UpdateReadStateFunc = func(context.Context, int64, int64, int64) error { return nil }
+
+			rec := serveAs(t, chatRoutes(store), testHostID, http.MethodGet, tt.path, "")
+			assertStatus(t, rec, tt.want)
+
+			reads := store.UpdateReadStateCalls()
+			if !tt.wantRead {
+				if len(reads) != 0 {
+					t.Fatalf("read state updated %d times, want none", len(reads))
+				}
+				return
+			}
+			if len(reads) != 1 || reads[0].LastReadMessageID != 2 {
+				t.Fatalf("UpdateReadState calls = %+v, want one up to message 2", reads)
+			}
+		})
+	}
+}
+
+func TestRenameConversationStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		err  error
+		want int
+	}{
+		{"renamed", "/api/conversations/10/title", `{"title":"Run club"}`, nil, http.StatusOK},
+		{"invalid id", "/api/conversations/abc/title", `{"title":"Run club"}`, nil, http.StatusBadRequest},
+		{"bad json", "/api/conversations/10/title", `{"title":`, nil, http.StatusBadRequest},
+		{"blank title", "/api/conversations/10/title", `{"title":"   "}`, nil, http.StatusBadRequest},
+		{"event chat", "/api/conversations/1/title", `{"title":"Run club"}`, ErrEventConversation, http.StatusBadRequest},
+		{"member, not owner", "/api/conversations/10/title", `{"title":"Run club"}`, ErrNotConversationOwner, http.StatusForbidden},
+		{"not a member", "/api/conversations/10/title", `{"title":"Run club"}`, ErrNotConversationMember, http.StatusForbidden},
+		{"missing conversation", "/api/conversations/10/title", `{"title":"Run club"}`, ErrConversationNotFound, http.StatusNotFound},
+		{"store failure", "/api/conversations/10/title", `{"title":"Run club"}`, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.RenameConversationFunc = func(context.Context, int64, int64, string) error { return tt.err }
+			store.GetConversationFunc = func(_ context.Context, conversationID int64) (*Conversation, error) {
+				title := "Run club"
+				return &Conversation{ID: conversationID, CreatedBy: testHostID, Title: &title}, nil
+			}
+			store.HydrateConversationSummaryFunc = func(_ context.Context, convo Conversation, _ int64) (ConversationSummary, error) {
+				return ConversationSummary{Conversation: convo}, nil
+			}
+
+			rec := serveAs(t, chatRoutes(store), testHostID, http.MethodPut, tt.path, tt.body)
+			assertStatus(t, rec, tt.want)
+			if tt.want != http.StatusOK {
+				return
+			}
+			renames := store.RenameConversationCalls()
+			if len(renames) != 1 || renames[0].Title != "Run club" {
+				t.Fatalf("RenameConversation calls = %+v", renames)
+			}
+			if got := len(store.CreateSystemMessageCalls()); got != 1 {
+				t.Fatalf("system messages = %d, want 1", got)
+			}
+		})
+	}
+}
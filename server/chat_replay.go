@@ -0,0 +1,245 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// replayBufferSize caps unacknowledged frames kept per client stream; older
+	// frames are dropped and a later resume reports a gap.
+	replayBufferSize = 256
+	// replayRetention is how long a stream outlives its socket so a reconnect
+	// can resume it.
+	replayRetention = 10 * time.Minute
+	// replayWriteTimeout bounds how long a replay waits on a slow socket.
+	replayWriteTimeout = 10 * time.Second
+	// maxClientIDLength keeps client-chosen stream keys reasonable.
+	maxClientIDLength = 64
+)
+
+// replayStream is the sequenced outbox for one (user, clientId) pair. Sockets
+// that connect with a clientId get every hub frame stamped with a `seq`; the
+// frames stay buffered until acked, including while the socket is away, so a
+// reconnecting client can `resume` instead of silently missing broadcasts.
+// Streams are owned by the hub goroutine.
+type replayStream struct {
+	userID        int64
+	clientID      string
+	client        *ChatClient        // nil while parked
+	subscriptions map[int64]struct{} // rooms to keep recording while parked
+	seq           int64              // last assigned sequence number
+	entries       []replayEntry      // unacked frames, oldest first
+	lostThrough   int64              // highest seq dropped before it was acked
+	parkedAt      time.Time
+}
+
+type replayEntry struct {
+	seq     int64
+	payload []byte
+}
+
+// streamControl carries `ack` and `resume` frames from a read pump to the hub.
+type streamControl struct {
+	client *ChatClient
+	kind   string
+	seq    int64
+}
+
+// record stamps payload with the next sequence number and buffers it.
+func (s *replayStream) record(payload []byte) []byte {
+	s.seq++
+	frame := withSeq(payload, s.seq)
+	if len(s.entries) == replayBufferSize {
+		s.lostThrough = s.entries[0].seq
+		s.entries = s.entries[1:]
+	}
+	s.entries = append(s.entries, replayEntry{seq: s.seq, payload: frame})
+	return frame
+}
+
+// ack drops every buffered frame up to and including seq.
+func (s *replayStream) ack(seq int64) {
+	i := 0
+	for i < len(s.entries) && s.entries[i].seq <= seq {
+		i++
+	}
+	s.entries = s.entries[i:]
+}
+
+// since returns the frames after seq, or ok=false when some of them are gone
+// (buffer overflow, or a stream that restarted since the client last saw it).
+func (s *replayStream) since(seq int64) ([][]byte, bool) {
+	if seq < s.lostThrough || seq > s.seq {
+		return nil, false
+	}
+	var frames [][]byte
+	for _, entry := range s.entries {
+		if entry.seq > seq {
+			frames = append(frames, entry.payload)
+		}
+	}
+	return frames, true
+}
+
+// withSeq splices `"seq":N` into a JSON object payload.
+func withSeq(payload []byte, seq int64) []byte {
+	if len(payload) < 2 || payload[0] != '{' {
+		return payload
+	}
+	frame := make([]byte, 0, len(payload)+24)
+	frame = append(frame, `{"seq":`...)
+	frame = strconv.AppendInt(frame, seq, 10)
+	if payload[1] != '}' {
+		frame = append(frame, ',')
+	}
+	return append(frame, payload[1:]...)
+}
+
+// attachStream gives a freshly registered client its stream, resuming a parked
+// one for the same clientId when it still exists.
+func (h *ChatHub) attachStream(client *ChatClient) {
+	if client.clientID == "" {
+		return
+	}
+	streams, ok := h.streams[client.userID]
+	if !ok {
+		streams = make(map[string]*replayStream)
+		h.streams[client.userID] = streams
+	}
+	stream, ok := streams[client.clientID]
+	if !ok {
+		stream = &replayStream{userID: client.userID, clientID: client.clientID}
+		streams[client.clientID] = stream
+	}
+	if stream.client != nil {
+		// The same device reconnected before its old socket was noticed as gone;
+		// the old socket keeps working but stops sequencing.
+		stream.client.stream = nil
+	} else {
+		h.unparkStream(stream)
+	}
+	stream.client = client
+	client.stream = stream
+}
+
+// parkStream detaches a departing client's stream but keeps recording the
+// rooms it was in until it is resumed or expires.
+func (h *ChatHub) parkStream(client *ChatClient) {
+	stream := client.stream
+	if stream == nil || stream.client != client {
+		return
+	}
+	client.stream = nil
+	stream.client = nil
+	stream.parkedAt = time.Now()
+	stream.subscriptions = make(map[int64]struct{}, len(client.subscriptions))
+	for conversationID := range client.subscriptions {
+		h.parkInRoom(stream, conversationID)
+	}
+}
+
+func (h *ChatHub) parkInRoom(stream *replayStream, conversationID int64) {
+	stream.subscriptions[conversationID] = struct{}{}
+	if _, ok := h.parked[conversationID]; !ok {
+		h.parked[conversationID] = make(map[*replayStream]struct{})
+	}
+	h.parked[conversationID][stream] = struct{}{}
+}
+
+func (h *ChatHub) unparkFromRoom(stream *replayStream, conversationID int64) {
+	delete(stream.subscriptions, conversationID)
+	if streams, ok := h.parked[conversationID]; ok {
+		delete(streams, stream)
+		if len(streams) == 0 {
+			delete(h.parked, conversationID)
+		}
+	}
+}
+
+func (h *ChatHub) unparkStream(stream *replayStream) {
+	for conversationID := range stream.subscriptions {
+		h.unparkFromRoom(stream, conversationID)
+	}
+	stream.subscriptions = nil
+}
+
+// dropStreams forgets every stream of a user, parked or not.
+func (h *ChatHub) dropStreams(userID int64) {
+	for _, stream := range h.streams[userID] {
+		if stream.client != nil {
+			stream.client.stream = nil
+		}
+		h.unparkStream(stream)
+	}
+	delete(h.streams, userID)
+}
+
+// expireStreams drops parked streams nobody resumed within replayRetention.
+func (h *ChatHub) expireStreams(now time.Time) {
+	for userID, streams := range h.streams {
+		for clientID, stream := range streams {
+			if stream.client == nil && now.Sub(stream.parkedAt) > replayRetention {
+				h.unparkStream(stream)
+				delete(streams, clientID)
+			}
+		}
+		if len(streams) == 0 {
+			delete(h.streams, userID)
+		}
+	}
+}
+
+// recordParked buffers a room payload for streams whose socket is away.
+func (h *ChatHub) recordParked(conversationID int64, payload []byte) {
+	for stream := range h.parked[conversationID] {
+		stream.record(payload)
+	}
+}
+
+// handleStreamControl applies an `ack` or `resume` frame.
+func (h *ChatHub) handleStreamControl(ctl streamControl) {
+	client := ctl.client
+	stream := client.stream
+	if stream == nil {
+		if ctl.kind == "resume" {
+			h.deliverDirect(client, []byte(`{"type":"system:error","code":"not_resumable"}`))
+		}
+		return
+	}
+
+	switch ctl.kind {
+	case "ack":
+		stream.ack(ctl.seq)
+	case "resume":
+		frames, ok := stream.since(ctl.seq)
+		if !ok {
+			// The client must refetch over REST, then carry on from lastSeq.
+			gap := []byte(`{"type":"replay:gap","lastSeq":` + strconv.FormatInt(stream.seq, 10) + `}`)
+			select {
+			case client.send <- gap:
+			default:
+			}
+			return
+		}
+		if len(frames) > 0 {
+			go client.replay(frames)
+		}
+	}
+}
+
+// replay writes buffered frames without tripping the hub's slow-socket
+// eviction. Live frames may interleave; clients order and dedupe by `seq`.
+func (c *ChatClient) replay(frames [][]byte) {
+	timeout := time.NewTimer(replayWriteTimeout)
+	defer timeout.Stop()
+	for _, frame := range frames {
+		select {
+		case c.send <- frame:
+		case <-timeout.C:
+			log.Printf("replay to user %d timed out", c.userID)
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sseKeepAlive is how often an idle event stream gets a comment line so
+// proxies don't time it out.
+const sseKeepAlive = 25 * time.Second
+
+// handleEventStream is a Server-Sent Events fallback for networks that block
+// WebSockets. It carries the same hub events as the socket (`message:new`,
+// `conversation:membership`, join request and connection notifications), one
+// JSON object per `data:` line, but is receive-only: messages are still sent
+// over REST or the socket. The token comes from the Authorization header or,
+// since EventSource can't set headers, the `token` query param.
+//
+// Responses:
+//   - 200 with a `text/event-stream` body that stays open
+//   - 401 without a valid token
+//   - 500 for repository/database failures
+func (h *ChatHub) handleEventStream(c *gin.Context) {
+	token := bearerTokenFromHeader(c.GetHeader("Authorization"))
+	if token == "" {
+		token = strings.TrimSpace(c.Query("token"))
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	claims, err := h.authenticateSocket(ctx, token, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, errSocketUnauthorized) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify session"})
+		return
+	}
+
+	conversations, _, err := h.repo.ListConversations(ctx, claims.UserID, ConversationListOptions{IncludeArchived: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list conversations"})
+		return
+	}
+
+	client := &ChatClient{
+		hub:           h,
+		done:          make(chan struct{}),
+		send:          make(chan []byte, 32),
+		userID:        claims.UserID,
+		subscriptions: make(map[int64]struct{}),
+		connectedAt:   time.Now(),
+		expiresAt:     claims.ExpiresAt,
+		sessionID:     claims.SessionID,
+	}
+	for _, convo := range conversations {
+		client.subscriptions[convo.ID] = struct{}{}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if _, err := fmt.Fprint(c.Writer, ": connected\n\n"); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	h.queueHello(client)
+	h.register <- client
+	defer func() {
+		h.unregister <- client
+	}()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case payload := <-client.send:
+			if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", payload); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-client.done:
+			// The hub dropped the listener (slow reader, expired session, or the
+			// account went away).
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
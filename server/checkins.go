@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The host shows a check-in code at the event, usually rendered as a QR code
+// by the app, and members scan it to mark they came. Check-ins feed the
+// attendance stats on profiles, where a no-show is a member of a finished
+// event that used check-in who never checked in.
+
+// checkinPrefix keeps check-in signatures distinct from session and invite
+// signatures made with the same secret.
+const checkinPrefix = "checkin."
+
+// checkinOpensBefore is how long before the start members can check in.
+// Check-in closes when the event is over.
+const checkinOpensBefore = time.Hour
+
+var ErrCheckinClosed = errors.New("check-in is not open for this event")
+var ErrHostCannotCheckIn = errors.New("hosts don't check in to their own event")
+var errWrongCheckinEvent = errors.New("check-in code is for another event")
+
+// checkinClaims is the payload of a check-in code. Codes are not stored and
+// don't expire; the check-in window is enforced from the event's current
+// start, so codes survive the event being moved.
+type checkinClaims struct {
+	EventID  int64     `json:"event_id"`
+	IssuedBy int64     `json:"issued_by"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// issueCheckin signs a check-in code the same way invites are signed, under
+// checkinPrefix.
+func (s *tokenSigner) issueCheckin(claims checkinClaims) (string, error) {
+	payloadBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encode check-in code: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(payloadBytes)
+	return payload + "." + s.sign([]byte(checkinPrefix+payload)), nil
+}
+
+// verifyCheckin checks a check-in code's signature.
+func (s *tokenSigner) verifyCheckin(token string) (*checkinClaims, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errMalformedToken
+	}
+	if !s.validSignature([]byte(checkinPrefix+payload), signature) {
+		return nil, errInvalidToken
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	var claims checkinClaims
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil || claims.EventID <= 0 {
+		return nil, errMalformedToken
+	}
+	return &claims, nil
+}
+
+const createTableEventCheckins = `
+CREATE TABLE IF NOT EXISTS event_checkins (
+    event_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    checked_in_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, user_id),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexEventCheckinsUser = `
+CREATE INDEX IF NOT EXISTS idx_event_checkins_user ON event_checkins(user_id);
+`
+
+const insertEventCheckin = `
+INSERT OR IGNORE INTO event_checkins (event_id, user_id) VALUES (?, ?);
+`
+
+const selectEventCheckin = `
+SELECT checked_in_at FROM event_checkins WHERE event_id = ? AND user_id = ?;
+`
+
+const selectEventCheckins = `
+SELECT ci.user_id, u.name, ci.checked_in_at
+FROM event_checkins ci
+JOIN users u ON u.id = ci.user_id
+WHERE ci.event_id = ?
+ORDER BY ci.checked_in_at, ci.user_id;
+`
+
+// selectUserAttendance counts the user's check-ins, and the finished events
+// that used check-in where they were a member but never checked in. Hosts
+// aren't counted for their own events.
+const selectUserAttendance = `
+SELECT
+    (SELECT COUNT(1) FROM event_checkins WHERE user_id = ?),
+    (SELECT COUNT(1)
+     FROM conversation_members cm
+     JOIN conversations c ON c.id = cm.conversation_id
+     JOIN events e ON e.id = c.event_id
+     WHERE cm.user_id = ?
+       AND e.user_id != cm.user_id
+       AND e.starts_at IS NOT NULL AND e.starts_at <= ?
+       AND EXISTS (SELECT 1 FROM event_checkins x WHERE x.event_id = e.id)
+       AND NOT EXISTS (SELECT 1 FROM event_checkins x WHERE x.event_id = e.id AND x.user_id = cm.user_id));
+`
+
+const deleteEventCheckinsForUser = `
+DELETE FROM event_checkins WHERE user_id = ?;
+`
+
+func (r *EventRepository) initEventCheckins(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventCheckins); err != nil {
+		return fmt.Errorf("create event checkins table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexEventCheckinsUser); err != nil {
+		return fmt.Errorf("create event checkins user index: %w", err)
+	}
+	return nil
+}
+
+// checkinOpen reports whether members can check in to the event now.
+func checkinOpen(event *Event, now time.Time) bool {
+	if event.StartsAt == nil {
+		return false
+	}
+	return !now.Before(event.StartsAt.Add(-checkinOpensBefore)) && !eventIsOver(event, now)
+}
+
+// CheckInToEvent marks userID as having come to the event and returns when
+// they checked in. Checking in twice keeps the first time.
+func (r *EventRepository) CheckInToEvent(ctx context.Context, eventID, userID int64, now time.Time) (time.Time, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if event.UserID == userID {
+		return time.Time{}, ErrHostCannotCheckIn
+	}
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			return time.Time{}, ErrNotConversationMember
+		}
+		return time.Time{}, err
+	}
+	role, err := memberRole(ctx, r.db, convo.ID, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if role == "" {
+		return time.Time{}, ErrNotConversationMember
+	}
+	if !checkinOpen(event, now) {
+		return time.Time{}, ErrCheckinClosed
+	}
+
+	if _, err := r.db.ExecContext(ctx, insertEventCheckin, eventID, userID); err != nil {
+		return time.Time{}, fmt.Errorf("insert event checkin: %w", err)
+	}
+	var checkedInAt time.Time
+	if err := r.db.QueryRowContext(ctx, selectEventCheckin, eventID, userID).Scan(&checkedInAt); err != nil {
+		return time.Time{}, fmt.Errorf("fetch event checkin: %w", err)
+	}
+	return checkedInAt, nil
+}
+
+// ListEventCheckins returns who checked in to the event, earliest first.
+// Only the host and co-hosts can see it.
+func (r *EventRepository) ListEventCheckins(ctx context.Context, eventID, actorID int64) ([]EventCheckin, error) {
+	if err := r.RequireEventModerator(ctx, eventID, actorID); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, selectEventCheckins, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("list event checkins: %w", err)
+	}
+	defer rows.Close()
+
+	checkins := []EventCheckin{}
+	for rows.Next() {
+		var checkin EventCheckin
+		if err := rows.Scan(&checkin.UserID, &checkin.Name, &checkin.CheckedInAt); err != nil {
+			return nil, fmt.Errorf("scan event checkin: %w", err)
+		}
+		checkins = append(checkins, checkin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate event checkins: %w", err)
+	}
+	return checkins, nil
+}
+
+// attendanceFor returns the user's check-in and no-show counts as of now.
+func (r *EventRepository) attendanceFor(ctx context.Context, userID int64, now time.Time) (Attendance, error) {
+	var attendance Attendance
+	finishedBy := sqliteTime(now.Add(-eventAssumedLength))
+	if err := r.db.QueryRowContext(ctx, selectUserAttendance, userID, userID, finishedBy).Scan(&attendance.Attended, &attendance.NoShows); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return attendance, nil
+		}
+		return attendance, fmt.Errorf("fetch attendance: %w", err)
+	}
+	return attendance, nil
+}
+
+// getCheckinCode returns a check-in code for the host to show at the event,
+// typically as a QR code members scan with the app.
+//
+// Responses:
+//  - 200 with `token`, `eventId`, `opensAt` and `closesAt` (the latter two
+//    omitted while the event has no start time)
+//  - 401 if the caller has no session
+//  - 400 for invalid event id
+//  - 403 if the caller isn't the host or a co-host
+//  - 404 if the event doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) getCheckinCode(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RequireEventModerator(ctx, eventID, claims.UserID); err != nil {
+		respondCheckinError(c, err)
+		return
+	}
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		respondCheckinError(c, err)
+		return
+	}
+
+	token, err := h.hub.signer.issueCheckin(checkinClaims{
+		EventID:  eventID,
+		IssuedBy: claims.UserID,
+		IssuedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create check-in code"})
+		return
+	}
+
+	response := gin.H{"token": token, "eventId": eventID}
+	if event.StartsAt != nil {
+		response["opensAt"] = event.StartsAt.Add(-checkinOpensBefore)
+		response["closesAt"] = event.StartsAt.Add(eventAssumedLength)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// checkIn marks the caller as attending with the code the host showed.
+// Checking in again is a no-op that returns the first check-in time.
+//
+// Responses:
+//  - 200 with `checkedInAt`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or event id, or a malformed, forged or other
+//    event's code
+//  - 403 if the caller isn't in the event chat or is the host
+//  - 404 if the event doesn't exist
+//  - 409 if check-in isn't open (from an hour before the start until the
+//    event is over)
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) checkIn(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload CheckInParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	code, err := h.hub.signer.verifyCheckin(payload.Token)
+	if err == nil && code.EventID != eventID {
+		err = errWrongCheckinEvent
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid check-in code"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	checkedInAt, err := h.repo.CheckInToEvent(ctx, eventID, claims.UserID, time.Now())
+	if err != nil {
+		respondCheckinError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"checkedInAt": checkedInAt})
+}
+
+// listCheckins returns who has checked in to the event.
+//
+// Responses:
+//  - 200 with `checkins`
+//  - 401 if the caller has no session
+//  - 400 for invalid event id
+//  - 403 if the caller isn't the host or a co-host
+//  - 404 if the event doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listCheckins(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	checkins, err := h.repo.ListEventCheckins(ctx, eventID, claims.UserID)
+	if err != nil {
+		respondCheckinError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"checkins": checkins})
+}
+
+func respondCheckinError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+	case errors.Is(err, ErrNotEventHost):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can manage check-in"})
+	case errors.Is(err, ErrNotConversationMember):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only members of the event can check in"})
+	case errors.Is(err, ErrHostCannotCheckIn):
+		c.JSON(http.StatusForbidden, gin.H{"error": "hosts don't check in to their own event"})
+	case errors.Is(err, ErrCheckinClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": "check-in isn't open for this event"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check in"})
+	}
+}
@@ -0,0 +1,213 @@
+// Command adminctl runs one-off maintenance against the server's database:
+// creating users, resetting passwords, promoting admins, managing service API
+// keys, purging old events and compacting the file. It shares the server's
+// database code through internal/maintenance. It doesn't migrate the schema,
+// so start the server against a new database once before using it.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+// defaultDatabasePath matches the server's own default.
+const defaultDatabasePath = "event.sqlite"
+
+const usage = `usage: adminctl <command> [flags]
+
+commands:
+  create-user     -name NAME -email EMAIL -password PASSWORD [-admin]
+  reset-password  -email EMAIL -password PASSWORD
+  promote         -email EMAIL
+  demote          -email EMAIL
+  create-api-key  -name NAME -scopes moderation,webhooks,debug [-expires-in 2160h]
+  list-api-keys
+  revoke-api-key  -name NAME
+  purge-events    [-older-than 720h] [-dry-run]
+  compact
+
+every command takes -db PATH (default $DATABASE_PATH or ` + defaultDatabasePath + `),
+which the server must already have migrated
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes one command and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	command := args[0]
+	switch command {
+	case "create-user", "reset-password", "promote", "demote", "create-api-key", "list-api-keys", "revoke-api-key", "purge-events", "compact":
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", command, usage)
+		return 2
+	}
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	defaultDB := defaultDatabasePath
+	if path := strings.TrimSpace(os.Getenv("DATABASE_PATH")); path != "" {
+		defaultDB = path
+	}
+	dbPath := flags.String("db", defaultDB, "path to the SQLite database")
+	name := flags.String("name", "", "display name, or api key name")
+	email := flags.String("email", "", "account email")
+	password := flags.String("password", "", "new password")
+	admin := flags.Bool("admin", false, "create the user as an admin")
+	scopes := flags.String("scopes", "", "comma-separated api key scopes")
+	expiresIn := flags.Duration("expires-in", 0, "how long the api key works; 0 never expires")
+	olderThan := flags.Duration("older-than", maintenance.DefaultPurgeAge, "purge past events that started before this long ago")
+	dryRun := flags.Bool("dry-run", false, "report what would be purged without deleting it")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	required := func(flagNames ...string) bool {
+		for _, flagName := range flagNames {
+			if strings.TrimSpace(flags.Lookup(flagName).Value.String()) == "" {
+				fmt.Fprintf(stderr, "%s: -%s is required\n", command, flagName)
+				return false
+			}
+		}
+		return true
+	}
+	switch command {
+	case "create-user":
+		if !required("name", "email", "password") {
+			return 2
+		}
+	case "reset-password":
+		if !required("email", "password") {
+			return 2
+		}
+	case "promote", "demote":
+		if !required("email") {
+			return 2
+		}
+	case "create-api-key":
+		if !required("name", "scopes") {
+			return 2
+		}
+	case "revoke-api-key":
+		if !required("name") {
+			return 2
+		}
+	}
+
+	db, err := maintenance.Open(*dbPath, 1)
+	if err != nil {
+		fmt.Fprintf(stderr, "open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := maintenance.RequireSchema(ctx, db); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", *dbPath, err)
+		return 1
+	}
+
+	switch command {
+	case "create-user":
+		id, err := maintenance.CreateUser(ctx, db, *name, *email, *password, *admin)
+		if err != nil {
+			fmt.Fprintf(stderr, "create user: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "created user %d (%s)\n", id, strings.TrimSpace(*email))
+	case "reset-password":
+		if err := maintenance.SetPassword(ctx, db, *email, *password); err != nil {
+			fmt.Fprintf(stderr, "reset password: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "password reset for %s\n", strings.TrimSpace(*email))
+	case "promote", "demote":
+		if err := maintenance.SetAdmin(ctx, db, *email, command == "promote"); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", command, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%sd %s\n", command, strings.TrimSpace(*email))
+	case "create-api-key":
+		granted, err := maintenance.ParseAPIKeyScopes(*scopes)
+		if err != nil {
+			fmt.Fprintf(stderr, "create-api-key: %v\n", err)
+			return 2
+		}
+		if *expiresIn < 0 {
+			fmt.Fprintf(stderr, "create-api-key: -expires-in must not be negative\n")
+			return 2
+		}
+		var expiresAt *time.Time
+		if *expiresIn > 0 {
+			value := time.Now().Add(*expiresIn)
+			expiresAt = &value
+		}
+		key, secret, err := maintenance.CreateAPIKey(ctx, db, *name, granted, expiresAt)
+		if err != nil {
+			fmt.Fprintf(stderr, "create api key: %v\n", err)
+			return 1
+		}
+		// The key is printed alone on stdout so scripts can capture it.
+		fmt.Fprintf(stderr, "created api key %q with scopes %s; it won't be shown again\n", key.Name, strings.Join(key.Scopes, ","))
+		fmt.Fprintln(stdout, secret)
+	case "list-api-keys":
+		keys, err := maintenance.ListAPIKeys(ctx, db)
+		if err != nil {
+			fmt.Fprintf(stderr, "list api keys: %v\n", err)
+			return 1
+		}
+		for _, key := range keys {
+			expires, lastUsed := "never", "never"
+			if key.ExpiresAt != nil {
+				expires = key.ExpiresAt.Format(time.RFC3339)
+			}
+			if key.LastUsedAt != nil {
+				lastUsed = key.LastUsedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(stdout, "%s\tscopes=%s\texpires=%s\tlast-used=%s\n", key.Name, strings.Join(key.Scopes, ","), expires, lastUsed)
+		}
+	case "revoke-api-key":
+		if err := maintenance.RevokeAPIKey(ctx, db, *name); err != nil {
+			fmt.Fprintf(stderr, "revoke api key: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "revoked api key %q\n", strings.TrimSpace(*name))
+	case "purge-events":
+		if *olderThan < 0 {
+			fmt.Fprintf(stderr, "purge-events: -older-than must not be negative\n")
+			return 2
+		}
+		ids, err := maintenance.PurgePastEvents(ctx, db, time.Now().Add(-*olderThan), *dryRun)
+		if err != nil {
+			fmt.Fprintf(stderr, "purge events: %v\n", err)
+			return 1
+		}
+		verb := "purged"
+		if *dryRun {
+			verb = "would purge"
+		}
+		fmt.Fprintf(stdout, "%s %d past events\n", verb, len(ids))
+	case "compact":
+		before, after, err := maintenance.Compact(ctx, db, *dbPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "compact: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "compacted %s: %d -> %d bytes\n", *dbPath, before, after)
+	}
+	return 0
+}
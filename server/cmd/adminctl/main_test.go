@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+func TestRun(t *testing.T) {
+	emptyDB := filepath.Join(t.TempDir(), "empty.sqlite")
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStderr string
+	}{
+		{"no command", nil, 2, "usage: adminctl"},
+		{"help", []string{"help"}, 2, "usage: adminctl"},
+		{"unknown command", []string{"drop-tables"}, 2, `unknown command "drop-tables"`},
+		{"unknown flag", []string{"compact", "-force"}, 2, "flag provided but not defined"},
+		{"missing email", []string{"promote", "-db", emptyDB}, 2, "-email is required"},
+		{"missing scopes", []string{"create-api-key", "-db", emptyDB, "-name", "ops"}, 2, "-scopes is required"},
+		{"unmigrated database", []string{"list-api-keys", "-db", emptyDB}, 1, maintenance.ErrNoSchema.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, &stdout, &stderr); got != tt.want {
+				t.Fatalf("exit code = %d, want %d; stderr %s", got, tt.want, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Fatalf("stderr = %q, want it to mention %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCompressionMinSize is the smallest response body worth compressing;
+// below it the encoding overhead outweighs the savings.
+const defaultCompressionMinSize = 1024
+
+var (
+	gzipWriters  = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	flateWriters = sync.Pool{New: func() any {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	}}
+)
+
+// compressionMinSizeFromEnv reads COMPRESSION_MIN_SIZE (bytes). A negative
+// value turns compression off.
+func compressionMinSizeFromEnv() int {
+	raw := strings.TrimSpace(os.Getenv("COMPRESSION_MIN_SIZE"))
+	if raw == "" {
+		return defaultCompressionMinSize
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("invalid COMPRESSION_MIN_SIZE %q; using %d", raw, defaultCompressionMinSize)
+		return defaultCompressionMinSize
+	}
+	return parsed
+}
+
+// compressionMiddleware gzips (or deflates) responses of at least minSize
+// bytes for clients that accept it. Paths in skip, like the WebSocket and SSE
+// endpoints, are passed through untouched, as are upgrade requests.
+func compressionMiddleware(minSize int, skip ...string) gin.HandlerFunc {
+	skipped := make(map[string]struct{}, len(skip))
+	for _, path := range skip {
+		skipped[path] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		if minSize < 0 || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		if _, ok := skipped[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", "Accept-Encoding")
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize}
+		c.Writer = writer
+		defer writer.finish()
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks gzip, then deflate, from an Accept-Encoding header,
+// honouring q=0 refusals.
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				ok = false
+			}
+		}
+		accepted[name] = ok
+	}
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[encoding]; listed && ok {
+			return encoding
+		}
+		if ok, listed := accepted["*"]; listed && ok {
+			if _, refused := accepted[encoding]; !refused {
+				return encoding
+			}
+		}
+	}
+	return ""
+}
+
+// compressWriter holds the body back until it reaches minSize, then switches
+// to compressing. Bodies that end smaller are sent as they are.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding   string
+	minSize    int
+	buf        bytes.Buffer
+	compressor io.WriteCloser
+	passthru   bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.compressor != nil:
+		return w.compressor.Write(data)
+	case w.passthru:
+		return w.ResponseWriter.Write(data)
+	}
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.startCompressing(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what is buffered. A handler flushing before minSize is reached
+// is streaming, so the rest of its response is left uncompressed.
+func (w *compressWriter) Flush() {
+	if w.compressor == nil && !w.passthru {
+		w.passthru = true
+		w.writeBuffered()
+	}
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) startCompressing() error {
+	header := w.Header()
+	status := w.Status()
+	if header.Get("Content-Encoding") != "" || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.passthru = true
+		return w.writeBuffered()
+	}
+
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	if w.encoding == "gzip" {
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(w.ResponseWriter)
+		w.compressor = gz
+	} else {
+		fl := flateWriters.Get().(*flate.Writer)
+		fl.Reset(w.ResponseWriter)
+		w.compressor = fl
+	}
+	_, err := w.compressor.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) writeBuffered() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish writes out a body that never reached minSize, or closes the
+// compressor and returns it to its pool.
+func (w *compressWriter) finish() {
+	if w.compressor == nil {
+		if err := w.writeBuffered(); err != nil {
+			log.Printf("write response failed: %v", err)
+		}
+		return
+	}
+	if err := w.compressor.Close(); err != nil {
+		log.Printf("close response compressor failed: %v", err)
+	}
+	switch compressor := w.compressor.(type) {
+	case *gzip.Writer:
+		gzipWriters.Put(compressor)
+	case *flate.Writer:
+		flateWriters.Put(compressor)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAddr         = ":8080"
+	defaultDatabasePath = "event.sqlite"
+	// devSessionSecret signs tokens when CHAT_SESSION_SECRET is unset, so
+	// local runs work out of the box. Never rely on it in production.
+	devSessionSecret = "local-dev-secret"
+)
+
+// Config is the server's startup configuration. It is loaded once from the
+// environment and flags, validated as a whole, and handed to each component.
+// Subsystems with many knobs (HTTP, chat) keep their own structs and readers;
+// Config only collects them so startup fails in one place.
+type Config struct {
+	Addr          string        // PORT or -addr; plain HTTP listen address
+	DatabasePath  string        // DATABASE_PATH or -db
+	MaxDBConns    int           // SQLITE_MAX_CONNS
+	SessionSecret string        // CHAT_SESSION_SECRET; signs new tokens
+	SessionTTL    time.Duration // SESSION_TTL; how long issued tokens stay valid
+
+	// PreviousSessionSecrets is CHAT_SESSION_PREVIOUS_SECRETS, retired
+	// secrets whose tokens, invites and codes still verify. Rotate by moving
+	// the old CHAT_SESSION_SECRET here.
+	PreviousSessionSecrets []string
+
+	HTTP HTTPConfig
+	Chat ChatConfig
+	Jobs JobsConfig
+}
+
+// loadConfig reads the environment, then lets command-line flags override
+// the listen address and database path. Every invalid value is reported in
+// the returned error, not just the first.
+func loadConfig(args []string) (Config, error) {
+	var problems []error
+
+	config := Config{
+		Addr:          defaultAddr,
+		DatabasePath:  defaultDatabasePath,
+		MaxDBConns:    defaultMaxDBConns,
+		SessionSecret: strings.TrimSpace(os.Getenv("CHAT_SESSION_SECRET")),
+		SessionTTL:    defaultSessionTTL,
+		HTTP:          newHTTPConfigFromEnv(),
+		Chat:          newChatConfigFromEnv(),
+
+		PreviousSessionSecrets: envList("CHAT_SESSION_PREVIOUS_SECRETS"),
+	}
+	var jobProblems []error
+	config.Jobs, jobProblems = newJobsConfigFromEnv()
+	problems = append(problems, jobProblems...)
+	if port := strings.TrimSpace(os.Getenv("PORT")); port != "" {
+		config.Addr = ":" + port
+	}
+	if path := strings.TrimSpace(os.Getenv("DATABASE_PATH")); path != "" {
+		config.DatabasePath = path
+	}
+	if raw := strings.TrimSpace(os.Getenv("SQLITE_MAX_CONNS")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("SQLITE_MAX_CONNS %q is not a whole number", raw))
+		} else {
+			config.MaxDBConns = parsed
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("SESSION_TTL")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("SESSION_TTL %q is not a duration such as 12h", raw))
+		} else {
+			config.SessionTTL = parsed
+		}
+	}
+
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	flags.StringVar(&config.Addr, "addr", config.Addr, "HTTP listen address (overrides PORT)")
+	flags.StringVar(&config.DatabasePath, "db", config.DatabasePath, "path to the SQLite database (overrides DATABASE_PATH)")
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	problems = append(problems, config.validate()...)
+	if len(problems) > 0 {
+		return Config{}, errors.Join(problems...)
+	}
+	if config.SessionSecret == "" {
+		log.Println("CHAT_SESSION_SECRET not set; using development fallback secret")
+		config.SessionSecret = devSessionSecret
+	}
+	return config, nil
+}
+
+// validate checks the resolved values, including the ones whose readers
+// already fell back on bad input.
+func (c Config) validate() []error {
+	var problems []error
+
+	if _, port, err := net.SplitHostPort(c.Addr); err != nil {
+		problems = append(problems, fmt.Errorf("listen address %q must look like :8080 or 127.0.0.1:8080", c.Addr))
+	} else if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		problems = append(problems, fmt.Errorf("listen port %q must be between 1 and 65535", port))
+	}
+
+	if c.DatabasePath == "" {
+		problems = append(problems, errors.New("database path must not be empty"))
+	} else if info, err := os.Stat(filepath.Dir(c.DatabasePath)); err != nil || !info.IsDir() {
+		problems = append(problems, fmt.Errorf("database directory %q does not exist", filepath.Dir(c.DatabasePath)))
+	}
+	if c.MaxDBConns < 1 {
+		problems = append(problems, fmt.Errorf("SQLITE_MAX_CONNS must be at least 1, got %d", c.MaxDBConns))
+	}
+
+	if len(c.PreviousSessionSecrets) > 0 && c.SessionSecret == "" {
+		problems = append(problems, errors.New("CHAT_SESSION_PREVIOUS_SECRETS needs CHAT_SESSION_SECRET set to the new secret"))
+	}
+	if c.SessionTTL < time.Minute {
+		problems = append(problems, fmt.Errorf("SESSION_TTL must be at least 1m, got %s", c.SessionTTL))
+	}
+
+	if c.HTTP.MaxUploadBytes < c.HTTP.MaxBodyBytes {
+		problems = append(problems, fmt.Errorf("UPLOAD_BODY_LIMIT (%d) must not be below REQUEST_BODY_LIMIT (%d)", c.HTTP.MaxUploadBytes, c.HTTP.MaxBodyBytes))
+	}
+	if c.HTTP.RequireSigning && len(c.HTTP.SigningSecrets) == 0 {
+		problems = append(problems, errors.New("REQUEST_SIGNING_REQUIRED needs REQUEST_SIGNING_SECRETS"))
+	}
+	if c.HTTP.ReadHeaderTimeout > c.HTTP.ReadTimeout {
+		problems = append(problems, fmt.Errorf("HTTP_READ_HEADER_TIMEOUT (%s) must not exceed HTTP_READ_TIMEOUT (%s)", c.HTTP.ReadHeaderTimeout, c.HTTP.ReadTimeout))
+	}
+
+	for _, interval := range c.Jobs.intervals() {
+		if interval.value < minJobInterval {
+			problems = append(problems, fmt.Errorf("%s must be at least %s, got %s", interval.name, minJobInterval, interval.value))
+		}
+	}
+	return problems
+}
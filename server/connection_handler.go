@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listConnections returns the caller's accepted connections.
+func (h *UserHandler) listConnections(c *gin.Context) {
+	h.respondWithConnections(c, connectionAccepted)
+}
+
+// listConnectionRequests returns pending requests in both directions; each
+// entry's `direction` says whether the caller sent or received it.
+func (h *UserHandler) listConnectionRequests(c *gin.Context) {
+	h.respondWithConnections(c, connectionPending)
+}
+
+func (h *UserHandler) respondWithConnections(c *gin.Context, status string) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	connections, err := h.repo.ListConnections(ctx, claims.UserID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load connections"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": connections})
+}
+
+// sendConnectionRequest asks another user to connect. If they had already
+// asked the caller, the two are connected straight away.
+//
+// Responses:
+//  - 201 with the pending or accepted connection
+//  - 400 for invalid JSON or a request to oneself
+//  - 401 if the caller has no session
+//  - 404 if the target user does not exist
+//  - 409 if already connected or a request is already pending
+//  - 500 for repository/database failures
+func (h *UserHandler) sendConnectionRequest(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload SendConnectionParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	connection, err := h.repo.SendConnectionRequest(ctx, claims.UserID, payload.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSelfConnection):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot connect with yourself"})
+		case errors.Is(err, ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, ErrConnectionExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "connection already exists"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send connection request"})
+		}
+		return
+	}
+
+	eventType := "connection:request"
+	if connection.Status == connectionAccepted {
+		eventType = "connection:accepted"
+	}
+	h.notifyConnection(ctx, payload.UserID, eventType, connection.ID)
+
+	c.JSON(http.StatusCreated, gin.H{"connection": connection})
+}
+
+// acceptConnection accepts the pending request :userId sent the caller.
+func (h *UserHandler) acceptConnection(c *gin.Context) {
+	h.respondToConnection(c, true)
+}
+
+// declineConnection declines the pending request :userId sent the caller. The
+// requester is not notified and may ask again later.
+func (h *UserHandler) declineConnection(c *gin.Context) {
+	h.respondToConnection(c, false)
+}
+
+// respondToConnection backs accept/decline.
+//
+// Responses:
+//  - 200 with the updated connection
+//  - 400 for invalid path params
+//  - 401 if the caller has no session
+//  - 404 if there is no pending request from that user
+//  - 500 for repository/database failures
+func (h *UserHandler) respondToConnection(c *gin.Context, accept bool) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	requesterID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil || requesterID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	connection, err := h.repo.RespondToConnectionRequest(ctx, claims.UserID, requesterID, accept)
+	if err != nil {
+		if errors.Is(err, ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "pending request not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update connection"})
+		return
+	}
+
+	if accept {
+		h.notifyConnection(ctx, requesterID, "connection:accepted", connection.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"connection": connection})
+}
+
+// removeConnection drops a connection with :userId, or withdraws a pending
+// request in either direction.
+//
+// Responses:
+//  - 204 on success
+//  - 400 for invalid path params
+//  - 401 if the caller has no session
+//  - 404 if there is nothing to remove
+//  - 500 for repository/database failures
+func (h *UserHandler) removeConnection(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	otherID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil || otherID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RemoveConnection(ctx, claims.UserID, otherID); err != nil {
+		if errors.Is(err, ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "connection not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove connection"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// notifyConnection pushes the connection, as the recipient sees it, to their
+// sockets. Failures only cost the live update, so they're not surfaced.
+func (h *UserHandler) notifyConnection(ctx context.Context, userID int64, eventType string, connectionID int64) {
+	connection, err := h.repo.GetConnection(ctx, connectionID, userID)
+	if err != nil {
+		return
+	}
+	h.hub.NotifyConnection(userID, eventType, *connection)
+}
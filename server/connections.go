@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrConnectionNotFound = errors.New("connection not found")
+var ErrConnectionExists = errors.New("connection already exists")
+var ErrSelfConnection = errors.New("cannot connect with yourself")
+var ErrNotConnected = errors.New("users are not connected")
+
+const (
+	connectionPending  = "pending"
+	connectionAccepted = "accepted"
+	connectionDeclined = "declined"
+)
+
+// A connection row is directional while pending (requester asked addressee)
+// and symmetric once accepted. Only one row ever exists per pair.
+const createTableConnections = `
+CREATE TABLE IF NOT EXISTS connections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    requester_id INTEGER NOT NULL,
+    addressee_id INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending','accepted','declined')),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    responded_at DATETIME,
+    UNIQUE (requester_id, addressee_id),
+    FOREIGN KEY (requester_id) REFERENCES users(id),
+    FOREIGN KEY (addressee_id) REFERENCES users(id)
+);
+`
+
+const createIndexConnectionsAddressee = `
+CREATE INDEX IF NOT EXISTS idx_connections_addressee
+ON connections(addressee_id, status);
+`
+
+const selectConnectionBetween = `
+SELECT id, requester_id, addressee_id, status
+FROM connections
+WHERE (requester_id = ? AND addressee_id = ?)
+   OR (requester_id = ? AND addressee_id = ?);
+`
+
+const insertConnection = `
+INSERT INTO connections (requester_id, addressee_id, status)
+VALUES (?, ?, 'pending');
+`
+
+const deleteConnectionByID = `
+DELETE FROM connections
+WHERE id = ?;
+`
+
+const acceptConnectionByID = `
+UPDATE connections
+SET status = 'accepted', responded_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+`
+
+const respondToConnection = `
+UPDATE connections
+SET status = ?, responded_at = CURRENT_TIMESTAMP
+WHERE requester_id = ? AND addressee_id = ? AND status = 'pending';
+`
+
+const deleteActiveConnection = `
+DELETE FROM connections
+WHERE ((requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?))
+  AND status IN ('pending','accepted');
+`
+
+const deleteConnectionsForUser = `
+DELETE FROM connections
+WHERE requester_id = ? OR addressee_id = ?;
+`
+
+// selectConnectionsForUser resolves the other side of each connection; the
+// viewer id is bound three times.
+const selectConnectionsForUser = `
+SELECT c.id, c.requester_id, c.status, c.created_at, c.responded_at, u.id, u.name
+FROM connections c
+JOIN users u ON u.id = CASE WHEN c.requester_id = ? THEN c.addressee_id ELSE c.requester_id END
+WHERE (c.requester_id = ? OR c.addressee_id = ?)
+  AND c.status = ?
+  AND u.deleted_at IS NULL
+ORDER BY COALESCE(c.responded_at, c.created_at) DESC, c.id DESC;
+`
+
+const selectConnectionByID = `
+SELECT c.id, c.requester_id, c.status, c.created_at, c.responded_at, u.id, u.name
+FROM connections c
+JOIN users u ON u.id = CASE WHEN c.requester_id = ? THEN c.addressee_id ELSE c.requester_id END
+WHERE c.id = ?;
+`
+
+const countAcceptedConnection = `
+SELECT COUNT(1)
+FROM connections
+WHERE ((requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?))
+  AND status = 'accepted';
+`
+
+const selectActiveUserExists = `
+SELECT COUNT(1)
+FROM users
+WHERE id = ? AND deleted_at IS NULL;
+`
+
+func (r *EventRepository) initConnections(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableConnections); err != nil {
+		return fmt.Errorf("create connections table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexConnectionsAddressee); err != nil {
+		return fmt.Errorf("create connections index: %w", err)
+	}
+	return nil
+}
+
+// SendConnectionRequest asks targetID to connect with userID. If targetID had
+// already asked userID, the pending request is accepted instead. A previously
+// declined request can be sent again.
+func (r *EventRepository) SendConnectionRequest(ctx context.Context, userID, targetID int64) (*Connection, error) {
+	if userID == targetID {
+		return nil, ErrSelfConnection
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin connection tx: %w", err)
+	}
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, selectActiveUserExists, targetID).Scan(&exists); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("lookup connection target: %w", err)
+	}
+	if exists == 0 {
+		tx.Rollback()
+		return nil, ErrUserNotFound
+	}
+
+	var existingID, requesterID, addresseeID int64
+	var status string
+	err = tx.QueryRowContext(ctx, selectConnectionBetween, userID, targetID, targetID, userID).Scan(&existingID, &requesterID, &addresseeID, &status)
+	var connectionID int64
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		connectionID, err = insertPendingConnection(ctx, tx, userID, targetID)
+	case err != nil:
+		err = fmt.Errorf("lookup connection: %w", err)
+	case status == connectionAccepted || (status == connectionPending && requesterID == userID):
+		err = ErrConnectionExists
+	case status == connectionPending:
+		connectionID = existingID
+		if _, err = tx.ExecContext(ctx, acceptConnectionByID, existingID); err != nil {
+			err = fmt.Errorf("accept connection: %w", err)
+		}
+	default:
+		if _, err = tx.ExecContext(ctx, deleteConnectionByID, existingID); err != nil {
+			err = fmt.Errorf("clear declined connection: %w", err)
+			break
+		}
+		connectionID, err = insertPendingConnection(ctx, tx, userID, targetID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit connection: %w", err)
+	}
+	return r.GetConnection(ctx, connectionID, userID)
+}
+
+func insertPendingConnection(ctx context.Context, tx *sql.Tx, userID, targetID int64) (int64, error) {
+	result, err := tx.ExecContext(ctx, insertConnection, userID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("insert connection: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("connection last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// RespondToConnectionRequest accepts or declines the pending request that
+// requesterID sent to userID.
+func (r *EventRepository) RespondToConnectionRequest(ctx context.Context, userID, requesterID int64, accept bool) (*Connection, error) {
+	status := connectionDeclined
+	if accept {
+		status = connectionAccepted
+	}
+
+	result, err := r.db.ExecContext(ctx, respondToConnection, status, requesterID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("respond to connection: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("check connection rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrConnectionNotFound
+	}
+
+	var connectionID, storedRequester, addresseeID int64
+	if err := r.db.QueryRowContext(ctx, selectConnectionBetween, requesterID, userID, userID, requesterID).Scan(&connectionID, &storedRequester, &addresseeID, &status); err != nil {
+		return nil, fmt.Errorf("reload connection: %w", err)
+	}
+	return r.GetConnection(ctx, connectionID, userID)
+}
+
+// RemoveConnection drops an accepted connection or withdraws a pending request
+// in either direction.
+func (r *EventRepository) RemoveConnection(ctx context.Context, userID, otherID int64) error {
+	result, err := r.db.ExecContext(ctx, deleteActiveConnection, userID, otherID, otherID, userID)
+	if err != nil {
+		return fmt.Errorf("remove connection: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check connection rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrConnectionNotFound
+	}
+	return nil
+}
+
+// ListConnections returns the user's connections with the given status,
+// most recently changed first.
+func (r *EventRepository) ListConnections(ctx context.Context, userID int64, status string) ([]Connection, error) {
+	rows, err := r.db.QueryContext(ctx, selectConnectionsForUser, userID, userID, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("list connections: %w", err)
+	}
+	defer rows.Close()
+
+	connections := []Connection{}
+	for rows.Next() {
+		connection, err := scanConnection(rows, userID)
+		if err != nil {
+			return nil, fmt.Errorf("scan connection: %w", err)
+		}
+		connections = append(connections, connection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate connections: %w", err)
+	}
+	return connections, nil
+}
+
+// AreConnected reports whether the two users have an accepted connection.
+func (r *EventRepository) AreConnected(ctx context.Context, userID, otherID int64) (bool, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, countAcceptedConnection, userID, otherID, otherID, userID).Scan(&count); err != nil {
+		return false, fmt.Errorf("check connection: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetConnection loads a connection as seen by viewerID.
+func (r *EventRepository) GetConnection(ctx context.Context, connectionID, viewerID int64) (*Connection, error) {
+	connection, err := scanConnection(r.db.QueryRowContext(ctx, selectConnectionByID, viewerID, connectionID), viewerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrConnectionNotFound
+		}
+		return nil, fmt.Errorf("fetch connection: %w", err)
+	}
+	return &connection, nil
+}
+
+func scanConnection(row rowScanner, viewerID int64) (Connection, error) {
+	var connection Connection
+	var requesterID int64
+	var respondedAt sql.NullTime
+	err := row.Scan(
+		&connection.ID,
+		&requesterID,
+		&connection.Status,
+		&connection.CreatedAt,
+		&respondedAt,
+		&connection.UserID,
+		&connection.Name,
+	)
+	connection.Direction = "incoming"
+	if requesterID == viewerID {
+		connection.Direction = "outgoing"
+	}
+	if respondedAt.Valid {
+		value := respondedAt.Time
+		connection.RespondedAt = &value
+	}
+	return connection, err
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidConversationCursor reports a `cursor` that ListConversations did
+// not issue.
+var ErrInvalidConversationCursor = errors.New("invalid conversation cursor")
+
+// maxConversationPageSize caps `limit` on GET /conversations.
+const maxConversationPageSize = 100
+
+// conversationActivity orders the inbox: the newest message, or creation for
+// a conversation nobody has written in.
+const conversationActivity = `COALESCE(c.last_message_at, c.created_at)`
+
+const backfillConversationLastMessageAt = `
+UPDATE conversations
+SET last_message_at = (SELECT MAX(m.created_at) FROM messages m WHERE m.conversation_id = conversations.id)
+WHERE last_message_at IS NULL;
+`
+
+const createTriggerConversationLastMessageAt = `
+CREATE TRIGGER IF NOT EXISTS conversations_touch_on_message
+AFTER INSERT ON messages
+BEGIN
+    UPDATE conversations SET last_message_at = NEW.created_at WHERE id = NEW.conversation_id;
+END;
+`
+
+func (r *EventRepository) initConversationActivity(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "conversations", "last_message_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, backfillConversationLastMessageAt); err != nil {
+		return fmt.Errorf("backfill conversation last_message_at: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTriggerConversationLastMessageAt); err != nil {
+		return fmt.Errorf("create conversation activity trigger: %w", err)
+	}
+	return nil
+}
+
+// conversationCursor is the position after the last conversation of a page.
+type conversationCursor struct {
+	activity string // conversationActivity as SQLite returns it
+	id       int64
+}
+
+func (c conversationCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.activity + "|" + strconv.FormatInt(c.id, 10)))
+}
+
+func decodeConversationCursor(raw string) (*conversationCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidConversationCursor
+	}
+	activity, idPart, ok := strings.Cut(string(decoded), "|")
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if !ok || activity == "" || err != nil || id <= 0 {
+		return nil, ErrInvalidConversationCursor
+	}
+	return &conversationCursor{activity: activity, id: id}, nil
+}
+
+// appendScanner lets scanConversation read a row carrying extra trailing
+// columns.
+type appendScanner struct {
+	rowScanner
+	extra []any
+}
+
+func (s appendScanner) Scan(dest ...any) error {
+	return s.rowScanner.Scan(append(dest, s.extra...)...)
+}
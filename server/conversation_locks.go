@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// A locked conversation is read-only: members keep its history but can't
+// post, so clients show a disabled composer. Event chats lock when the event
+// is cancelled or deleted, or EVENT_CHAT_LOCK_AFTER after it started, and
+// unlock when the host edits the event back to life. System notices still
+// get in.
+
+// defaultEventChatLockAfter is how long after an event starts its chat locks.
+const defaultEventChatLockAfter = 7 * 24 * time.Hour
+
+// conversationLockedCode is the error code clients key the disabled composer
+// off, on sockets and REST alike.
+const conversationLockedCode = "conversation_locked"
+
+var ErrConversationLocked = errors.New("conversation is locked")
+
+const selectConversationLocked = `
+SELECT locked FROM conversations WHERE id = ?;
+`
+
+const updateEventConversationLocked = `
+UPDATE conversations SET locked = ? WHERE event_id = ?;
+`
+
+// lockInactiveEventConversations locks the chats of events that were called
+// off, for databases from before conversations could lock.
+const lockInactiveEventConversations = `
+UPDATE conversations SET locked = 1
+WHERE locked = 0
+  AND event_id IN (SELECT id FROM events WHERE status IN ('` + eventStatusCancelled + `', '` + eventStatusDeleted + `'));
+`
+
+const lockPastEventConversations = `
+UPDATE conversations SET locked = 1
+WHERE locked = 0
+  AND event_id IN (SELECT id FROM events WHERE status = 'past' AND starts_at IS NOT NULL AND starts_at <= ?);
+`
+
+func (r *EventRepository) initConversationLocks(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "conversations", "locked", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, lockInactiveEventConversations); err != nil {
+		return fmt.Errorf("lock inactive event chats: %w", err)
+	}
+	return nil
+}
+
+// isConversationLocked reports whether the conversation is read-only. q is
+// the transaction the caller is writing in, if any.
+func isConversationLocked(ctx context.Context, q rowQuery, conversationID int64) (bool, error) {
+	var locked bool
+	if err := q.QueryRowContext(ctx, selectConversationLocked, conversationID).Scan(&locked); err != nil {
+		return false, fmt.Errorf("check conversation locked: %w", err)
+	}
+	return locked, nil
+}
+
+// IsConversationLocked reports whether the conversation takes no new
+// messages.
+func (r *EventRepository) IsConversationLocked(ctx context.Context, conversationID int64) (bool, error) {
+	return isConversationLocked(ctx, r.db, conversationID)
+}
+
+// LockPastEventChats locks the chats of past events that started at or
+// before cutoff and returns how many it locked.
+func (r *EventRepository) LockPastEventChats(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, lockPastEventConversations, sqliteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("lock past event chats: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("lock past event chats rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// respondConversationLocked answers a REST post to a locked conversation.
+func respondConversationLocked(c *gin.Context) {
+	c.JSON(http.StatusConflict, gin.H{"error": "this conversation is read-only", "code": conversationLockedCode})
+}
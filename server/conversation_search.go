@@ -0,0 +1,30 @@
+package main
+
+import "strings"
+
+// maxConversationSearchLength bounds `q` on GET /conversations/search.
+const maxConversationSearchLength = 100
+
+// conversationSearchClause matches a conversation's title, another live
+// member's name, or its event's title against one LIKE pattern (bound three
+// times).
+const conversationSearchClause = `(
+    c.title LIKE ? ESCAPE '\'
+    OR EXISTS (
+        SELECT 1
+        FROM conversation_members pm
+        JOIN users pu ON pu.id = pm.user_id
+        WHERE pm.conversation_id = c.id
+          AND pm.user_id != cm.user_id
+          AND pu.deleted_at IS NULL
+          AND pu.name LIKE ? ESCAPE '\'
+    )
+    OR EXISTS (SELECT 1 FROM events se WHERE se.id = c.event_id AND se.title LIKE ? ESCAPE '\')
+)`
+
+// likeContains builds a case-insensitive substring pattern for term, escaping
+// LIKE's wildcards so they match literally.
+func likeContains(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	return "%" + escaped + "%"
+}
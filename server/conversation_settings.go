@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// mutedForever is stored as muted_until when a chat is muted without an end.
+var mutedForever = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+// maxSlowModeSeconds caps the slow mode interval hosts can set.
+const maxSlowModeSeconds = 3600
+
+var ErrNotEventConversation = errors.New("conversation is not an event chat")
+
+const createTableConversationSettings = `
+CREATE TABLE IF NOT EXISTS conversation_settings (
+    conversation_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    muted_until DATETIME,
+    archived_at DATETIME,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (conversation_id, user_id),
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const upsertConversationMute = `
+INSERT INTO conversation_settings (conversation_id, user_id, muted_until, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(conversation_id, user_id) DO UPDATE SET
+    muted_until = excluded.muted_until,
+    updated_at = CURRENT_TIMESTAMP;
+`
+
+const upsertConversationArchive = `
+INSERT INTO conversation_settings (conversation_id, user_id, archived_at, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(conversation_id, user_id) DO UPDATE SET
+    archived_at = excluded.archived_at,
+    updated_at = CURRENT_TIMESTAMP;
+`
+
+const selectConversationSettings = `
+SELECT muted_until, archived_at
+FROM conversation_settings
+WHERE conversation_id = ? AND user_id = ?;
+`
+
+// unarchiveForRecipients revives a conversation for everyone but the sender
+// of a new message.
+const unarchiveForRecipients = `
+UPDATE conversation_settings
+SET archived_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE conversation_id = ? AND user_id <> ? AND archived_at IS NOT NULL;
+`
+
+const unarchiveConversation = `
+UPDATE conversations
+SET archived_at = NULL
+WHERE id = ? AND archived_at IS NOT NULL;
+`
+
+// archiveStaleEventConversations archives event chats whose event started
+// before the cutoff and that have been quiet since then, so a chat revived by
+// a new message isn't immediately archived again.
+const archiveStaleEventConversations = `
+UPDATE conversations
+SET archived_at = CURRENT_TIMESTAMP
+WHERE archived_at IS NULL
+  AND event_id IN (SELECT id FROM events WHERE starts_at IS NOT NULL AND starts_at <= ?)
+  AND NOT EXISTS (
+      SELECT 1 FROM messages m
+      WHERE m.conversation_id = conversations.id AND m.created_at > ?
+  )
+RETURNING id;
+`
+
+const updateConversationSlowMode = `
+UPDATE conversations
+SET slow_mode_seconds = ?
+WHERE id = ?;
+`
+
+// selectLastUserMessageAt is when the member last posted an ordinary message;
+// system and bot messages don't count against slow mode.
+const selectLastUserMessageAt = `
+SELECT created_at
+FROM messages
+WHERE conversation_id = ? AND sender_id = ? AND kind = 'user'
+ORDER BY id DESC
+LIMIT 1;
+`
+
+const deleteConversationSettingsForUser = `
+DELETE FROM conversation_settings
+WHERE user_id = ?;
+`
+
+// selectUnreadTotal counts unread messages across the user's conversations,
+// leaving out muted ones; it mirrors countUnreadMessages per conversation.
+const selectUnreadTotal = `
+SELECT COUNT(m.id), COUNT(DISTINCT m.conversation_id)
+FROM conversation_members cm
+JOIN messages m ON m.conversation_id = cm.conversation_id
+LEFT JOIN conversation_read_state rs ON rs.conversation_id = cm.conversation_id AND rs.user_id = cm.user_id
+LEFT JOIN conversation_settings cs ON cs.conversation_id = cm.conversation_id AND cs.user_id = cm.user_id
+WHERE cm.user_id = ?
+  AND m.id > COALESCE(rs.last_read_message_id, 0)
+  AND (cs.muted_until IS NULL OR cs.muted_until <= ?);
+`
+
+func (r *EventRepository) initConversationSettings(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableConversationSettings); err != nil {
+		return fmt.Errorf("create conversation settings table: %w", err)
+	}
+	return nil
+}
+
+// MuteConversation silences a conversation for the user until the given time,
+// or indefinitely when until is nil.
+func (r *EventRepository) MuteConversation(ctx context.Context, conversationID, userID int64, until *time.Time) (time.Time, error) {
+	mutedUntil := mutedForever
+	if until != nil {
+		mutedUntil = until.UTC()
+	}
+	if _, err := r.db.ExecContext(ctx, upsertConversationMute, conversationID, userID, sqliteTime(mutedUntil)); err != nil {
+		return time.Time{}, fmt.Errorf("mute conversation: %w", err)
+	}
+	return mutedUntil, nil
+}
+
+// UnmuteConversation clears the user's mute on a conversation.
+func (r *EventRepository) UnmuteConversation(ctx context.Context, conversationID, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, upsertConversationMute, conversationID, userID, nil); err != nil {
+		return fmt.Errorf("unmute conversation: %w", err)
+	}
+	return nil
+}
+
+// ArchiveConversation hides a conversation from the user's default list
+// until they unarchive it or a new message arrives.
+func (r *EventRepository) ArchiveConversation(ctx context.Context, conversationID, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, upsertConversationArchive, conversationID, userID, sqliteTime(time.Now())); err != nil {
+		return fmt.Errorf("archive conversation: %w", err)
+	}
+	return nil
+}
+
+// UnarchiveConversation brings a conversation back for the user. An event
+// chat archived for everyone is revived too.
+func (r *EventRepository) UnarchiveConversation(ctx context.Context, conversationID, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, upsertConversationArchive, conversationID, userID, nil); err != nil {
+		return fmt.Errorf("unarchive conversation: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, unarchiveConversation, conversationID); err != nil {
+		return fmt.Errorf("unarchive event conversation: %w", err)
+	}
+	return nil
+}
+
+// ArchiveStaleEventChats archives event conversations whose event started at
+// or before cutoff and that have had no messages since. It returns the IDs
+// of the archived conversations.
+func (r *EventRepository) ArchiveStaleEventChats(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, archiveStaleEventConversations, sqliteTime(cutoff), sqliteTime(cutoff))
+	if err != nil {
+		return nil, fmt.Errorf("archive stale event chats: %w", err)
+	}
+	defer rows.Close()
+
+	var archived []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan archived conversation: %w", err)
+		}
+		archived = append(archived, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate archived conversations: %w", err)
+	}
+	return archived, nil
+}
+
+// conversationSettings returns when the user's mute ends (nil unless muted
+// right now) and whether they archived the conversation.
+func (r *EventRepository) conversationSettings(ctx context.Context, conversationID, userID int64) (*time.Time, bool, error) {
+	var mutedUntil, archivedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, selectConversationSettings, conversationID, userID).Scan(&mutedUntil, &archivedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("fetch conversation settings: %w", err)
+	}
+	if !mutedUntil.Valid || !mutedUntil.Time.After(time.Now()) {
+		return nil, archivedAt.Valid, nil
+	}
+	value := mutedUntil.Time
+	return &value, archivedAt.Valid, nil
+}
+
+// CountUnread returns the user's unread message total and how many
+// conversations contribute to it, ignoring muted conversations.
+func (r *EventRepository) CountUnread(ctx context.Context, userID int64) (int, int, error) {
+	var messages, conversations int
+	if err := r.db.QueryRowContext(ctx, selectUnreadTotal, userID, sqliteTime(time.Now())).Scan(&messages, &conversations); err != nil {
+		return 0, 0, fmt.Errorf("count unread total: %w", err)
+	}
+	return messages, conversations, nil
+}
+
+// SetSlowMode sets how many seconds each member of an event chat must wait
+// between messages; 0 turns slow mode off. Only the event's host or a co-host
+// may change it.
+func (r *EventRepository) SetSlowMode(ctx context.Context, conversationID, actorID int64, seconds int) error {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if convo.EventID == nil {
+		return ErrNotEventConversation
+	}
+	event, err := r.GetEventByID(ctx, *convo.EventID)
+	if err != nil {
+		return err
+	}
+	if err := checkEventModerator(ctx, r.db, event, convo.ID, actorID); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, updateConversationSlowMode, seconds, conversationID); err != nil {
+		return fmt.Errorf("set slow mode: %w", err)
+	}
+	return nil
+}
+
+// SlowModeWait reports how much longer the user must wait before posting in
+// the conversation, or 0 if they may post now. Hosts and co-hosts are never
+// held back.
+func (r *EventRepository) SlowModeWait(ctx context.Context, conversationID, userID int64, now time.Time) (time.Duration, error) {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if convo.SlowModeSeconds <= 0 || convo.EventID == nil {
+		return 0, nil
+	}
+	event, err := r.GetEventByID(ctx, *convo.EventID)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkEventModerator(ctx, r.db, event, convo.ID, userID); err == nil {
+		return 0, nil
+	} else if !errors.Is(err, ErrNotEventHost) {
+		return 0, err
+	}
+
+	var last time.Time
+	if err := r.db.QueryRowContext(ctx, selectLastUserMessageAt, conversationID, userID).Scan(&last); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("fetch last message time: %w", err)
+	}
+	wait := last.Add(time.Duration(convo.SlowModeSeconds) * time.Second).Sub(now)
+	if wait < 0 {
+		return 0, nil
+	}
+	return wait, nil
+}
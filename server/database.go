@@ -1,29 +1,68 @@
 package main
 
 import (
+    "context"
     "database/sql"
-    "fmt"
+    "errors"
+    "math/rand"
+    "time"
 
-    _ "modernc.org/sqlite"
+    "modernc.org/sqlite"
+    sqlite3 "modernc.org/sqlite/lib"
+
+    "who-else-is-free-server/internal/maintenance"
 )
 
-// openDB establishes a SQLite connection with sane defaults for this app.
-func openDB(path string) (*sql.DB, error) {
-    dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", path)
+const (
+    defaultMaxDBConns = 4
 
-    conn, err := sql.Open("sqlite", dsn)
-    if err != nil {
-        return nil, fmt.Errorf("open sqlite: %w", err)
-    }
+    // busyRetries is how many more times a transaction start is tried after
+    // SQLite's own busy timeout gives up; busyRetryBase doubles each time.
+    busyRetries   = 3
+    busyRetryBase = 25 * time.Millisecond
+)
 
-    conn.SetConnMaxLifetime(0)
-    conn.SetMaxIdleConns(10)
-    conn.SetMaxOpenConns(1)
+// openDB establishes a SQLite connection pool with sane defaults for this app.
+//
+// The database runs in WAL mode, so the maxConns connections read
+// concurrently while SQLite admits one writer at a time. Transactions begin
+// IMMEDIATE, taking the write lock up front: a deferred transaction that
+// reads and then writes can fail with SQLITE_BUSY without waiting. Foreign
+// keys are not enforced; the old `_foreign_keys` parameter was never applied
+// by this driver and the table-rebuild migrations assume they are off.
+//
+// adminctl opens the file through the same internal/maintenance helper.
+func openDB(path string, maxConns int) (*sql.DB, error) {
+    return maintenance.Open(path, maxConns)
+}
 
-    if err := conn.Ping(); err != nil {
-        _ = conn.Close()
-        return nil, fmt.Errorf("ping sqlite: %w", err)
+// isBusy reports whether err is SQLite refusing a lock another connection
+// holds.
+func isBusy(err error) bool {
+    var sqliteErr *sqlite.Error
+    if !errors.As(err, &sqliteErr) {
+        return false
     }
+    code := sqliteErr.Code() & 0xff
+    return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
 
-    return conn, nil
+// beginTx starts a write transaction, backing off and retrying while the
+// database is busy so a burst of writers queues instead of failing.
+func (r *EventRepository) beginTx(ctx context.Context) (*sql.Tx, error) {
+    delay := busyRetryBase
+    for attempt := 0; ; attempt++ {
+        tx, err := r.db.BeginTx(ctx, nil)
+        if err == nil || !isBusy(err) || attempt == busyRetries {
+            return tx, err
+        }
+        // Jitter keeps writers that failed together from retrying together.
+        wait := delay + time.Duration(rand.Int63n(int64(delay)))
+        select {
+        case <-time.After(wait):
+        case <-ctx.Done():
+            return nil, err
+        }
+        delay *= 2
+    }
 }
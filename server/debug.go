@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HubStats is a snapshot of the hub's in-memory state, for spotting leaked
+// clients and backed-up channels.
+type HubStats struct {
+	Rooms          int            `json:"rooms"`
+	Clients        int            `json:"clients"`
+	Users          int            `json:"users"`
+	ClientsPerUser map[int64]int  `json:"clients_per_user"`
+	Streams        int            `json:"replay_streams"`
+	ParkedStreams  int            `json:"parked_streams"`
+	QueuedFrames   int            `json:"queued_frames"` // frames waiting in client send buffers
+	Backlogs       map[string]int `json:"channel_backlogs"`
+	Goroutines     int            `json:"goroutines"`
+}
+
+// collectStats runs on the hub goroutine, which owns the maps it reads.
+func (h *ChatHub) collectStats() HubStats {
+	stats := HubStats{
+		Rooms:          len(h.subscriptions),
+		Users:          len(h.clientsByUser),
+		ClientsPerUser: make(map[int64]int, len(h.clientsByUser)),
+		Backlogs: map[string]int{
+			"membership": len(h.membership),
+			"disconnect": len(h.disconnect),
+			"direct":     len(h.direct),
+			"control":    len(h.control),
+			"subscribe":  len(h.subscribe),
+			"refresh":    len(h.refresh),
+		},
+		Goroutines: runtime.NumGoroutine(),
+	}
+	for userID, clients := range h.clientsByUser {
+		stats.ClientsPerUser[userID] = len(clients)
+		stats.Clients += len(clients)
+		for client := range clients {
+			stats.QueuedFrames += len(client.send)
+		}
+	}
+	for _, streams := range h.streams {
+		stats.Streams += len(streams)
+		for _, stream := range streams {
+			if stream.client == nil {
+				stats.ParkedStreams++
+			}
+		}
+	}
+	return stats
+}
+
+// Stats asks the hub goroutine for a snapshot, failing if it doesn't answer
+// before ctx expires.
+func (h *ChatHub) Stats(ctx context.Context) (HubStats, error) {
+	reply := make(chan HubStats, 1)
+	select {
+	case h.stats <- reply:
+	case <-ctx.Done():
+		return HubStats{}, fmt.Errorf("chat hub unresponsive: %w", ctx.Err())
+	}
+	select {
+	case stats := <-reply:
+		return stats, nil
+	case <-ctx.Done():
+		return HubStats{}, fmt.Errorf("chat hub unresponsive: %w", ctx.Err())
+	}
+}
+
+// adminAccountsFromEnv reads ADMIN_USERNAME and ADMIN_PASSWORD. Without both
+// only session tokens with the admin role reach the admin endpoints.
+func adminAccountsFromEnv() gin.Accounts {
+	username := strings.TrimSpace(os.Getenv("ADMIN_USERNAME"))
+	password := os.Getenv("ADMIN_PASSWORD")
+	if username == "" || password == "" {
+		return nil
+	}
+	return gin.Accounts{username: password}
+}
+
+// registerDebugRoutes mounts net/http/pprof under /debug/pprof and hub
+// diagnostics at /debug/hub, behind admin auth.
+//
+// Responses:
+//  - 200 with the profile or HubStats
+//  - 401 without valid admin credentials
+//  - 403 for a session token without the admin role or an API key
+//    without the debug scope
+//  - 503 when the hub doesn't answer in time
+func registerDebugRoutes(r *gin.Engine, adminAuth gin.HandlerFunc, hub *ChatHub) {
+	debug := r.Group("/debug", adminAuth)
+	debug.GET("/hub", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+
+		stats, err := hub.Stats(ctx)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	})
+
+	profiles := func(c *gin.Context) {
+		switch strings.TrimPrefix(c.Param("profile"), "/") {
+		case "cmdline":
+			pprof.Cmdline(c.Writer, c.Request)
+		case "profile":
+			pprof.Profile(c.Writer, c.Request)
+		case "symbol":
+			pprof.Symbol(c.Writer, c.Request)
+		case "trace":
+			pprof.Trace(c.Writer, c.Request)
+		default:
+			// Index serves the listing and every named profile (goroutine,
+			// heap, ...) from the path.
+			pprof.Index(c.Writer, c.Request)
+		}
+	}
+	debug.GET("/pprof/*profile", profiles)
+	debug.POST("/pprof/*profile", profiles)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Once a week every user who hasn't turned the digest off is emailed the
+// upcoming events that best match their interests and their profile's age
+// and gender, nearest first among equals, and what they haven't read in their
+// chats. Users have no home location, so "near" means near the last event
+// they hosted or joined.
+
+const (
+	// defaultEmailDigestInterval controls how often due digests are looked for.
+	defaultEmailDigestInterval = time.Hour
+	// emailDigestPeriod is how long a user waits between digests.
+	emailDigestPeriod = 7 * 24 * time.Hour
+	// emailDigestBatchSize caps the digests sent per sweep, so a big backlog
+	// spreads over several.
+	emailDigestBatchSize = 50
+	// emailDigestEventLimit is how many events a digest lists.
+	emailDigestEventLimit = 5
+	// emailDigestRadiusKm drops events further than this from the user's
+	// origin, when both are known.
+	emailDigestRadiusKm = 25.0
+	// emailDigestChatLimit is how many unread chats a digest names.
+	emailDigestChatLimit = 3
+)
+
+const createTableEmailDigestsSent = `
+CREATE TABLE IF NOT EXISTS email_digests_sent (
+    user_id INTEGER PRIMARY KEY,
+    sent_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+// selectDueDigestRecipients is completed with the condition for digest
+// emails.
+const selectDueDigestRecipients = `
+SELECT u.id, u.name, u.email
+FROM users u
+LEFT JOIN user_notification_settings ns ON ns.user_id = u.id
+LEFT JOIN email_digests_sent ds ON ds.user_id = u.id
+WHERE u.deleted_at IS NULL
+  AND %s
+  AND (ds.sent_at IS NULL OR ds.sent_at <= ?)
+ORDER BY u.id
+LIMIT ?;
+`
+
+const upsertEmailDigestSent = `
+INSERT INTO email_digests_sent (user_id, sent_at)
+VALUES (?, ?)
+ON CONFLICT(user_id) DO UPDATE SET sent_at = excluded.sent_at;
+`
+
+const deleteEmailDigestSentForUser = `
+DELETE FROM email_digests_sent
+WHERE user_id = ?;
+`
+
+// selectDigestOrigin finds where the user's last event with coordinates was,
+// hosted or joined.
+const selectDigestOrigin = `
+SELECT e.latitude, e.longitude
+FROM events e
+LEFT JOIN conversations c ON c.event_id = e.id
+LEFT JOIN conversation_members cm ON cm.conversation_id = c.id AND cm.user_id = ?
+WHERE (e.user_id = ? OR cm.user_id IS NOT NULL)
+  AND e.latitude IS NOT NULL AND e.longitude IS NOT NULL
+ORDER BY e.created_at DESC, e.id DESC
+LIMIT 1;
+`
+
+func (r *EventRepository) initEmailDigests(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEmailDigestsSent); err != nil {
+		return fmt.Errorf("create email digests table: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDigests returns up to limit users who get the digest and haven't
+// had one since emailDigestPeriod before now, and records them as sent, so a
+// failed delivery waits for next week rather than repeating.
+func (r *EventRepository) ClaimDueDigests(ctx context.Context, now time.Time, limit int) ([]emailRecipient, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin claim digests tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(selectDueDigestRecipients, notificationEnabledSQL(notifyDigest, notificationEmail))
+	rows, err := tx.QueryContext(ctx, query, sqliteTime(now.Add(-emailDigestPeriod)), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list due digests: %w", err)
+	}
+	var due []emailRecipient
+	for rows.Next() {
+		var recipient emailRecipient
+		if err := rows.Scan(&recipient.userID, &recipient.name, &recipient.email); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan due digest: %w", err)
+		}
+		due = append(due, recipient)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate due digests: %w", err)
+	}
+	rows.Close()
+
+	for _, recipient := range due {
+		if _, err := tx.ExecContext(ctx, upsertEmailDigestSent, recipient.userID, sqliteTime(now)); err != nil {
+			return nil, fmt.Errorf("record digest: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit claim digests: %w", err)
+	}
+	return due, nil
+}
+
+// GetDigestOrigin returns where the user's last hosted or joined event with
+// coordinates was, or nil if there isn't one.
+func (r *EventRepository) GetDigestOrigin(ctx context.Context, userID int64) (*Coordinates, error) {
+	var origin Coordinates
+	err := r.db.QueryRowContext(ctx, selectDigestOrigin, userID, userID).Scan(&origin.Latitude, &origin.Longitude)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch digest origin: %w", err)
+	}
+	return &origin, nil
+}
+
+// EmailDigestJob emails each user their weekly digest.
+type EmailDigestJob struct {
+	repo        *EventRepository
+	recommender *Recommender
+	mailer      EmailSender
+	interval    time.Duration
+}
+
+// newEmailDigestJob schedules the sweep from config's EMAIL_DIGEST_INTERVAL.
+func newEmailDigestJob(repo *EventRepository, mailer EmailSender, config JobsConfig) *EmailDigestJob {
+	return &EmailDigestJob{repo: repo, recommender: NewRecommender(repo), mailer: mailer, interval: config.EmailDigestInterval}
+}
+
+// Register schedules the sweep on runner every interval. Without email
+// configured there is nothing to send, so it isn't scheduled.
+func (j *EmailDigestJob) Register(runner *JobRunner) {
+	if j.mailer == nil {
+		return
+	}
+	runner.Register("email_digests", j.interval, j.sweep)
+}
+
+func (j *EmailDigestJob) sweep(ctx context.Context) error {
+	sweepCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	now := time.Now()
+	due, err := j.repo.ClaimDueDigests(sweepCtx, now, emailDigestBatchSize)
+	if err != nil {
+		return err
+	}
+	sent := 0
+	for _, recipient := range due {
+		data, err := j.compose(sweepCtx, recipient, now)
+		if err != nil {
+			log.Printf("compose digest for user %d failed: %v", recipient.userID, err)
+			continue
+		}
+		if data == nil {
+			continue
+		}
+		sendEmail(ctx, j.mailer, emailDigestEmail, recipient.email, *data)
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("sent %d email digests", sent)
+	}
+	return nil
+}
+
+// compose gathers the recipient's digest, or returns nil when there's
+// nothing worth sending.
+func (j *EmailDigestJob) compose(ctx context.Context, recipient emailRecipient, now time.Time) (*emailDigestData, error) {
+	origin, err := j.repo.GetDigestOrigin(ctx, recipient.userID)
+	if err != nil {
+		return nil, err
+	}
+	recommended, err := j.recommender.Recommend(ctx, recipient.userID, origin, 0)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := j.repo.GetUserProfile(ctx, recipient.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := emailDigestData{Name: recipient.name}
+	horizon := now.Add(emailDigestPeriod)
+	var picked []*Event
+	for i := range recommended {
+		evt := &recommended[i]
+		if evt.StartsAt != nil && evt.StartsAt.After(horizon) {
+			continue
+		}
+		if len(profile.Interests) > 0 && len(evt.MatchedTags) == 0 {
+			continue
+		}
+		if evt.DistanceKm != nil && *evt.DistanceKm > emailDigestRadiusKm {
+			continue
+		}
+		picked = append(picked, &evt.Event)
+		if len(picked) == emailDigestEventLimit {
+			break
+		}
+	}
+	if err := shapeEventLocations(ctx, j.repo, recipient.userID, picked...); err != nil {
+		return nil, err
+	}
+	for _, evt := range picked {
+		data.Events = append(data.Events, emailDigestEvent{
+			Title:    evt.Title,
+			When:     evt.DateLabel + " " + evt.Time,
+			Location: evt.Location,
+			Tags:     strings.Join(evt.Tags, ", "),
+		})
+	}
+
+	conversations, _, err := j.repo.ListConversations(ctx, recipient.userID, ConversationListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(conversations, func(a, b int) bool {
+		return conversations[a].UnreadCount > conversations[b].UnreadCount
+	})
+	for _, convo := range conversations {
+		if convo.UnreadCount == 0 {
+			break
+		}
+		data.UnreadTotal += convo.UnreadCount
+		if len(data.UnreadChats) < emailDigestChatLimit {
+			data.UnreadChats = append(data.UnreadChats, emailDigestChat{Title: digestChatTitle(convo, recipient.userID), Unread: convo.UnreadCount})
+		}
+	}
+
+	if len(data.Events) == 0 && data.UnreadTotal == 0 {
+		return nil, nil
+	}
+	return &data, nil
+}
+
+// digestChatTitle names a chat the way the app does: by its title, or by the
+// other people in it.
+func digestChatTitle(convo ConversationSummary, viewerID int64) string {
+	if convo.Title != nil && *convo.Title != "" {
+		return *convo.Title
+	}
+	var names []string
+	for _, participant := range convo.Participants {
+		if participant.ID != viewerID {
+			names = append(names, participant.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// emailTimeout bounds a single delivery attempt, connection included.
+const emailTimeout = 10 * time.Second
+
+// defaultSMTPPort is the submission port, which speaks STARTTLS.
+const defaultSMTPPort = "587"
+
+// EmailMessage is a rendered email with plain-text and HTML alternatives.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// EmailSender delivers emails. Implementations must be safe for concurrent
+// use.
+type EmailSender interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// newEmailSenderFromEnv picks a provider from EMAIL_PROVIDER: `smtp`, or
+// `console` to log emails instead of sending them in development. Unset
+// disables email and returns nil.
+func newEmailSenderFromEnv() (EmailSender, error) {
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_PROVIDER"))); provider {
+	case "":
+		return nil, nil
+	case "console":
+		return consoleEmailSender{}, nil
+	case "smtp":
+		host := strings.TrimSpace(os.Getenv("SMTP_HOST"))
+		if host == "" {
+			return nil, errors.New("SMTP_HOST is required for EMAIL_PROVIDER=smtp")
+		}
+		from, err := mail.ParseAddress(strings.TrimSpace(os.Getenv("EMAIL_FROM")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid EMAIL_FROM: %w", err)
+		}
+		port := strings.TrimSpace(os.Getenv("SMTP_PORT"))
+		if port == "" {
+			port = defaultSMTPPort
+		}
+		return &smtpEmailSender{
+			host:     host,
+			addr:     net.JoinHostPort(host, port),
+			username: strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+			password: os.Getenv("SMTP_PASSWORD"),
+			from:     from,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown EMAIL_PROVIDER %q", provider)
+	}
+}
+
+// consoleEmailSender logs the text part of each email, so flows like password
+// reset can be followed locally without a mail server.
+type consoleEmailSender struct{}
+
+func (consoleEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	log.Printf("email to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
+// smtpEmailSender submits mail to an SMTP relay, upgrading to TLS when the
+// server offers STARTTLS and authenticating when a username is set.
+type smtpEmailSender struct {
+	host     string
+	addr     string
+	username string
+	password string
+	from     *mail.Address
+}
+
+func (s *smtpEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	body, err := s.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send: %w", err)
+	}
+	return client.Quit()
+}
+
+// compose builds a multipart/alternative message with the text part first, so
+// clients that can render HTML pick the last one.
+func (s *smtpEmailSender) compose(msg EmailMessage) ([]byte, error) {
+	var boundary [12]byte
+	if _, err := rand.Read(boundary[:]); err != nil {
+		return nil, fmt.Errorf("email boundary: %w", err)
+	}
+	mark := hex.EncodeToString(boundary[:])
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mark)
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", mark)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		fmt.Fprintf(&buf, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, fmt.Errorf("encode email body: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("encode email body: %w", err)
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", mark)
+	return buf.Bytes(), nil
+}
+
+// emailRecipient is a user an email is addressed to.
+type emailRecipient struct {
+	userID int64
+	name   string
+	email  string
+}
+
+// selectEmailRecipient is completed with the condition for the category being
+// emailed.
+const selectEmailRecipient = `
+SELECT u.id, u.name, u.email
+FROM users u
+LEFT JOIN user_notification_settings ns ON ns.user_id = u.id
+WHERE u.id = ?
+  AND u.deleted_at IS NULL
+  AND %s;
+`
+
+// selectChatEmailRecipients is completed with the condition for the category
+// being emailed.
+const selectChatEmailRecipients = `
+SELECT u.id, u.name, u.email
+FROM conversation_members cm
+JOIN users u ON u.id = cm.user_id
+LEFT JOIN user_notification_settings ns ON ns.user_id = cm.user_id
+WHERE cm.conversation_id = ?
+  AND u.deleted_at IS NULL
+  AND %s;
+`
+
+// GetEmailRecipient returns where to email the user about category, or nil if
+// they turned those emails off or the account is gone.
+func (r *EventRepository) GetEmailRecipient(ctx context.Context, userID int64, category notificationCategory) (*emailRecipient, error) {
+	var recipient emailRecipient
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(selectEmailRecipient, notificationEnabledSQL(category, notificationEmail)), userID).
+		Scan(&recipient.userID, &recipient.name, &recipient.email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch email recipient: %w", err)
+	}
+	return &recipient, nil
+}
+
+// ListChatEmailRecipients returns the members of the chat who get emails
+// about category.
+func (r *EventRepository) ListChatEmailRecipients(ctx context.Context, conversationID int64, category notificationCategory) ([]emailRecipient, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectChatEmailRecipients, notificationEnabledSQL(category, notificationEmail)), conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list chat email recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []emailRecipient
+	for rows.Next() {
+		var recipient emailRecipient
+		if err := rows.Scan(&recipient.userID, &recipient.name, &recipient.email); err != nil {
+			return nil, fmt.Errorf("scan email recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate email recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// sendEmail renders tmpl for the recipient and delivers it, logging failures.
+func sendEmail(ctx context.Context, sender EmailSender, tmpl *emailTemplate, to string, data any) {
+	msg, err := tmpl.render(to, data)
+	if err != nil {
+		log.Printf("render %s email failed: %v", tmpl.name, err)
+		return
+	}
+	emailCtx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+	if err := sender.Send(emailCtx, msg); err != nil {
+		log.Printf("%s email delivery failed: %v", tmpl.name, err)
+	}
+}
+
+// emailJoinDecision tells a requester the host approved or denied them, if
+// they get join request emails.
+func (h *ChatHub) emailJoinDecision(ctx context.Context, req ConversationJoinRequest) {
+	if h.mailer == nil || (req.Status != "approved" && req.Status != "denied") {
+		return
+	}
+	recipient, err := h.repo.GetEmailRecipient(ctx, req.UserID, notifyJoinRequests)
+	if err != nil {
+		log.Printf("join decision email recipient lookup failed: %v", err)
+		return
+	}
+	if recipient == nil {
+		return
+	}
+	event, err := h.repo.GetEventByID(ctx, req.EventID)
+	if err != nil {
+		log.Printf("join decision email event lookup failed: %v", err)
+		return
+	}
+	sendEmail(ctx, h.mailer, joinDecisionEmail, recipient.email, joinDecisionEmailData{
+		Name:       recipient.name,
+		EventTitle: event.Title,
+		When:       event.DateLabel + " " + event.Time,
+		Location:   event.Location,
+		Approved:   req.Status == "approved",
+	})
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// Emails are rendered server-side from a subject, a plain-text body and an
+// HTML body sharing one layout, so every client gets the same wording.
+
+// emailTemplate is one kind of email.
+type emailTemplate struct {
+	name    string
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+const emailHTMLLayout = `<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f5f7;font-family:-apple-system,Helvetica,Arial,sans-serif;color:#1d1d1f;">
+<div style="max-width:480px;margin:0 auto;background:#ffffff;border-radius:12px;padding:24px;">
+{{template "content" .}}
+</div>
+<p style="max-width:480px;margin:16px auto 0;font-size:12px;color:#86868b;">Who Else Is Free · You can choose which emails you get in the app's notification settings.</p>
+</body>
+</html>`
+
+// newEmailTemplate parses one email's parts, panicking on a template error
+// like template.Must since they're all fixed at build time.
+func newEmailTemplate(name, subject, text, html string) *emailTemplate {
+	layout := htmltemplate.Must(htmltemplate.New(name).Parse(emailHTMLLayout))
+	htmltemplate.Must(layout.New("content").Parse(html))
+	return &emailTemplate{
+		name:    name,
+		subject: texttemplate.Must(texttemplate.New(name + "_subject").Parse(subject)),
+		text:    texttemplate.Must(texttemplate.New(name + "_text").Parse(text)),
+		html:    layout,
+	}
+}
+
+// render fills the template in for data.
+func (t *emailTemplate) render(to string, data any) (EmailMessage, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return EmailMessage{}, fmt.Errorf("render subject: %w", err)
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return EmailMessage{}, fmt.Errorf("render text: %w", err)
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return EmailMessage{}, fmt.Errorf("render html: %w", err)
+	}
+	return EmailMessage{To: to, Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
+
+// joinDecisionEmailData fills in joinDecisionEmail.
+type joinDecisionEmailData struct {
+	Name       string
+	EventTitle string
+	When       string
+	Location   string
+	Approved   bool
+}
+
+var joinDecisionEmail = newEmailTemplate("join_decision",
+	`{{if .Approved}}You're in: {{.EventTitle}}{{else}}Your request to join {{.EventTitle}}{{end}}`,
+	`Hi {{.Name}},
+
+{{if .Approved -}}
+The host approved your request to join "{{.EventTitle}}". It's on {{.When}} at {{.Location}}, and the event chat is open in the app.
+{{- else -}}
+The host couldn't take you for "{{.EventTitle}}" this time. There are plenty of other plans in the app to join.
+{{- end}}
+`,
+	`<p>Hi {{.Name}},</p>
+{{if .Approved}}<p>The host approved your request to join <strong>{{.EventTitle}}</strong>. It's on {{.When}} at {{.Location}}, and the event chat is open in the app.</p>
+{{else}}<p>The host couldn't take you for <strong>{{.EventTitle}}</strong> this time. There are plenty of other plans in the app to join.</p>
+{{end}}`)
+
+// eventCancelledEmailData fills in eventCancelledEmail.
+type eventCancelledEmailData struct {
+	Name         string
+	EventTitle   string
+	When         string
+	MinAttendees int
+}
+
+var eventCancelledEmail = newEmailTemplate("event_cancelled",
+	`Cancelled: {{.EventTitle}}`,
+	`Hi {{.Name}},
+
+"{{.EventTitle}}" on {{.When}} was cancelled because fewer than {{.MinAttendees}} people joined.
+`,
+	`<p>Hi {{.Name}},</p>
+<p><strong>{{.EventTitle}}</strong> on {{.When}} was cancelled because fewer than {{.MinAttendees}} people joined.</p>
+`)
+
+// passwordResetEmailData fills in passwordResetEmail.
+type passwordResetEmailData struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
+
+var passwordResetEmail = newEmailTemplate("password_reset",
+	`Reset your password`,
+	`Hi {{.Name}},
+
+Someone asked to reset the password for your account. If it was you, open this link within {{.ExpiresIn}} to choose a new one:
+
+{{.Link}}
+
+If it wasn't you, ignore this email; your password stays the same.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Someone asked to reset the password for your account. If it was you, open this link within {{.ExpiresIn}} to choose a new one:</p>
+<p><a href="{{.Link}}" style="display:inline-block;padding:10px 16px;background:#0071e3;color:#ffffff;border-radius:8px;text-decoration:none;">Reset password</a></p>
+<p>If it wasn't you, ignore this email; your password stays the same.</p>
+`)
+
+// emailDigestData fills in emailDigestEmail.
+type emailDigestData struct {
+	Name        string
+	Events      []emailDigestEvent
+	UnreadTotal int
+	UnreadChats []emailDigestChat
+}
+
+type emailDigestEvent struct {
+	Title    string
+	When     string
+	Location string
+	Tags     string
+}
+
+type emailDigestChat struct {
+	Title  string
+	Unread int
+}
+
+var emailDigestEmail = newEmailTemplate("digest",
+	`Your week: {{with .Events}}{{len .}} plans for you{{else}}{{.UnreadTotal}} unread messages{{end}}`,
+	`Hi {{.Name}},
+{{with .Events}}
+Plans coming up for you:
+{{range .}}
+- {{.Title}}, {{.When}} at {{.Location}}{{with .Tags}} ({{.}}){{end}}
+{{- end}}
+{{end}}{{if .UnreadTotal}}
+You have {{.UnreadTotal}} unread messages:
+{{range .UnreadChats}}
+- {{.Title}}: {{.Unread}}
+{{- end}}
+{{end}}
+Open the app to join in.
+`,
+	`<p>Hi {{.Name}},</p>
+{{with .Events}}<p>Plans coming up for you:</p>
+<ul>
+{{range .}}<li><strong>{{.Title}}</strong>, {{.When}} at {{.Location}}{{with .Tags}} <span style="color:#86868b;">({{.}})</span>{{end}}</li>
+{{end}}</ul>
+{{end}}{{if .UnreadTotal}}<p>You have {{.UnreadTotal}} unread messages:</p>
+<ul>
+{{range .UnreadChats}}<li>{{.Title}}: {{.Unread}}</li>
+{{end}}</ul>
+{{end}}<p>Open the app to join in.</p>
+`)
+
+// magicLinkEmailData fills in magicLinkEmail.
+type magicLinkEmailData struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
+
+var magicLinkEmail = newEmailTemplate("magic_link",
+	`Your login link`,
+	`Hi {{.Name}},
+
+Open this link within {{.ExpiresIn}} to sign in. It works once:
+
+{{.Link}}
+
+If you didn't ask to sign in, ignore this email.
+`,
+	`<p>Hi {{.Name}},</p>
+<p>Open this link within {{.ExpiresIn}} to sign in. It works once.</p>
+<p><a href="{{.Link}}" style="display:inline-block;padding:10px 16px;background:#0071e3;color:#ffffff;border-radius:8px;text-decoration:none;">Sign in</a></p>
+<p>If you didn't ask to sign in, ignore this email.</p>
+`)
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+// Deleting an event hides it everywhere at once but keeps its chat, locked,
+// for a grace period so members can still look up what was said. The purge
+// job then deletes the event for good, and its chat and messages with it.
+
+// eventStatusDeleted marks an event its host deleted and the purge job
+// hasn't removed yet. deleted_at says when.
+const eventStatusDeleted = "deleted"
+
+const (
+	// defaultEventPurgeInterval controls how often deleted events are purged.
+	defaultEventPurgeInterval = time.Hour
+	// defaultEventPurgeGrace is how long a deleted event's chat is kept.
+	defaultEventPurgeGrace = 7 * 24 * time.Hour
+)
+
+const createIndexEventsDeletedAt = `
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events(deleted_at) WHERE deleted_at IS NOT NULL;
+`
+
+const softDeleteEvent = `
+UPDATE events
+SET status = '` + eventStatusDeleted + `', deleted_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+`
+
+const selectPurgeableDeletedEvents = `
+SELECT id FROM events
+WHERE deleted_at IS NOT NULL AND deleted_at <= ?;
+`
+
+func (r *EventRepository) initEventDeletion(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "events", "deleted_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexEventsDeletedAt); err != nil {
+		return fmt.Errorf("create events deleted_at index: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeletedEvents removes the events deleted at or before cutoff, with
+// their chats, and returns how many there were.
+func (r *EventRepository) PurgeDeletedEvents(ctx context.Context, cutoff time.Time) (int, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin purge tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := maintenance.PurgeEvents(ctx, tx, selectPurgeableDeletedEvents, sqliteTime(cutoff))
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit purge: %w", err)
+	}
+	return n, nil
+}
+
+// EventPurgeJob removes deleted events, chats included, once their grace
+// period is over.
+type EventPurgeJob struct {
+	repo     *EventRepository
+	interval time.Duration
+	grace    time.Duration
+}
+
+// newEventPurgeJob schedules the purge from config's EVENT_PURGE_INTERVAL and
+// EVENT_PURGE_GRACE.
+func newEventPurgeJob(repo *EventRepository, config JobsConfig) *EventPurgeJob {
+	return &EventPurgeJob{repo: repo, interval: config.PurgeInterval, grace: config.PurgeGrace}
+}
+
+// Register schedules the purge on runner every interval.
+func (j *EventPurgeJob) Register(runner *JobRunner) {
+	runner.Register("event_purge", j.interval, j.purge)
+}
+
+func (j *EventPurgeJob) purge(ctx context.Context) error {
+	purgeCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	n, err := j.repo.PurgeDeletedEvents(purgeCtx, time.Now().Add(-j.grace))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("purged %d deleted events", n)
+	}
+	return nil
+}
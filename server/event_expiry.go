@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultEventExpiryInterval controls how often elapsed events are swept.
+const defaultEventExpiryInterval = time.Minute
+
+// defaultEventChatArchiveAfter is how long after an event starts its chat is
+// archived, provided nobody has written in it since.
+const defaultEventChatArchiveAfter = 72 * time.Hour
+
+// EventExpiryJob periodically flips events whose start time has elapsed to
+// `past` so they drop out of the default feed, archives their group chats
+// once they've gone quiet for archiveAfter, and locks them lockAfter the
+// start.
+type EventExpiryJob struct {
+	repo         *EventRepository
+	interval     time.Duration
+	archiveAfter time.Duration // 0 disables chat archiving
+	lockAfter    time.Duration // 0 disables chat locking
+}
+
+// newEventExpiryJob schedules the sweep from config's EVENT_EXPIRY_INTERVAL,
+// EVENT_CHAT_ARCHIVE_AFTER and EVENT_CHAT_LOCK_AFTER.
+func newEventExpiryJob(repo *EventRepository, config JobsConfig) *EventExpiryJob {
+	return &EventExpiryJob{repo: repo, interval: config.ExpiryInterval, archiveAfter: config.ChatArchiveAfter, lockAfter: config.ChatLockAfter}
+}
+
+// Register schedules the sweep on runner every interval.
+func (j *EventExpiryJob) Register(runner *JobRunner) {
+	runner.Register("event_expiry", j.interval, j.sweep)
+}
+
+func (j *EventExpiryJob) sweep(ctx context.Context) error {
+	sweepCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	now := time.Now()
+	expired, err := j.repo.ExpireEvents(sweepCtx, now)
+	if err != nil {
+		return fmt.Errorf("expire events: %w", err)
+	}
+	if len(expired) > 0 {
+		log.Printf("marked %d events as past", len(expired))
+	}
+
+	if j.archiveAfter > 0 {
+		archived, err := j.repo.ArchiveStaleEventChats(sweepCtx, now.Add(-j.archiveAfter))
+		if err != nil {
+			return fmt.Errorf("archive event chats: %w", err)
+		}
+		if len(archived) > 0 {
+			log.Printf("archived %d event chats", len(archived))
+		}
+	}
+
+	if j.lockAfter > 0 {
+		locked, err := j.repo.LockPastEventChats(sweepCtx, now.Add(-j.lockAfter))
+		if err != nil {
+			return err
+		}
+		if locked > 0 {
+			log.Printf("locked %d past event chats", locked)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// eventDeletionRetention is how long removed events are remembered for
+// updated_since syncs. Clients syncing from further back must refetch.
+const eventDeletionRetention = 7 * 24 * time.Hour
+
+const createTableEventDeletions = `
+CREATE TABLE IF NOT EXISTS event_deletions (
+    event_id INTEGER PRIMARY KEY,
+    deleted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const createIndexEventsUpdatedAt = `
+CREATE INDEX IF NOT EXISTS idx_events_updated_at ON events(updated_at);
+`
+
+const backfillEventUpdatedAt = `
+UPDATE events
+SET updated_at = COALESCE(datetime(created_at), CURRENT_TIMESTAMP)
+WHERE updated_at IS NULL;
+`
+
+// eventFeedTriggers keep events.updated_at current for everything the feed
+// shows: the row itself, the chat's member count, pending requests, bookmarks
+// and the host's name. Deleted events, soft deleted ones and those of deleted
+// accounts included, are logged in event_deletions. They are created after every migration that
+// rebuilds a table, since a rebuild would drop them.
+var eventFeedTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_insert
+AFTER INSERT ON events
+WHEN NEW.updated_at IS NULL
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;`,
+	// The trending job rewrites scores constantly; that isn't a change to
+	// the event. The first version of this trigger counted it as one.
+	`DROP TRIGGER IF EXISTS events_touch_on_update;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_edit
+AFTER UPDATE ON events
+WHEN NEW.updated_at IS OLD.updated_at AND NEW.score IS OLD.score
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_log_delete
+AFTER DELETE ON events
+BEGIN
+    INSERT OR REPLACE INTO event_deletions (event_id, deleted_at) VALUES (OLD.id, CURRENT_TIMESTAMP);
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_log_soft_delete
+AFTER UPDATE OF deleted_at ON events
+WHEN NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL
+BEGIN
+    INSERT OR REPLACE INTO event_deletions (event_id, deleted_at) VALUES (NEW.id, CURRENT_TIMESTAMP);
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_member_join
+AFTER INSERT ON conversation_members
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = (SELECT event_id FROM conversations WHERE id = NEW.conversation_id);
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_member_leave
+AFTER DELETE ON conversation_members
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = (SELECT event_id FROM conversations WHERE id = OLD.conversation_id);
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_request
+AFTER INSERT ON conversation_join_requests
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.event_id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_request_decision
+AFTER UPDATE OF status ON conversation_join_requests
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.event_id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_bookmark
+AFTER INSERT ON event_bookmarks
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.event_id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_unbookmark
+AFTER DELETE ON event_bookmarks
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = OLD.event_id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_touch_on_host_rename
+AFTER UPDATE OF name ON users
+BEGIN
+    UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE user_id = NEW.id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS events_log_host_deleted
+AFTER UPDATE OF deleted_at ON users
+WHEN NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL
+BEGIN
+    INSERT OR REPLACE INTO event_deletions (event_id, deleted_at)
+    SELECT id, CURRENT_TIMESTAMP FROM events WHERE user_id = NEW.id;
+END;`,
+}
+
+// selectEventFeedVersion summarises the feed for ETags: how many events match
+// and when any of them, or any deletion, last changed. It is completed with
+// the same filters as List.
+const selectEventFeedVersion = `
+SELECT COUNT(*), COALESCE(MAX(e.updated_at), ''), COALESCE((SELECT MAX(deleted_at) FROM event_deletions), ''), TOTAL(e.score)
+FROM events e
+JOIN users u ON u.id = e.user_id
+WHERE u.deleted_at IS NULL AND e.deleted_at IS NULL
+`
+
+const selectEventDeletionsSince = `
+SELECT event_id
+FROM event_deletions
+WHERE deleted_at >= ?
+ORDER BY event_id;
+`
+
+const deleteExpiredEventDeletions = `
+DELETE FROM event_deletions
+WHERE deleted_at < ?;
+`
+
+func (r *EventRepository) initEventFeed(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "events", "updated_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, backfillEventUpdatedAt); err != nil {
+		return fmt.Errorf("backfill event updated_at: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexEventsUpdatedAt); err != nil {
+		return fmt.Errorf("create events updated_at index: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableEventDeletions); err != nil {
+		return fmt.Errorf("create event deletions table: %w", err)
+	}
+	for _, trigger := range eventFeedTriggers {
+		if _, err := r.db.ExecContext(ctx, trigger); err != nil {
+			return fmt.Errorf("create event feed trigger: %w", err)
+		}
+	}
+	return nil
+}
+
+// EventFeedVersion identifies one state of the events feed for a viewer and
+// set of filters.
+type EventFeedVersion struct {
+	Count        int
+	LastModified time.Time // zero for an empty, never-changed feed
+	// ScoreTotal sums the trending scores, which change without touching
+	// LastModified.
+	ScoreTotal float64
+}
+
+// ETag returns a weak validator for the feed. The viewer, filters and sort
+// are folded in because the response depends on them, and so are the scores
+// when the feed is sorted by them.
+func (v EventFeedVersion) ETag(opts EventListOptions, updatedSince string) string {
+	scores := ""
+	if opts.Sort == eventSortTrending {
+		scores = strconv.FormatFloat(v.ScoreTotal, 'g', -1, 64)
+	}
+	origin := ""
+	if opts.Origin != nil {
+		origin = fmt.Sprintf("%g,%g", opts.Origin.Latitude, opts.Origin.Longitude)
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%s|%d|%t|%s|%s|%s|%s|%s",
+		v.Count,
+		v.LastModified.UTC().Format(time.RFC3339),
+		opts.ViewerID,
+		opts.IncludePast,
+		strings.Join(opts.Tags, ","),
+		updatedSince,
+		opts.Sort,
+		scores,
+		origin,
+	)))
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// GetEventFeedVersion computes the version of the feed List would return for
+// opts, in a single aggregate query.
+func (r *EventRepository) GetEventFeedVersion(ctx context.Context, opts EventListOptions) (EventFeedVersion, error) {
+	query, args := eventListFilters(selectEventFeedVersion, opts)
+
+	var version EventFeedVersion
+	var lastUpdated, lastDeleted string
+	if err := r.db.QueryRowContext(ctx, query+";", args...).Scan(&version.Count, &lastUpdated, &lastDeleted, &version.ScoreTotal); err != nil {
+		return EventFeedVersion{}, fmt.Errorf("fetch event feed version: %w", err)
+	}
+	latest := max(lastUpdated, lastDeleted)
+	if latest != "" {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", latest, time.UTC)
+		if err != nil {
+			return EventFeedVersion{}, fmt.Errorf("parse event feed version: %w", err)
+		}
+		version.LastModified = parsed
+	}
+	return version, nil
+}
+
+// ListEventChanges returns the events changed at or after since that match
+// opts, and the IDs of events that left the feed in that time: deleted, or
+// changed so they no longer match (e.g. they became past).
+func (r *EventRepository) ListEventChanges(ctx context.Context, opts EventListOptions, since time.Time) ([]Event, []int64, error) {
+	// The filters are applied afterwards so rows that stopped matching are
+	// reported as removed rather than silently left out.
+	changed, err := r.listEvents(ctx, selectEvents+" AND e.updated_at >= ? ORDER BY e.created_at DESC;", []any{sqliteTime(since)}, opts.ViewerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make([]Event, 0, len(changed))
+	removed := []int64{}
+	for _, evt := range changed {
+		if matchesEventListOptions(evt, opts) {
+			events = append(events, evt)
+		} else {
+			removed = append(removed, evt.ID)
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectEventDeletionsSince, sqliteTime(since))
+	if err != nil {
+		return nil, nil, fmt.Errorf("list event deletions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, nil, fmt.Errorf("scan event deletion: %w", err)
+		}
+		removed = append(removed, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate event deletions: %w", err)
+	}
+	return events, removed, nil
+}
+
+// PruneEventDeletions forgets deletions older than cutoff.
+func (r *EventRepository) PruneEventDeletions(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteExpiredEventDeletions, sqliteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("prune event deletions: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// matchesEventListOptions applies List's status and tag filters to one event.
+func matchesEventListOptions(evt Event, opts EventListOptions) bool {
+	if evt.Visibility != eventVisibilityPublic {
+		return false
+	}
+	if !opts.IncludePast && evt.Status != "active" {
+		return false
+	}
+	if len(opts.Tags) == 0 {
+		return true
+	}
+	for _, want := range opts.Tags {
+		for _, tag := range evt.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eventFeedNotModified reports whether the client's cached copy is current,
+// preferring If-None-Match over If-Modified-Since as HTTP requires.
+func eventFeedNotModified(ifNoneMatch, ifModifiedSince, etag string, lastModified time.Time) bool {
+	if ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := time.Parse(time.RFC1123, ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(since)
+}
+
+// registerEventDeletionPruning schedules daily cleanup of the deletion log.
+func registerEventDeletionPruning(runner *JobRunner, repo *EventRepository) {
+	runner.Register("event_deletion_prune", 24*time.Hour, func(ctx context.Context) error {
+		pruneCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+
+		_, err := repo.PruneEventDeletions(pruneCtx, time.Now().Add(-eventDeletionRetention))
+		return err
+	})
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// eventStatusCancelled marks an event called off for missing its minimum.
+// Its chat is locked. Editing the event makes both active again.
+const eventStatusCancelled = "cancelled"
+
+const (
+	// defaultMinAttendeesInterval controls how often events are checked
+	// against their minimum.
+	defaultMinAttendeesInterval = time.Minute
+	// defaultMinAttendeesCutoff is how long before the start an event must
+	// have reached its minimum.
+	defaultMinAttendeesCutoff = 2 * time.Hour
+)
+
+var ErrEventCancelled = errors.New("event was cancelled")
+
+// cancelUndersubscribedEvents cancels active events starting within the
+// cutoff whose chat, host included, is still smaller than min_attendees.
+const cancelUndersubscribedEvents = `
+UPDATE events AS e
+SET status = '` + eventStatusCancelled + `'
+WHERE e.status = 'active'
+  AND e.min_attendees IS NOT NULL
+  AND e.starts_at IS NOT NULL
+  AND e.starts_at > ? AND e.starts_at <= ?
+  AND ` + eventMemberCount + ` < e.min_attendees
+RETURNING id, user_id, title, date_label, time, min_attendees;
+`
+
+// selectChatPushTokens returns every member's devices, muted or not, for
+// notices too important to skip. Cancellations count as reminders, so members
+// who turned reminder pushes off don't get them.
+var selectChatPushTokens = `
+SELECT pt.token
+FROM conversation_members cm
+JOIN push_tokens pt ON pt.user_id = cm.user_id
+LEFT JOIN user_notification_settings ns ON ns.user_id = cm.user_id
+WHERE cm.conversation_id = ?
+  AND ` + notificationEnabledSQL(notifyReminders, notificationPush) + `;
+`
+
+func (r *EventRepository) initMinAttendees(ctx context.Context) error {
+	return r.ensureColumn(ctx, "events", "min_attendees", "INTEGER")
+}
+
+// cancelledEvent is an event the minimum check called off.
+type cancelledEvent struct {
+	eventID        int64
+	hostID         int64
+	title          string
+	when           string // date label and time, e.g. "Today 19:00"
+	minAttendees   int
+	conversationID int64 // 0 if the event has no chat
+}
+
+// CancelUndersubscribedEvents cancels the events that haven't reached their
+// minimum cutoff before they start, as of now, and returns them.
+func (r *EventRepository) CancelUndersubscribedEvents(ctx context.Context, now time.Time, cutoff time.Duration) ([]cancelledEvent, error) {
+	rows, err := r.db.QueryContext(ctx, cancelUndersubscribedEvents, sqliteTime(now), sqliteTime(now.Add(cutoff)))
+	if err != nil {
+		return nil, fmt.Errorf("cancel undersubscribed events: %w", err)
+	}
+	var cancelled []cancelledEvent
+	for rows.Next() {
+		var evt cancelledEvent
+		var dateLabel, startTime string
+		if err := rows.Scan(&evt.eventID, &evt.hostID, &evt.title, &dateLabel, &startTime, &evt.minAttendees); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan cancelled event: %w", err)
+		}
+		evt.when = dateLabel + " " + startTime
+		cancelled = append(cancelled, evt)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate cancelled events: %w", err)
+	}
+	rows.Close()
+
+	for i := range cancelled {
+		convo, err := r.GetConversationByEventID(ctx, cancelled[i].eventID)
+		if errors.Is(err, ErrConversationNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		cancelled[i].conversationID = convo.ID
+		if _, err := r.db.ExecContext(ctx, updateEventConversationLocked, true, cancelled[i].eventID); err != nil {
+			return nil, fmt.Errorf("lock cancelled event conversation: %w", err)
+		}
+	}
+	return cancelled, nil
+}
+
+// ListChatPushTokens returns the device tokens of every member of the chat
+// who gets reminder pushes.
+func (r *EventRepository) ListChatPushTokens(ctx context.Context, conversationID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, selectChatPushTokens, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list chat push tokens: %w", err)
+	}
+	return scanPushTokens(rows)
+}
+
+// MinAttendeesJob cancels events that are short of their host's minimum a
+// cutoff before they start, and tells the chat.
+type MinAttendeesJob struct {
+	repo     *EventRepository
+	hub      *ChatHub
+	interval time.Duration
+	cutoff   time.Duration
+}
+
+// newMinAttendeesJob schedules the sweep from config's
+// EVENT_MIN_ATTENDEES_INTERVAL and EVENT_MIN_ATTENDEES_CUTOFF.
+func newMinAttendeesJob(repo *EventRepository, hub *ChatHub, config JobsConfig) *MinAttendeesJob {
+	return &MinAttendeesJob{repo: repo, hub: hub, interval: config.MinAttendeesInterval, cutoff: config.MinAttendeesCutoff}
+}
+
+// Register schedules the sweep on runner every interval.
+func (j *MinAttendeesJob) Register(runner *JobRunner) {
+	runner.Register("event_min_attendees", j.interval, j.sweep)
+}
+
+func (j *MinAttendeesJob) sweep(ctx context.Context) error {
+	sweepCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	cancelled, err := j.repo.CancelUndersubscribedEvents(sweepCtx, time.Now(), j.cutoff)
+	if err != nil {
+		return err
+	}
+	for _, evt := range cancelled {
+		j.notify(sweepCtx, evt)
+	}
+	if len(cancelled) > 0 {
+		log.Printf("cancelled %d events short of their minimum", len(cancelled))
+	}
+	return nil
+}
+
+// notify posts the cancellation in the event chat and pushes and emails it to
+// every member, the host included, since nobody chose it.
+func (j *MinAttendeesJob) notify(ctx context.Context, evt cancelledEvent) {
+	if evt.conversationID == 0 {
+		return
+	}
+	body := fmt.Sprintf("\"%s\" was cancelled: it needed at least %d attendees", evt.title, evt.minAttendees)
+	j.hub.PostSystemMessage(ctx, evt.conversationID, evt.hostID, body)
+	j.email(ctx, evt)
+
+	if j.hub.pusher == nil {
+		return
+	}
+	tokens, err := j.repo.ListChatPushTokens(ctx, evt.conversationID)
+	if err != nil {
+		log.Printf("cancellation recipients lookup failed: %v", err)
+		return
+	}
+	if len(tokens) == 0 {
+		return
+	}
+	notification := PushNotification{
+		Title: evt.title,
+		Body:  fmt.Sprintf("Cancelled: fewer than %d people joined", evt.minAttendees),
+		Data: map[string]any{
+			"type":           "event:cancelled",
+			"eventId":        evt.eventID,
+			"conversationId": evt.conversationID,
+		},
+	}
+	pushCtx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+	if err := j.hub.pusher.Send(pushCtx, tokens, notification); err != nil {
+		log.Printf("cancellation push delivery failed: %v", err)
+	}
+}
+
+// email sends the cancellation to members who get reminder emails.
+func (j *MinAttendeesJob) email(ctx context.Context, evt cancelledEvent) {
+	if j.hub.mailer == nil {
+		return
+	}
+	recipients, err := j.repo.ListChatEmailRecipients(ctx, evt.conversationID, notifyReminders)
+	if err != nil {
+		log.Printf("cancellation email recipients lookup failed: %v", err)
+		return
+	}
+	for _, recipient := range recipients {
+		sendEmail(ctx, j.hub.mailer, eventCancelledEmail, recipient.email, eventCancelledEmailData{
+			Name:         recipient.name,
+			EventTitle:   evt.title,
+			When:         evt.when,
+			MinAttendees: evt.minAttendees,
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultEventReminderInterval controls how often upcoming events are checked
+// for due reminders.
+const defaultEventReminderInterval = time.Minute
+
+// reminderLead is one of the reminders sent ahead of an event. Column names
+// the user_reminder_settings flag that opts a user in or out of it.
+type reminderLead struct {
+	name   string
+	before time.Duration
+	column string
+	label  string
+}
+
+// reminderLeads are checked shortest first, so an event created less than an
+// hour out only gets the one-hour reminder.
+var reminderLeads = []reminderLead{
+	{name: "1h", before: time.Hour, column: "hour_before", label: "in 1 hour"},
+	{name: "24h", before: 24 * time.Hour, column: "day_before", label: "in 24 hours"},
+}
+
+const createTableEventRemindersSent = `
+CREATE TABLE IF NOT EXISTS event_reminders_sent (
+    event_id INTEGER NOT NULL,
+    lead TEXT NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, lead),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+`
+
+const createTableUserReminderSettings = `
+CREATE TABLE IF NOT EXISTS user_reminder_settings (
+    user_id INTEGER PRIMARY KEY,
+    day_before INTEGER NOT NULL DEFAULT 1,
+    hour_before INTEGER NOT NULL DEFAULT 1,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+// selectDueReminderEvents finds active event chats starting within the lead
+// window (after the shorter lead's window) that haven't had this reminder.
+const selectDueReminderEvents = `
+SELECT e.id, e.user_id, e.title, c.id
+FROM events e
+JOIN conversations c ON c.event_id = e.id
+WHERE e.status = 'active'
+  AND e.starts_at IS NOT NULL
+  AND e.starts_at > ? AND e.starts_at <= ?
+  AND NOT EXISTS (
+      SELECT 1 FROM event_reminders_sent rs
+      WHERE rs.event_id = e.id AND rs.lead = ?
+  );
+`
+
+const insertEventReminderSent = `
+INSERT OR IGNORE INTO event_reminders_sent (event_id, lead)
+VALUES (?, ?);
+`
+
+// selectReminderPushTokens is completed with the settings column for the lead.
+// Members without saved settings get every reminder, unless they turned
+// reminder pushes off altogether.
+var selectReminderPushTokens = `
+SELECT pt.token
+FROM conversation_members cm
+JOIN push_tokens pt ON pt.user_id = cm.user_id
+LEFT JOIN user_reminder_settings rs ON rs.user_id = cm.user_id
+LEFT JOIN user_notification_settings ns ON ns.user_id = cm.user_id
+WHERE cm.conversation_id = ?
+  AND COALESCE(rs.%s, 1) = 1
+  AND ` + notificationEnabledSQL(notifyReminders, notificationPush) + `;
+`
+
+const selectUserReminderSettings = `
+SELECT day_before, hour_before
+FROM user_reminder_settings
+WHERE user_id = ?;
+`
+
+const upsertUserReminderSettings = `
+INSERT INTO user_reminder_settings (user_id, day_before, hour_before, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(user_id) DO UPDATE SET
+    day_before = excluded.day_before,
+    hour_before = excluded.hour_before,
+    updated_at = CURRENT_TIMESTAMP;
+`
+
+const deleteUserReminderSettings = `
+DELETE FROM user_reminder_settings
+WHERE user_id = ?;
+`
+
+func (r *EventRepository) initEventReminders(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventRemindersSent); err != nil {
+		return fmt.Errorf("create event reminders table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableUserReminderSettings); err != nil {
+		return fmt.Errorf("create reminder settings table: %w", err)
+	}
+	return nil
+}
+
+// dueReminder is an event whose chat should hear about lead now.
+type dueReminder struct {
+	eventID        int64
+	hostID         int64
+	title          string
+	conversationID int64
+}
+
+// ClaimDueReminders returns the events due for a reminder at now and records
+// them as sent, so each reminder goes out once even if delivery fails.
+// Events starting within the shorter window are left to that reminder.
+func (r *EventRepository) ClaimDueReminders(ctx context.Context, lead reminderLead, shorter time.Duration, now time.Time) ([]dueReminder, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin claim reminders tx: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, selectDueReminderEvents, sqliteTime(now.Add(shorter)), sqliteTime(now.Add(lead.before)), lead.name)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("list due reminders: %w", err)
+	}
+	var due []dueReminder
+	for rows.Next() {
+		var reminder dueReminder
+		if err := rows.Scan(&reminder.eventID, &reminder.hostID, &reminder.title, &reminder.conversationID); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, fmt.Errorf("scan due reminder: %w", err)
+		}
+		due = append(due, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("iterate due reminders: %w", err)
+	}
+	rows.Close()
+
+	for _, reminder := range due {
+		if _, err := tx.ExecContext(ctx, insertEventReminderSent, reminder.eventID, lead.name); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("record reminder: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit claim reminders: %w", err)
+	}
+	return due, nil
+}
+
+// ListReminderPushTokens returns the device tokens of members of the chat who
+// want this reminder.
+func (r *EventRepository) ListReminderPushTokens(ctx context.Context, conversationID int64, lead reminderLead) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectReminderPushTokens, lead.column), conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list reminder push tokens: %w", err)
+	}
+	return scanPushTokens(rows)
+}
+
+// GetReminderSettings returns the user's reminder preferences; users who never
+// saved any get every reminder.
+func (r *EventRepository) GetReminderSettings(ctx context.Context, userID int64) (*ReminderSettings, error) {
+	settings := ReminderSettings{DayBefore: true, HourBefore: true}
+	err := r.db.QueryRowContext(ctx, selectUserReminderSettings, userID).Scan(&settings.DayBefore, &settings.HourBefore)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("fetch reminder settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SetReminderSettings replaces the user's reminder preferences.
+func (r *EventRepository) SetReminderSettings(ctx context.Context, userID int64, settings ReminderSettings) error {
+	if _, err := r.db.ExecContext(ctx, upsertUserReminderSettings, userID, settings.DayBefore, settings.HourBefore); err != nil {
+		return fmt.Errorf("save reminder settings: %w", err)
+	}
+	return nil
+}
+
+// EventReminderJob posts a reminder into each event chat 24 hours and 1 hour
+// before the event starts, and pushes it to members who haven't opted out.
+type EventReminderJob struct {
+	repo     *EventRepository
+	hub      *ChatHub
+	interval time.Duration
+}
+
+// newEventReminderJob schedules the sweep from config's EVENT_REMINDER_INTERVAL.
+func newEventReminderJob(repo *EventRepository, hub *ChatHub, config JobsConfig) *EventReminderJob {
+	return &EventReminderJob{repo: repo, hub: hub, interval: config.ReminderInterval}
+}
+
+// Register schedules the sweep on runner every interval.
+func (j *EventReminderJob) Register(runner *JobRunner) {
+	runner.Register("event_reminders", j.interval, j.sweep)
+}
+
+func (j *EventReminderJob) sweep(ctx context.Context) error {
+	sweepCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var shorter time.Duration
+	var errs []error
+	for _, lead := range reminderLeads {
+		due, err := j.repo.ClaimDueReminders(sweepCtx, lead, shorter, now)
+		shorter = lead.before
+		if err != nil {
+			errs = append(errs, fmt.Errorf("claim %s reminders: %w", lead.name, err))
+			continue
+		}
+		for _, reminder := range due {
+			j.remind(sweepCtx, reminder, lead)
+		}
+		if len(due) > 0 {
+			log.Printf("sent %d %s event reminders", len(due), lead.name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (j *EventReminderJob) remind(ctx context.Context, reminder dueReminder, lead reminderLead) {
+	body := fmt.Sprintf("Reminder: \"%s\" starts %s", reminder.title, lead.label)
+	j.hub.PostSystemMessage(ctx, reminder.conversationID, reminder.hostID, body)
+
+	if j.hub.pusher == nil {
+		return
+	}
+	tokens, err := j.repo.ListReminderPushTokens(ctx, reminder.conversationID, lead)
+	if err != nil {
+		log.Printf("reminder recipients lookup failed: %v", err)
+		return
+	}
+	if len(tokens) == 0 {
+		return
+	}
+	notification := PushNotification{
+		Title: reminder.title,
+		Body:  "Starts " + lead.label,
+		Data: map[string]any{
+			"type":           "event:reminder",
+			"eventId":        reminder.eventID,
+			"conversationId": reminder.conversationID,
+			"lead":           lead.name,
+		},
+	}
+	pushCtx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+	if err := j.hub.pusher.Send(pushCtx, tokens, notification); err != nil {
+		log.Printf("reminder push delivery failed: %v", err)
+	}
+}
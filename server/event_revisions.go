@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Every host edit that changes something is kept as a revision, for sorting
+// out disputes about what an event said and for debugging client sync. The
+// editor isn't a foreign key so history outlives their account.
+const createTableEventRevisions = `
+CREATE TABLE IF NOT EXISTS event_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL,
+    editor_id INTEGER NOT NULL,
+    changes TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_event_revisions_event ON event_revisions(event_id, id);
+`
+
+const insertEventRevision = `
+INSERT INTO event_revisions (event_id, editor_id, changes) VALUES (?, ?, ?);
+`
+
+const selectEventRevisions = `
+SELECT er.id, er.event_id, er.editor_id, COALESCE(u.name, ''), er.changes, er.created_at
+FROM event_revisions er
+LEFT JOIN users u ON u.id = er.editor_id
+WHERE er.event_id = ?
+ORDER BY er.id DESC;
+`
+
+func (r *EventRepository) initEventRevisions(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventRevisions); err != nil {
+		return fmt.Errorf("create event revisions table: %w", err)
+	}
+	return nil
+}
+
+// recordEventRevision stores an edit's changes within its transaction. An
+// edit that changed nothing isn't recorded.
+func recordEventRevision(ctx context.Context, tx *sql.Tx, eventID, editorID int64, changes []EventFieldChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("encode event revision: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, insertEventRevision, eventID, editorID, string(encoded)); err != nil {
+		return fmt.Errorf("insert event revision: %w", err)
+	}
+	return nil
+}
+
+// ListEventRevisions returns the event's edits, newest first, to its host.
+func (r *EventRepository) ListEventRevisions(ctx context.Context, eventID, hostID int64) ([]EventRevision, error) {
+	evt, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if evt.UserID != hostID {
+		return nil, ErrNotEventHost
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectEventRevisions, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("list event revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []EventRevision{}
+	for rows.Next() {
+		var revision EventRevision
+		var encoded string
+		if err := rows.Scan(&revision.ID, &revision.EventID, &revision.EditorID, &revision.EditorName, &encoded, &revision.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event revision: %w", err)
+		}
+		if err := json.Unmarshal([]byte(encoded), &revision.Changes); err != nil {
+			return nil, fmt.Errorf("decode event revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate event revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// getEventHistory returns the host's edits to the event, newest first.
+//
+// Responses:
+//  - 200 with `revisions`
+//  - 400 for invalid event id
+//  - 403 if the caller is not the event host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures
+func (h *EventHandler) getEventHistory(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	revisions, err := h.repo.ListEventRevisions(ctx, eventID, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can see its history"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch event history"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
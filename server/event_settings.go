@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Welcome templates can use these placeholders. A template without {name}
+// still mentions the new member, at the start.
+const (
+	welcomeNamePlaceholder  = "{name}"
+	welcomeEventPlaceholder = "{event}"
+)
+
+const createTableEventSettings = `
+CREATE TABLE IF NOT EXISTS event_settings (
+    event_id INTEGER PRIMARY KEY,
+    welcome_message TEXT,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+`
+
+const selectEventSettings = `
+SELECT welcome_message, updated_at FROM event_settings WHERE event_id = ?;
+`
+
+const upsertEventSettings = `
+INSERT INTO event_settings (event_id, welcome_message, updated_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(event_id) DO UPDATE SET
+    welcome_message = excluded.welcome_message,
+    updated_at = CURRENT_TIMESTAMP;
+`
+
+func (r *EventRepository) initEventSettings(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventSettings); err != nil {
+		return fmt.Errorf("create event settings table: %w", err)
+	}
+	return nil
+}
+
+// GetEventSettings returns the event's settings, or the defaults when the
+// host never saved any.
+func (r *EventRepository) GetEventSettings(ctx context.Context, eventID int64) (*EventSettings, error) {
+	settings := EventSettings{EventID: eventID}
+	var welcome sql.NullString
+	var updatedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, selectEventSettings, eventID).Scan(&welcome, &updatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("fetch event settings: %w", err)
+	}
+	if welcome.Valid {
+		settings.WelcomeMessage = welcome.String
+	}
+	if updatedAt.Valid {
+		settings.UpdatedAt = &updatedAt.Time
+	}
+	return &settings, nil
+}
+
+// UpdateEventSettings saves the host's settings for the event. An empty
+// welcome message turns welcomes off.
+func (r *EventRepository) UpdateEventSettings(ctx context.Context, eventID, hostID int64, params UpdateEventSettingsParams) (*EventSettings, error) {
+	if err := requireEventHost(ctx, r.db, eventID, hostID); err != nil {
+		return nil, err
+	}
+	welcome := sql.NullString{}
+	if trimmed := strings.TrimSpace(*params.WelcomeMessage); trimmed != "" {
+		welcome = sql.NullString{String: trimmed, Valid: true}
+	}
+	if _, err := r.db.ExecContext(ctx, upsertEventSettings, eventID, welcome); err != nil {
+		return nil, fmt.Errorf("save event settings: %w", err)
+	}
+	return r.GetEventSettings(ctx, eventID)
+}
+
+// renderWelcomeMessage fills in a welcome template for the member called
+// name, who is mentioned as `@name` so they get a `message:mention`.
+func renderWelcomeMessage(template, name, eventTitle string) string {
+	mention := "@" + name
+	if !strings.Contains(template, welcomeNamePlaceholder) {
+		template = mention + " " + template
+	}
+	return strings.NewReplacer(welcomeNamePlaceholder, mention, welcomeEventPlaceholder, eventTitle).Replace(template)
+}
+
+// WelcomeMember posts the event's welcome message, if the host set one, to
+// the event chat from the host, mentioning the member who just joined. The
+// member is already in, so failures are only logged.
+func (h *ChatHub) WelcomeMember(ctx context.Context, eventID, conversationID, userID int64) {
+	settings, err := h.repo.GetEventSettings(ctx, eventID)
+	if err != nil {
+		log.Printf("load welcome message for event %d failed: %v", eventID, err)
+		return
+	}
+	if settings.WelcomeMessage == "" {
+		return
+	}
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		log.Printf("load event %d for welcome message failed: %v", eventID, err)
+		return
+	}
+	names, err := h.repo.GetUserNames(ctx, []int64{userID})
+	if err != nil {
+		log.Printf("resolve name for welcome message failed: %v", err)
+		return
+	}
+
+	msg, err := h.repo.CreateMessage(ctx, CreateMessageParams{
+		ConversationID: conversationID,
+		SenderID:       event.UserID,
+		Body:           renderWelcomeMessage(settings.WelcomeMessage, names[userID], event.Title),
+		DeliveryStatus: "sent",
+	})
+	if err != nil {
+		log.Printf("post welcome message to conversation %d failed: %v", conversationID, err)
+		return
+	}
+	h.BroadcastMessage(*msg)
+	h.notifyMentions(*msg)
+}
+
+// getEventSettings returns the host's settings for the event.
+//
+// Responses:
+//  - 200 with `settings`
+//  - 401 if the caller has no session
+//  - 400 for invalid event id
+//  - 403 if the caller isn't the host
+//  - 404 if the event doesn't exist
+//  - 500 for repository/database failures
+func (h *EventHandler) getEventSettings(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		respondEventSettingsError(c, err)
+		return
+	}
+	if event.UserID != claims.UserID {
+		respondEventSettingsError(c, ErrNotEventHost)
+		return
+	}
+
+	settings, err := h.repo.GetEventSettings(ctx, eventID)
+	if err != nil {
+		respondEventSettingsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// updateEventSettings saves the host's settings for the event. The welcome
+// message is posted from the host whenever a join request is approved; it
+// may use {name} and {event}, and an empty one turns welcomes off.
+//
+// Responses:
+//  - 200 with the saved `settings`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or event id, or a welcome message over 500
+//    characters
+//  - 403 if the caller isn't the host
+//  - 404 if the event doesn't exist
+//  - 500 for repository/database failures
+func (h *EventHandler) updateEventSettings(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload UpdateEventSettingsParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	settings, err := h.repo.UpdateEventSettings(ctx, eventID, claims.UserID, payload)
+	if err != nil {
+		respondEventSettingsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+func respondEventSettingsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+	case errors.Is(err, ErrNotEventHost):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can manage event settings"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load event settings"})
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Event list sort orders for `?sort=`. newest is the default; recent is its
+// older name and still accepted.
+const (
+	eventSortNewest   = "newest"
+	eventSortRecent   = "recent"
+	eventSortSoonest  = "soonest"
+	eventSortClosest  = "closest"
+	eventSortPopular  = "popular"
+	eventSortTrending = "trending"
+)
+
+// newest and soonest read events in order from an index leading with
+// status, since the feed hides past events by default. closest and popular
+// are computed per row and sorted; popular looks up each event's chat
+// through idx_conversations_event.
+var eventSortIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_events_newest ON events(status, created_at DESC);`,
+	`CREATE INDEX IF NOT EXISTS idx_events_soonest ON events(status, starts_at IS NULL, starts_at, created_at DESC);`,
+	`CREATE INDEX IF NOT EXISTS idx_conversations_event ON conversations(event_id);`,
+}
+
+// eventMemberCount is the size of an event's chat, host included, as a
+// correlated subquery over events aliased `e`.
+const eventMemberCount = `
+(SELECT COUNT(1) FROM conversations c
+ JOIN conversation_members cm ON cm.conversation_id = c.id
+ WHERE c.event_id = e.id)`
+
+// eventDistance orders events by squared equirectangular distance from an
+// origin, which ranks like the real distance at feed scale. The placeholders
+// are the origin's latitude twice, longitude, the cosine of its latitude,
+// longitude and the cosine again. Events without coordinates sort last.
+const eventDistance = `
+e.latitude IS NULL OR e.longitude IS NULL,
+(e.latitude - ?) * (e.latitude - ?) + ((e.longitude - ?) * ?) * ((e.longitude - ?) * ?)`
+
+func (r *EventRepository) initEventSortIndexes(ctx context.Context) error {
+	for _, stmt := range eventSortIndexes {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create event sort index: %w", err)
+		}
+	}
+	return nil
+}
+
+// eventListOrder returns the ORDER BY clause for opts.Sort, and its
+// arguments. Ties fall back to newest first.
+func eventListOrder(opts EventListOptions) (string, []any) {
+	switch opts.Sort {
+	case eventSortSoonest:
+		return " ORDER BY e.starts_at IS NULL, e.starts_at ASC, e.created_at DESC;", nil
+	case eventSortClosest:
+		if opts.Origin == nil {
+			break
+		}
+		lat, lng := opts.Origin.Latitude, opts.Origin.Longitude
+		scale := math.Cos(lat * math.Pi / 180)
+		return " ORDER BY " + eventDistance + ", e.created_at DESC;", []any{lat, lat, lng, scale, lng, scale}
+	case eventSortPopular:
+		return " ORDER BY " + eventMemberCount + " DESC, e.created_at DESC;", nil
+	case eventSortTrending:
+		return " ORDER BY e.score DESC, e.created_at DESC;", nil
+	}
+	return " ORDER BY e.created_at DESC;", nil
+}
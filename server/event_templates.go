@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Hosts who run the same meetup again can duplicate a past event, or save it
+// as a template and create events from that later. Either way the new event
+// gets its own chat; only the event's own fields are copied.
+
+// maxEventTemplatesPerUser caps how many templates a host keeps.
+const maxEventTemplatesPerUser = 20
+
+var ErrEventTemplateNotFound = errors.New("event template not found")
+var ErrTooManyEventTemplates = errors.New("too many event templates")
+
+// The event's fields are kept as JSON so templates follow new event fields
+// without a migration; the place is kept in columns since EventPlace has no
+// JSON form.
+const createTableEventTemplates = `
+CREATE TABLE IF NOT EXISTS event_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    fields TEXT NOT NULL,
+    latitude REAL,
+    longitude REAL,
+    place_name TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_event_templates_user ON event_templates(user_id, id);
+`
+
+const insertEventTemplate = `
+INSERT INTO event_templates (user_id, name, fields, latitude, longitude, place_name)
+SELECT ?, ?, ?, ?, ?, ?
+WHERE (SELECT COUNT(1) FROM event_templates WHERE user_id = ?) < ?
+RETURNING id, created_at;
+`
+
+const selectEventTemplateColumns = `
+SELECT id, user_id, name, fields, latitude, longitude, place_name, created_at
+FROM event_templates
+`
+
+const deleteEventTemplate = `
+DELETE FROM event_templates WHERE id = ? AND user_id = ?;
+`
+
+func (r *EventRepository) initEventTemplates(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventTemplates); err != nil {
+		return fmt.Errorf("create event templates table: %w", err)
+	}
+	return nil
+}
+
+// eventTemplateFieldsFrom copies the reusable fields of evt, whose tags must
+// be attached.
+func eventTemplateFieldsFrom(evt *Event) EventTemplateFields {
+	fields := EventTemplateFields{
+		Title:             evt.Title,
+		Location:          evt.Location,
+		Time:              evt.Time,
+		Description:       evt.Description,
+		Gender:            evt.Gender,
+		MinAge:            evt.MinAge,
+		MaxAge:            evt.MaxAge,
+		DateLabel:         evt.DateLabel,
+		Capacity:          evt.Capacity,
+		MinAttendees:      evt.MinAttendees,
+		Tags:              evt.Tags,
+		StrictEligibility: evt.StrictEligibility,
+		Visibility:        evt.Visibility,
+		HideExactLocation: evt.HideExactLocation,
+	}
+	if evt.Area != nil {
+		fields.Area = *evt.Area
+	}
+	if evt.Latitude != nil && evt.Longitude != nil {
+		fields.Place = &EventPlace{Latitude: *evt.Latitude, Longitude: *evt.Longitude}
+		if evt.PlaceName != nil {
+			fields.Place.PlaceName = *evt.PlaceName
+		}
+	}
+	return fields
+}
+
+// createParams turns the fields into a new event for hostID, with the
+// overrides in params applied.
+func (f EventTemplateFields) createParams(hostID int64, params DuplicateEventParams) CreateEventParams {
+	create := CreateEventParams{
+		Title:             f.Title,
+		Location:          f.Location,
+		Time:              f.Time,
+		Description:       f.Description,
+		Gender:            f.Gender,
+		MinAge:            f.MinAge,
+		MaxAge:            f.MaxAge,
+		DateLabel:         f.DateLabel,
+		Capacity:          f.Capacity,
+		MinAttendees:      f.MinAttendees,
+		Tags:              f.Tags,
+		StrictEligibility: f.StrictEligibility,
+		Visibility:        f.Visibility,
+		HideExactLocation: f.HideExactLocation,
+		Area:              f.Area,
+		UserID:            hostID,
+		Place:             f.Place,
+	}
+	if title := strings.TrimSpace(params.Title); title != "" {
+		create.Title = title
+	}
+	if clock := strings.TrimSpace(params.Time); clock != "" {
+		create.Time = clock
+	}
+	if params.DateLabel != "" {
+		create.DateLabel = params.DateLabel
+	}
+	return create
+}
+
+// hostedEventFields loads the reusable fields of an event hostID hosts.
+func (r *EventRepository) hostedEventFields(ctx context.Context, eventID, hostID int64) (EventTemplateFields, error) {
+	evt, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return EventTemplateFields{}, err
+	}
+	if evt.UserID != hostID {
+		return EventTemplateFields{}, ErrNotEventHost
+	}
+	events := []Event{*evt}
+	if err := r.attachEventTags(ctx, events); err != nil {
+		return EventTemplateFields{}, err
+	}
+	return eventTemplateFieldsFrom(&events[0]), nil
+}
+
+// DuplicateEvent creates a copy of an event the host runs, with a fresh chat,
+// and returns its ID.
+func (r *EventRepository) DuplicateEvent(ctx context.Context, eventID, hostID int64, params DuplicateEventParams) (int64, error) {
+	fields, err := r.hostedEventFields(ctx, eventID, hostID)
+	if err != nil {
+		return 0, err
+	}
+	return r.Create(ctx, fields.createParams(hostID, params))
+}
+
+// CreateEventTemplate saves one of the host's events as a template.
+func (r *EventRepository) CreateEventTemplate(ctx context.Context, hostID int64, params CreateEventTemplateParams) (*EventTemplate, error) {
+	fields, err := r.hostedEventFields(ctx, params.EventID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encode event template: %w", err)
+	}
+	latitude, longitude, placeName := nullablePlace(fields.Place)
+
+	template := EventTemplate{UserID: hostID, Name: strings.TrimSpace(params.Name), Event: fields}
+	err = r.db.QueryRowContext(ctx, insertEventTemplate,
+		hostID, template.Name, string(encoded), latitude, longitude, placeName,
+		hostID, maxEventTemplatesPerUser,
+	).Scan(&template.ID, &template.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTooManyEventTemplates
+	}
+	if err != nil {
+		return nil, fmt.Errorf("insert event template: %w", err)
+	}
+	return &template, nil
+}
+
+func scanEventTemplate(scanner rowScanner) (EventTemplate, error) {
+	var (
+		template  EventTemplate
+		encoded   string
+		latitude  sql.NullFloat64
+		longitude sql.NullFloat64
+		placeName sql.NullString
+	)
+	if err := scanner.Scan(&template.ID, &template.UserID, &template.Name, &encoded, &latitude, &longitude, &placeName, &template.CreatedAt); err != nil {
+		return EventTemplate{}, err
+	}
+	if err := json.Unmarshal([]byte(encoded), &template.Event); err != nil {
+		return EventTemplate{}, fmt.Errorf("decode event template: %w", err)
+	}
+	if latitude.Valid && longitude.Valid {
+		template.Event.Place = &EventPlace{Latitude: latitude.Float64, Longitude: longitude.Float64, PlaceName: placeName.String}
+	}
+	return template, nil
+}
+
+// ListEventTemplates returns the user's templates, oldest first.
+func (r *EventRepository) ListEventTemplates(ctx context.Context, userID int64) ([]EventTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, selectEventTemplateColumns+"WHERE user_id = ? ORDER BY id;", userID)
+	if err != nil {
+		return nil, fmt.Errorf("list event templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []EventTemplate{}
+	for rows.Next() {
+		template, err := scanEventTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan event template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate event templates: %w", err)
+	}
+	return templates, nil
+}
+
+// CreateEventFromTemplate creates an event from one of the user's templates
+// and returns its ID.
+func (r *EventRepository) CreateEventFromTemplate(ctx context.Context, templateID, userID int64, params DuplicateEventParams) (int64, error) {
+	template, err := scanEventTemplate(r.db.QueryRowContext(ctx, selectEventTemplateColumns+"WHERE id = ? AND user_id = ?;", templateID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrEventTemplateNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("fetch event template: %w", err)
+	}
+	return r.Create(ctx, template.Event.createParams(userID, params))
+}
+
+// DeleteEventTemplate removes one of the user's templates.
+func (r *EventRepository) DeleteEventTemplate(ctx context.Context, templateID, userID int64) error {
+	res, err := r.db.ExecContext(ctx, deleteEventTemplate, templateID, userID)
+	if err != nil {
+		return fmt.Errorf("delete event template: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrEventTemplateNotFound
+	}
+	return nil
+}
+
+// bindDuplicateEventParams reads the optional overrides body.
+func bindDuplicateEventParams(c *gin.Context) (DuplicateEventParams, bool) {
+	var payload DuplicateEventParams
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return payload, false
+		}
+	}
+	return payload, true
+}
+
+// duplicateEvent creates a copy of the caller's event with a fresh chat. The
+// optional body can change the title, time and date_label.
+//
+// Responses:
+//  - 201 with the new event's `id`
+//  - 400 for invalid event id or body
+//  - 403 if the caller is not the event host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures
+func (h *EventHandler) duplicateEvent(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	payload, ok := bindDuplicateEventParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	id, err := h.repo.DuplicateEvent(ctx, eventID, claims.UserID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can duplicate the event"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to duplicate event"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// listEventTemplates returns the caller's event templates.
+//
+// Responses:
+//  - 200 with `data`
+//  - 500 for repository/database failures
+func (h *EventHandler) listEventTemplates(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	templates, err := h.repo.ListEventTemplates(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch event templates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": templates})
+}
+
+// createEventTemplate saves one of the caller's events as a template.
+//
+// Responses:
+//  - 201 with `template`
+//  - 400 for an invalid body
+//  - 403 if the caller is not the event host
+//  - 404 if the event is not found
+//  - 409 if the caller already has the maximum number of templates
+//  - 500 for repository/database failures
+func (h *EventHandler) createEventTemplate(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	var payload CreateEventTemplateParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	template, err := h.repo.CreateEventTemplate(ctx, claims.UserID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can save it as a template"})
+		case errors.Is(err, ErrTooManyEventTemplates):
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("you can keep at most %d event templates", maxEventTemplatesPerUser)})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save event template"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"template": template})
+}
+
+// createEventFromTemplate creates an event from one of the caller's
+// templates. The optional body can change the title, time and date_label.
+//
+// Responses:
+//  - 201 with the new event's `id`
+//  - 400 for invalid template id or body
+//  - 404 if the template is not found
+//  - 500 for repository/database failures
+func (h *EventHandler) createEventFromTemplate(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || templateID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+	payload, ok := bindDuplicateEventParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	id, err := h.repo.CreateEventFromTemplate(ctx, templateID, claims.UserID, payload)
+	if err != nil {
+		if errors.Is(err, ErrEventTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create event"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// deleteEventTemplate removes one of the caller's templates. Events created
+// from it are kept.
+//
+// Responses:
+//  - 204 on success
+//  - 400 for invalid template id
+//  - 404 if the template is not found
+//  - 500 for repository/database failures
+func (h *EventHandler) deleteEventTemplate(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || templateID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.DeleteEventTemplate(ctx, templateID, claims.UserID); err != nil {
+		if errors.Is(err, ErrEventTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete event template"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// EventFieldChange is one field a host edit changed, with the event's
+// before and after values.
+type EventFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// eventUpdatedEvent tells the event chat what an edit changed, so open
+// event screens can refresh without refetching.
+type eventUpdatedEvent struct {
+	Type           string             `json:"type"`
+	ConversationID int64              `json:"conversationId"`
+	EventID        int64              `json:"eventId"`
+	Changes        []EventFieldChange `json:"changes"`
+}
+
+// optionalInt unwraps a nullable count so changes serialize as a number or
+// null.
+func optionalInt(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// diffEventUpdate lists the fields params changes on before, in the order
+// members care about them. Fields params leaves as they are aren't listed.
+func diffEventUpdate(before *Event, params UpdateEventParams) []EventFieldChange {
+	var changes []EventFieldChange
+	add := func(field string, from, to any) {
+		if from != to {
+			changes = append(changes, EventFieldChange{Field: field, From: from, To: to})
+		}
+	}
+	add("title", before.Title, params.Title)
+	add("date_label", before.DateLabel, params.DateLabel)
+	add("time", before.Time, params.Time)
+	add("location", before.Location, params.Location)
+	add("description", before.Description, params.Description)
+	add("gender", before.Gender, params.Gender)
+	add("min_age", before.MinAge, params.MinAge)
+	add("max_age", before.MaxAge, params.MaxAge)
+	add("capacity", optionalInt(before.Capacity), optionalInt(params.Capacity))
+	add("min_attendees", optionalInt(before.MinAttendees), optionalInt(params.MinAttendees))
+	add("strict_eligibility", before.StrictEligibility, params.StrictEligibility)
+	if params.Visibility != "" {
+		add("visibility", before.Visibility, params.Visibility)
+	}
+	if params.HideExactLocation != nil {
+		add("hide_exact_location", before.HideExactLocation, *params.HideExactLocation)
+	}
+	if params.Area != nil {
+		var area string
+		if before.Area != nil {
+			area = *before.Area
+		}
+		add("area", area, strings.TrimSpace(*params.Area))
+	}
+	return changes
+}
+
+// describeEventChanges words changes as a system message from actor, naming
+// the title, time and location and summing up the rest, e.g. `Ava changed
+// the time to Tmrw 19:00 and the location to "Café Luna"`.
+func describeEventChanges(actor string, changes []EventFieldChange, after UpdateEventParams) string {
+	var parts []string
+	var timeChanged, others bool
+	for _, change := range changes {
+		switch change.Field {
+		case "title":
+			parts = append(parts, fmt.Sprintf("the title to \"%s\"", after.Title))
+		case "date_label", "time":
+			if !timeChanged {
+				parts = append(parts, fmt.Sprintf("the time to %s %s", after.DateLabel, after.Time))
+				timeChanged = true
+			}
+		case "location":
+			parts = append(parts, fmt.Sprintf("the location to \"%s\"", after.Location))
+		default:
+			others = true
+		}
+	}
+	if len(parts) == 0 {
+		return actor + " updated the event details"
+	}
+	summary := parts[0]
+	if len(parts) > 1 {
+		summary = strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+	}
+	if others {
+		return actor + " changed " + summary + ", and updated other details"
+	}
+	return actor + " changed " + summary
+}
+
+// BroadcastEventUpdate sends `event:updated` with the edit's changes to the
+// event chat.
+func (h *ChatHub) BroadcastEventUpdate(conversationID, eventID int64, changes []EventFieldChange) {
+	payload, err := json.Marshal(eventUpdatedEvent{Type: "event:updated", ConversationID: conversationID, EventID: eventID, Changes: changes})
+	if err != nil {
+		log.Printf("marshal event update failed: %v", err)
+		return
+	}
+	h.broadcast <- chatBroadcast{conversationID: conversationID, payload: payload}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Event visibility levels. Public events are listed in the feed. Link-only
+// events are left out of every list but open to anyone with the link or ID.
+// Private events are only visible to their host and chat members, and are
+// joined with an invite link rather than a join request.
+const (
+	eventVisibilityPublic   = "public"
+	eventVisibilityLinkOnly = "link_only"
+	eventVisibilityPrivate  = "private"
+)
+
+// eventListedClause keeps the events lists show, over events aliased `e`.
+const eventListedClause = " AND e.visibility = '" + eventVisibilityPublic + "'"
+
+func (r *EventRepository) initEventVisibility(ctx context.Context) error {
+	return r.ensureColumn(ctx, "events", "visibility", "TEXT NOT NULL DEFAULT '"+eventVisibilityPublic+"'")
+}
+
+// eventVisibilityOrDefault maps an omitted visibility to public.
+func eventVisibilityOrDefault(visibility string) string {
+	if visibility == "" {
+		return eventVisibilityPublic
+	}
+	return visibility
+}
+
+// CanViewEvent reports whether viewerID may see the event. Guests (viewerID
+// 0) only see events that aren't private.
+func (r *EventRepository) CanViewEvent(ctx context.Context, event *Event, viewerID int64) (bool, error) {
+	if event.Visibility != eventVisibilityPrivate || (viewerID > 0 && event.UserID == viewerID) {
+		return true, nil
+	}
+	if viewerID <= 0 {
+		return false, nil
+	}
+	convo, err := r.GetConversationByEventID(ctx, event.ID)
+	if errors.Is(err, ErrConversationNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	role, err := memberRole(ctx, r.db, convo.ID, viewerID)
+	if err != nil {
+		return false, fmt.Errorf("check event visibility: %w", err)
+	}
+	return role != "", nil
+}
+
+// GetVisibleEvent is GetEventByID for a viewer: a private event they can't
+// see is reported as ErrEventNotFound, so its existence isn't revealed.
+func (r *EventRepository) GetVisibleEvent(ctx context.Context, eventID, viewerID int64) (*Event, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := r.CanViewEvent(ctx, event, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrEventNotFound
+	}
+	return event, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNoGeocodeMatch = errors.New("no geocoding match")
+
+// geocodeTimeout bounds a single lookup so event writes are not held up by a
+// slow provider.
+const geocodeTimeout = 3 * time.Second
+
+// EventPlace is the resolved position of an event's free-text location.
+type EventPlace struct {
+	Latitude  float64
+	Longitude float64
+	PlaceName string
+}
+
+// Geocoder resolves free-text locations into coordinates and a normalized
+// place name. Implementations must be safe for concurrent use.
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*EventPlace, error)
+}
+
+// newGeocoderFromEnv picks a provider from GEOCODER (`nominatim` or `google`).
+// Unset disables geocoding and returns nil.
+func newGeocoderFromEnv() (Geocoder, error) {
+	client := &http.Client{Timeout: geocodeTimeout}
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("GEOCODER"))); provider {
+	case "":
+		return nil, nil
+	case "nominatim":
+		baseURL := strings.TrimSpace(os.Getenv("NOMINATIM_URL"))
+		if baseURL == "" {
+			baseURL = "https://nominatim.openstreetmap.org"
+		}
+		userAgent := strings.TrimSpace(os.Getenv("NOMINATIM_USER_AGENT"))
+		if userAgent == "" {
+			userAgent = "who-else-is-free-server"
+		}
+		return &nominatimGeocoder{client: client, baseURL: strings.TrimRight(baseURL, "/"), userAgent: userAgent}, nil
+	case "google":
+		apiKey := strings.TrimSpace(os.Getenv("GOOGLE_GEOCODING_API_KEY"))
+		if apiKey == "" {
+			return nil, errors.New("GOOGLE_GEOCODING_API_KEY is required for the google geocoder")
+		}
+		return &googleGeocoder{client: client, apiKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown GEOCODER %q", provider)
+	}
+}
+
+// nominatimGeocoder queries an OpenStreetMap Nominatim instance. The public
+// instance requires an identifying User-Agent and at most one request/second.
+type nominatimGeocoder struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+}
+
+func (g *nominatimGeocoder) Geocode(ctx context.Context, query string) (*EventPlace, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build nominatim request: %w", err)
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nominatim status %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("decode nominatim response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, errNoGeocodeMatch
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse nominatim latitude: %w", err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse nominatim longitude: %w", err)
+	}
+	return &EventPlace{Latitude: lat, Longitude: lon, PlaceName: results[0].DisplayName}, nil
+}
+
+// googleGeocoder uses the Google Maps Geocoding API.
+type googleGeocoder struct {
+	client *http.Client
+	apiKey string
+}
+
+func (g *googleGeocoder) Geocode(ctx context.Context, query string) (*EventPlace, error) {
+	params := url.Values{}
+	params.Set("address", query)
+	params.Set("key", g.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://maps.googleapis.com/maps/api/geocode/json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build google geocode request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("google geocode request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google geocode status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status  string `json:"status"`
+		Results []struct {
+			FormattedAddress string `json:"formatted_address"`
+			Geometry         struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode google geocode response: %w", err)
+	}
+	if body.Status == "ZERO_RESULTS" || len(body.Results) == 0 {
+		return nil, errNoGeocodeMatch
+	}
+	if body.Status != "OK" {
+		return nil, fmt.Errorf("google geocode status %s", body.Status)
+	}
+
+	result := body.Results[0]
+	return &EventPlace{
+		Latitude:  result.Geometry.Location.Lat,
+		Longitude: result.Geometry.Location.Lng,
+		PlaceName: result.FormattedAddress,
+	}, nil
+}
+
+// geocodeLocation resolves a location with the configured geocoder. Failures
+// are logged and yield nil so event writes still succeed without coordinates.
+func geocodeLocation(ctx context.Context, geocoder Geocoder, location string) *EventPlace {
+	if geocoder == nil || strings.TrimSpace(location) == "" {
+		return nil
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, geocodeTimeout)
+	defer cancel()
+
+	place, err := geocoder.Geocode(lookupCtx, location)
+	if err != nil {
+		if !errors.Is(err, errNoGeocodeMatch) {
+			log.Printf("geocode %q failed: %v", location, err)
+		}
+		return nil
+	}
+	return place
+}
@@ -0,0 +1,550 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// graphqlMaxDepth bounds nesting such as event.conversation.messages.sender.
+	graphqlMaxDepth = 8
+	// graphqlMaxParallelism caps concurrent resolvers per request, which is
+	// also how many lookups a loader can gather into one batch.
+	graphqlMaxParallelism = 16
+)
+
+var errGraphQLUnauthenticated = errors.New("authentication required")
+
+// graphqlSchema mirrors the REST models. Fields the viewer may not see, such
+// as another chat's roster, resolve to null rather than failing the query.
+const graphqlSchema = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	event(id: ID!): Event
+	events(includePast: Boolean = false, tags: [String!]): [Event!]!
+	# The viewer's conversations, most recently active first.
+	conversations(limit: Int = 20, cursor: String): ConversationPage!
+	conversation(id: ID!): Conversation
+}
+
+type User {
+	id: ID!
+	name: String!
+}
+
+type Event {
+	id: ID!
+	title: String!
+	# Only the host's area when the exact venue is hidden from the viewer.
+	location: String!
+	locationApproximate: Boolean!
+	time: String!
+	description: String!
+	gender: String!
+	minAge: Int!
+	maxAge: Int!
+	dateLabel: String!
+	status: String!
+	# public, link_only or private.
+	visibility: String!
+	capacity: Int
+	startsAt: Time
+	createdAt: Time!
+	tags: [String!]!
+	bookmarked: Boolean
+	memberCount: Int!
+	host: User!
+	# The chat roster; null unless the viewer is a member.
+	members: [EventMember!]
+	# The event chat; null unless the viewer is a member.
+	conversation: Conversation
+	# Requests in the given status; null unless the viewer hosts or co-hosts.
+	joinRequests(status: String = "pending"): [JoinRequest!]
+}
+
+type EventMember {
+	user: User!
+	role: String!
+	joinedAt: Time!
+}
+
+type JoinRequest {
+	id: ID!
+	user: User!
+	status: String!
+	note: String
+	ineligibleReason: String
+	createdAt: Time!
+}
+
+type ConversationPage {
+	nodes: [Conversation!]!
+	nextCursor: String
+}
+
+type Conversation {
+	id: ID!
+	title: String
+	createdAt: Time!
+	event: Event
+	participants: [User!]!
+	lastMessage: Message
+	unreadCount: Int!
+	# Newest first.
+	messages(limit: Int = 20, offset: Int = 0): [Message!]!
+}
+
+type Message {
+	id: ID!
+	sender: User!
+	body: String!
+	kind: String!
+	attachmentType: String
+	createdAt: Time!
+}
+`
+
+type graphqlContextKey struct{}
+
+// graphqlRequestContext is what every resolver reads from ctx.
+type graphqlRequestContext struct {
+	viewerID int64 // 0 for guests
+	loaders  *graphqlLoaders
+}
+
+func graphqlContext(ctx context.Context) *graphqlRequestContext {
+	return ctx.Value(graphqlContextKey{}).(*graphqlRequestContext)
+}
+
+func parseGraphQLID(id graphql.ID) (int64, bool) {
+	value, err := strconv.ParseInt(string(id), 10, 64)
+	return value, err == nil && value > 0
+}
+
+func graphqlIDOf(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
+
+type graphqlResolver struct {
+	repo Store
+}
+
+func (r *graphqlResolver) Event(ctx context.Context, args struct{ ID graphql.ID }) (*eventResolver, error) {
+	id, ok := parseGraphQLID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	evt, err := r.loadEvent(ctx, id)
+	if err != nil || evt == nil {
+		return nil, err
+	}
+	// Private events read as missing to anyone outside them.
+	viewerID := graphqlContext(ctx).viewerID
+	visible, err := r.repo.CanViewEvent(ctx, &evt.event, viewerID)
+	if err != nil || !visible {
+		return nil, err
+	}
+	if err := shapeEventLocations(ctx, r.repo, viewerID, &evt.event); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+func (r *graphqlResolver) loadEvent(ctx context.Context, id int64) (*eventResolver, error) {
+	evt, found, err := graphqlContext(ctx).loaders.events.Load(ctx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &eventResolver{repo: r.repo, event: evt}, nil
+}
+
+func (r *graphqlResolver) Events(ctx context.Context, args struct {
+	IncludePast bool
+	Tags        *[]string
+}) ([]*eventResolver, error) {
+	opts := EventListOptions{ViewerID: graphqlContext(ctx).viewerID, IncludePast: args.IncludePast}
+	if args.Tags != nil {
+		opts.Tags = *args.Tags
+	}
+	events, err := r.repo.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := shapeEventLocations(ctx, r.repo, opts.ViewerID, eventRefs(events)...); err != nil {
+		return nil, err
+	}
+	resolvers := make([]*eventResolver, len(events))
+	for i, evt := range events {
+		resolvers[i] = &eventResolver{repo: r.repo, event: evt}
+	}
+	return resolvers, nil
+}
+
+func (r *graphqlResolver) Conversations(ctx context.Context, args struct {
+	Limit  int32
+	Cursor *string
+}) (*conversationPageResolver, error) {
+	viewerID := graphqlContext(ctx).viewerID
+	if viewerID == 0 {
+		return nil, errGraphQLUnauthenticated
+	}
+	opts := ConversationListOptions{Limit: int(args.Limit)}
+	if args.Cursor != nil {
+		opts.Cursor = *args.Cursor
+	}
+	if opts.Limit <= 0 || opts.Limit > maxConversationPageSize {
+		opts.Limit = maxConversationPageSize
+	}
+	summaries, next, err := r.repo.ListConversations(ctx, viewerID, opts)
+	if err != nil {
+		return nil, err
+	}
+	page := &conversationPageResolver{nodes: make([]*conversationResolver, len(summaries))}
+	if next != "" {
+		page.nextCursor = &next
+	}
+	for i, summary := range summaries {
+		page.nodes[i] = &conversationResolver{repo: r.repo, summary: summary}
+	}
+	return page, nil
+}
+
+func (r *graphqlResolver) Conversation(ctx context.Context, args struct{ ID graphql.ID }) (*conversationResolver, error) {
+	id, ok := parseGraphQLID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	return r.loadConversation(ctx, id)
+}
+
+// loadConversation returns nil for conversations the viewer isn't in, the
+// same as for ones that don't exist.
+func (r *graphqlResolver) loadConversation(ctx context.Context, id int64) (*conversationResolver, error) {
+	viewerID := graphqlContext(ctx).viewerID
+	if viewerID == 0 {
+		return nil, errGraphQLUnauthenticated
+	}
+	isMember, err := r.repo.IsConversationMember(ctx, id, viewerID)
+	if err != nil || !isMember {
+		return nil, err
+	}
+	convo, err := r.repo.GetConversation(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	summary, err := r.repo.HydrateConversationSummary(ctx, *convo, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return &conversationResolver{repo: r.repo, summary: summary}, nil
+}
+
+type userResolver struct {
+	id   int64
+	name string
+}
+
+func (u *userResolver) ID() graphql.ID { return graphqlIDOf(u.id) }
+func (u *userResolver) Name() string   { return u.name }
+
+// loadUser resolves a user reference through the request's batch loader.
+func loadUser(ctx context.Context, id int64) (*userResolver, error) {
+	name, found, err := graphqlContext(ctx).loaders.users.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		// Deleted accounts keep their row; only a bad reference lands here.
+		name = "Unknown user"
+	}
+	return &userResolver{id: id, name: name}, nil
+}
+
+type eventResolver struct {
+	repo  Store
+	event Event
+}
+
+func (e *eventResolver) ID() graphql.ID      { return graphqlIDOf(e.event.ID) }
+func (e *eventResolver) Title() string       { return e.event.Title }
+func (e *eventResolver) Location() string    { return e.event.Location }
+func (e *eventResolver) LocationApproximate() bool {
+	return e.event.LocationApproximate
+}
+func (e *eventResolver) Time() string        { return e.event.Time }
+func (e *eventResolver) Description() string { return e.event.Description }
+func (e *eventResolver) Gender() string      { return e.event.Gender }
+func (e *eventResolver) MinAge() int32       { return int32(e.event.MinAge) }
+func (e *eventResolver) MaxAge() int32       { return int32(e.event.MaxAge) }
+func (e *eventResolver) DateLabel() string   { return e.event.DateLabel }
+func (e *eventResolver) Status() string      { return e.event.Status }
+func (e *eventResolver) Visibility() string  { return e.event.Visibility }
+func (e *eventResolver) MemberCount() int32  { return int32(e.event.MemberCount) }
+func (e *eventResolver) Bookmarked() *bool   { return e.event.Bookmarked }
+
+func (e *eventResolver) Tags() []string {
+	if e.event.Tags == nil {
+		return []string{}
+	}
+	return e.event.Tags
+}
+
+func (e *eventResolver) Capacity() *int32 {
+	if e.event.Capacity == nil {
+		return nil
+	}
+	capacity := int32(*e.event.Capacity)
+	return &capacity
+}
+
+func (e *eventResolver) StartsAt() *graphql.Time {
+	if e.event.StartsAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *e.event.StartsAt}
+}
+
+func (e *eventResolver) CreatedAt() graphql.Time { return graphql.Time{Time: e.event.CreatedAt} }
+
+func (e *eventResolver) Host() *userResolver {
+	return &userResolver{id: e.event.UserID, name: e.event.HostName}
+}
+
+func (e *eventResolver) Members(ctx context.Context) (*[]*eventMemberResolver, error) {
+	viewerID := graphqlContext(ctx).viewerID
+	if viewerID == 0 {
+		return nil, nil
+	}
+	members, err := e.repo.ListEventMembers(ctx, e.event.ID, viewerID)
+	if err != nil {
+		if errors.Is(err, ErrNotConversationMember) || errors.Is(err, ErrConversationNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	resolvers := make([]*eventMemberResolver, len(members))
+	for i := range members {
+		resolvers[i] = &eventMemberResolver{member: members[i]}
+	}
+	return &resolvers, nil
+}
+
+func (e *eventResolver) Conversation(ctx context.Context) (*conversationResolver, error) {
+	if graphqlContext(ctx).viewerID == 0 {
+		return nil, nil
+	}
+	convo, err := e.repo.GetConversationByEventID(ctx, e.event.ID)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return (&graphqlResolver{repo: e.repo}).loadConversation(ctx, convo.ID)
+}
+
+func (e *eventResolver) JoinRequests(ctx context.Context, args struct{ Status string }) (*[]*joinRequestResolver, error) {
+	viewerID := graphqlContext(ctx).viewerID
+	if viewerID == 0 {
+		return nil, nil
+	}
+	requests, err := e.repo.ListJoinRequests(ctx, e.event.ID, viewerID, args.Status)
+	if err != nil {
+		if errors.Is(err, ErrNotEventHost) || errors.Is(err, ErrConversationNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	resolvers := make([]*joinRequestResolver, len(requests))
+	for i := range requests {
+		resolvers[i] = &joinRequestResolver{request: requests[i]}
+	}
+	return &resolvers, nil
+}
+
+type eventMemberResolver struct {
+	member EventMember
+}
+
+func (m *eventMemberResolver) User() *userResolver {
+	return &userResolver{id: m.member.UserID, name: m.member.Name}
+}
+func (m *eventMemberResolver) Role() string           { return m.member.Role }
+func (m *eventMemberResolver) JoinedAt() graphql.Time { return graphql.Time{Time: m.member.JoinedAt} }
+
+type joinRequestResolver struct {
+	request ConversationJoinRequest
+}
+
+func (j *joinRequestResolver) ID() graphql.ID { return graphqlIDOf(j.request.ID) }
+func (j *joinRequestResolver) User(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, j.request.UserID)
+}
+func (j *joinRequestResolver) Status() string            { return j.request.Status }
+func (j *joinRequestResolver) Note() *string             { return j.request.Note }
+func (j *joinRequestResolver) IneligibleReason() *string { return j.request.IneligibleReason }
+func (j *joinRequestResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: j.request.CreatedAt}
+}
+
+type conversationPageResolver struct {
+	nodes      []*conversationResolver
+	nextCursor *string
+}
+
+func (p *conversationPageResolver) Nodes() []*conversationResolver { return p.nodes }
+func (p *conversationPageResolver) NextCursor() *string            { return p.nextCursor }
+
+type conversationResolver struct {
+	repo    Store
+	summary ConversationSummary
+}
+
+func (c *conversationResolver) ID() graphql.ID     { return graphqlIDOf(c.summary.ID) }
+func (c *conversationResolver) Title() *string     { return c.summary.Title }
+func (c *conversationResolver) UnreadCount() int32 { return int32(c.summary.UnreadCount) }
+func (c *conversationResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: c.summary.CreatedAt}
+}
+
+func (c *conversationResolver) Event(ctx context.Context) (*eventResolver, error) {
+	if c.summary.EventID == nil {
+		return nil, nil
+	}
+	return (&graphqlResolver{repo: c.repo}).loadEvent(ctx, *c.summary.EventID)
+}
+
+func (c *conversationResolver) Participants() []*userResolver {
+	users := make([]*userResolver, len(c.summary.Participants))
+	for i, p := range c.summary.Participants {
+		users[i] = &userResolver{id: p.ID, name: p.Name}
+	}
+	return users
+}
+
+func (c *conversationResolver) LastMessage() *messageResolver {
+	last := c.summary.LastMessage
+	if last == nil {
+		return nil
+	}
+	return &messageResolver{
+		id:             last.ID,
+		senderID:       last.SenderID,
+		body:           last.Body,
+		kind:           last.Kind,
+		attachmentType: last.AttachmentType,
+		createdAt:      last.CreatedAt,
+	}
+}
+
+// Messages doesn't move the viewer's read marker, unlike the REST listing.
+func (c *conversationResolver) Messages(ctx context.Context, args struct {
+	Limit  int32
+	Offset int32
+}) ([]*messageResolver, error) {
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxConversationPageSize {
+		limit = maxConversationPageSize
+	}
+	messages, err := c.repo.ListMessages(ctx, c.summary.ID, graphqlContext(ctx).viewerID, limit, int(args.Offset))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*messageResolver, len(messages))
+	for i, msg := range messages {
+		resolver := &messageResolver{id: msg.ID, senderID: msg.SenderID, body: msg.Body, kind: msg.Kind, createdAt: msg.CreatedAt}
+		if msg.AttachmentURL != nil {
+			resolver.attachmentType = attachmentType(*msg.AttachmentURL)
+		}
+		resolvers[i] = resolver
+	}
+	return resolvers, nil
+}
+
+type messageResolver struct {
+	id             int64
+	senderID       int64
+	body           string
+	kind           string
+	attachmentType string
+	createdAt      time.Time
+}
+
+func (m *messageResolver) ID() graphql.ID { return graphqlIDOf(m.id) }
+func (m *messageResolver) Body() string   { return m.body }
+func (m *messageResolver) Kind() string   { return m.kind }
+func (m *messageResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: m.createdAt}
+}
+
+func (m *messageResolver) Sender(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, m.senderID)
+}
+
+func (m *messageResolver) AttachmentType() *string {
+	if m.attachmentType == "" {
+		return nil
+	}
+	return &m.attachmentType
+}
+
+type graphqlRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// registerGraphQLRoute mounts POST /graphql on a group that resolves the
+// session optionally: guests can read events, while conversations need a
+// signed-in viewer.
+//
+// The body is a standard GraphQL request, `{"query", "operationName",
+// "variables"}`. User and event lookups made while resolving one request are
+// batched, so a list of events with their hosts costs a fixed number of
+// queries.
+//
+// Responses:
+//  - 200 with `data` and any resolver `errors`
+//  - 400 if the body has no query
+func registerGraphQLRoute(router *gin.RouterGroup, repo Store) {
+	schema := graphql.MustParseSchema(graphqlSchema, &graphqlResolver{repo: repo},
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxParallelism(graphqlMaxParallelism),
+	)
+
+	router.POST("/graphql", func(c *gin.Context) {
+		var req graphqlRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid GraphQL request"})
+			return
+		}
+
+		var viewerID int64
+		if claims, ok := sessionFromContext(c); ok {
+			viewerID = claims.UserID
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, graphqlContextKey{}, &graphqlRequestContext{
+			viewerID: viewerID,
+			loaders:  newGraphQLLoaders(ctx, repo, viewerID),
+		})
+
+		c.JSON(http.StatusOK, schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+	})
+}
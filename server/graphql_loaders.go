@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// loaderWait is how long a loader collects keys before querying. Resolvers
+// for sibling fields run concurrently, so their lookups land in one batch.
+const loaderWait = 2 * time.Millisecond
+
+// batchLoader merges the lookups a GraphQL request makes into one query per
+// batch and caches the results for the rest of the request.
+type batchLoader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(context.Context, []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	cache   map[K]*loaderResult[V]
+	pending []K
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+func newBatchLoader[K comparable, V any](ctx context.Context, fetch func(context.Context, []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{ctx: ctx, fetch: fetch, cache: make(map[K]*loaderResult[V])}
+}
+
+// Load returns the value for key; found is false when fetch had none.
+func (l *batchLoader[K, V]) Load(ctx context.Context, key K) (value V, found bool, err error) {
+	l.mu.Lock()
+	result, ok := l.cache[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.cache[key] = result
+		l.pending = append(l.pending, key)
+		if len(l.pending) == 1 {
+			time.AfterFunc(loaderWait, l.dispatch)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.found, result.err
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+}
+
+func (l *batchLoader[K, V]) dispatch() {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	results := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.cache[key]
+	}
+	l.mu.Unlock()
+
+	values, err := l.fetch(l.ctx, keys)
+	for i, key := range keys {
+		result := results[i]
+		result.value, result.found = values[key]
+		result.err = err
+		close(result.done)
+	}
+}
+
+// graphqlLoaders are built per request, so the cache never outlives the
+// viewer it was loaded for.
+type graphqlLoaders struct {
+	users  *batchLoader[int64, string]
+	events *batchLoader[int64, Event]
+}
+
+func newGraphQLLoaders(ctx context.Context, repo Store, viewerID int64) *graphqlLoaders {
+	return &graphqlLoaders{
+		users: newBatchLoader(ctx, repo.GetUserNames),
+		events: newBatchLoader(ctx, func(ctx context.Context, ids []int64) (map[int64]Event, error) {
+			return repo.GetEventsByIDs(ctx, ids, viewerID)
+		}),
+	}
+}
+
+// GetEventsByIDs loads the given events with tags and counts in one query,
+// keyed by ID. Missing events, and those of deleted hosts, are left out.
+func (r *EventRepository) GetEventsByIDs(ctx context.Context, ids []int64, viewerID int64) (map[int64]Event, error) {
+	if len(ids) == 0 {
+		return map[int64]Event{}, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	events, err := r.listEvents(ctx, selectEvents+fmt.Sprintf(" AND e.id IN (%s);", placeholders(len(ids))), args, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]Event, len(events))
+	for _, evt := range events {
+		byID[evt.ID] = evt
+	}
+	return byID, nil
+}
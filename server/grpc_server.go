@@ -0,0 +1,302 @@
+package main
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/whoelse/v1/whoelse.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	whoelsev1 "who-else-is-free-server/proto/whoelse/v1"
+)
+
+// GRPCConfig is the internal gRPC listener. Services authenticate with a
+// shared bearer token; there are no per-user sessions on this port.
+type GRPCConfig struct {
+	Addr  string // e.g. ":9090"
+	Token string
+}
+
+func (c GRPCConfig) enabled() bool {
+	return c.Addr != "" && c.Token != ""
+}
+
+// newGRPCConfigFromEnv reads GRPC_ADDR and GRPC_AUTH_TOKEN. Without both the
+// gRPC server doesn't start.
+func newGRPCConfigFromEnv() GRPCConfig {
+	config := GRPCConfig{
+		Addr:  strings.TrimSpace(os.Getenv("GRPC_ADDR")),
+		Token: strings.TrimSpace(os.Getenv("GRPC_AUTH_TOKEN")),
+	}
+	if config.Addr != "" && config.Token == "" {
+		log.Printf("GRPC_ADDR is set without GRPC_AUTH_TOKEN; gRPC server disabled")
+	}
+	return config
+}
+
+// serveGRPC listens on config.Addr in the background. A listener that can't
+// bind is fatal, like the HTTP one.
+func serveGRPC(config GRPCConfig, repo *EventRepository) {
+	listener, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		log.Fatalf("failed to listen for gRPC on %s: %v", config.Addr, err)
+	}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcTokenAuth(config.Token)))
+	whoelsev1.RegisterEventsServer(server, &grpcEventsService{repo: repo})
+	whoelsev1.RegisterConversationsServer(server, &grpcConversationsService{repo: repo})
+	whoelsev1.RegisterMessagesServer(server, &grpcMessagesService{repo: repo})
+
+	go func() {
+		log.Printf("serving gRPC on %s", config.Addr)
+		if err := server.Serve(listener); err != nil {
+			log.Printf("gRPC listener stopped: %v", err)
+		}
+	}()
+}
+
+// grpcTokenAuth rejects calls whose `authorization` metadata isn't
+// "Bearer <token>".
+func grpcTokenAuth(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var presented string
+		if values := md.Get("authorization"); len(values) > 0 {
+			presented = bearerTokenFromHeader(values[0])
+		}
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid service token")
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// grpcError maps repository errors onto status codes; anything unexpected is
+// logged and hidden behind Internal.
+func grpcError(err error, method string) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound), errors.Is(err, ErrConversationNotFound), errors.Is(err, ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidConversationCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	log.Printf("gRPC %s failed: %v", method, err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// grpcPageSize applies the REST default and cap to a request's limit.
+func grpcPageSize(limit int32) int {
+	if limit <= 0 {
+		return 20
+	}
+	if limit > maxConversationPageSize {
+		return maxConversationPageSize
+	}
+	return int(limit)
+}
+
+// grpcEventsService has no viewer, so events that hide their exact
+// location are shaped as they are for guests.
+type grpcEventsService struct {
+	whoelsev1.UnimplementedEventsServer
+	repo *EventRepository
+}
+
+func (s *grpcEventsService) GetEvent(ctx context.Context, req *whoelsev1.GetEventRequest) (*whoelsev1.Event, error) {
+	events, err := s.repo.GetEventsByIDs(ctx, []int64{req.GetId()}, 0)
+	if err != nil {
+		return nil, grpcError(err, "GetEvent")
+	}
+	evt, ok := events[req.GetId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, ErrEventNotFound.Error())
+	}
+	if err := shapeEventLocations(ctx, s.repo, 0, &evt); err != nil {
+		return nil, grpcError(err, "GetEvent")
+	}
+	return eventToProto(evt), nil
+}
+
+func (s *grpcEventsService) ListEvents(ctx context.Context, req *whoelsev1.ListEventsRequest) (*whoelsev1.ListEventsResponse, error) {
+	events, err := s.repo.List(ctx, EventListOptions{IncludePast: req.GetIncludePast(), Tags: req.GetTags()})
+	if err != nil {
+		return nil, grpcError(err, "ListEvents")
+	}
+	if err := shapeEventLocations(ctx, s.repo, 0, eventRefs(events)...); err != nil {
+		return nil, grpcError(err, "ListEvents")
+	}
+	resp := &whoelsev1.ListEventsResponse{Events: make([]*whoelsev1.Event, len(events))}
+	for i, evt := range events {
+		resp.Events[i] = eventToProto(evt)
+	}
+	return resp, nil
+}
+
+func (s *grpcEventsService) ListEventMembers(ctx context.Context, req *whoelsev1.ListEventMembersRequest) (*whoelsev1.ListEventMembersResponse, error) {
+	convo, err := s.repo.GetConversationByEventID(ctx, req.GetEventId())
+	if err != nil {
+		return nil, grpcError(err, "ListEventMembers")
+	}
+	members, err := s.repo.conversationRoster(ctx, convo.ID)
+	if err != nil {
+		return nil, grpcError(err, "ListEventMembers")
+	}
+	resp := &whoelsev1.ListEventMembersResponse{Members: make([]*whoelsev1.EventMember, len(members))}
+	for i, member := range members {
+		resp.Members[i] = &whoelsev1.EventMember{
+			UserId:   member.UserID,
+			Name:     member.Name,
+			Role:     member.Role,
+			JoinedAt: timestamppb.New(member.JoinedAt),
+		}
+	}
+	return resp, nil
+}
+
+type grpcConversationsService struct {
+	whoelsev1.UnimplementedConversationsServer
+	repo *EventRepository
+}
+
+func (s *grpcConversationsService) GetConversation(ctx context.Context, req *whoelsev1.GetConversationRequest) (*whoelsev1.Conversation, error) {
+	convo, err := s.repo.GetConversation(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcError(err, "GetConversation")
+	}
+	// Not HydrateConversationSummary: there's no viewer to count unread for.
+	_, memberIDs, err := fetchConversationParticipants(ctx, s.repo.db, convo.ID)
+	if err != nil {
+		return nil, grpcError(err, "GetConversation")
+	}
+	lastMessage, err := s.repo.fetchLatestMessage(ctx, convo.ID)
+	if err != nil {
+		return nil, grpcError(err, "GetConversation")
+	}
+	return conversationToProto(ConversationSummary{Conversation: *convo, MemberIDs: memberIDs, LastMessage: lastMessage}), nil
+}
+
+func (s *grpcConversationsService) ListConversations(ctx context.Context, req *whoelsev1.ListConversationsRequest) (*whoelsev1.ListConversationsResponse, error) {
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	summaries, next, err := s.repo.ListConversations(ctx, req.GetUserId(), ConversationListOptions{
+		Limit:  grpcPageSize(req.GetLimit()),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		return nil, grpcError(err, "ListConversations")
+	}
+	resp := &whoelsev1.ListConversationsResponse{
+		Conversations: make([]*whoelsev1.Conversation, len(summaries)),
+		NextCursor:    next,
+	}
+	for i, summary := range summaries {
+		resp.Conversations[i] = conversationToProto(summary)
+	}
+	return resp, nil
+}
+
+type grpcMessagesService struct {
+	whoelsev1.UnimplementedMessagesServer
+	repo *EventRepository
+}
+
+func (s *grpcMessagesService) ListMessages(ctx context.Context, req *whoelsev1.ListMessagesRequest) (*whoelsev1.ListMessagesResponse, error) {
+	if _, err := s.repo.GetConversation(ctx, req.GetConversationId()); err != nil {
+		return nil, grpcError(err, "ListMessages")
+	}
+	messages, err := s.repo.ListMessages(ctx, req.GetConversationId(), 0, grpcPageSize(req.GetLimit()), int(req.GetOffset()))
+	if err != nil {
+		return nil, grpcError(err, "ListMessages")
+	}
+	resp := &whoelsev1.ListMessagesResponse{Messages: make([]*whoelsev1.Message, len(messages))}
+	for i, msg := range messages {
+		resp.Messages[i] = &whoelsev1.Message{
+			Id:             msg.ID,
+			ConversationId: msg.ConversationID,
+			SenderId:       msg.SenderID,
+			Body:           msg.Body,
+			AttachmentUrl:  msg.AttachmentURL,
+			Kind:           msg.Kind,
+			CreatedAt:      timestamppb.New(msg.CreatedAt),
+		}
+	}
+	return resp, nil
+}
+
+func eventToProto(evt Event) *whoelsev1.Event {
+	out := &whoelsev1.Event{
+		Id:                evt.ID,
+		HostId:            evt.UserID,
+		HostName:          evt.HostName,
+		Title:             evt.Title,
+		Location:          evt.Location,
+		Time:              evt.Time,
+		Description:       evt.Description,
+		Gender:            evt.Gender,
+		MinAge:            int32(evt.MinAge),
+		MaxAge:            int32(evt.MaxAge),
+		DateLabel:         evt.DateLabel,
+		Status:            evt.Status,
+		StartsAt:          optionalTimestamp(evt.StartsAt),
+		CreatedAt:         timestamppb.New(evt.CreatedAt),
+		Latitude:          evt.Latitude,
+		Longitude:         evt.Longitude,
+		PlaceName:         evt.PlaceName,
+		Tags:              evt.Tags,
+		StrictEligibility: evt.StrictEligibility,
+		MemberCount:       int32(evt.MemberCount),
+	}
+	if evt.Capacity != nil {
+		capacity := int32(*evt.Capacity)
+		out.Capacity = &capacity
+	}
+	return out
+}
+
+func conversationToProto(summary ConversationSummary) *whoelsev1.Conversation {
+	out := &whoelsev1.Conversation{
+		Id:          summary.ID,
+		Title:       summary.Title,
+		CreatedBy:   summary.CreatedBy,
+		CreatedAt:   timestamppb.New(summary.CreatedAt),
+		EventId:     summary.EventID,
+		ArchivedAt:  optionalTimestamp(summary.ArchivedAt),
+		MemberIds:   summary.MemberIDs,
+		UnreadCount: int32(summary.UnreadCount),
+	}
+	if last := summary.LastMessage; last != nil {
+		out.LastMessage = &whoelsev1.Message{
+			Id:             last.ID,
+			ConversationId: summary.ID,
+			SenderId:       last.SenderID,
+			Body:           last.Body,
+			Kind:           last.Kind,
+			CreatedAt:      timestamppb.New(last.CreatedAt),
+		}
+	}
+	return out
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	whoelsev1 "who-else-is-free-server/proto/whoelse/v1"
+)
+
+// gRPC calls act for a service, not a member, so an event that hides its
+// exact location must come back approximated, like it does for a guest.
+func TestGRPCEventsHideExactLocation(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	hostID := mustCreateUser(t, repo, "ava")
+	id, err := repo.Create(ctx, CreateEventParams{
+		Title: "Board games", Location: "12 Elm Street, Flat 3", Time: "19:00", Gender: "Any",
+		MinAge: 18, MaxAge: 99, DateLabel: "Today", UserID: hostID,
+		HideExactLocation: true, Area: "Kreuzberg",
+	})
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	if _, err := repo.db.Exec(`UPDATE events SET latitude = 52.49874, longitude = 13.40321, place_name = 'Elm Street' WHERE id = ?`, id); err != nil {
+		t.Fatalf("set coordinates: %v", err)
+	}
+
+	assertApproximated := func(t *testing.T, evt *whoelsev1.Event) {
+		t.Helper()
+		if evt.GetLocation() != "Kreuzberg" {
+			t.Errorf("location = %q, want the area", evt.GetLocation())
+		}
+		if evt.PlaceName != nil {
+			t.Errorf("place name = %q, want none", evt.GetPlaceName())
+		}
+		if evt.GetLatitude() != 52.5 || evt.GetLongitude() != 13.4 {
+			t.Errorf("coordinates = %v,%v, want rounded to 52.5,13.4", evt.GetLatitude(), evt.GetLongitude())
+		}
+	}
+
+	service := &grpcEventsService{repo: repo}
+	t.Run("GetEvent", func(t *testing.T) {
+		evt, err := service.GetEvent(ctx, &whoelsev1.GetEventRequest{Id: id})
+		if err != nil {
+			t.Fatalf("get event: %v", err)
+		}
+		assertApproximated(t, evt)
+	})
+	t.Run("ListEvents", func(t *testing.T) {
+		resp, err := service.ListEvents(ctx, &whoelsev1.ListEventsRequest{})
+		if err != nil {
+			t.Fatalf("list events: %v", err)
+		}
+		if len(resp.GetEvents()) != 1 {
+			t.Fatalf("listed %d events, want 1", len(resp.GetEvents()))
+		}
+		assertApproximated(t, resp.GetEvents()[0])
+	})
+}
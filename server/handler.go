@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,28 +15,125 @@ import (
 const requestTimeout = 5 * time.Second
 
 type EventHandler struct {
-	repo *EventRepository
+	repo        Store
+	geocoder    Geocoder // nil disables geocoding
+	recommender *Recommender
+	hub         *ChatHub
 }
 
-func NewEventHandler(repo *EventRepository) *EventHandler {
-	return &EventHandler{repo: repo}
+func NewEventHandler(repo Store, geocoder Geocoder, hub *ChatHub) *EventHandler {
+	return &EventHandler{repo: repo, geocoder: geocoder, recommender: NewRecommender(repo), hub: hub}
 }
 
 func (h *EventHandler) RegisterRoutes(group *gin.RouterGroup) {
 	group.GET("/events", h.listEvents)
 	group.POST("/events", h.createEvent)
+	group.GET("/events/:id/reviews", h.listEventReviews)
+	group.GET("/users/:id/events", h.listHostEvents)
+	group.GET("/tags", h.listTags)
 }
 
 func (h *EventHandler) RegisterProtectedRoutes(group *gin.RouterGroup) {
 	group.PUT("/events/:id", h.updateEvent)
 	group.DELETE("/events/:id", h.deleteEvent)
+	group.POST("/events/:id/transfer", h.transferEvent)
+	group.GET("/events/:id/history", h.getEventHistory)
+	group.POST("/events/:id/duplicate", h.duplicateEvent)
+	group.GET("/event-templates", h.listEventTemplates)
+	group.POST("/event-templates", h.createEventTemplate)
+	group.POST("/event-templates/:id/events", h.createEventFromTemplate)
+	group.DELETE("/event-templates/:id", h.deleteEventTemplate)
+	group.GET("/events/:id/settings", h.getEventSettings)
+	group.PUT("/events/:id/settings", h.updateEventSettings)
+	group.POST("/events/:id/reviews", h.createEventReview)
+	group.GET("/events/bookmarked", h.listBookmarkedEvents)
+	group.GET("/events/recommended", h.listRecommendedEvents)
+	group.POST("/events/:id/bookmark", h.bookmarkEvent)
+	group.DELETE("/events/:id/bookmark", h.unbookmarkEvent)
 }
 
 func (h *EventHandler) listEvents(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
-	events, err := h.repo.List(ctx)
+	opts := EventListOptions{IncludePast: c.Query("include_past") == "true"}
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			opts.Tags = append(opts.Tags, tag)
+		}
+	}
+	if claims, ok := sessionFromContext(c); ok {
+		opts.ViewerID = claims.UserID
+	}
+	switch sort := c.Query("sort"); sort {
+	case "", eventSortNewest, eventSortRecent:
+	case eventSortSoonest, eventSortPopular, eventSortTrending:
+		opts.Sort = sort
+	case eventSortClosest:
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+		if latErr != nil || lngErr != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort=closest needs a valid lat and lng"})
+			return
+		}
+		opts.Sort = sort
+		opts.Origin = &Coordinates{Latitude: lat, Longitude: lng}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be newest, soonest, closest, popular or trending"})
+		return
+	}
+
+	rawSince := c.Query("updated_since")
+	var since time.Time
+	if rawSince != "" && opts.Sort == eventSortTrending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "updated_since can't be combined with sort=trending"})
+		return
+	}
+	if rawSince != "" {
+		parsed, err := time.Parse(time.RFC3339, rawSince)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "updated_since must be an RFC 3339 timestamp"})
+			return
+		}
+		if parsed.Before(time.Now().Add(-eventDeletionRetention)) {
+			c.JSON(http.StatusGone, gin.H{"error": "updated_since is too old; refetch the full feed"})
+			return
+		}
+		since = parsed
+	}
+
+	version, err := h.repo.GetEventFeedVersion(ctx, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch events"})
+		return
+	}
+	etag := version.ETag(opts, rawSince)
+	c.Header("ETag", etag)
+	if !version.LastModified.IsZero() {
+		c.Header("Last-Modified", version.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if eventFeedNotModified(c.GetHeader("If-None-Match"), c.GetHeader("If-Modified-Since"), etag, version.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if rawSince != "" {
+		events, removed, err := h.repo.ListEventChanges(ctx, opts, since)
+		if err == nil {
+			err = shapeEventLocations(ctx, h.repo, opts.ViewerID, eventRefs(events)...)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch events"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": events, "removed": removed})
+		return
+	}
+
+	events, err := h.repo.List(ctx, opts)
+	if err == nil {
+		err = shapeEventLocations(ctx, h.repo, opts.ViewerID, eventRefs(events)...)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch events"})
 		return
@@ -54,19 +153,73 @@ func (h *EventHandler) createEvent(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "max_age must be greater than or equal to min_age"})
 		return
 	}
+	if payload.MinAttendees != nil && payload.Capacity != nil && *payload.MinAttendees > *payload.Capacity {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_attendees can't be more than capacity"})
+		return
+	}
+
+	key, ok := normalizeIdempotencyKey(c.GetHeader(idempotencyKeyHeader))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+		return
+	}
+	// Keys belong to the signed-in caller. The body's user_id is anyone's to
+	// claim, so keying on it would let one client replay or burn another's
+	// keys; without a session the key is ignored.
+	claims, signedIn := sessionFromContext(c)
+	if !signedIn {
+		key = ""
+	}
+	if key != "" {
+		payload.IdempotencyKey = key
+		payload.IdempotencyUserID = claims.UserID
+	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
+	// A retried create returns the event the first attempt made.
+	if key != "" {
+		if h.replayCreatedEvent(ctx, c, claims.UserID, key) {
+			return
+		}
+	}
+
+	payload.Place = geocodeLocation(c.Request.Context(), h.geocoder, payload.Location)
+
 	id, err := h.repo.Create(ctx, payload)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create event"})
+		if errors.Is(err, ErrIdempotencyKeyUsed) && h.replayCreatedEvent(ctx, c, claims.UserID, key) {
+			return
+		}
+		if errors.Is(err, ErrUnknownTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create event"})
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
 
+// replayCreatedEvent answers a retried create with the original event's ID,
+// marked with an Idempotent-Replayed header. It reports whether it wrote a
+// response.
+func (h *EventHandler) replayCreatedEvent(ctx context.Context, c *gin.Context, userID int64, key string) bool {
+	id, found, err := h.repo.LookupIdempotencyKey(ctx, userID, idempotencyScopeEvent, key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create event"})
+		return true
+	}
+	if !found {
+		return false
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+	return true
+}
+
 func (h *EventHandler) updateEvent(c *gin.Context) {
 	var payload UpdateEventParams
 	if err := c.ShouldBindJSON(&payload); err != nil {
@@ -78,6 +231,10 @@ func (h *EventHandler) updateEvent(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "max_age must be greater than or equal to min_age"})
 		return
 	}
+	if payload.MinAttendees != nil && payload.Capacity != nil && *payload.MinAttendees > *payload.Capacity {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_attendees can't be more than capacity"})
+		return
+	}
 
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -93,19 +250,44 @@ func (h *EventHandler) updateEvent(c *gin.Context) {
 		return
 	}
 
+	// Reuse stored coordinates when the location text is unchanged so edits
+	// don't hit the geocoding provider needlessly.
+	existing, err := h.repo.GetEventByID(c.Request.Context(), id)
+	if err == nil && existing.Latitude != nil && strings.EqualFold(strings.TrimSpace(existing.Location), strings.TrimSpace(payload.Location)) {
+		payload.Place = &EventPlace{Latitude: *existing.Latitude, Longitude: *existing.Longitude}
+		if existing.PlaceName != nil {
+			payload.Place.PlaceName = *existing.PlaceName
+		}
+	} else {
+		payload.Place = geocodeLocation(c.Request.Context(), h.geocoder, payload.Location)
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
 	defer cancel()
 
-	err = h.repo.Update(ctx, id, claims.UserID, payload)
+	changes, err := h.repo.Update(ctx, id, claims.UserID, payload)
 	if err != nil {
 		if errors.Is(err, ErrEventNotFound) {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "event not found or not owned by user"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found or not owned by user"})
+		} else if errors.Is(err, ErrUnknownTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update event"})
 		}
 		return
 	}
 
+	// Let the event chat know what changed, so members aren't surprised by a
+	// new time or place. An edit that changes nothing stays quiet.
+	if len(changes) > 0 {
+		if convo, err := h.repo.GetConversationByEventID(ctx, id); err == nil {
+			if names, err := h.repo.GetUserNames(ctx, []int64{claims.UserID}); err == nil {
+				h.hub.PostSystemMessage(ctx, convo.ID, claims.UserID, describeEventChanges(names[claims.UserID], changes, payload))
+			}
+			h.hub.BroadcastEventUpdate(convo.ID, id, changes)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "event updated"})
 }
 
@@ -137,5 +319,189 @@ func (h *EventHandler) deleteEvent(c *gin.Context) {
 		return
 	}
 
+	// The chat stays a while, read-only, so tell its members why.
+	if convo, err := h.repo.GetConversationByEventID(ctx, id); err == nil {
+		h.hub.Announce(ctx, convo.ID, claims.UserID, "%s deleted the event. This chat is now read-only and will be removed soon.", claims.UserID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "event deleted"})
 }
+
+// transferEvent lets the host hand the event, and ownership of its chat, to
+// another chat member. The chat gets a system message about the handover.
+func (h *EventHandler) transferEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	claims, exists := sessionFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var payload TransferEventParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if payload.UserID == claims.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot transfer an event to yourself"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	convoID, err := h.repo.TransferEvent(ctx, id, claims.UserID, payload.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can transfer the event"})
+		case errors.Is(err, ErrNotConversationMember):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "new host must be a member of the event chat"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to transfer event"})
+		}
+		return
+	}
+
+	if names, err := h.repo.GetUserNames(ctx, []int64{claims.UserID, payload.UserID}); err == nil {
+		h.hub.PostSystemMessage(ctx, convoID, claims.UserID, fmt.Sprintf("%s handed hosting over to %s", names[claims.UserID], names[payload.UserID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "event transferred", "user_id": payload.UserID})
+}
+
+func (h *EventHandler) listTags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	tags, err := h.repo.ListTags(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+func (h *EventHandler) listRecommendedEvents(c *gin.Context) {
+	claims, exists := sessionFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	// Proximity only counts when the client shares its position.
+	var origin *Coordinates
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	if latErr == nil && lngErr == nil {
+		origin = &Coordinates{Latitude: lat, Longitude: lng}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		limit = defaultRecommendLimit
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	events, err := h.recommender.Recommend(ctx, claims.UserID, origin, limit)
+	if err == nil {
+		err = shapeRecommendedLocations(ctx, h.repo, claims.UserID, events)
+	}
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch recommendations"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+func (h *EventHandler) listBookmarkedEvents(c *gin.Context) {
+	claims, exists := sessionFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	events, err := h.repo.ListBookmarkedEvents(ctx, claims.UserID)
+	if err == nil {
+		err = shapeEventLocations(ctx, h.repo, claims.UserID, eventRefs(events)...)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bookmarked events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+func (h *EventHandler) bookmarkEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	claims, exists := sessionFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.BookmarkEvent(ctx, claims.UserID, id); err != nil {
+		if errors.Is(err, ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to bookmark event"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "event bookmarked"})
+}
+
+func (h *EventHandler) unbookmarkEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	claims, exists := sessionFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RemoveBookmark(ctx, claims.UserID, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove bookmark"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bookmark removed"})
+}
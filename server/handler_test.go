@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+const (
+	testHostID   int64 = 1
+	testMemberID int64 = 2
+	testOtherID  int64 = 3
+)
+
+// errStoreDown stands in for a database failure in the 500 cases.
+var errStoreDown = errors.New("store down")
+
+// newTestStore returns a StoreMock with the lookups most handlers make on
+// their way to a response: event 1 hosted by testHostID with a chat of the
+// same ID, user names, and system messages. Tests override the calls they
+// are about; any other call panics.
+func newTestStore() *StoreMock {
+	names := map[int64]string{testHostID: "Ava", testMemberID: "Liam", testOtherID: "Sophia"}
+	return &StoreMock{
+		GetEventByIDFunc: func(_ context.Context, eventID int64) (*Event, error) {
+			return &Event{ID: eventID, UserID: testHostID, Title: "Evening run", Location: "Riverside"}, nil
+		},
+		GetConversationByEventIDFunc: func(_ context.Context, eventID int64) (*Conversation, error) {
+			return &Conversation{ID: eventID, CreatedBy: testHostID, EventID: &eventID}, nil
+		},
+		GetUserNamesFunc: func(_ context.Context, userIDs []int64) (map[int64]string, error) {
+			found := make(map[int64]string, len(userIDs))
+			for _, id := range userIDs {
+				found[id] = names[id]
+			}
+			return found, nil
+		},
+		CreateSystemMessageFunc: func(_ context.Context, conversationID, actorID int64, body string) (*Message, error) {
+			return &Message{ID: 100, ConversationID: conversationID, SenderID: actorID, Body: body, Kind: "system", MessageType: messageTypeText, CreatedAt: time.Now()}, nil
+		},
+	}
+}
+
+// newTestHub returns a running hub over store with no sockets attached, so
+// handlers can post system messages.
+func newTestHub(store Store) *ChatHub {
+	hub := NewChatHub(store, nil, nil, nil, noopImageModerator{}, defaultChatConfig())
+	go hub.Run()
+	return hub
+}
+
+// serveAs sends one request through routes as the signed-in userID and
+// returns the recorded response.
+func serveAs(t *testing.T, routes func(*gin.RouterGroup), userID int64, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	group := router.Group("/api", func(c *gin.Context) {
+		c.Set(string(sessionContextKey), &sessionClaims{UserID: userID, Roles: []string{roleUser}})
+	})
+	routes(group)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func assertStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("status = %d, want %d; body %s", rec.Code, want, rec.Body.String())
+	}
+}
+
+func eventRoutes(store Store) func(*gin.RouterGroup) {
+	handler := NewEventHandler(store, nil, newTestHub(store))
+	return func(group *gin.RouterGroup) {
+		handler.RegisterRoutes(group)
+		handler.RegisterProtectedRoutes(group)
+	}
+}
+
+const validEventUpdate = `{"title":"Morning run","location":"Riverside","time":"7:00 AM","gender":"Any","min_age":18,"max_age":40,"date_label":"Tmrw"}`
+
+func TestUpdateEventStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		err  error
+		want int
+	}{
+		{"updated", "/api/events/1", validEventUpdate, nil, http.StatusOK},
+		{"missing fields", "/api/events/1", `{"title":"Morning run"}`, nil, http.StatusBadRequest},
+		{"ages reversed", "/api/events/1", `{"title":"Morning run","location":"Riverside","time":"7:00 AM","gender":"Any","min_age":40,"max_age":18,"date_label":"Tmrw"}`, nil, http.StatusBadRequest},
+		{"invalid id", "/api/events/abc", validEventUpdate, nil, http.StatusBadRequest},
+		{"unknown tag", "/api/events/1", validEventUpdate, ErrUnknownTag, http.StatusBadRequest},
+		{"missing or not hosted", "/api/events/1", validEventUpdate, ErrEventNotFound, http.StatusNotFound},
+		{"store failure", "/api/events/1", validEventUpdate, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.UpdateFunc = func(context.Context, int64, int64, UpdateEventParams) ([]EventFieldChange, error) {
+				return nil, tt.err
+			}
+			rec := serveAs(t, eventRoutes(store), testHostID, http.MethodPut, tt.path, tt.body)
+			assertStatus(t, rec, tt.want)
+			if tt.want == http.StatusBadRequest && tt.err == nil && len(store.UpdateCalls()) != 0 {
+				t.Fatal("Update called for an invalid request")
+			}
+		})
+	}
+}
+
+func TestDeleteEventStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"deleted", "/api/events/1", nil, http.StatusOK},
+		{"invalid id", "/api/events/abc", nil, http.StatusBadRequest},
+		{"missing or not hosted", "/api/events/1", ErrEventNotFound, http.StatusNotFound},
+		{"store failure", "/api/events/1", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.DeleteFunc = func(context.Context, int64, int64) error { return tt.err }
+			rec := serveAs(t, eventRoutes(store), testHostID, http.MethodDelete, tt.path, "")
+			assertStatus(t, rec, tt.want)
+
+			wantNotices := 0
+			if tt.want == http.StatusOK {
+				wantNotices = 1
+			}
+			if got := len(store.CreateSystemMessageCalls()); got != wantNotices {
+				t.Fatalf("system messages = %d, want %d", got, wantNotices)
+			}
+		})
+	}
+}
+
+func TestTransferEventStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		err  error
+		want int
+	}{
+		{"transferred", "/api/events/1/transfer", `{"user_id":2}`, nil, http.StatusOK},
+		{"invalid id", "/api/events/abc/transfer", `{"user_id":2}`, nil, http.StatusBadRequest},
+		{"missing user", "/api/events/1/transfer", `{}`, nil, http.StatusBadRequest},
+		{"to yourself", "/api/events/1/transfer", `{"user_id":1}`, nil, http.StatusBadRequest},
+		{"to a non-member", "/api/events/1/transfer", `{"user_id":3}`, ErrNotConversationMember, http.StatusBadRequest},
+		{"not the host", "/api/events/1/transfer", `{"user_id":2}`, ErrNotEventHost, http.StatusForbidden},
+		{"missing event", "/api/events/1/transfer", `{"user_id":2}`, ErrEventNotFound, http.StatusNotFound},
+		{"store failure", "/api/events/1/transfer", `{"user_id":2}`, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.TransferEventFunc = func(_ context.Context, eventID, _, _ int64) (int64, error) {
+				return eventID, tt.err
+			}
+			rec := serveAs(t, eventRoutes(store), testHostID, http.MethodPost, tt.path, tt.body)
+			assertStatus(t, rec, tt.want)
+			if tt.want == http.StatusOK {
+				calls := store.TransferEventCalls()
+				if len(calls) != 1 || calls[0].HostID != testHostID || calls[0].NewHostID != testMemberID {
+					t.Fatalf("TransferEvent calls = %+v", calls)
+				}
+			}
+		})
+	}
+}
+
+func TestListTagsStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"listed", nil, http.StatusOK},
+		{"store failure", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.ListTagsFunc = func(context.Context) ([]Tag, error) {
+				return []Tag{{ID: 1, Name: "Running"}}, tt.err
+			}
+			rec := serveAs(t, eventRoutes(store), testHostID, http.MethodGet, "/api/tags", "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
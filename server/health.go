@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds each readiness check, well inside the default
+// Kubernetes probe timeout.
+const readinessTimeout = 2 * time.Second
+
+// Ping checks the database answers queries against the schema, not just that
+// the connection opens: a locked or missing database fails here.
+func (r *EventRepository) Ping(ctx context.Context) error {
+	var one int
+	err := r.db.QueryRowContext(ctx, "SELECT 1 FROM users LIMIT 1;").Scan(&one)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+// registerHealthRoutes mounts the probes. /health is kept for existing
+// monitors and behaves like /health/live.
+//
+// Responses:
+//  - 200 from /health/live whenever the process is serving
+//  - 200 from /health/ready with each check "ok"
+//  - 503 from /health/ready with the failing checks' errors
+func registerHealthRoutes(r *gin.Engine, repo *EventRepository, hub *ChatHub) {
+	live := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+	r.GET("/health", live)
+	r.GET("/health/live", live)
+
+	r.GET("/health/ready", func(c *gin.Context) {
+		checks := map[string]func(context.Context) error{
+			"database": repo.Ping,
+			"chat_hub": hub.Ping,
+		}
+		results := make(gin.H, len(checks))
+		status, code := "ok", http.StatusOK
+		for name, check := range checks {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+			err := check(ctx)
+			cancel()
+			if err != nil {
+				results[name] = err.Error()
+				status, code = "unavailable", http.StatusServiceUnavailable
+				continue
+			}
+			results[name] = "ok"
+		}
+		c.JSON(code, gin.H{"status": status, "checks": results})
+	})
+}
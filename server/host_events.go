@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidEventCursor reports a `cursor` that ListHostEvents did not issue.
+var ErrInvalidEventCursor = errors.New("invalid event cursor")
+
+const (
+	defaultHostEventsPageSize = 20
+	// maxHostEventsPageSize caps `limit` on GET /users/:id/events.
+	maxHostEventsPageSize = 50
+)
+
+const createIndexEventsHost = `
+CREATE INDEX IF NOT EXISTS idx_events_host ON events(user_id, status, starts_at IS NULL, starts_at, id);
+`
+
+// hostEventsAfter continues a page after the cursor's event in soonest
+// order; the first form is for a dated event, the second for an undated one.
+const (
+	hostEventsAfterDated   = " AND (e.starts_at IS NULL OR e.starts_at > ? OR (e.starts_at = ? AND e.id > ?))"
+	hostEventsAfterUndated = " AND e.starts_at IS NULL AND e.id > ?"
+)
+
+func (r *EventRepository) initHostEvents(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createIndexEventsHost); err != nil {
+		return fmt.Errorf("create events host index: %w", err)
+	}
+	return nil
+}
+
+// eventCursor is the position after the last event of a page.
+type eventCursor struct {
+	startsAt string // sqliteTime of the start, empty for an undated event
+	id       int64
+}
+
+func (c eventCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.startsAt + "|" + strconv.FormatInt(c.id, 10)))
+}
+
+func decodeEventCursor(raw string) (*eventCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidEventCursor
+	}
+	startsAt, idPart, ok := strings.Cut(string(decoded), "|")
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if !ok || err != nil || id <= 0 {
+		return nil, ErrInvalidEventCursor
+	}
+	return &eventCursor{startsAt: startsAt, id: id}, nil
+}
+
+// ListHostEvents returns a page of the host's upcoming events, soonest first
+// with undated events last, and the cursor for the next page, empty on the
+// last one. Hosts viewing their own see the unlisted ones too.
+func (r *EventRepository) ListHostEvents(ctx context.Context, hostID, viewerID int64, limit int, cursor string) ([]Event, string, error) {
+	deleted, err := r.IsUserDeleted(ctx, hostID)
+	if err != nil {
+		return nil, "", err
+	}
+	if deleted {
+		return nil, "", ErrUserNotFound
+	}
+	after, err := decodeEventCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := selectEvents + " AND e.user_id = ? AND e.status = 'active'"
+	if viewerID != hostID {
+		query += eventListedClause
+	}
+	args := []any{hostID}
+	switch {
+	case after == nil:
+	case after.startsAt == "":
+		query += hostEventsAfterUndated
+		args = append(args, after.id)
+	default:
+		query += hostEventsAfterDated
+		args = append(args, after.startsAt, after.startsAt, after.id)
+	}
+	// One extra row tells whether another page follows.
+	query += " ORDER BY e.starts_at IS NULL, e.starts_at ASC, e.id ASC LIMIT ?;"
+	args = append(args, limit+1)
+
+	events, err := r.listEvents(ctx, query, args, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if events == nil {
+		events = []Event{}
+	}
+
+	var next string
+	if len(events) > limit {
+		events = events[:limit]
+		last := eventCursor{id: events[limit-1].ID}
+		if start := events[limit-1].StartsAt; start != nil {
+			last.startsAt = sqliteTime(*start)
+		}
+		next = last.encode()
+	}
+	return events, next, nil
+}
+
+// listHostEvents returns the host's upcoming events, for "More from this
+// host".
+//
+// Query params: `limit` (1-50, default 20) and `cursor`.
+// Responses:
+//  - 200 with `data` and `next_cursor` when more follow
+//  - 400 for an invalid user id, limit or cursor
+//  - 404 if the user doesn't exist or deleted their account
+//  - 500 for repository/database failures
+func (h *EventHandler) listHostEvents(c *gin.Context) {
+	hostID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || hostID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	limit := defaultHostEventsPageSize
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxHostEventsPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	var viewerID int64
+	if claims, ok := sessionFromContext(c); ok {
+		viewerID = claims.UserID
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	events, next, err := h.repo.ListHostEvents(ctx, hostID, viewerID, limit, c.Query("cursor"))
+	if err == nil {
+		err = shapeEventLocations(ctx, h.repo, viewerID, eventRefs(events)...)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidEventCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+		case errors.Is(err, ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch events"})
+		}
+		return
+	}
+
+	response := gin.H{"data": events}
+	if next != "" {
+		response["next_cursor"] = next
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamingPaths hold a connection open indefinitely, so they are exempt from
+// the server's read/write timeouts and from compression.
+var streamingPaths = []string{"/api/ws", "/api/events/stream"}
+
+// HTTPConfig holds the router's CORS, proxy and request size settings, and
+// the listener's timeouts.
+type HTTPConfig struct {
+	AllowOrigins   []string // "*" allows any origin
+	AllowHeaders   []string // request headers browsers may send
+	TrustedProxies []string // CIDRs or IPs whose X-Forwarded-For is believed; empty trusts none
+	MaxBodyBytes   int64    // largest request body, in bytes
+	MaxUploadBytes int64    // largest multipart upload, in bytes
+	MaxJSONDepth   int      // deepest object/array nesting accepted in JSON bodies
+
+	SigningSecrets []string // app keys public requests may be signed with; empty disables signing
+	RequireSigning bool     // reject unsigned public requests instead of letting them through
+
+	ReadHeaderTimeout time.Duration // time to read request headers; the slowloris guard
+	ReadTimeout       time.Duration // time to read a whole request, body included
+	WriteTimeout      time.Duration // time to write a response, from the end of the headers
+	IdleTimeout       time.Duration // how long a keep-alive connection may sit idle
+	MaxHeaderBytes    int           // largest request header block, in bytes
+}
+
+func defaultHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		AllowOrigins:   []string{"*"},
+		AllowHeaders:   []string{"Origin", "Content-Type", "Authorization", idempotencyKeyHeader, requestSignatureHeader, "If-None-Match", "If-Modified-Since"},
+		MaxBodyBytes:   defaultMaxBodyBytes,
+		MaxUploadBytes: defaultMaxUploadBytes,
+		MaxJSONDepth:   defaultMaxJSONDepth,
+
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    64 << 10,
+	}
+}
+
+// newHTTPConfigFromEnv overrides the defaults with CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_HEADERS and TRUSTED_PROXIES (comma-separated lists), and
+// REQUEST_BODY_LIMIT, UPLOAD_BODY_LIMIT and HTTP_MAX_HEADER_BYTES (bytes), and
+// HTTP_READ_HEADER_TIMEOUT, HTTP_READ_TIMEOUT, HTTP_WRITE_TIMEOUT and
+// HTTP_IDLE_TIMEOUT (Go durations), and REQUEST_SIGNING_SECRETS (a
+// comma-separated list) and REQUEST_SIGNING_REQUIRED. Invalid values are
+// logged and dropped.
+func newHTTPConfigFromEnv() HTTPConfig {
+	config := defaultHTTPConfig()
+	var origins []string
+	for _, origin := range envList("CORS_ALLOWED_ORIGINS") {
+		// The CORS middleware refuses to start on anything else.
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			log.Printf("invalid CORS_ALLOWED_ORIGINS entry %q; ignoring it", origin)
+			continue
+		}
+		origins = append(origins, origin)
+	}
+	if len(origins) > 0 {
+		config.AllowOrigins = origins
+	}
+	if headers := envList("CORS_ALLOWED_HEADERS"); len(headers) > 0 {
+		config.AllowHeaders = headers
+	}
+	for _, proxy := range envList("TRUSTED_PROXIES") {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			log.Printf("invalid TRUSTED_PROXIES entry %q; ignoring it", proxy)
+			continue
+		}
+		config.TrustedProxies = append(config.TrustedProxies, proxy)
+	}
+	config.MaxBodyBytes = int64(envPositiveInt("REQUEST_BODY_LIMIT", int(config.MaxBodyBytes), false))
+	config.MaxUploadBytes = int64(envPositiveInt("UPLOAD_BODY_LIMIT", int(config.MaxUploadBytes), false))
+	config.ReadHeaderTimeout = envPositiveDuration("HTTP_READ_HEADER_TIMEOUT", config.ReadHeaderTimeout)
+	config.ReadTimeout = envPositiveDuration("HTTP_READ_TIMEOUT", config.ReadTimeout)
+	config.WriteTimeout = envPositiveDuration("HTTP_WRITE_TIMEOUT", config.WriteTimeout)
+	config.IdleTimeout = envPositiveDuration("HTTP_IDLE_TIMEOUT", config.IdleTimeout)
+	config.MaxHeaderBytes = envPositiveInt("HTTP_MAX_HEADER_BYTES", config.MaxHeaderBytes, false)
+	config.SigningSecrets = envList("REQUEST_SIGNING_SECRETS")
+	config.RequireSigning = os.Getenv("REQUEST_SIGNING_REQUIRED") == "true"
+
+	if len(config.AllowOrigins) == 1 && config.AllowOrigins[0] == "*" {
+		log.Printf("CORS allows any origin; set CORS_ALLOWED_ORIGINS to restrict it")
+	}
+	return config
+}
+
+// envList splits a comma-separated variable, dropping blank entries.
+func envList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// newHTTPServer applies the configured timeouts and header limit to a
+// listener on addr.
+func newHTTPServer(addr string, config HTTPConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+		MaxHeaderBytes:    config.MaxHeaderBytes,
+	}
+}
+
+// streamingDeadlineMiddleware lifts the connection deadlines the server set
+// for streaming paths. WebSocket upgrades clear them anyway, but an SSE
+// stream would otherwise be cut at WriteTimeout, and the read deadline would
+// cancel its context when it fires.
+func streamingDeadlineMiddleware(paths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, path := range paths {
+			if c.Request.URL.Path != path {
+				continue
+			}
+			rc := http.NewResponseController(c.Writer)
+			if err := rc.SetReadDeadline(time.Time{}); err != nil {
+				log.Printf("clear read deadline for %s: %v", path, err)
+			}
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+				log.Printf("clear write deadline for %s: %v", path, err)
+			}
+			break
+		}
+		c.Next()
+	}
+}
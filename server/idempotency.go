@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	idempotencyScopeEvent   = "event"
+	idempotencyScopeMessage = "message"
+
+	// idempotencyKeyHeader is the request header clients retry creates with.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyKeyMaxLength bounds what we store per key.
+	idempotencyKeyMaxLength = 255
+	// idempotencyKeyRetention is how long a key keeps deduplicating retries.
+	idempotencyKeyRetention = 24 * time.Hour
+)
+
+// ErrIdempotencyKeyUsed reports that a concurrent request stored the same key
+// first; look the key up again to get its resource.
+var ErrIdempotencyKeyUsed = errors.New("idempotency key already used")
+
+const createTableIdempotencyKeys = `
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id INTEGER NOT NULL,
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    resource_id INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, scope, key)
+);
+`
+
+const selectIdempotencyKey = `
+SELECT resource_id
+FROM idempotency_keys
+WHERE user_id = ? AND scope = ? AND key = ? AND created_at > ?;
+`
+
+// insertIdempotencyKey replaces an expired row for the same key, but leaves a
+// live one alone so the caller can tell it lost a race.
+const insertIdempotencyKey = `
+INSERT INTO idempotency_keys (user_id, scope, key, resource_id, created_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(user_id, scope, key) DO UPDATE SET
+    resource_id = excluded.resource_id,
+    created_at = excluded.created_at
+WHERE idempotency_keys.created_at <= ?;
+`
+
+const deleteExpiredIdempotencyKeys = `
+DELETE FROM idempotency_keys
+WHERE created_at <= ?;
+`
+
+func (r *EventRepository) initIdempotencyKeys(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableIdempotencyKeys); err != nil {
+		return fmt.Errorf("create idempotency keys table: %w", err)
+	}
+	return nil
+}
+
+// normalizeIdempotencyKey trims a client key and reports whether it can be
+// stored. An empty key is valid and means no deduplication.
+func normalizeIdempotencyKey(raw string) (string, bool) {
+	key := strings.TrimSpace(raw)
+	return key, len(key) <= idempotencyKeyMaxLength
+}
+
+// LookupIdempotencyKey returns the resource a live key created, if any.
+func (r *EventRepository) LookupIdempotencyKey(ctx context.Context, userID int64, scope, key string) (int64, bool, error) {
+	var resourceID int64
+	cutoff := sqliteTime(time.Now().Add(-idempotencyKeyRetention))
+	err := r.db.QueryRowContext(ctx, selectIdempotencyKey, userID, scope, key, cutoff).Scan(&resourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("lookup idempotency key: %w", err)
+	}
+	return resourceID, true, nil
+}
+
+// recordIdempotencyKey ties key to the resource created in tx. It returns
+// ErrIdempotencyKeyUsed if a live row already holds the key.
+func recordIdempotencyKey(ctx context.Context, tx *sql.Tx, userID int64, scope, key string, resourceID int64) error {
+	cutoff := sqliteTime(time.Now().Add(-idempotencyKeyRetention))
+	res, err := tx.ExecContext(ctx, insertIdempotencyKey, userID, scope, key, resourceID, cutoff)
+	if err != nil {
+		return fmt.Errorf("record idempotency key: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("record idempotency key rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrIdempotencyKeyUsed
+	}
+	return nil
+}
+
+// PruneIdempotencyKeys deletes keys that stopped deduplicating at cutoff.
+func (r *EventRepository) PruneIdempotencyKeys(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteExpiredIdempotencyKeys, sqliteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("prune idempotency keys: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// registerIdempotencyKeyPruning schedules hourly cleanup of expired keys.
+func registerIdempotencyKeyPruning(runner *JobRunner, repo *EventRepository) {
+	runner.Register("idempotency_key_prune", time.Hour, func(ctx context.Context) error {
+		pruneCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+
+		pruned, err := repo.PruneIdempotencyKeys(pruneCtx, time.Now().Add(-idempotencyKeyRetention))
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Printf("pruned %d idempotency keys", pruned)
+		}
+		return nil
+	})
+}
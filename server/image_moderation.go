@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// imageModerationTimeout bounds one provider call so a slow provider can't
+// hold a send past requestTimeout.
+const imageModerationTimeout = 3 * time.Second
+
+// maxAttachmentURLLength caps the attachment URL a message may carry.
+const maxAttachmentURLLength = 2048
+
+var errInvalidAttachmentURL = errors.New("attachment must be an https image URL")
+
+// ImageVerdict is a moderator's decision on one image. Action is empty to
+// allow it, or one of the filter actions (flag, hide, reject).
+type ImageVerdict struct {
+	Action string
+	Reason string
+}
+
+// ImageModerator checks an image before its URL is stored with a message.
+// Implementations must be safe for concurrent use.
+type ImageModerator interface {
+	ModerateImage(ctx context.Context, imageURL string) (ImageVerdict, error)
+}
+
+// newImageModeratorFromEnv uses the provider at IMAGE_MODERATION_URL, with
+// IMAGE_MODERATION_API_KEY as its bearer token. Unset allows every image.
+func newImageModeratorFromEnv() (ImageModerator, error) {
+	endpoint := strings.TrimSpace(os.Getenv("IMAGE_MODERATION_URL"))
+	if endpoint == "" {
+		return noopImageModerator{}, nil
+	}
+	if parsed, err := url.Parse(endpoint); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("IMAGE_MODERATION_URL %q must be an http(s) URL", endpoint)
+	}
+	return &httpImageModerator{
+		client:   &http.Client{Timeout: imageModerationTimeout},
+		endpoint: endpoint,
+		apiKey:   strings.TrimSpace(os.Getenv("IMAGE_MODERATION_API_KEY")),
+	}, nil
+}
+
+// noopImageModerator allows every image; it stands in when no provider is
+// configured.
+type noopImageModerator struct{}
+
+func (noopImageModerator) ModerateImage(context.Context, string) (ImageVerdict, error) {
+	return ImageVerdict{}, nil
+}
+
+// httpImageModerator asks an external provider. It POSTs
+// {"image_url": "..."} and expects {"action": "allow"|"flag"|"hide"|"reject",
+// "reason": "..."} back; the provider fetches the image itself.
+type httpImageModerator struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+}
+
+func (m *httpImageModerator) ModerateImage(ctx context.Context, imageURL string) (ImageVerdict, error) {
+	body, err := json.Marshal(map[string]string{"image_url": imageURL})
+	if err != nil {
+		return ImageVerdict{}, fmt.Errorf("encode image moderation request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return ImageVerdict{}, fmt.Errorf("build image moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return ImageVerdict{}, fmt.Errorf("image moderation request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ImageVerdict{}, fmt.Errorf("image moderation status %d", resp.StatusCode)
+	}
+
+	var decoded struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return ImageVerdict{}, fmt.Errorf("decode image moderation response: %w", err)
+	}
+	action := strings.ToLower(strings.TrimSpace(decoded.Action))
+	if action == "allow" {
+		return ImageVerdict{}, nil
+	}
+	if _, ok := filterActionRank[action]; !ok {
+		return ImageVerdict{}, fmt.Errorf("image moderation returned unknown action %q", decoded.Action)
+	}
+	return ImageVerdict{Action: action, Reason: decoded.Reason}, nil
+}
+
+// imageModerationFilter runs a message's attachment past the moderator. It
+// goes last in the chain, so cheaper filters can reject before a provider
+// call. A provider failure flags the message for review instead of letting
+// the image through unseen or blocking chat.
+type imageModerationFilter struct {
+	moderator ImageModerator
+}
+
+func (f imageModerationFilter) Check(ctx context.Context, input MessageFilterInput) (*filterVerdict, error) {
+	if input.AttachmentURL == "" {
+		return nil, nil
+	}
+	verdict, err := f.moderator.ModerateImage(ctx, input.AttachmentURL)
+	if err != nil {
+		return &filterVerdict{Filter: "image", Action: filterActionFlag, Code: "image_blocked", Reason: fmt.Sprintf("moderation unavailable: %v", err)}, nil
+	}
+	if verdict.Action == "" {
+		return nil, nil
+	}
+	return &filterVerdict{Filter: "image", Action: verdict.Action, Code: "image_blocked", Reason: verdict.Reason}, nil
+}
+
+// normalizeAttachmentURL trims raw and checks it is an absolute https URL of
+// reasonable length pointing at an image, the only attachment the moderator
+// can check. Empty means no attachment.
+func normalizeAttachmentURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	if len(raw) > maxAttachmentURLLength {
+		return "", fmt.Errorf("%w of at most %d characters", errInvalidAttachmentURL, maxAttachmentURLLength)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || attachmentType(parsed.Path) != "image" {
+		return "", errInvalidAttachmentURL
+	}
+	return raw, nil
+}
@@ -0,0 +1,241 @@
+package maintenance
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// API keys let services reach the admin endpoints without a user session.
+// adminctl creates and revokes them and the server authenticates them; each
+// key is limited to its scopes and may expire. Only the key's hash is stored.
+
+const (
+	// APIKeyPrefix marks service keys so they're recognisable in config files.
+	APIKeyPrefix = "svc_"
+	// apiKeyTouchInterval throttles last-used updates to one write per key
+	// per interval.
+	apiKeyTouchInterval = time.Minute
+)
+
+// Scopes an API key can be granted, each covering a group of admin routes.
+const (
+	APIKeyScopeModeration = "moderation" // filtered words, flags, shadow bans, verification
+	APIKeyScopeWebhooks   = "webhooks"   // admin webhooks, which receive every event
+	APIKeyScopeDebug      = "debug"      // hub stats and pprof
+)
+
+// APIKeyScopes lists every scope a key can be granted.
+var APIKeyScopes = []string{APIKeyScopeModeration, APIKeyScopeWebhooks, APIKeyScopeDebug}
+
+var (
+	ErrInvalidAPIKey   = errors.New("invalid api key")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrAPIKeyNameTaken = errors.New("api key name is already in use")
+)
+
+// APIKey is a service credential. The key itself is only returned when it's
+// created.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+const selectAPIKeyIDByName = `
+SELECT id FROM api_keys WHERE name = ?;
+`
+
+const insertAPIKey = `
+INSERT INTO api_keys (name, key_hash, scopes, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?);
+`
+
+const selectAPIKeys = `
+SELECT id, name, scopes, created_at, expires_at, last_used_at
+FROM api_keys
+ORDER BY name;
+`
+
+const selectAPIKeyByHash = `
+SELECT id, name, scopes, created_at, expires_at, last_used_at
+FROM api_keys
+WHERE key_hash = ?;
+`
+
+const touchAPIKey = `
+UPDATE api_keys
+SET last_used_at = ?
+WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?);
+`
+
+const deleteAPIKey = `
+DELETE FROM api_keys WHERE name = ?;
+`
+
+// ParseAPIKeyScopes reads a comma-separated scope list, rejecting unknown
+// scopes and empty lists.
+func ParseAPIKeyScopes(raw string) ([]string, error) {
+	var scopes []string
+	for _, scope := range strings.Split(raw, ",") {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" || slices.Contains(scopes, scope) {
+			continue
+		}
+		if !slices.Contains(APIKeyScopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q; use %s", scope, strings.Join(APIKeyScopes, ", "))
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	return scopes, nil
+}
+
+// hashAPIKey is how keys are looked up without storing them.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return APIKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+func scanAPIKey(row interface{ Scan(...any) error }) (*APIKey, error) {
+	var key APIKey
+	var scopes string
+	var expiresAt, lastUsedAt sql.NullTime
+	if err := row.Scan(&key.ID, &key.Name, &scopes, &key.CreatedAt, &expiresAt, &lastUsedAt); err != nil {
+		return nil, err
+	}
+	key.Scopes = strings.Split(scopes, ",")
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	return &key, nil
+}
+
+// CreateAPIKey stores a key for a service and returns it with the key itself,
+// which is never retrievable again. A nil expiresAt never expires. It
+// returns ErrAPIKeyNameTaken if another key has this name.
+func CreateAPIKey(ctx context.Context, db *sql.DB, name string, scopes []string, expiresAt *time.Time) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("begin create api key tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existing int64
+	err = tx.QueryRowContext(ctx, selectAPIKeyIDByName, name).Scan(&existing)
+	if err == nil {
+		return nil, "", ErrAPIKeyNameTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("check api key name: %w", err)
+	}
+
+	secret, err := newAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key := APIKey{Name: name, Scopes: scopes, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	var expires any
+	if expiresAt != nil {
+		value := expiresAt.UTC().Truncate(time.Second)
+		key.ExpiresAt = &value
+		expires = FormatTime(value)
+	}
+
+	res, err := tx.ExecContext(ctx, insertAPIKey, name, hashAPIKey(secret), strings.Join(scopes, ","), FormatTime(key.CreatedAt), expires)
+	if err != nil {
+		return nil, "", fmt.Errorf("insert api key: %w", err)
+	}
+	if key.ID, err = res.LastInsertId(); err != nil {
+		return nil, "", fmt.Errorf("fetch api key id: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("commit api key: %w", err)
+	}
+	return &key, secret, nil
+}
+
+// ListAPIKeys returns every key, expired ones included, by name.
+func ListAPIKeys(ctx context.Context, db *sql.DB) ([]APIKey, error) {
+	rows, err := db.QueryContext(ctx, selectAPIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey deletes the key with this name, which stops working at once.
+func RevokeAPIKey(ctx context.Context, db *sql.DB, name string) error {
+	res, err := db.ExecContext(ctx, deleteAPIKey, strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	} else if affected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves a key to its record, recording the use at most
+// once per apiKeyTouchInterval. It returns ErrInvalidAPIKey for unknown and
+// expired keys.
+func AuthenticateAPIKey(ctx context.Context, db *sql.DB, secret string, now time.Time) (*APIKey, error) {
+	if !strings.HasPrefix(secret, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := scanAPIKey(db.QueryRowContext(ctx, selectAPIKeyByHash, hashAPIKey(secret)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("authenticate api key: %w", err)
+	}
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if _, err := db.ExecContext(ctx, touchAPIKey, FormatTime(now), key.ID, FormatTime(now.Add(-apiKeyTouchInterval))); err != nil {
+			return nil, fmt.Errorf("touch api key: %w", err)
+		}
+	}
+	return key, nil
+}
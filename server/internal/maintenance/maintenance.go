@@ -0,0 +1,90 @@
+// Package maintenance holds the database operations the server and the
+// adminctl tool share: opening the SQLite file, creating and promoting users,
+// managing service API keys, purging events and compacting the file. Schema
+// migrations stay with the server, so these expect a database it has already
+// migrated.
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrNoSchema means the database has never been migrated by the server.
+var ErrNoSchema = errors.New("database has no schema; start the server against it once to run migrations")
+
+const selectUsersTable = `
+SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'users';
+`
+
+// Open opens the SQLite database at path with the busy timeout, WAL journal
+// and immediate write transactions the server relies on.
+func Open(path string, maxConns int) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate", path)
+
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	conn.SetConnMaxLifetime(0)
+	conn.SetMaxIdleConns(maxConns)
+	conn.SetMaxOpenConns(maxConns)
+
+	if err := conn.Ping(); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("ping sqlite: %w", err)
+	}
+
+	return conn, nil
+}
+
+// RequireSchema returns ErrNoSchema unless the server has migrated db.
+func RequireSchema(ctx context.Context, db *sql.DB) error {
+	var one int
+	err := db.QueryRowContext(ctx, selectUsersTable).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoSchema
+	}
+	if err != nil {
+		return fmt.Errorf("check schema: %w", err)
+	}
+	return nil
+}
+
+// FormatTime renders t the way DATETIME columns store it, in UTC, so stored
+// times compare correctly as text.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// Compact checkpoints the WAL into the main file and rebuilds it with VACUUM,
+// returning the file size before and after. VACUUM needs exclusive access, so
+// run it while the server is stopped or quiet.
+func Compact(ctx context.Context, db *sql.DB, path string) (int64, int64, error) {
+	before := fileSize(path) + fileSize(path+"-wal")
+	if _, err := db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return 0, 0, fmt.Errorf("checkpoint wal: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `VACUUM`); err != nil {
+		return 0, 0, fmt.Errorf("vacuum: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return 0, 0, fmt.Errorf("checkpoint wal: %w", err)
+	}
+	return before, fileSize(path) + fileSize(path+"-wal"), nil
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
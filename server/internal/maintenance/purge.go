@@ -0,0 +1,144 @@
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultPurgeAge is how long past events are kept by PurgePastEvents.
+const DefaultPurgeAge = 30 * 24 * time.Hour
+
+// selectPurgeablePastEvents picks past events whose start (or creation, for
+// events without one) is older than the cutoff.
+const selectPurgeablePastEvents = `
+SELECT id FROM events
+WHERE status = 'past' AND COALESCE(starts_at, created_at) < ?
+ORDER BY id;
+`
+
+// purgeEventStatements delete everything hanging off the events in the
+// temporary purge_events table, children first, then the events themselves.
+// Foreign keys aren't enforced, so nothing cascades on its own.
+// Deleting the events fires events_log_delete, so feed clients drop them.
+// Outbox entries name what they're about inside their JSON payload, so the
+// ones about a purged event, its join requests or its messages are collected
+// into purge_outbox first, along with the webhook deliveries they fanned out
+// to, while the rows they point at still exist.
+var purgeEventStatements = []string{
+	`INSERT INTO purge_outbox (id) SELECT id FROM outbox WHERE
+	    (kind = 'webhook:event' AND json_extract(payload, '$.type') = 'event.created' AND json_extract(payload, '$.id') IN (SELECT id FROM purge_events))
+	 OR (kind = 'webhook:event' AND json_extract(payload, '$.type') = 'join_request.created' AND json_extract(payload, '$.id') IN (SELECT id FROM conversation_join_requests WHERE event_id IN (SELECT id FROM purge_events)))
+	 OR (kind = 'webhook:event' AND json_extract(payload, '$.type') = 'message.created' AND json_extract(payload, '$.id') IN (SELECT m.id FROM messages m JOIN conversations c ON c.id = m.conversation_id WHERE c.event_id IN (SELECT id FROM purge_events)))
+	 OR (kind IN ('message:push', 'message:link_preview') AND json_extract(payload, '$.message_id') IN (SELECT m.id FROM messages m JOIN conversations c ON c.id = m.conversation_id WHERE c.event_id IN (SELECT id FROM purge_events)))
+	 OR (kind = 'join_request:decided' AND json_extract(payload, '$.conversation_id') IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events)))`,
+	`INSERT OR IGNORE INTO purge_outbox (id) SELECT o.id FROM outbox o JOIN webhook_deliveries d ON d.id = json_extract(o.payload, '$.delivery_id') WHERE o.kind = 'webhook:deliver' AND d.outbox_id IN (SELECT id FROM purge_outbox)`,
+	`DELETE FROM webhook_deliveries WHERE outbox_id IN (SELECT id FROM purge_outbox)`,
+	`DELETE FROM outbox WHERE id IN (SELECT id FROM purge_outbox)`,
+	`DELETE FROM message_mentions WHERE message_id IN (SELECT m.id FROM messages m JOIN conversations c ON c.id = m.conversation_id WHERE c.event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM message_flags WHERE message_id IN (SELECT m.id FROM messages m JOIN conversations c ON c.id = m.conversation_id WHERE c.event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM poll_votes WHERE poll_id IN (SELECT p.id FROM polls p JOIN conversations c ON c.id = p.conversation_id WHERE c.event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM poll_options WHERE poll_id IN (SELECT p.id FROM polls p JOIN conversations c ON c.id = p.conversation_id WHERE c.event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM polls WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM scheduled_messages WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM messages WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM conversation_members WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM conversation_read_state WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM conversation_settings WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM unread_counters WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM conversation_shadow_bans WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM bot_conversations WHERE conversation_id IN (SELECT id FROM conversations WHERE event_id IN (SELECT id FROM purge_events))`,
+	`DELETE FROM conversations WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM conversation_join_requests WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_bookmarks WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_tags WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_bans WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_reminders_sent WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_time_slot_votes WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_time_slots WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_time_polls WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_settings WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_checkins WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_revisions WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM event_reviews WHERE event_id IN (SELECT id FROM purge_events)`,
+	`DELETE FROM events WHERE id IN (SELECT id FROM purge_events)`,
+}
+
+// PurgePastEvents deletes past events that started before cutoff together
+// with their chats, messages, requests and bookmarks, and returns their IDs.
+// With dryRun nothing is deleted.
+func PurgePastEvents(ctx context.Context, db *sql.DB, cutoff time.Time, dryRun bool) ([]int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin purge tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, selectPurgeablePastEvents, FormatTime(cutoff))
+	if err != nil {
+		return nil, fmt.Errorf("list purgeable events: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan purgeable event: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate purgeable events: %w", err)
+	}
+	rows.Close()
+
+	if dryRun || len(ids) == 0 {
+		return ids, nil
+	}
+
+	if _, err := PurgeEvents(ctx, tx, selectPurgeablePastEvents, FormatTime(cutoff)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit purge: %w", err)
+	}
+	return ids, nil
+}
+
+// PurgeEvents deletes the events selectIDs picks, with everything hanging
+// off them, within tx, and returns how many there were. The server's purge
+// of deleted events uses it too, so both remove the same rows.
+func PurgeEvents(ctx context.Context, tx *sql.Tx, selectIDs string, args ...any) (int, error) {
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE IF NOT EXISTS purge_events (id INTEGER PRIMARY KEY)`); err != nil {
+		return 0, fmt.Errorf("create purge table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM purge_events`); err != nil {
+		return 0, fmt.Errorf("reset purge table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE IF NOT EXISTS purge_outbox (id INTEGER PRIMARY KEY)`); err != nil {
+		return 0, fmt.Errorf("create purge outbox table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM purge_outbox`); err != nil {
+		return 0, fmt.Errorf("reset purge outbox table: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO purge_events (id) `+selectIDs, args...)
+	if err != nil {
+		return 0, fmt.Errorf("fill purge table: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("fill purge table rows affected: %w", err)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	for _, statement := range purgeEventStatements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return 0, fmt.Errorf("purge events: %w", err)
+		}
+	}
+	return int(n), nil
+}
@@ -0,0 +1,114 @@
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrEmailTaken   = errors.New("email is already registered")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+const selectUserIDByAnyEmail = `
+SELECT id FROM users WHERE email = ?;
+`
+
+const insertUser = `
+INSERT INTO users (name, email, password)
+VALUES (?, ?, ?);
+`
+
+const updateUserPassword = `
+UPDATE users SET password = ? WHERE email = ? AND deleted_at IS NULL;
+`
+
+const updateUserAdmin = `
+UPDATE users SET is_admin = ? WHERE email = ? AND deleted_at IS NULL;
+`
+
+const revokeSessionsByEmail = `
+UPDATE user_sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id IN (SELECT id FROM users WHERE email = ?) AND revoked_at IS NULL;
+`
+
+// CreateUser registers an account directly, bypassing any signup flow. It
+// returns ErrEmailTaken if any account, erased ones included, has the email.
+func CreateUser(ctx context.Context, db *sql.DB, name, email, password string, admin bool) (int64, error) {
+	email = strings.TrimSpace(email)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin create user tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existing int64
+	err = tx.QueryRowContext(ctx, selectUserIDByAnyEmail, email).Scan(&existing)
+	if err == nil {
+		return 0, ErrEmailTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("check email: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, insertUser, strings.TrimSpace(name), email, password)
+	if err != nil {
+		return 0, fmt.Errorf("insert user: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("fetch user id: %w", err)
+	}
+	if admin {
+		if _, err := tx.ExecContext(ctx, updateUserAdmin, true, email); err != nil {
+			return 0, fmt.Errorf("promote user: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit user: %w", err)
+	}
+	return id, nil
+}
+
+// SetPassword replaces the password of the account with this email.
+// Sessions already issued stay valid until they expire.
+func SetPassword(ctx context.Context, db *sql.DB, email, password string) error {
+	res, err := db.ExecContext(ctx, updateUserPassword, password, strings.TrimSpace(email))
+	if err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	return requireAffectedUser(res)
+}
+
+// SetAdmin grants or revokes the admin flag of the account with this email.
+// Tokens carry the admin role until they expire, so revoking it also signs
+// the account out everywhere; a grant applies from the next sign-in.
+func SetAdmin(ctx context.Context, db *sql.DB, email string, admin bool) error {
+	res, err := db.ExecContext(ctx, updateUserAdmin, admin, strings.TrimSpace(email))
+	if err != nil {
+		return fmt.Errorf("update admin flag: %w", err)
+	}
+	if err := requireAffectedUser(res); err != nil || admin {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, revokeSessionsByEmail, strings.TrimSpace(email)); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	return nil
+}
+
+func requireAffectedUser(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// invitePrefix keeps invite signatures distinct from session signatures made
+// with the same secret, so neither kind of token passes for the other.
+const invitePrefix = "invite."
+
+// inviteClaims is the payload of an event invite link. Invites are not stored;
+// a link stays valid until it expires (if ever), and hosts can ban anyone who
+// misuses one.
+type inviteClaims struct {
+	EventID   int64      `json:"event_id"`
+	InvitedBy int64      `json:"invited_by"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// issueInvite signs an invite with the current session key, under
+// invitePrefix. Invites signed with a retired key still verify.
+func (s *tokenSigner) issueInvite(claims inviteClaims) (string, error) {
+	payloadBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encode invite: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(payloadBytes)
+	return payload + "." + s.sign([]byte(invitePrefix+payload)), nil
+}
+
+// verifyInvite checks an invite's signature and expiry.
+func (s *tokenSigner) verifyInvite(token string) (*inviteClaims, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errMalformedToken
+	}
+	if !s.validSignature([]byte(invitePrefix+payload), signature) {
+		return nil, errInvalidToken
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	var claims inviteClaims
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil || claims.EventID <= 0 {
+		return nil, errMalformedToken
+	}
+	if claims.ExpiresAt != nil && time.Now().UTC().After(*claims.ExpiresAt) {
+		return nil, errExpiredToken
+	}
+	return &claims, nil
+}
+
+// AcceptEventInvite adds userID straight into the event chat, skipping host
+// approval. An open request the user already had is marked approved. Bans and
+// capacity still apply. It returns the event chat's ID.
+func (r *EventRepository) AcceptEventInvite(ctx context.Context, eventID, userID, invitedBy int64) (int64, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	if event.UserID == userID {
+		return 0, ErrAlreadyConversationMember
+	}
+	if event.Status == eventStatusCancelled {
+		return 0, ErrEventCancelled
+	}
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin accept invite tx: %w", err)
+	}
+
+	var member int
+	if err := tx.QueryRowContext(ctx, checkConversationMembership, convo.ID, userID).Scan(&member); err == nil {
+		tx.Rollback()
+		return 0, ErrAlreadyConversationMember
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return 0, fmt.Errorf("check membership: %w", err)
+	}
+
+	banned, err := isBannedFromEvent(ctx, tx, eventID, userID)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if banned {
+		tx.Rollback()
+		return 0, ErrBannedFromEvent
+	}
+
+	full, err := isEventFull(ctx, tx, event, convo.ID)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if full {
+		tx.Rollback()
+		return 0, ErrEventFull
+	}
+
+	req, err := scanJoinRequest(tx.QueryRowContext(ctx, selectOpenJoinRequest, eventID, userID))
+	switch {
+	case err == nil:
+		err = admitJoinRequest(ctx, tx, req, convo.ID, &invitedBy)
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err = tx.ExecContext(ctx, insertConversationMember, convo.ID, userID, roleMember); err != nil {
+			err = fmt.Errorf("add conversation member: %w", err)
+		}
+	default:
+		err = fmt.Errorf("check open join request: %w", err)
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit accept invite: %w", err)
+	}
+	return convo.ID, nil
+}
+
+// createInvite issues an invite link token for an event. The optional body
+// `{"expires_at": "..."}` sets when the link stops working; without it the
+// link never expires.
+//
+// Responses:
+//  - 201 with `token`, `eventId` and `expiresAt`
+//  - 401 if the caller has no session
+//  - 400 for an invalid event id or an expiry in the past
+//  - 403 if the caller is not the event host or a co-host
+//  - 404 if the event is not found
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) createInvite(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventIDParam := c.Param("id")
+	eventID, err := strconv.ParseInt(eventIDParam, 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload CreateInviteParams
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	now := time.Now().UTC()
+	if payload.ExpiresAt != nil {
+		if !payload.ExpiresAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		expiresAt := payload.ExpiresAt.UTC()
+		payload.ExpiresAt = &expiresAt
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RequireEventModerator(ctx, eventID, claims.UserID); err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrNotEventHost):
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event host or a co-host can create invites"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invite"})
+		}
+		return
+	}
+
+	token, err := h.hub.signer.issueInvite(inviteClaims{
+		EventID:   eventID,
+		InvitedBy: claims.UserID,
+		IssuedAt:  now,
+		ExpiresAt: payload.ExpiresAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invite"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":     token,
+		"eventId":   eventID,
+		"expiresAt": payload.ExpiresAt,
+	})
+}
+
+// acceptInvite adds the caller to the invited event's chat without a join
+// request. The room is told as usual.
+//
+// Responses:
+//  - 200 with `eventId` and `conversationId`
+//  - 401 if the caller has no session
+//  - 400 if the invite is malformed or forged
+//  - 403 with `code: banned` if the host banned the caller
+//  - 404 if the event no longer exists
+//  - 409 if the caller is already a member, or the event is full or cancelled
+//  - 410 if the invite has expired
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) acceptInvite(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	invite, err := h.hub.signer.verifyInvite(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, errExpiredToken) {
+			c.JSON(http.StatusGone, gin.H{"error": "invite has expired"})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invite"})
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	convoID, err := h.repo.AcceptEventInvite(ctx, invite.EventID, claims.UserID, invite.InvitedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound), errors.Is(err, ErrConversationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, ErrBannedFromEvent):
+			c.JSON(http.StatusForbidden, gin.H{"error": "you can't join this event", "code": "banned"})
+		case errors.Is(err, ErrAlreadyConversationMember):
+			c.JSON(http.StatusConflict, gin.H{"error": "already a member of this chat"})
+		case errors.Is(err, ErrEventFull):
+			c.JSON(http.StatusConflict, gin.H{"error": "event is full"})
+		case errors.Is(err, ErrEventCancelled):
+			c.JSON(http.StatusConflict, gin.H{"error": "event was cancelled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept invite"})
+		}
+		return
+	}
+
+	h.hub.NotifyMembership(convoID, claims.UserID, "added")
+	h.hub.Announce(ctx, convoID, claims.UserID, "%s joined with an invite link", claims.UserID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":        invite.EventID,
+		"conversationId": convoID,
+	})
+}
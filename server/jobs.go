@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// jobJitterFraction is the most a run is pushed back, as a share of the job's
+// interval, so instances started together don't all hit the database at once.
+const jobJitterFraction = 0.1
+
+const createTableJobLocks = `
+CREATE TABLE IF NOT EXISTS job_locks (
+    name TEXT PRIMARY KEY,
+    owner TEXT NOT NULL,
+    locked_until DATETIME NOT NULL,
+    last_run_at DATETIME,
+    last_error TEXT
+);
+`
+
+// acquireJobLock takes the lease on a job if nobody holds it, it has lapsed,
+// or the caller already holds it. No row is touched when someone else does.
+const acquireJobLock = `
+INSERT INTO job_locks (name, owner, locked_until)
+VALUES (?, ?, ?)
+ON CONFLICT(name) DO UPDATE SET
+    owner = excluded.owner,
+    locked_until = excluded.locked_until
+WHERE job_locks.locked_until <= ? OR job_locks.owner = excluded.owner;
+`
+
+const updateJobRun = `
+UPDATE job_locks
+SET last_run_at = ?, last_error = ?
+WHERE name = ? AND owner = ?;
+`
+
+func (r *EventRepository) initJobs(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableJobLocks); err != nil {
+		return fmt.Errorf("create job locks table: %w", err)
+	}
+	return nil
+}
+
+// AcquireJobLock leases the named job to owner until `until`. It reports false
+// when another instance holds an unexpired lease.
+func (r *EventRepository) AcquireJobLock(ctx context.Context, name, owner string, now, until time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, acquireJobLock, name, owner, sqliteTime(until), sqliteTime(now))
+	if err != nil {
+		return false, fmt.Errorf("acquire job lock: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("acquire job lock rows affected: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// RecordJobRun stores when owner last ran the job and how it went.
+func (r *EventRepository) RecordJobRun(ctx context.Context, name, owner string, ranAt time.Time, runErr error) error {
+	var lastError any
+	if runErr != nil {
+		lastError = runErr.Error()
+	}
+	if _, err := r.db.ExecContext(ctx, updateJobRun, sqliteTime(ranAt), lastError, name, owner); err != nil {
+		return fmt.Errorf("record job run: %w", err)
+	}
+	return nil
+}
+
+// scheduledJob is recurring work run by JobRunner.
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// JobRunner runs recurring jobs in-process. Each run first takes a lease on
+// the job in job_locks lasting one interval, so when several server instances
+// share a database a job runs on only one of them per interval.
+type JobRunner struct {
+	repo  *EventRepository
+	owner string
+	jobs  []scheduledJob
+}
+
+// NewJobRunner creates a runner identified by JOB_INSTANCE_ID, or by host name
+// and pid when that isn't set.
+func NewJobRunner(repo *EventRepository) *JobRunner {
+	owner := strings.TrimSpace(os.Getenv("JOB_INSTANCE_ID"))
+	if owner == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			host = "localhost"
+		}
+		owner = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return &JobRunner{repo: repo, owner: owner}
+}
+
+// Register adds a job that runs every interval. It must be called before Start.
+func (r *JobRunner) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	r.jobs = append(r.jobs, scheduledJob{name: name, interval: interval, run: run})
+}
+
+// Start runs every registered job in its own goroutine until ctx is cancelled.
+func (r *JobRunner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		go r.loop(ctx, job)
+	}
+}
+
+// loop runs the job once straight away and then every interval plus jitter.
+func (r *JobRunner) loop(ctx context.Context, job scheduledJob) {
+	for {
+		r.runOnce(ctx, job)
+
+		jitter := time.Duration(rand.Int63n(int64(float64(job.interval)*jobJitterFraction) + 1))
+		timer := time.NewTimer(job.interval + jitter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *JobRunner) runOnce(ctx context.Context, job scheduledJob) {
+	lockCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	now := time.Now()
+	acquired, err := r.repo.AcquireJobLock(lockCtx, job.name, r.owner, now, now.Add(job.interval))
+	if err != nil {
+		log.Printf("job %s: %v", job.name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	runErr := job.run(ctx)
+	if runErr != nil {
+		log.Printf("job %s failed: %v", job.name, runErr)
+	}
+
+	recordCtx, recordCancel := context.WithTimeout(ctx, requestTimeout)
+	defer recordCancel()
+	if err := r.repo.RecordJobRun(recordCtx, job.name, r.owner, now, runErr); err != nil {
+		log.Printf("job %s: %v", job.name, err)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import "time"
+
+// minJobInterval keeps a mistyped interval (say 1ms) from turning a job into
+// a busy loop against the database.
+const minJobInterval = time.Second
+
+// JobsConfig holds the background jobs' schedules and windows, all Go
+// durations. Unlike the chat and HTTP readers, a bad value here fails startup:
+// a typo in a purge grace or a cancellation cutoff should not quietly run on
+// the default.
+type JobsConfig struct {
+	ExpiryInterval   time.Duration // EVENT_EXPIRY_INTERVAL
+	ChatArchiveAfter time.Duration // EVENT_CHAT_ARCHIVE_AFTER; 0 disables archiving
+	ChatLockAfter    time.Duration // EVENT_CHAT_LOCK_AFTER; 0 disables locking
+
+	TrendingInterval time.Duration // EVENT_TRENDING_INTERVAL
+	TrendingHalfLife time.Duration // EVENT_TRENDING_HALF_LIFE
+
+	ReminderInterval time.Duration // EVENT_REMINDER_INTERVAL
+
+	MinAttendeesInterval time.Duration // EVENT_MIN_ATTENDEES_INTERVAL
+	MinAttendeesCutoff   time.Duration // EVENT_MIN_ATTENDEES_CUTOFF
+
+	PurgeInterval time.Duration // EVENT_PURGE_INTERVAL
+	PurgeGrace    time.Duration // EVENT_PURGE_GRACE; 0 purges on the next run
+
+	OutboxPollInterval time.Duration // OUTBOX_POLL_INTERVAL
+
+	ScheduledMessageInterval time.Duration // SCHEDULED_MESSAGE_INTERVAL
+
+	EmailDigestInterval time.Duration // EMAIL_DIGEST_INTERVAL
+}
+
+func defaultJobsConfig() JobsConfig {
+	return JobsConfig{
+		ExpiryInterval:           defaultEventExpiryInterval,
+		ChatArchiveAfter:         defaultEventChatArchiveAfter,
+		ChatLockAfter:            defaultEventChatLockAfter,
+		TrendingInterval:         defaultTrendingInterval,
+		TrendingHalfLife:         defaultTrendingHalfLife,
+		ReminderInterval:         defaultEventReminderInterval,
+		MinAttendeesInterval:     defaultMinAttendeesInterval,
+		MinAttendeesCutoff:       defaultMinAttendeesCutoff,
+		PurgeInterval:            defaultEventPurgeInterval,
+		PurgeGrace:               defaultEventPurgeGrace,
+		OutboxPollInterval:       defaultOutboxPollInterval,
+		ScheduledMessageInterval: defaultScheduledMessageInterval,
+		EmailDigestInterval:      defaultEmailDigestInterval,
+	}
+}
+
+// newJobsConfigFromEnv reads the job settings, returning every invalid one
+// rather than falling back.
+func newJobsConfigFromEnv() (JobsConfig, []error) {
+	config := defaultJobsConfig()
+	var problems []error
+	read := func(name string, target *time.Duration, allowZero bool) {
+		parsed, err := envDuration(name, *target, allowZero)
+		if err != nil {
+			problems = append(problems, err)
+		}
+		*target = parsed
+	}
+
+	read("EVENT_EXPIRY_INTERVAL", &config.ExpiryInterval, false)
+	read("EVENT_CHAT_ARCHIVE_AFTER", &config.ChatArchiveAfter, true)
+	read("EVENT_CHAT_LOCK_AFTER", &config.ChatLockAfter, true)
+	read("EVENT_TRENDING_INTERVAL", &config.TrendingInterval, false)
+	read("EVENT_TRENDING_HALF_LIFE", &config.TrendingHalfLife, false)
+	read("EVENT_REMINDER_INTERVAL", &config.ReminderInterval, false)
+	read("EVENT_MIN_ATTENDEES_INTERVAL", &config.MinAttendeesInterval, false)
+	read("EVENT_MIN_ATTENDEES_CUTOFF", &config.MinAttendeesCutoff, false)
+	read("EVENT_PURGE_INTERVAL", &config.PurgeInterval, false)
+	read("EVENT_PURGE_GRACE", &config.PurgeGrace, true)
+	read("OUTBOX_POLL_INTERVAL", &config.OutboxPollInterval, false)
+	read("SCHEDULED_MESSAGE_INTERVAL", &config.ScheduledMessageInterval, false)
+	read("EMAIL_DIGEST_INTERVAL", &config.EmailDigestInterval, false)
+	return config, problems
+}
+
+// jobInterval is one job's sweep interval and the variable that sets it.
+type jobInterval struct {
+	name  string
+	value time.Duration
+}
+
+// intervals lists the sweep intervals, in a fixed order, for validate.
+func (cfg JobsConfig) intervals() []jobInterval {
+	return []jobInterval{
+		{"EVENT_EXPIRY_INTERVAL", cfg.ExpiryInterval},
+		{"EVENT_TRENDING_INTERVAL", cfg.TrendingInterval},
+		{"EVENT_REMINDER_INTERVAL", cfg.ReminderInterval},
+		{"EVENT_MIN_ATTENDEES_INTERVAL", cfg.MinAttendeesInterval},
+		{"EVENT_PURGE_INTERVAL", cfg.PurgeInterval},
+		{"OUTBOX_POLL_INTERVAL", cfg.OutboxPollInterval},
+		{"SCHEDULED_MESSAGE_INTERVAL", cfg.ScheduledMessageInterval},
+		{"EMAIL_DIGEST_INTERVAL", cfg.EmailDigestInterval},
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// linkPreviewTimeout bounds one page fetch, redirects included.
+	linkPreviewTimeout = 4 * time.Second
+	// maxLinkPreviewBytes is how much of a page is read looking for its head.
+	maxLinkPreviewBytes     = 512 << 10
+	maxLinkPreviewRedirects = 3
+	// maxLinkPreviewText caps the title and description kept from a page.
+	maxLinkPreviewText = 300
+)
+
+var errNoLinkPreview = errors.New("page has no preview metadata")
+var errPrivateAddress = errors.New("refusing to fetch a non-public address")
+
+// LinkPreview is what a message's first link points at, read from the page's
+// OpenGraph tags. It is stored in the message metadata as `link_preview`.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// LinkPreviewer fetches the preview for a URL. Implementations must be safe
+// for concurrent use.
+type LinkPreviewer interface {
+	Preview(ctx context.Context, rawURL string) (*LinkPreview, error)
+}
+
+// newLinkPreviewerFromEnv enables previews with LINK_PREVIEWS=true. Pages are
+// fetched with LINK_PREVIEW_USER_AGENT, if set. Unset disables previews and
+// returns nil.
+func newLinkPreviewerFromEnv() LinkPreviewer {
+	raw := strings.TrimSpace(os.Getenv("LINK_PREVIEWS"))
+	if raw == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Printf("invalid LINK_PREVIEWS %q; link previews disabled", raw)
+		return nil
+	}
+	if !enabled {
+		return nil
+	}
+	userAgent := strings.TrimSpace(os.Getenv("LINK_PREVIEW_USER_AGENT"))
+	if userAgent == "" {
+		userAgent = "who-else-is-free-server (link preview)"
+	}
+	return newOpenGraphPreviewer(userAgent)
+}
+
+// openGraphPreviewer reads OpenGraph tags from the page's head, falling back
+// to <title> and the description meta tag. Pages are fetched from public
+// addresses only, so a message can't make the server probe its own network.
+type openGraphPreviewer struct {
+	client    *http.Client
+	userAgent string
+}
+
+func newOpenGraphPreviewer(userAgent string) *openGraphPreviewer {
+	dialer := &net.Dialer{Timeout: linkPreviewTimeout, Control: publicAddressControl(errPrivateAddress)}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   linkPreviewTimeout,
+		ResponseHeaderTimeout: linkPreviewTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       time.Minute,
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   linkPreviewTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxLinkPreviewRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to %s URL", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+	return &openGraphPreviewer{client: client, userAgent: userAgent}
+}
+
+func (p *openGraphPreviewer) Preview(ctx context.Context, rawURL string) (*LinkPreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build preview request: %w", err)
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch preview: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("preview status %d", resp.StatusCode)
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mediaType != "text/html" {
+		return nil, errNoLinkPreview
+	}
+
+	preview := parseOpenGraph(io.LimitReader(resp.Body, maxLinkPreviewBytes), resp.Request.URL)
+	if preview.Title == "" && preview.Description == "" {
+		return nil, errNoLinkPreview
+	}
+	preview.URL = rawURL
+	return preview, nil
+}
+
+// parseOpenGraph scans the document head for preview metadata. Relative
+// image URLs are resolved against base, the page's final URL.
+func parseOpenGraph(r io.Reader, base *url.URL) *LinkPreview {
+	var og, fallback LinkPreview
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return mergeLinkPreview(og, fallback, base)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "body":
+				return mergeLinkPreview(og, fallback, base)
+			case "title":
+				inTitle = true
+			case "meta":
+				if !hasAttr {
+					continue
+				}
+				var key, content string
+				for {
+					attr, value, more := tokenizer.TagAttr()
+					switch string(attr) {
+					case "property", "name":
+						key = strings.ToLower(string(value))
+					case "content":
+						content = string(value)
+					}
+					if !more {
+						break
+					}
+				}
+				switch key {
+				case "og:title":
+					og.Title = content
+				case "og:description":
+					og.Description = content
+				case "og:image":
+					og.ImageURL = content
+				case "og:site_name":
+					og.SiteName = content
+				case "description":
+					fallback.Description = content
+				}
+			}
+		case html.TextToken:
+			if inTitle && fallback.Title == "" {
+				fallback.Title = string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				return mergeLinkPreview(og, fallback, base)
+			}
+		}
+	}
+}
+
+func mergeLinkPreview(og, fallback LinkPreview, base *url.URL) *LinkPreview {
+	preview := &LinkPreview{
+		Title:       clipPreviewText(og.Title),
+		Description: clipPreviewText(og.Description),
+		SiteName:    clipPreviewText(og.SiteName),
+	}
+	if preview.Title == "" {
+		preview.Title = clipPreviewText(fallback.Title)
+	}
+	if preview.Description == "" {
+		preview.Description = clipPreviewText(fallback.Description)
+	}
+	if image, err := base.Parse(strings.TrimSpace(og.ImageURL)); err == nil && og.ImageURL != "" && (image.Scheme == "http" || image.Scheme == "https") {
+		preview.ImageURL = image.String()
+	}
+	return preview
+}
+
+func clipPreviewText(text string) string {
+	text = strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+	if utf8.RuneCountInString(text) <= maxLinkPreviewText {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxLinkPreviewText-1])) + "…"
+}
+
+// firstLink returns the first link in body as an absolute http(s) URL.
+// Trailing punctuation is taken as part of the sentence, not the link.
+func firstLink(body string) (string, bool) {
+	match := linkPattern.FindString(body)
+	if match == "" {
+		return "", false
+	}
+	match = strings.TrimRight(match, ".,;:!?)]}'\"")
+	if strings.HasPrefix(strings.ToLower(match), "www.") {
+		match = "https://" + match
+	}
+	parsed, err := url.Parse(match)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+// wantsLinkPreview reports whether a new message should get a preview.
+func wantsLinkPreview(params CreateMessageParams, kind, messageType string) bool {
+	return kind == messageKindUser && messageType == messageTypeText && !params.Hidden && linkPattern.MatchString(params.Body)
+}
+
+const setMessageMetadata = `
+UPDATE messages SET metadata = ? WHERE id = ? AND metadata IS NULL;
+`
+
+// SetMessageMetadata stores metadata on a message that has none yet and
+// reports whether it did.
+func (r *EventRepository) SetMessageMetadata(ctx context.Context, messageID int64, metadata json.RawMessage) (bool, error) {
+	result, err := r.db.ExecContext(ctx, setMessageMetadata, string(metadata), messageID)
+	if err != nil {
+		return false, fmt.Errorf("set message metadata: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("check metadata rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// BroadcastMessageUpdate sends `message:updated` with the message's current
+// state to its conversation.
+func (h *ChatHub) BroadcastMessageUpdate(msg Message) {
+	payload, err := json.Marshal(outboundMessage{Type: "message:updated", Message: newMessagePayload(msg)})
+	if err != nil {
+		log.Printf("marshal message update failed: %v", err)
+		return
+	}
+	h.broadcast <- chatBroadcast{conversationID: msg.ConversationID, payload: payload}
+}
+
+// deliverLinkPreview fetches the preview for a message:link_preview entry,
+// stores it in the message metadata and tells the chat. A page that can't be
+// previewed is not retried; only database failures are.
+func (d *OutboxDispatcher) deliverLinkPreview(ctx context.Context, entry outboxEntry) error {
+	if d.previews == nil {
+		return nil
+	}
+	var payload outboxMessagePayload
+	if err := json.Unmarshal(entry.payload, &payload); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	msg, err := d.repo.GetMessageByID(ctx, payload.MessageID)
+	if errors.Is(err, ErrMessageNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	link, ok := firstLink(msg.Body)
+	if !ok || len(msg.Metadata) > 0 {
+		return nil
+	}
+
+	preview, err := d.previews.Preview(ctx, link)
+	if err != nil {
+		log.Printf("no link preview for message %d: %v", msg.ID, err)
+		return nil
+	}
+	metadata, err := json.Marshal(struct {
+		LinkPreview *LinkPreview `json:"link_preview"`
+	}{preview})
+	if err != nil {
+		return fmt.Errorf("encode link preview: %w", err)
+	}
+	stored, err := d.repo.SetMessageMetadata(ctx, msg.ID, metadata)
+	if err != nil || !stored {
+		return err
+	}
+	msg.Metadata = metadata
+	d.hub.BroadcastMessageUpdate(*msg)
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errUnknownLink = errors.New("link does not point to anything")
+
+// resolvedLink is what a share URL points at. Type is "event",
+// "conversation", "user" or "invite"; only the matching IDs are set.
+type resolvedLink struct {
+	Type           string     `json:"type"`
+	EventID        int64      `json:"eventId,omitempty"`
+	ConversationID int64      `json:"conversationId,omitempty"`
+	UserID         int64      `json:"userId,omitempty"`
+	InvitedBy      int64      `json:"invitedBy,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+}
+
+// parseShareLink splits a share URL into its kind and the last path segment.
+// Any host or app scheme is accepted, as long as the path is one of
+// /events/:id, /conversations/:id, /users/:id or /invites/:token. A bare
+// invite token is accepted too.
+func parseShareLink(raw string) (kind, ref string, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", false
+	}
+	if !strings.Contains(raw, "/") {
+		return "invites", raw, true
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", "", false
+	}
+	// For app links like whoelseisfree://events/12 the first segment parses
+	// as the host.
+	path := parsed.Path
+	if parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Host != "" {
+		path = parsed.Host + "/" + path
+	}
+
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	if len(segments) < 2 {
+		return "", "", false
+	}
+	kind, ref = segments[len(segments)-2], segments[len(segments)-1]
+	switch kind {
+	case "events", "conversations", "users", "invites":
+		return kind, ref, true
+	}
+	return "", "", false
+}
+
+// resolveLink turns a share link into the IDs the app needs to route it, so
+// universal links take one server call. Conversations only resolve for their
+// members. Invites are checked, not accepted.
+//
+// Query params: `url` (a share URL or bare invite token).
+// Responses:
+//  - 200 with the resolved `link`
+//  - 401 if the caller has no session
+//  - 400 if `url` is missing or not a recognised share link, or the invite is malformed
+//  - 404 if the target doesn't exist or isn't visible to the caller
+//  - 410 if the invite has expired
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) resolveLink(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	kind, ref, ok := parseShareLink(c.Query("url"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unrecognised link"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	var link *resolvedLink
+	var err error
+	if kind == "invites" {
+		invite, verifyErr := h.hub.signer.verifyInvite(ref)
+		switch {
+		case errors.Is(verifyErr, errExpiredToken):
+			c.JSON(http.StatusGone, gin.H{"error": "invite has expired"})
+			return
+		case verifyErr != nil:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invite"})
+			return
+		}
+		link, err = h.resolveEventLink(ctx, invite.EventID, claims.UserID, true)
+		if link != nil {
+			link.Type = "invite"
+			link.InvitedBy = invite.InvitedBy
+			link.ExpiresAt = invite.ExpiresAt
+		}
+	} else {
+		id, parseErr := strconv.ParseInt(ref, 10, 64)
+		if parseErr != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unrecognised link"})
+			return
+		}
+		switch kind {
+		case "events":
+			link, err = h.resolveEventLink(ctx, id, claims.UserID, false)
+		case "conversations":
+			link, err = h.resolveConversationLink(ctx, id, claims.UserID)
+		case "users":
+			link, err = h.resolveUserLink(ctx, id)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, errUnknownLink) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "link not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve link"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"link": link})
+}
+
+// resolveEventLink resolves an event by ID, or by invite, which also opens
+// private events to the caller.
+func (h *ChatHTTPHandler) resolveEventLink(ctx context.Context, eventID, viewerID int64, invited bool) (*resolvedLink, error) {
+	var err error
+	if invited {
+		_, err = h.repo.GetEventByID(ctx, eventID)
+	} else {
+		_, err = h.repo.GetVisibleEvent(ctx, eventID, viewerID)
+	}
+	if err != nil {
+		if errors.Is(err, ErrEventNotFound) {
+			return nil, errUnknownLink
+		}
+		return nil, err
+	}
+	link := &resolvedLink{Type: "event", EventID: eventID}
+	convo, err := h.repo.GetConversationByEventID(ctx, eventID)
+	if err == nil {
+		link.ConversationID = convo.ID
+	} else if !errors.Is(err, ErrConversationNotFound) {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (h *ChatHTTPHandler) resolveConversationLink(ctx context.Context, conversationID, viewerID int64) (*resolvedLink, error) {
+	isMember, err := h.repo.IsConversationMember(ctx, conversationID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errUnknownLink
+	}
+	convo, err := h.repo.GetConversation(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			return nil, errUnknownLink
+		}
+		return nil, err
+	}
+	link := &resolvedLink{Type: "conversation", ConversationID: convo.ID}
+	if convo.EventID != nil {
+		link.EventID = *convo.EventID
+	}
+	return link, nil
+}
+
+func (h *ChatHTTPHandler) resolveUserLink(ctx context.Context, userID int64) (*resolvedLink, error) {
+	names, err := h.repo.GetUserNames(ctx, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := names[userID]; !ok {
+		return nil, errUnknownLink
+	}
+	return &resolvedLink{Type: "user", UserID: userID}, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// approximateCoordinateScale rounds coordinates to two decimal places, about
+// a kilometre, for events that hide their exact location.
+const approximateCoordinateScale = 100
+
+const selectMemberEventIDs = `
+SELECT c.event_id
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+WHERE cm.user_id = ? AND c.event_id IS NOT NULL;
+`
+
+func (r *EventRepository) initLocationPrivacy(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "events", "hide_exact_location", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	return r.ensureColumn(ctx, "events", "area", "TEXT")
+}
+
+// nullableArea stores a blank area as NULL.
+func nullableArea(area string) sql.NullString {
+	area = strings.TrimSpace(area)
+	return sql.NullString{String: area, Valid: area != ""}
+}
+
+// updatedArea is the area parameter for updateEvent: nil keeps the stored
+// one and a blank clears it.
+func updatedArea(area *string) any {
+	if area == nil {
+		return nil
+	}
+	return strings.TrimSpace(*area)
+}
+
+// ListMemberEventIDs loads the events whose chat the user is in, host
+// included, as a set.
+func (r *EventRepository) ListMemberEventIDs(ctx context.Context, userID int64) (map[int64]struct{}, error) {
+	rows, err := r.db.QueryContext(ctx, selectMemberEventIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query member event ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]struct{})
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan member event id: %w", err)
+		}
+		ids[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate member event ids: %w", err)
+	}
+	return ids, nil
+}
+
+// approximateEventLocation replaces the venue with the host's area and
+// rounds the coordinates.
+func approximateEventLocation(evt *Event) {
+	evt.Location = ""
+	if evt.Area != nil {
+		evt.Location = *evt.Area
+	}
+	evt.PlaceName = nil
+	if evt.Latitude != nil && evt.Longitude != nil {
+		lat := math.Round(*evt.Latitude*approximateCoordinateScale) / approximateCoordinateScale
+		lng := math.Round(*evt.Longitude*approximateCoordinateScale) / approximateCoordinateScale
+		evt.Latitude, evt.Longitude = &lat, &lng
+	}
+	evt.LocationApproximate = true
+}
+
+// shapeEventLocations approximates the location of every event that hides it
+// from viewerID: everyone but the host and the event chat's members. Guests
+// are viewerID 0.
+func shapeEventLocations(ctx context.Context, store EventStore, viewerID int64, events ...*Event) error {
+	var members map[int64]struct{}
+	for _, evt := range events {
+		if !evt.HideExactLocation || (viewerID > 0 && evt.UserID == viewerID) {
+			continue
+		}
+		if members == nil && viewerID > 0 {
+			ids, err := store.ListMemberEventIDs(ctx, viewerID)
+			if err != nil {
+				return err
+			}
+			members = ids
+		}
+		if _, ok := members[evt.ID]; !ok {
+			approximateEventLocation(evt)
+		}
+	}
+	return nil
+}
+
+// eventRefs points at each event in a slice, for shapeEventLocations.
+func eventRefs(events []Event) []*Event {
+	refs := make([]*Event, len(events))
+	for i := range events {
+		refs[i] = &events[i]
+	}
+	return refs
+}
+
+// shapeRecommendedLocations is shapeEventLocations for recommendations. The
+// distance to an approximated event is rounded to the kilometre, so it can't
+// be used to find the venue either.
+func shapeRecommendedLocations(ctx context.Context, store EventStore, viewerID int64, recs []RecommendedEvent) error {
+	refs := make([]*Event, len(recs))
+	for i := range recs {
+		refs[i] = &recs[i].Event
+	}
+	if err := shapeEventLocations(ctx, store, viewerID, refs...); err != nil {
+		return err
+	}
+	for i := range recs {
+		if recs[i].LocationApproximate && recs[i].DistanceKm != nil {
+			distance := math.Round(*recs[i].DistanceKm)
+			recs[i].DistanceKm = &distance
+		}
+	}
+	return nil
+}
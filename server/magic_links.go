@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Magic links sign in without a password: asking emails a link carrying a
+// signed token, and the app posts the token back for a session. The token
+// names the account and a random nonce; the nonce's hash is stored so each
+// link signs in once.
+
+const (
+	// magicLinkPrefix keeps magic link signatures distinct from session and
+	// check-in signatures made with the same secret.
+	magicLinkPrefix = "magic."
+	// magicLinkTTL is how long a login link works.
+	magicLinkTTL = 15 * time.Minute
+	// magicLinkCooldown stops the same account being mailed over and over.
+	magicLinkCooldown = time.Minute
+	// defaultMagicLinkURL is the app screen login links open.
+	defaultMagicLinkURL = "http://localhost:8081/magic-login"
+)
+
+var ErrInvalidMagicLink = errors.New("login link is invalid or expired")
+
+// magicLinkClaims is the payload of a magic link token.
+type magicLinkClaims struct {
+	UserID    int64     `json:"user_id"`
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// issueMagicLink signs a magic link token the same way check-in codes are
+// signed, under magicLinkPrefix.
+func (s *tokenSigner) issueMagicLink(claims magicLinkClaims) (string, error) {
+	payloadBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encode magic link: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(payloadBytes)
+	return payload + "." + s.sign([]byte(magicLinkPrefix+payload)), nil
+}
+
+// verifyMagicLink checks a magic link token's signature. Expiry and single
+// use are checked against the stored nonce.
+func (s *tokenSigner) verifyMagicLink(token string) (*magicLinkClaims, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errMalformedToken
+	}
+	if !s.validSignature([]byte(magicLinkPrefix+payload), signature) {
+		return nil, errInvalidToken
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	var claims magicLinkClaims
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil || claims.UserID <= 0 || claims.Nonce == "" {
+		return nil, errMalformedToken
+	}
+	return &claims, nil
+}
+
+const createTableMagicLinks = `
+CREATE TABLE IF NOT EXISTS magic_links (
+    nonce_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexMagicLinksUser = `
+CREATE INDEX IF NOT EXISTS idx_magic_links_user
+ON magic_links(user_id, created_at);
+`
+
+const selectRecentMagicLink = `
+SELECT 1 FROM magic_links
+WHERE user_id = ? AND created_at > ?
+LIMIT 1;
+`
+
+// Asking again replaces any link still outstanding.
+const deleteUnusedMagicLinks = `
+DELETE FROM magic_links
+WHERE user_id = ? AND used_at IS NULL;
+`
+
+const insertMagicLink = `
+INSERT INTO magic_links (nonce_hash, user_id, expires_at, created_at)
+VALUES (?, ?, ?, ?);
+`
+
+const claimMagicLink = `
+UPDATE magic_links
+SET used_at = CURRENT_TIMESTAMP
+WHERE nonce_hash = ? AND user_id = ? AND used_at IS NULL AND expires_at > ?;
+`
+
+const selectSessionUser = `
+SELECT id, name, email, created_at
+FROM users
+WHERE id = ? AND deleted_at IS NULL;
+`
+
+const deleteMagicLinksForUser = `
+DELETE FROM magic_links
+WHERE user_id = ?;
+`
+
+func (r *EventRepository) initMagicLinks(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableMagicLinks); err != nil {
+		return fmt.Errorf("create magic links table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexMagicLinksUser); err != nil {
+		return fmt.Errorf("create magic links index: %w", err)
+	}
+	return nil
+}
+
+// CreateMagicLink issues a login link for the account with this email,
+// returning its claims to sign and the account. It returns ErrUserNotFound
+// for unknown emails, and nil claims without error when a link went out
+// within magicLinkCooldown.
+func (r *EventRepository) CreateMagicLink(ctx context.Context, email string, now time.Time) (*magicLinkClaims, *User, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin magic link tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var user User
+	err = tx.QueryRowContext(ctx, selectAccountByEmail, strings.TrimSpace(email)).Scan(&user.ID, &user.Name, &user.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch magic link user: %w", err)
+	}
+
+	var recent int
+	err = tx.QueryRowContext(ctx, selectRecentMagicLink, user.ID, sqliteTime(now.Add(-magicLinkCooldown))).Scan(&recent)
+	if err == nil {
+		return nil, nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("check recent magic link: %w", err)
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, nil, fmt.Errorf("generate magic link nonce: %w", err)
+	}
+	claims := magicLinkClaims{UserID: user.ID, Nonce: base64.RawURLEncoding.EncodeToString(raw), ExpiresAt: now.Add(magicLinkTTL).UTC()}
+
+	if _, err := tx.ExecContext(ctx, deleteUnusedMagicLinks, user.ID); err != nil {
+		return nil, nil, fmt.Errorf("replace magic links: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, insertMagicLink, hashSecretToken(claims.Nonce), user.ID, sqliteTime(claims.ExpiresAt), sqliteTime(now)); err != nil {
+		return nil, nil, fmt.Errorf("store magic link: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("commit magic link: %w", err)
+	}
+	return &claims, &user, nil
+}
+
+// ConsumeMagicLink spends a login link and returns its account. It returns
+// ErrInvalidMagicLink if the link is unknown, used or expired, or the account
+// is gone.
+func (r *EventRepository) ConsumeMagicLink(ctx context.Context, claims magicLinkClaims, now time.Time) (*User, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin magic link sign-in tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, claimMagicLink, hashSecretToken(claims.Nonce), claims.UserID, sqliteTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("claim magic link: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("check rows affected: %w", err)
+	} else if affected == 0 {
+		return nil, ErrInvalidMagicLink
+	}
+
+	var user User
+	err = tx.QueryRowContext(ctx, selectSessionUser, claims.UserID).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidMagicLink
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch magic link user: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit magic link sign-in: %w", err)
+	}
+	return &user, nil
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type verifyMagicLinkRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// magicLinkURLFromEnv reads MAGIC_LINK_URL, the app screen login links
+// open; the token is added as the `token` query parameter.
+func magicLinkURLFromEnv() string {
+	if raw := strings.TrimSpace(os.Getenv("MAGIC_LINK_URL")); raw != "" {
+		return raw
+	}
+	return defaultMagicLinkURL
+}
+
+// requestMagicLink emails a login link to the account with this email. Like
+// password reset, the answer is the same whether or not the account exists
+// and the email is sent in the background.
+//
+// Responses:
+//  - 202 once the request is accepted
+//  - 400 for invalid JSON or email
+//  - 500 for repository/database failures
+//  - 503 if email is not configured
+func (h *AuthHandler) requestMagicLink(c *gin.Context) {
+	if h.mailer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "email login is not available"})
+		return
+	}
+
+	var payload magicLinkRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	claims, user, err := h.repo.CreateMagicLink(ctx, payload.Email, time.Now())
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to send login link"})
+		return
+	}
+	if user != nil {
+		token, err := h.signer.issueMagicLink(*claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to send login link"})
+			return
+		}
+		go sendEmail(context.Background(), h.mailer, magicLinkEmail, user.Email, magicLinkEmailData{
+			Name:      user.Name,
+			Link:      tokenLink(h.magicURL, token),
+			ExpiresIn: "15 minutes",
+		})
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If that account exists, a login link is on its way"})
+}
+
+// signInWithMagicLink exchanges the token from a login link for a session,
+// the same as login issues.
+//
+// Responses:
+//  - 200 with the user and session token
+//  - 400 for invalid JSON
+//  - 401 if the link is forged, unknown, used or expired
+//  - 500 for repository/database failures
+func (h *AuthHandler) signInWithMagicLink(c *gin.Context) {
+	var payload verifyMagicLinkRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := h.signer.verifyMagicLink(payload.Token)
+	if err != nil || time.Now().After(claims.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This login link is invalid or has expired"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	user, err := h.repo.ConsumeMagicLink(ctx, *claims, time.Now())
+	if err != nil {
+		if errors.Is(err, ErrInvalidMagicLink) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "This login link is invalid or has expired"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	h.respondWithSession(c, ctx, user)
+}
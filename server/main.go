@@ -3,13 +3,20 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 )
 
-const databasePath = "event.sqlite"
-
 func main() {
-	database, err := openDB(databasePath)
+    // Load optional server/.env so local dev can configure secrets easily.
+    loadServerEnv()
+
+	config, err := loadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+
+	database, err := openDB(config.DatabasePath, config.MaxDBConns)
 	if err != nil {
 		log.Fatalf("failed to open database: %v", err)
 	}
@@ -19,14 +26,18 @@ func main() {
 		}
 	}()
 
-    // Load optional server/.env so local dev can configure secrets easily.
-    loadServerEnv()
-
 	repo := NewEventRepository(database)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			log.Printf("error closing repository: %v", err)
+		}
+	}()
 
-	signer, err := newTokenSignerFromEnv()
+	signer := newTokenSigner(config)
+
+	tlsConfig, err := newTLSConfigFromEnv()
 	if err != nil {
-		log.Fatalf("failed to load session signer: %v", err)
+		log.Fatalf("failed to load TLS settings: %v", err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -36,17 +47,78 @@ func main() {
 		log.Fatalf("failed to run migrations: %v", err)
 	}
 
-	if err := repo.EnsureSeedData(ctx); err != nil {
-		log.Printf("failed to seed database: %v", err)
+	if seed := newSeedConfigFromEnv(); seed.Enabled {
+		fixtures, err := seed.load()
+		if err != nil {
+			log.Fatalf("failed to load seed data: %v", err)
+		}
+		if err := repo.EnsureSeedData(ctx, fixtures); err != nil {
+			log.Printf("failed to seed database: %v", err)
+		}
+	}
+
+	geocoder, err := newGeocoderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure geocoder: %v", err)
 	}
 
-	eventHandler := NewEventHandler(repo)
-	authHandler := NewAuthHandler(repo, signer)
-	chatHub := NewChatHub(repo, signer)
+	pusher, err := newPushSenderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure push notifications: %v", err)
+	}
+
+	mailer, err := newEmailSenderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure email: %v", err)
+	}
+	sms, err := newSMSSenderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure sms: %v", err)
+	}
+
+	images, err := newImageModeratorFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure image moderation: %v", err)
+	}
+
+	chatHub := NewChatHub(repo, signer, pusher, mailer, images, config.Chat)
 	go chatHub.Run()
-	srv := setupRouter(eventHandler, authHandler, chatHub, signer)
 
-	if err := srv.Run(); err != nil {
+	outbox := newOutboxDispatcher(repo, chatHub, newLinkPreviewerFromEnv(), config.Jobs)
+	go outbox.Run(context.Background())
+
+	jobs := NewJobRunner(repo)
+	newEventExpiryJob(repo, config.Jobs).Register(jobs)
+	newTrendingJob(repo, config.Jobs).Register(jobs)
+	newEventReminderJob(repo, chatHub, config.Jobs).Register(jobs)
+	newMinAttendeesJob(repo, chatHub, config.Jobs).Register(jobs)
+	newEventPurgeJob(repo, config.Jobs).Register(jobs)
+	newScheduledMessageJob(repo, chatHub, config.Jobs).Register(jobs)
+	newEmailDigestJob(repo, mailer, config.Jobs).Register(jobs)
+	outbox.RegisterPruning(jobs)
+	registerIdempotencyKeyPruning(jobs, repo)
+	registerWebhookDeliveryPruning(jobs, repo)
+	registerEventDeletionPruning(jobs, repo)
+	registerSessionPruning(jobs, repo)
+	jobs.Start(context.Background())
+
+	eventHandler := NewEventHandler(repo, geocoder, chatHub)
+	authHandler := NewAuthHandler(repo, signer, mailer, sms)
+	userHandler := NewUserHandler(repo, chatHub)
+	if grpcConfig := newGRPCConfigFromEnv(); grpcConfig.enabled() {
+		serveGRPC(grpcConfig, repo)
+	}
+
+	srv := setupRouter(config.HTTP, repo, eventHandler, authHandler, userHandler, chatHub, signer)
+
+	if tlsConfig.enabled() {
+		if err := serveTLS(tlsConfig, config.HTTP, srv); err != nil {
+			log.Fatalf("failed to start server: %v", err)
+		}
+		return
+	}
+	log.Printf("serving HTTP on %s", config.Addr)
+	if err := newHTTPServer(config.Addr, config.HTTP, srv).ListenAndServe(); err != nil {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
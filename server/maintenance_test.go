@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+// The maintenance package can't migrate a database itself, so its
+// statements are checked here against the schema Init builds.
+
+// mustCreatePastEvent stores an event hosted by hostID that started at
+// startsAt and is marked past, with a message in its chat.
+func mustCreatePastEvent(t *testing.T, repo *EventRepository, hostID int64, startsAt time.Time) int64 {
+	t.Helper()
+	ctx := context.Background()
+	id, err := repo.Create(ctx, CreateEventParams{
+		Title: "Evening run", Location: "Park", Time: "18:00", Gender: "Any",
+		MinAge: 18, MaxAge: 99, DateLabel: "Today", UserID: hostID,
+	})
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	if _, err := repo.db.Exec(`UPDATE events SET status = 'past', starts_at = ? WHERE id = ?`, sqliteTime(startsAt), id); err != nil {
+		t.Fatalf("mark event past: %v", err)
+	}
+	if _, err := repo.db.Exec(`INSERT INTO messages (conversation_id, sender_id, body) SELECT id, ?, 'See you there' FROM conversations WHERE event_id = ?`, hostID, id); err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	return id
+}
+
+func TestPurgePastEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	hostID := mustCreateUser(t, repo, "ava")
+	now := time.Now()
+	oldID := mustCreatePastEvent(t, repo, hostID, now.Add(-maintenance.DefaultPurgeAge-time.Hour))
+	recentID := mustCreatePastEvent(t, repo, hostID, now.Add(-time.Hour))
+	cutoff := now.Add(-maintenance.DefaultPurgeAge)
+
+	ids, err := maintenance.PurgePastEvents(ctx, repo.db, cutoff, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != oldID {
+		t.Fatalf("dry run would purge %v, want [%d]", ids, oldID)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM events`); got != 2 {
+		t.Fatalf("events after dry run = %d, want 2", got)
+	}
+
+	if _, err := maintenance.PurgePastEvents(ctx, repo.db, cutoff, false); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM events WHERE id = ?`, oldID); got != 0 {
+		t.Fatalf("old event kept")
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM conversations WHERE event_id = ?`, oldID); got != 0 {
+		t.Fatalf("old event's chat kept")
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM messages m JOIN conversations c ON c.id = m.conversation_id WHERE c.event_id = ?`, recentID); got != 1 {
+		t.Fatalf("recent event's messages = %d, want 1", got)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM messages`); got != 1 {
+		t.Fatalf("messages after purge = %d, want only the recent event's", got)
+	}
+}
+
+func TestMaintenanceUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	avaID := mustCreateUser(t, repo, "ava")
+
+	if _, err := maintenance.CreateUser(ctx, repo.db, "Ava again", "ava@example.com", "password", false); !errors.Is(err, maintenance.ErrEmailTaken) {
+		t.Fatalf("duplicate email error = %v, want %v", err, maintenance.ErrEmailTaken)
+	}
+	if err := maintenance.SetPassword(ctx, repo.db, "nobody@example.com", "password"); !errors.Is(err, maintenance.ErrUserNotFound) {
+		t.Fatalf("unknown email error = %v, want %v", err, maintenance.ErrUserNotFound)
+	}
+
+	if err := maintenance.SetAdmin(ctx, repo.db, "ava@example.com", true); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	roles, err := repo.UserRoles(ctx, avaID)
+	if err != nil || len(roles) != 2 || roles[1] != roleAdmin {
+		t.Fatalf("roles after promote = %v, %v; want user and admin", roles, err)
+	}
+	sessionID, err := repo.CreateSession(ctx, avaID, "Ava's phone", "192.0.2.1", "", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	// Demoting signs the account out, since its tokens carry the admin role.
+	if err := maintenance.SetAdmin(ctx, repo.db, "ava@example.com", false); err != nil {
+		t.Fatalf("demote: %v", err)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM user_sessions WHERE id = ? AND revoked_at IS NULL`, sessionID); got != 0 {
+		t.Fatalf("live sessions after demote = %d, want 0", got)
+	}
+}
+
+func TestMaintenanceCompact(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	var path string
+	if err := repo.db.QueryRow(`SELECT file FROM pragma_database_list WHERE name = 'main'`).Scan(&path); err != nil {
+		t.Fatalf("find database file: %v", err)
+	}
+	before, after, err := maintenance.Compact(ctx, repo.db, path)
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if before == 0 || after == 0 {
+		t.Fatalf("compacted %d -> %d bytes, want both sizes measured", before, after)
+	}
+	if err := maintenance.RequireSchema(ctx, repo.db); err != nil {
+		t.Fatalf("schema after compact: %v", err)
+	}
+}
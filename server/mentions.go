@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const createTableMessageMentions = `
+CREATE TABLE IF NOT EXISTS message_mentions (
+    message_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (message_id, user_id),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexMessageMentionsUser = `
+CREATE INDEX IF NOT EXISTS idx_message_mentions_user
+ON message_mentions(user_id);
+`
+
+const insertMessageMention = `
+INSERT OR IGNORE INTO message_mentions (message_id, user_id)
+VALUES (?, ?);
+`
+
+// selectMentionsForMessages expects the message ID placeholders to be filled in.
+const selectMentionsForMessages = `
+SELECT mm.message_id, u.id, u.name
+FROM message_mentions mm
+JOIN users u ON u.id = mm.user_id
+WHERE mm.message_id IN (%s)
+ORDER BY mm.message_id, u.name;
+`
+
+func (r *EventRepository) initMentions(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableMessageMentions); err != nil {
+		return fmt.Errorf("create message mentions table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexMessageMentionsUser); err != nil {
+		return fmt.Errorf("create message mentions index: %w", err)
+	}
+	return nil
+}
+
+// parseMentions finds `@name` references to conversation members in body. A
+// member can be mentioned by full name or first name, case-insensitively; the
+// longest match wins and the sender is never mentioned.
+func parseMentions(body string, senderID int64, members []ConversationParticipant) []MessageMention {
+	if !strings.Contains(body, "@") {
+		return nil
+	}
+	lower := strings.ToLower(body)
+
+	var mentions []MessageMention
+	seen := make(map[int64]struct{})
+	for i := strings.IndexByte(lower, '@'); i >= 0; {
+		// Skip e-mail addresses and similar, where @ follows a word character.
+		if before, _ := utf8.DecodeLastRuneInString(lower[:i]); i == 0 || !isMentionRune(before) {
+			if member, ok := matchMention(lower[i+1:], members); ok && member.ID != senderID {
+				if _, dup := seen[member.ID]; !dup {
+					seen[member.ID] = struct{}{}
+					mentions = append(mentions, MessageMention{UserID: member.ID, Name: member.Name})
+				}
+			}
+		}
+
+		next := strings.IndexByte(lower[i+1:], '@')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return mentions
+}
+
+// matchMention returns the member whose name (or first name) starts rest and
+// ends on a word boundary.
+func matchMention(rest string, members []ConversationParticipant) (ConversationParticipant, bool) {
+	var best ConversationParticipant
+	bestLen := 0
+	for _, member := range members {
+		full := strings.ToLower(strings.TrimSpace(member.Name))
+		candidates := []string{full}
+		if first, _, found := strings.Cut(full, " "); found {
+			candidates = append(candidates, first)
+		}
+		for _, candidate := range candidates {
+			if candidate == "" || len(candidate) <= bestLen || !strings.HasPrefix(rest, candidate) {
+				continue
+			}
+			if after, _ := utf8.DecodeRuneInString(rest[len(candidate):]); len(rest) > len(candidate) && isMentionRune(after) {
+				continue
+			}
+			best, bestLen = member, len(candidate)
+		}
+	}
+	return best, bestLen > 0
+}
+
+func isMentionRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// storeMentions resolves and saves the members mentioned in a new message.
+func storeMentions(ctx context.Context, tx *sql.Tx, msg *Message) error {
+	if !strings.Contains(msg.Body, "@") {
+		return nil
+	}
+	members, _, err := fetchConversationParticipants(ctx, tx, msg.ConversationID)
+	if err != nil {
+		return err
+	}
+	msg.Mentions = parseMentions(msg.Body, msg.SenderID, members)
+	for _, mention := range msg.Mentions {
+		if _, err := tx.ExecContext(ctx, insertMessageMention, msg.ID, mention.UserID); err != nil {
+			return fmt.Errorf("insert message mention: %w", err)
+		}
+	}
+	return nil
+}
+
+// attachMentions loads mentions for a page of messages in one query.
+func (r *EventRepository) attachMentions(ctx context.Context, messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]int, len(messages))
+	args := make([]any, 0, len(messages))
+	for i := range messages {
+		index[messages[i].ID] = i
+		args = append(args, messages[i].ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectMentionsForMessages, placeholders(len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("query message mentions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID int64
+		var mention MessageMention
+		if err := rows.Scan(&messageID, &mention.UserID, &mention.Name); err != nil {
+			return fmt.Errorf("scan message mention: %w", err)
+		}
+		if i, ok := index[messageID]; ok {
+			messages[i].Mentions = append(messages[i].Mentions, mention)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate message mentions: %w", err)
+	}
+	return nil
+}
+
+// mentionEvent tells a mentioned user about the message, even when they have
+// muted the conversation or are not subscribed to it on this socket.
+type mentionEvent struct {
+	Type    string         `json:"type"`
+	Message messagePayload `json:"message"`
+}
+
+// notifyMentions sends `message:mention` to each mentioned user's sockets.
+func (h *ChatHub) notifyMentions(msg Message) {
+	if len(msg.Mentions) == 0 {
+		return
+	}
+	payload, err := json.Marshal(mentionEvent{Type: "message:mention", Message: newMessagePayload(msg)})
+	if err != nil {
+		log.Printf("marshal mention event failed: %v", err)
+		return
+	}
+	for _, mention := range msg.Mentions {
+		h.NotifyUser(mention.UserID, payload)
+	}
+}
@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// What happens to a message a filter catches, strongest first. Hidden
+// messages are stored and echoed to the sender only, so a spammer can't tell
+// nobody else sees them; hidden and flagged messages both land in the review
+// queue.
+const (
+	filterActionReject = "reject"
+	filterActionHide   = "hide"
+	filterActionFlag   = "flag"
+)
+
+var filterActionRank = map[string]int{filterActionFlag: 1, filterActionHide: 2, filterActionReject: 3}
+
+var ErrFilteredWordNotFound = errors.New("filtered word not found")
+
+// linkPattern matches what clients render as links.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// MessageFilterConfig tunes the filters handleSend runs before storing a
+// message. Each filter has its own action.
+type MessageFilterConfig struct {
+	WordAction   string        // for messages containing a word on the admin list, unless the word sets its own
+	MaxLinks     int           // links allowed per message; 0 disables the check
+	LinkAction   string        // for messages with more than MaxLinks links
+	RepeatLimit  int           // identical messages a user may send per RepeatWindow; 0 disables the check
+	RepeatWindow time.Duration // sliding window for RepeatLimit
+	RepeatAction string        // for messages past RepeatLimit
+
+	NewMemberWindow       time.Duration // how long after joining a member counts as new; see newMemberFilter
+	NewMemberMessageLimit int           // messages a new member may send within NewMemberWindow; 0 disables the cap
+}
+
+func defaultMessageFilterConfig() MessageFilterConfig {
+	return MessageFilterConfig{
+		WordAction:   filterActionReject,
+		MaxLinks:     3,
+		LinkAction:   filterActionReject,
+		RepeatLimit:  3,
+		RepeatWindow: time.Minute,
+		RepeatAction: filterActionReject,
+
+		NewMemberWindow:       10 * time.Minute,
+		NewMemberMessageLimit: 5,
+	}
+}
+
+// newMessageFilterConfigFromEnv overrides the defaults with
+// MESSAGE_FILTER_WORD_ACTION, MESSAGE_FILTER_MAX_LINKS,
+// MESSAGE_FILTER_LINK_ACTION, MESSAGE_FILTER_REPEAT_LIMIT,
+// MESSAGE_FILTER_REPEAT_WINDOW, MESSAGE_FILTER_REPEAT_ACTION,
+// MESSAGE_FILTER_NEW_MEMBER_WINDOW and MESSAGE_FILTER_NEW_MEMBER_MESSAGE_LIMIT.
+// Invalid values are logged and ignored.
+func newMessageFilterConfigFromEnv() MessageFilterConfig {
+	config := defaultMessageFilterConfig()
+	config.WordAction = envFilterAction("MESSAGE_FILTER_WORD_ACTION", config.WordAction)
+	config.MaxLinks = envPositiveInt("MESSAGE_FILTER_MAX_LINKS", config.MaxLinks, true)
+	config.LinkAction = envFilterAction("MESSAGE_FILTER_LINK_ACTION", config.LinkAction)
+	config.RepeatLimit = envPositiveInt("MESSAGE_FILTER_REPEAT_LIMIT", config.RepeatLimit, true)
+	config.RepeatWindow = envPositiveDuration("MESSAGE_FILTER_REPEAT_WINDOW", config.RepeatWindow)
+	config.RepeatAction = envFilterAction("MESSAGE_FILTER_REPEAT_ACTION", config.RepeatAction)
+	config.NewMemberWindow = envPositiveDuration("MESSAGE_FILTER_NEW_MEMBER_WINDOW", config.NewMemberWindow)
+	config.NewMemberMessageLimit = envPositiveInt("MESSAGE_FILTER_NEW_MEMBER_MESSAGE_LIMIT", config.NewMemberMessageLimit, true)
+	return config
+}
+
+func envFilterAction(name, fallback string) string {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv(name)))
+	if raw == "" {
+		return fallback
+	}
+	if _, ok := filterActionRank[raw]; !ok {
+		log.Printf("invalid %s %q; using %s", name, raw, fallback)
+		return fallback
+	}
+	return raw
+}
+
+// MessageFilterInput is a message about to be stored.
+type MessageFilterInput struct {
+	ConversationID int64
+	SenderID       int64
+	Body           string
+	AttachmentURL  string // empty without an attachment
+	Now            time.Time
+}
+
+// filterVerdict is a filter's objection to a message. Code is the
+// `system:error` code a rejected sender gets, with RetryAfter as
+// `retryAfterSeconds` when the objection wears off by itself.
+type filterVerdict struct {
+	Filter     string
+	Action     string
+	Code       string
+	Reason     string
+	RetryAfter time.Duration
+}
+
+// MessageFilter inspects a message before it is stored and returns nil to let
+// it through.
+type MessageFilter interface {
+	Check(ctx context.Context, input MessageFilterInput) (*filterVerdict, error)
+}
+
+// messageFilterChain runs filters in order and keeps the strongest verdict;
+// a reject stops the chain. A filter that fails is logged and skipped, so an
+// outage never blocks chat.
+type messageFilterChain []MessageFilter
+
+func newMessageFilterChain(repo Store, config MessageFilterConfig, images ImageModerator) messageFilterChain {
+	chain := messageFilterChain{wordListFilter{repo: repo, action: config.WordAction}}
+	if config.MaxLinks > 0 {
+		chain = append(chain, linkLimitFilter{max: config.MaxLinks, action: config.LinkAction})
+	}
+	chain = append(chain, newMemberFilter{repo: repo, window: config.NewMemberWindow, limit: config.NewMemberMessageLimit})
+	if config.RepeatLimit > 0 {
+		chain = append(chain, repeatFilter{repo: repo, limit: config.RepeatLimit, window: config.RepeatWindow, action: config.RepeatAction})
+	}
+	return append(chain, imageModerationFilter{moderator: images})
+}
+
+func (chain messageFilterChain) Run(ctx context.Context, input MessageFilterInput) *filterVerdict {
+	var strongest *filterVerdict
+	for _, filter := range chain {
+		verdict, err := filter.Check(ctx, input)
+		if err != nil {
+			log.Printf("message filter failed: %v", err)
+			continue
+		}
+		if verdict == nil {
+			continue
+		}
+		if strongest == nil || filterActionRank[verdict.Action] > filterActionRank[strongest.Action] {
+			strongest = verdict
+		}
+		if strongest.Action == filterActionReject {
+			break
+		}
+	}
+	return strongest
+}
+
+// normalizeFilterText lowercases text and reduces it to its words separated
+// by single spaces, so "F.o.o" and "foo!" don't slip past each other.
+func normalizeFilterText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// wordListFilter catches messages containing a word or phrase from the
+// admin-managed list. Only whole words match.
+type wordListFilter struct {
+	repo   Store
+	action string
+}
+
+func (f wordListFilter) Check(ctx context.Context, input MessageFilterInput) (*filterVerdict, error) {
+	words, err := f.repo.ListFilteredWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	body := " " + normalizeFilterText(input.Body) + " "
+	var verdict *filterVerdict
+	for _, word := range words {
+		if !strings.Contains(body, " "+word.Word+" ") {
+			continue
+		}
+		action := f.action
+		if word.Action != nil {
+			action = *word.Action
+		}
+		if verdict == nil || filterActionRank[action] > filterActionRank[verdict.Action] {
+			verdict = &filterVerdict{Filter: "word_list", Action: action, Code: "message_blocked", Reason: fmt.Sprintf("contains %q", word.Word)}
+		}
+	}
+	return verdict, nil
+}
+
+// linkLimitFilter catches messages with too many links.
+type linkLimitFilter struct {
+	max    int
+	action string
+}
+
+func (f linkLimitFilter) Check(_ context.Context, input MessageFilterInput) (*filterVerdict, error) {
+	if count := len(linkPattern.FindAllStringIndex(input.Body, f.max+1)); count > f.max {
+		return &filterVerdict{Filter: "links", Action: f.action, Code: "too_many_links", Reason: fmt.Sprintf("more than %d links", f.max)}, nil
+	}
+	return nil, nil
+}
+
+// repeatFilter catches a user sending the same text over and over, in one
+// chat or across several. Messages with an attachment are left to the image
+// moderator, since a burst of photos often shares a caption or has none.
+type repeatFilter struct {
+	repo   Store
+	limit  int
+	window time.Duration
+	action string
+}
+
+func (f repeatFilter) Check(ctx context.Context, input MessageFilterInput) (*filterVerdict, error) {
+	if input.AttachmentURL != "" {
+		return nil, nil
+	}
+	count, err := f.repo.CountRecentIdenticalMessages(ctx, input.SenderID, input.Body, input.Now.Add(-f.window))
+	if err != nil {
+		return nil, err
+	}
+	if count >= f.limit {
+		return &filterVerdict{Filter: "repeat", Action: f.action, Code: "repeated_message", Reason: fmt.Sprintf("sent %d times within %s", count+1, f.window)}, nil
+	}
+	return nil, nil
+}
+
+const createTableFilteredWords = `
+CREATE TABLE IF NOT EXISTS filtered_words (
+    word TEXT PRIMARY KEY,
+    action TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const createTableMessageFlags = `
+CREATE TABLE IF NOT EXISTS message_flags (
+    message_id INTEGER PRIMARY KEY,
+    filter TEXT NOT NULL,
+    action TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE
+);
+`
+
+const createIndexMessagesSender = `
+CREATE INDEX IF NOT EXISTS idx_messages_sender_created
+ON messages (sender_id, created_at);
+`
+
+const selectFilteredWords = `
+SELECT word, action, created_at FROM filtered_words ORDER BY word;
+`
+
+const upsertFilteredWord = `
+INSERT INTO filtered_words (word, action)
+VALUES (?, ?)
+ON CONFLICT(word) DO UPDATE SET action = excluded.action;
+`
+
+const deleteFilteredWord = `
+DELETE FROM filtered_words WHERE word = ?;
+`
+
+const countRecentIdenticalMessages = `
+SELECT COUNT(1)
+FROM messages
+WHERE sender_id = ? AND kind = 'user' AND body = ? AND created_at >= ?;
+`
+
+const insertMessageFlag = `
+INSERT OR REPLACE INTO message_flags (message_id, filter, action, reason)
+VALUES (?, ?, ?, ?);
+`
+
+const selectMessageFlags = `
+SELECT f.message_id, m.conversation_id, m.sender_id, m.body, f.filter, f.action, f.reason, f.created_at
+FROM message_flags f
+JOIN messages m ON m.id = f.message_id
+ORDER BY f.created_at DESC, f.message_id DESC
+LIMIT ?;
+`
+
+// maxMessageFlagsListed caps the review queue listing.
+const maxMessageFlagsListed = 200
+
+func (r *EventRepository) initMessageFilters(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableFilteredWords); err != nil {
+		return fmt.Errorf("create filtered words table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableMessageFlags); err != nil {
+		return fmt.Errorf("create message flags table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexMessagesSender); err != nil {
+		return fmt.Errorf("create messages sender index: %w", err)
+	}
+	return nil
+}
+
+// ListFilteredWords returns the admin word list.
+func (r *EventRepository) ListFilteredWords(ctx context.Context) ([]FilteredWord, error) {
+	rows, err := r.db.QueryContext(ctx, selectFilteredWords)
+	if err != nil {
+		return nil, fmt.Errorf("list filtered words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []FilteredWord{}
+	for rows.Next() {
+		var word FilteredWord
+		var action sql.NullString
+		if err := rows.Scan(&word.Word, &action, &word.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan filtered word: %w", err)
+		}
+		if action.Valid {
+			word.Action = &action.String
+		}
+		words = append(words, word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate filtered words: %w", err)
+	}
+	return words, nil
+}
+
+// AddFilteredWords adds words to the list, or changes the action of ones
+// already on it. A nil action means the configured default.
+func (r *EventRepository) AddFilteredWords(ctx context.Context, words []string, action *string) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin filtered words tx: %w", err)
+	}
+	for _, word := range words {
+		if _, err := tx.ExecContext(ctx, upsertFilteredWord, word, action); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("insert filtered word: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit filtered words: %w", err)
+	}
+	return nil
+}
+
+// DeleteFilteredWord takes a word off the list.
+func (r *EventRepository) DeleteFilteredWord(ctx context.Context, word string) error {
+	result, err := r.db.ExecContext(ctx, deleteFilteredWord, word)
+	if err != nil {
+		return fmt.Errorf("delete filtered word: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrFilteredWordNotFound
+	}
+	return nil
+}
+
+// CountRecentIdenticalMessages counts the user's messages with exactly this
+// body sent since the given time, in any conversation.
+func (r *EventRepository) CountRecentIdenticalMessages(ctx context.Context, senderID int64, body string, since time.Time) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, countRecentIdenticalMessages, senderID, body, sqliteTime(since)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count identical messages: %w", err)
+	}
+	return count, nil
+}
+
+// FlagMessage puts a stored message in the review queue.
+func (r *EventRepository) FlagMessage(ctx context.Context, messageID int64, filter, action, reason string) error {
+	if _, err := r.db.ExecContext(ctx, insertMessageFlag, messageID, filter, action, reason); err != nil {
+		return fmt.Errorf("flag message: %w", err)
+	}
+	return nil
+}
+
+// ListMessageFlags returns the review queue, newest first.
+func (r *EventRepository) ListMessageFlags(ctx context.Context) ([]MessageFlag, error) {
+	rows, err := r.db.QueryContext(ctx, selectMessageFlags, maxMessageFlagsListed)
+	if err != nil {
+		return nil, fmt.Errorf("list message flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := []MessageFlag{}
+	for rows.Next() {
+		var flag MessageFlag
+		if err := rows.Scan(&flag.MessageID, &flag.ConversationID, &flag.SenderID, &flag.Body, &flag.Filter, &flag.Action, &flag.Reason, &flag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan message flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate message flags: %w", err)
+	}
+	return flags, nil
+}
+
+// MessageFilterHandler serves the admin word list and review queue.
+type MessageFilterHandler struct {
+	repo Store
+}
+
+func NewMessageFilterHandler(repo Store) *MessageFilterHandler {
+	return &MessageFilterHandler{repo: repo}
+}
+
+// registerAdminMessageFilterRoutes mounts the word list and review queue
+// under /admin behind admin auth.
+func registerAdminMessageFilterRoutes(r *gin.Engine, adminAuth gin.HandlerFunc, handler *MessageFilterHandler) {
+	admin := r.Group("/admin", adminAuth)
+	admin.GET("/filtered-words", handler.listFilteredWords)
+	admin.POST("/filtered-words", handler.addFilteredWords)
+	admin.DELETE("/filtered-words/:word", handler.deleteFilteredWord)
+	admin.GET("/message-flags", handler.listMessageFlags)
+}
+
+// listFilteredWords returns the word list.
+func (h *MessageFilterHandler) listFilteredWords(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	words, err := h.repo.ListFilteredWords(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list filtered words"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"words": words})
+}
+
+// addFilteredWords adds words or phrases to the list. Matching ignores case
+// and punctuation, so entries are stored in that normalized form. `action`
+// overrides MESSAGE_FILTER_WORD_ACTION for these words.
+//
+// Responses:
+//  - 200 with the whole list
+//  - 400 for invalid JSON, an unknown action, or an entry without letters or
+//    digits
+//  - 500 for repository/database failures
+func (h *MessageFilterHandler) addFilteredWords(c *gin.Context) {
+	var payload AddFilteredWordsParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	words := make([]string, 0, len(payload.Words))
+	for _, raw := range payload.Words {
+		word := normalizeFilterText(raw)
+		if word == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%q has no letters or digits", raw)})
+			return
+		}
+		words = append(words, word)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.AddFilteredWords(ctx, words, payload.Action); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save filtered words"})
+		return
+	}
+	list, err := h.repo.ListFilteredWords(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list filtered words"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"words": list})
+}
+
+// deleteFilteredWord takes a word off the list.
+//
+// Responses:
+//  - 204 on success
+//  - 404 if the word isn't on the list
+//  - 500 for repository/database failures
+func (h *MessageFilterHandler) deleteFilteredWord(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	err := h.repo.DeleteFilteredWord(ctx, normalizeFilterText(c.Param("word")))
+	if err != nil {
+		if errors.Is(err, ErrFilteredWordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "word not on the list"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete filtered word"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// listMessageFlags returns the latest flagged and hidden messages for review.
+func (h *MessageFilterHandler) listMessageFlags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	flags, err := h.repo.ListMessageFlags(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list flagged messages"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"flags": flags})
+}
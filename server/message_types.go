@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message types say how clients render a message. Every type has a plain
+// body too, so older clients, previews and pushes still show something.
+const (
+	messageTypeText     = "text"
+	messageTypeLocation = "location"
+	messageTypePoll     = "poll" // only created through the polls endpoint
+)
+
+// maxLocationLabelLength caps the place name shown on a location pin.
+const maxLocationLabelLength = 120
+
+var ErrInvalidMessageType = errors.New("unknown message type")
+var ErrInvalidMessageMetadata = errors.New("invalid message metadata")
+
+// LocationMetadata is the metadata of a location message: a map pin with an
+// optional label, e.g. "Park entrance".
+type LocationMetadata struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+	Label     string  `json:"label,omitempty"`
+}
+
+// normalizeMessageContent checks a message's type and metadata and returns
+// the type, the metadata re-encoded in canonical form, and the body to store.
+// An empty type means text; text messages carry no metadata. A location
+// without a body gets its label, or a generic line, as the body.
+func normalizeMessageContent(messageType string, metadata json.RawMessage, body string) (string, json.RawMessage, string, error) {
+	switch strings.TrimSpace(messageType) {
+	case "", messageTypeText:
+		if len(metadata) > 0 && string(metadata) != "null" {
+			return "", nil, "", fmt.Errorf("%w: text messages take no metadata", ErrInvalidMessageMetadata)
+		}
+		return messageTypeText, nil, body, nil
+	case messageTypeLocation:
+		var raw struct {
+			Latitude  *float64 `json:"lat"`
+			Longitude *float64 `json:"lng"`
+			Label     string   `json:"label"`
+		}
+		if err := json.Unmarshal(metadata, &raw); err != nil || raw.Latitude == nil || raw.Longitude == nil {
+			return "", nil, "", fmt.Errorf("%w: location needs lat and lng", ErrInvalidMessageMetadata)
+		}
+		location := LocationMetadata{Latitude: *raw.Latitude, Longitude: *raw.Longitude, Label: raw.Label}
+		if location.Latitude < -90 || location.Latitude > 90 || location.Longitude < -180 || location.Longitude > 180 {
+			return "", nil, "", fmt.Errorf("%w: lat must be within ±90 and lng within ±180", ErrInvalidMessageMetadata)
+		}
+		location.Label = strings.TrimSpace(location.Label)
+		if utf8.RuneCountInString(location.Label) > maxLocationLabelLength {
+			return "", nil, "", fmt.Errorf("%w: label is longer than %d characters", ErrInvalidMessageMetadata, maxLocationLabelLength)
+		}
+		encoded, err := json.Marshal(location)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("encode location: %w", err)
+		}
+		if strings.TrimSpace(body) == "" {
+			body = "Shared a location"
+			if location.Label != "" {
+				body = "📍 " + location.Label
+			}
+		}
+		return messageTypeLocation, encoded, body, nil
+	default:
+		return "", nil, "", fmt.Errorf("%w %q", ErrInvalidMessageType, messageType)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -25,7 +26,7 @@ func bearerTokenFromHeader(header string) string {
 	return strings.TrimSpace(parts[1])
 }
 
-func sessionMiddleware(signer *tokenSigner) gin.HandlerFunc {
+func sessionMiddleware(signer *tokenSigner, repo UserStore) gin.HandlerFunc {
     // sessionMiddleware is applied to REST routes that require authentication.
     // It pulls the bearer token, validates it, and stashes the claims on the context
     // so handlers can trust the user identity.
@@ -46,6 +47,20 @@ func sessionMiddleware(signer *tokenSigner) gin.HandlerFunc {
 			return
 		}
 
+		// Signed-out devices and deleted accounts are revoked by checking the
+		// stored session on every request.
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+		active, err := sessionActive(ctx, repo, claims, c.ClientIP())
+		cancel()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to verify session"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+
 		c.Set(string(sessionContextKey), claims)
 		c.Next()
 	}
@@ -60,3 +75,103 @@ func sessionFromContext(c *gin.Context) (*sessionClaims, bool) {
 	claims, ok := value.(*sessionClaims)
 	return claims, ok
 }
+
+// optionalSessionMiddleware attaches claims when a valid bearer token is sent
+// but lets anonymous callers through, so public routes can personalize output.
+func optionalSessionMiddleware(signer *tokenSigner, repo UserStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerTokenFromHeader(c.GetHeader("Authorization"))
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		claims, err := signer.verify(token)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+		active, err := sessionActive(ctx, repo, claims, c.ClientIP())
+		cancel()
+		if err != nil || !active || !claims.hasRole(roleUser) {
+			c.Next()
+			return
+		}
+
+		c.Set(string(sessionContextKey), claims)
+		c.Next()
+	}
+}
+
+// requireRole lets through only tokens granted role. It runs after
+// sessionMiddleware, which has already checked the token is live.
+func requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := sessionFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+			return
+		}
+		if !claims.hasRole(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token lacks the " + role + " role"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// adminMiddleware guards the /admin and /debug routes. A session token with
+// the admin role or an API key granted scope works as well as the
+// ADMIN_USERNAME basic-auth account; either way gin.AuthUserKey is set, which
+// admin handlers read to tell an admin from a signed-in user.
+func adminMiddleware(accounts gin.Accounts, signer *tokenSigner, repo Store, scope string) gin.HandlerFunc {
+	var basicAuth gin.HandlerFunc
+	if len(accounts) > 0 {
+		basicAuth = gin.BasicAuth(accounts)
+	}
+	keyAuth := apiKeyMiddleware(repo, scope)
+	return func(c *gin.Context) {
+		if c.GetHeader(apiKeyHeader) != "" {
+			keyAuth(c)
+			return
+		}
+
+		token := bearerTokenFromHeader(c.GetHeader("Authorization"))
+		if token == "" {
+			if basicAuth == nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing authorization"})
+				return
+			}
+			basicAuth(c)
+			return
+		}
+
+		claims, err := signer.verify(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+		active, err := sessionActive(ctx, repo, claims, c.ClientIP())
+		cancel()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to verify session"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+		if !claims.hasRole(roleAdmin) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token lacks the admin role"})
+			return
+		}
+
+		c.Set(string(sessionContextKey), claims)
+		c.Set(gin.AuthUserKey, claims.Email)
+		c.Next()
+	}
+}
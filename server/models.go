@@ -1,20 +1,59 @@
 package main
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Event struct {
-	ID          int64     `json:"id"`
-	UserID      int64     `json:"user_id"`
-	Title       string    `json:"title"`
-	Location    string    `json:"location"`
-	Time        string    `json:"time"`
-	Description string    `json:"description"`
-	Gender      string    `json:"gender"`
-	MinAge      int       `json:"min_age"`
-	MaxAge      int       `json:"max_age"`
-	DateLabel   string    `json:"date_label"`
-	HostName    string    `json:"host_name"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int64  `json:"id"`
+	UserID      int64  `json:"user_id"`
+	Title       string `json:"title"`
+	Location    string `json:"location"`
+	Time        string `json:"time"`
+	Description string `json:"description"`
+	Gender      string `json:"gender"`
+	MinAge      int    `json:"min_age"`
+	MaxAge      int    `json:"max_age"`
+	DateLabel   string `json:"date_label"`
+	HostName    string `json:"host_name"`
+	// HostVerified is whether an admin has verified the host.
+	HostVerified bool      `json:"host_verified"`
+	CreatedAt    time.Time `json:"created_at"`
+	Capacity     *int      `json:"capacity,omitempty"` // max chat members incl. host; nil is unlimited
+	// MinAttendees is how many chat members, host included, the event needs
+	// by the cutoff before it starts, or it is cancelled.
+	MinAttendees *int       `json:"min_attendees,omitempty"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	Status       string     `json:"status"`
+	Latitude     *float64   `json:"latitude,omitempty"`
+	Longitude    *float64   `json:"longitude,omitempty"`
+	PlaceName    *string    `json:"place_name,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Bookmarked   *bool      `json:"bookmarked,omitempty"`
+	// StrictEligibility rejects join requests that fail the gender/age filters
+	// instead of just flagging them.
+	StrictEligibility bool `json:"strict_eligibility"`
+	// Visibility is public, link_only or private.
+	Visibility string `json:"visibility"`
+	// HideExactLocation shows people outside the event chat only Area and
+	// rounded coordinates; LocationApproximate marks a payload shaped that
+	// way.
+	HideExactLocation   bool    `json:"hide_exact_location"`
+	Area                *string `json:"area,omitempty"`
+	LocationApproximate bool    `json:"location_approximate,omitempty"`
+	// MemberCount is the event chat's size, host included. PendingRequestCount
+	// is only filled in for the host.
+	MemberCount         int  `json:"member_count"`
+	PendingRequestCount *int `json:"pending_request_count,omitempty"`
+	// HostRating aggregates the host's reviews across all their events.
+	HostRating *HostRating `json:"host_rating,omitempty"`
+}
+
+// Tag is an interest category events can be labelled with.
+type Tag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
 }
 
 type User struct {
@@ -25,12 +64,77 @@ type User struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type Conversation struct {
+// UserProfile is the signed-in user's own view of their account.
+type UserProfile struct {
 	ID        int64     `json:"id"`
-	Title     *string   `json:"title,omitempty"`
-	CreatedBy int64     `json:"created_by"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     *string   `json:"phone,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
-	EventID   *int64    `json:"event_id,omitempty"`
+	Gender    *string   `json:"gender,omitempty"`
+	BirthDate *string   `json:"birth_date,omitempty"`
+	Interests []string  `json:"interests"`
+	IsAdmin   bool      `json:"is_admin"`
+	// Verified is set once an admin approves the user's verification request.
+	Verified bool `json:"verified"`
+	// HostRating is nil until someone reviews an event the user hosted.
+	HostRating *HostRating `json:"host_rating,omitempty"`
+	Attendance Attendance  `json:"attendance"`
+}
+
+type UpdateProfileParams struct {
+	Gender    *string `json:"gender" binding:"omitempty,oneof=Male Female Other"`
+	BirthDate *string `json:"birth_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type UpdateInterestsParams struct {
+	Tags []string `json:"tags" binding:"max=10"`
+}
+
+// Availability is a window during which a user has said they're free.
+type Availability struct {
+	UserID    int64     `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Note      *string   `json:"note,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SetAvailabilityParams struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Note     *string   `json:"note" binding:"omitempty,max=140"`
+}
+
+// Connection is a friend link (or pending request) seen from one side; UserID
+// and Name describe the other person.
+type Connection struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Direction   string     `json:"direction"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+type SendConnectionParams struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+type Conversation struct {
+	ID         int64      `json:"id"`
+	Title      *string    `json:"title,omitempty"`
+	CreatedBy  int64      `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EventID    *int64     `json:"event_id,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// SlowModeSeconds is how long each member must wait between messages in
+	// an event chat; 0 means slow mode is off.
+	SlowModeSeconds int `json:"slow_mode_seconds,omitempty"`
+	// Locked conversations are read-only; see conversationLockedCode.
+	Locked bool `json:"locked"`
 }
 
 type ConversationMember struct {
@@ -40,16 +144,209 @@ type ConversationMember struct {
 	Role           string    `json:"role"`
 }
 
+// EventMember is a roster entry for an event's group chat.
+type EventMember struct {
+	UserID   int64     `json:"user_id"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// EventBan is a user the host or a co-host removed and barred from rejoining.
+type EventBan struct {
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	BannedBy  int64     `json:"banned_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ShadowBan is a conversation member whose messages only they can see.
+type ShadowBan struct {
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	BannedBy  int64     `json:"banned_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Message struct {
-	ID             int64     `json:"id"`
+	ID             int64            `json:"id"`
+	ConversationID int64            `json:"conversation_id"`
+	SenderID       int64            `json:"sender_id"`
+	Body           string           `json:"body"`
+	AttachmentURL  *string          `json:"attachment_url,omitempty"`
+	DeliveryStatus string           `json:"delivery_status"`
+	Kind           string           `json:"kind"`
+	MessageType    string           `json:"message_type"`       // "text", "location" or "poll"; see message_types.go
+	Metadata       json.RawMessage  `json:"metadata,omitempty"` // type-specific, e.g. LocationMetadata
+	Poll           *Poll            `json:"poll,omitempty"`     // current results, on poll messages
+	Mentions       []MessageMention `json:"mentions,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// Poll is a question posted to a chat as a `poll` message. Voter IDs are
+// shown to everyone in the chat.
+type Poll struct {
+	ID             int64        `json:"id"`
+	ConversationID int64        `json:"conversation_id"`
+	MessageID      int64        `json:"message_id"`
+	CreatedBy      int64        `json:"created_by"`
+	Question       string       `json:"question"`
+	AllowMultiple  bool         `json:"allow_multiple"`
+	Options        []PollOption `json:"options"`
+	TotalVoters    int          `json:"total_voters"`
+	ClosedAt       *time.Time   `json:"closed_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type PollOption struct {
+	ID       int64   `json:"id"`
+	Label    string  `json:"label"`
+	Votes    int     `json:"votes"`
+	VoterIDs []int64 `json:"voter_ids"`
+}
+
+type CreatePollParams struct {
+	Question      string   `json:"question" binding:"required"`
+	Options       []string `json:"options" binding:"required"`
+	AllowMultiple bool     `json:"allow_multiple"`
+}
+
+type PollVoteParams struct {
+	OptionIDs []int64 `json:"option_ids" binding:"required"`
+}
+
+// FilteredWord is an entry on the admin word list. A nil Action means
+// MESSAGE_FILTER_WORD_ACTION.
+type FilteredWord struct {
+	Word      string    `json:"word"`
+	Action    *string   `json:"action,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AddFilteredWordsParams struct {
+	Words  []string `json:"words" binding:"required,min=1,max=100,dive,required,max=100"`
+	Action *string  `json:"action" binding:"omitempty,oneof=reject hide flag"`
+}
+
+// MessageFlag is a message a filter flagged or hid, waiting for review.
+type MessageFlag struct {
+	MessageID      int64     `json:"message_id"`
 	ConversationID int64     `json:"conversation_id"`
 	SenderID       int64     `json:"sender_id"`
 	Body           string    `json:"body"`
-	AttachmentURL  *string   `json:"attachment_url,omitempty"`
-	DeliveryStatus string    `json:"delivery_status"`
+	Filter         string    `json:"filter"`
+	Action         string    `json:"action"`
+	Reason         string    `json:"reason"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// EventSettings are the host's per-event chat settings.
+type EventSettings struct {
+	EventID        int64      `json:"event_id"`
+	WelcomeMessage string     `json:"welcome_message"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+type UpdateEventSettingsParams struct {
+	WelcomeMessage *string `json:"welcome_message" binding:"required,max=500"`
+}
+
+// EventReview is a member's rating of an event they were in, and so of its
+// host.
+type EventReview struct {
+	ID           int64     `json:"id"`
+	EventID      int64     `json:"event_id"`
+	ReviewerID   int64     `json:"reviewer_id"`
+	ReviewerName string    `json:"reviewer_name"`
+	Rating       int       `json:"rating"`
+	Body         string    `json:"body"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CreateEventReviewParams struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Body   string `json:"body" binding:"max=500"`
+}
+
+// VerificationRequest is a user asking admins to verify them.
+type VerificationRequest struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	UserName  string     `json:"user_name"`
+	Note      string     `json:"note"`
+	Status    string     `json:"status"`           // pending, approved or rejected
+	Reason    *string    `json:"reason,omitempty"` // why a request was turned down
+	CreatedAt time.Time  `json:"created_at"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+}
+
+type RequestVerificationParams struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+type DecideVerificationParams struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// EventCheckin is a member who checked in at an event.
+type EventCheckin struct {
+	UserID      int64     `json:"user_id"`
+	Name        string    `json:"name"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
+
+type CheckInParams struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Attendance counts the events a user checked in to, and the finished events
+// that used check-in where they were a member but didn't show up.
+type Attendance struct {
+	Attended int `json:"attended"`
+	NoShows  int `json:"no_shows"`
+}
+
+// HostRating is the average of a host's review ratings.
+type HostRating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// TimePoll asks an event chat which proposed start times suit them. Once
+// the host confirms a slot the event moves to it.
+type TimePoll struct {
+	EventID         int64      `json:"event_id"`
+	CreatedBy       int64      `json:"created_by"`
+	Slots           []TimeSlot `json:"slots"`
+	ConfirmedSlotID *int64     `json:"confirmed_slot_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type TimeSlot struct {
+	ID               int64     `json:"id"`
+	StartsAt         time.Time `json:"starts_at"`
+	Available        int       `json:"available"`
+	AvailableUserIDs []int64   `json:"available_user_ids"`
+}
+
+type CreateTimePollParams struct {
+	Slots []time.Time `json:"slots" binding:"required"`
+}
+
+type TimePollAvailabilityParams struct {
+	SlotIDs []int64 `json:"slot_ids" binding:"required"`
+}
+
+type ConfirmTimeSlotParams struct {
+	SlotID int64 `json:"slot_id" binding:"required"`
+}
+
+// MessageMention is a conversation member referenced as `@name` in a message.
+type MessageMention struct {
+	UserID int64  `json:"userId"`
+	Name   string `json:"name"`
+}
+
 type ConversationSummary struct {
 	Conversation
 	MemberIDs    []int64                   `json:"member_ids"`
@@ -57,6 +354,34 @@ type ConversationSummary struct {
 	Event        *ConversationEventMeta    `json:"event,omitempty"`
 	LastMessage  *MessageSummary           `json:"last_message,omitempty"`
 	UnreadCount  int                       `json:"unread_count"`
+	MutedUntil   *time.Time                `json:"muted_until,omitempty"` // set only while muted
+	Archived     bool                      `json:"archived"`              // by the viewer, or a stale event chat
+}
+
+// ConversationDetail is a single conversation with its full member roster.
+type ConversationDetail struct {
+	ConversationSummary
+	Members []ConversationMemberState `json:"members"`
+}
+
+// ConversationMemberState is a member plus how far they have read, for
+// seen-by indicators. LastReadMessageID is nil until they read anything.
+type ConversationMemberState struct {
+	UserID            int64     `json:"user_id"`
+	Name              string    `json:"name"`
+	Role              string    `json:"role"`
+	JoinedAt          time.Time `json:"joined_at"`
+	LastReadMessageID *int64    `json:"last_read_message_id,omitempty"`
+}
+
+// ConversationListOptions filters ListConversations. By default archived
+// conversations are left out.
+type ConversationListOptions struct {
+	IncludeArchived bool
+	ArchivedOnly    bool
+	Limit           int    // page size; 0 lists everything
+	Cursor          string // next_cursor from the previous page
+	Search          string // keeps conversations whose title, other members or event match
 }
 
 type CreateMessageParams struct {
@@ -65,11 +390,162 @@ type CreateMessageParams struct {
 	Body           string
 	AttachmentURL  *string
 	DeliveryStatus string
+	Kind           string // defaults to "user"
+	MessageType    string // defaults to "text"
+	Metadata       json.RawMessage
+	Notify         bool   // queue a push to the other members
+	Hidden         bool   // shown to the sender only; see message_filters.go
+	IdempotencyKey string // dedups retried sends; see idempotency.go
+}
+
+type CreateInviteParams struct {
+	// ExpiresAt is optional; omitted links never expire.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type MuteConversationParams struct {
+	// Until is optional; omitted mutes indefinitely.
+	Until *time.Time `json:"until"`
+}
+
+type SlowModeParams struct {
+	// Seconds between messages per member; 0 turns slow mode off.
+	Seconds *int `json:"seconds" binding:"required,gte=0,lte=3600"`
+}
+
+// ReminderSettings says which event reminders a user gets pushed.
+type ReminderSettings struct {
+	DayBefore  bool `json:"day_before"`
+	HourBefore bool `json:"hour_before"`
+}
+
+// ScheduledMessage is a message waiting to be posted at SendAt.
+type ScheduledMessage struct {
+	ID             int64     `json:"id"`
+	ConversationID int64     `json:"conversation_id"`
+	SenderID       int64     `json:"sender_id"`
+	Body           string    `json:"body"`
+	SendAt         time.Time `json:"send_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ScheduleMessageParams struct {
+	Body   string    `json:"body" binding:"required"`
+	SendAt time.Time `json:"send_at" binding:"required"`
+}
+
+type ReminderSettingsParams struct {
+	DayBefore  *bool `json:"day_before" binding:"required"`
+	HourBefore *bool `json:"hour_before" binding:"required"`
+}
+
+// NotificationChannels says how a user hears about one category of
+// notifications.
+type NotificationChannels struct {
+	Push  bool `json:"push"`
+	Email bool `json:"email"`
+}
+
+// NotificationSettings says which notifications a user gets, per category.
+type NotificationSettings struct {
+	Messages     NotificationChannels `json:"messages"`
+	JoinRequests NotificationChannels `json:"join_requests"`
+	Reminders    NotificationChannels `json:"reminders"`
+	Marketing    NotificationChannels `json:"marketing"`
+	// Digest is the weekly email of events and unread chats.
+	Digest NotificationChannels `json:"digest"`
+}
+
+type NotificationChannelsParams struct {
+	Push  *bool `json:"push" binding:"required"`
+	Email *bool `json:"email" binding:"required"`
+}
+
+type NotificationSettingsParams struct {
+	Messages     *NotificationChannelsParams `json:"messages" binding:"required"`
+	JoinRequests *NotificationChannelsParams `json:"join_requests" binding:"required"`
+	Reminders    *NotificationChannelsParams `json:"reminders" binding:"required"`
+	Marketing    *NotificationChannelsParams `json:"marketing" binding:"required"`
+	Digest       *NotificationChannelsParams `json:"digest"` // optional; nil keeps the current setting
+}
+
+// Session is a device the user is signed in on.
+type Session struct {
+	ID         int64     `json:"id"`
+	DeviceName string    `json:"device_name"`
+	IP         string    `json:"ip"`
+	Location   *string   `json:"location,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current marks the session making the request.
+	Current bool `json:"current"`
+}
+
+// Bot is an API-key account its owner can add to conversations to post
+// messages, e.g. reminders or weather updates. It isn't a conversation member,
+// so it takes no capacity and gets no unread counts.
+type Bot struct {
+	ID              int64     `json:"id"`
+	UserID          int64     `json:"user_id"` // sender_id of its messages
+	Name            string    `json:"name"`
+	ConversationIDs []int64   `json:"conversation_ids"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type CreateBotParams struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+type AddBotParams struct {
+	BotID int64 `json:"bot_id" binding:"required,gte=1"`
+}
+
+type BotMessageParams struct {
+	ConversationID int64  `json:"conversation_id" binding:"required,gte=1"`
+	Body           string `json:"body" binding:"required,min=1"`
+}
+
+// Webhook is a URL that receives signed POSTs for the subscribed event types.
+// Hosts' webhooks only hear about their own events; admin webhooks (no
+// user_id) hear about everything.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	UserID    *int64    `json:"user_id,omitempty"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one payload sent, or being retried, to a webhook.
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	WebhookID      int64      `json:"webhook_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"` // pending, delivered or failed
+	Attempts       int        `json:"attempts"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+type CreateWebhookParams struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=event.created join_request.created message.created"`
+	// Secret is optional; one is generated when omitted.
+	Secret string `json:"secret" binding:"omitempty,min=16,max=255"`
+}
+
+type RegisterPushTokenParams struct {
+	Token    string `json:"token" binding:"required,max=255"`
+	Platform string `json:"platform" binding:"omitempty,oneof=ios android web"`
 }
 
 type ConversationParticipant struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Verified bool   `json:"verified"`
 }
 
 type ConversationEventMeta struct {
@@ -80,11 +556,17 @@ type ConversationEventMeta struct {
 	DateLabel string `json:"date_label"`
 }
 
+// MessageSummary is the last-message preview shown in the conversation list.
 type MessageSummary struct {
-	ID        int64     `json:"id"`
-	SenderID  int64     `json:"sender_id"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             int64     `json:"id"`
+	SenderID       int64     `json:"sender_id"`
+	SenderName     string    `json:"sender_name"`
+	SenderDeleted  bool      `json:"sender_deleted"` // sender's account was deleted; the name is "Deleted user"
+	Body           string    `json:"body"`
+	AttachmentType string    `json:"attachment_type,omitempty"` // image, video, audio or file
+	Kind           string    `json:"kind"`
+	System         bool      `json:"system"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type ConversationJoinRequest struct {
@@ -95,27 +577,141 @@ type ConversationJoinRequest struct {
 	CreatedAt time.Time  `json:"created_at"`
 	DecidedAt *time.Time `json:"decided_at,omitempty"`
 	DecidedBy *int64     `json:"decided_by,omitempty"`
+	// IneligibleReason is "gender" or "age" when the requester fails the
+	// event's filters on a non-strict event.
+	IneligibleReason *string `json:"ineligible_reason,omitempty"`
+	Note             *string `json:"note,omitempty"` // requester's intro for the host
+}
+
+type JoinRequestParams struct {
+	Note string `json:"note" binding:"max=280"`
+}
+
+// JoinDecision is one entry of a host's batch approve/deny call.
+type JoinDecision struct {
+	UserID int64  `json:"userId" binding:"required,gte=1"`
+	Action string `json:"action" binding:"required,oneof=approve deny"`
+}
+
+type JoinDecisionsParams struct {
+	Decisions []JoinDecision `json:"decisions" binding:"required,min=1,max=50,dive"`
+}
+
+// JoinDecisionResult reports how one batch decision went; Error is set when OK
+// is false.
+type JoinDecisionResult struct {
+	UserID  int64                    `json:"userId"`
+	Action  string                   `json:"action"`
+	OK      bool                     `json:"ok"`
+	Request *ConversationJoinRequest `json:"request,omitempty"`
+	Error   string                   `json:"error,omitempty"`
 }
 
 type CreateEventParams struct {
-	Title       string `json:"title" binding:"required,min=1"`
-	Location    string `json:"location" binding:"required,min=1"`
-	Time        string `json:"time" binding:"required,min=1"`
-	Description string `json:"description"`
-	Gender      string `json:"gender" binding:"required,min=1"`
-	MinAge      int    `json:"min_age" binding:"required,gte=0"`
-	MaxAge      int    `json:"max_age" binding:"required,gte=0"`
-	DateLabel   string `json:"date_label" binding:"required,oneof=Today Tmrw"`
-	UserID      int64  `json:"user_id" binding:"required,gte=1"`
+	Title             string   `json:"title" binding:"required,min=1"`
+	Location          string   `json:"location" binding:"required,min=1"`
+	Time              string   `json:"time" binding:"required,min=1"`
+	Description       string   `json:"description"`
+	Gender            string   `json:"gender" binding:"required,min=1"`
+	MinAge            int      `json:"min_age" binding:"required,gte=0"`
+	MaxAge            int      `json:"max_age" binding:"required,gte=0"`
+	DateLabel         string   `json:"date_label" binding:"required,oneof=Today Tmrw"`
+	Capacity          *int     `json:"capacity" binding:"omitempty,gte=2"`
+	MinAttendees      *int     `json:"min_attendees" binding:"omitempty,gte=2"`
+	Tags              []string `json:"tags" binding:"omitempty,max=5"`
+	StrictEligibility bool     `json:"strict_eligibility"`
+	// Visibility is public (the default), link_only or private.
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public link_only private"`
+	// HideExactLocation needs an Area, the neighbourhood shown instead.
+	HideExactLocation bool   `json:"hide_exact_location"`
+	Area              string `json:"area" binding:"required_if=HideExactLocation true,max=100"`
+	UserID            int64  `json:"user_id" binding:"required,gte=1"`
+
+	// Place is filled by the handler's geocoder, never by clients.
+	Place *EventPlace `json:"-"`
+	// IdempotencyKey comes from the Idempotency-Key header and is recorded
+	// for IdempotencyUserID, the signed-in caller, never the body's UserID.
+	IdempotencyKey    string `json:"-"`
+	IdempotencyUserID int64  `json:"-"`
+}
+
+type TransferEventParams struct {
+	UserID int64 `json:"user_id" binding:"required,gte=1"`
+}
+
+// EventRevision is one edit to an event: who made it, when, and what it
+// changed.
+type EventRevision struct {
+	ID         int64              `json:"id"`
+	EventID    int64              `json:"event_id"`
+	EditorID   int64              `json:"editor_id"`
+	EditorName string             `json:"editor_name"`
+	Changes    []EventFieldChange `json:"changes"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// EventTemplate is a host's saved event, to create new ones from.
+type EventTemplate struct {
+	ID        int64               `json:"id"`
+	UserID    int64               `json:"user_id"`
+	Name      string              `json:"name"`
+	Event     EventTemplateFields `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// EventTemplateFields are the parts of an event a duplicate or a template
+// carries over. Everything else, like the chat, starts fresh.
+type EventTemplateFields struct {
+	Title             string      `json:"title"`
+	Location          string      `json:"location"`
+	Time              string      `json:"time"`
+	Description       string      `json:"description"`
+	Gender            string      `json:"gender"`
+	MinAge            int         `json:"min_age"`
+	MaxAge            int         `json:"max_age"`
+	DateLabel         string      `json:"date_label"`
+	Capacity          *int        `json:"capacity,omitempty"`
+	MinAttendees      *int        `json:"min_attendees,omitempty"`
+	Tags              []string    `json:"tags,omitempty"`
+	StrictEligibility bool        `json:"strict_eligibility"`
+	Visibility        string      `json:"visibility"`
+	HideExactLocation bool        `json:"hide_exact_location"`
+	Area              string      `json:"area,omitempty"`
+	Place             *EventPlace `json:"-"`
+}
+
+type CreateEventTemplateParams struct {
+	EventID int64  `json:"event_id" binding:"required,gte=1"`
+	Name    string `json:"name" binding:"required,min=1,max=80"`
+}
+
+// DuplicateEventParams can rename the new event and pick its day and time;
+// omitted fields are copied.
+type DuplicateEventParams struct {
+	Title     string `json:"title"`
+	Time      string `json:"time"`
+	DateLabel string `json:"date_label" binding:"omitempty,oneof=Today Tmrw"`
 }
 
 type UpdateEventParams struct {
-	Title       string `json:"title" binding:"required,min=1"`
-	Location    string `json:"location" binding:"required,min=1"`
-	Time        string `json:"time" binding:"required,min=1"`
-	Description string `json:"description"`
-	Gender      string `json:"gender" binding:"required,min=1"`
-	MinAge      int    `json:"min_age" binding:"required,gte=0"`
-	MaxAge      int    `json:"max_age" binding:"required,gte=0"`
-	DateLabel   string `json:"date_label" binding:"required,oneof=Today Tmrw"`
+	Title             string   `json:"title" binding:"required,min=1"`
+	Location          string   `json:"location" binding:"required,min=1"`
+	Time              string   `json:"time" binding:"required,min=1"`
+	Description       string   `json:"description"`
+	Gender            string   `json:"gender" binding:"required,min=1"`
+	MinAge            int      `json:"min_age" binding:"required,gte=0"`
+	MaxAge            int      `json:"max_age" binding:"required,gte=0"`
+	DateLabel         string   `json:"date_label" binding:"required,oneof=Today Tmrw"`
+	Capacity          *int     `json:"capacity" binding:"omitempty,gte=2"`
+	MinAttendees      *int     `json:"min_attendees" binding:"omitempty,gte=2"`
+	Tags              []string `json:"tags" binding:"omitempty,max=5"`
+	StrictEligibility bool     `json:"strict_eligibility"`
+	// Visibility keeps the current level when omitted.
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public link_only private"`
+	// HideExactLocation and Area keep their current values when omitted.
+	HideExactLocation *bool   `json:"hide_exact_location"`
+	Area              *string `json:"area" binding:"omitempty,max=100"`
+
+	// Place is filled by the handler's geocoder, never by clients.
+	Place *EventPlace `json:"-"`
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// selectNewMemberActivity returns the sender's role and join time in the
+// conversation, and how many messages they have sent there since joining.
+const selectNewMemberActivity = `
+SELECT cm.role, cm.joined_at, (
+    SELECT COUNT(1)
+    FROM messages m
+    WHERE m.conversation_id = cm.conversation_id
+      AND m.sender_id = cm.user_id
+      AND m.kind = 'user'
+      AND m.created_at >= cm.joined_at
+)
+FROM conversation_members cm
+WHERE cm.conversation_id = ? AND cm.user_id = ?;
+`
+
+// MemberActivity is what newMemberFilter needs to know about a sender.
+type MemberActivity struct {
+	Role     string
+	JoinedAt time.Time
+	Sent     int
+}
+
+// GetMemberActivity returns the member's role, join time and message count
+// since joining. It returns ErrNotConversationMember for non-members.
+func (r *EventRepository) GetMemberActivity(ctx context.Context, conversationID, userID int64) (*MemberActivity, error) {
+	var activity MemberActivity
+	err := r.db.QueryRowContext(ctx, selectNewMemberActivity, conversationID, userID).Scan(&activity.Role, &activity.JoinedAt, &activity.Sent)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotConversationMember
+		}
+		return nil, fmt.Errorf("fetch member activity: %w", err)
+	}
+	return &activity, nil
+}
+
+// newMemberFilter holds back members who just joined a chat, the usual
+// shape of a spam run: until they have been in for the window they may send
+// at most limit messages, and no links until they have said something
+// without one. Hosts, co-hosts and owners are exempt.
+type newMemberFilter struct {
+	repo   Store
+	window time.Duration
+	limit  int
+}
+
+func (f newMemberFilter) Check(ctx context.Context, input MessageFilterInput) (*filterVerdict, error) {
+	activity, err := f.repo.GetMemberActivity(ctx, input.ConversationID, input.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	if activity.Role == roleOwner || activity.Role == roleCoHost {
+		return nil, nil
+	}
+
+	remaining := activity.JoinedAt.Add(f.window).Sub(input.Now)
+	isNew := remaining > 0
+	if (isNew || activity.Sent == 0) && linkPattern.MatchString(input.Body) {
+		verdict := &filterVerdict{Filter: "new_member", Action: filterActionReject, Code: "new_member_links", Reason: "links from a member who just joined or hasn't posted yet"}
+		if isNew {
+			verdict.RetryAfter = remaining
+		}
+		return verdict, nil
+	}
+	if isNew && f.limit > 0 && activity.Sent >= f.limit {
+		return &filterVerdict{Filter: "new_member", Action: filterActionReject, Code: "new_member_limit", Reason: fmt.Sprintf("more than %d messages within %s of joining", f.limit, f.window), RetryAfter: remaining}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Users choose, per category, whether they hear about things by push and by
+// email. Every sender looks the recipient's choice up before delivering, so
+// turning a category off stops it on every device at once. Reminder pushes
+// also still honour the finer-grained /users/me/reminders settings.
+
+// notificationCategory names a group of notifications users can turn off. It
+// doubles as the prefix of its user_notification_settings columns.
+type notificationCategory string
+
+const (
+	notifyMessages     notificationCategory = "messages"
+	notifyJoinRequests notificationCategory = "join_requests"
+	notifyReminders    notificationCategory = "reminders"
+	notifyMarketing    notificationCategory = "marketing"
+	notifyDigest       notificationCategory = "digest" // email only; nothing pushes digests
+)
+
+const (
+	notificationPush  = "push"
+	notificationEmail = "email"
+)
+
+// defaultNotificationSettings is what users who never saved any get: chat
+// messages push only, marketing nowhere until they opt in. The table's
+// column defaults match.
+var defaultNotificationSettings = NotificationSettings{
+	Messages:     NotificationChannels{Push: true},
+	JoinRequests: NotificationChannels{Push: true, Email: true},
+	Reminders:    NotificationChannels{Push: true, Email: true},
+	Digest:       NotificationChannels{Email: true},
+}
+
+const createTableUserNotificationSettings = `
+CREATE TABLE IF NOT EXISTS user_notification_settings (
+    user_id INTEGER PRIMARY KEY,
+    messages_push INTEGER NOT NULL DEFAULT 1,
+    messages_email INTEGER NOT NULL DEFAULT 0,
+    join_requests_push INTEGER NOT NULL DEFAULT 1,
+    join_requests_email INTEGER NOT NULL DEFAULT 1,
+    reminders_push INTEGER NOT NULL DEFAULT 1,
+    reminders_email INTEGER NOT NULL DEFAULT 1,
+    marketing_push INTEGER NOT NULL DEFAULT 0,
+    marketing_email INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const selectUserNotificationSettings = `
+SELECT messages_push, messages_email, join_requests_push, join_requests_email,
+       reminders_push, reminders_email, marketing_push, marketing_email,
+       digest_push, digest_email
+FROM user_notification_settings
+WHERE user_id = ?;
+`
+
+const upsertUserNotificationSettings = `
+INSERT INTO user_notification_settings (
+    user_id, messages_push, messages_email, join_requests_push, join_requests_email,
+    reminders_push, reminders_email, marketing_push, marketing_email,
+    digest_push, digest_email, updated_at
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(user_id) DO UPDATE SET
+    messages_push = excluded.messages_push,
+    messages_email = excluded.messages_email,
+    join_requests_push = excluded.join_requests_push,
+    join_requests_email = excluded.join_requests_email,
+    reminders_push = excluded.reminders_push,
+    reminders_email = excluded.reminders_email,
+    marketing_push = excluded.marketing_push,
+    marketing_email = excluded.marketing_email,
+    digest_push = excluded.digest_push,
+    digest_email = excluded.digest_email,
+    updated_at = CURRENT_TIMESTAMP;
+`
+
+const deleteUserNotificationSettings = `
+DELETE FROM user_notification_settings
+WHERE user_id = ?;
+`
+
+func (r *EventRepository) initNotificationSettings(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableUserNotificationSettings); err != nil {
+		return fmt.Errorf("create notification settings table: %w", err)
+	}
+	if err := r.ensureColumn(ctx, "user_notification_settings", "digest_push", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	return r.ensureColumn(ctx, "user_notification_settings", "digest_email", "INTEGER NOT NULL DEFAULT 1")
+}
+
+// channels returns the settings for one category.
+func (s NotificationSettings) channels(category notificationCategory) NotificationChannels {
+	switch category {
+	case notifyMessages:
+		return s.Messages
+	case notifyJoinRequests:
+		return s.JoinRequests
+	case notifyReminders:
+		return s.Reminders
+	case notifyDigest:
+		return s.Digest
+	default:
+		return s.Marketing
+	}
+}
+
+// notificationEnabledSQL is a condition on the user_notification_settings row
+// aliased ns that holds when the user gets category over channel, falling
+// back to the default when they never saved settings.
+func notificationEnabledSQL(category notificationCategory, channel string) string {
+	channels := defaultNotificationSettings.channels(category)
+	enabled := channels.Push
+	if channel == notificationEmail {
+		enabled = channels.Email
+	}
+	fallback := 0
+	if enabled {
+		fallback = 1
+	}
+	return fmt.Sprintf("COALESCE(ns.%s_%s, %d) = 1", category, channel, fallback)
+}
+
+func (p NotificationChannelsParams) channels() NotificationChannels {
+	return NotificationChannels{Push: *p.Push, Email: *p.Email}
+}
+
+// settings applies p over current, which only supplies the digest setting
+// when p leaves it out.
+func (p NotificationSettingsParams) settings(current NotificationSettings) NotificationSettings {
+	settings := NotificationSettings{
+		Messages:     p.Messages.channels(),
+		JoinRequests: p.JoinRequests.channels(),
+		Reminders:    p.Reminders.channels(),
+		Marketing:    p.Marketing.channels(),
+		Digest:       current.Digest,
+	}
+	if p.Digest != nil {
+		settings.Digest = p.Digest.channels()
+	}
+	return settings
+}
+
+// GetNotificationSettings returns the user's notification preferences, or the
+// defaults if they never saved any.
+func (r *EventRepository) GetNotificationSettings(ctx context.Context, userID int64) (*NotificationSettings, error) {
+	settings := defaultNotificationSettings
+	err := r.db.QueryRowContext(ctx, selectUserNotificationSettings, userID).Scan(
+		&settings.Messages.Push, &settings.Messages.Email,
+		&settings.JoinRequests.Push, &settings.JoinRequests.Email,
+		&settings.Reminders.Push, &settings.Reminders.Email,
+		&settings.Marketing.Push, &settings.Marketing.Email,
+		&settings.Digest.Push, &settings.Digest.Email,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("fetch notification settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SetNotificationSettings replaces the user's notification preferences.
+func (r *EventRepository) SetNotificationSettings(ctx context.Context, userID int64, settings NotificationSettings) error {
+	if _, err := r.db.ExecContext(ctx, upsertUserNotificationSettings, userID,
+		settings.Messages.Push, settings.Messages.Email,
+		settings.JoinRequests.Push, settings.JoinRequests.Email,
+		settings.Reminders.Push, settings.Reminders.Email,
+		settings.Marketing.Push, settings.Marketing.Email,
+		settings.Digest.Push, settings.Digest.Email,
+	); err != nil {
+		return fmt.Errorf("save notification settings: %w", err)
+	}
+	return nil
+}
+
+// getNotificationSettings returns which notifications the caller gets, per
+// category and channel.
+//
+// Responses:
+//  - 200 with `settings`
+//  - 401 if the caller has no session
+//  - 500 for repository/database failures
+func (h *UserHandler) getNotificationSettings(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	settings, err := h.repo.GetNotificationSettings(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load notification settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// setNotificationSettings replaces the caller's notification preferences.
+// Every category must say both whether to push and whether to email, except
+// `digest`, which is kept as it is when left out.
+//
+// Responses:
+//  - 200 with the saved `settings`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or a missing category or channel
+//  - 500 for repository/database failures
+func (h *UserHandler) setNotificationSettings(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload NotificationSettingsParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	current, err := h.repo.GetNotificationSettings(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load notification settings"})
+		return
+	}
+	settings := payload.settings(*current)
+	if err := h.repo.SetNotificationSettings(ctx, claims.UserID, settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save notification settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/mail"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Google and Apple sign-in: the app gets an identity token (an RS256 JWT)
+// from the platform SDK and posts it here. The token is checked against the
+// provider's published keys and the app's client IDs, then signs in the
+// account linked to that provider subject. The first time, an account with
+// the same verified email is linked, or a new one is created. An email the
+// provider hasn't verified is never linked or given an account.
+
+const (
+	// identityKeysTimeout bounds fetching a provider's signing keys.
+	identityKeysTimeout = 5 * time.Second
+	// identityKeysTTL is how long fetched keys are trusted before refetching.
+	identityKeysTTL = time.Hour
+	// identityKeysMinRefresh stops tokens with unknown key IDs from making us
+	// refetch on every request.
+	identityKeysMinRefresh = time.Minute
+	// identityClockSkew tolerates clocks a little behind the provider's.
+	identityClockSkew = time.Minute
+)
+
+var (
+	ErrInvalidIdentityToken    = errors.New("identity token is invalid")
+	ErrIdentityEmailUnverified = errors.New("identity email is not verified")
+)
+
+// identityProviderNames are the providers sign-in can be configured for.
+var identityProviderNames = []string{"google", "apple"}
+
+// identityProvider checks identity tokens issued by one provider.
+type identityProvider struct {
+	name      string
+	issuers   []string
+	audiences []string
+	jwksURL   string
+	client    *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// newIdentityProvidersFromEnv enables each provider whose client IDs are set:
+// GOOGLE_CLIENT_IDS and APPLE_CLIENT_IDS, comma-separated, one per app build
+// that signs in (Apple's are bundle or service IDs). GOOGLE_JWKS_URL and
+// APPLE_JWKS_URL override where signing keys are fetched from.
+func newIdentityProvidersFromEnv() map[string]*identityProvider {
+	client := &http.Client{Timeout: identityKeysTimeout}
+	providers := make(map[string]*identityProvider)
+	for _, provider := range []*identityProvider{
+		{
+			name:    "google",
+			issuers: []string{"https://accounts.google.com", "accounts.google.com"},
+			jwksURL: "https://www.googleapis.com/oauth2/v3/certs",
+		},
+		{
+			name:    "apple",
+			issuers: []string{"https://appleid.apple.com"},
+			jwksURL: "https://appleid.apple.com/auth/keys",
+		},
+	} {
+		prefix := strings.ToUpper(provider.name)
+		provider.audiences = envList(prefix + "_CLIENT_IDS")
+		if len(provider.audiences) == 0 {
+			continue
+		}
+		if raw := strings.TrimSpace(os.Getenv(prefix + "_JWKS_URL")); raw != "" {
+			provider.jwksURL = raw
+		}
+		provider.client = client
+		providers[provider.name] = provider
+	}
+	return providers
+}
+
+// identityClaims are the identity token claims sign-in uses.
+type identityClaims struct {
+	Issuer        string       `json:"iss"`
+	Audience      string       `json:"aud"`
+	Subject       string       `json:"sub"`
+	ExpiresAt     int64        `json:"exp"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+}
+
+// flexibleBool accepts both true and "true", since Apple sends booleans as
+// strings.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+// verify checks token's signature, issuer, audience and expiry, returning its
+// claims. It returns ErrInvalidIdentityToken for any token we shouldn't
+// trust, and another error only when the provider's keys can't be fetched.
+func (p *identityProvider) verify(ctx context.Context, token string, now time.Time) (*identityClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidIdentityToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeTokenSegment(parts[0], &header); err != nil || header.Alg != "RS256" {
+		return nil, ErrInvalidIdentityToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidIdentityToken
+	}
+
+	key, err := p.key(ctx, header.Kid, now)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, ErrInvalidIdentityToken
+	}
+
+	var claims identityClaims
+	if err := decodeTokenSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidIdentityToken
+	}
+	if !slices.Contains(p.issuers, claims.Issuer) || !slices.Contains(p.audiences, claims.Audience) {
+		return nil, ErrInvalidIdentityToken
+	}
+	if claims.Subject == "" || now.Add(-identityClockSkew).Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidIdentityToken
+	}
+	return &claims, nil
+}
+
+// decodeTokenSegment decodes one base64url JSON part of a JWT.
+func decodeTokenSegment(segment string, dst any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+// key returns the provider's signing key with this ID, refetching the key set
+// when it's stale or doesn't have it, since providers rotate keys.
+func (p *identityProvider) key(ctx context.Context, kid string, now time.Time) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fresh := now.Sub(p.fetchedAt) < identityKeysTTL
+	if key, ok := p.keys[kid]; ok && fresh {
+		return key, nil
+	}
+	if fresh && now.Sub(p.fetchedAt) < identityKeysMinRefresh {
+		return nil, ErrInvalidIdentityToken
+	}
+
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys, p.fetchedAt = keys, now
+	key, ok := keys[kid]
+	if !ok {
+		return nil, ErrInvalidIdentityToken
+	}
+	return key, nil
+}
+
+// fetchKeys downloads the provider's JSON Web Key Set.
+func (p *identityProvider) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build %s keys request: %w", p.name, err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s keys: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s keys: status %d", p.name, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode %s keys: %w", p.name, err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+const createTableUserIdentities = `
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id INTEGER NOT NULL,
+    email TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, subject),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexUserIdentitiesUser = `
+CREATE INDEX IF NOT EXISTS idx_user_identities_user
+ON user_identities(user_id);
+`
+
+const selectIdentityUser = `
+SELECT u.id, u.name, u.email, u.created_at
+FROM user_identities i
+JOIN users u ON u.id = i.user_id
+WHERE i.provider = ? AND i.subject = ? AND u.deleted_at IS NULL;
+`
+
+const selectUserIDByEmail = `
+SELECT id FROM users
+WHERE email = ? COLLATE NOCASE AND deleted_at IS NULL;
+`
+
+const insertUserIdentity = `
+INSERT INTO user_identities (provider, subject, user_id, email)
+VALUES (?, ?, ?, ?);
+`
+
+const deleteUserIdentitiesForUser = `
+DELETE FROM user_identities
+WHERE user_id = ?;
+`
+
+func (r *EventRepository) initUserIdentities(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableUserIdentities); err != nil {
+		return fmt.Errorf("create user identities table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexUserIdentitiesUser); err != nil {
+		return fmt.Errorf("create user identities index: %w", err)
+	}
+	return nil
+}
+
+// externalIdentity is who a provider says signed in.
+type externalIdentity struct {
+	provider      string
+	subject       string
+	email         string
+	emailVerified bool
+	name          string
+}
+
+// SignInWithIdentity returns the account linked to the identity, linking the
+// account with the same email if the provider verified it, or creating one.
+// It returns ErrIdentityEmailUnverified for an unlinked identity whose email
+// the provider didn't verify: linking would hand over the account that owns
+// the email, and creating one would claim an address the caller may not own.
+func (r *EventRepository) SignInWithIdentity(ctx context.Context, identity externalIdentity) (*User, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin identity sign-in tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	user, err := scanIdentityUser(tx.QueryRowContext(ctx, selectIdentityUser, identity.provider, identity.subject))
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("lookup identity: %w", err)
+	}
+
+	var userID int64
+	email := identity.email
+	if email != "" {
+		if !identity.emailVerified {
+			return nil, ErrIdentityEmailUnverified
+		}
+		err = tx.QueryRowContext(ctx, selectUserIDByEmail, email).Scan(&userID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("lookup user by email: %w", err)
+		}
+	} else {
+		// The subject is unique per provider, so it doubles as a placeholder
+		// address.
+		email = identity.provider + "-" + hashSecretToken(identity.subject)[:16] + "@users.invalid"
+	}
+
+	if userID == 0 {
+		res, err := tx.ExecContext(ctx, insertUser, identity.name, email, "")
+		if err != nil {
+			return nil, fmt.Errorf("insert user: %w", err)
+		}
+		if userID, err = res.LastInsertId(); err != nil {
+			return nil, fmt.Errorf("fetch user id: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, insertUserIdentity, identity.provider, identity.subject, userID, nullableString(identity.email)); err != nil {
+		return nil, fmt.Errorf("link identity: %w", err)
+	}
+
+	user, err = scanIdentityUser(tx.QueryRowContext(ctx, selectIdentityUser, identity.provider, identity.subject))
+	if err != nil {
+		return nil, fmt.Errorf("fetch linked user: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit identity sign-in: %w", err)
+	}
+	return user, nil
+}
+
+func scanIdentityUser(row *sql.Row) (*User, error) {
+	var user User
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+type identitySignInRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+	// Name is used if the sign-in creates an account. Apple only gives it to
+	// the app, not in the token, and only the first time.
+	Name string `json:"name" binding:"max=100"`
+}
+
+// signInWithProvider signs in with a Google or Apple identity token, issuing
+// the same session as login.
+//
+// Responses:
+//  - 200 with the user and session token
+//  - 400 for invalid JSON
+//  - 401 if the identity token doesn't check out
+//  - 404 for an unknown provider
+//  - 403 if the identity isn't linked yet and the provider didn't verify its
+//    email
+//  - 500 for repository/database failures or if the provider's keys can't be
+//    fetched
+//  - 503 if the provider is not configured
+func (h *AuthHandler) signInWithProvider(c *gin.Context) {
+	name := c.Param("provider")
+	provider, ok := h.identity[name]
+	if !ok {
+		if slices.Contains(identityProviderNames, name) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "sign-in with " + name + " is not available"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown sign-in provider"})
+		return
+	}
+
+	var payload identitySignInRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	claims, err := provider.verify(ctx, payload.IDToken, time.Now())
+	if err != nil {
+		if errors.Is(err, ErrInvalidIdentityToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid identity token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		email = ""
+	}
+	user, err := h.repo.SignInWithIdentity(ctx, externalIdentity{
+		provider:      provider.name,
+		subject:       claims.Subject,
+		email:         email,
+		emailVerified: bool(claims.EmailVerified),
+		name:          identityDisplayName(payload.Name, claims.Name, email),
+	})
+	if err != nil {
+		if errors.Is(err, ErrIdentityEmailUnverified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Verify your email with " + provider.name + " before signing in"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	h.respondWithSession(c, ctx, user)
+}
+
+// identityDisplayName names a new account: what the app sent, else what the
+// provider says, else the email's local part.
+func identityDisplayName(requested, claimed, email string) string {
+	for _, name := range []string{requested, claimed} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	if local, _, ok := strings.Cut(email, "@"); ok && local != "" {
+		return local
+	}
+	return "New user"
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testIdentityIssuer   = "https://accounts.google.com"
+	testIdentityAudience = "app.example.ios"
+	testIdentityKid      = "key-1"
+)
+
+// newTestIdentityProvider serves key's public half as the provider's only
+// signing key.
+func newTestIdentityProvider(t *testing.T, key *rsa.PrivateKey) *identityProvider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testIdentityKid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(server.Close)
+	return &identityProvider{
+		name:      "google",
+		issuers:   []string{testIdentityIssuer},
+		audiences: []string{testIdentityAudience},
+		jwksURL:   server.URL,
+		client:    server.Client(),
+	}
+}
+
+// signIdentityToken signs an RS256 identity token the way a provider would.
+func signIdentityToken(t *testing.T, key *rsa.PrivateKey, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	signingInput := encodeSegment(t, map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign identity token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestIdentityProviderVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate other key: %v", err)
+	}
+	now := time.Now()
+	claims := func(edit func(map[string]any)) map[string]any {
+		c := map[string]any{
+			"iss":            testIdentityIssuer,
+			"aud":            testIdentityAudience,
+			"sub":            "1234567890",
+			"exp":            now.Add(time.Hour).Unix(),
+			"email":          "ana@example.com",
+			"email_verified": true,
+		}
+		if edit != nil {
+			edit(c)
+		}
+		return c
+	}
+	valid := signIdentityToken(t, key, "RS256", testIdentityKid, claims(nil))
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", valid, true},
+		{"within clock skew", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { c["exp"] = now.Add(-identityClockSkew / 2).Unix() })), true},
+		{"apple string boolean", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { c["email_verified"] = "true" })), true},
+		{"wrong issuer", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { c["iss"] = "https://evil.example.com" })), false},
+		{"wrong audience", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { c["aud"] = "someone-elses.app" })), false},
+		{"expired", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { c["exp"] = now.Add(-2 * identityClockSkew).Unix() })), false},
+		{"no expiry", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { delete(c, "exp") })), false},
+		{"no subject", signIdentityToken(t, key, "RS256", testIdentityKid, claims(func(c map[string]any) { c["sub"] = "" })), false},
+		{"signed by another key", signIdentityToken(t, otherKey, "RS256", testIdentityKid, claims(nil)), false},
+		{"unknown kid", signIdentityToken(t, key, "RS256", "key-2", claims(nil)), false},
+		{"alg none", encodeSegment(t, map[string]string{"alg": "none", "kid": testIdentityKid}) + "." + parts[1] + ".", false},
+		{"alg HS256", encodeSegment(t, map[string]string{"alg": "HS256", "kid": testIdentityKid}) + "." + parts[1] + "." + parts[2], false},
+		{"tampered claims", parts[0] + "." + encodeSegment(t, claims(func(c map[string]any) { c["sub"] = "someone-else" })) + "." + parts[2], false},
+		{"two parts", parts[0] + "." + parts[1], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestIdentityProvider(t, key)
+			got, err := provider.verify(context.Background(), tt.token, now)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidIdentityToken) {
+					t.Fatalf("verify error = %v, want %v", err, ErrInvalidIdentityToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if got.Subject != "1234567890" || !bool(got.EmailVerified) {
+				t.Fatalf("claims = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSignInWithIdentity(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		email    string
+		verified bool
+		wantErr  error
+		wantUser string // the existing account it signs in, or "" for a new one
+	}{
+		{"verified email links the account", "ava@example.com", true, nil, "ava"},
+		{"verified new email creates an account", "new@example.com", true, nil, ""},
+		{"no email creates an account", "", false, nil, ""},
+		{"unverified email of an account", "ava@example.com", false, ErrIdentityEmailUnverified, ""},
+		{"unverified new email", "new@example.com", false, ErrIdentityEmailUnverified, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			avaID := mustCreateUser(t, repo, "ava")
+			identity := externalIdentity{provider: "google", subject: "1234567890", email: tt.email, emailVerified: tt.verified, name: "Google user"}
+
+			user, err := repo.SignInWithIdentity(ctx, identity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("sign in error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got := countRows(t, repo, `SELECT COUNT(*) FROM user_identities`); got != 0 {
+					t.Fatalf("identities linked = %d, want 0", got)
+				}
+				if got := countRows(t, repo, `SELECT COUNT(*) FROM users`); got != 1 {
+					t.Fatalf("users = %d, want only the existing account", got)
+				}
+				return
+			}
+			if tt.wantUser == "ava" && user.ID != avaID {
+				t.Fatalf("signed in user %d, want the existing account %d", user.ID, avaID)
+			}
+			if tt.wantUser == "" && user.ID == avaID {
+				t.Fatal("signed in the existing account, want a new one")
+			}
+
+			// Later sign-ins find the identity by subject.
+			again, err := repo.SignInWithIdentity(ctx, identity)
+			if err != nil || again.ID != user.ID {
+				t.Fatalf("second sign in = %+v, %v; want user %d", again, err, user.ID)
+			}
+		})
+	}
+}
+
+func TestSignInWithIdentityKeepsLinkedUnverified(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	identity := externalIdentity{provider: "apple", subject: "001234.abcd", email: "ava@example.com", emailVerified: true, name: "Ava"}
+	user, err := repo.SignInWithIdentity(ctx, identity)
+	if err != nil {
+		t.Fatalf("first sign in: %v", err)
+	}
+
+	// Once linked, the email's status no longer matters: the subject decides.
+	identity.emailVerified = false
+	again, err := repo.SignInWithIdentity(ctx, identity)
+	if err != nil || again.ID != user.ID {
+		t.Fatalf("linked sign in = %+v, %v; want user %d", again, err, user.ID)
+	}
+}
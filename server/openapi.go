@@ -0,0 +1,462 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPIAuth says whether an operation needs a bearer session.
+type openAPIAuth int
+
+const (
+	authRequired openAPIAuth = iota
+	authOptional             // anonymous callers get a reduced response
+	authNone
+)
+
+// openAPIObject describes an untyped gin.H body: each value's Go type becomes
+// the property's schema.
+type openAPIObject map[string]any
+
+// openAPIOperation adds what the route table can't: the JSON bodies. Request
+// and Response hold zero values of the types the handler binds and returns.
+type openAPIOperation struct {
+	Request  any
+	Response any
+	Status   int // success status; 200 when zero
+	Auth     openAPIAuth
+}
+
+// openAPIOperations documents the bodies of the REST routes, keyed by
+// "METHOD path" as registered with gin. Routes missing here are still listed,
+// without body schemas.
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /health":       {Response: openAPIObject{"status": ""}, Auth: authNone},
+	"GET /health/live":  {Response: openAPIObject{"status": ""}, Auth: authNone},
+	"GET /health/ready": {Response: openAPIObject{"status": "", "checks": map[string]string{}}, Auth: authNone},
+
+	"POST /api/login": {
+		Request:  loginRequest{},
+		Response: openAPIObject{"user": openAPIObject{"id": int64(0), "name": "", "email": ""}, "token": "", "roles": []string{}, "expires_at": time.Time{}},
+		Auth:     authNone,
+	},
+	"POST /api/login/magic":            {Request: magicLinkRequest{}, Response: openAPIObject{"message": ""}, Status: http.StatusAccepted, Auth: authNone},
+	"POST /api/login/magic/verify":     {Request: verifyMagicLinkRequest{}, Response: openAPIObject{"user": User{}, "token": "", "roles": []string{}, "expires_at": time.Time{}}, Auth: authNone},
+	"POST /api/password-reset":         {Request: passwordResetRequest{}, Response: openAPIObject{"message": ""}, Status: http.StatusAccepted, Auth: authNone},
+	"POST /api/password-reset/confirm": {Request: confirmPasswordResetRequest{}, Response: openAPIObject{"message": ""}, Auth: authNone},
+	"POST /api/otp/request":            {Request: otpRequest{}, Response: openAPIObject{"message": ""}, Status: http.StatusAccepted, Auth: authNone},
+	"POST /api/oauth/:provider":        {Request: identitySignInRequest{}, Response: openAPIObject{"user": User{}, "token": "", "roles": []string{}, "expires_at": time.Time{}}, Auth: authNone},
+	"POST /api/otp/verify":             {Request: verifyOTPRequest{}, Response: openAPIObject{"user": User{}, "token": "", "roles": []string{}, "expires_at": time.Time{}}, Auth: authNone},
+
+	"GET /api/events":                      {Response: openAPIObject{"data": []Event{}, "removed": []int64{}}, Auth: authOptional},
+	"POST /api/events":                     {Request: CreateEventParams{}, Response: openAPIObject{"id": int64(0)}, Status: http.StatusCreated, Auth: authOptional},
+	"POST /api/graphql":                    {Request: graphqlRequest{}, Response: openAPIObject{"data": map[string]any{}, "errors": []map[string]any{}}, Auth: authOptional},
+	"GET /api/tags":                        {Response: openAPIObject{"data": []Tag{}}, Auth: authOptional},
+	"PUT /api/events/:id":                  {Request: UpdateEventParams{}, Response: openAPIObject{"message": ""}},
+	"DELETE /api/events/:id":               {Response: openAPIObject{"message": ""}},
+	"POST /api/events/:id/transfer":        {Request: TransferEventParams{}, Response: openAPIObject{"message": "", "user_id": int64(0)}},
+	"GET /api/events/:id/history":          {Response: openAPIObject{"revisions": []EventRevision{}}},
+	"POST /api/events/:id/duplicate":       {Request: DuplicateEventParams{}, Response: openAPIObject{"id": int64(0)}, Status: http.StatusCreated},
+	"GET /api/event-templates":             {Response: openAPIObject{"data": []EventTemplate{}}},
+	"POST /api/event-templates":            {Request: CreateEventTemplateParams{}, Response: openAPIObject{"template": EventTemplate{}}, Status: http.StatusCreated},
+	"POST /api/event-templates/:id/events": {Request: DuplicateEventParams{}, Response: openAPIObject{"id": int64(0)}, Status: http.StatusCreated},
+	"DELETE /api/event-templates/:id":      {Status: http.StatusNoContent},
+	"GET /api/events/:id/settings":         {Response: openAPIObject{"settings": EventSettings{}}},
+	"PUT /api/events/:id/settings":         {Request: UpdateEventSettingsParams{}, Response: openAPIObject{"settings": EventSettings{}}},
+	"GET /api/events/:id/reviews":          {Response: openAPIObject{"reviews": []EventReview{}}, Auth: authOptional},
+	"POST /api/events/:id/reviews":         {Request: CreateEventReviewParams{}, Response: openAPIObject{"review": EventReview{}}, Status: http.StatusCreated},
+	"GET /api/events/:id/checkin-code":     {Response: openAPIObject{"token": "", "eventId": int64(0), "opensAt": time.Time{}, "closesAt": time.Time{}}},
+	"POST /api/events/:id/checkin":         {Request: CheckInParams{}, Response: openAPIObject{"checkedInAt": time.Time{}}},
+	"GET /api/events/:id/checkins":         {Response: openAPIObject{"checkins": []EventCheckin{}}},
+	"GET /api/events/bookmarked":           {Response: openAPIObject{"data": []Event{}}},
+	"GET /api/events/recommended":          {Response: openAPIObject{"data": []RecommendedEvent{}}},
+	"POST /api/events/:id/bookmark":        {Response: openAPIObject{"message": ""}},
+	"DELETE /api/events/:id/bookmark":      {Response: openAPIObject{"message": ""}},
+
+	"GET /api/users/me":                       {Response: openAPIObject{"user": UserProfile{}}},
+	"GET /api/users/:id/events":               {Response: openAPIObject{"data": []Event{}, "next_cursor": ""}, Auth: authOptional},
+	"PUT /api/users/me/profile":               {Request: UpdateProfileParams{}, Response: openAPIObject{"user": UserProfile{}}},
+	"PUT /api/users/me/interests":             {Request: UpdateInterestsParams{}, Response: openAPIObject{"user": UserProfile{}}},
+	"GET /api/users/me/verification":          {Response: openAPIObject{"verified": false, "request": VerificationRequest{}}},
+	"POST /api/users/me/verification":         {Request: RequestVerificationParams{}, Response: openAPIObject{"request": VerificationRequest{}}, Status: http.StatusCreated},
+	"GET /api/sessions":                       {Response: openAPIObject{"sessions": []Session{}}},
+	"DELETE /api/sessions/:id":                {Status: http.StatusNoContent},
+	"POST /api/users/me/phone":                {Request: verifyOTPRequest{}, Response: openAPIObject{"phone": ""}},
+	"GET /api/availability/me":                {Response: openAPIObject{"availability": Availability{}}},
+	"PUT /api/availability/me":                {Request: SetAvailabilityParams{}, Response: openAPIObject{"availability": Availability{}}},
+	"GET /api/availability/friends":           {Response: openAPIObject{"data": []Availability{}}},
+	"GET /api/users/me/reminders":             {Response: openAPIObject{"reminders": ReminderSettings{}}},
+	"PUT /api/users/me/reminders":             {Request: ReminderSettingsParams{}, Response: openAPIObject{"reminders": ReminderSettings{}}},
+	"GET /api/users/me/notification-settings": {Response: openAPIObject{"settings": NotificationSettings{}}},
+	"PUT /api/users/me/notification-settings": {Request: NotificationSettingsParams{}, Response: openAPIObject{"settings": NotificationSettings{}}},
+	"POST /api/users/me/push-tokens":          {Request: RegisterPushTokenParams{}},
+	"GET /api/connections":                    {Response: openAPIObject{"data": []Connection{}}},
+	"GET /api/connections/requests":           {Response: openAPIObject{"data": []Connection{}}},
+	"POST /api/connections":                   {Request: SendConnectionParams{}, Response: openAPIObject{"connection": Connection{}}, Status: http.StatusCreated},
+	"POST /api/connections/:userId/accept":    {Response: openAPIObject{"connection": Connection{}}},
+	"POST /api/connections/:userId/decline":   {Response: openAPIObject{"connection": Connection{}}},
+
+	"GET /api/webhooks":                {Response: openAPIObject{"data": []Webhook{}}},
+	"POST /api/webhooks":               {Request: CreateWebhookParams{}, Response: openAPIObject{"webhook": Webhook{}, "secret": ""}, Status: http.StatusCreated},
+	"DELETE /api/webhooks/:id":         {Status: http.StatusNoContent},
+	"GET /api/webhooks/:id/deliveries": {Response: openAPIObject{"data": []WebhookDelivery{}}},
+
+	"GET /api/bots":                             {Response: openAPIObject{"data": []Bot{}}},
+	"POST /api/bots":                            {Request: CreateBotParams{}, Response: openAPIObject{"bot": Bot{}, "api_key": ""}, Status: http.StatusCreated},
+	"DELETE /api/bots/:id":                      {Status: http.StatusNoContent},
+	"POST /api/conversations/:id/bots":          {Request: AddBotParams{}, Response: openAPIObject{"bot": Bot{}}},
+	"DELETE /api/conversations/:id/bots/:botId": {Status: http.StatusNoContent},
+	// The bearer token here is the bot's API key, or the bot token it was
+	// exchanged for, not a user session.
+	"POST /api/bots/token":        {Response: openAPIObject{"token": "", "roles": []string{}, "expires_at": time.Time{}}, Auth: authNone},
+	"POST /api/bots/:id/messages": {Request: BotMessageParams{}, Response: openAPIObject{"message": messagePayload{}}, Status: http.StatusCreated},
+
+	"GET /api/conversations":                                        {Response: listConversationResponse{}},
+	"GET /api/conversations/search":                                 {Response: listConversationResponse{}},
+	"GET /api/conversations/unread":                                 {Response: openAPIObject{"unreadMessages": 0, "unreadConversations": 0}},
+	"GET /api/conversations/:id":                                    {Response: openAPIObject{"conversation": ConversationDetail{}}},
+	"GET /api/conversations/:id/messages":                           {Response: listMessagesResponse{}},
+	"POST /api/conversations/:id/messages/schedule":                 {Request: ScheduleMessageParams{}, Response: openAPIObject{"scheduledMessage": ScheduledMessage{}}, Status: http.StatusCreated},
+	"GET /api/conversations/:id/messages/scheduled":                 {Response: openAPIObject{"scheduledMessages": []ScheduledMessage{}}},
+	"DELETE /api/conversations/:id/messages/scheduled/:scheduledId": {Status: http.StatusNoContent},
+	"GET /api/conversations/:id/shadow-bans":                        {Response: openAPIObject{"bans": []ShadowBan{}}},
+	"PUT /api/conversations/:id/shadow-bans/:userId":                {Status: http.StatusNoContent},
+	"DELETE /api/conversations/:id/shadow-bans/:userId":             {Status: http.StatusNoContent},
+	"POST /api/conversations/:id/polls":                             {Request: CreatePollParams{}, Response: openAPIObject{"message": messagePayload{}}, Status: http.StatusCreated},
+	"GET /api/conversations/:id/polls/:pollId":                      {Response: openAPIObject{"poll": Poll{}}},
+	"PUT /api/conversations/:id/polls/:pollId/votes":                {Request: PollVoteParams{}, Response: openAPIObject{"poll": Poll{}}},
+	"DELETE /api/conversations/:id/polls/:pollId/votes":             {Response: openAPIObject{"poll": Poll{}}},
+	"POST /api/conversations/:id/polls/:pollId/close":               {Response: openAPIObject{"poll": Poll{}}},
+	"POST /api/events/:id/time-poll":                                {Request: CreateTimePollParams{}, Response: openAPIObject{"poll": TimePoll{}}, Status: http.StatusCreated},
+	"GET /api/events/:id/time-poll":                                 {Response: openAPIObject{"poll": TimePoll{}}},
+	"PUT /api/events/:id/time-poll/availability":                    {Request: TimePollAvailabilityParams{}, Response: openAPIObject{"poll": TimePoll{}}},
+	"POST /api/events/:id/time-poll/confirm":                        {Request: ConfirmTimeSlotParams{}, Response: openAPIObject{"poll": TimePoll{}}},
+	"DELETE /api/events/:id/time-poll":                              {Status: http.StatusNoContent},
+	"POST /api/conversations":                                       {Request: createConversationRequest{}, Response: createConversationResponse{}, Status: http.StatusCreated},
+	"POST /api/conversations/direct":                                {Request: createDirectConversationRequest{}, Response: createConversationResponse{}},
+	"POST /api/conversations/:id/members":                           {Request: addConversationMembersRequest{}, Response: openAPIObject{"conversation": ConversationSummary{}, "addedIds": []int64{}}},
+	"PUT /api/conversations/:id/title":                              {Request: renameConversationRequest{}, Response: createConversationResponse{}},
+	"POST /api/conversations/:id/read":                              {Request: markReadRequest{}, Response: openAPIObject{"conversationId": int64(0), "unreadCount": 0}},
+	"POST /api/conversations/:id/mute":                              {Request: MuteConversationParams{}, Response: openAPIObject{"conversationId": int64(0), "mutedUntil": time.Time{}}},
+	"PUT /api/conversations/:id/slow-mode":                          {Request: SlowModeParams{}, Response: createConversationResponse{}},
+
+	"POST /api/events/:id/chat/requests":                 {Request: JoinRequestParams{}, Response: joinRequestResponse{}, Status: http.StatusCreated},
+	"GET /api/events/:id/chat/requests":                  {Response: listJoinRequestsResponse{}},
+	"POST /api/events/:id/chat/requests/batch":           {Request: JoinDecisionsParams{}, Response: openAPIObject{"results": []JoinDecisionResult{}, "conversationId": int64(0)}},
+	"POST /api/events/:id/chat/requests/:userId/approve": {Response: openAPIObject{"request": ConversationJoinRequest{}, "conversationId": int64(0)}},
+	"POST /api/events/:id/chat/requests/:userId/deny":    {Response: joinRequestResponse{}},
+	"POST /api/events/:id/chat/waitlist/:userId/promote": {Response: openAPIObject{"request": ConversationJoinRequest{}, "conversationId": int64(0)}},
+	"GET /api/events/:id/members":                        {Response: eventMembersResponse{}},
+	"GET /api/events/:id/chat/bans":                      {Response: openAPIObject{"bans": []EventBan{}}},
+	"POST /api/events/:id/invites":                       {Request: CreateInviteParams{}, Response: openAPIObject{"token": "", "eventId": int64(0), "expiresAt": time.Time{}}, Status: http.StatusCreated},
+	"POST /api/invites/:token/accept":                    {Response: openAPIObject{"eventId": int64(0), "conversationId": int64(0)}},
+	"GET /api/links/resolve":                             {Response: openAPIObject{"link": resolvedLink{}}},
+
+	// The socket and stream authenticate with a token in the handshake.
+	"GET /api/ws":            {Auth: authNone},
+	"GET /api/events/stream": {Auth: authNone},
+}
+
+// registerOpenAPIRoutes serves an OpenAPI 3 document for every route
+// registered so far at /api/openapi.json, so call it last. With
+// API_DOCS_UI=true, Swagger UI is served at /api/docs.
+func registerOpenAPIRoutes(r *gin.Engine) {
+	spec, err := json.Marshal(buildOpenAPISpec(r.Routes(), openAPIOperations))
+	if err != nil {
+		log.Fatalf("failed to build OpenAPI spec: %v", err)
+	}
+	r.GET("/api/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+
+	if os.Getenv("API_DOCS_UI") == "true" {
+		r.GET("/api/docs", func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+		})
+	}
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Who Else Is Free API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "/api/openapi.json", dom_id: "#swagger-ui"});</script>
+</body>
+</html>`
+
+// buildOpenAPISpec turns the route table into an OpenAPI document, taking
+// summaries from the handler names and schemas from the documented types.
+func buildOpenAPISpec(routes gin.RoutesInfo, operations map[string]openAPIOperation) map[string]any {
+	schemas := openAPISchemas{components: map[string]any{}}
+	requests := openAPISchemas{components: schemas.components, request: true}
+	paths := map[string]map[string]any{}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	for _, route := range routes {
+		// Operator routes behind basic auth stay out of the public document.
+		if strings.HasPrefix(route.Path, "/debug/") || strings.HasPrefix(route.Path, "/admin/") {
+			continue
+		}
+		op := operations[route.Method+" "+route.Path]
+		path, params := openAPIPath(route.Path)
+
+		operation := map[string]any{
+			"operationId": handlerName(route.Handler),
+			"summary":     handlerSummary(route.Handler),
+			"tags":        []string{openAPITag(route.Path)},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		switch op.Auth {
+		case authRequired:
+			operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+		case authOptional:
+			operation["security"] = []map[string][]string{{}, {"bearerAuth": {}}}
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": requests.of(reflect.TypeOf(op.Request))}},
+			}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := map[string]any{"description": http.StatusText(status)}
+		if op.Response != nil {
+			response["content"] = map[string]any{"application/json": map[string]any{"schema": schemas.value(op.Response)}}
+		}
+		operation["responses"] = map[string]any{
+			strconv.Itoa(status): response,
+			"default": map[string]any{
+				"description": "Error",
+				"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}}},
+			},
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	schemas.components["Error"] = map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"error": map[string]any{"type": "string"}},
+		"required":   []string{"error"},
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": "Who Else Is Free API", "version": "1.0.0"},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas":         schemas.components,
+			"securitySchemes": map[string]any{"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"}},
+		},
+	}
+}
+
+// openAPIPath rewrites gin's `:id` and `*rest` segments as `{id}` and lists
+// them as path parameters; names ending in "id" are integers.
+func openAPIPath(ginPath string) (string, []map[string]any) {
+	segments := strings.Split(ginPath, "/")
+	var params []map[string]any
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		schema := map[string]any{"type": "string"}
+		if strings.HasSuffix(strings.ToLower(name), "id") {
+			schema = map[string]any{"type": "integer", "format": "int64"}
+		}
+		params = append(params, map[string]any{"name": name, "in": "path", "required": true, "schema": schema})
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// openAPITag groups operations by their first path segment after /api.
+func openAPITag(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, "/api"), "/")
+	if len(parts) > 1 && parts[1] != "" {
+		return parts[1]
+	}
+	return "api"
+}
+
+// handlerName extracts the method name from a gin handler name such as
+// "main.(*ChatHTTPHandler).listConversations-fm".
+func handlerName(handler string) string {
+	name := strings.TrimSuffix(handler[strings.LastIndex(handler, ".")+1:], "-fm")
+	if strings.HasPrefix(name, "func") {
+		// An anonymous handler; use the function that registered it.
+		rest := strings.TrimSuffix(handler, "."+name)
+		name = rest[strings.LastIndex(rest, ".")+1:]
+	}
+	return name
+}
+
+// handlerSummary turns "listConversations" into "List conversations".
+func handlerSummary(handler string) string {
+	var words []string
+	var current []rune
+	for _, r := range handlerName(handler) {
+		if unicode.IsUpper(r) && len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	words = append(words, string(current))
+	summary := strings.Join(words, " ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
+
+// openAPISchemas builds JSON schemas by reflection, collecting named structs
+// under components/schemas. Request schemas take required fields from
+// binding tags alone; response fields are required unless they are omitempty
+// or pointers.
+type openAPISchemas struct {
+	components map[string]any
+	request    bool
+}
+
+// value describes a documented body: an openAPIObject inline, anything else
+// by its type.
+func (s openAPISchemas) value(v any) map[string]any {
+	object, ok := v.(openAPIObject)
+	if !ok {
+		return s.of(reflect.TypeOf(v))
+	}
+	properties := map[string]any{}
+	required := make([]string, 0, len(object))
+	for name, value := range object {
+		properties[name] = s.value(value)
+		required = append(required, name)
+	}
+	sort.Strings(required)
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (s openAPISchemas) of(t reflect.Type) map[string]any {
+	switch {
+	case t == nil:
+		return map[string]any{}
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.of(t.Elem())
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.Struct:
+		return s.structRef(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structRef registers t under its Go name and returns a reference to it.
+// Unnamed structs are described inline.
+func (s openAPISchemas) structRef(t reflect.Type) map[string]any {
+	name := t.Name()
+	if name == "" {
+		return s.structSchema(t)
+	}
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, done := s.components[name]; done {
+		return ref
+	}
+	s.components[name] = map[string]any{} // placeholder for recursive types
+	s.components[name] = s.structSchema(t)
+	return ref
+}
+
+func (s openAPISchemas) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	s.collectFields(t, properties, &required)
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// collectFields mirrors encoding/json: embedded structs are flattened and
+// "-" is skipped.
+func (s openAPISchemas) collectFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.collectFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.of(field.Type)
+
+		if s.request {
+			if strings.Contains(field.Tag.Get("binding"), "required") {
+				*required = append(*required, name)
+			}
+		} else if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
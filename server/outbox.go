@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// defaultOutboxPollInterval is how often the outbox is checked when nothing
+	// signals new rows: retries, and rows left by a process that died.
+	defaultOutboxPollInterval = 5 * time.Second
+	// outboxLease is how long a claimed row is hidden from other dispatchers
+	// while it is being delivered.
+	outboxLease = time.Minute
+	// outboxBatchSize caps how many rows one dispatch pass claims.
+	outboxBatchSize = 50
+	// outboxMaxAttempts is how many deliveries are tried before a row is
+	// marked failed.
+	outboxMaxAttempts = 10
+	// outboxMaxBackoff caps the wait between attempts.
+	outboxMaxBackoff = 10 * time.Minute
+	// outboxRetention is how long delivered and failed rows are kept.
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Outbox entry kinds.
+const (
+	outboxMessagePush     = "message:push"
+	outboxJoinDecision    = "join_request:decided"
+	outboxWebhookEvent    = "webhook:event"
+	outboxWebhookDelivery = "webhook:deliver"
+	outboxLinkPreview     = "message:link_preview"
+)
+
+const createTableOutbox = `
+CREATE TABLE IF NOT EXISTS outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    available_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    delivered_at DATETIME
+);
+`
+
+const createIndexOutboxPending = `
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(status, available_at);
+`
+
+const insertOutboxEntry = `
+INSERT INTO outbox (kind, payload)
+VALUES (?, ?);
+`
+
+const selectDueOutboxEntries = `
+SELECT id, kind, payload, attempts
+FROM outbox
+WHERE status = 'pending' AND available_at <= ?
+ORDER BY id
+LIMIT ?;
+`
+
+const leaseOutboxEntry = `
+UPDATE outbox
+SET available_at = ?, attempts = attempts + 1
+WHERE id = ?;
+`
+
+const markOutboxDelivered = `
+UPDATE outbox
+SET status = 'delivered', delivered_at = CURRENT_TIMESTAMP, last_error = NULL
+WHERE id = ?;
+`
+
+const markOutboxRetry = `
+UPDATE outbox
+SET status = ?, available_at = ?, last_error = ?
+WHERE id = ?;
+`
+
+const deleteFinishedOutboxEntries = `
+DELETE FROM outbox
+WHERE status IN ('delivered', 'failed') AND created_at < ?;
+`
+
+const selectMessageByID = `
+SELECT id, conversation_id, sender_id, body, attachment_url, delivery_status, kind, message_type, metadata, created_at
+FROM messages
+WHERE id = ?;
+`
+
+// outboxMessagePayload is the payload of a message:push entry.
+type outboxMessagePayload struct {
+	MessageID int64 `json:"message_id"`
+}
+
+// outboxJoinDecisionPayload is the payload of a join_request:decided entry.
+type outboxJoinDecisionPayload struct {
+	ConversationID int64 `json:"conversation_id"`
+	RequestID      int64 `json:"request_id"`
+}
+
+// outboxEntry is a claimed outbox row. Attempts includes the current one.
+type outboxEntry struct {
+	id       int64
+	kind     string
+	payload  []byte
+	attempts int
+}
+
+func (r *EventRepository) initOutbox(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableOutbox); err != nil {
+		return fmt.Errorf("create outbox table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexOutboxPending); err != nil {
+		return fmt.Errorf("create outbox index: %w", err)
+	}
+	return nil
+}
+
+// enqueueOutbox records a notification in tx, so it is written if and only if
+// the change it announces is. Call signalOutbox once tx commits.
+func enqueueOutbox(ctx context.Context, tx *sql.Tx, kind string, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode outbox payload: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, insertOutboxEntry, kind, string(encoded)); err != nil {
+		return fmt.Errorf("insert outbox entry: %w", err)
+	}
+	return nil
+}
+
+// signalOutbox wakes the dispatcher without waiting for its next poll.
+func (r *EventRepository) signalOutbox() {
+	select {
+	case r.outboxReady <- struct{}{}:
+	default:
+	}
+}
+
+// ClaimOutboxEntries leases up to limit due rows until now+lease and counts
+// the attempt. A dispatcher that dies mid-delivery leaves its rows to be
+// claimed again when the lease runs out.
+func (r *EventRepository) ClaimOutboxEntries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]outboxEntry, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin claim outbox tx: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, selectDueOutboxEntries, sqliteTime(now), limit)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("list due outbox entries: %w", err)
+	}
+	var entries []outboxEntry
+	for rows.Next() {
+		var entry outboxEntry
+		var payload string
+		if err := rows.Scan(&entry.id, &entry.kind, &payload, &entry.attempts); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, fmt.Errorf("scan outbox entry: %w", err)
+		}
+		entry.payload = []byte(payload)
+		entry.attempts++
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("iterate outbox entries: %w", err)
+	}
+	rows.Close()
+
+	leasedUntil := sqliteTime(now.Add(lease))
+	for _, entry := range entries {
+		if _, err := tx.ExecContext(ctx, leaseOutboxEntry, leasedUntil, entry.id); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("lease outbox entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit claim outbox: %w", err)
+	}
+	return entries, nil
+}
+
+// MarkOutboxDelivered records a successful delivery.
+func (r *EventRepository) MarkOutboxDelivered(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, markOutboxDelivered, id); err != nil {
+		return fmt.Errorf("mark outbox delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkOutboxRetry schedules another attempt at retryAt, or marks the row
+// failed when giveUp is set.
+func (r *EventRepository) MarkOutboxRetry(ctx context.Context, id int64, retryAt time.Time, giveUp bool, deliveryErr error) error {
+	status := "pending"
+	if giveUp {
+		status = "failed"
+	}
+	if _, err := r.db.ExecContext(ctx, markOutboxRetry, status, sqliteTime(retryAt), deliveryErr.Error(), id); err != nil {
+		return fmt.Errorf("mark outbox retry: %w", err)
+	}
+	return nil
+}
+
+// PruneOutbox deletes delivered and failed rows created before cutoff.
+func (r *EventRepository) PruneOutbox(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteFinishedOutboxEntries, sqliteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("prune outbox: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetMessageByID returns one message by its ID.
+func (r *EventRepository) GetMessageByID(ctx context.Context, id int64) (*Message, error) {
+	msg, err := scanMessage(r.db.QueryRowContext(ctx, selectMessageByID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("fetch message: %w", err)
+	}
+	return &msg, nil
+}
+
+// GetJoinRequestByID returns one join request by its ID.
+func (r *EventRepository) GetJoinRequestByID(ctx context.Context, id int64) (*ConversationJoinRequest, error) {
+	return fetchJoinRequestByID(ctx, r.db, id)
+}
+
+// OutboxDispatcher delivers outbox rows written by repository transactions:
+// message pushes, join decision notices, webhooks and link previews. Rows are only marked
+// delivered after the push provider accepts them, the hub takes them or the
+// webhook answers, so a crash in between replays them (at-least-once
+// delivery).
+type OutboxDispatcher struct {
+	repo     *EventRepository
+	hub      *ChatHub
+	webhooks *webhookSender
+	previews LinkPreviewer
+	interval time.Duration
+}
+
+// newOutboxDispatcher polls every config.OutboxPollInterval. Link previews are
+// fetched only when previews is non-nil.
+func newOutboxDispatcher(repo *EventRepository, hub *ChatHub, previews LinkPreviewer, config JobsConfig) *OutboxDispatcher {
+	repo.queueLinkPreviews = previews != nil
+	return &OutboxDispatcher{repo: repo, hub: hub, webhooks: newWebhookSenderFromEnv(), previews: previews, interval: config.OutboxPollInterval}
+}
+
+// Run dispatches once immediately, then whenever the repository signals new
+// rows or the poll interval passes, until ctx is cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.repo.outboxReady:
+		case <-ticker.C:
+		}
+	}
+}
+
+// RegisterPruning schedules daily cleanup of finished rows on runner.
+func (d *OutboxDispatcher) RegisterPruning(runner *JobRunner) {
+	runner.Register("outbox_prune", 24*time.Hour, func(ctx context.Context) error {
+		pruneCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+
+		pruned, err := d.repo.PruneOutbox(pruneCtx, time.Now().Add(-outboxRetention))
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Printf("pruned %d outbox entries", pruned)
+		}
+		return nil
+	})
+}
+
+// dispatch drains due rows a batch at a time.
+func (d *OutboxDispatcher) dispatch(ctx context.Context) {
+	for {
+		claimCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		entries, err := d.repo.ClaimOutboxEntries(claimCtx, time.Now(), outboxLease, outboxBatchSize)
+		cancel()
+		if err != nil {
+			log.Printf("claim outbox entries failed: %v", err)
+			return
+		}
+		for _, entry := range entries {
+			d.deliverEntry(ctx, entry)
+		}
+		if len(entries) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (d *OutboxDispatcher) deliverEntry(ctx context.Context, entry outboxEntry) {
+	deliverCtx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+
+	deliveryErr := d.deliver(deliverCtx, entry)
+
+	markCtx, markCancel := context.WithTimeout(ctx, requestTimeout)
+	defer markCancel()
+	if deliveryErr == nil {
+		if err := d.repo.MarkOutboxDelivered(markCtx, entry.id); err != nil {
+			log.Printf("outbox entry %d: %v", entry.id, err)
+		}
+		return
+	}
+
+	giveUp := entry.attempts >= outboxMaxAttempts
+	// attempts never exceeds outboxMaxAttempts, so the shift can't overflow.
+	backoff := min(time.Duration(1<<entry.attempts)*time.Second, outboxMaxBackoff)
+	if giveUp {
+		log.Printf("outbox entry %d (%s) failed for good: %v", entry.id, entry.kind, deliveryErr)
+	} else {
+		log.Printf("outbox entry %d (%s) failed, retrying in %s: %v", entry.id, entry.kind, backoff, deliveryErr)
+	}
+	if err := d.repo.MarkOutboxRetry(markCtx, entry.id, time.Now().Add(backoff), giveUp, deliveryErr); err != nil {
+		log.Printf("outbox entry %d: %v", entry.id, err)
+	}
+}
+
+func (d *OutboxDispatcher) deliver(ctx context.Context, entry outboxEntry) error {
+	switch entry.kind {
+	case outboxMessagePush:
+		var payload outboxMessagePayload
+		if err := json.Unmarshal(entry.payload, &payload); err != nil {
+			return fmt.Errorf("decode payload: %w", err)
+		}
+		msg, err := d.repo.GetMessageByID(ctx, payload.MessageID)
+		if errors.Is(err, ErrMessageNotFound) {
+			// Deleted before we got to it; nothing left to announce.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return d.hub.sendMessagePush(ctx, *msg)
+	case outboxJoinDecision:
+		var payload outboxJoinDecisionPayload
+		if err := json.Unmarshal(entry.payload, &payload); err != nil {
+			return fmt.Errorf("decode payload: %w", err)
+		}
+		req, err := d.repo.GetJoinRequestByID(ctx, payload.RequestID)
+		if errors.Is(err, ErrJoinRequestNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		d.hub.NotifyJoinDecision(payload.ConversationID, *req)
+		d.hub.emailJoinDecision(ctx, *req)
+		return nil
+	case outboxWebhookEvent:
+		return d.fanOutWebhookEvent(ctx, entry)
+	case outboxWebhookDelivery:
+		return d.deliverWebhook(ctx, entry)
+	case outboxLinkPreview:
+		return d.deliverLinkPreview(ctx, entry)
+	default:
+		return fmt.Errorf("unknown outbox kind %q", entry.kind)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Forgotten passwords are reset by email: asking mails a single-use link
+// carrying a random token, and posting that token with a new password sets
+// it. Only the token's hash is stored, so a leaked database can't be used to
+// take accounts over.
+
+const (
+	// passwordResetTTL is how long a reset link works.
+	passwordResetTTL = time.Hour
+	// passwordResetCooldown stops the same account being mailed over and over.
+	passwordResetCooldown = time.Minute
+	// defaultPasswordResetURL is the app screen reset links open.
+	defaultPasswordResetURL = "http://localhost:8081/reset-password"
+)
+
+var ErrInvalidResetToken = errors.New("password reset token is invalid or expired")
+
+const createTablePasswordResetTokens = `
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexPasswordResetTokensUser = `
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user
+ON password_reset_tokens(user_id, created_at);
+`
+
+const selectAccountByEmail = `
+SELECT id, name, email
+FROM users
+WHERE email = ? AND deleted_at IS NULL;
+`
+
+const selectRecentPasswordReset = `
+SELECT 1 FROM password_reset_tokens
+WHERE user_id = ? AND created_at > ?
+LIMIT 1;
+`
+
+// Asking again replaces any link still outstanding.
+const deleteUnusedPasswordResetTokens = `
+DELETE FROM password_reset_tokens
+WHERE user_id = ? AND used_at IS NULL;
+`
+
+const insertPasswordResetToken = `
+INSERT INTO password_reset_tokens (token_hash, user_id, expires_at, created_at)
+VALUES (?, ?, ?, ?);
+`
+
+const claimPasswordResetToken = `
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+RETURNING user_id;
+`
+
+const deletePasswordResetTokensForUser = `
+DELETE FROM password_reset_tokens
+WHERE user_id = ?;
+`
+
+const updateUserPasswordByID = `
+UPDATE users SET password = ? WHERE id = ? AND deleted_at IS NULL;
+`
+
+func (r *EventRepository) initPasswordResets(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTablePasswordResetTokens); err != nil {
+		return fmt.Errorf("create password reset tokens table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexPasswordResetTokensUser); err != nil {
+		return fmt.Errorf("create password reset tokens index: %w", err)
+	}
+	return nil
+}
+
+// CreatePasswordResetToken issues a reset token for the account with this
+// email and returns it with the account. It returns ErrUserNotFound for
+// unknown emails, and a nil user without error when a link went out within
+// passwordResetCooldown.
+func (r *EventRepository) CreatePasswordResetToken(ctx context.Context, email string, now time.Time) (string, *User, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("begin password reset tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var user User
+	err = tx.QueryRowContext(ctx, selectAccountByEmail, strings.TrimSpace(email)).Scan(&user.ID, &user.Name, &user.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrUserNotFound
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch password reset user: %w", err)
+	}
+
+	var recent int
+	err = tx.QueryRowContext(ctx, selectRecentPasswordReset, user.ID, sqliteTime(now.Add(-passwordResetCooldown))).Scan(&recent)
+	if err == nil {
+		return "", nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", nil, fmt.Errorf("check recent password reset: %w", err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("generate password reset token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if _, err := tx.ExecContext(ctx, deleteUnusedPasswordResetTokens, user.ID); err != nil {
+		return "", nil, fmt.Errorf("replace password reset tokens: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, insertPasswordResetToken, hashSecretToken(token), user.ID, sqliteTime(now.Add(passwordResetTTL)), sqliteTime(now)); err != nil {
+		return "", nil, fmt.Errorf("store password reset token: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", nil, fmt.Errorf("commit password reset token: %w", err)
+	}
+	return token, &user, nil
+}
+
+// ResetPassword spends token on setting the account's new password. It
+// returns ErrInvalidResetToken if the token is unknown, used or expired.
+func (r *EventRepository) ResetPassword(ctx context.Context, token, password string, now time.Time) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin reset password tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRowContext(ctx, claimPasswordResetToken, hashSecretToken(token), sqliteTime(now)).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return fmt.Errorf("claim password reset token: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, updateUserPasswordByID, password, userID)
+	if err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	} else if affected == 0 {
+		return ErrInvalidResetToken
+	}
+	if _, err := tx.ExecContext(ctx, deleteUnusedPasswordResetTokens, userID); err != nil {
+		return fmt.Errorf("clear password reset tokens: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit reset password: %w", err)
+	}
+	return nil
+}
+
+type passwordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type confirmPasswordResetRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// passwordResetURLFromEnv reads PASSWORD_RESET_URL, the app screen reset
+// links open; the token is added as the `token` query parameter.
+func passwordResetURLFromEnv() string {
+	if raw := strings.TrimSpace(os.Getenv("PASSWORD_RESET_URL")); raw != "" {
+		return raw
+	}
+	return defaultPasswordResetURL
+}
+
+// tokenLink adds token to an app link as the `token` query parameter.
+func tokenLink(base, token string) string {
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// requestPasswordReset emails a reset link to the account with this email.
+// The answer is the same whether or not the account exists, and the email is
+// sent in the background so timing doesn't tell either.
+//
+// Responses:
+//  - 202 once the request is accepted
+//  - 400 for invalid JSON or email
+//  - 500 for repository/database failures
+//  - 503 if email is not configured
+func (h *AuthHandler) requestPasswordReset(c *gin.Context) {
+	if h.mailer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "password reset is not available"})
+		return
+	}
+
+	var payload passwordResetRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	token, user, err := h.repo.CreatePasswordResetToken(ctx, payload.Email, time.Now())
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to reset password"})
+		return
+	}
+	if user != nil {
+		go sendEmail(context.Background(), h.mailer, passwordResetEmail, user.Email, passwordResetEmailData{
+			Name:      user.Name,
+			Link:      tokenLink(h.resetURL, token),
+			ExpiresIn: "1 hour",
+		})
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If that account exists, a reset link is on its way"})
+}
+
+// confirmPasswordReset sets a new password with the token from a reset link.
+//
+// Responses:
+//  - 200 once the password is changed
+//  - 400 for invalid JSON, a password under 8 characters, or a token that is
+//    unknown, used or expired
+//  - 500 for repository/database failures
+func (h *AuthHandler) confirmPasswordReset(c *gin.Context) {
+	var payload confirmPasswordResetRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.ResetPassword(ctx, payload.Token, payload.Password, time.Now()); err != nil {
+		if errors.Is(err, ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "This reset link is invalid or has expired"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated"})
+}
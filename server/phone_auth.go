@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Phone sign-in works by one-time code: asking texts a six digit code to the
+// number, and posting it back signs in the account with that phone. A signed-in
+// user links a phone the same way, so codes go out to any number and only the
+// code's hash is stored.
+
+const (
+	// phoneOTPDigits is the length of a texted code.
+	phoneOTPDigits = 6
+	// phoneOTPTTL is how long a code works.
+	phoneOTPTTL = 10 * time.Minute
+	// phoneOTPCooldown stops the same number being texted over and over.
+	phoneOTPCooldown = 30 * time.Second
+	// phoneOTPMaxAttempts is how many wrong guesses burn a code.
+	phoneOTPMaxAttempts = 5
+	// phoneOTPMaxFailures is how many wrong guesses a phone gets across all
+	// its codes within phoneOTPFailureWindow. Asking for a new code doesn't
+	// reset it, so burning codes can't buy more guesses.
+	phoneOTPMaxFailures = 10
+	// phoneOTPFailureWindow is how far back wrong guesses count. A locked
+	// phone unlocks as its oldest counted guess ages out.
+	phoneOTPFailureWindow = time.Hour
+)
+
+var ErrInvalidOTP = errors.New("one-time code is invalid or expired")
+var ErrOTPCooldown = errors.New("a code was sent to this phone moments ago")
+var ErrPhoneTaken = errors.New("phone number belongs to another account")
+var ErrOTPLocked = errors.New("too many wrong codes for this phone")
+
+const createTablePhoneOTPs = `
+CREATE TABLE IF NOT EXISTS phone_otps (
+    phone TEXT PRIMARY KEY,
+    code_hash TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const createTablePhoneOTPFailures = `
+CREATE TABLE IF NOT EXISTS phone_otp_failures (
+    phone TEXT NOT NULL,
+    failed_at DATETIME NOT NULL
+);
+`
+
+const createIndexPhoneOTPFailures = `
+CREATE INDEX IF NOT EXISTS idx_phone_otp_failures_phone
+ON phone_otp_failures(phone, failed_at);
+`
+
+const createIndexUsersPhone = `
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone
+ON users(phone)
+WHERE phone IS NOT NULL;
+`
+
+const selectRecentPhoneOTP = `
+SELECT 1 FROM phone_otps
+WHERE phone = ? AND created_at > ?
+LIMIT 1;
+`
+
+// Asking again replaces the code still outstanding.
+const upsertPhoneOTP = `
+INSERT INTO phone_otps (phone, code_hash, expires_at, attempts, created_at)
+VALUES (?, ?, ?, 0, ?)
+ON CONFLICT(phone) DO UPDATE SET
+    code_hash = excluded.code_hash,
+    expires_at = excluded.expires_at,
+    attempts = 0,
+    created_at = excluded.created_at;
+`
+
+const selectPhoneOTP = `
+SELECT code_hash, attempts
+FROM phone_otps
+WHERE phone = ? AND expires_at > ?;
+`
+
+const updatePhoneOTPAttempts = `
+UPDATE phone_otps SET attempts = ? WHERE phone = ?;
+`
+
+const deletePhoneOTP = `
+DELETE FROM phone_otps
+WHERE phone = ?;
+`
+
+const countPhoneOTPFailures = `
+SELECT COUNT(*) FROM phone_otp_failures
+WHERE phone = ? AND failed_at > ?;
+`
+
+const insertPhoneOTPFailure = `
+INSERT INTO phone_otp_failures (phone, failed_at)
+VALUES (?, ?);
+`
+
+// Guesses older than the window no longer count for any phone.
+const deleteStalePhoneOTPFailures = `
+DELETE FROM phone_otp_failures
+WHERE failed_at <= ?;
+`
+
+const deletePhoneOTPFailures = `
+DELETE FROM phone_otp_failures
+WHERE phone = ?;
+`
+
+const selectUserByPhone = `
+SELECT id, name, email, created_at
+FROM users
+WHERE phone = ? AND deleted_at IS NULL;
+`
+
+const selectPhoneOwner = `
+SELECT id FROM users
+WHERE phone = ? AND id != ?
+LIMIT 1;
+`
+
+const updateUserPhone = `
+UPDATE users SET phone = ? WHERE id = ? AND deleted_at IS NULL;
+`
+
+func (r *EventRepository) initPhoneAuth(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "users", "phone", "TEXT"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexUsersPhone); err != nil {
+		return fmt.Errorf("create users phone index: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTablePhoneOTPs); err != nil {
+		return fmt.Errorf("create phone otps table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTablePhoneOTPFailures); err != nil {
+		return fmt.Errorf("create phone otp failures table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexPhoneOTPFailures); err != nil {
+		return fmt.Errorf("create phone otp failures index: %w", err)
+	}
+	return nil
+}
+
+// hashPhoneOTP ties a code to its phone, so equal codes for two numbers
+// don't share a hash.
+func hashPhoneOTP(phone, code string) string {
+	return hashSecretToken(phone + ":" + code)
+}
+
+// phoneOTPLocked reports whether phone has used up its wrong guesses for
+// the window ending at now.
+func phoneOTPLocked(ctx context.Context, tx *sql.Tx, phone string, now time.Time) (bool, error) {
+	var failures int
+	if err := tx.QueryRowContext(ctx, countPhoneOTPFailures, phone, sqliteTime(now.Add(-phoneOTPFailureWindow))).Scan(&failures); err != nil {
+		return false, fmt.Errorf("count phone otp failures: %w", err)
+	}
+	return failures >= phoneOTPMaxFailures, nil
+}
+
+// CreatePhoneOTP issues a code for phone, replacing any outstanding one. It
+// returns ErrOTPCooldown when a code went out within phoneOTPCooldown, and
+// ErrOTPLocked while the phone is locked out, since the code couldn't be
+// used.
+func (r *EventRepository) CreatePhoneOTP(ctx context.Context, phone string, now time.Time) (string, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return "", fmt.Errorf("begin phone otp tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if locked, err := phoneOTPLocked(ctx, tx, phone, now); err != nil {
+		return "", err
+	} else if locked {
+		return "", ErrOTPLocked
+	}
+
+	var recent int
+	err = tx.QueryRowContext(ctx, selectRecentPhoneOTP, phone, sqliteTime(now.Add(-phoneOTPCooldown))).Scan(&recent)
+	if err == nil {
+		return "", ErrOTPCooldown
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("check recent phone otp: %w", err)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("generate phone otp: %w", err)
+	}
+	code := fmt.Sprintf("%0*d", phoneOTPDigits, n.Int64())
+
+	if _, err := tx.ExecContext(ctx, upsertPhoneOTP, phone, hashPhoneOTP(phone, code), sqliteTime(now.Add(phoneOTPTTL)), sqliteTime(now)); err != nil {
+		return "", fmt.Errorf("store phone otp: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit phone otp: %w", err)
+	}
+	return code, nil
+}
+
+// VerifyPhoneOTP spends the code issued for phone. It returns ErrInvalidOTP
+// if there is no live code or it doesn't match; after phoneOTPMaxAttempts
+// wrong guesses the code is dropped. Once the phone has phoneOTPMaxFailures
+// wrong guesses within phoneOTPFailureWindow, whichever codes they were
+// against, it returns ErrOTPLocked without checking the code.
+func (r *EventRepository) VerifyPhoneOTP(ctx context.Context, phone, code string, now time.Time) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin verify phone otp tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if locked, err := phoneOTPLocked(ctx, tx, phone, now); err != nil {
+		return err
+	} else if locked {
+		return ErrOTPLocked
+	}
+
+	var codeHash string
+	var attempts int
+	err = tx.QueryRowContext(ctx, selectPhoneOTP, phone, sqliteTime(now)).Scan(&codeHash, &attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidOTP
+	}
+	if err != nil {
+		return fmt.Errorf("fetch phone otp: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(hashPhoneOTP(phone, code))) != 1 {
+		if attempts+1 >= phoneOTPMaxAttempts {
+			_, err = tx.ExecContext(ctx, deletePhoneOTP, phone)
+		} else {
+			_, err = tx.ExecContext(ctx, updatePhoneOTPAttempts, attempts+1, phone)
+		}
+		if err != nil {
+			return fmt.Errorf("record phone otp attempt: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deleteStalePhoneOTPFailures, sqliteTime(now.Add(-phoneOTPFailureWindow))); err != nil {
+			return fmt.Errorf("prune phone otp failures: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertPhoneOTPFailure, phone, sqliteTime(now)); err != nil {
+			return fmt.Errorf("record phone otp failure: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit phone otp attempt: %w", err)
+		}
+		return ErrInvalidOTP
+	}
+
+	if _, err := tx.ExecContext(ctx, deletePhoneOTP, phone); err != nil {
+		return fmt.Errorf("spend phone otp: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deletePhoneOTPFailures, phone); err != nil {
+		return fmt.Errorf("clear phone otp failures: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit verify phone otp: %w", err)
+	}
+	return nil
+}
+
+// GetUserByPhone returns the account with this phone, or ErrUserNotFound.
+func (r *EventRepository) GetUserByPhone(ctx context.Context, phone string) (*User, error) {
+	var user User
+	err := r.db.QueryRowContext(ctx, selectUserByPhone, phone).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup user by phone: %w", err)
+	}
+	return &user, nil
+}
+
+// SetUserPhone links phone to the user, replacing any phone they had. It
+// returns ErrPhoneTaken if another account has it.
+func (r *EventRepository) SetUserPhone(ctx context.Context, userID int64, phone string) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin set phone tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var ownerID int64
+	err = tx.QueryRowContext(ctx, selectPhoneOwner, phone, userID).Scan(&ownerID)
+	if err == nil {
+		return ErrPhoneTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("check phone owner: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, updateUserPhone, phone, userID)
+	if err != nil {
+		return fmt.Errorf("update phone: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	} else if affected == 0 {
+		return ErrUserNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit set phone: %w", err)
+	}
+	return nil
+}
+
+type otpRequest struct {
+	Phone string `json:"phone" binding:"required,e164"`
+}
+
+type verifyOTPRequest struct {
+	Phone string `json:"phone" binding:"required,e164"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// requestOTP texts a sign-in code to the phone. Codes go to any number,
+// since the same code links a phone to an account.
+//
+// Responses:
+//  - 202 once the code is sent
+//  - 400 for invalid JSON or a phone not in E.164 form
+//  - 429 if a code was sent to the phone in the last 30 seconds, or the
+//    phone is locked out after too many wrong codes
+//  - 500 for repository/database or delivery failures
+//  - 503 if SMS is not configured
+func (h *AuthHandler) requestOTP(c *gin.Context) {
+	if h.sms == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "phone sign-in is not available"})
+		return
+	}
+
+	var payload otpRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	code, err := h.repo.CreatePhoneOTP(ctx, payload.Phone, time.Now())
+	if err != nil {
+		if errors.Is(err, ErrOTPCooldown) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Wait a moment before asking for another code"})
+			return
+		}
+		if errors.Is(err, ErrOTPLocked) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many wrong codes; try again later"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to send code"})
+		return
+	}
+
+	smsCtx, cancelSMS := context.WithTimeout(ctx, smsTimeout)
+	defer cancelSMS()
+	body := fmt.Sprintf("Your Who Else Is Free code is %s. It expires in %d minutes.", code, int(phoneOTPTTL/time.Minute))
+	if err := h.sms.Send(smsCtx, payload.Phone, body); err != nil {
+		log.Printf("otp sms delivery failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to send code"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Code sent"})
+}
+
+// verifyOTP signs in the account with this phone using a texted code, issuing
+// the same session as login.
+//
+// Responses:
+//  - 200 with the user and session token
+//  - 400 for invalid JSON or phone
+//  - 401 if the code is wrong or expired
+//  - 404 if no account has this phone
+//  - 429 if the phone is locked out after too many wrong codes
+//  - 500 for repository/database failures
+func (h *AuthHandler) verifyOTP(c *gin.Context) {
+	var payload verifyOTPRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.VerifyPhoneOTP(ctx, payload.Phone, strings.TrimSpace(payload.Code), time.Now()); err != nil {
+		if errors.Is(err, ErrInvalidOTP) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired code"})
+			return
+		}
+		if errors.Is(err, ErrOTPLocked) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many wrong codes; try again later"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	user, err := h.repo.GetUserByPhone(ctx, payload.Phone)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No account uses this phone number"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to sign in"})
+		return
+	}
+
+	h.respondWithSession(c, ctx, user)
+}
+
+// setPhone links a phone to the caller's account with a code texted to it by
+// POST /api/otp/request, so they can sign in with it from then on.
+//
+// Responses:
+//  - 200 with the linked phone
+//  - 400 for invalid JSON or phone
+//  - 401 if the caller has no session, or the code is wrong or expired
+//  - 404 if the account no longer exists
+//  - 409 if another account has this phone
+//  - 429 if the phone is locked out after too many wrong codes
+//  - 500 for repository/database failures
+func (h *UserHandler) setPhone(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload verifyOTPRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.VerifyPhoneOTP(ctx, payload.Phone, strings.TrimSpace(payload.Code), time.Now()); err != nil {
+		if errors.Is(err, ErrInvalidOTP) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired code"})
+			return
+		}
+		if errors.Is(err, ErrOTPLocked) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many wrong codes; try again later"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to update phone"})
+		return
+	}
+
+	if err := h.repo.SetUserPhone(ctx, claims.UserID, payload.Phone); err != nil {
+		switch {
+		case errors.Is(err, ErrPhoneTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": "This phone number belongs to another account"})
+		case errors.Is(err, ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to update phone"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"phone": payload.Phone})
+}
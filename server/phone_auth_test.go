@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testPhone = "+14155550123"
+
+// wrongCode returns a code that isn't code.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+// mustCreatePhoneOTP issues a code for testPhone at now, failing the test on error.
+func mustCreatePhoneOTP(t *testing.T, repo *EventRepository, now time.Time) string {
+	t.Helper()
+	code, err := repo.CreatePhoneOTP(context.Background(), testPhone, now)
+	if err != nil {
+		t.Fatalf("create phone otp: %v", err)
+	}
+	return code
+}
+
+func TestCreatePhoneOTPCooldown(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	now := time.Now().UTC()
+	first := mustCreatePhoneOTP(t, repo, now)
+
+	if _, err := repo.CreatePhoneOTP(ctx, testPhone, now.Add(phoneOTPCooldown-time.Second)); !errors.Is(err, ErrOTPCooldown) {
+		t.Fatalf("create within cooldown error = %v, want %v", err, ErrOTPCooldown)
+	}
+	if _, err := repo.CreatePhoneOTP(ctx, "+14155550199", now); err != nil {
+		t.Fatalf("another phone is not cooling down: %v", err)
+	}
+
+	later := now.Add(phoneOTPCooldown + time.Second)
+	second := mustCreatePhoneOTP(t, repo, later)
+	if first != second {
+		// Asking again replaces the outstanding code.
+		if err := repo.VerifyPhoneOTP(ctx, testPhone, first, later); !errors.Is(err, ErrInvalidOTP) {
+			t.Fatalf("replaced code error = %v, want %v", err, ErrInvalidOTP)
+		}
+	}
+	if err := repo.VerifyPhoneOTP(ctx, testPhone, second, later); err != nil {
+		t.Fatalf("verify new code: %v", err)
+	}
+}
+
+func TestVerifyPhoneOTPBurnsCodeAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	now := time.Now().UTC()
+	code := mustCreatePhoneOTP(t, repo, now)
+
+	for i := 0; i < phoneOTPMaxAttempts; i++ {
+		if err := repo.VerifyPhoneOTP(ctx, testPhone, wrongCode(code), now); !errors.Is(err, ErrInvalidOTP) {
+			t.Fatalf("wrong guess %d error = %v, want %v", i+1, err, ErrInvalidOTP)
+		}
+	}
+	if err := repo.VerifyPhoneOTP(ctx, testPhone, code, now); !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("burnt code error = %v, want %v", err, ErrInvalidOTP)
+	}
+}
+
+func TestVerifyPhoneOTPLocksOutAcrossCodes(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	start := time.Now().UTC()
+	now := start
+
+	// Reissuing after each burnt code must not reset the phone's count.
+	for failures := 0; failures < phoneOTPMaxFailures; {
+		code := mustCreatePhoneOTP(t, repo, now)
+		for i := 0; i < phoneOTPMaxAttempts && failures < phoneOTPMaxFailures; i++ {
+			if err := repo.VerifyPhoneOTP(ctx, testPhone, wrongCode(code), now); !errors.Is(err, ErrInvalidOTP) {
+				t.Fatalf("wrong guess %d error = %v, want %v", failures+1, err, ErrInvalidOTP)
+			}
+			failures++
+		}
+		now = now.Add(phoneOTPCooldown + time.Second)
+	}
+
+	if _, err := repo.CreatePhoneOTP(ctx, testPhone, now); !errors.Is(err, ErrOTPLocked) {
+		t.Fatalf("create while locked error = %v, want %v", err, ErrOTPLocked)
+	}
+	if err := repo.VerifyPhoneOTP(ctx, testPhone, "123456", now); !errors.Is(err, ErrOTPLocked) {
+		t.Fatalf("verify while locked error = %v, want %v", err, ErrOTPLocked)
+	}
+	if _, err := repo.CreatePhoneOTP(ctx, "+14155550199", now); err != nil {
+		t.Fatalf("another phone is not locked: %v", err)
+	}
+
+	// The first code's guesses age out of the window, freeing the phone.
+	unlocked := start.Add(phoneOTPFailureWindow + time.Second)
+	code := mustCreatePhoneOTP(t, repo, unlocked)
+	if err := repo.VerifyPhoneOTP(ctx, testPhone, code, unlocked); err != nil {
+		t.Fatalf("verify after the window: %v", err)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM phone_otp_failures WHERE phone = ?`, testPhone); got != 0 {
+		t.Fatalf("failures kept after sign-in = %d, want 0", got)
+	}
+}
+
+func TestVerifyOTPLockedStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"wrong code", "/api/otp/verify", ErrInvalidOTP, http.StatusUnauthorized},
+		{"locked", "/api/otp/verify", ErrOTPLocked, http.StatusTooManyRequests},
+		{"locked, linking", "/api/users/me/phone", ErrOTPLocked, http.StatusTooManyRequests},
+		{"store failure", "/api/otp/verify", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.VerifyPhoneOTPFunc = func(context.Context, string, string, time.Time) error { return tt.err }
+			auth := NewAuthHandler(store, newTestSigner(testSessionSecret), nil, consoleSMSSender{})
+			users := NewUserHandler(store, newTestHub(store))
+			routes := func(group *gin.RouterGroup) {
+				auth.RegisterRoutes(group)
+				users.RegisterProtectedRoutes(group)
+			}
+			rec := serveAs(t, routes, testMemberID, http.MethodPost, tt.path, `{"phone":"`+testPhone+`","code":"123456"}`)
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestRequestOTPLockedStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"sent", nil, http.StatusAccepted},
+		{"cooling down", ErrOTPCooldown, http.StatusTooManyRequests},
+		{"locked", ErrOTPLocked, http.StatusTooManyRequests},
+		{"store failure", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.CreatePhoneOTPFunc = func(context.Context, string, time.Time) (string, error) {
+				if tt.err != nil {
+					return "", tt.err
+				}
+				return "123456", nil
+			}
+			auth := NewAuthHandler(store, newTestSigner(testSessionSecret), nil, consoleSMSSender{})
+			rec := serveAs(t, auth.RegisterRoutes, 0, http.MethodPost, "/api/otp/request", `{"phone":"`+testPhone+`"}`)
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
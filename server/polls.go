@@ -0,0 +1,692 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxPollQuestionLength = 200
+	maxPollOptionLength   = 80
+	minPollOptions        = 2
+	maxPollOptions        = 10
+)
+
+var ErrPollNotFound = errors.New("poll not found")
+var ErrPollClosed = errors.New("poll is closed")
+var ErrInvalidPollVote = errors.New("invalid poll vote")
+var ErrNotPollCreator = errors.New("user did not create the poll")
+
+const createTablePolls = `
+CREATE TABLE IF NOT EXISTS polls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    conversation_id INTEGER NOT NULL,
+    message_id INTEGER UNIQUE,
+    created_by INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    allow_multiple INTEGER NOT NULL DEFAULT 0,
+    closed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+`
+
+const createTablePollOptions = `
+CREATE TABLE IF NOT EXISTS poll_options (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    poll_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE
+);
+`
+
+const createTablePollVotes = `
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id INTEGER NOT NULL,
+    option_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, option_id, user_id),
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES poll_options(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const insertPoll = `
+INSERT INTO polls (conversation_id, created_by, question, allow_multiple)
+VALUES (?, ?, ?, ?);
+`
+
+const insertPollOption = `
+INSERT INTO poll_options (poll_id, position, label)
+VALUES (?, ?, ?);
+`
+
+const updatePollMessage = `
+UPDATE polls SET message_id = ? WHERE id = ?;
+`
+
+const deletePoll = `
+DELETE FROM polls WHERE id = ?;
+`
+
+const deletePollOptions = `
+DELETE FROM poll_options WHERE poll_id = ?;
+`
+
+// selectPolls expects the column (id or message_id) and its placeholders to
+// be filled in.
+const selectPolls = `
+SELECT id, conversation_id, message_id, created_by, question, allow_multiple, closed_at, created_at
+FROM polls
+WHERE %s IN (%s);
+`
+
+// selectPollResults lists every option of the polls with one row per vote,
+// or a single row with a NULL voter for options nobody picked.
+const selectPollResults = `
+SELECT o.poll_id, o.id, o.label, v.user_id
+FROM poll_options o
+LEFT JOIN poll_votes v ON v.option_id = o.id
+WHERE o.poll_id IN (%s)
+ORDER BY o.poll_id, o.position, v.created_at, v.user_id;
+`
+
+const selectPollOptionIDs = `
+SELECT id FROM poll_options WHERE poll_id = ?;
+`
+
+const deletePollVotesForUserInPoll = `
+DELETE FROM poll_votes WHERE poll_id = ? AND user_id = ?;
+`
+
+const insertPollVote = `
+INSERT INTO poll_votes (poll_id, option_id, user_id)
+VALUES (?, ?, ?);
+`
+
+const closePoll = `
+UPDATE polls SET closed_at = CURRENT_TIMESTAMP WHERE id = ? AND closed_at IS NULL;
+`
+
+const deletePollVotesForUser = `
+DELETE FROM poll_votes WHERE user_id = ?;
+`
+
+func (r *EventRepository) initPolls(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTablePolls); err != nil {
+		return fmt.Errorf("create polls table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTablePollOptions); err != nil {
+		return fmt.Errorf("create poll options table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTablePollVotes); err != nil {
+		return fmt.Errorf("create poll votes table: %w", err)
+	}
+	return nil
+}
+
+// CreatePoll stores a poll and posts the message that carries it, so it shows
+// up in the chat history like any other message. The returned message has the
+// poll attached.
+func (r *EventRepository) CreatePoll(ctx context.Context, conversationID, userID int64, params CreatePollParams) (*Message, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin create poll tx: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, insertPoll, conversationID, userID, params.Question, params.AllowMultiple)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert poll: %w", err)
+	}
+	pollID, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("poll id: %w", err)
+	}
+	for i, label := range params.Options {
+		if _, err := tx.ExecContext(ctx, insertPollOption, pollID, i, label); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert poll option: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit create poll: %w", err)
+	}
+
+	// The message goes through CreateMessage for its push and webhook side
+	// effects, so it can't share the transaction; a poll left without one is
+	// removed again.
+	msg, err := r.CreateMessage(ctx, CreateMessageParams{
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Body:           "📊 " + params.Question,
+		MessageType:    messageTypePoll,
+		DeliveryStatus: "sent",
+		Notify:         true,
+	})
+	if err == nil {
+		_, err = r.db.ExecContext(ctx, updatePollMessage, msg.ID, pollID)
+	}
+	if err != nil {
+		if _, cleanupErr := r.db.ExecContext(ctx, deletePollOptions, pollID); cleanupErr != nil {
+			log.Printf("remove options of unposted poll %d failed: %v", pollID, cleanupErr)
+		}
+		if _, cleanupErr := r.db.ExecContext(ctx, deletePoll, pollID); cleanupErr != nil {
+			log.Printf("remove unposted poll %d failed: %v", pollID, cleanupErr)
+		}
+		return nil, fmt.Errorf("post poll message: %w", err)
+	}
+
+	poll, err := r.GetPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	msg.Poll = poll
+	return msg, nil
+}
+
+// GetPoll returns a poll with its current results.
+func (r *EventRepository) GetPoll(ctx context.Context, pollID int64) (*Poll, error) {
+	polls, err := r.loadPolls(ctx, "id", []int64{pollID})
+	if err != nil {
+		return nil, err
+	}
+	if len(polls) == 0 {
+		return nil, ErrPollNotFound
+	}
+	return &polls[0], nil
+}
+
+// loadPolls fetches the polls whose column (id or message_id) is in ids,
+// with their options and voters.
+func (r *EventRepository) loadPolls(ctx context.Context, column string, ids []int64) ([]Poll, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectPolls, column, placeholders(len(args))), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query polls: %w", err)
+	}
+	var polls []Poll
+	for rows.Next() {
+		var poll Poll
+		var messageID sql.NullInt64
+		var closedAt sql.NullTime
+		if err := rows.Scan(&poll.ID, &poll.ConversationID, &messageID, &poll.CreatedBy, &poll.Question, &poll.AllowMultiple, &closedAt, &poll.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan poll: %w", err)
+		}
+		poll.MessageID = messageID.Int64
+		if closedAt.Valid {
+			value := closedAt.Time
+			poll.ClosedAt = &value
+		}
+		poll.Options = []PollOption{}
+		polls = append(polls, poll)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate polls: %w", err)
+	}
+	rows.Close()
+	if len(polls) == 0 {
+		return nil, nil
+	}
+
+	index := make(map[int64]int, len(polls))
+	pollArgs := make([]any, 0, len(polls))
+	for i := range polls {
+		index[polls[i].ID] = i
+		pollArgs = append(pollArgs, polls[i].ID)
+	}
+	rows, err = r.db.QueryContext(ctx, fmt.Sprintf(selectPollResults, placeholders(len(pollArgs))), pollArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("query poll results: %w", err)
+	}
+	defer rows.Close()
+
+	voters := make(map[int64]map[int64]bool, len(polls))
+	for rows.Next() {
+		var pollID, optionID int64
+		var label string
+		var voterID sql.NullInt64
+		if err := rows.Scan(&pollID, &optionID, &label, &voterID); err != nil {
+			return nil, fmt.Errorf("scan poll result: %w", err)
+		}
+		poll := &polls[index[pollID]]
+		if n := len(poll.Options); n == 0 || poll.Options[n-1].ID != optionID {
+			poll.Options = append(poll.Options, PollOption{ID: optionID, Label: label, VoterIDs: []int64{}})
+		}
+		if voterID.Valid {
+			option := &poll.Options[len(poll.Options)-1]
+			option.VoterIDs = append(option.VoterIDs, voterID.Int64)
+			option.Votes++
+			if voters[pollID] == nil {
+				voters[pollID] = make(map[int64]bool)
+			}
+			voters[pollID][voterID.Int64] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate poll results: %w", err)
+	}
+	for i := range polls {
+		polls[i].TotalVoters = len(voters[polls[i].ID])
+	}
+	return polls, nil
+}
+
+// attachPolls fills in Poll on the poll messages among messages.
+func (r *EventRepository) attachPolls(ctx context.Context, messages []Message) error {
+	var ids []int64
+	index := make(map[int64]int)
+	for i := range messages {
+		if messages[i].MessageType == messageTypePoll {
+			ids = append(ids, messages[i].ID)
+			index[messages[i].ID] = i
+		}
+	}
+	polls, err := r.loadPolls(ctx, "message_id", ids)
+	if err != nil {
+		return err
+	}
+	for i := range polls {
+		if j, ok := index[polls[i].MessageID]; ok {
+			messages[j].Poll = &polls[i]
+		}
+	}
+	return nil
+}
+
+// VotePoll replaces the user's votes on a poll with optionIDs. A poll that
+// doesn't allow multiple answers takes exactly one.
+func (r *EventRepository) VotePoll(ctx context.Context, conversationID, pollID, userID int64, optionIDs []int64) (*Poll, error) {
+	poll, err := r.GetPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.ConversationID != conversationID {
+		return nil, ErrPollNotFound
+	}
+	if poll.ClosedAt != nil {
+		return nil, ErrPollClosed
+	}
+	chosen := make(map[int64]bool, len(optionIDs))
+	for _, id := range optionIDs {
+		chosen[id] = true
+	}
+	if len(chosen) == 0 || (!poll.AllowMultiple && len(chosen) > 1) {
+		return nil, fmt.Errorf("%w: pick one option", ErrInvalidPollVote)
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin vote tx: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, selectPollOptionIDs, pollID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("list poll options: %w", err)
+	}
+	valid := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, fmt.Errorf("scan poll option: %w", err)
+		}
+		valid[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("iterate poll options: %w", err)
+	}
+	rows.Close()
+	for id := range chosen {
+		if !valid[id] {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: option %d is not part of this poll", ErrInvalidPollVote, id)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, deletePollVotesForUserInPoll, pollID, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("clear poll votes: %w", err)
+	}
+	for id := range chosen {
+		if _, err := tx.ExecContext(ctx, insertPollVote, pollID, id, userID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert poll vote: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit vote: %w", err)
+	}
+	return r.GetPoll(ctx, pollID)
+}
+
+// RetractPollVote removes the user's votes from an open poll.
+func (r *EventRepository) RetractPollVote(ctx context.Context, conversationID, pollID, userID int64) (*Poll, error) {
+	poll, err := r.GetPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.ConversationID != conversationID {
+		return nil, ErrPollNotFound
+	}
+	if poll.ClosedAt != nil {
+		return nil, ErrPollClosed
+	}
+	if _, err := r.db.ExecContext(ctx, deletePollVotesForUserInPoll, pollID, userID); err != nil {
+		return nil, fmt.Errorf("retract poll vote: %w", err)
+	}
+	return r.GetPoll(ctx, pollID)
+}
+
+// ClosePoll stops a poll from taking votes. Only its creator may close it;
+// closing a closed poll is a no-op.
+func (r *EventRepository) ClosePoll(ctx context.Context, conversationID, pollID, userID int64) (*Poll, error) {
+	poll, err := r.GetPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.ConversationID != conversationID {
+		return nil, ErrPollNotFound
+	}
+	if poll.CreatedBy != userID {
+		return nil, ErrNotPollCreator
+	}
+	if _, err := r.db.ExecContext(ctx, closePoll, pollID); err != nil {
+		return nil, fmt.Errorf("close poll: %w", err)
+	}
+	return r.GetPoll(ctx, pollID)
+}
+
+// pollUpdateEvent carries a poll's current results to the chat after a vote
+// or when it closes.
+type pollUpdateEvent struct {
+	Type           string `json:"type"`
+	ConversationID int64  `json:"conversationId"`
+	Poll           Poll   `json:"poll"`
+}
+
+// BroadcastPollUpdate sends `poll:update` to everyone in the poll's chat.
+func (h *ChatHub) BroadcastPollUpdate(poll Poll) {
+	payload, err := json.Marshal(pollUpdateEvent{Type: "poll:update", ConversationID: poll.ConversationID, Poll: poll})
+	if err != nil {
+		log.Printf("marshal poll update failed: %v", err)
+		return
+	}
+	h.broadcast <- chatBroadcast{conversationID: poll.ConversationID, payload: payload}
+}
+
+// createPoll posts a poll to the conversation as a `poll` message. Members
+// vote over REST and everyone in the chat gets `poll:update` as results
+// change.
+//
+// Responses:
+//  - 201 with the poll message
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or conversation id, an empty or too long question,
+//    or fewer than 2, more than 10, blank, too long or repeated options
+//  - 403 if the caller is not a member
+//  - 409 with code `conversation_locked` if the conversation is read-only
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) createPoll(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var payload CreatePollParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	payload.Question = strings.TrimSpace(payload.Question)
+	if payload.Question == "" || utf8.RuneCountInString(payload.Question) > maxPollQuestionLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("question must be 1-%d characters", maxPollQuestionLength)})
+		return
+	}
+	if len(payload.Options) < minPollOptions || len(payload.Options) > maxPollOptions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a poll needs %d-%d options", minPollOptions, maxPollOptions)})
+		return
+	}
+	seen := make(map[string]bool, len(payload.Options))
+	for i, option := range payload.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || utf8.RuneCountInString(option) > maxPollOptionLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("options must be 1-%d characters", maxPollOptionLength)})
+			return
+		}
+		if seen[strings.ToLower(option)] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "options must be different"})
+			return
+		}
+		seen[strings.ToLower(option)] = true
+		payload.Options[i] = option
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	msg, err := h.repo.CreatePoll(ctx, conversationID, claims.UserID, payload)
+	if errors.Is(err, ErrConversationLocked) {
+		respondConversationLocked(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create poll"})
+		return
+	}
+	if err := h.repo.UpdateReadState(ctx, conversationID, claims.UserID, msg.ID); err != nil {
+		log.Printf("update read state after poll failed: %v", err)
+	}
+
+	h.hub.BroadcastMessage(*msg)
+	c.JSON(http.StatusCreated, gin.H{"message": newMessagePayload(*msg)})
+}
+
+// getPoll returns a poll's current results.
+//
+// Responses:
+//  - 200 with `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation or poll id
+//  - 403 if the caller is not a member
+//  - 404 if the poll isn't in this conversation
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) getPoll(c *gin.Context) {
+	claims, conversationID, pollID, ok := h.pollRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	poll, err := h.repo.GetPoll(ctx, pollID)
+	if err == nil && poll.ConversationID != conversationID {
+		err = ErrPollNotFound
+	}
+	if err != nil {
+		respondPollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// votePoll replaces the caller's votes with `option_ids`, which must hold
+// exactly one option unless the poll allows several.
+//
+// Responses:
+//  - 200 with the updated `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON, ids, or options not in this poll
+//  - 403 if the caller is not a member
+//  - 404 if the poll isn't in this conversation
+//  - 409 once the poll is closed
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) votePoll(c *gin.Context) {
+	claims, conversationID, pollID, ok := h.pollRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload PollVoteParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	poll, err := h.repo.VotePoll(ctx, conversationID, pollID, claims.UserID, payload.OptionIDs)
+	if err != nil {
+		respondPollError(c, err)
+		return
+	}
+
+	h.hub.BroadcastPollUpdate(*poll)
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// retractPollVote removes the caller's votes from an open poll.
+//
+// Responses:
+//  - 200 with the updated `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation or poll id
+//  - 403 if the caller is not a member
+//  - 404 if the poll isn't in this conversation
+//  - 409 once the poll is closed
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) retractPollVote(c *gin.Context) {
+	claims, conversationID, pollID, ok := h.pollRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	poll, err := h.repo.RetractPollVote(ctx, conversationID, pollID, claims.UserID)
+	if err != nil {
+		respondPollError(c, err)
+		return
+	}
+
+	h.hub.BroadcastPollUpdate(*poll)
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// closePoll stops the caller's poll from taking more votes.
+//
+// Responses:
+//  - 200 with the final `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation or poll id
+//  - 403 if the caller didn't create the poll
+//  - 404 if the poll isn't in this conversation
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) closePoll(c *gin.Context) {
+	claims, conversationID, pollID, ok := h.pollRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	poll, err := h.repo.ClosePoll(ctx, conversationID, pollID, claims.UserID)
+	if err != nil {
+		respondPollError(c, err)
+		return
+	}
+
+	h.hub.BroadcastPollUpdate(*poll)
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// pollRequest reads the session and the conversation and poll ids, answering
+// the request itself when any is missing or malformed.
+func (h *ChatHTTPHandler) pollRequest(c *gin.Context) (*sessionClaims, int64, int64, bool) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return nil, 0, 0, false
+	}
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return nil, 0, 0, false
+	}
+	pollID, err := strconv.ParseInt(c.Param("pollId"), 10, 64)
+	if err != nil || pollID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid poll id"})
+		return nil, 0, 0, false
+	}
+	return claims, conversationID, pollID, true
+}
+
+func respondPollError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPollNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "poll not found"})
+	case errors.Is(err, ErrPollClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": "poll is closed"})
+	case errors.Is(err, ErrInvalidPollVote):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNotPollCreator):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the poll's creator can close it"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update poll"})
+	}
+}
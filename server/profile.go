@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const createTableUserInterests = `
+CREATE TABLE IF NOT EXISTS user_interests (
+    user_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (user_id, tag_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+`
+
+const selectUserProfile = `
+SELECT id, name, email, phone, created_at, gender, birth_date, is_admin, verified_at IS NOT NULL
+FROM users
+WHERE id = ? AND deleted_at IS NULL;
+`
+
+const updateUserProfile = `
+UPDATE users
+SET gender = ?, birth_date = ?
+WHERE id = ? AND deleted_at IS NULL;
+`
+
+const selectUserInterests = `
+SELECT t.name
+FROM user_interests ui
+JOIN tags t ON t.id = ui.tag_id
+WHERE ui.user_id = ?
+ORDER BY t.name ASC;
+`
+
+const deleteUserInterests = `
+DELETE FROM user_interests
+WHERE user_id = ?;
+`
+
+const insertUserInterest = `
+INSERT OR IGNORE INTO user_interests (user_id, tag_id)
+VALUES (?, ?);
+`
+
+// birthDateLayout is the storage and wire format for users.birth_date.
+const birthDateLayout = "2006-01-02"
+
+// initProfiles adds the profile columns and interests table.
+func (r *EventRepository) initProfiles(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "users", "gender", "TEXT"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "users", "birth_date", "TEXT"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, createTableUserInterests); err != nil {
+		return fmt.Errorf("create user interests table: %w", err)
+	}
+	return nil
+}
+
+// GetUserProfile loads the user's profile fields and interest tags.
+func (r *EventRepository) GetUserProfile(ctx context.Context, userID int64) (*UserProfile, error) {
+	var profile UserProfile
+	var phone, gender, birthDate sql.NullString
+	if err := r.db.QueryRowContext(ctx, selectUserProfile, userID).Scan(
+		&profile.ID,
+		&profile.Name,
+		&profile.Email,
+		&phone,
+		&profile.CreatedAt,
+		&gender,
+		&birthDate,
+		&profile.IsAdmin,
+		&profile.Verified,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("fetch user profile: %w", err)
+	}
+	if phone.Valid {
+		value := phone.String
+		profile.Phone = &value
+	}
+	if gender.Valid {
+		value := gender.String
+		profile.Gender = &value
+	}
+	if birthDate.Valid {
+		value := birthDate.String
+		profile.BirthDate = &value
+	}
+
+	interests, err := r.ListUserInterests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Interests = interests
+
+	profile.HostRating, err = r.hostRatingFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Attendance, err = r.attendanceFor(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateUserProfile stores the caller's gender and birth date; nil clears a field.
+func (r *EventRepository) UpdateUserProfile(ctx context.Context, userID int64, params UpdateProfileParams) error {
+	var gender, birthDate sql.NullString
+	if params.Gender != nil && strings.TrimSpace(*params.Gender) != "" {
+		gender = sql.NullString{String: strings.TrimSpace(*params.Gender), Valid: true}
+	}
+	if params.BirthDate != nil && *params.BirthDate != "" {
+		birthDate = sql.NullString{String: *params.BirthDate, Valid: true}
+	}
+	result, err := r.db.ExecContext(ctx, updateUserProfile, gender, birthDate, userID)
+	if err != nil {
+		return fmt.Errorf("update user profile: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check profile rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ListUserInterests returns the names of the tags the user follows.
+func (r *EventRepository) ListUserInterests(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, selectUserInterests, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user interests: %w", err)
+	}
+	defer rows.Close()
+
+	interests := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan user interest: %w", err)
+		}
+		interests = append(interests, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user interests: %w", err)
+	}
+	return interests, nil
+}
+
+// SetUserInterests replaces the user's interest tags with catalog tags.
+func (r *EventRepository) SetUserInterests(ctx context.Context, userID int64, names []string) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin user interests tx: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteUserInterests, userID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("clear user interests: %w", err)
+	}
+	for _, name := range names {
+		var tagID int64
+		if err := tx.QueryRowContext(ctx, selectTagIDByName, strings.TrimSpace(name)).Scan(&tagID); err != nil {
+			tx.Rollback()
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrUnknownTag, name)
+			}
+			return fmt.Errorf("lookup tag: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertUserInterest, userID, tagID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("insert user interest: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit user interests: %w", err)
+	}
+	return nil
+}
+
+// Age returns the profile's age in whole years at now, if a birth date is set.
+func (p *UserProfile) Age(now time.Time) (int, bool) {
+	if p.BirthDate == nil {
+		return 0, false
+	}
+	born, err := time.Parse(birthDateLayout, *p.BirthDate)
+	if err != nil {
+		return 0, false
+	}
+	age := now.Year() - born.Year()
+	if now.YearDay() < born.YearDay() {
+		age--
+	}
+	return age, true
+}
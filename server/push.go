@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// pushTimeout bounds a single delivery attempt to the provider.
+const pushTimeout = 5 * time.Second
+
+// pushPreviewLength caps how much of a message body goes into a notification.
+const pushPreviewLength = 120
+
+// PushNotification is a provider-neutral mobile notification.
+type PushNotification struct {
+	Title string
+	Body  string
+	Data  map[string]any
+}
+
+// PushSender delivers notifications to device tokens. Implementations must be
+// safe for concurrent use.
+type PushSender interface {
+	Send(ctx context.Context, tokens []string, notification PushNotification) error
+}
+
+// newPushSenderFromEnv picks a provider from PUSH_PROVIDER (`expo`). Unset
+// disables push and returns nil.
+func newPushSenderFromEnv() (PushSender, error) {
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("PUSH_PROVIDER"))); provider {
+	case "":
+		return nil, nil
+	case "expo":
+		endpoint := strings.TrimSpace(os.Getenv("EXPO_PUSH_URL"))
+		if endpoint == "" {
+			endpoint = "https://exp.host/--/api/v2/push/send"
+		}
+		return &expoPushSender{
+			client:      &http.Client{Timeout: pushTimeout},
+			endpoint:    endpoint,
+			accessToken: strings.TrimSpace(os.Getenv("EXPO_ACCESS_TOKEN")),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown PUSH_PROVIDER %q", provider)
+	}
+}
+
+// expoPushSender posts to the Expo push service, which fans out to APNs/FCM
+// for the app's ExponentPushToken[...] tokens.
+type expoPushSender struct {
+	client      *http.Client
+	endpoint    string
+	accessToken string // optional; required when the Expo project enforces push security
+}
+
+func (s *expoPushSender) Send(ctx context.Context, tokens []string, notification PushNotification) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	type expoMessage struct {
+		To    string         `json:"to"`
+		Title string         `json:"title,omitempty"`
+		Body  string         `json:"body"`
+		Data  map[string]any `json:"data,omitempty"`
+		Sound string         `json:"sound,omitempty"`
+	}
+	messages := make([]expoMessage, 0, len(tokens))
+	for _, token := range tokens {
+		messages = append(messages, expoMessage{
+			To:    token,
+			Title: notification.Title,
+			Body:  notification.Body,
+			Data:  notification.Data,
+			Sound: "default",
+		})
+	}
+
+	payload, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("marshal expo push: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build expo push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.accessToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("expo push request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expo push status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+const createTablePushTokens = `
+CREATE TABLE IF NOT EXISTS push_tokens (
+    token TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    platform TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexPushTokensUser = `
+CREATE INDEX IF NOT EXISTS idx_push_tokens_user
+ON push_tokens(user_id);
+`
+
+// A token moves to whoever registered it last, e.g. after switching accounts
+// on the same device.
+const upsertPushToken = `
+INSERT INTO push_tokens (token, user_id, platform)
+VALUES (?, ?, ?)
+ON CONFLICT(token) DO UPDATE SET
+    user_id = excluded.user_id,
+    platform = excluded.platform,
+    created_at = CURRENT_TIMESTAMP;
+`
+
+const deletePushToken = `
+DELETE FROM push_tokens
+WHERE token = ? AND user_id = ?;
+`
+
+const deletePushTokensForUser = `
+DELETE FROM push_tokens
+WHERE user_id = ?;
+`
+
+// selectPushTokensForConversation returns tokens of members other than the
+// sender who have not muted the conversation or turned message pushes off.
+// Members mentioned in the message are left out; they get their own
+// notification.
+var selectPushTokensForConversation = `
+SELECT pt.token
+FROM conversation_members cm
+JOIN push_tokens pt ON pt.user_id = cm.user_id
+LEFT JOIN conversation_settings cs ON cs.conversation_id = cm.conversation_id AND cs.user_id = cm.user_id
+LEFT JOIN user_notification_settings ns ON ns.user_id = cm.user_id
+WHERE cm.conversation_id = ?
+  AND cm.user_id <> ?
+  AND (cs.muted_until IS NULL OR cs.muted_until <= ?)
+  AND ` + notificationEnabledSQL(notifyMessages, notificationPush) + `
+  AND NOT EXISTS (
+      SELECT 1 FROM message_mentions mm
+      WHERE mm.message_id = ? AND mm.user_id = cm.user_id
+  );
+`
+
+// Mentions notify regardless of mute, but not users who turned message
+// pushes off.
+var selectPushTokensForMentions = `
+SELECT pt.token
+FROM message_mentions mm
+JOIN push_tokens pt ON pt.user_id = mm.user_id
+LEFT JOIN user_notification_settings ns ON ns.user_id = mm.user_id
+WHERE mm.message_id = ?
+  AND ` + notificationEnabledSQL(notifyMessages, notificationPush) + `;
+`
+
+// selectPushTokensForUser is completed with the condition for the category
+// being pushed.
+const selectPushTokensForUser = `
+SELECT pt.token
+FROM push_tokens pt
+LEFT JOIN user_notification_settings ns ON ns.user_id = pt.user_id
+WHERE pt.user_id = ?
+  AND %s;
+`
+
+func (r *EventRepository) initPushTokens(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTablePushTokens); err != nil {
+		return fmt.Errorf("create push tokens table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexPushTokensUser); err != nil {
+		return fmt.Errorf("create push tokens index: %w", err)
+	}
+	return nil
+}
+
+// RegisterPushToken associates a device token with the user.
+func (r *EventRepository) RegisterPushToken(ctx context.Context, userID int64, token, platform string) error {
+	var nullablePlatform any
+	if platform != "" {
+		nullablePlatform = platform
+	}
+	if _, err := r.db.ExecContext(ctx, upsertPushToken, token, userID, nullablePlatform); err != nil {
+		return fmt.Errorf("register push token: %w", err)
+	}
+	return nil
+}
+
+// RemovePushToken forgets one of the user's device tokens, e.g. on sign-out.
+func (r *EventRepository) RemovePushToken(ctx context.Context, userID int64, token string) error {
+	if _, err := r.db.ExecContext(ctx, deletePushToken, token, userID); err != nil {
+		return fmt.Errorf("remove push token: %w", err)
+	}
+	return nil
+}
+
+// ListPushTokensForConversation returns the device tokens that should hear
+// about a new message, skipping the sender, members who muted the chat, and
+// members the message mentions.
+func (r *EventRepository) ListPushTokensForConversation(ctx context.Context, msg Message, now time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, selectPushTokensForConversation, msg.ConversationID, msg.SenderID, sqliteTime(now), msg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list push tokens: %w", err)
+	}
+	return scanPushTokens(rows)
+}
+
+// ListPushTokensForMentions returns the device tokens of users mentioned in
+// the message, muted or not.
+func (r *EventRepository) ListPushTokensForMentions(ctx context.Context, messageID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, selectPushTokensForMentions, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("list mention push tokens: %w", err)
+	}
+	return scanPushTokens(rows)
+}
+
+// ListPushTokensForUser returns every device token registered to a user, or
+// none if they turned pushes for category off.
+func (r *EventRepository) ListPushTokensForUser(ctx context.Context, userID int64, category notificationCategory) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectPushTokensForUser, notificationEnabledSQL(category, notificationPush)), userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user push tokens: %w", err)
+	}
+	return scanPushTokens(rows)
+}
+
+func scanPushTokens(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var tokens []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, fmt.Errorf("scan push token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate push tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// sendMessagePush notifies the conversation's other members' devices about a
+// user message; mentioned members are notified even if they muted the chat.
+// The outbox dispatcher calls it and retries on error.
+func (h *ChatHub) sendMessagePush(ctx context.Context, msg Message) error {
+	if h.pusher == nil {
+		return nil
+	}
+
+	tokens, err := h.repo.ListPushTokensForConversation(ctx, msg, time.Now())
+	if err != nil {
+		return fmt.Errorf("push recipients lookup: %w", err)
+	}
+	mentionTokens, err := h.repo.ListPushTokensForMentions(ctx, msg.ID)
+	if err != nil {
+		return fmt.Errorf("push mention recipients lookup: %w", err)
+	}
+	if len(tokens) == 0 && len(mentionTokens) == 0 {
+		return nil
+	}
+
+	names, err := h.repo.GetUserNames(ctx, []int64{msg.SenderID})
+	if err != nil {
+		return fmt.Errorf("push sender lookup: %w", err)
+	}
+
+	body := msg.Body
+	if runes := []rune(body); len(runes) > pushPreviewLength {
+		body = string(runes[:pushPreviewLength]) + "…"
+	}
+	notification := PushNotification{
+		Title: names[msg.SenderID],
+		Body:  body,
+		Data: map[string]any{
+			"type":           "message:new",
+			"conversationId": msg.ConversationID,
+			"messageId":      msg.ID,
+		},
+	}
+	if len(tokens) > 0 {
+		if err := h.pusher.Send(ctx, tokens, notification); err != nil {
+			return fmt.Errorf("push delivery: %w", err)
+		}
+	}
+
+	if len(mentionTokens) > 0 {
+		notification.Title = names[msg.SenderID] + " mentioned you"
+		notification.Data = map[string]any{
+			"type":           "message:mention",
+			"conversationId": msg.ConversationID,
+			"messageId":      msg.ID,
+		}
+		if err := h.pusher.Send(ctx, mentionTokens, notification); err != nil {
+			return fmt.Errorf("push mention delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// pushToDevices sends a notification to all of one user's devices in the
+// background, unless they turned pushes for category off; failures are only
+// logged.
+func (h *ChatHub) pushToDevices(userID int64, category notificationCategory, notification PushNotification) {
+	if h.pusher == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+		defer cancel()
+
+		tokens, err := h.repo.ListPushTokensForUser(ctx, userID, category)
+		if err != nil {
+			log.Printf("push recipients lookup failed: %v", err)
+			return
+		}
+		if len(tokens) == 0 {
+			return
+		}
+		if err := h.pusher.Send(ctx, tokens, notification); err != nil {
+			log.Printf("push delivery failed: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Recommendation weights. Tag overlap dominates; proximity breaks ties
+// between equally relevant events.
+const (
+	recommendTagWeight       = 1.0
+	recommendProximityWeight = 0.5
+	// recommendProximityScale is the distance (km) at which proximity counts half.
+	recommendProximityScale = 5.0
+	defaultRecommendLimit   = 20
+)
+
+// Coordinates is a point on the globe in decimal degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// RecommendedEvent is an event plus why it was ranked where it was.
+type RecommendedEvent struct {
+	Event
+	Score       float64  `json:"score"`
+	MatchedTags []string `json:"matched_tags"`
+	DistanceKm  *float64 `json:"distance_km,omitempty"`
+}
+
+// Recommender ranks upcoming events for a user on top of EventRepository.
+type Recommender struct {
+	repo Store
+}
+
+func NewRecommender(repo Store) *Recommender {
+	return &Recommender{repo: repo}
+}
+
+// Recommend returns upcoming events the user is eligible for, excluding ones
+// they host, ordered by tag overlap with their interests and, when origin is
+// known, proximity.
+func (r *Recommender) Recommend(ctx context.Context, userID int64, origin *Coordinates, limit int) ([]RecommendedEvent, error) {
+	if limit <= 0 {
+		limit = defaultRecommendLimit
+	}
+
+	profile, err := r.repo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.repo.List(ctx, EventListOptions{ViewerID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	interests := make(map[string]struct{}, len(profile.Interests))
+	for _, name := range profile.Interests {
+		interests[strings.ToLower(name)] = struct{}{}
+	}
+
+	now := time.Now()
+	ranked := make([]RecommendedEvent, 0, len(events))
+	for _, evt := range events {
+		if evt.UserID == userID || !isEligible(profile, evt, now) {
+			continue
+		}
+
+		candidate := RecommendedEvent{Event: evt, MatchedTags: []string{}}
+		for _, tag := range evt.Tags {
+			if _, ok := interests[strings.ToLower(tag)]; ok {
+				candidate.MatchedTags = append(candidate.MatchedTags, tag)
+			}
+		}
+		if len(interests) > 0 {
+			candidate.Score += recommendTagWeight * float64(len(candidate.MatchedTags)) / float64(len(interests))
+		}
+
+		if origin != nil && evt.Latitude != nil && evt.Longitude != nil {
+			distance := haversineKm(*origin, Coordinates{Latitude: *evt.Latitude, Longitude: *evt.Longitude})
+			candidate.DistanceKm = &distance
+			candidate.Score += recommendProximityWeight * recommendProximityScale / (recommendProximityScale + distance)
+		}
+
+		ranked = append(ranked, candidate)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, nil
+}
+
+// isEligible applies the event's gender and age filters. Profile fields that
+// are unset don't exclude the user.
+func isEligible(profile *UserProfile, evt Event, now time.Time) bool {
+	return eligibilityProblem(profile, evt, now) == ""
+}
+
+// eligibilityProblem names the first filter the profile fails, "gender" or
+// "age", or returns "" when it passes them all.
+func eligibilityProblem(profile *UserProfile, evt Event, now time.Time) string {
+	if profile.Gender != nil && !strings.EqualFold(evt.Gender, "Any") && !strings.EqualFold(evt.Gender, *profile.Gender) {
+		return "gender"
+	}
+	if age, ok := profile.Age(now); ok && (age < evt.MinAge || age > evt.MaxAge) {
+		return "age"
+	}
+	return ""
+}
+
+// haversineKm returns the great-circle distance between two points.
+func haversineKm(a, b Coordinates) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(b.Latitude - a.Latitude)
+	dLng := toRad(b.Longitude - a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Latitude))*math.Cos(toRad(b.Latitude))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -3,9 +3,15 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
+	"sync"
+	"time"
+
+	"who-else-is-free-server/internal/maintenance"
 )
 
 var ErrInvalidCredentials = errors.New("invalid credentials")
@@ -17,18 +23,56 @@ var ErrJoinRequestNotFound = errors.New("join request not found")
 var ErrNotEventHost = errors.New("user is not the event host")
 var ErrCannotRemoveHost = errors.New("event host cannot be removed from the conversation")
 var ErrNotConversationMember = errors.New("user is not a conversation member")
+var ErrUserNotFound = errors.New("user not found")
+var ErrEventFull = errors.New("event is at capacity")
+var ErrIneligible = errors.New("requester does not meet the event's requirements")
+
+var ErrMessageNotFound = errors.New("message not found")
+var ErrNotConversationOwner = errors.New("user is not the conversation owner")
+var ErrEventConversation = errors.New("event conversations are managed through join requests")
+
+// Event chat roles. The host is the owner; co-hosts share moderation.
+const (
+	roleOwner  = "owner"
+	roleCoHost = "co_host"
+	roleMember = "member"
+)
+
+// IneligibleError rejects a join request on a strict event; it matches
+// ErrIneligible with errors.Is.
+type IneligibleError struct {
+	Reason string // "gender" or "age"
+}
+
+func (e *IneligibleError) Error() string {
+	return fmt.Sprintf("%s (%s)", ErrIneligible.Error(), e.Reason)
+}
+
+func (e *IneligibleError) Is(target error) bool {
+	return target == ErrIneligible
+}
 
 type rowQuery interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
+type rowsQuery interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 const createTableUsers = `
 CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     name TEXT NOT NULL,
     email TEXT NOT NULL UNIQUE,
     password TEXT NOT NULL,
-    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME
 );
 `
 
@@ -44,7 +88,15 @@ CREATE TABLE IF NOT EXISTS events (
     min_age INTEGER NOT NULL,
     max_age INTEGER NOT NULL,
     date_label TEXT NOT NULL CHECK(date_label IN ('Today', 'Tmrw')),
+    capacity INTEGER,
+    starts_at DATETIME,
+    status TEXT NOT NULL DEFAULT 'active',
+    latitude REAL,
+    longitude REAL,
+    place_name TEXT,
+    strict_eligibility INTEGER NOT NULL DEFAULT 0,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
     FOREIGN KEY (user_id) REFERENCES users(id),
     CHECK (min_age >= 0),
     CHECK (max_age >= min_age)
@@ -58,6 +110,7 @@ CREATE TABLE IF NOT EXISTS conversations (
     title TEXT,
     created_by INTEGER NOT NULL,
     event_id INTEGER,
+    archived_at DATETIME,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (created_by) REFERENCES users(id),
     FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE
@@ -84,6 +137,9 @@ CREATE TABLE IF NOT EXISTS messages (
     body TEXT NOT NULL,
     attachment_url TEXT,
     delivery_status TEXT NOT NULL DEFAULT 'sent',
+    kind TEXT NOT NULL DEFAULT 'user',
+    message_type TEXT NOT NULL DEFAULT 'text',
+    metadata TEXT,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
     FOREIGN KEY (sender_id) REFERENCES users(id)
@@ -108,13 +164,21 @@ CREATE TABLE IF NOT EXISTS conversation_read_state (
 `
 
 const insertEvent = `
-INSERT INTO events (user_id, title, location, time, description, gender, min_age, max_age, date_label)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO events (user_id, title, location, time, description, gender, min_age, max_age, date_label, capacity, starts_at, latitude, longitude, place_name, strict_eligibility, visibility, hide_exact_location, area, min_attendees)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 `
 
 const updateEvent = `
 UPDATE events
-SET title = ?, location = ?, time = ?, description = ?, gender = ?, min_age = ?, max_age = ?, date_label = ?
+SET title = ?, location = ?, time = ?, description = ?, gender = ?, min_age = ?, max_age = ?, date_label = ?, capacity = ?, starts_at = ?, status = 'active',
+    latitude = ?, longitude = ?, place_name = ?, strict_eligibility = ?, visibility = COALESCE(NULLIF(?, ''), visibility),
+    hide_exact_location = COALESCE(?, hide_exact_location), area = COALESCE(?, area), min_attendees = ?
+WHERE id = ? AND user_id = ? AND deleted_at IS NULL;
+`
+
+const updateEventHost = `
+UPDATE events
+SET user_id = ?
 WHERE id = ? AND user_id = ?;
 `
 
@@ -134,26 +198,40 @@ VALUES (?, ?, ?);
 `
 
 const insertMessage = `
-INSERT INTO messages (conversation_id, sender_id, body, attachment_url, delivery_status)
-VALUES (?, ?, ?, ?, ?)
-RETURNING id, conversation_id, sender_id, body, attachment_url, delivery_status, created_at;
+INSERT INTO messages (conversation_id, sender_id, body, attachment_url, delivery_status, kind, message_type, metadata, hidden)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, conversation_id, sender_id, body, attachment_url, delivery_status, kind, message_type, metadata, created_at;
 `
 
 const upsertReadState = `
 INSERT INTO conversation_read_state (conversation_id, user_id, last_read_message_id, updated_at)
 VALUES (?, ?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(conversation_id, user_id)
-DO UPDATE SET last_read_message_id = excluded.last_read_message_id, updated_at = CURRENT_TIMESTAMP;
+DO UPDATE SET last_read_message_id = MAX(COALESCE(last_read_message_id, 0), excluded.last_read_message_id), updated_at = CURRENT_TIMESTAMP;
 `
 
+const checkMessageInConversation = `
+SELECT 1
+FROM messages
+WHERE id = ? AND conversation_id = ?
+LIMIT 1;
+`
+
+// selectConversationsForUser has no ORDER BY so ListConversations can append
+// the archive and cursor filters first. The last column is
+// conversationActivity, read into the page cursor.
 const selectConversationsForUser = `
-SELECT c.id, c.title, c.created_by, c.created_at, c.event_id
+SELECT c.id, c.title, c.created_by, c.created_at, c.event_id, c.archived_at, c.slow_mode_seconds, c.locked, COALESCE(c.last_message_at, c.created_at)
 FROM conversations c
 JOIN conversation_members cm ON cm.conversation_id = c.id
+LEFT JOIN conversation_settings cs ON cs.conversation_id = c.id AND cs.user_id = cm.user_id
 WHERE cm.user_id = ?
-ORDER BY c.created_at DESC;
 `
 
+// conversationArchivedClause is true when the chat is archived for everyone
+// (stale event chat) or by the viewer.
+const conversationArchivedClause = `(c.archived_at IS NOT NULL OR cs.archived_at IS NOT NULL)`
+
 const selectMembersForConversation = `
 SELECT user_id
 FROM conversation_members
@@ -161,26 +239,50 @@ WHERE conversation_id = ?;
 `
 
 const selectParticipantsForConversation = `
-SELECT cm.user_id, u.name
+SELECT cm.user_id, u.name, u.verified_at IS NOT NULL
+FROM conversation_members cm
+JOIN users u ON u.id = cm.user_id
+WHERE cm.conversation_id = ?
+ORDER BY cm.joined_at ASC;
+`
+
+const selectMemberDetailsForConversation = `
+SELECT cm.user_id, u.name, cm.role, cm.joined_at
 FROM conversation_members cm
 JOIN users u ON u.id = cm.user_id
 WHERE cm.conversation_id = ?
 ORDER BY cm.joined_at ASC;
 `
 
+// selectMessagesForConversation leaves out hidden messages and those of
+// shadow-banned members, unless the viewer sent them.
 const selectMessagesForConversation = `
-SELECT id, conversation_id, sender_id, body, attachment_url, delivery_status, created_at
+SELECT id, conversation_id, sender_id, body, attachment_url, delivery_status, kind, message_type, metadata, created_at
 FROM messages
 WHERE conversation_id = ?
+  AND (sender_id = ? OR (hidden = 0 AND sender_id NOT IN (
+      SELECT user_id FROM conversation_shadow_bans WHERE conversation_id = messages.conversation_id
+  )))
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
 `
 
+const selectMemberReadStatesForConversation = `
+SELECT cm.user_id, u.name, cm.role, cm.joined_at, rs.last_read_message_id
+FROM conversation_members cm
+JOIN users u ON u.id = cm.user_id
+LEFT JOIN conversation_read_state rs ON rs.conversation_id = cm.conversation_id AND rs.user_id = cm.user_id
+WHERE cm.conversation_id = ?
+ORDER BY cm.joined_at ASC;
+`
+
 const selectLatestMessageForConversation = `
-SELECT id, conversation_id, sender_id, body, attachment_url, delivery_status, created_at
-FROM messages
-WHERE conversation_id = ?
-ORDER BY created_at DESC
+SELECT m.id, m.sender_id, m.body, m.attachment_url, m.kind, m.created_at, COALESCE(u.name, ''), u.deleted_at IS NOT NULL
+FROM messages m
+LEFT JOIN users u ON u.id = m.sender_id
+WHERE m.conversation_id = ? AND m.hidden = 0
+  AND m.sender_id NOT IN (SELECT user_id FROM conversation_shadow_bans WHERE conversation_id = m.conversation_id)
+ORDER BY m.created_at DESC, m.id DESC
 LIMIT 1;
 `
 
@@ -191,92 +293,281 @@ WHERE conversation_id = ? AND user_id = ?
 LIMIT 1;
 `
 
+// selectMemberCountsForEvents expects the event ID placeholders to be filled in.
+const selectMemberCountsForEvents = `
+SELECT c.event_id, COUNT(cm.user_id)
+FROM conversations c
+JOIN conversation_members cm ON cm.conversation_id = c.id
+WHERE c.event_id IN (%s)
+GROUP BY c.event_id;
+`
+
+// selectPendingRequestCountsForEvents expects the event ID placeholders to be
+// filled in.
+const selectPendingRequestCountsForEvents = `
+SELECT event_id, COUNT(*)
+FROM conversation_join_requests
+WHERE status = 'pending' AND event_id IN (%s)
+GROUP BY event_id;
+`
+
+// selectEvents is completed with filters and ordering by List.
 const selectEvents = `
-SELECT e.id, e.user_id, e.title, e.location, e.time, e.description, e.gender, e.min_age, e.max_age, e.date_label, e.created_at, u.name AS host_name
+SELECT e.id, e.user_id, e.title, e.location, e.time, e.description, e.gender, e.min_age, e.max_age, e.date_label, e.created_at, u.name AS host_name, e.capacity, e.starts_at, e.status, e.latitude, e.longitude, e.place_name, e.strict_eligibility, u.verified_at IS NOT NULL AS host_verified, e.visibility, e.hide_exact_location, e.area, e.min_attendees
 FROM events e
 JOIN users u ON u.id = e.user_id
-ORDER BY e.created_at DESC;
+WHERE u.deleted_at IS NULL AND e.deleted_at IS NULL
+`
+
+const selectEventsMissingStart = `
+SELECT id, time, date_label, created_at
+FROM events
+WHERE starts_at IS NULL;
+`
+
+const updateEventStart = `
+UPDATE events
+SET starts_at = ?
+WHERE id = ?;
+`
+
+const markPastEvents = `
+UPDATE events
+SET status = 'past'
+WHERE status = 'active' AND starts_at IS NOT NULL AND starts_at <= ?
+RETURNING id;
 `
 
 const selectEventByID = `
-SELECT e.id, e.user_id, e.title, e.location, e.time, e.description, e.gender, e.min_age, e.max_age, e.date_label, e.created_at, u.name AS host_name
+SELECT e.id, e.user_id, e.title, e.location, e.time, e.description, e.gender, e.min_age, e.max_age, e.date_label, e.created_at, u.name AS host_name, e.capacity, e.starts_at, e.status, e.latitude, e.longitude, e.place_name, e.strict_eligibility, u.verified_at IS NOT NULL AS host_verified, e.visibility, e.hide_exact_location, e.area, e.min_attendees
 FROM events e
 JOIN users u ON u.id = e.user_id
-WHERE e.id = ?
+WHERE e.id = ? AND e.deleted_at IS NULL
 LIMIT 1;
 `
 
-const countEvents = `
-SELECT COUNT(1)
-FROM events;
+const selectBookmarkedEvents = `
+SELECT e.id, e.user_id, e.title, e.location, e.time, e.description, e.gender, e.min_age, e.max_age, e.date_label, e.created_at, u.name AS host_name, e.capacity, e.starts_at, e.status, e.latitude, e.longitude, e.place_name, e.strict_eligibility, u.verified_at IS NOT NULL AS host_verified, e.visibility, e.hide_exact_location, e.area, e.min_attendees
+FROM event_bookmarks b
+JOIN events e ON e.id = b.event_id
+JOIN users u ON u.id = e.user_id
+WHERE b.user_id = ? AND u.deleted_at IS NULL AND e.deleted_at IS NULL
+ORDER BY b.created_at DESC;
 `
 
-const countUsers = `
-SELECT COUNT(1)
-FROM users;
+const selectBookmarkedEventIDs = `
+SELECT event_id
+FROM event_bookmarks
+WHERE user_id = ?;
+`
+
+const createTableEventBookmarks = `
+CREATE TABLE IF NOT EXISTS event_bookmarks (
+    user_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, event_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+`
+
+const insertEventBookmark = `
+INSERT OR IGNORE INTO event_bookmarks (user_id, event_id)
+VALUES (?, ?);
 `
 
-const countConversations = `
+const deleteEventBookmark = `
+DELETE FROM event_bookmarks
+WHERE user_id = ? AND event_id = ?;
+`
+
+const countUsers = `
 SELECT COUNT(1)
-FROM conversations;
+FROM users;
 `
 
 const selectConversationByEventID = `
-SELECT id, title, created_by, created_at, event_id
+SELECT id, title, created_by, created_at, event_id, archived_at, slow_mode_seconds, locked
 FROM conversations
 WHERE event_id = ?
 LIMIT 1;
 `
 
-const selectConversationByTitle = `
-SELECT id
-FROM conversations
-WHERE title = ?
+// selectDirectConversation finds a non-event conversation whose only two
+// members are the given users.
+const selectDirectConversation = `
+SELECT c.id, c.title, c.created_by, c.created_at, c.event_id, c.archived_at, c.slow_mode_seconds, c.locked
+FROM conversations c
+WHERE c.event_id IS NULL
+  AND (SELECT COUNT(1) FROM conversation_members cm WHERE cm.conversation_id = c.id) = 2
+  AND EXISTS (SELECT 1 FROM conversation_members cm WHERE cm.conversation_id = c.id AND cm.user_id = ?)
+  AND EXISTS (SELECT 1 FROM conversation_members cm WHERE cm.conversation_id = c.id AND cm.user_id = ?)
+ORDER BY c.id ASC
 LIMIT 1;
 `
 
+const selectConversationByID = `
+SELECT id, title, created_by, created_at, event_id, archived_at, slow_mode_seconds, locked
+FROM conversations
+WHERE id = ?;
+`
+
+const selectConversationMemberRole = `
+SELECT role
+FROM conversation_members
+WHERE conversation_id = ? AND user_id = ?;
+`
+
+const updateConversationMemberRole = `
+UPDATE conversation_members
+SET role = ?
+WHERE conversation_id = ? AND user_id = ?;
+`
+
+const updateConversationTitle = `
+UPDATE conversations
+SET title = ?
+WHERE id = ?;
+`
+
+const updateEventConversationTitle = `
+UPDATE conversations
+SET title = ?
+WHERE event_id = ?;
+`
+
+// selectUserNames is completed with an IN list by GetUserNames.
+const selectUserNames = `
+SELECT id, name
+FROM users
+WHERE id IN (%s);
+`
+
+// joinRequestStatusCheck is compared against the stored DDL so older databases
+// get their join request table rebuilt when new statuses are introduced.
+const joinRequestStatusCheck = `CHECK(status IN ('pending','approved','denied','cancelled','waitlisted'))`
+
 const createTableConversationJoinRequests = `
 CREATE TABLE IF NOT EXISTS conversation_join_requests (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     event_id INTEGER NOT NULL,
     user_id INTEGER NOT NULL,
-    status TEXT NOT NULL CHECK(status IN ('pending','approved','denied')) DEFAULT 'pending',
+    status TEXT NOT NULL ` + joinRequestStatusCheck + ` DEFAULT 'pending',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     decided_at DATETIME,
     decided_by INTEGER,
+    ineligible_reason TEXT,
+    note TEXT,
     FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users(id),
     FOREIGN KEY (decided_by) REFERENCES users(id)
 );
 `
 
-const selectAllUsers = `
-SELECT id, name
-FROM users;
-`
-
 const selectUserByEmail = `
 SELECT id, name, email, password, created_at
 FROM users
-WHERE email = ?;
+WHERE email = ? AND deleted_at IS NULL;
+`
+
+const selectUserDeletedAt = `
+SELECT deleted_at
+FROM users
+WHERE id = ?;
+`
+
+const selectConversationIDsForUser = `
+SELECT conversation_id
+FROM conversation_members
+WHERE user_id = ?;
+`
+
+const cancelPendingJoinRequestsForUser = `
+UPDATE conversation_join_requests
+SET status = 'cancelled', decided_at = CURRENT_TIMESTAMP, decided_by = ?
+WHERE user_id = ? AND status IN ('pending', 'waitlisted');
+`
+
+const deleteConversationMembershipsForUser = `
+DELETE FROM conversation_members
+WHERE user_id = ?;
+`
+
+const deleteReadStateForUser = `
+DELETE FROM conversation_read_state
+WHERE user_id = ?;
+`
+
+const deleteBookmarksForUser = `
+DELETE FROM event_bookmarks
+WHERE user_id = ?;
+`
+
+const deleteInterestsForUser = `
+DELETE FROM user_interests
+WHERE user_id = ?;
+`
+
+// anonymizeUser scrubs identifying fields; messages keep their sender_id so
+// history stays intact but now resolves to "Deleted user".
+const anonymizeUser = `
+UPDATE users
+SET name = 'Deleted user', email = ?, password = '', phone = NULL, gender = NULL, birth_date = NULL, deleted_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
 `
 
 const selectPendingJoinRequest = `
-SELECT id, event_id, user_id, status, created_at, decided_at, decided_by
+SELECT id, event_id, user_id, status, created_at, decided_at, decided_by, ineligible_reason, note
 FROM conversation_join_requests
 WHERE event_id = ? AND user_id = ? AND status = 'pending'
 LIMIT 1;
 `
 
 const selectJoinRequestByID = `
-SELECT id, event_id, user_id, status, created_at, decided_at, decided_by
+SELECT id, event_id, user_id, status, created_at, decided_at, decided_by, ineligible_reason, note
 FROM conversation_join_requests
 WHERE id = ?;
 `
 
+const selectOpenJoinRequest = `
+SELECT id, event_id, user_id, status, created_at, decided_at, decided_by, ineligible_reason, note
+FROM conversation_join_requests
+WHERE event_id = ? AND user_id = ? AND status IN ('pending', 'waitlisted')
+LIMIT 1;
+`
+
+const selectWaitlistedJoinRequest = `
+SELECT id, event_id, user_id, status, created_at, decided_at, decided_by, ineligible_reason, note
+FROM conversation_join_requests
+WHERE event_id = ? AND user_id = ? AND status = 'waitlisted'
+LIMIT 1;
+`
+
+const selectNextWaitlistedJoinRequest = `
+SELECT id, event_id, user_id, status, created_at, decided_at, decided_by, ineligible_reason, note
+FROM conversation_join_requests
+WHERE event_id = ? AND status = 'waitlisted'
+ORDER BY created_at ASC, id ASC
+LIMIT 1;
+`
+
+const selectJoinRequestsForEvent = `
+SELECT id, event_id, user_id, status, created_at, decided_at, decided_by, ineligible_reason, note
+FROM conversation_join_requests
+WHERE event_id = ? AND status = ?
+ORDER BY created_at ASC, id ASC;
+`
+
+const countConversationMembers = `
+SELECT COUNT(1)
+FROM conversation_members
+WHERE conversation_id = ?;
+`
+
 const insertJoinRequest = `
-INSERT INTO conversation_join_requests (event_id, user_id, status)
-VALUES (?, ?, 'pending');
+INSERT INTO conversation_join_requests (event_id, user_id, status, ineligible_reason, note)
+VALUES (?, ?, ?, ?, ?);
 `
 
 const updateJoinRequestStatus = `
@@ -297,10 +588,18 @@ WHERE conversation_id = ? AND user_id = ?;
 
 type EventRepository struct {
 	db *sql.DB
+	// outboxReady wakes the outbox dispatcher after a commit writes rows.
+	outboxReady chan struct{}
+	// queueLinkPreviews is set when the outbox dispatcher has a link
+	// previewer, so messages with links queue a preview fetch.
+	queueLinkPreviews bool
+	// stmts caches prepared hot queries by their SQL text.
+	stmtMu sync.Mutex
+	stmts  map[string]*sql.Stmt
 }
 
 func NewEventRepository(db *sql.DB) *EventRepository {
-	return &EventRepository{db: db}
+	return &EventRepository{db: db, outboxReady: make(chan struct{}, 1), stmts: make(map[string]*sql.Stmt)}
 }
 
 func (r *EventRepository) Init(ctx context.Context) error {
@@ -311,6 +610,9 @@ func (r *EventRepository) Init(ctx context.Context) error {
 	if _, err := r.db.ExecContext(ctx, createTableEvents); err != nil {
 		return fmt.Errorf("create events table: %w", err)
 	}
+	if err := r.ensureEventsUserIDColumn(ctx); err != nil {
+		return err
+	}
 	if _, err := r.db.ExecContext(ctx, createTableConversations); err != nil {
 		return fmt.Errorf("create conversations table: %w", err)
 	}
@@ -332,687 +634,2268 @@ func (r *EventRepository) Init(ctx context.Context) error {
 	if _, err := r.db.ExecContext(ctx, createTableConversationJoinRequests); err != nil {
 		return fmt.Errorf("create conversation join requests table: %w", err)
 	}
-	return nil
-}
-
-func (r *EventRepository) ensureEventsUserIDColumn(ctx context.Context) error {
-	rows, err := r.db.QueryContext(ctx, `PRAGMA table_info(events);`)
-	if err != nil {
-		return fmt.Errorf("inspect events table: %w", err)
+	if _, err := r.db.ExecContext(ctx, createTableEventBookmarks); err != nil {
+		return fmt.Errorf("create event bookmarks table: %w", err)
 	}
-	defer rows.Close()
-
-	hasUserID := false
-	for rows.Next() {
-		var (
-			cid        int
-			name       string
-			colType    string
-			notNull    int
-			defaultVal sql.NullString
-			pk         int
-		)
-		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
-			return fmt.Errorf("scan events schema: %w", err)
-		}
-		_ = cid
-		_ = colType
-		_ = notNull
-		_ = defaultVal
-		_ = pk
-		if name == "user_id" {
-			hasUserID = true
-			break
-		}
+	if err := r.initTags(ctx); err != nil {
+		return err
 	}
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate events schema: %w", err)
+	if err := r.initProfiles(ctx); err != nil {
+		return err
 	}
-	if hasUserID {
-		return nil
+	if err := r.initAvailability(ctx); err != nil {
+		return err
 	}
-
-	if _, err := r.db.ExecContext(ctx, `ALTER TABLE events ADD COLUMN user_id INTEGER NOT NULL DEFAULT 1 REFERENCES users(id);`); err != nil {
-		return fmt.Errorf("add user_id column: %w", err)
+	if err := r.initConnections(ctx); err != nil {
+		return err
 	}
-
-	var fallbackUserID int64
-	if err := r.db.QueryRowContext(ctx, `SELECT id FROM users ORDER BY id ASC LIMIT 1`).Scan(&fallbackUserID); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			// Users will be seeded shortly; existing rows carry the default until then.
-			return nil
-		}
-		return fmt.Errorf("lookup fallback user: %w", err)
+	if err := r.initConversationSettings(ctx); err != nil {
+		return err
 	}
-
-	if _, err := r.db.ExecContext(ctx, `UPDATE events SET user_id = ? WHERE user_id = 1;`, fallbackUserID); err != nil {
-		return fmt.Errorf("backfill event owners: %w", err)
+	if err := r.initPushTokens(ctx); err != nil {
+		return err
 	}
-
-	return nil
-}
-
-func (r *EventRepository) ensureConversationEventColumn(ctx context.Context) error {
-	rows, err := r.db.QueryContext(ctx, `PRAGMA table_info(conversations);`)
-	if err != nil {
-		return fmt.Errorf("inspect conversations table: %w", err)
+	if err := r.initMentions(ctx); err != nil {
+		return err
 	}
-	defer rows.Close()
-
-	hasEventID := false
-	for rows.Next() {
-		var (
-			cid        int
-			name       string
-			colType    string
-			notNull    int
-			defaultVal sql.NullString
-			pk         int
-		)
-		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
-			return fmt.Errorf("scan conversations schema: %w", err)
-		}
-		_ = cid
-		_ = colType
-		_ = notNull
-		_ = defaultVal
-		_ = pk
-		if name == "event_id" {
-			hasEventID = true
-			break
-		}
+	if err := r.initEventBans(ctx); err != nil {
+		return err
 	}
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate conversations schema: %w", err)
+	if err := r.initEventReminders(ctx); err != nil {
+		return err
 	}
-	if hasEventID {
-		return nil
+	if err := r.initJobs(ctx); err != nil {
+		return err
 	}
-
-	if _, err := r.db.ExecContext(ctx, `ALTER TABLE conversations ADD COLUMN event_id INTEGER REFERENCES events(id) ON DELETE CASCADE;`); err != nil {
-		return fmt.Errorf("add conversation event_id column: %w", err)
+	if err := r.initOutbox(ctx); err != nil {
+		return err
 	}
-	return nil
-}
-
-func (r *EventRepository) Create(ctx context.Context, params CreateEventParams) (int64, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return 0, fmt.Errorf("begin event tx: %w", err)
+	if err := r.initIdempotencyKeys(ctx); err != nil {
+		return err
 	}
-
-	res, err := tx.ExecContext(ctx, insertEvent,
-		params.UserID,
-		params.Title,
-		params.Location,
-		params.Time,
+	if err := r.initWebhooks(ctx); err != nil {
+		return err
+	}
+	if err := r.initBots(ctx); err != nil {
+		return err
+	}
+	if err := r.initScheduledMessages(ctx); err != nil {
+		return err
+	}
+	if err := r.initPolls(ctx); err != nil {
+		return err
+	}
+	if err := r.initTimePolls(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventSettings(ctx); err != nil {
+		return err
+	}
+	if err := r.initMessageFilters(ctx); err != nil {
+		return err
+	}
+	if err := r.initShadowBans(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventReviews(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventCheckins(ctx); err != nil {
+		return err
+	}
+	if err := r.initVerificationRequests(ctx); err != nil {
+		return err
+	}
+	if err := r.ensureJoinRequestStatuses(ctx); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "users", "deleted_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "strict_eligibility", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "conversation_join_requests", "ineligible_reason", "TEXT"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "conversation_join_requests", "note", "TEXT"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "capacity", "INTEGER"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "starts_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "status", "TEXT NOT NULL DEFAULT 'active'"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "latitude", "REAL"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "longitude", "REAL"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "events", "place_name", "TEXT"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "conversations", "archived_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "messages", "kind", "TEXT NOT NULL DEFAULT 'user'"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "messages", "message_type", "TEXT NOT NULL DEFAULT 'text'"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "messages", "metadata", "TEXT"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "messages", "hidden", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "conversation_settings", "archived_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "conversations", "slow_mode_seconds", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "users", "is_admin", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.ensureColumn(ctx, "users", "verified_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := r.initEventScores(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventSortIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.initHostEvents(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventVisibility(ctx); err != nil {
+		return err
+	}
+	if err := r.initLocationPrivacy(ctx); err != nil {
+		return err
+	}
+	if err := r.initMinAttendees(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventTemplates(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventRevisions(ctx); err != nil {
+		return err
+	}
+	if err := r.initEventDeletion(ctx); err != nil {
+		return err
+	}
+	if err := r.initConversationLocks(ctx); err != nil {
+		return err
+	}
+	if err := r.initNotificationSettings(ctx); err != nil {
+		return err
+	}
+	if err := r.initPasswordResets(ctx); err != nil {
+		return err
+	}
+	if err := r.initEmailDigests(ctx); err != nil {
+		return err
+	}
+	if err := r.initPhoneAuth(ctx); err != nil {
+		return err
+	}
+	if err := r.initUserIdentities(ctx); err != nil {
+		return err
+	}
+	if err := r.initMagicLinks(ctx); err != nil {
+		return err
+	}
+	if err := r.initUserSessions(ctx); err != nil {
+		return err
+	}
+	if err := r.initAPIKeys(ctx); err != nil {
+		return err
+	}
+	if err := r.backfillEventStarts(ctx); err != nil {
+		return err
+	}
+	if err := r.initUnreadCounters(ctx); err != nil {
+		return err
+	}
+	if err := r.initConversationActivity(ctx); err != nil {
+		return err
+	}
+	// Last, so table rebuilds above can't drop its triggers.
+	if err := r.initEventFeed(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// tableColumns lists the column names of a table via PRAGMA table_info.
+func tableColumns(ctx context.Context, q rowsQuery, table string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf(`PRAGMA table_info(%s);`, table))
+	if err != nil {
+		return nil, fmt.Errorf("inspect %s table: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return nil, fmt.Errorf("scan %s schema: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s schema: %w", table, err)
+	}
+	return columns, nil
+}
+
+// ensureColumn adds a column to an existing table when an older database is
+// missing it. SQLite only allows constant defaults here.
+func (r *EventRepository) ensureColumn(ctx context.Context, table, column, definition string) error {
+	columns, err := tableColumns(ctx, r.db, table)
+	if err != nil {
+		return err
+	}
+	for _, name := range columns {
+		if name == column {
+			return nil
+		}
+	}
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s;`, table, column, definition)); err != nil {
+		return fmt.Errorf("add %s.%s column: %w", table, column, err)
+	}
+	return nil
+}
+
+// ensureJoinRequestStatuses rebuilds conversation_join_requests when its CHECK
+// constraint predates the current status list, since SQLite cannot alter it.
+func (r *EventRepository) ensureJoinRequestStatuses(ctx context.Context) error {
+	var ddl string
+	if err := r.db.QueryRowContext(ctx, `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'conversation_join_requests'`).Scan(&ddl); err != nil {
+		return fmt.Errorf("inspect join requests table: %w", err)
+	}
+	if strings.Contains(ddl, joinRequestStatusCheck) {
+		return nil
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin join requests rebuild tx: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE conversation_join_requests RENAME TO conversation_join_requests_old;`); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("rename join requests table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, createTableConversationJoinRequests); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("recreate join requests table: %w", err)
+	}
+
+	oldColumns, err := tableColumns(ctx, tx, "conversation_join_requests_old")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	newColumns, err := tableColumns(ctx, tx, "conversation_join_requests")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	var shared []string
+	for _, name := range oldColumns {
+		for _, candidate := range newColumns {
+			if name == candidate {
+				shared = append(shared, name)
+				break
+			}
+		}
+	}
+	columnList := strings.Join(shared, ", ")
+	copyRows := fmt.Sprintf(`INSERT INTO conversation_join_requests (%s) SELECT %s FROM conversation_join_requests_old;`, columnList, columnList)
+	if _, err := tx.ExecContext(ctx, copyRows); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("copy join requests: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DROP TABLE conversation_join_requests_old;`); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("drop old join requests table: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit join requests rebuild: %w", err)
+	}
+	return nil
+}
+
+func (r *EventRepository) ensureEventsUserIDColumn(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, `PRAGMA table_info(events);`)
+	if err != nil {
+		return fmt.Errorf("inspect events table: %w", err)
+	}
+	defer rows.Close()
+
+	hasUserID := false
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return fmt.Errorf("scan events schema: %w", err)
+		}
+		_ = cid
+		_ = colType
+		_ = notNull
+		_ = defaultVal
+		_ = pk
+		if name == "user_id" {
+			hasUserID = true
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate events schema: %w", err)
+	}
+	if hasUserID {
+		return nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, `ALTER TABLE events ADD COLUMN user_id INTEGER NOT NULL DEFAULT 1 REFERENCES users(id);`); err != nil {
+		return fmt.Errorf("add user_id column: %w", err)
+	}
+
+	var fallbackUserID int64
+	if err := r.db.QueryRowContext(ctx, `SELECT id FROM users ORDER BY id ASC LIMIT 1`).Scan(&fallbackUserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Users will be seeded shortly; existing rows carry the default until then.
+			return nil
+		}
+		return fmt.Errorf("lookup fallback user: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `UPDATE events SET user_id = ? WHERE user_id = 1;`, fallbackUserID); err != nil {
+		return fmt.Errorf("backfill event owners: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EventRepository) ensureConversationEventColumn(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, `PRAGMA table_info(conversations);`)
+	if err != nil {
+		return fmt.Errorf("inspect conversations table: %w", err)
+	}
+	defer rows.Close()
+
+	hasEventID := false
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return fmt.Errorf("scan conversations schema: %w", err)
+		}
+		_ = cid
+		_ = colType
+		_ = notNull
+		_ = defaultVal
+		_ = pk
+		if name == "event_id" {
+			hasEventID = true
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate conversations schema: %w", err)
+	}
+	if hasEventID {
+		return nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, `ALTER TABLE conversations ADD COLUMN event_id INTEGER REFERENCES events(id) ON DELETE CASCADE;`); err != nil {
+		return fmt.Errorf("add conversation event_id column: %w", err)
+	}
+	return nil
+}
+
+func (r *EventRepository) Create(ctx context.Context, params CreateEventParams) (int64, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin event tx: %w", err)
+	}
+
+	latitude, longitude, placeName := nullablePlace(params.Place)
+
+	res, err := tx.ExecContext(ctx, insertEvent,
+		params.UserID,
+		params.Title,
+		params.Location,
+		params.Time,
+		params.Description,
+		params.Gender,
+		params.MinAge,
+		params.MaxAge,
+		params.DateLabel,
+		nullableInt(params.Capacity),
+		nullableEventStart(time.Now(), params.DateLabel, params.Time),
+		latitude,
+		longitude,
+		placeName,
+		params.StrictEligibility,
+		eventVisibilityOrDefault(params.Visibility),
+		params.HideExactLocation,
+		nullableArea(params.Area),
+		nullableInt(params.MinAttendees),
+	)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("insert event: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("fetch event id: %w", err)
+	}
+
+	nullableTitle := sql.NullString{String: params.Title, Valid: len(strings.TrimSpace(params.Title)) > 0}
+	nullableEventID := sql.NullInt64{Int64: id, Valid: true}
+
+	convoRes, err := tx.ExecContext(ctx, insertConversation, nullableTitle, params.UserID, nullableEventID)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("insert event conversation: %w", err)
+	}
+
+	convoID, err := convoRes.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("fetch event conversation id: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, insertConversationMember, convoID, params.UserID, "owner"); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("insert event conversation owner: %w", err)
+	}
+
+	if err := setEventTags(ctx, tx, id, params.Tags); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if params.IdempotencyKey != "" {
+		if err := recordIdempotencyKey(ctx, tx, params.IdempotencyUserID, idempotencyScopeEvent, params.IdempotencyKey, id); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	announced, err := enqueueWebhookEvent(ctx, tx, webhookEventCreated, id)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit event: %w", err)
+	}
+	if announced {
+		r.signalOutbox()
+	}
+
+	return id, nil
+}
+
+func (r *EventRepository) Update(ctx context.Context, id int64, userID int64, params UpdateEventParams) ([]EventFieldChange, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin event update tx: %w", err)
+	}
+
+	// Read the event in the same transaction, so the recorded changes are
+	// exactly this edit's.
+	before, err := scanEvent(tx.QueryRowContext(ctx, selectEventByID, id))
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		return nil, fmt.Errorf("fetch event: %w", err)
+	}
+
+	latitude, longitude, placeName := nullablePlace(params.Place)
+
+	result, err := tx.ExecContext(ctx, updateEvent,
+		params.Title,
+		params.Location,
+		params.Time,
 		params.Description,
 		params.Gender,
 		params.MinAge,
 		params.MaxAge,
 		params.DateLabel,
+		nullableInt(params.Capacity),
+		nullableEventStart(time.Now(), params.DateLabel, params.Time),
+		latitude,
+		longitude,
+		placeName,
+		params.StrictEligibility,
+		params.Visibility,
+		params.HideExactLocation,
+		updatedArea(params.Area),
+		nullableInt(params.MinAttendees),
+		id,
+		userID,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("update event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return nil, ErrEventNotFound
+	}
+
+	// The event chat is titled after the event.
+	if _, err := tx.ExecContext(ctx, updateEventConversationTitle, params.Title, id); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("rename event conversation: %w", err)
+	}
+
+	// An edit makes a cancelled or past event active again, and its chat
+	// with it.
+	if _, err := tx.ExecContext(ctx, updateEventConversationLocked, false, id); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("unlock event conversation: %w", err)
+	}
+
+	// A nil tag list leaves the current tags untouched; an empty one clears them.
+	if params.Tags != nil {
+		if err := setEventTags(ctx, tx, id, params.Tags); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	changes := diffEventUpdate(&before, params)
+	if err := recordEventRevision(ctx, tx, id, userID, changes); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit event update: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Delete soft deletes the host's event: it disappears at once, and its chat
+// stays, locked, until EventPurgeJob removes both.
+func (r *EventRepository) Delete(ctx context.Context, id int64, userID int64) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin event delete tx: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, softDeleteEvent, id, userID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("check delete rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return ErrEventNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, updateEventConversationLocked, true, id); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("lock event conversation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit event delete: %w", err)
+	}
+
+	return nil
+}
+
+// TransferEvent hands an event from its host to another member of the event
+// chat, who also becomes the conversation owner; the old host stays on as a
+// regular member. It returns the event chat's ID.
+func (r *EventRepository) TransferEvent(ctx context.Context, eventID, hostID, newHostID int64) (int64, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	if event.UserID != hostID {
+		return 0, ErrNotEventHost
+	}
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transfer event tx: %w", err)
+	}
+
+	var member int
+	if err := tx.QueryRowContext(ctx, checkConversationMembership, convo.ID, newHostID).Scan(&member); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotConversationMember
+		}
+		return 0, fmt.Errorf("check membership: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, updateEventHost, newHostID, eventID, hostID)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("update event host: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		// Someone else transferred the event first.
+		tx.Rollback()
+		return 0, ErrNotEventHost
+	}
+	if _, err := tx.ExecContext(ctx, updateConversationMemberRole, roleMember, convo.ID, hostID); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("demote previous host: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, updateConversationMemberRole, roleOwner, convo.ID, newHostID); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("promote new host: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit event transfer: %w", err)
+	}
+	return convo.ID, nil
+}
+
+// scanEvent reads the column list shared by every event SELECT.
+func scanEvent(row rowScanner) (Event, error) {
+	var evt Event
+	var capacity, minAttendees sql.NullInt64
+	var startsAt sql.NullTime
+	var latitude, longitude sql.NullFloat64
+	var placeName, area sql.NullString
+	err := row.Scan(
+		&evt.ID,
+		&evt.UserID,
+		&evt.Title,
+		&evt.Location,
+		&evt.Time,
+		&evt.Description,
+		&evt.Gender,
+		&evt.MinAge,
+		&evt.MaxAge,
+		&evt.DateLabel,
+		&evt.CreatedAt,
+		&evt.HostName,
+		&capacity,
+		&startsAt,
+		&evt.Status,
+		&latitude,
+		&longitude,
+		&placeName,
+		&evt.StrictEligibility,
+		&evt.HostVerified,
+		&evt.Visibility,
+		&evt.HideExactLocation,
+		&area,
+		&minAttendees,
 	)
+	if capacity.Valid {
+		value := int(capacity.Int64)
+		evt.Capacity = &value
+	}
+	if minAttendees.Valid {
+		value := int(minAttendees.Int64)
+		evt.MinAttendees = &value
+	}
+	if startsAt.Valid {
+		value := startsAt.Time
+		evt.StartsAt = &value
+	}
+	if latitude.Valid && longitude.Valid {
+		lat, lng := latitude.Float64, longitude.Float64
+		evt.Latitude = &lat
+		evt.Longitude = &lng
+	}
+	if area.Valid && area.String != "" {
+		value := area.String
+		evt.Area = &value
+	}
+	if placeName.Valid {
+		value := placeName.String
+		evt.PlaceName = &value
+	}
+	return evt, err
+}
+
+// EventListOptions narrows and personalizes List.
+type EventListOptions struct {
+	// ViewerID flags events with `bookmarked` for a signed-in user; 0 for guests.
+	ViewerID int64
+	// IncludePast also returns events whose start time has elapsed.
+	IncludePast bool
+	// Tags keeps events carrying any of the named tags.
+	Tags []string
+	// Sort is one of the eventSort orders; empty means newest first.
+	Sort string
+	// Origin is where eventSortClosest measures from.
+	Origin *Coordinates
+}
+
+// List returns the visible events in opts.Sort order. Past events are hidden
+// unless opts.IncludePast is set.
+func (r *EventRepository) List(ctx context.Context, opts EventListOptions) ([]Event, error) {
+	query, args := eventListFilters(selectEvents, opts)
+	order, orderArgs := eventListOrder(opts)
+	return r.listEvents(ctx, query+order, append(args, orderArgs...), opts.ViewerID)
+}
+
+// eventListFilters appends the visibility filter and opts' status and tag
+// filters to a query over events aliased `e`.
+func eventListFilters(query string, opts EventListOptions) (string, []any) {
+	var args []any
+	query += eventListedClause
+	if !opts.IncludePast {
+		query += " AND e.status = 'active'"
+	}
+	if len(opts.Tags) > 0 {
+		query += fmt.Sprintf(eventTagFilter, placeholders(len(opts.Tags)))
+		for _, tag := range opts.Tags {
+			args = append(args, tag)
+		}
+	}
+	return query, args
+}
+
+// listEvents runs a selectEvents query and fills in tags, counts and, for a
+// signed-in viewer, bookmarks.
+func (r *EventRepository) listEvents(ctx context.Context, query string, args []any, viewerID int64) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+
+	for rows.Next() {
+		evt, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, evt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate events: %w", err)
+	}
+
+	if err := r.attachEventTags(ctx, events); err != nil {
+		return nil, err
+	}
+	if err := r.attachEventCounts(ctx, events, viewerID); err != nil {
+		return nil, err
+	}
+	if err := r.attachHostRatings(ctx, events); err != nil {
+		return nil, err
+	}
+
+	if viewerID > 0 {
+		bookmarked, err := r.fetchBookmarkedEventIDs(ctx, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range events {
+			_, ok := bookmarked[events[i].ID]
+			events[i].Bookmarked = &ok
+		}
+	}
+
+	return events, nil
+}
+
+// ExpireEvents marks active events whose start time is at or before now as
+// past. It returns the IDs of the events that were expired.
+func (r *EventRepository) ExpireEvents(ctx context.Context, now time.Time) ([]int64, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin expire events tx: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, markPastEvents, sqliteTime(now))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("mark past events: %w", err)
+	}
+	var expired []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, fmt.Errorf("scan expired event: %w", err)
+		}
+		expired = append(expired, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("iterate expired events: %w", err)
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit expire events: %w", err)
+	}
+	return expired, nil
+}
+
+// backfillEventStarts resolves starts_at for rows created before the column
+// existed, using each event's creation day as the reference for its label.
+func (r *EventRepository) backfillEventStarts(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, selectEventsMissingStart)
+	if err != nil {
+		return fmt.Errorf("list events missing start: %w", err)
+	}
+
+	type pendingStart struct {
+		id       int64
+		startsAt sql.NullString
+	}
+	var pending []pendingStart
+	for rows.Next() {
+		var (
+			id        int64
+			clock     string
+			dateLabel string
+			createdAt time.Time
+		)
+		if err := rows.Scan(&id, &clock, &dateLabel, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan event missing start: %w", err)
+		}
+		pending = append(pending, pendingStart{id: id, startsAt: nullableEventStart(createdAt, dateLabel, clock)})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("iterate events missing start: %w", err)
+	}
+	rows.Close()
+
+	for _, p := range pending {
+		if !p.startsAt.Valid {
+			continue
+		}
+		if _, err := r.db.ExecContext(ctx, updateEventStart, p.startsAt, p.id); err != nil {
+			return fmt.Errorf("backfill event start: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetchBookmarkedEventIDs loads the viewer's bookmarks as a set so list
+// responses can be flagged without a per-event query.
+func (r *EventRepository) fetchBookmarkedEventIDs(ctx context.Context, userID int64) (map[int64]struct{}, error) {
+	rows, err := r.db.QueryContext(ctx, selectBookmarkedEventIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query bookmarked event ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]struct{})
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan bookmarked event id: %w", err)
+		}
+		ids[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bookmarked event ids: %w", err)
+	}
+	return ids, nil
+}
+
+// attachEventCounts fills in member counts for a page of events, and pending
+// request counts on the ones viewerID hosts, with one query each.
+func (r *EventRepository) attachEventCounts(ctx context.Context, events []Event, viewerID int64) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]int, len(events))
+	args := make([]any, 0, len(events))
+	var hosted []any
+	for i := range events {
+		index[events[i].ID] = i
+		args = append(args, events[i].ID)
+		if viewerID > 0 && events[i].UserID == viewerID {
+			zero := 0
+			events[i].PendingRequestCount = &zero
+			hosted = append(hosted, events[i].ID)
+		}
+	}
+
+	err := r.scanEventCounts(ctx, fmt.Sprintf(selectMemberCountsForEvents, placeholders(len(args))), args, func(eventID int64, count int) {
+		events[index[eventID]].MemberCount = count
+	})
+	if err != nil {
+		return fmt.Errorf("event member counts: %w", err)
+	}
+	if len(hosted) == 0 {
+		return nil
+	}
+	err = r.scanEventCounts(ctx, fmt.Sprintf(selectPendingRequestCountsForEvents, placeholders(len(hosted))), hosted, func(eventID int64, count int) {
+		*events[index[eventID]].PendingRequestCount = count
+	})
+	if err != nil {
+		return fmt.Errorf("event pending request counts: %w", err)
+	}
+	return nil
+}
+
+// scanEventCounts runs an (event_id, count) aggregate and hands each row to set.
+func (r *EventRepository) scanEventCounts(ctx context.Context, query string, args []any, set func(eventID int64, count int)) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventID int64
+		var count int
+		if err := rows.Scan(&eventID, &count); err != nil {
+			return err
+		}
+		set(eventID, count)
+	}
+	return rows.Err()
+}
+
+// BookmarkEvent saves an event for the user. Saving twice is a no-op.
+func (r *EventRepository) BookmarkEvent(ctx context.Context, userID, eventID int64) error {
+	if _, err := r.GetEventByID(ctx, eventID); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, insertEventBookmark, userID, eventID); err != nil {
+		return fmt.Errorf("insert event bookmark: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookmark deletes a saved event; removing a missing bookmark is a no-op.
+func (r *EventRepository) RemoveBookmark(ctx context.Context, userID, eventID int64) error {
+	if _, err := r.db.ExecContext(ctx, deleteEventBookmark, userID, eventID); err != nil {
+		return fmt.Errorf("delete event bookmark: %w", err)
+	}
+	return nil
+}
+
+// ListBookmarkedEvents returns the user's saved events, most recently saved first.
+func (r *EventRepository) ListBookmarkedEvents(ctx context.Context, userID int64) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx, selectBookmarkedEvents, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query bookmarked events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	bookmarked := true
+	for rows.Next() {
+		evt, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan bookmarked event: %w", err)
+		}
+		evt.Bookmarked = &bookmarked
+		events = append(events, evt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bookmarked events: %w", err)
+	}
+	if err := r.attachEventTags(ctx, events); err != nil {
+		return nil, err
+	}
+	if err := r.attachEventCounts(ctx, events, userID); err != nil {
+		return nil, err
+	}
+	if err := r.attachHostRatings(ctx, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// CreateConversation creates a new conversation and ensures the creator is a member.
+func (r *EventRepository) CreateConversation(ctx context.Context, title *string, createdBy int64, memberIDs []int64, eventID *int64) (*Conversation, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin conversation tx: %w", err)
+	}
+
+	role := "owner"
+	var nullableTitle sql.NullString
+	if title != nil {
+		nullableTitle = sql.NullString{String: *title, Valid: true}
+	}
+	var nullableEventID sql.NullInt64
+	if eventID != nil {
+		nullableEventID = sql.NullInt64{Int64: *eventID, Valid: true}
+	}
+
+	res, err := tx.ExecContext(ctx, insertConversation, nullableTitle, createdBy, nullableEventID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert conversation: %w", err)
+	}
+
+	convoID, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("fetch conversation id: %w", err)
+	}
+
+	// ensure creator is in the member list
+	creatorIncluded := false
+	for _, memberID := range memberIDs {
+		if memberID == createdBy {
+			creatorIncluded = true
+			break
+		}
+	}
+	if !creatorIncluded {
+		memberIDs = append(memberIDs, createdBy)
+	}
+
+	for _, memberID := range memberIDs {
+		memberRole := "member"
+		if memberID == createdBy {
+			memberRole = role
+		}
+		if _, err := tx.ExecContext(ctx, insertConversationMember, convoID, memberID, memberRole); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert conversation member: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit conversation: %w", err)
+	}
+
+	conversation := &Conversation{ID: convoID, CreatedBy: createdBy}
+	if nullableTitle.Valid {
+		value := nullableTitle.String
+		conversation.Title = &value
+	}
+	if nullableEventID.Valid {
+		value := nullableEventID.Int64
+		conversation.EventID = &value
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT created_at FROM conversations WHERE id = ?", convoID)
+	if err := row.Scan(&conversation.CreatedAt); err != nil {
+		return nil, fmt.Errorf("fetch conversation created_at: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// ListConversations returns all conversations visible to the user, hydrated with participants and unread counts.
+// They are ordered by latest activity. With opts.Limit set, a page of
+// that many is returned along with the cursor for the next one, empty on the
+// last page.
+func (r *EventRepository) ListConversations(ctx context.Context, userID int64, opts ConversationListOptions) ([]ConversationSummary, string, error) {
+	query := selectConversationsForUser
+	args := []any{userID}
+	switch {
+	case opts.ArchivedOnly:
+		query += " AND " + conversationArchivedClause
+	case !opts.IncludeArchived:
+		query += " AND NOT " + conversationArchivedClause
+	}
+	if opts.Search != "" {
+		pattern := likeContains(opts.Search)
+		query += " AND " + conversationSearchClause
+		args = append(args, pattern, pattern, pattern)
+	}
+	after, err := decodeConversationCursor(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil {
+		query += " AND (" + conversationActivity + " < ? OR (" + conversationActivity + " = ? AND c.id < ?))"
+		args = append(args, after.activity, after.activity, after.id)
+	}
+	query += " ORDER BY " + conversationActivity + " DESC, c.id DESC"
+	if opts.Limit > 0 {
+		// One extra row tells whether another page follows.
+		query += " LIMIT ?"
+		args = append(args, opts.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+";", args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("list conversations: %w", err)
+	}
+
+	var conversations []Conversation
+	var positions []conversationCursor
+	for rows.Next() {
+		var activity string
+		convo, err := scanConversation(appendScanner{rows, []any{&activity}})
+		if err != nil {
+			rows.Close()
+			return nil, "", fmt.Errorf("scan conversation: %w", err)
+		}
+		conversations = append(conversations, convo)
+		positions = append(positions, conversationCursor{activity: activity, id: convo.ID})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, "", fmt.Errorf("iterate conversations: %w", err)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, "", fmt.Errorf("close conversations rows: %w", err)
+	}
+
+	var next string
+	if opts.Limit > 0 && len(conversations) > opts.Limit {
+		conversations = conversations[:opts.Limit]
+		next = positions[opts.Limit-1].encode()
+	}
+
+	summaries := make([]ConversationSummary, 0, len(conversations))
+	for _, convo := range conversations {
+		summary, err := r.HydrateConversationSummary(ctx, convo, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, next, nil
+}
+
+// ListMessages paginates messages for a given conversation.
+func (r *EventRepository) ListMessages(ctx context.Context, conversationID, viewerID int64, limit, offset int) ([]Message, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectMessagesForConversation, conversationID, viewerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate messages: %w", err)
+	}
+	if err := r.attachMentions(ctx, messages); err != nil {
+		return nil, err
+	}
+	if err := r.attachPolls(ctx, messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+const (
+	messageKindUser   = "user"
+	messageKindSystem = "system"
+)
+
+// CreateMessage stores a new message and returns the saved row for broadcasting.
+// With params.Notify set, a push to the other members is queued in the outbox
+// in the same transaction, as is a link preview fetch for text with a link.
+// A params.IdempotencyKey another send already used
+// fails with ErrIdempotencyKeyUsed.
+func (r *EventRepository) CreateMessage(ctx context.Context, params CreateMessageParams) (*Message, error) {
+	attachment := sql.NullString{}
+	if params.AttachmentURL != nil {
+		attachment = sql.NullString{String: *params.AttachmentURL, Valid: true}
+	}
+
+	kind := params.Kind
+	if kind == "" {
+		kind = messageKindUser
+	}
+	messageType := params.MessageType
+	if messageType == "" {
+		messageType = messageTypeText
+	}
+	metadata := sql.NullString{}
+	if len(params.Metadata) > 0 {
+		metadata = sql.NullString{String: string(params.Metadata), Valid: true}
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin create message tx: %w", err)
+	}
+
+	// A locked chat is read-only; only system notices get in.
+	if kind == messageKindUser {
+		locked, err := isConversationLocked(ctx, tx, params.ConversationID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if locked {
+			tx.Rollback()
+			return nil, ErrConversationLocked
+		}
+	}
+
+	insert, err := r.prepared(ctx, insertMessage)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	msg, err := scanMessage(tx.StmtContext(ctx, insert).QueryRowContext(ctx, params.ConversationID, params.SenderID, params.Body, attachment, params.DeliveryStatus, kind, messageType, metadata, params.Hidden))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert message: %w", err)
+	}
+
+	// A new message from someone revives the chat for everyone who archived it.
+	// Hidden messages do nothing that others could notice.
+	if kind == messageKindUser && !params.Hidden {
+		if _, err := tx.ExecContext(ctx, unarchiveForRecipients, msg.ConversationID, msg.SenderID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("unarchive for recipients: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, unarchiveConversation, msg.ConversationID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("unarchive conversation: %w", err)
+		}
+		if err := storeMentions(ctx, tx, &msg); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if params.IdempotencyKey != "" {
+		if err := recordIdempotencyKey(ctx, tx, params.SenderID, idempotencyScopeMessage, params.IdempotencyKey, msg.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if params.Notify && !params.Hidden {
+		if err := enqueueOutbox(ctx, tx, outboxMessagePush, outboxMessagePayload{MessageID: msg.ID}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	previewing := r.queueLinkPreviews && wantsLinkPreview(params, kind, messageType)
+	if previewing {
+		if err := enqueueOutbox(ctx, tx, outboxLinkPreview, outboxMessagePayload{MessageID: msg.ID}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	announced := false
+	if !params.Hidden {
+		announced, err = enqueueWebhookEvent(ctx, tx, webhookMessageCreated, msg.ID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit message: %w", err)
+	}
+	if (params.Notify && !params.Hidden) || announced || previewing {
+		r.signalOutbox()
+	}
+	return &msg, nil
+}
+
+func scanJoinRequest(row rowScanner) (*ConversationJoinRequest, error) {
+	var req ConversationJoinRequest
+	var decidedAt sql.NullTime
+	var decidedBy sql.NullInt64
+	var ineligibleReason, note sql.NullString
+	if err := row.Scan(&req.ID, &req.EventID, &req.UserID, &req.Status, &req.CreatedAt, &decidedAt, &decidedBy, &ineligibleReason, &note); err != nil {
+		return nil, err
+	}
+	if note.Valid {
+		req.Note = &note.String
+	}
+	if ineligibleReason.Valid {
+		req.IneligibleReason = &ineligibleReason.String
+	}
+	if decidedAt.Valid {
+		t := decidedAt.Time
+		req.DecidedAt = &t
+	}
+	if decidedBy.Valid {
+		id := decidedBy.Int64
+		req.DecidedBy = &id
+	}
+	return &req, nil
+}
+
+func fetchJoinRequestByID(ctx context.Context, q rowQuery, id int64) (*ConversationJoinRequest, error) {
+	req, err := scanJoinRequest(q.QueryRowContext(ctx, selectJoinRequestByID, id))
 	if err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("insert event: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrJoinRequestNotFound
+		}
+		return nil, fmt.Errorf("fetch join request: %w", err)
+	}
+	return req, nil
+}
+
+// scanMessage reads id, conversation_id, sender_id, body, attachment_url,
+// delivery_status, kind, message_type, metadata, created_at.
+func scanMessage(row rowScanner) (Message, error) {
+	var msg Message
+	var attachment, metadata sql.NullString
+	if err := row.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body, &attachment, &msg.DeliveryStatus, &msg.Kind, &msg.MessageType, &metadata, &msg.CreatedAt); err != nil {
+		return Message{}, err
+	}
+	if attachment.Valid {
+		msg.AttachmentURL = &attachment.String
 	}
+	if metadata.Valid {
+		msg.Metadata = json.RawMessage(metadata.String)
+	}
+	return msg, nil
+}
 
-	id, err := res.LastInsertId()
+// scanConversation reads id, title, created_by, created_at, event_id,
+// archived_at, slow_mode_seconds, locked.
+func scanConversation(row rowScanner) (Conversation, error) {
+	var convo Conversation
+	var title sql.NullString
+	var eventID sql.NullInt64
+	var archivedAt sql.NullTime
+	if err := row.Scan(&convo.ID, &title, &convo.CreatedBy, &convo.CreatedAt, &eventID, &archivedAt, &convo.SlowModeSeconds, &convo.Locked); err != nil {
+		return Conversation{}, err
+	}
+	if title.Valid {
+		value := title.String
+		convo.Title = &value
+	}
+	if eventID.Valid {
+		value := eventID.Int64
+		convo.EventID = &value
+	}
+	if archivedAt.Valid {
+		value := archivedAt.Time
+		convo.ArchivedAt = &value
+	}
+	return convo, nil
+}
+
+func fetchConversationByEventID(ctx context.Context, q rowQuery, eventID int64) (*Conversation, error) {
+	convo, err := scanConversation(q.QueryRowContext(ctx, selectConversationByEventID, eventID))
 	if err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("fetch event id: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("fetch conversation by event: %w", err)
 	}
+	return &convo, nil
+}
 
-	nullableTitle := sql.NullString{String: params.Title, Valid: len(strings.TrimSpace(params.Title)) > 0}
-	nullableEventID := sql.NullInt64{Int64: id, Valid: true}
+func (r *EventRepository) GetEventByID(ctx context.Context, eventID int64) (*Event, error) {
+	evt, err := scanEvent(r.db.QueryRowContext(ctx, selectEventByID, eventID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		return nil, fmt.Errorf("fetch event: %w", err)
+	}
+	return &evt, nil
+}
 
-	convoRes, err := tx.ExecContext(ctx, insertConversation, nullableTitle, params.UserID, nullableEventID)
+// FindOrCreateDirectConversation returns the existing two-person conversation
+// between userID and otherID, creating one only if none exists. created
+// reports which happened.
+func (r *EventRepository) FindOrCreateDirectConversation(ctx context.Context, userID, otherID int64) (*Conversation, bool, error) {
+	convo, err := scanConversation(r.db.QueryRowContext(ctx, selectDirectConversation, userID, otherID))
+	if err == nil {
+		return &convo, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("fetch direct conversation: %w", err)
+	}
+
+	created, err := r.CreateConversation(ctx, nil, userID, []int64{otherID}, nil)
 	if err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("insert event conversation: %w", err)
+		return nil, false, err
 	}
+	return created, true, nil
+}
 
-	convoID, err := convoRes.LastInsertId()
+// AddConversationMembers lets the owner of a non-event conversation add
+// people to it. Users who are already members are skipped; the IDs actually
+// added are returned.
+func (r *EventRepository) AddConversationMembers(ctx context.Context, conversationID, actorID int64, userIDs []int64) ([]int64, error) {
+	tx, err := r.beginTx(ctx)
 	if err != nil {
+		return nil, fmt.Errorf("begin add members tx: %w", err)
+	}
+
+	if _, err := requireConversationOwner(ctx, tx, conversationID, actorID); err != nil {
 		tx.Rollback()
-		return 0, fmt.Errorf("fetch event conversation id: %w", err)
+		return nil, err
 	}
 
-	if _, err := tx.ExecContext(ctx, insertConversationMember, convoID, params.UserID, "owner"); err != nil {
+	added := []int64{}
+	for _, userID := range userIDs {
+		if containsInt64(added, userID) {
+			continue
+		}
+		result, err := tx.ExecContext(ctx, insertConversationMember, conversationID, userID, "member")
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert conversation member: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("check member rows affected: %w", err)
+		}
+		if rowsAffected > 0 {
+			added = append(added, userID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit add members: %w", err)
+	}
+	return added, nil
+}
+
+// RenameConversation lets the owner of a non-event conversation change its
+// title. Event chats follow their event's title instead.
+func (r *EventRepository) RenameConversation(ctx context.Context, conversationID, actorID int64, title string) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin rename tx: %w", err)
+	}
+
+	if _, err := requireConversationOwner(ctx, tx, conversationID, actorID); err != nil {
 		tx.Rollback()
-		return 0, fmt.Errorf("insert event conversation owner: %w", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, updateConversationTitle, title, conversationID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("rename conversation: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("commit event: %w", err)
+		return fmt.Errorf("commit rename: %w", err)
 	}
+	return nil
+}
 
-	return id, nil
+// requireConversationOwner loads a non-event conversation and checks that
+// actorID owns it.
+func requireConversationOwner(ctx context.Context, q rowQuery, conversationID, actorID int64) (*Conversation, error) {
+	convo, err := scanConversation(q.QueryRowContext(ctx, selectConversationByID, conversationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("fetch conversation: %w", err)
+	}
+	if convo.EventID != nil {
+		return nil, ErrEventConversation
+	}
+
+	var role string
+	if err := q.QueryRowContext(ctx, selectConversationMemberRole, conversationID, actorID).Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotConversationMember
+		}
+		return nil, fmt.Errorf("fetch member role: %w", err)
+	}
+	if role != "owner" {
+		return nil, ErrNotConversationOwner
+	}
+	return &convo, nil
 }
 
-func (r *EventRepository) Update(ctx context.Context, id int64, userID int64, params UpdateEventParams) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+// CreateSystemMessage records an automated notice in a conversation, such as
+// a membership change. actorID is stored as the sender so history can show
+// who caused it.
+func (r *EventRepository) CreateSystemMessage(ctx context.Context, conversationID, actorID int64, body string) (*Message, error) {
+	return r.CreateMessage(ctx, CreateMessageParams{
+		ConversationID: conversationID,
+		SenderID:       actorID,
+		Body:           body,
+		DeliveryStatus: "sent",
+		Kind:           messageKindSystem,
+	})
+}
+
+// GetUserNames resolves display names keyed by user ID, skipping unknown IDs.
+func (r *EventRepository) GetUserNames(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	names := make(map[int64]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+	args := make([]any, len(userIDs))
+	for i, userID := range userIDs {
+		args[i] = userID
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectUserNames, placeholders(len(userIDs))), args...)
 	if err != nil {
-		return fmt.Errorf("begin event update tx: %w", err)
+		return nil, fmt.Errorf("fetch user names: %w", err)
 	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int64
+		var name string
+		if err := rows.Scan(&userID, &name); err != nil {
+			return nil, fmt.Errorf("scan user name: %w", err)
+		}
+		names[userID] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user names: %w", err)
+	}
+	return names, nil
+}
 
-	result, err := tx.ExecContext(ctx, updateEvent,
-		params.Title,
-		params.Location,
-		params.Time,
-		params.Description,
-		params.Gender,
-		params.MinAge,
-		params.MaxAge,
-		params.DateLabel,
-		id,
-		userID,
-	)
+// GetConversation loads a conversation by ID.
+func (r *EventRepository) GetConversation(ctx context.Context, conversationID int64) (*Conversation, error) {
+	convo, err := scanConversation(r.db.QueryRowContext(ctx, selectConversationByID, conversationID))
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("update event: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("fetch conversation: %w", err)
 	}
+	return &convo, nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+func (r *EventRepository) GetConversationByEventID(ctx context.Context, eventID int64) (*Conversation, error) {
+	return fetchConversationByEventID(ctx, r.db, eventID)
+}
+
+// CreateJoinRequest files a request to join the event's chat. note is the
+// requester's optional intro for the host; empty stores none. Private events
+// are joined by invite, so to anyone outside them they don't exist.
+func (r *EventRepository) CreateJoinRequest(ctx context.Context, eventID, userID int64, note string) (*ConversationJoinRequest, error) {
+	event, err := r.GetVisibleEvent(ctx, eventID, userID)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("check rows affected: %w", err)
+		return nil, err
+	}
+	if event.UserID == userID {
+		return nil, ErrAlreadyConversationMember
+	}
+	if event.Status == eventStatusCancelled {
+		return nil, ErrEventCancelled
 	}
 
-	if rowsAffected == 0 {
-		tx.Rollback()
-		return ErrEventNotFound
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit event update: %w", err)
+	isMember, err := r.IsConversationMember(ctx, convo.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, ErrAlreadyConversationMember
 	}
 
-	return nil
-}
+	banned, err := isBannedFromEvent(ctx, r.db, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if banned {
+		return nil, ErrBannedFromEvent
+	}
 
-func (r *EventRepository) Delete(ctx context.Context, id int64, userID int64) error {
-	result, err := r.db.ExecContext(ctx, `DELETE FROM events WHERE id = ? AND user_id = ?`, id, userID)
+	// Requesters who don't match the event's gender/age filters are turned
+	// away on strict events and flagged for the host otherwise.
+	profile, err := r.GetUserProfile(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("delete event: %w", err)
+		return nil, err
+	}
+	var ineligibleReason any
+	if reason := eligibilityProblem(profile, *event, time.Now()); reason != "" {
+		if event.StrictEligibility {
+			return nil, &IneligibleError{Reason: reason}
+		}
+		ineligibleReason = reason
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if _, err := scanJoinRequest(r.db.QueryRowContext(ctx, selectOpenJoinRequest, eventID, userID)); err == nil {
+		return nil, ErrJoinRequestExists
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("check pending join request: %w", err)
+	}
+
+	// Full events still accept requests, but they queue on the waitlist until
+	// a spot opens up.
+	status := "pending"
+	full, err := isEventFull(ctx, r.db, event, convo.ID)
 	if err != nil {
-		return fmt.Errorf("check delete rows affected: %w", err)
+		return nil, err
+	}
+	if full {
+		status = "waitlisted"
 	}
 
-	if rowsAffected == 0 {
-		return ErrEventNotFound
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin join request tx: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, insertJoinRequest, eventID, userID, status, ineligibleReason, nullableString(note))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert join request: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("fetch join request id: %w", err)
+	}
+	announced, err := enqueueWebhookEvent(ctx, tx, webhookJoinRequestCreated, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit join request: %w", err)
+	}
+	if announced {
+		r.signalOutbox()
 	}
+	return fetchJoinRequestByID(ctx, r.db, id)
+}
 
-	return nil
+func (r *EventRepository) ApproveJoinRequest(ctx context.Context, eventID, userID, approverID int64) (*ConversationJoinRequest, error) {
+	return r.decideJoinRequest(ctx, eventID, userID, approverID, true)
 }
 
-func (r *EventRepository) List(ctx context.Context) ([]Event, error) {
-	rows, err := r.db.QueryContext(ctx, selectEvents)
+func (r *EventRepository) DenyJoinRequest(ctx context.Context, eventID, userID, approverID int64) (*ConversationJoinRequest, error) {
+	return r.decideJoinRequest(ctx, eventID, userID, approverID, false)
+}
+
+func (r *EventRepository) decideJoinRequest(ctx context.Context, eventID, userID, approverID int64, approve bool) (*ConversationJoinRequest, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEventModerator(ctx, r.db, event, convo.ID, approverID); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.beginTx(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("query events: %w", err)
+		return nil, fmt.Errorf("begin join decision tx: %w", err)
 	}
-	defer rows.Close()
+	requestID, err := decideJoinRequestTx(ctx, tx, event, convo.ID, userID, approverID, approve)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit join decision: %w", err)
+	}
+	r.signalOutbox()
 
-	var events []Event
+	return fetchJoinRequestByID(ctx, r.db, requestID)
+}
 
-	for rows.Next() {
-		var evt Event
-		if err := rows.Scan(
-			&evt.ID,
-			&evt.UserID,
-			&evt.Title,
-			&evt.Location,
-			&evt.Time,
-			&evt.Description,
-			&evt.Gender,
-			&evt.MinAge,
-			&evt.MaxAge,
-			&evt.DateLabel,
-			&evt.CreatedAt,
-			&evt.HostName,
-		); err != nil {
-			return nil, fmt.Errorf("scan event: %w", err)
+// decideJoinRequestTx approves or denies userID's pending request inside tx
+// and returns the request ID. Sentinel errors (not found, already a member,
+// full) are returned before anything is written, so tx stays usable.
+func decideJoinRequestTx(ctx context.Context, tx *sql.Tx, event *Event, convoID, userID, approverID int64, approve bool) (int64, error) {
+	if approve {
+		var member int
+		err := tx.QueryRowContext(ctx, checkConversationMembership, convoID, userID).Scan(&member)
+		if err == nil {
+			return 0, ErrAlreadyConversationMember
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("check membership: %w", err)
 		}
-		events = append(events, evt)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate events: %w", err)
+	req, err := scanJoinRequest(tx.QueryRowContext(ctx, selectPendingJoinRequest, event.ID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrJoinRequestNotFound
+		}
+		return 0, fmt.Errorf("fetch pending join request: %w", err)
 	}
 
-	return events, nil
-}
+	if !approve {
+		if _, err := tx.ExecContext(ctx, updateJoinRequestStatus, "denied", approverID, req.ID); err != nil {
+			return 0, fmt.Errorf("deny join request: %w", err)
+		}
+		if err := enqueueOutbox(ctx, tx, outboxJoinDecision, outboxJoinDecisionPayload{ConversationID: convoID, RequestID: req.ID}); err != nil {
+			return 0, err
+		}
+		return req.ID, nil
+	}
 
-// CreateConversation creates a new conversation and ensures the creator is a member.
-func (r *EventRepository) CreateConversation(ctx context.Context, title *string, createdBy int64, memberIDs []int64, eventID *int64) (*Conversation, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+	full, err := isEventFull(ctx, tx, event, convoID)
 	if err != nil {
-		return nil, fmt.Errorf("begin conversation tx: %w", err)
+		return 0, err
 	}
-
-	role := "owner"
-	var nullableTitle sql.NullString
-	if title != nil {
-		nullableTitle = sql.NullString{String: *title, Valid: true}
+	if full {
+		return 0, ErrEventFull
 	}
-	var nullableEventID sql.NullInt64
-	if eventID != nil {
-		nullableEventID = sql.NullInt64{Int64: *eventID, Valid: true}
+	if _, err := tx.ExecContext(ctx, updateJoinRequestStatus, "approved", approverID, req.ID); err != nil {
+		return 0, fmt.Errorf("approve join request: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, insertConversationMember, convoID, userID, "member"); err != nil {
+		return 0, fmt.Errorf("add conversation member: %w", err)
+	}
+	if err := enqueueOutbox(ctx, tx, outboxJoinDecision, outboxJoinDecisionPayload{ConversationID: convoID, RequestID: req.ID}); err != nil {
+		return 0, err
 	}
+	return req.ID, nil
+}
 
-	res, err := tx.ExecContext(ctx, insertConversation, nullableTitle, createdBy, nullableEventID)
+// DecideJoinRequests applies a host's approve/deny decisions in one
+// transaction, in order, so earlier approvals count towards capacity for later
+// ones. Per-request problems are reported in the item's result; only database
+// failures abort the batch. It also returns the event chat's ID.
+func (r *EventRepository) DecideJoinRequests(ctx context.Context, eventID, approverID int64, decisions []JoinDecision) ([]JoinDecisionResult, int64, error) {
+	event, err := r.GetEventByID(ctx, eventID)
 	if err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("insert conversation: %w", err)
+		return nil, 0, err
 	}
-
-	convoID, err := res.LastInsertId()
+	convo, err := r.GetConversationByEventID(ctx, eventID)
 	if err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("fetch conversation id: %w", err)
+		return nil, 0, err
 	}
-
-	// ensure creator is in the member list
-	creatorIncluded := false
-	for _, memberID := range memberIDs {
-		if memberID == createdBy {
-			creatorIncluded = true
-			break
-		}
+	if err := checkEventModerator(ctx, r.db, event, convo.ID, approverID); err != nil {
+		return nil, 0, err
 	}
-	if !creatorIncluded {
-		memberIDs = append(memberIDs, createdBy)
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("begin batch join decision tx: %w", err)
 	}
 
-	for _, memberID := range memberIDs {
-		memberRole := "member"
-		if memberID == createdBy {
-			memberRole = role
-		}
-		if _, err := tx.ExecContext(ctx, insertConversationMember, convoID, memberID, memberRole); err != nil {
+	results := make([]JoinDecisionResult, 0, len(decisions))
+	for _, decision := range decisions {
+		result := JoinDecisionResult{UserID: decision.UserID, Action: decision.Action}
+		requestID, err := decideJoinRequestTx(ctx, tx, event, convo.ID, decision.UserID, approverID, decision.Action == "approve")
+		switch {
+		case err == nil:
+			req, err := fetchJoinRequestByID(ctx, tx, requestID)
+			if err != nil {
+				tx.Rollback()
+				return nil, 0, err
+			}
+			result.OK = true
+			result.Request = req
+		case errors.Is(err, ErrJoinRequestNotFound):
+			result.Error = "pending request not found"
+		case errors.Is(err, ErrAlreadyConversationMember):
+			result.Error = "user already a member"
+		case errors.Is(err, ErrEventFull):
+			result.Error = "event is full"
+		default:
 			tx.Rollback()
-			return nil, fmt.Errorf("insert conversation member: %w", err)
+			return nil, 0, err
 		}
+		results = append(results, result)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit conversation: %w", err)
+		return nil, 0, fmt.Errorf("commit batch join decision: %w", err)
 	}
+	r.signalOutbox()
+	return results, convo.ID, nil
+}
 
-	conversation := &Conversation{ID: convoID, CreatedBy: createdBy}
-	if nullableTitle.Valid {
-		value := nullableTitle.String
-		conversation.Title = &value
-	}
-	if nullableEventID.Valid {
-		value := nullableEventID.Int64
-		conversation.EventID = &value
+// RemoveEventMember drops a user from the event chat on actorID's behalf:
+// anyone may leave, the host may remove anyone else, and co-hosts may remove
+// regular members. When the event has a capacity, the oldest waitlisted
+// request is promoted into the freed spot and returned so the caller can
+// notify the promoted user; otherwise it is nil. With ban set the user is also
+// kept from requesting to join again until unbanned.
+func (r *EventRepository) RemoveEventMember(ctx context.Context, eventID, userID, actorID int64, ban bool) (*ConversationJoinRequest, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
 	}
-
-	row := r.db.QueryRowContext(ctx, "SELECT created_at FROM conversations WHERE id = ?", convoID)
-	if err := row.Scan(&conversation.CreatedAt); err != nil {
-		return nil, fmt.Errorf("fetch conversation created_at: %w", err)
+	if event.UserID == userID {
+		return nil, ErrCannotRemoveHost
 	}
 
-	return conversation, nil
-}
-
-// ListConversations returns all conversations visible to the user, hydrated with participants and unread counts.
-func (r *EventRepository) ListConversations(ctx context.Context, userID int64) ([]ConversationSummary, error) {
-	rows, err := r.db.QueryContext(ctx, selectConversationsForUser, userID)
+	convo, err := r.GetConversationByEventID(ctx, eventID)
 	if err != nil {
-		return nil, fmt.Errorf("list conversations: %w", err)
+		return nil, err
 	}
 
-	var conversations []Conversation
-	for rows.Next() {
-		var convo Conversation
-		var title sql.NullString
-		var eventID sql.NullInt64
-		if err := rows.Scan(&convo.ID, &title, &convo.CreatedBy, &convo.CreatedAt, &eventID); err != nil {
-			rows.Close()
-			return nil, fmt.Errorf("scan conversation: %w", err)
+	if actorID != userID && actorID != event.UserID {
+		if err := checkEventModerator(ctx, r.db, event, convo.ID, actorID); err != nil {
+			return nil, err
 		}
-		if title.Valid {
-			value := title.String
-			convo.Title = &value
+		// Only the host can remove a fellow co-host.
+		role, err := memberRole(ctx, r.db, convo.ID, userID)
+		if err != nil {
+			return nil, err
 		}
-		if eventID.Valid {
-			value := eventID.Int64
-			convo.EventID = &value
+		if role == roleCoHost {
+			return nil, ErrNotEventHost
 		}
-		conversations = append(conversations, convo)
 	}
-	if err := rows.Err(); err != nil {
-		rows.Close()
-		return nil, fmt.Errorf("iterate conversations: %w", err)
+
+	isMember, err := r.IsConversationMember(ctx, convo.ID, userID)
+	if err != nil {
+		return nil, err
 	}
-	if err := rows.Close(); err != nil {
-		return nil, fmt.Errorf("close conversations rows: %w", err)
+	if !isMember {
+		return nil, ErrNotConversationMember
 	}
 
-	summaries := make([]ConversationSummary, 0, len(conversations))
-	for _, convo := range conversations {
-		summary, err := r.hydrateConversationSummary(ctx, convo, userID)
-		if err != nil {
-			return nil, err
-		}
-		summaries = append(summaries, summary)
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin remove member tx: %w", err)
 	}
 
-	return summaries, nil
-}
-
-// ListMessages paginates messages for a given conversation.
-func (r *EventRepository) ListMessages(ctx context.Context, conversationID int64, limit, offset int) ([]Message, error) {
-	if limit <= 0 {
-		limit = 20
+	if _, err := tx.ExecContext(ctx, deleteConversationMember, convo.ID, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete conversation member: %w", err)
 	}
-	if offset < 0 {
-		offset = 0
+
+	if _, err := tx.ExecContext(ctx, deleteConversationReadState, convo.ID, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete conversation read state: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, selectMessagesForConversation, conversationID, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("list messages: %w", err)
+	if ban {
+		if _, err := tx.ExecContext(ctx, insertEventBan, eventID, userID, actorID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert event ban: %w", err)
+		}
 	}
-	defer rows.Close()
 
-	var messages []Message
-	for rows.Next() {
-		var msg Message
-		var attachment sql.NullString
-		if err := rows.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body, &attachment, &msg.DeliveryStatus, &msg.CreatedAt); err != nil {
-			return nil, fmt.Errorf("scan message: %w", err)
+	var promotedID int64
+	if event.Capacity != nil {
+		next, err := scanJoinRequest(tx.QueryRowContext(ctx, selectNextWaitlistedJoinRequest, eventID))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			tx.Rollback()
+			return nil, fmt.Errorf("fetch next waitlisted request: %w", err)
 		}
-		if attachment.Valid {
-			msg.AttachmentURL = &attachment.String
+		if next != nil {
+			full, err := isEventFull(ctx, tx, event, convo.ID)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if !full {
+				if err := admitJoinRequest(ctx, tx, next, convo.ID, nil); err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				promotedID = next.ID
+			}
 		}
-		messages = append(messages, msg)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate messages: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit remove member: %w", err)
 	}
 
-	return messages, nil
-}
-
-// CreateMessage stores a new message and returns the saved row for broadcasting.
-func (r *EventRepository) CreateMessage(ctx context.Context, params CreateMessageParams) (*Message, error) {
-	attachment := sql.NullString{}
-	if params.AttachmentURL != nil {
-		attachment = sql.NullString{String: *params.AttachmentURL, Valid: true}
+	if promotedID == 0 {
+		return nil, nil
 	}
+	return fetchJoinRequestByID(ctx, r.db, promotedID)
+}
 
-	var msg Message
-	row := r.db.QueryRowContext(ctx, insertMessage, params.ConversationID, params.SenderID, params.Body, attachment, params.DeliveryStatus)
-	var attachmentOut sql.NullString
-	if err := row.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body, &attachmentOut, &msg.DeliveryStatus, &msg.CreatedAt); err != nil {
-		return nil, fmt.Errorf("insert message: %w", err)
-	}
-	if attachmentOut.Valid {
-		msg.AttachmentURL = &attachmentOut.String
+// PromoteWaitlistedUser lets the host or a co-host admit a waitlisted requester directly
+// into the event chat, provided a spot is free.
+func (r *EventRepository) PromoteWaitlistedUser(ctx context.Context, eventID, userID, hostID int64) (*ConversationJoinRequest, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
 	}
-	return &msg, nil
-}
 
-func scanJoinRequest(row *sql.Row) (*ConversationJoinRequest, error) {
-	var req ConversationJoinRequest
-	var decidedAt sql.NullTime
-	var decidedBy sql.NullInt64
-	if err := row.Scan(&req.ID, &req.EventID, &req.UserID, &req.Status, &req.CreatedAt, &decidedAt, &decidedBy); err != nil {
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
 		return nil, err
 	}
-	if decidedAt.Valid {
-		t := decidedAt.Time
-		req.DecidedAt = &t
+	if err := checkEventModerator(ctx, r.db, event, convo.ID, hostID); err != nil {
+		return nil, err
 	}
-	if decidedBy.Valid {
-		id := decidedBy.Int64
-		req.DecidedBy = &id
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin promote waitlist tx: %w", err)
 	}
-	return &req, nil
-}
 
-func fetchJoinRequestByID(ctx context.Context, q rowQuery, id int64) (*ConversationJoinRequest, error) {
-	req, err := scanJoinRequest(q.QueryRowContext(ctx, selectJoinRequestByID, id))
+	req, err := scanJoinRequest(tx.QueryRowContext(ctx, selectWaitlistedJoinRequest, eventID, userID))
 	if err != nil {
+		tx.Rollback()
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrJoinRequestNotFound
 		}
-		return nil, fmt.Errorf("fetch join request: %w", err)
+		return nil, fmt.Errorf("fetch waitlisted join request: %w", err)
 	}
-	return req, nil
-}
 
-func fetchConversationByEventID(ctx context.Context, q rowQuery, eventID int64) (*Conversation, error) {
-	row := q.QueryRowContext(ctx, selectConversationByEventID, eventID)
-	var convo Conversation
-	var title sql.NullString
-	var eventIDValue sql.NullInt64
-	if err := row.Scan(&convo.ID, &title, &convo.CreatedBy, &convo.CreatedAt, &eventIDValue); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrConversationNotFound
-		}
-		return nil, fmt.Errorf("fetch conversation by event: %w", err)
+	full, err := isEventFull(ctx, tx, event, convo.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
-	if title.Valid {
-		value := title.String
-		convo.Title = &value
+	if full {
+		tx.Rollback()
+		return nil, ErrEventFull
 	}
-	if eventIDValue.Valid {
-		value := eventIDValue.Int64
-		convo.EventID = &value
+
+	if err := admitJoinRequest(ctx, tx, req, convo.ID, &hostID); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
-	return &convo, nil
-}
 
-func (r *EventRepository) GetEventByID(ctx context.Context, eventID int64) (*Event, error) {
-	row := r.db.QueryRowContext(ctx, selectEventByID, eventID)
-	var evt Event
-	if err := row.Scan(
-		&evt.ID,
-		&evt.UserID,
-		&evt.Title,
-		&evt.Location,
-		&evt.Time,
-		&evt.Description,
-		&evt.Gender,
-		&evt.MinAge,
-		&evt.MaxAge,
-		&evt.DateLabel,
-		&evt.CreatedAt,
-		&evt.HostName,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrEventNotFound
-		}
-		return nil, fmt.Errorf("fetch event: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit waitlist promotion: %w", err)
 	}
-	return &evt, nil
-}
 
-func (r *EventRepository) GetConversationByEventID(ctx context.Context, eventID int64) (*Conversation, error) {
-	return fetchConversationByEventID(ctx, r.db, eventID)
+	return fetchJoinRequestByID(ctx, r.db, req.ID)
 }
 
-func (r *EventRepository) CreateJoinRequest(ctx context.Context, eventID, userID int64) (*ConversationJoinRequest, error) {
+// ListJoinRequests returns the host's (or a co-host's) view of requests in a
+// given status (pending or waitlisted), oldest first.
+func (r *EventRepository) ListJoinRequests(ctx context.Context, eventID, hostID int64, status string) ([]ConversationJoinRequest, error) {
 	event, err := r.GetEventByID(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
-	if event.UserID == userID {
-		return nil, ErrAlreadyConversationMember
+	if event.UserID != hostID {
+		convo, err := r.GetConversationByEventID(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkEventModerator(ctx, r.db, event, convo.ID, hostID); err != nil {
+			return nil, err
+		}
 	}
 
-	convo, err := r.GetConversationByEventID(ctx, eventID)
+	rows, err := r.db.QueryContext(ctx, selectJoinRequestsForEvent, eventID, status)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("list join requests: %w", err)
 	}
+	defer rows.Close()
 
-	isMember, err := r.IsConversationMember(ctx, convo.ID, userID)
-	if err != nil {
-		return nil, err
+	requests := []ConversationJoinRequest{}
+	for rows.Next() {
+		req, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan join request: %w", err)
+		}
+		requests = append(requests, *req)
 	}
-	if isMember {
-		return nil, ErrAlreadyConversationMember
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate join requests: %w", err)
 	}
+	return requests, nil
+}
 
-	if _, err := scanJoinRequest(r.db.QueryRowContext(ctx, selectPendingJoinRequest, eventID, userID)); err == nil {
-		return nil, ErrJoinRequestExists
-	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("check pending join request: %w", err)
+// checkEventModerator returns ErrNotEventHost unless userID hosts the event or
+// is a co-host in its chat.
+func checkEventModerator(ctx context.Context, q rowQuery, event *Event, conversationID, userID int64) error {
+	if event.UserID == userID {
+		return nil
 	}
-
-	res, err := r.db.ExecContext(ctx, insertJoinRequest, eventID, userID)
+	role, err := memberRole(ctx, q, conversationID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("insert join request: %w", err)
+		return err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("fetch join request id: %w", err)
+	if role != roleCoHost {
+		return ErrNotEventHost
 	}
-	return fetchJoinRequestByID(ctx, r.db, id)
+	return nil
 }
 
-func (r *EventRepository) ApproveJoinRequest(ctx context.Context, eventID, userID, approverID int64) (*ConversationJoinRequest, error) {
-	event, err := r.GetEventByID(ctx, eventID)
-	if err != nil {
-		return nil, err
-	}
-	if event.UserID != approverID {
-		return nil, ErrNotEventHost
+// memberRole returns userID's role in the conversation, or "" for non-members.
+func memberRole(ctx context.Context, q rowQuery, conversationID, userID int64) (string, error) {
+	var role string
+	if err := q.QueryRowContext(ctx, selectConversationMemberRole, conversationID, userID).Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("fetch member role: %w", err)
 	}
+	return role, nil
+}
 
-	convo, err := r.GetConversationByEventID(ctx, eventID)
+// SetEventCoHost promotes an event chat member to co-host or demotes them back
+// to a regular member. Only the host may do this. It returns the event chat's
+// ID.
+func (r *EventRepository) SetEventCoHost(ctx context.Context, eventID, hostID, userID int64, coHost bool) (int64, error) {
+	event, err := r.GetEventByID(ctx, eventID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-
-	isMember, err := r.IsConversationMember(ctx, convo.ID, userID)
-	if err != nil {
-		return nil, err
+	if event.UserID != hostID {
+		return 0, ErrNotEventHost
 	}
-	if isMember {
-		return nil, ErrAlreadyConversationMember
+	if userID == hostID {
+		return 0, ErrCannotRemoveHost
 	}
-
-	tx, err := r.db.BeginTx(ctx, nil)
+	convo, err := r.GetConversationByEventID(ctx, eventID)
 	if err != nil {
-		return nil, fmt.Errorf("begin approve join tx: %w", err)
+		return 0, err
 	}
 
-	req, err := scanJoinRequest(tx.QueryRowContext(ctx, selectPendingJoinRequest, eventID, userID))
+	role := roleMember
+	if coHost {
+		role = roleCoHost
+	}
+	result, err := r.db.ExecContext(ctx, updateConversationMemberRole, role, convo.ID, userID)
 	if err != nil {
-		tx.Rollback()
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrJoinRequestNotFound
-		}
-		return nil, fmt.Errorf("fetch pending join request: %w", err)
+		return 0, fmt.Errorf("update member role: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("check role rows affected: %w", err)
+	} else if n == 0 {
+		return 0, ErrNotConversationMember
 	}
+	return convo.ID, nil
+}
 
-	if _, err := tx.ExecContext(ctx, updateJoinRequestStatus, "approved", approverID, req.ID); err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("approve join request: %w", err)
+// admitJoinRequest marks a request approved and adds the requester to the
+// conversation. decidedBy is nil for automatic promotions.
+func admitJoinRequest(ctx context.Context, tx *sql.Tx, req *ConversationJoinRequest, conversationID int64, decidedBy *int64) error {
+	var decider sql.NullInt64
+	if decidedBy != nil {
+		decider = sql.NullInt64{Int64: *decidedBy, Valid: true}
 	}
-	if _, err := tx.ExecContext(ctx, insertConversationMember, convo.ID, userID, "member"); err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("add conversation member: %w", err)
+	if _, err := tx.ExecContext(ctx, updateJoinRequestStatus, "approved", decider, req.ID); err != nil {
+		return fmt.Errorf("approve join request: %w", err)
 	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit join approval: %w", err)
+	if _, err := tx.ExecContext(ctx, insertConversationMember, conversationID, req.UserID, "member"); err != nil {
+		return fmt.Errorf("add conversation member: %w", err)
 	}
-
-	return fetchJoinRequestByID(ctx, r.db, req.ID)
+	return nil
 }
 
-func (r *EventRepository) DenyJoinRequest(ctx context.Context, eventID, userID, approverID int64) (*ConversationJoinRequest, error) {
-	event, err := r.GetEventByID(ctx, eventID)
-	if err != nil {
-		return nil, err
+// isEventFull reports whether the event chat has reached its capacity. The
+// host counts towards capacity; events without one are never full.
+func isEventFull(ctx context.Context, q rowQuery, event *Event, conversationID int64) (bool, error) {
+	if event.Capacity == nil {
+		return false, nil
 	}
-	if event.UserID != approverID {
-		return nil, ErrNotEventHost
+	var count int
+	if err := q.QueryRowContext(ctx, countConversationMembers, conversationID).Scan(&count); err != nil {
+		return false, fmt.Errorf("count conversation members: %w", err)
 	}
+	return count >= *event.Capacity, nil
+}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+// resolveEventStart turns the relative Today/Tmrw label plus an HH:MM clock
+// time into an absolute start, anchored on reference in the server's local
+// time zone (TZ). Unparseable times report false.
+func resolveEventStart(reference time.Time, dateLabel, clock string) (time.Time, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(clock))
 	if err != nil {
-		return nil, fmt.Errorf("begin deny join tx: %w", err)
+		return time.Time{}, false
+	}
+	day := reference.In(time.Local)
+	if dateLabel == "Tmrw" {
+		day = day.AddDate(0, 0, 1)
 	}
+	return time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), 0, 0, time.Local), true
+}
 
-	req, err := scanJoinRequest(tx.QueryRowContext(ctx, selectPendingJoinRequest, eventID, userID))
-	if err != nil {
-		tx.Rollback()
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrJoinRequestNotFound
-		}
-		return nil, fmt.Errorf("fetch pending join request: %w", err)
+// nullableEventStart resolves an event start into a DATETIME parameter.
+func nullableEventStart(reference time.Time, dateLabel, clock string) sql.NullString {
+	start, ok := resolveEventStart(reference, dateLabel, clock)
+	if !ok {
+		return sql.NullString{}
 	}
+	return sql.NullString{String: sqliteTime(start), Valid: true}
+}
 
-	if _, err := tx.ExecContext(ctx, updateJoinRequestStatus, "denied", approverID, req.ID); err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("deny join request: %w", err)
+// sqliteTime formats t like CURRENT_TIMESTAMP (UTC, second precision) so
+// stored values compare correctly as text.
+func sqliteTime(t time.Time) string {
+	return maintenance.FormatTime(t)
+}
+
+// nullablePlace splits a geocoding result into nullable column parameters.
+func nullablePlace(place *EventPlace) (sql.NullFloat64, sql.NullFloat64, sql.NullString) {
+	if place == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}, sql.NullString{}
 	}
+	return sql.NullFloat64{Float64: place.Latitude, Valid: true},
+		sql.NullFloat64{Float64: place.Longitude, Valid: true},
+		sql.NullString{String: place.PlaceName, Valid: place.PlaceName != ""}
+}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit join denial: %w", err)
+// nullableInt maps an optional int onto a nullable SQL parameter.
+func nullableInt(value *int) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
 	}
+	return sql.NullInt64{Int64: int64(*value), Valid: true}
+}
 
-	return fetchJoinRequestByID(ctx, r.db, req.ID)
+// nullableString stores empty strings as NULL.
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
 }
 
-func (r *EventRepository) RemoveEventMember(ctx context.Context, eventID, userID int64) error {
-	event, err := r.GetEventByID(ctx, eventID)
-	if err != nil {
-		return err
-	}
-	if event.UserID == userID {
-		return ErrCannotRemoveHost
+// ListEventMembers returns the roster of an event's group chat. Only current
+// members (the host included) may view it.
+func (r *EventRepository) ListEventMembers(ctx context.Context, eventID, viewerID int64) ([]EventMember, error) {
+	if _, err := r.GetEventByID(ctx, eventID); err != nil {
+		return nil, err
 	}
 
 	convo, err := r.GetConversationByEventID(ctx, eventID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	isMember, err := r.IsConversationMember(ctx, convo.ID, userID)
+	isMember, err := r.IsConversationMember(ctx, convo.ID, viewerID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !isMember {
-		return ErrNotConversationMember
+		return nil, ErrNotConversationMember
 	}
+	return r.conversationRoster(ctx, convo.ID)
+}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+// conversationRoster lists a conversation's members without checking who is
+// asking.
+func (r *EventRepository) conversationRoster(ctx context.Context, conversationID int64) ([]EventMember, error) {
+	rows, err := r.db.QueryContext(ctx, selectMemberDetailsForConversation, conversationID)
 	if err != nil {
-		return fmt.Errorf("begin remove member tx: %w", err)
+		return nil, fmt.Errorf("list event members: %w", err)
 	}
+	defer rows.Close()
 
-	if _, err := tx.ExecContext(ctx, deleteConversationMember, convo.ID, userID); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("delete conversation member: %w", err)
+	members := []EventMember{}
+	for rows.Next() {
+		var member EventMember
+		if err := rows.Scan(&member.UserID, &member.Name, &member.Role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("scan event member: %w", err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate event members: %w", err)
 	}
 
-	if _, err := tx.ExecContext(ctx, deleteConversationReadState, convo.ID, userID); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("delete conversation read state: %w", err)
+	return members, nil
+}
+
+// GetConversationDetail loads a conversation as seen by viewerID along with
+// every member's read cursor.
+func (r *EventRepository) GetConversationDetail(ctx context.Context, conversationID, viewerID int64) (*ConversationDetail, error) {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := r.HydrateConversationSummary(ctx, *convo, viewerID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit remove member: %w", err)
+	rows, err := r.db.QueryContext(ctx, selectMemberReadStatesForConversation, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list member read states: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	detail := &ConversationDetail{ConversationSummary: summary, Members: []ConversationMemberState{}}
+	for rows.Next() {
+		var member ConversationMemberState
+		var lastRead sql.NullInt64
+		if err := rows.Scan(&member.UserID, &member.Name, &member.Role, &member.JoinedAt, &lastRead); err != nil {
+			return nil, fmt.Errorf("scan member read state: %w", err)
+		}
+		if lastRead.Valid {
+			id := lastRead.Int64
+			member.LastReadMessageID = &id
+		}
+		detail.Members = append(detail.Members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate member read states: %w", err)
+	}
+
+	return detail, nil
 }
 
-// hydrateConversationSummary enriches a conversation with participant info and unread counts for the viewer.
-func (r *EventRepository) hydrateConversationSummary(ctx context.Context, convo Conversation, viewerID int64) (ConversationSummary, error) {
-	participants, memberIDs, err := r.fetchConversationParticipants(ctx, convo.ID)
+// HydrateConversationSummary enriches a conversation with participant info and unread counts for the viewer.
+func (r *EventRepository) HydrateConversationSummary(ctx context.Context, convo Conversation, viewerID int64) (ConversationSummary, error) {
+	participants, memberIDs, err := fetchConversationParticipants(ctx, r.db, convo.ID)
 	if err != nil {
 		return ConversationSummary{}, err
 	}
@@ -1027,6 +2910,11 @@ func (r *EventRepository) hydrateConversationSummary(ctx context.Context, convo
 		return ConversationSummary{}, err
 	}
 
+	mutedUntil, archivedByViewer, err := r.conversationSettings(ctx, convo.ID, viewerID)
+	if err != nil {
+		return ConversationSummary{}, err
+	}
+
 	var eventMeta *ConversationEventMeta
 	if convo.EventID != nil {
 		evt, err := r.GetEventByID(ctx, *convo.EventID)
@@ -1051,6 +2939,8 @@ func (r *EventRepository) hydrateConversationSummary(ctx context.Context, convo
 		Participants: participants,
 		Event:        eventMeta,
 		UnreadCount:  unreadCount,
+		MutedUntil:   mutedUntil,
+		Archived:     archivedByViewer || convo.ArchivedAt != nil,
 	}
 	if lastMessage != nil {
 		summary.LastMessage = lastMessage
@@ -1059,8 +2949,8 @@ func (r *EventRepository) hydrateConversationSummary(ctx context.Context, convo
 }
 
 // fetchConversationParticipants returns the members of a conversation plus their IDs for fast lookup.
-func (r *EventRepository) fetchConversationParticipants(ctx context.Context, conversationID int64) ([]ConversationParticipant, []int64, error) {
-	rows, err := r.db.QueryContext(ctx, selectParticipantsForConversation, conversationID)
+func fetchConversationParticipants(ctx context.Context, q rowsQuery, conversationID int64) ([]ConversationParticipant, []int64, error) {
+	rows, err := q.QueryContext(ctx, selectParticipantsForConversation, conversationID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("list conversation participants: %w", err)
 	}
@@ -1070,7 +2960,7 @@ func (r *EventRepository) fetchConversationParticipants(ctx context.Context, con
 	var memberIDs []int64
 	for rows.Next() {
 		var participant ConversationParticipant
-		if err := rows.Scan(&participant.ID, &participant.Name); err != nil {
+		if err := rows.Scan(&participant.ID, &participant.Name, &participant.Verified); err != nil {
 			return nil, nil, fmt.Errorf("scan conversation participant: %w", err)
 		}
 		participants = append(participants, participant)
@@ -1085,35 +2975,59 @@ func (r *EventRepository) fetchConversationParticipants(ctx context.Context, con
 
 // fetchLatestMessage grabs the newest message so we can show previews/unread counts.
 func (r *EventRepository) fetchLatestMessage(ctx context.Context, conversationID int64) (*MessageSummary, error) {
-	row := r.db.QueryRowContext(ctx, selectLatestMessageForConversation, conversationID)
+	stmt, err := r.prepared(ctx, selectLatestMessageForConversation)
+	if err != nil {
+		return nil, err
+	}
+	row := stmt.QueryRowContext(ctx, conversationID)
 
-	var msg Message
+	var summary MessageSummary
 	var attachment sql.NullString
-	if err := row.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body, &attachment, &msg.DeliveryStatus, &msg.CreatedAt); err != nil {
+	if err := row.Scan(&summary.ID, &summary.SenderID, &summary.Body, &attachment, &summary.Kind, &summary.CreatedAt, &summary.SenderName, &summary.SenderDeleted); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("fetch latest message: %w", err)
 	}
-
-	summary := &MessageSummary{
-		ID:        msg.ID,
-		SenderID:  msg.SenderID,
-		Body:      msg.Body,
-		CreatedAt: msg.CreatedAt,
+	if attachment.Valid {
+		summary.AttachmentType = attachmentType(attachment.String)
 	}
+	summary.System = summary.Kind == messageKindSystem
 
-	return summary, nil
+	return &summary, nil
+}
+
+// attachmentType classifies an attachment by its URL's file extension so
+// previews can show "Photo" rather than a link.
+func attachmentType(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	switch strings.ToLower(path.Ext(url)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic":
+		return "image"
+	case ".mp4", ".mov", ".webm":
+		return "video"
+	case ".mp3", ".m4a", ".aac", ".wav", ".ogg":
+		return "audio"
+	default:
+		return "file"
+	}
 }
 
-// countUnreadMessages uses the stored read cursor to compute unread totals.
+// countUnreadMessages reads the member's maintained counter, recounting from
+// the stored read cursor when it is missing or has drifted.
 func (r *EventRepository) countUnreadMessages(ctx context.Context, conversationID, userID int64, lastMessage *MessageSummary) (int, error) {
 	if lastMessage == nil {
 		return 0, nil
 	}
 
+	cursor, err := r.prepared(ctx, selectReadCursor)
+	if err != nil {
+		return 0, err
+	}
 	var lastReadID sql.NullInt64
-	err := r.db.QueryRowContext(ctx, "SELECT last_read_message_id FROM conversation_read_state WHERE conversation_id = ? AND user_id = ?", conversationID, userID).Scan(&lastReadID)
+	err = cursor.QueryRowContext(ctx, conversationID, userID).Scan(&lastReadID)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return 0, fmt.Errorf("fetch read cursor: %w", err)
 	}
@@ -1122,20 +3036,16 @@ func (r *EventRepository) countUnreadMessages(ctx context.Context, conversationI
 		return 0, nil
 	}
 
-	var count int
-	query := "SELECT COUNT(1) FROM messages WHERE conversation_id = ? AND id > ?"
-	threshold := int64(0)
-	if lastReadID.Valid {
-		threshold = lastReadID.Int64
-	}
-	if err := r.db.QueryRowContext(ctx, query, conversationID, threshold).Scan(&count); err != nil {
-		return 0, fmt.Errorf("count unread messages: %w", err)
+	count, ok, err := r.cachedUnreadCount(ctx, conversationID, userID, lastReadID.Int64, lastMessage.ID)
+	if err != nil || ok {
+		return count, err
 	}
-
-	return count, nil
+	return r.recountUnread(ctx, conversationID, userID)
 }
 
 // UpdateReadState advances a user's read cursor for a conversation.
+// UpdateReadState advances the user's read cursor; it never moves backwards,
+// so reading an older page doesn't mark newer messages unread again.
 func (r *EventRepository) UpdateReadState(ctx context.Context, conversationID, userID, lastReadMessageID int64) error {
 	if lastReadMessageID <= 0 {
 		return nil
@@ -1146,359 +3056,214 @@ func (r *EventRepository) UpdateReadState(ctx context.Context, conversationID, u
 	return nil
 }
 
-func (r *EventRepository) IsConversationMember(ctx context.Context, conversationID, userID int64) (bool, error) {
+// MarkConversationRead moves the user's read cursor up to messageID, which
+// must belong to the conversation, and returns the remaining unread count.
+func (r *EventRepository) MarkConversationRead(ctx context.Context, conversationID, userID, messageID int64) (int, error) {
 	var exists int
-	if err := r.db.QueryRowContext(ctx, checkConversationMembership, conversationID, userID).Scan(&exists); err != nil {
+	if err := r.db.QueryRowContext(ctx, checkMessageInConversation, messageID, conversationID).Scan(&exists); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
+			return 0, ErrMessageNotFound
 		}
-		return false, fmt.Errorf("check conversation membership: %w", err)
-	}
-	return true, nil
-}
-
-var seedEvents = []CreateEventParams{
-	{
-		UserID:      1,
-		Title:       "Running Buddy",
-		Location:    "Phoenix Park",
-		Time:        "09:00",
-		Description: "Morning run followed by coffee.",
-		Gender:      "Any",
-		MinAge:      20,
-		MaxAge:      30,
-		DateLabel:   "Today",
-	},
-	{
-		UserID:      2,
-		Title:       "Live Music Night",
-		Location:    "Workmans Club",
-		Time:        "20:00",
-		Description: "Indie bands and craft beers.",
-		Gender:      "Female",
-		MinAge:      22,
-		MaxAge:      32,
-		DateLabel:   "Today",
-	},
-	{
-		UserID:      3,
-		Title:       "Trail Hike",
-		Location:    "Howth Cliffs",
-		Time:        "10:00",
-		Description: "Scenic hike with lunch after.",
-		Gender:      "Any",
-		MinAge:      18,
-		MaxAge:      40,
-		DateLabel:   "Tmrw",
-	},
-	{
-		UserID:      1,
-		Title:       "Community Potluck",
-		Location:    "Docklands Hub",
-		Time:        "19:00",
-		Description: "Bring a dish and meet new neighbours.",
-		Gender:      "Any",
-		MinAge:      21,
-		MaxAge:      45,
-		DateLabel:   "Tmrw",
-	},
-	{
-		UserID:      2,
-		Title:       "Indie Film Screening",
-		Location:    "Lightbox Cinema",
-		Time:        "21:30",
-		Description: "Private screening of festival favourites.",
-		Gender:      "Any",
-		MinAge:      23,
-		MaxAge:      38,
-		DateLabel:   "Today",
-	},
-}
-
-func (r *EventRepository) EnsureSeedData(ctx context.Context) error {
-	if err := r.ensureSeedUsers(ctx); err != nil {
-		return err
-	}
-	if err := r.ensureEventsUserIDColumn(ctx); err != nil {
-		return err
-	}
-	if err := r.ensureSeedEvents(ctx); err != nil {
-		return err
+		return 0, fmt.Errorf("check message: %w", err)
 	}
-	if err := r.ensureSeedConversations(ctx); err != nil {
-		return err
+	if err := r.UpdateReadState(ctx, conversationID, userID, messageID); err != nil {
+		return 0, err
 	}
-	return r.ensureSeedEventGroupChat(ctx)
-}
-
-type seedUser struct {
-	Name     string
-	Email    string
-	Password string
-}
 
-var seedUsers = []seedUser{
-	{
-		Name:     "Ava Johnson",
-		Email:    "ava@example.com",
-		Password: "password123",
-	},
-	{
-		Name:     "Liam Patel",
-		Email:    "liam@example.com",
-		Password: "welcome123",
-	},
-	{
-		Name:     "Sophia Chen",
-		Email:    "sophia@example.com",
-		Password: "secret123",
-	},
-	{
-		Name:     "Noah Smith",
-		Email:    "noah@example.com",
-		Password: "sunset123",
-	},
-}
-
-func (r *EventRepository) ensureSeedUsers(ctx context.Context) error {
-	var count int
-	if err := r.db.QueryRowContext(ctx, countUsers).Scan(&count); err != nil {
-		return fmt.Errorf("count users: %w", err)
+	lastMessage, err := r.fetchLatestMessage(ctx, conversationID)
+	if err != nil {
+		return 0, err
 	}
+	return r.countUnreadMessages(ctx, conversationID, userID, lastMessage)
+}
 
-	if count > 0 {
-		return nil
+func (r *EventRepository) IsConversationMember(ctx context.Context, conversationID, userID int64) (bool, error) {
+	stmt, err := r.prepared(ctx, checkConversationMembership)
+	if err != nil {
+		return false, err
 	}
-
-	for _, user := range seedUsers {
-		if _, err := r.db.ExecContext(ctx, insertUser, user.Name, user.Email, user.Password); err != nil {
-			return fmt.Errorf("seed user %q: %w", user.Email, err)
+	var exists int
+	if err := stmt.QueryRowContext(ctx, conversationID, userID).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
 		}
+		return false, fmt.Errorf("check conversation membership: %w", err)
 	}
-
-	return nil
+	return true, nil
 }
 
-func (r *EventRepository) ensureSeedEvents(ctx context.Context) error {
-	var count int
-	if err := r.db.QueryRowContext(ctx, countEvents).Scan(&count); err != nil {
-		return fmt.Errorf("count events: %w", err)
-	}
-
-	if count > 0 {
-		return nil
-	}
-
-	for _, evt := range seedEvents {
-		if _, err := r.Create(ctx, evt); err != nil {
-			return fmt.Errorf("seed event %q: %w", evt.Title, err)
+// IsUserDeleted reports whether the account behind a session has been erased,
+// which revokes every token issued to it. Unknown users count as deleted.
+func (r *EventRepository) IsUserDeleted(ctx context.Context, userID int64) (bool, error) {
+	var deletedAt sql.NullTime
+	if err := r.db.QueryRowContext(ctx, selectUserDeletedAt, userID).Scan(&deletedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
 		}
+		return false, fmt.Errorf("check user deletion: %w", err)
 	}
-
-	return nil
+	return deletedAt.Valid, nil
 }
 
-func (r *EventRepository) ensureSeedConversations(ctx context.Context) error {
-	var count int
-	if err := r.db.QueryRowContext(ctx, countConversations).Scan(&count); err != nil {
-		return fmt.Errorf("count conversations: %w", err)
-	}
-
-	alreadySeeded := count > 0
-
-	rows, err := r.db.QueryContext(ctx, selectAllUsers)
+// DeleteUser soft-deletes an account: it cancels pending join requests, drops
+// every conversation membership and read cursor, deletes the account's
+// webhooks and their delivery log, and anonymizes the user row.
+// The IDs of the conversations the user was removed from are returned so the
+// caller can notify live sockets.
+func (r *EventRepository) DeleteUser(ctx context.Context, userID int64) ([]int64, error) {
+	tx, err := r.beginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("list users: %w", err)
+		return nil, fmt.Errorf("begin delete user tx: %w", err)
 	}
-	defer rows.Close()
 
-	type seedUserRecord struct {
-		ID   int64
-		Name string
+	rows, err := tx.QueryContext(ctx, selectConversationIDsForUser, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("list user conversations: %w", err)
 	}
-
-	var users []seedUserRecord
+	var conversationIDs []int64
 	for rows.Next() {
-		var record seedUserRecord
-		if err := rows.Scan(&record.ID, &record.Name); err != nil {
-			return fmt.Errorf("scan user: %w", err)
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, fmt.Errorf("scan user conversation: %w", err)
 		}
-		users = append(users, record)
+		conversationIDs = append(conversationIDs, id)
 	}
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate users: %w", err)
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("iterate user conversations: %w", err)
 	}
+	rows.Close()
 
-	if len(users) < 2 {
-		return nil
+	placeholderEmail := fmt.Sprintf("deleted-user-%d@deleted.invalid", userID)
+	result, err := tx.ExecContext(ctx, anonymizeUser, placeholderEmail, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("anonymize user: %w", err)
 	}
-
-	if !alreadySeeded {
-		sampleMessages := []string{
-			"Hey there! Want to sync up later?",
-			"Looking forward to catching up soon.",
-			"Should we plan something fun tonight?",
-		}
-
-		msgIndex := 0
-		for i := 0; i < len(users); i++ {
-			for j := i + 1; j < len(users); j++ {
-				pair := []int64{users[i].ID, users[j].ID}
-				convo, err := r.CreateConversation(ctx, nil, users[i].ID, pair, nil)
-				if err != nil {
-					return fmt.Errorf("seed direct conversation: %w", err)
-				}
-
-				intro := sampleMessages[msgIndex%len(sampleMessages)]
-				msgIndex++
-				if _, err = r.CreateMessage(ctx, CreateMessageParams{
-					ConversationID: convo.ID,
-					SenderID:       users[i].ID,
-					Body:           intro,
-					DeliveryStatus: "sent",
-				}); err != nil {
-					return fmt.Errorf("seed conversation message: %w", err)
-				}
-
-				reply := fmt.Sprintf("Hi %s! Count me in.", users[i].Name)
-				replyMsg, err := r.CreateMessage(ctx, CreateMessageParams{
-					ConversationID: convo.ID,
-					SenderID:       users[j].ID,
-					Body:           reply,
-					DeliveryStatus: "sent",
-				})
-				if err != nil {
-					return fmt.Errorf("seed conversation reply: %w", err)
-				}
-
-				if err := r.UpdateReadState(ctx, convo.ID, users[i].ID, replyMsg.ID); err != nil {
-					return fmt.Errorf("seed read state sender: %w", err)
-				}
-				if err := r.UpdateReadState(ctx, convo.ID, users[j].ID, replyMsg.ID); err != nil {
-					return fmt.Errorf("seed read state recipient: %w", err)
-				}
-			}
-		}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("check anonymize rows affected: %w", err)
 	}
-
-	if len(users) >= 3 {
-		groupTitle := "Planning Crew"
-		var existingID int64
-		err := r.db.QueryRowContext(ctx, selectConversationByTitle, groupTitle).Scan(&existingID)
-		if err != nil {
-			if !errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("check existing group conversation: %w", err)
-			}
-
-			members := []int64{users[0].ID, users[1].ID, users[2].ID}
-			convo, err := r.CreateConversation(ctx, &groupTitle, users[0].ID, members, nil)
-			if err != nil {
-				return fmt.Errorf("seed group conversation: %w", err)
-			}
-
-			seedGroupMessages := []struct {
-				sender int64
-				body   string
-			}{
-				{sender: users[0].ID, body: "Team, let's sync here about weekend ideas."},
-				{sender: users[1].ID, body: "Love it. How about a hike followed by brunch?"},
-				{sender: users[2].ID, body: "Count me in! I can book a table if we pick a spot."},
-			}
-
-			var lastMsgID int64
-			for _, msg := range seedGroupMessages {
-				created, err := r.CreateMessage(ctx, CreateMessageParams{
-					ConversationID: convo.ID,
-					SenderID:       msg.sender,
-					Body:           msg.body,
-					DeliveryStatus: "sent",
-				})
-				if err != nil {
-					return fmt.Errorf("seed group conversation message: %w", err)
-				}
-				if created != nil {
-					lastMsgID = created.ID
-				}
-			}
-
-			if lastMsgID > 0 {
-				for _, member := range members {
-					if err := r.UpdateReadState(ctx, convo.ID, member, lastMsgID); err != nil {
-						return fmt.Errorf("seed group conversation read state: %w", err)
-					}
-				}
-			}
-		}
+	if rowsAffected == 0 {
+		tx.Rollback()
+		return nil, ErrUserNotFound
 	}
 
-	return nil
-}
-
-func (r *EventRepository) ensureSeedEventGroupChat(ctx context.Context) error {
-	convo, err := r.GetConversationByEventID(ctx, 1)
-	if err != nil {
-		if errors.Is(err, ErrConversationNotFound) || errors.Is(err, ErrEventNotFound) {
-			return nil
-		}
-		return err
+	if _, err := tx.ExecContext(ctx, cancelPendingJoinRequestsForUser, userID, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("cancel pending join requests: %w", err)
 	}
-
-	_, memberIDs, err := r.fetchConversationParticipants(ctx, convo.ID)
-	if err != nil {
-		return err
+	if _, err := tx.ExecContext(ctx, deleteConversationMembershipsForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete conversation memberships: %w", err)
 	}
-	if len(memberIDs) >= 4 {
-		return nil
+	if _, err := tx.ExecContext(ctx, deleteReadStateForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete read state: %w", err)
 	}
-
-	memberSet := make(map[int64]struct{}, len(memberIDs))
-	for _, id := range memberIDs {
-		memberSet[id] = struct{}{}
+	if _, err := tx.ExecContext(ctx, deleteBookmarksForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete bookmarks: %w", err)
 	}
-
-	additionalMembers := []int64{2, 3, 4}
-	for _, userID := range additionalMembers {
-		if _, ok := memberSet[userID]; ok {
-			continue
-		}
-		if _, err := r.db.ExecContext(ctx, insertConversationMember, convo.ID, userID, "member"); err != nil {
-			return fmt.Errorf("seed event group member %d: %w", userID, err)
-		}
+	if _, err := tx.ExecContext(ctx, deleteInterestsForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete interests: %w", err)
 	}
-
-	sampleMessages := []struct {
-		sender int64
-		body   string
-	}{
-		{sender: convo.CreatedBy, body: "Hey everyone! Use this chat to coordinate before the event."},
-		{sender: 2, body: "Thanks for adding me—looking forward to it."},
-		{sender: 3, body: "I'll bring snacks. Any allergy concerns?"},
-		{sender: 4, body: "I’m good with anything. See you all there!"},
+	if _, err := tx.ExecContext(ctx, deleteUserAvailability, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete availability: %w", err)
 	}
-
-	var lastMessageID int64
-	for _, msg := range sampleMessages {
-		created, err := r.CreateMessage(ctx, CreateMessageParams{
-			ConversationID: convo.ID,
-			SenderID:       msg.sender,
-			Body:           msg.body,
-			DeliveryStatus: "sent",
-		})
-		if err != nil {
-			return fmt.Errorf("seed event group message: %w", err)
-		}
-		if created != nil {
-			lastMessageID = created.ID
-		}
+	if _, err := tx.ExecContext(ctx, deleteConnectionsForUser, userID, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete connections: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteConversationSettingsForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete conversation settings: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deletePushTokensForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete push tokens: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteUserReminderSettings, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete reminder settings: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteUserNotificationSettings, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete notification settings: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deletePasswordResetTokensForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete password reset tokens: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteEmailDigestSentForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete email digests: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteUserIdentitiesForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete user identities: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteMagicLinksForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete magic links: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteSessionsForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete sessions: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deletePollVotesForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete poll votes: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteEventTimeSlotVotesForAccount, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete time poll availability: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteShadowBansForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete shadow bans: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteEventReviewsByUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete event reviews: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteEventCheckinsForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete event checkins: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteVerificationRequestsForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete verification requests: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteScheduledMessagesForUser, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete scheduled messages: %w", err)
+	}
+	if err := deleteBotsOwnedBy(ctx, tx, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, deleteWebhookDeliveriesForOwner, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteWebhooksForOwner, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("delete webhooks: %w", err)
 	}
 
-	if lastMessageID > 0 {
-		if err := r.UpdateReadState(ctx, convo.ID, convo.CreatedBy, lastMessageID); err != nil {
-			return fmt.Errorf("seed event group read state: %w", err)
-		}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit delete user: %w", err)
 	}
 
-	return nil
+	return conversationIDs, nil
 }
 
 func (r *EventRepository) AuthenticateUser(ctx context.Context, email, password string) (*User, error) {
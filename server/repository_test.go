@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"who-else-is-free-server/internal/maintenance"
+)
+
+// newTestRepository opens a migrated database in a temporary directory.
+func newTestRepository(t *testing.T) *EventRepository {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "test.sqlite"), 1)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	repo := NewEventRepository(db)
+	t.Cleanup(func() {
+		repo.Close()
+		db.Close()
+	})
+	if err := repo.Init(context.Background()); err != nil {
+		t.Fatalf("init repository: %v", err)
+	}
+	return repo
+}
+
+// mustCreateUser registers an account named name, failing the test on error.
+func mustCreateUser(t *testing.T, repo *EventRepository, name string) int64 {
+	t.Helper()
+	id, err := maintenance.CreateUser(context.Background(), repo.db, name, name+"@example.com", "password", false)
+	if err != nil {
+		t.Fatalf("create user %s: %v", name, err)
+	}
+	return id
+}
+
+// countRows returns the result of a COUNT(*) query.
+func countRows(t *testing.T, repo *EventRepository, query string, args ...any) int {
+	t.Helper()
+	var count int
+	if err := repo.db.QueryRow(query, args...).Scan(&count); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	return count
+}
+
+func TestDeleteUserRemovesWebhooks(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	hostID := mustCreateUser(t, repo, "ava")
+	otherID := mustCreateUser(t, repo, "liam")
+
+	params := CreateWebhookParams{URL: "https://hooks.example.com/in", Events: []string{webhookMessageCreated}}
+	if _, _, err := repo.CreateWebhook(ctx, hostID, params); err != nil {
+		t.Fatalf("create host webhook: %v", err)
+	}
+	if _, _, err := repo.CreateWebhook(ctx, otherID, params); err != nil {
+		t.Fatalf("create other webhook: %v", err)
+	}
+	if err := repo.FanOutWebhookEvent(ctx, 1, webhookMessageCreated, hostID, []byte(`{}`)); err != nil {
+		t.Fatalf("fan out: %v", err)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM webhook_deliveries`); got != 1 {
+		t.Fatalf("deliveries before delete = %d, want 1", got)
+	}
+
+	if _, err := repo.DeleteUser(ctx, hostID); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM webhooks WHERE user_id = ?`, hostID); got != 0 {
+		t.Errorf("webhooks left for deleted user = %d, want 0", got)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM webhook_deliveries`); got != 0 {
+		t.Errorf("deliveries left for deleted user = %d, want 0", got)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM webhooks WHERE user_id = ?`, otherID); got != 1 {
+		t.Errorf("other user's webhooks = %d, want 1", got)
+	}
+}
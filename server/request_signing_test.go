@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAppKey = "app-key"
+
+// signRequest builds the X-Request-Signature header a client signing with
+// secret at ts would send.
+func signRequest(secret string, ts time.Time, method, requestURI, body string) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(requestSigningPayload(unix, method, requestURI, []byte(body))))
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRequestVerifierVerify(t *testing.T) {
+	now := time.Unix(1_760_000_000, 0)
+	const uri = "/api/events?category=Running"
+	const body = `{"title":"Evening run"}`
+	signed := signRequest(testAppKey, now, http.MethodPost, uri, body)
+
+	tests := []struct {
+		name    string
+		header  string
+		method  string
+		uri     string
+		body    string
+		require bool
+		wantErr error
+	}{
+		{"valid", signed, http.MethodPost, uri, body, false, nil},
+		{"rotated key", signRequest("old-app-key", now, http.MethodPost, uri, body), http.MethodPost, uri, body, false, nil},
+		{"within skew", signRequest(testAppKey, now.Add(-requestSignatureTolerance+time.Second), http.MethodPost, uri, body), http.MethodPost, uri, body, false, nil},
+		{"wrong key", signRequest("guessed-key", now, http.MethodPost, uri, body), http.MethodPost, uri, body, false, errRequestSignature},
+		{"wrong signature", strings.Replace(signed, "v1=", "v1=00", 1), http.MethodPost, uri, body, false, errRequestSignature},
+		{"missing signature", "t=" + strconv.FormatInt(now.Unix(), 10), http.MethodPost, uri, body, false, errRequestSignature},
+		{"bad timestamp", "t=soon,v1=abc", http.MethodPost, uri, body, false, errRequestSignature},
+		{"too old", signRequest(testAppKey, now.Add(-requestSignatureTolerance-time.Second), http.MethodPost, uri, body), http.MethodPost, uri, body, false, errRequestSignatureTS},
+		{"too far ahead", signRequest(testAppKey, now.Add(requestSignatureTolerance+time.Second), http.MethodPost, uri, body), http.MethodPost, uri, body, false, errRequestSignatureTS},
+		{"changed body", signed, http.MethodPost, uri, `{"title":"Morning run"}`, false, errRequestSignature},
+		{"changed path", signed, http.MethodPost, "/api/events/1/join", body, false, errRequestSignature},
+		{"changed query", signed, http.MethodPost, "/api/events?category=Hiking", body, false, errRequestSignature},
+		{"changed method", signed, http.MethodPut, uri, body, false, errRequestSignature},
+		{"unsigned, optional", "", http.MethodPost, uri, body, false, nil},
+		{"unsigned, required", "", http.MethodPost, uri, body, true, errRequestUnsigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newRequestVerifier([]string{testAppKey, "old-app-key"}, tt.require)
+			err := verifier.verify(tt.header, tt.method, tt.uri, []byte(tt.body), now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequestVerifierRejectsReplays(t *testing.T) {
+	now := time.Unix(1_760_000_000, 0)
+	verifier := newRequestVerifier([]string{testAppKey}, true)
+	header := signRequest(testAppKey, now, http.MethodPost, "/api/auth/login", `{}`)
+
+	if err := verifier.verify(header, http.MethodPost, "/api/auth/login", []byte(`{}`), now); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := verifier.verify(header, http.MethodPost, "/api/auth/login", []byte(`{}`), now.Add(time.Second)); !errors.Is(err, errRequestReplayed) {
+		t.Fatalf("replay error = %v, want %v", err, errRequestReplayed)
+	}
+	// Pruning after the window must not let the signature back in: by then
+	// its timestamp is out of range.
+	later := now.Add(requestSignatureTolerance + 2*time.Minute)
+	if err := verifier.verify(header, http.MethodPost, "/api/auth/login", []byte(`{}`), later); !errors.Is(err, errRequestSignatureTS) {
+		t.Fatalf("late replay error = %v, want %v", err, errRequestSignatureTS)
+	}
+}
+
+func TestRequestSigningMiddleware(t *testing.T) {
+	const body = `{"email":"ana@example.com"}`
+	header := signRequest(testAppKey, time.Now(), http.MethodPost, "/api/auth/login", body)
+
+	router := gin.New()
+	router.Use(requestSigningMiddleware([]string{testAppKey}, true))
+	var received string
+	router.POST("/api/auth/login", func(c *gin.Context) {
+		var payload struct{ Email string }
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		received = payload.Email
+		c.Status(http.StatusOK)
+	})
+	send := func(signature, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set(requestSignatureHeader, signature)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The body the signature covers still reaches the handler.
+	assertStatus(t, send(header, body), http.StatusOK)
+	if received != "ana@example.com" {
+		t.Fatalf("handler read email %q, want the signed body", received)
+	}
+	assertStatus(t, send(header, body), http.StatusUnauthorized)
+	assertStatus(t, send(signRequest(testAppKey, time.Now(), http.MethodPost, "/api/auth/login", body), `{"email":"mallory@example.com"}`), http.StatusUnauthorized)
+	assertStatus(t, send("", body), http.StatusUnauthorized)
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Events have a start but no end time, so an event counts as over
+// eventAssumedLength after it starts; members can review it from then on.
+// Reviews keep the host they rated, so a later transfer doesn't move them,
+// and they outlive adminctl purges of old events so ratings don't drop as
+// history is pruned.
+const eventAssumedLength = 3 * time.Hour
+
+var ErrEventNotOver = errors.New("event hasn't ended yet")
+var ErrAlreadyReviewed = errors.New("already reviewed this event")
+var ErrHostCannotReview = errors.New("hosts can't review their own event")
+
+const createTableEventReviews = `
+CREATE TABLE IF NOT EXISTS event_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL,
+    host_id INTEGER NOT NULL,
+    reviewer_id INTEGER NOT NULL,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, reviewer_id),
+    FOREIGN KEY (event_id) REFERENCES events(id),
+    FOREIGN KEY (host_id) REFERENCES users(id),
+    FOREIGN KEY (reviewer_id) REFERENCES users(id)
+);
+`
+
+const createIndexEventReviewsHost = `
+CREATE INDEX IF NOT EXISTS idx_event_reviews_host ON event_reviews(host_id);
+`
+
+// insertEventReview ignores a second review from the same member, which
+// CreateEventReview reports as ErrAlreadyReviewed.
+const insertEventReview = `
+INSERT OR IGNORE INTO event_reviews (event_id, host_id, reviewer_id, rating, body)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, created_at;
+`
+
+const selectEventReviews = `
+SELECT r.id, r.event_id, r.reviewer_id, u.name, r.rating, r.body, r.created_at
+FROM event_reviews r
+JOIN users u ON u.id = r.reviewer_id
+WHERE r.event_id = ?
+ORDER BY r.created_at DESC, r.id DESC;
+`
+
+// selectHostRatings expects the host ID placeholders to be filled in.
+const selectHostRatings = `
+SELECT host_id, ROUND(AVG(rating), 2), COUNT(1)
+FROM event_reviews
+WHERE host_id IN (%s)
+GROUP BY host_id;
+`
+
+const deleteEventReviewsByUser = `
+DELETE FROM event_reviews WHERE reviewer_id = ?;
+`
+
+func (r *EventRepository) initEventReviews(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventReviews); err != nil {
+		return fmt.Errorf("create event reviews table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexEventReviewsHost); err != nil {
+		return fmt.Errorf("create event reviews host index: %w", err)
+	}
+	return nil
+}
+
+// eventIsOver reports whether the event finished by now.
+func eventIsOver(event *Event, now time.Time) bool {
+	return event.StartsAt != nil && !now.Before(event.StartsAt.Add(eventAssumedLength))
+}
+
+// CreateEventReview stores a member's rating of the event and its host. Only
+// members of the event chat can review, once, after the event is over.
+func (r *EventRepository) CreateEventReview(ctx context.Context, eventID, reviewerID int64, params CreateEventReviewParams, now time.Time) (*EventReview, error) {
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.UserID == reviewerID {
+		return nil, ErrHostCannotReview
+	}
+	convo, err := r.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			return nil, ErrNotConversationMember
+		}
+		return nil, err
+	}
+	role, err := memberRole(ctx, r.db, convo.ID, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrNotConversationMember
+	}
+	if event.Status == eventStatusCancelled {
+		return nil, ErrEventCancelled
+	}
+	if !eventIsOver(event, now) {
+		return nil, ErrEventNotOver
+	}
+
+	review := EventReview{
+		EventID:    eventID,
+		ReviewerID: reviewerID,
+		Rating:     params.Rating,
+		Body:       strings.TrimSpace(params.Body),
+	}
+	err = r.db.QueryRowContext(ctx, insertEventReview, eventID, event.UserID, reviewerID, review.Rating, review.Body).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAlreadyReviewed
+		}
+		return nil, fmt.Errorf("insert event review: %w", err)
+	}
+	names, err := r.GetUserNames(ctx, []int64{reviewerID})
+	if err != nil {
+		return nil, err
+	}
+	review.ReviewerName = names[reviewerID]
+	return &review, nil
+}
+
+// ListEventReviews returns the event's reviews, newest first, if viewerID can
+// see the event.
+func (r *EventRepository) ListEventReviews(ctx context.Context, eventID, viewerID int64) ([]EventReview, error) {
+	if _, err := r.GetVisibleEvent(ctx, eventID, viewerID); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, selectEventReviews, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("list event reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []EventReview{}
+	for rows.Next() {
+		var review EventReview
+		if err := rows.Scan(&review.ID, &review.EventID, &review.ReviewerID, &review.ReviewerName, &review.Rating, &review.Body, &review.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate event reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// fetchHostRatings aggregates reviews per host. Hosts nobody has reviewed are
+// left out.
+func (r *EventRepository) fetchHostRatings(ctx context.Context, hostIDs []int64) (map[int64]HostRating, error) {
+	ratings := make(map[int64]HostRating, len(hostIDs))
+	if len(hostIDs) == 0 {
+		return ratings, nil
+	}
+	args := make([]any, len(hostIDs))
+	for i, id := range hostIDs {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(selectHostRatings, placeholders(len(args))), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query host ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hostID int64
+		var rating HostRating
+		if err := rows.Scan(&hostID, &rating.Average, &rating.Count); err != nil {
+			return nil, fmt.Errorf("scan host rating: %w", err)
+		}
+		ratings[hostID] = rating
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate host ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// attachHostRatings fills in each event's host rating with one query.
+func (r *EventRepository) attachHostRatings(ctx context.Context, events []Event) error {
+	seen := make(map[int64]struct{}, len(events))
+	var hostIDs []int64
+	for _, evt := range events {
+		if _, ok := seen[evt.UserID]; !ok {
+			seen[evt.UserID] = struct{}{}
+			hostIDs = append(hostIDs, evt.UserID)
+		}
+	}
+	ratings, err := r.fetchHostRatings(ctx, hostIDs)
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		if rating, ok := ratings[events[i].UserID]; ok {
+			events[i].HostRating = &rating
+		}
+	}
+	return nil
+}
+
+// hostRatingFor returns the user's rating as a host, or nil if nobody has
+// reviewed their events.
+func (r *EventRepository) hostRatingFor(ctx context.Context, userID int64) (*HostRating, error) {
+	ratings, err := r.fetchHostRatings(ctx, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	rating, ok := ratings[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &rating, nil
+}
+
+// listEventReviews returns the event's reviews.
+//
+// Responses:
+//  - 200 with `reviews`
+//  - 400 for invalid event id
+//  - 404 if the event doesn't exist or is private to the caller
+//  - 500 for repository/database failures
+func (h *EventHandler) listEventReviews(c *gin.Context) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var viewerID int64
+	if claims, ok := sessionFromContext(c); ok {
+		viewerID = claims.UserID
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	reviews, err := h.repo.ListEventReviews(ctx, eventID, viewerID)
+	if err != nil {
+		respondEventReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
+
+// createEventReview rates a finished event and its host from 1 to 5, with an
+// optional short review.
+//
+// Responses:
+//  - 201 with the stored `review`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or event id, a rating outside 1–5, or a review
+//    over 500 characters
+//  - 403 if the caller wasn't in the event chat or is the host
+//  - 404 if the event doesn't exist
+//  - 409 if the event isn't over yet or was cancelled, or the caller already
+//    reviewed it
+//  - 500 for repository/database failures
+func (h *EventHandler) createEventReview(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload CreateEventReviewParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	review, err := h.repo.CreateEventReview(ctx, eventID, claims.UserID, payload, time.Now())
+	if err != nil {
+		respondEventReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"review": review})
+}
+
+func respondEventReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+	case errors.Is(err, ErrNotConversationMember):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only members of the event can review it"})
+	case errors.Is(err, ErrHostCannotReview):
+		c.JSON(http.StatusForbidden, gin.H{"error": "hosts can't review their own event"})
+	case errors.Is(err, ErrEventNotOver):
+		c.JSON(http.StatusConflict, gin.H{"error": "the event hasn't ended yet"})
+	case errors.Is(err, ErrEventCancelled):
+		c.JSON(http.StatusConflict, gin.H{"error": "the event was cancelled"})
+	case errors.Is(err, ErrAlreadyReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": "you already reviewed this event"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save review"})
+	}
+}
@@ -1,38 +1,71 @@
 package main
 
 import (
-	"net/http"
+	"log"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func setupRouter(eventHandler *EventHandler, authHandler *AuthHandler, chatHub *ChatHub, signer *tokenSigner) *gin.Engine {
+func setupRouter(config HTTPConfig, repo *EventRepository, eventHandler *EventHandler, authHandler *AuthHandler, userHandler *UserHandler, chatHub *ChatHub, signer *tokenSigner) *gin.Engine {
 	r := gin.Default()
 
+	// c.ClientIP() (used in logs) only believes X-Forwarded-For from these.
+	if err := r.SetTrustedProxies(config.TrustedProxies); err != nil {
+		log.Fatalf("failed to configure trusted proxies: %v", err)
+	}
+
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:  []string{"*"},
+		AllowOrigins:  config.AllowOrigins,
 		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:  []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders: []string{"Content-Length"},
+		AllowHeaders:  config.AllowHeaders,
+		ExposeHeaders: []string{"Content-Length", "ETag", "Last-Modified", "Idempotent-Replayed"},
 		MaxAge:        12 * time.Hour,
 	}))
+	r.Use(bodyLimitMiddleware(config.MaxBodyBytes, config.MaxUploadBytes, config.MaxJSONDepth))
+	// Long-lived streams compress poorly and must flush frame by frame.
+	r.Use(compressionMiddleware(compressionMinSizeFromEnv(), streamingPaths...))
+	r.Use(streamingDeadlineMiddleware(streamingPaths...))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	registerHealthRoutes(r, repo, chatHub)
+	adminAccounts := adminAccountsFromEnv()
+	adminAuth := func(scope string) gin.HandlerFunc {
+		return adminMiddleware(adminAccounts, signer, repo, scope)
+	}
+	registerDebugRoutes(r, adminAuth(apiKeyScopeDebug), chatHub)
+	webhookHandler := NewWebhookHandler(repo)
+	registerAdminWebhookRoutes(r, adminAuth(apiKeyScopeWebhooks), webhookHandler)
+	registerAdminMessageFilterRoutes(r, adminAuth(apiKeyScopeModeration), NewMessageFilterHandler(repo))
+	registerAdminShadowBanRoutes(r, adminAuth(apiKeyScopeModeration), &ChatHTTPHandler{repo: repo, hub: chatHub})
+	registerAdminVerificationRoutes(r, adminAuth(apiKeyScopeModeration), NewVerificationHandler(repo, chatHub))
 
 	api := r.Group("/api")
-	authHandler.RegisterRoutes(api)
-	eventHandler.RegisterRoutes(api)
+	// Sign-in and the public listings are what scripts hammer, so that's
+	// where the apps sign requests. Bots have their own keys.
+	signed := requestSigningMiddleware(config.SigningSecrets, config.RequireSigning)
+	authHandler.RegisterRoutes(api.Group("", signed))
+	botHandler := NewBotHandler(repo, chatHub)
+	botHandler.RegisterRoutes(api)
+
+	public := api.Group("")
+	public.Use(signed, optionalSessionMiddleware(signer, repo))
+	eventHandler.RegisterRoutes(public)
+	registerGraphQLRoute(public, repo)
 
 	protected := api.Group("")
-	protected.Use(sessionMiddleware(signer))
+	protected.Use(sessionMiddleware(signer, repo), requireRole(roleUser))
 	eventHandler.RegisterProtectedRoutes(protected)
-	RegisterChatRoutes(protected, eventHandler.repo, chatHub)
+	userHandler.RegisterProtectedRoutes(protected)
+	RegisterChatRoutes(protected, repo, chatHub)
+	webhookHandler.RegisterProtectedRoutes(protected)
+	botHandler.RegisterProtectedRoutes(protected)
 
 	api.GET("/ws", chatHub.handleWebSocket)
+	api.GET("/events/stream", chatHub.handleEventStream)
+
+	// Last, so the document covers every route above.
+	registerOpenAPIRoutes(r)
 
 	return r
 }
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultScheduledMessageInterval controls how often due scheduled messages
+// are posted, and so how late one can be.
+const defaultScheduledMessageInterval = 15 * time.Second
+
+const (
+	maxScheduleAhead    = 30 * 24 * time.Hour // furthest out a message can be scheduled
+	maxScheduledPerUser = 25                  // pending scheduled messages per user
+)
+
+var ErrScheduleLimitReached = errors.New("too many scheduled messages")
+var ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+
+const createTableScheduledMessages = `
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    conversation_id INTEGER NOT NULL,
+    sender_id INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    send_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    FOREIGN KEY (sender_id) REFERENCES users(id)
+);
+`
+
+const createScheduledMessagesIndex = `
+CREATE INDEX IF NOT EXISTS scheduled_messages_send_at_idx
+ON scheduled_messages (send_at);
+`
+
+const countScheduledMessagesForUser = `
+SELECT COUNT(1) FROM scheduled_messages WHERE sender_id = ?;
+`
+
+const insertScheduledMessage = `
+INSERT INTO scheduled_messages (conversation_id, sender_id, body, send_at)
+VALUES (?, ?, ?, ?)
+RETURNING id, conversation_id, sender_id, body, send_at, created_at;
+`
+
+const selectScheduledMessagesForUser = `
+SELECT id, conversation_id, sender_id, body, send_at, created_at
+FROM scheduled_messages
+WHERE conversation_id = ? AND sender_id = ?
+ORDER BY send_at ASC, id ASC;
+`
+
+const deleteScheduledMessage = `
+DELETE FROM scheduled_messages
+WHERE id = ? AND conversation_id = ? AND sender_id = ?;
+`
+
+// claimDueScheduledMessages removes and returns every message due by now in
+// one statement, so two instances sharing the database never both post one.
+const claimDueScheduledMessages = `
+DELETE FROM scheduled_messages
+WHERE send_at <= ?
+RETURNING id, conversation_id, sender_id, body, send_at, created_at;
+`
+
+const deleteScheduledMessagesForUser = `
+DELETE FROM scheduled_messages
+WHERE sender_id = ?;
+`
+
+func (r *EventRepository) initScheduledMessages(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableScheduledMessages); err != nil {
+		return fmt.Errorf("create scheduled messages table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createScheduledMessagesIndex); err != nil {
+		return fmt.Errorf("create scheduled messages index: %w", err)
+	}
+	return nil
+}
+
+func scanScheduledMessage(row rowScanner) (ScheduledMessage, error) {
+	var msg ScheduledMessage
+	err := row.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body, &msg.SendAt, &msg.CreatedAt)
+	return msg, err
+}
+
+// ScheduleMessage queues a message to be posted at sendAt. Callers check
+// membership and validate the body first.
+func (r *EventRepository) ScheduleMessage(ctx context.Context, conversationID, senderID int64, body string, sendAt time.Time) (*ScheduledMessage, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin schedule message tx: %w", err)
+	}
+
+	var pending int
+	if err := tx.QueryRowContext(ctx, countScheduledMessagesForUser, senderID).Scan(&pending); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("count scheduled messages: %w", err)
+	}
+	if pending >= maxScheduledPerUser {
+		tx.Rollback()
+		return nil, ErrScheduleLimitReached
+	}
+	msg, err := scanScheduledMessage(tx.QueryRowContext(ctx, insertScheduledMessage, conversationID, senderID, body, sqliteTime(sendAt)))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert scheduled message: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit schedule message: %w", err)
+	}
+	return &msg, nil
+}
+
+// ListScheduledMessages returns the user's pending messages in a
+// conversation, soonest first.
+func (r *EventRepository) ListScheduledMessages(ctx context.Context, conversationID, senderID int64) ([]ScheduledMessage, error) {
+	rows, err := r.db.QueryContext(ctx, selectScheduledMessagesForUser, conversationID, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []ScheduledMessage{}
+	for rows.Next() {
+		msg, err := scanScheduledMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan scheduled message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scheduled messages: %w", err)
+	}
+	return messages, nil
+}
+
+// CancelScheduledMessage drops one of the user's pending messages.
+func (r *EventRepository) CancelScheduledMessage(ctx context.Context, conversationID, senderID, scheduledID int64) error {
+	res, err := r.db.ExecContext(ctx, deleteScheduledMessage, scheduledID, conversationID, senderID)
+	if err != nil {
+		return fmt.Errorf("cancel scheduled message: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cancel scheduled message rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrScheduledMessageNotFound
+	}
+	return nil
+}
+
+// ClaimDueScheduledMessages takes every message due at now off the queue. A
+// claimed message is gone even if posting it then fails, so none is sent twice.
+func (r *EventRepository) ClaimDueScheduledMessages(ctx context.Context, now time.Time) ([]ScheduledMessage, error) {
+	rows, err := r.db.QueryContext(ctx, claimDueScheduledMessages, sqliteTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("claim scheduled messages: %w", err)
+	}
+	defer rows.Close()
+
+	var due []ScheduledMessage
+	for rows.Next() {
+		msg, err := scanScheduledMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan scheduled message: %w", err)
+		}
+		due = append(due, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scheduled messages: %w", err)
+	}
+	return due, nil
+}
+
+// ScheduledMessageJob posts queued messages once they are due, the same way
+// a message sent over the socket is stored and broadcast.
+type ScheduledMessageJob struct {
+	repo     *EventRepository
+	hub      *ChatHub
+	interval time.Duration
+}
+
+// newScheduledMessageJob schedules the sweep from config's
+// SCHEDULED_MESSAGE_INTERVAL.
+func newScheduledMessageJob(repo *EventRepository, hub *ChatHub, config JobsConfig) *ScheduledMessageJob {
+	return &ScheduledMessageJob{repo: repo, hub: hub, interval: config.ScheduledMessageInterval}
+}
+
+// Register schedules the sweep on runner every interval.
+func (j *ScheduledMessageJob) Register(runner *JobRunner) {
+	runner.Register("scheduled_messages", j.interval, j.sweep)
+}
+
+func (j *ScheduledMessageJob) sweep(ctx context.Context) error {
+	sweepCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	due, err := j.repo.ClaimDueScheduledMessages(sweepCtx, time.Now())
+	if err != nil {
+		return err
+	}
+	sent := 0
+	for _, scheduled := range due {
+		if j.post(sweepCtx, scheduled) {
+			sent++
+		}
+	}
+	if len(due) > 0 {
+		log.Printf("posted %d of %d scheduled messages", sent, len(due))
+	}
+	return nil
+}
+
+// post sends one claimed message, unless the sender has left the chat since
+// scheduling it.
+func (j *ScheduledMessageJob) post(ctx context.Context, scheduled ScheduledMessage) bool {
+	allowed, err := j.repo.IsConversationMember(ctx, scheduled.ConversationID, scheduled.SenderID)
+	if err != nil {
+		log.Printf("scheduled message %d membership check failed: %v", scheduled.ID, err)
+		return false
+	}
+	if !allowed {
+		log.Printf("dropping scheduled message %d: user %d left conversation %d", scheduled.ID, scheduled.SenderID, scheduled.ConversationID)
+		return false
+	}
+
+	shadowBanned, err := j.repo.IsShadowBanned(ctx, scheduled.ConversationID, scheduled.SenderID)
+	if err != nil {
+		log.Printf("scheduled message %d shadow ban check failed: %v", scheduled.ID, err)
+		return false
+	}
+
+	msg, err := j.repo.CreateMessage(ctx, CreateMessageParams{
+		ConversationID: scheduled.ConversationID,
+		SenderID:       scheduled.SenderID,
+		Body:           scheduled.Body,
+		DeliveryStatus: "sent",
+		Notify:         true,
+		Hidden:         shadowBanned,
+	})
+	if err != nil {
+		log.Printf("post scheduled message %d failed: %v", scheduled.ID, err)
+		return false
+	}
+	if err := j.repo.UpdateReadState(ctx, msg.ConversationID, msg.SenderID, msg.ID); err != nil {
+		log.Printf("update read state after scheduled send failed: %v", err)
+	}
+	if shadowBanned {
+		j.hub.EchoHiddenMessage(*msg)
+		return true
+	}
+	j.hub.BroadcastMessage(*msg)
+	j.hub.notifyMentions(*msg)
+	return true
+}
+
+// scheduleMessage queues a message from the caller to be posted at
+// `send_at`, at most 30 days out. It goes out through the same path as a
+// socket send, within SCHEDULED_MESSAGE_INTERVAL of the requested time, and
+// is dropped if the caller has left the chat by then.
+//
+// Responses:
+//  - 201 with the scheduled message
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON, conversation id, an empty or too long body, or a
+//    `send_at` that is past or too far ahead
+//  - 403 if the caller is not a member
+//  - 409 once the caller has 25 messages pending, or with code
+//    `conversation_locked` if the conversation is read-only
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) scheduleMessage(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var payload ScheduleMessageParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(payload.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body is required"})
+		return
+	}
+	if utf8.RuneCountInString(payload.Body) > h.hub.config.MaxMessageLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message is too long", "maxLength": h.hub.config.MaxMessageLength})
+		return
+	}
+	now := time.Now()
+	if !payload.SendAt.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send_at must be in the future"})
+		return
+	}
+	if payload.SendAt.After(now.Add(maxScheduleAhead)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send_at must be within 30 days"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+	locked, err := h.repo.IsConversationLocked(ctx, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to schedule message"})
+		return
+	}
+	if locked {
+		respondConversationLocked(c)
+		return
+	}
+
+	scheduled, err := h.repo.ScheduleMessage(ctx, conversationID, claims.UserID, payload.Body, payload.SendAt)
+	if err != nil {
+		if errors.Is(err, ErrScheduleLimitReached) {
+			c.JSON(http.StatusConflict, gin.H{"error": "too many scheduled messages", "limit": maxScheduledPerUser})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to schedule message"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"scheduledMessage": scheduled})
+}
+
+// listScheduledMessages returns the caller's pending messages in a
+// conversation, soonest first. Other members' queues aren't visible.
+//
+// Responses:
+//  - 200 with `scheduledMessages`
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation id
+//  - 403 if the caller is not a member
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listScheduledMessages(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if !h.requireMember(c, ctx, conversationID, claims.UserID) {
+		return
+	}
+
+	messages, err := h.repo.ListScheduledMessages(ctx, conversationID, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list scheduled messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scheduledMessages": messages})
+}
+
+// cancelScheduledMessage drops one of the caller's pending messages before it
+// goes out.
+//
+// Responses:
+//  - 204 on success
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation or scheduled message id
+//  - 404 if the caller has no such pending message in the conversation
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) cancelScheduledMessage(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+	scheduledID, err := strconv.ParseInt(c.Param("scheduledId"), 10, 64)
+	if err != nil || scheduledID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduled message id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.CancelScheduledMessage(ctx, conversationID, claims.UserID, scheduledID); err != nil {
+		if errors.Is(err, ErrScheduledMessageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "scheduled message not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel scheduled message"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// demoFixtures is the dataset SEED_DEMO_DATA loads when SEED_FIXTURES isn't
+// set: four demo users, their events and a small group chat.
+//
+//go:embed seed/demo.json
+var demoFixtures []byte
+
+// SeedFixtures is a dataset for a fresh database. Users are referenced by
+// email everywhere else in the file.
+type SeedFixtures struct {
+	Users         []SeedUser         `json:"users" yaml:"users"`
+	Events        []SeedEvent        `json:"events" yaml:"events"`
+	Conversations []SeedConversation `json:"conversations" yaml:"conversations"`
+}
+
+type SeedUser struct {
+	Name     string `json:"name" yaml:"name"`
+	Email    string `json:"email" yaml:"email"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// SeedEvent is an event and, optionally, who else is in its chat and what
+// they said there.
+type SeedEvent struct {
+	Host        string        `json:"host" yaml:"host"`
+	Title       string        `json:"title" yaml:"title"`
+	Location    string        `json:"location" yaml:"location"`
+	Time        string        `json:"time" yaml:"time"`
+	Description string        `json:"description" yaml:"description"`
+	Gender      string        `json:"gender" yaml:"gender"`
+	MinAge      int           `json:"min_age" yaml:"min_age"`
+	MaxAge      int           `json:"max_age" yaml:"max_age"`
+	DateLabel   string        `json:"date_label" yaml:"date_label"`
+	Capacity    *int          `json:"capacity" yaml:"capacity"`
+	Tags        []string      `json:"tags" yaml:"tags"`
+	Members     []string      `json:"members" yaml:"members"`
+	Messages    []SeedMessage `json:"messages" yaml:"messages"`
+}
+
+// SeedConversation is a chat outside any event; the first member creates it.
+type SeedConversation struct {
+	Title    string        `json:"title" yaml:"title"`
+	Members  []string      `json:"members" yaml:"members"`
+	Messages []SeedMessage `json:"messages" yaml:"messages"`
+}
+
+type SeedMessage struct {
+	From string `json:"from" yaml:"from"`
+	Body string `json:"body" yaml:"body"`
+}
+
+// SeedConfig decides whether startup seeds the database, and with what.
+type SeedConfig struct {
+	Enabled  bool
+	Fixtures string // path to a JSON or YAML file; empty for the demo data
+}
+
+// newSeedConfigFromEnv reads SEED_DEMO_DATA and SEED_FIXTURES. Seeding is off
+// unless SEED_DEMO_DATA is "true", so production databases never get demo
+// accounts.
+func newSeedConfigFromEnv() SeedConfig {
+	return SeedConfig{
+		Enabled:  os.Getenv("SEED_DEMO_DATA") == "true",
+		Fixtures: strings.TrimSpace(os.Getenv("SEED_FIXTURES")),
+	}
+}
+
+// load parses the configured fixtures, choosing YAML or JSON by extension.
+func (c SeedConfig) load() (*SeedFixtures, error) {
+	raw, name := demoFixtures, "seed/demo.json"
+	if c.Fixtures != "" {
+		data, err := os.ReadFile(c.Fixtures)
+		if err != nil {
+			return nil, fmt.Errorf("read seed fixtures: %w", err)
+		}
+		raw, name = data, c.Fixtures
+	}
+
+	var fixtures SeedFixtures
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(raw, &fixtures); err != nil {
+			return nil, fmt.Errorf("parse seed fixtures %s: %w", name, err)
+		}
+	default:
+		if err := json.Unmarshal(raw, &fixtures); err != nil {
+			return nil, fmt.Errorf("parse seed fixtures %s: %w", name, err)
+		}
+	}
+	return &fixtures, nil
+}
+
+// EnsureSeedData loads fixtures into a database that has no users yet. A
+// database with any users is left alone, so restarting never duplicates or
+// overwrites data.
+func (r *EventRepository) EnsureSeedData(ctx context.Context, fixtures *SeedFixtures) error {
+	var count int
+	if err := r.db.QueryRowContext(ctx, countUsers).Scan(&count); err != nil {
+		return fmt.Errorf("count users: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	userIDs := make(map[string]int64, len(fixtures.Users))
+	for _, user := range fixtures.Users {
+		res, err := r.db.ExecContext(ctx, insertUser, user.Name, user.Email, user.Password)
+		if err != nil {
+			return fmt.Errorf("seed user %q: %w", user.Email, err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("seed user %q id: %w", user.Email, err)
+		}
+		userIDs[user.Email] = id
+	}
+	lookup := func(email string) (int64, error) {
+		id, ok := userIDs[email]
+		if !ok {
+			return 0, fmt.Errorf("seed fixtures reference unknown user %q", email)
+		}
+		return id, nil
+	}
+
+	for _, evt := range fixtures.Events {
+		hostID, err := lookup(evt.Host)
+		if err != nil {
+			return err
+		}
+		eventID, err := r.Create(ctx, CreateEventParams{
+			Title:       evt.Title,
+			Location:    evt.Location,
+			Time:        evt.Time,
+			Description: evt.Description,
+			Gender:      evt.Gender,
+			MinAge:      evt.MinAge,
+			MaxAge:      evt.MaxAge,
+			DateLabel:   evt.DateLabel,
+			Capacity:    evt.Capacity,
+			Tags:        evt.Tags,
+			UserID:      hostID,
+		})
+		if err != nil {
+			return fmt.Errorf("seed event %q: %w", evt.Title, err)
+		}
+		if len(evt.Members) == 0 && len(evt.Messages) == 0 {
+			continue
+		}
+
+		convo, err := r.GetConversationByEventID(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("seed event %q chat: %w", evt.Title, err)
+		}
+		for _, email := range evt.Members {
+			userID, err := lookup(email)
+			if err != nil {
+				return err
+			}
+			if _, err := r.db.ExecContext(ctx, insertConversationMember, convo.ID, userID, roleMember); err != nil {
+				return fmt.Errorf("seed event %q member %q: %w", evt.Title, email, err)
+			}
+		}
+		if err := r.seedMessages(ctx, convo.ID, evt.Messages, lookup); err != nil {
+			return fmt.Errorf("seed event %q: %w", evt.Title, err)
+		}
+	}
+
+	for _, fixture := range fixtures.Conversations {
+		if len(fixture.Members) == 0 {
+			return fmt.Errorf("seed conversation %q has no members", fixture.Title)
+		}
+		memberIDs := make([]int64, 0, len(fixture.Members))
+		for _, email := range fixture.Members {
+			userID, err := lookup(email)
+			if err != nil {
+				return err
+			}
+			memberIDs = append(memberIDs, userID)
+		}
+		var title *string
+		if fixture.Title != "" {
+			title = &fixture.Title
+		}
+		convo, err := r.CreateConversation(ctx, title, memberIDs[0], memberIDs, nil)
+		if err != nil {
+			return fmt.Errorf("seed conversation %q: %w", fixture.Title, err)
+		}
+		if err := r.seedMessages(ctx, convo.ID, fixture.Messages, lookup); err != nil {
+			return fmt.Errorf("seed conversation %q: %w", fixture.Title, err)
+		}
+	}
+
+	log.Printf("seeded %d users, %d events and %d conversations", len(fixtures.Users), len(fixtures.Events), len(fixtures.Conversations))
+	return nil
+}
+
+// seedMessages posts messages in order. Each sender has read up to their own
+// last message, so anything said after it shows as unread.
+func (r *EventRepository) seedMessages(ctx context.Context, conversationID int64, messages []SeedMessage, lookup func(string) (int64, error)) error {
+	lastSent := make(map[int64]int64)
+	for _, msg := range messages {
+		senderID, err := lookup(msg.From)
+		if err != nil {
+			return err
+		}
+		created, err := r.CreateMessage(ctx, CreateMessageParams{
+			ConversationID: conversationID,
+			SenderID:       senderID,
+			Body:           msg.Body,
+			DeliveryStatus: "sent",
+		})
+		if err != nil {
+			return fmt.Errorf("seed message: %w", err)
+		}
+		lastSent[senderID] = created.ID
+	}
+	for userID, messageID := range lastSent {
+		if err := r.UpdateReadState(ctx, conversationID, userID, messageID); err != nil {
+			return fmt.Errorf("seed read state: %w", err)
+		}
+	}
+	return nil
+}
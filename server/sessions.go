@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Every sign-in stores a session for the device, and its token carries the
+// session's ID. Requests check the session is still live, so a user can see
+// where they're signed in and sign other devices out.
+
+const (
+	// sessionTouchInterval throttles last-seen updates to one write per
+	// session per interval.
+	sessionTouchInterval = time.Minute
+	// sessionDeviceHeader is where apps name the device signing in, like
+	// "iPhone 14".
+	sessionDeviceHeader = "X-Device-Name"
+	// maxSessionLabel caps the device name and location kept.
+	maxSessionLabel = 100
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+const createTableUserSessions = `
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    device_name TEXT NOT NULL,
+    ip TEXT NOT NULL,
+    location TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createIndexUserSessionsUser = `
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user
+ON user_sessions(user_id, last_seen_at);
+`
+
+const insertUserSession = `
+INSERT INTO user_sessions (user_id, device_name, ip, location, created_at, last_seen_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+`
+
+const selectLiveSession = `
+SELECT s.last_seen_at
+FROM user_sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = ? AND s.user_id = ? AND s.revoked_at IS NULL AND u.deleted_at IS NULL;
+`
+
+const touchUserSession = `
+UPDATE user_sessions
+SET last_seen_at = ?, ip = ?
+WHERE id = ? AND last_seen_at < ?;
+`
+
+const selectUserSessions = `
+SELECT id, device_name, ip, location, created_at, last_seen_at
+FROM user_sessions
+WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+ORDER BY last_seen_at DESC, id DESC;
+`
+
+const revokeUserSession = `
+UPDATE user_sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?;
+`
+
+const selectUserIsAdmin = `
+SELECT is_admin FROM users WHERE id = ?;
+`
+
+const deleteExpiredUserSessions = `
+DELETE FROM user_sessions
+WHERE expires_at < ?;
+`
+
+const deleteSessionsForUser = `
+DELETE FROM user_sessions
+WHERE user_id = ?;
+`
+
+func (r *EventRepository) initUserSessions(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableUserSessions); err != nil {
+		return fmt.Errorf("create user sessions table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexUserSessionsUser); err != nil {
+		return fmt.Errorf("create user sessions index: %w", err)
+	}
+	return nil
+}
+
+// CreateSession stores a session for a device signing in and returns its ID.
+func (r *EventRepository) CreateSession(ctx context.Context, userID int64, deviceName, ip, location string, expiresAt time.Time) (int64, error) {
+	now := sqliteTime(time.Now())
+	res, err := r.db.ExecContext(ctx, insertUserSession, userID, deviceName, ip, nullableString(location), now, now, sqliteTime(expiresAt))
+	if err != nil {
+		return 0, fmt.Errorf("insert session: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("fetch session id: %w", err)
+	}
+	return id, nil
+}
+
+// UserRoles returns the roles signing in grants the account: user, plus
+// admin for accounts promoted with adminctl.
+func (r *EventRepository) UserRoles(ctx context.Context, userID int64) ([]string, error) {
+	var isAdmin bool
+	if err := r.db.QueryRowContext(ctx, selectUserIsAdmin, userID).Scan(&isAdmin); err != nil {
+		return nil, fmt.Errorf("lookup roles: %w", err)
+	}
+	if isAdmin {
+		return []string{roleUser, roleAdmin}, nil
+	}
+	return []string{roleUser}, nil
+}
+
+// TouchSession reports whether the token's session is live, meaning not
+// revoked and its account not deleted, and records the request as its last
+// activity at most once per sessionTouchInterval.
+func (r *EventRepository) TouchSession(ctx context.Context, claims *sessionClaims, ip string, now time.Time) (bool, error) {
+	var lastSeen time.Time
+	err := r.db.QueryRowContext(ctx, selectLiveSession, claims.SessionID, claims.UserID).Scan(&lastSeen)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lookup session: %w", err)
+	}
+	if now.Sub(lastSeen) >= sessionTouchInterval {
+		if _, err := r.db.ExecContext(ctx, touchUserSession, sqliteTime(now), ip, claims.SessionID, sqliteTime(now.Add(-sessionTouchInterval))); err != nil {
+			return false, fmt.Errorf("touch session: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// ListSessions returns the user's live sessions, most recently active first.
+func (r *EventRepository) ListSessions(ctx context.Context, userID int64, now time.Time) ([]Session, error) {
+	rows, err := r.db.QueryContext(ctx, selectUserSessions, userID, sqliteTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []Session{}
+	for rows.Next() {
+		var session Session
+		var location sql.NullString
+		if err := rows.Scan(&session.ID, &session.DeviceName, &session.IP, &location, &session.CreatedAt, &session.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		if location.Valid {
+			value := location.String
+			session.Location = &value
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's sessions out. It returns
+// ErrSessionNotFound if the session isn't theirs or is already over.
+func (r *EventRepository) RevokeSession(ctx context.Context, userID, sessionID int64, now time.Time) error {
+	res, err := r.db.ExecContext(ctx, revokeUserSession, sessionID, userID, sqliteTime(now))
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	} else if affected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// PruneSessions deletes sessions whose tokens expired before cutoff.
+func (r *EventRepository) PruneSessions(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteExpiredUserSessions, sqliteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("prune sessions: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// registerSessionPruning schedules daily cleanup of expired sessions.
+func registerSessionPruning(runner *JobRunner, repo *EventRepository) {
+	runner.Register("session_prune", 24*time.Hour, func(ctx context.Context) error {
+		pruneCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+
+		pruned, err := repo.PruneSessions(pruneCtx, time.Now())
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Printf("pruned %d expired sessions", pruned)
+		}
+		return nil
+	})
+}
+
+// sessionActive checks a verified token still has a live session, recording
+// the request as the device's latest activity. Tokens issued before sessions
+// were stored only need their account to still exist.
+func sessionActive(ctx context.Context, repo UserStore, claims *sessionClaims, ip string) (bool, error) {
+	if claims.SessionID == 0 {
+		deleted, err := repo.IsUserDeleted(ctx, claims.UserID)
+		return !deleted, err
+	}
+	return repo.TouchSession(ctx, claims, ip, time.Now())
+}
+
+// sessionDeviceName names the device signing in from the app's
+// X-Device-Name header, or roughly from its user agent.
+func sessionDeviceName(r *http.Request) string {
+	if name := clipSessionLabel(r.Header.Get(sessionDeviceHeader)); name != "" {
+		return name
+	}
+	agent := r.UserAgent()
+	for _, known := range []struct{ marker, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Macintosh", "Mac"},
+		{"Windows", "Windows PC"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(agent, known.marker) {
+			return known.name
+		}
+	}
+	return "Unknown device"
+}
+
+// sessionLocationHeaderFromEnv reads SESSION_LOCATION_HEADER, the header a
+// fronting proxy or CDN puts the caller's city in (like CF-IPCity). Unset
+// records no location.
+func sessionLocationHeaderFromEnv() string {
+	return strings.TrimSpace(os.Getenv("SESSION_LOCATION_HEADER"))
+}
+
+// sessionLocation reads the caller's city from header, if one is configured.
+func sessionLocation(r *http.Request, header string) string {
+	if header == "" {
+		return ""
+	}
+	return clipSessionLabel(r.Header.Get(header))
+}
+
+// clipSessionLabel tidies a client-supplied label to at most
+// maxSessionLabel characters.
+func clipSessionLabel(label string) string {
+	label = strings.TrimSpace(strings.ToValidUTF8(label, ""))
+	if utf8.RuneCountInString(label) <= maxSessionLabel {
+		return label
+	}
+	return strings.TrimSpace(string([]rune(label)[:maxSessionLabel]))
+}
+
+// listSessions returns the devices the caller is signed in on.
+//
+// Responses:
+//  - 200 with the sessions, the one making the request marked current
+//  - 401 if the caller has no session
+//  - 500 for repository/database failures
+func (h *UserHandler) listSessions(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	sessions, err := h.repo.ListSessions(ctx, claims.UserID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sessions"})
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.SessionID
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// revokeSession signs one of the caller's devices out, closing its live
+// sockets. Revoking the current session signs the caller out.
+//
+// Responses:
+//  - 204 once the session is revoked
+//  - 400 for a malformed session id
+//  - 401 if the caller has no session
+//  - 404 if the session isn't the caller's or is already over
+//  - 500 for repository/database failures
+func (h *UserHandler) revokeSession(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || sessionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RevokeSession(ctx, claims.UserID, sessionID, time.Now()); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke session"})
+		return
+	}
+	h.hub.DisconnectSession(claims.UserID, sessionID, "session revoked")
+
+	c.Status(http.StatusNoContent)
+}
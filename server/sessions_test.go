@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionRouter serves the user routes behind the real session middleware,
+// backed by repo.
+func sessionRouter(repo *EventRepository, signer *tokenSigner) *gin.Engine {
+	router := gin.New()
+	group := router.Group("/api", sessionMiddleware(signer, repo))
+	NewUserHandler(repo, newTestHub(repo)).RegisterProtectedRoutes(group)
+	return router
+}
+
+// mustSignIn stores a session for userID and returns its ID and token.
+func mustSignIn(t *testing.T, repo *EventRepository, signer *tokenSigner, userID int64, device string) (int64, string) {
+	t.Helper()
+	expiresAt := time.Now().Add(time.Hour)
+	sessionID, err := repo.CreateSession(context.Background(), userID, device, "192.0.2.1", "", expiresAt)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return sessionID, mustIssue(t, signer, userID, sessionID, expiresAt)
+}
+
+// mustIssue signs a user token for sessionID, failing the test on error.
+func mustIssue(t *testing.T, signer *tokenSigner, userID, sessionID int64, expiresAt time.Time) string {
+	t.Helper()
+	token, _, err := signer.issue(userID, "", sessionID, []string{roleUser}, expiresAt)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	return token
+}
+
+// sendWithToken sends one request with token as its bearer token.
+func sendWithToken(router *gin.Engine, token, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRevokedSessionTokenIsRejected(t *testing.T) {
+	repo := newTestRepository(t)
+	signer := newTestSigner(testSessionSecret)
+	router := sessionRouter(repo, signer)
+	avaID := mustCreateUser(t, repo, "ava")
+	phoneID, phone := mustSignIn(t, repo, signer, avaID, "Ava's phone")
+	laptopID, laptop := mustSignIn(t, repo, signer, avaID, "Ava's laptop")
+
+	assertStatus(t, sendWithToken(router, phone, http.MethodGet, "/api/sessions"), http.StatusOK)
+	assertStatus(t, sendWithToken(router, laptop, http.MethodDelete, "/api/sessions/"+strconv.FormatInt(phoneID, 10)), http.StatusNoContent)
+
+	// The token still verifies, but its session is gone.
+	assertStatus(t, sendWithToken(router, phone, http.MethodGet, "/api/sessions"), http.StatusUnauthorized)
+	assertStatus(t, sendWithToken(router, laptop, http.MethodGet, "/api/sessions"), http.StatusOK)
+	assertStatus(t, sendWithToken(router, laptop, http.MethodDelete, "/api/sessions/"+strconv.FormatInt(phoneID, 10)), http.StatusNotFound)
+
+	// Revoking the current session signs the caller out.
+	assertStatus(t, sendWithToken(router, laptop, http.MethodDelete, "/api/sessions/"+strconv.FormatInt(laptopID, 10)), http.StatusNoContent)
+	assertStatus(t, sendWithToken(router, laptop, http.MethodGet, "/api/sessions"), http.StatusUnauthorized)
+}
+
+func TestRevokeSessionOfAnotherUser(t *testing.T) {
+	repo := newTestRepository(t)
+	signer := newTestSigner(testSessionSecret)
+	router := sessionRouter(repo, signer)
+	avaID := mustCreateUser(t, repo, "ava")
+	liamID := mustCreateUser(t, repo, "liam")
+	avaSessionID, ava := mustSignIn(t, repo, signer, avaID, "Ava's phone")
+	_, liam := mustSignIn(t, repo, signer, liamID, "Liam's phone")
+
+	assertStatus(t, sendWithToken(router, liam, http.MethodDelete, "/api/sessions/"+strconv.FormatInt(avaSessionID, 10)), http.StatusNotFound)
+	assertStatus(t, sendWithToken(router, ava, http.MethodGet, "/api/sessions"), http.StatusOK)
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM user_sessions WHERE id = ? AND revoked_at IS NULL`, avaSessionID); got != 1 {
+		t.Fatalf("live sessions for ava = %d, want 1", got)
+	}
+
+	// A forged token naming Ava's session under Liam's account doesn't
+	// reach it either.
+	forged := mustIssue(t, signer, liamID, avaSessionID, time.Now().Add(time.Hour))
+	assertStatus(t, sendWithToken(router, forged, http.MethodGet, "/api/sessions"), http.StatusUnauthorized)
+}
+
+func TestSessionMiddlewareRejects(t *testing.T) {
+	repo := newTestRepository(t)
+	signer := newTestSigner(testSessionSecret)
+	router := sessionRouter(repo, signer)
+	avaID := mustCreateUser(t, repo, "ava")
+	sessionID, live := mustSignIn(t, repo, signer, avaID, "Ava's phone")
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"live", live, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"expired", mustIssue(t, signer, avaID, sessionID, time.Now().Add(-time.Minute)), http.StatusUnauthorized},
+		{"signed elsewhere", mustIssue(t, newTestSigner("another-secret"), avaID, sessionID, later), http.StatusUnauthorized},
+		{"unknown session", mustIssue(t, signer, avaID, sessionID+100, later), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertStatus(t, sendWithToken(router, tt.token, http.MethodGet, "/api/sessions"), tt.want)
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// A shadow-banned member can keep chatting, but only they see what they
+// send: their messages are stored hidden and echoed to their own sockets, and
+// their earlier messages drop out of everyone else's history while the ban
+// lasts. Nothing is announced, so the member can't tell.
+
+var ErrNotConversationModerator = errors.New("only the chat's host or co-hosts can do this")
+var ErrCannotShadowBanHost = errors.New("the host can't be shadow-banned")
+
+const createTableConversationShadowBans = `
+CREATE TABLE IF NOT EXISTS conversation_shadow_bans (
+    conversation_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    banned_by INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (conversation_id, user_id),
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const insertConversationShadowBan = `
+INSERT OR IGNORE INTO conversation_shadow_bans (conversation_id, user_id, banned_by)
+VALUES (?, ?, ?);
+`
+
+const deleteConversationShadowBan = `
+DELETE FROM conversation_shadow_bans WHERE conversation_id = ? AND user_id = ?;
+`
+
+const checkConversationShadowBan = `
+SELECT 1 FROM conversation_shadow_bans WHERE conversation_id = ? AND user_id = ? LIMIT 1;
+`
+
+const selectConversationShadowBans = `
+SELECT sb.user_id, u.name, sb.banned_by, sb.created_at
+FROM conversation_shadow_bans sb
+JOIN users u ON u.id = sb.user_id
+WHERE sb.conversation_id = ?
+ORDER BY sb.created_at DESC, sb.user_id;
+`
+
+const deleteShadowBansForUser = `
+DELETE FROM conversation_shadow_bans WHERE user_id = ?;
+`
+
+func (r *EventRepository) initShadowBans(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableConversationShadowBans); err != nil {
+		return fmt.Errorf("create conversation shadow bans table: %w", err)
+	}
+	return nil
+}
+
+// IsShadowBanned reports whether userID is shadow-banned in the conversation.
+func (r *EventRepository) IsShadowBanned(ctx context.Context, conversationID, userID int64) (bool, error) {
+	var banned int
+	if err := r.db.QueryRowContext(ctx, checkConversationShadowBan, conversationID, userID).Scan(&banned); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("check shadow ban: %w", err)
+	}
+	return true, nil
+}
+
+// checkConversationModerator allows the host and co-hosts of an event chat,
+// and the owner of any other group chat. actorID 0 is an admin and always
+// allowed.
+func (r *EventRepository) checkConversationModerator(ctx context.Context, convo *Conversation, actorID int64) error {
+	if actorID == 0 {
+		return nil
+	}
+	if convo.EventID != nil {
+		event, err := r.GetEventByID(ctx, *convo.EventID)
+		if err != nil {
+			return err
+		}
+		if err := checkEventModerator(ctx, r.db, event, convo.ID, actorID); err != nil {
+			if errors.Is(err, ErrNotEventHost) {
+				return ErrNotConversationModerator
+			}
+			return err
+		}
+		return nil
+	}
+	role, err := memberRole(ctx, r.db, convo.ID, actorID)
+	if err != nil {
+		return err
+	}
+	if role != roleOwner {
+		return ErrNotConversationModerator
+	}
+	return nil
+}
+
+// ShadowBanUser shadow-bans a member of the conversation. The host, or the
+// owner of a group chat, can't be shadow-banned; banning someone twice is a
+// no-op.
+func (r *EventRepository) ShadowBanUser(ctx context.Context, conversationID, actorID, userID int64) error {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if err := r.checkConversationModerator(ctx, convo, actorID); err != nil {
+		return err
+	}
+
+	role, err := memberRole(ctx, r.db, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	switch role {
+	case "":
+		return ErrNotConversationMember
+	case roleOwner:
+		return ErrCannotShadowBanHost
+	}
+
+	if _, err := r.db.ExecContext(ctx, insertConversationShadowBan, conversationID, userID, actorID); err != nil {
+		return fmt.Errorf("insert shadow ban: %w", err)
+	}
+	return nil
+}
+
+// LiftShadowBan makes the member's earlier messages visible again. What they
+// sent while banned stays hidden. Lifting a ban that doesn't exist is a
+// no-op.
+func (r *EventRepository) LiftShadowBan(ctx context.Context, conversationID, actorID, userID int64) error {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if err := r.checkConversationModerator(ctx, convo, actorID); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, deleteConversationShadowBan, conversationID, userID); err != nil {
+		return fmt.Errorf("delete shadow ban: %w", err)
+	}
+	return nil
+}
+
+// ListShadowBans returns the conversation's shadow-banned members, most
+// recent first. BannedBy is 0 for bans an admin placed.
+func (r *EventRepository) ListShadowBans(ctx context.Context, conversationID, actorID int64) ([]ShadowBan, error) {
+	convo, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkConversationModerator(ctx, convo, actorID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectConversationShadowBans, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list shadow bans: %w", err)
+	}
+	defer rows.Close()
+
+	bans := []ShadowBan{}
+	for rows.Next() {
+		var ban ShadowBan
+		if err := rows.Scan(&ban.UserID, &ban.Name, &ban.BannedBy, &ban.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan shadow ban: %w", err)
+		}
+		bans = append(bans, ban)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate shadow bans: %w", err)
+	}
+	return bans, nil
+}
+
+// registerAdminShadowBanRoutes mounts the shadow ban endpoints under /admin
+// too, so admins can act in any conversation.
+func registerAdminShadowBanRoutes(r *gin.Engine, adminAuth gin.HandlerFunc, handler *ChatHTTPHandler) {
+	admin := r.Group("/admin", adminAuth)
+	admin.GET("/conversations/:id/shadow-bans", handler.listShadowBans)
+	admin.PUT("/conversations/:id/shadow-bans/:userId", handler.shadowBanUser)
+	admin.DELETE("/conversations/:id/shadow-bans/:userId", handler.liftShadowBan)
+}
+
+// moderationActor is who is moderating: 0 for an admin, else the signed-in
+// user.
+func moderationActor(c *gin.Context) (int64, bool) {
+	if _, ok := c.Get(gin.AuthUserKey); ok {
+		return 0, true
+	}
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// listShadowBans returns the conversation's shadow-banned members.
+//
+// Responses:
+//  - 200 with `bans`
+//  - 401 if the caller has no session
+//  - 400 for invalid conversation id
+//  - 403 if the caller isn't the host, a co-host, or an admin
+//  - 404 if the conversation doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) listShadowBans(c *gin.Context) {
+	actorID, ok := moderationActor(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	bans, err := h.repo.ListShadowBans(ctx, conversationID, actorID)
+	if err != nil {
+		respondShadowBanError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+// shadowBanUser shadow-bans a member. Nothing is posted to the chat and the
+// member isn't told.
+//
+// Responses:
+//  - 204 on success (also when already banned)
+//  - 401 if the caller has no session
+//  - 400 for invalid path params, or when the caller targets themselves
+//  - 403 if the caller isn't the host, a co-host, or an admin, or the
+//    target is the host
+//  - 404 if the conversation doesn't exist or the target isn't a member
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) shadowBanUser(c *gin.Context) {
+	actorID, ok := moderationActor(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	if userID == actorID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot shadow-ban yourself"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.ShadowBanUser(ctx, conversationID, actorID, userID); err != nil {
+		respondShadowBanError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// liftShadowBan lifts a member's shadow ban.
+//
+// Responses:
+//  - 204 on success (also when there was no ban)
+//  - 401 if the caller has no session
+//  - 400 for invalid path params
+//  - 403 if the caller isn't the host, a co-host, or an admin
+//  - 404 if the conversation doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) liftShadowBan(c *gin.Context) {
+	actorID, ok := moderationActor(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || conversationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.LiftShadowBan(ctx, conversationID, actorID, userID); err != nil {
+		respondShadowBanError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondShadowBanError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrConversationNotFound), errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+	case errors.Is(err, ErrNotConversationMember):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user is not a member of this conversation"})
+	case errors.Is(err, ErrNotConversationModerator):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the host or a co-host can manage shadow bans"})
+	case errors.Is(err, ErrCannotShadowBanHost):
+		c.JSON(http.StatusForbidden, gin.H{"error": "the host can't be shadow-banned"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update shadow ban"})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// smsTimeout bounds a single delivery attempt to the provider.
+const smsTimeout = 5 * time.Second
+
+// SMSSender delivers text messages to E.164 phone numbers. Implementations
+// must be safe for concurrent use.
+type SMSSender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// newSMSSenderFromEnv picks a provider from SMS_PROVIDER: `twilio`, or
+// `console` to log texts instead of sending them in development. Unset
+// disables SMS and returns nil.
+func newSMSSenderFromEnv() (SMSSender, error) {
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("SMS_PROVIDER"))); provider {
+	case "":
+		return nil, nil
+	case "console":
+		return consoleSMSSender{}, nil
+	case "twilio":
+		sender := &twilioSMSSender{
+			client:     &http.Client{Timeout: smsTimeout},
+			accountSID: strings.TrimSpace(os.Getenv("TWILIO_ACCOUNT_SID")),
+			authToken:  strings.TrimSpace(os.Getenv("TWILIO_AUTH_TOKEN")),
+			from:       strings.TrimSpace(os.Getenv("TWILIO_FROM")),
+		}
+		if sender.accountSID == "" || sender.authToken == "" || sender.from == "" {
+			return nil, errors.New("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM are required for SMS_PROVIDER=twilio")
+		}
+		return sender, nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_PROVIDER %q", provider)
+	}
+}
+
+// consoleSMSSender logs each text, so phone sign-in can be tried locally.
+type consoleSMSSender struct{}
+
+func (consoleSMSSender) Send(ctx context.Context, to, body string) error {
+	log.Printf("sms to %s: %s", to, body)
+	return nil
+}
+
+// twilioSMSSender sends through Twilio's Messages API.
+type twilioSMSSender struct {
+	client     *http.Client
+	accountSID string
+	authToken  string
+	from       string
+}
+
+func (s *twilioSMSSender) Send(ctx context.Context, to, body string) error {
+	endpoint := "https://api.twilio.com/2010-04-01/Accounts/" + url.PathEscape(s.accountSID) + "/Messages.json"
+	form := url.Values{"To": {to}, "From": {s.from}, "Body": {body}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("twilio status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// selectReadCursor backs unread counts, which run once per conversation on
+// every inbox load.
+const selectReadCursor = `
+SELECT last_read_message_id
+FROM conversation_read_state
+WHERE conversation_id = ? AND user_id = ?;
+`
+
+// prepared returns query prepared on the pool, preparing it on first use.
+// It is meant for the few hot chat-path queries; database/sql re-prepares
+// the statement on each connection that later runs it. Inside a transaction,
+// bind it with tx.StmtContext.
+func (r *EventRepository) prepared(ctx context.Context, query string) (*sql.Stmt, error) {
+	r.stmtMu.Lock()
+	defer r.stmtMu.Unlock()
+
+	if stmt, ok := r.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare statement: %w", err)
+	}
+	r.stmts[query] = stmt
+	return stmt, nil
+}
+
+// Close releases the cached statements. The database itself belongs to the
+// caller of NewEventRepository.
+func (r *EventRepository) Close() error {
+	r.stmtMu.Lock()
+	defer r.stmtMu.Unlock()
+
+	var firstErr error
+	for query, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("close statement: %w", err)
+		}
+		delete(r.stmts, query)
+	}
+	return firstErr
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// The handlers, the middleware and the chat hub depend on these interfaces
+// rather than on *EventRepository, so they can be exercised against the
+// generated StoreMock without SQLite. *EventRepository implements all of
+// them.
+
+//go:generate moq -out store_mock_test.go . Store
+
+// EventStore covers events and everything hosts do to them: bookmarks, the
+// feed, join requests, rosters, bans and invites.
+type EventStore interface {
+	List(ctx context.Context, opts EventListOptions) ([]Event, error)
+	Create(ctx context.Context, params CreateEventParams) (int64, error)
+	Update(ctx context.Context, id int64, userID int64, params UpdateEventParams) ([]EventFieldChange, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	GetEventByID(ctx context.Context, eventID int64) (*Event, error)
+	GetEventsByIDs(ctx context.Context, ids []int64, viewerID int64) (map[int64]Event, error)
+	CanViewEvent(ctx context.Context, event *Event, viewerID int64) (bool, error)
+	TransferEvent(ctx context.Context, eventID, hostID, newHostID int64) (int64, error)
+	ListEventRevisions(ctx context.Context, eventID, hostID int64) ([]EventRevision, error)
+	DuplicateEvent(ctx context.Context, eventID, hostID int64, params DuplicateEventParams) (int64, error)
+	CreateEventTemplate(ctx context.Context, hostID int64, params CreateEventTemplateParams) (*EventTemplate, error)
+	ListEventTemplates(ctx context.Context, userID int64) ([]EventTemplate, error)
+	CreateEventFromTemplate(ctx context.Context, templateID, userID int64, params DuplicateEventParams) (int64, error)
+	DeleteEventTemplate(ctx context.Context, templateID, userID int64) error
+	GetEventSettings(ctx context.Context, eventID int64) (*EventSettings, error)
+	UpdateEventSettings(ctx context.Context, eventID, hostID int64, params UpdateEventSettingsParams) (*EventSettings, error)
+	CreateEventReview(ctx context.Context, eventID, reviewerID int64, params CreateEventReviewParams, now time.Time) (*EventReview, error)
+	ListEventReviews(ctx context.Context, eventID, viewerID int64) ([]EventReview, error)
+	GetVisibleEvent(ctx context.Context, eventID, viewerID int64) (*Event, error)
+	BookmarkEvent(ctx context.Context, userID, eventID int64) error
+	RemoveBookmark(ctx context.Context, userID, eventID int64) error
+	ListBookmarkedEvents(ctx context.Context, userID int64) ([]Event, error)
+	ListHostEvents(ctx context.Context, hostID, viewerID int64, limit int, cursor string) ([]Event, string, error)
+	GetEventFeedVersion(ctx context.Context, opts EventListOptions) (EventFeedVersion, error)
+	ListEventChanges(ctx context.Context, opts EventListOptions, since time.Time) ([]Event, []int64, error)
+	ListTags(ctx context.Context) ([]Tag, error)
+
+	CreateJoinRequest(ctx context.Context, eventID, userID int64, note string) (*ConversationJoinRequest, error)
+	ApproveJoinRequest(ctx context.Context, eventID, userID, approverID int64) (*ConversationJoinRequest, error)
+	DenyJoinRequest(ctx context.Context, eventID, userID, approverID int64) (*ConversationJoinRequest, error)
+	DecideJoinRequests(ctx context.Context, eventID, approverID int64, decisions []JoinDecision) ([]JoinDecisionResult, int64, error)
+	ListJoinRequests(ctx context.Context, eventID, hostID int64, status string) ([]ConversationJoinRequest, error)
+	ListEventMembers(ctx context.Context, eventID, viewerID int64) ([]EventMember, error)
+	RemoveEventMember(ctx context.Context, eventID, userID, actorID int64, ban bool) (*ConversationJoinRequest, error)
+	PromoteWaitlistedUser(ctx context.Context, eventID, userID, hostID int64) (*ConversationJoinRequest, error)
+	SetEventCoHost(ctx context.Context, eventID, hostID, userID int64, coHost bool) (int64, error)
+	ListEventBans(ctx context.Context, eventID, actorID int64) ([]EventBan, error)
+	UnbanEventUser(ctx context.Context, eventID, actorID, userID int64) error
+	AcceptEventInvite(ctx context.Context, eventID, userID, invitedBy int64) (int64, error)
+	CheckInToEvent(ctx context.Context, eventID, userID int64, now time.Time) (time.Time, error)
+	ListEventCheckins(ctx context.Context, eventID, actorID int64) ([]EventCheckin, error)
+	RequireEventModerator(ctx context.Context, eventID, userID int64) error
+	ListMemberEventIDs(ctx context.Context, userID int64) (map[int64]struct{}, error)
+}
+
+// ConversationStore covers conversations, their members and per-user
+// settings.
+type ConversationStore interface {
+	CreateConversation(ctx context.Context, title *string, createdBy int64, memberIDs []int64, eventID *int64) (*Conversation, error)
+	FindOrCreateDirectConversation(ctx context.Context, userID, otherID int64) (*Conversation, bool, error)
+	GetConversation(ctx context.Context, conversationID int64) (*Conversation, error)
+	GetConversationByEventID(ctx context.Context, eventID int64) (*Conversation, error)
+	GetConversationDetail(ctx context.Context, conversationID, viewerID int64) (*ConversationDetail, error)
+	ListConversations(ctx context.Context, userID int64, opts ConversationListOptions) ([]ConversationSummary, string, error)
+	AddConversationMembers(ctx context.Context, conversationID, actorID int64, userIDs []int64) ([]int64, error)
+	RenameConversation(ctx context.Context, conversationID, actorID int64, title string) error
+	IsConversationMember(ctx context.Context, conversationID, userID int64) (bool, error)
+	ArchiveConversation(ctx context.Context, conversationID, userID int64) error
+	UnarchiveConversation(ctx context.Context, conversationID, userID int64) error
+	MuteConversation(ctx context.Context, conversationID, userID int64, until *time.Time) (time.Time, error)
+	UnmuteConversation(ctx context.Context, conversationID, userID int64) error
+	CountUnread(ctx context.Context, userID int64) (int, int, error)
+	SetSlowMode(ctx context.Context, conversationID, actorID int64, seconds int) error
+	HydrateConversationSummary(ctx context.Context, convo Conversation, viewerID int64) (ConversationSummary, error)
+}
+
+// MessageStore covers messages, read state, send deduplication, scheduled
+// messages and the push tokens a message is delivered to.
+type MessageStore interface {
+	ListMessages(ctx context.Context, conversationID, viewerID int64, limit, offset int) ([]Message, error)
+	GetMessageByID(ctx context.Context, id int64) (*Message, error)
+	CreateMessage(ctx context.Context, params CreateMessageParams) (*Message, error)
+	CreateSystemMessage(ctx context.Context, conversationID, actorID int64, body string) (*Message, error)
+	UpdateReadState(ctx context.Context, conversationID, userID, lastReadMessageID int64) error
+	MarkConversationRead(ctx context.Context, conversationID, userID, messageID int64) (int, error)
+	LookupIdempotencyKey(ctx context.Context, userID int64, scope, key string) (int64, bool, error)
+	SlowModeWait(ctx context.Context, conversationID, userID int64, now time.Time) (time.Duration, error)
+	ScheduleMessage(ctx context.Context, conversationID, senderID int64, body string, sendAt time.Time) (*ScheduledMessage, error)
+	ListScheduledMessages(ctx context.Context, conversationID, senderID int64) ([]ScheduledMessage, error)
+	CancelScheduledMessage(ctx context.Context, conversationID, senderID, scheduledID int64) error
+	ListPushTokensForConversation(ctx context.Context, msg Message, now time.Time) ([]string, error)
+	ListPushTokensForMentions(ctx context.Context, messageID int64) ([]string, error)
+	ListPushTokensForUser(ctx context.Context, userID int64, category notificationCategory) ([]string, error)
+}
+
+// PollStore covers in-chat polls and their votes.
+type PollStore interface {
+	CreatePoll(ctx context.Context, conversationID, userID int64, params CreatePollParams) (*Message, error)
+	GetPoll(ctx context.Context, pollID int64) (*Poll, error)
+	VotePoll(ctx context.Context, conversationID, pollID, userID int64, optionIDs []int64) (*Poll, error)
+	RetractPollVote(ctx context.Context, conversationID, pollID, userID int64) (*Poll, error)
+	ClosePoll(ctx context.Context, conversationID, pollID, userID int64) (*Poll, error)
+	CreateTimePoll(ctx context.Context, eventID, hostID int64, starts []time.Time) (*TimePoll, error)
+	GetTimePoll(ctx context.Context, eventID int64) (*TimePoll, error)
+	SetTimePollAvailability(ctx context.Context, eventID, userID int64, slotIDs []int64) (*TimePoll, error)
+	ConfirmTimeSlot(ctx context.Context, eventID, hostID, slotID int64, now time.Time) (*TimePoll, error)
+	DeleteTimePoll(ctx context.Context, eventID, hostID int64) error
+}
+
+// ModerationStore covers the message filters' word list and review queue,
+// and shadow bans.
+type ModerationStore interface {
+	ListFilteredWords(ctx context.Context) ([]FilteredWord, error)
+	AddFilteredWords(ctx context.Context, words []string, action *string) error
+	DeleteFilteredWord(ctx context.Context, word string) error
+	CountRecentIdenticalMessages(ctx context.Context, senderID int64, body string, since time.Time) (int, error)
+	FlagMessage(ctx context.Context, messageID int64, filter, action, reason string) error
+	ListMessageFlags(ctx context.Context) ([]MessageFlag, error)
+	GetMemberActivity(ctx context.Context, conversationID, userID int64) (*MemberActivity, error)
+	IsShadowBanned(ctx context.Context, conversationID, userID int64) (bool, error)
+	IsConversationLocked(ctx context.Context, conversationID int64) (bool, error)
+	ShadowBanUser(ctx context.Context, conversationID, actorID, userID int64) error
+	LiftShadowBan(ctx context.Context, conversationID, actorID, userID int64) error
+	ListShadowBans(ctx context.Context, conversationID, actorID int64) ([]ShadowBan, error)
+}
+
+// UserStore covers accounts as seen by sign-in and the chat layer.
+type UserStore interface {
+	AuthenticateUser(ctx context.Context, email, password string) (*User, error)
+	GetUserNames(ctx context.Context, userIDs []int64) (map[int64]string, error)
+	GetUserProfile(ctx context.Context, userID int64) (*UserProfile, error)
+	IsUserDeleted(ctx context.Context, userID int64) (bool, error)
+	CreateSession(ctx context.Context, userID int64, deviceName, ip, location string, expiresAt time.Time) (int64, error)
+	TouchSession(ctx context.Context, claims *sessionClaims, ip string, now time.Time) (bool, error)
+	UserRoles(ctx context.Context, userID int64) ([]string, error)
+	AreConnected(ctx context.Context, userID, otherID int64) (bool, error)
+	GetEmailRecipient(ctx context.Context, userID int64, category notificationCategory) (*emailRecipient, error)
+	CreatePasswordResetToken(ctx context.Context, email string, now time.Time) (string, *User, error)
+	ResetPassword(ctx context.Context, token, password string, now time.Time) error
+	CreatePhoneOTP(ctx context.Context, phone string, now time.Time) (string, error)
+	VerifyPhoneOTP(ctx context.Context, phone, code string, now time.Time) error
+	GetUserByPhone(ctx context.Context, phone string) (*User, error)
+	SignInWithIdentity(ctx context.Context, identity externalIdentity) (*User, error)
+	CreateMagicLink(ctx context.Context, email string, now time.Time) (*magicLinkClaims, *User, error)
+	ConsumeMagicLink(ctx context.Context, claims magicLinkClaims, now time.Time) (*User, error)
+}
+
+// AccountStore covers what signed-in users manage about themselves: profile,
+// availability, connections, notification settings, push tokens, sessions and
+// deleting the account.
+type AccountStore interface {
+	UpdateUserProfile(ctx context.Context, userID int64, params UpdateProfileParams) error
+	SetUserInterests(ctx context.Context, userID int64, names []string) error
+	SetUserPhone(ctx context.Context, userID int64, phone string) error
+	DeleteUser(ctx context.Context, userID int64) ([]int64, error)
+	GetAvailability(ctx context.Context, userID int64) (*Availability, error)
+	SetAvailability(ctx context.Context, userID int64, params SetAvailabilityParams) (*Availability, error)
+	ClearAvailability(ctx context.Context, userID int64) error
+	ListAvailabilityFeed(ctx context.Context, viewerID int64) ([]Availability, error)
+	SendConnectionRequest(ctx context.Context, userID, targetID int64) (*Connection, error)
+	RespondToConnectionRequest(ctx context.Context, userID, requesterID int64, accept bool) (*Connection, error)
+	GetConnection(ctx context.Context, connectionID, viewerID int64) (*Connection, error)
+	ListConnections(ctx context.Context, userID int64, status string) ([]Connection, error)
+	RemoveConnection(ctx context.Context, userID, otherID int64) error
+	GetNotificationSettings(ctx context.Context, userID int64) (*NotificationSettings, error)
+	SetNotificationSettings(ctx context.Context, userID int64, settings NotificationSettings) error
+	GetReminderSettings(ctx context.Context, userID int64) (*ReminderSettings, error)
+	SetReminderSettings(ctx context.Context, userID int64, settings ReminderSettings) error
+	RegisterPushToken(ctx context.Context, userID int64, token, platform string) error
+	RemovePushToken(ctx context.Context, userID int64, token string) error
+	ListSessions(ctx context.Context, userID int64, now time.Time) ([]Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID int64, now time.Time) error
+}
+
+// VerificationStore covers verification requests, from the user asking to the
+// moderator deciding.
+type VerificationStore interface {
+	GetVerificationStatus(ctx context.Context, userID int64) (bool, *VerificationRequest, error)
+	SubmitVerificationRequest(ctx context.Context, userID int64, note string) (*VerificationRequest, error)
+	ListVerificationRequests(ctx context.Context, status string) ([]VerificationRequest, error)
+	DecideVerificationRequest(ctx context.Context, requestID int64, approve bool, reviewer, reason string) (*VerificationRequest, error)
+}
+
+// BotStore covers bot accounts, their keys and the chats they post in.
+type BotStore interface {
+	CreateBot(ctx context.Context, ownerID int64, name string) (*Bot, string, error)
+	ListBots(ctx context.Context, ownerID int64) ([]Bot, error)
+	DeleteBot(ctx context.Context, ownerID, botID int64) error
+	AuthenticateBot(ctx context.Context, key string) (*Bot, error)
+	GetBotByUserID(ctx context.Context, userID int64) (*Bot, error)
+	AddBotToConversation(ctx context.Context, conversationID, botID, actorID int64) (*Bot, error)
+	RemoveBotFromConversation(ctx context.Context, conversationID, botID, actorID int64) (*Bot, error)
+	IsBotInConversation(ctx context.Context, botID, conversationID int64) (bool, error)
+}
+
+// WebhookStore covers webhook subscriptions and their delivery log.
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, ownerID int64, params CreateWebhookParams) (*Webhook, string, error)
+	ListWebhooks(ctx context.Context, ownerID int64) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, ownerID, webhookID int64) error
+	ListWebhookDeliveries(ctx context.Context, ownerID, webhookID int64) ([]WebhookDelivery, error)
+}
+
+// APIKeyStore checks the keys services send to the admin routes.
+type APIKeyStore interface {
+	AuthenticateAPIKey(ctx context.Context, secret string, now time.Time) (*APIKey, error)
+}
+
+// Store is everything the handlers, the middleware and the hub use.
+type Store interface {
+	EventStore
+	ConversationStore
+	MessageStore
+	PollStore
+	ModerationStore
+	UserStore
+	AccountStore
+	VerificationStore
+	BotStore
+	WebhookStore
+	APIKeyStore
+}
+
+var _ Store = (*EventRepository)(nil)
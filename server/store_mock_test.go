@@ -0,0 +1,8514 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Ensure, that StoreMock does implement Store.
+// If this is not the case, regenerate this file with moq.
+var _ Store = &StoreMock{}
+
+// StoreMock is a mock implementation of Store.
+//
+//	func TestSomethingThatUsesStore(t *testing.T) {
+//
+//		// make and configure a mocked Store
+//		mockedStore := &StoreMock{
+//			AcceptEventInviteFunc: func(ctx context.Context, eventID int64, userID int64, invitedBy int64) (int64, error) {
+//				panic("mock out the AcceptEventInvite method")
+//			},
+//			AddBotToConversationFunc: func(ctx context.Context, conversationID int64, botID int64, actorID int64) (*Bot, error) {
+//				panic("mock out the AddBotToConversation method")
+//			},
+//			AddConversationMembersFunc: func(ctx context.Context, conversationID int64, actorID int64, userIDs []int64) ([]int64, error) {
+//				panic("mock out the AddConversationMembers method")
+//			},
+//			AddFilteredWordsFunc: func(ctx context.Context, words []string, action *string) error {
+//				panic("mock out the AddFilteredWords method")
+//			},
+//			ApproveJoinRequestFunc: func(ctx context.Context, eventID int64, userID int64, approverID int64) (*ConversationJoinRequest, error) {
+//				panic("mock out the ApproveJoinRequest method")
+//			},
+//			ArchiveConversationFunc: func(ctx context.Context, conversationID int64, userID int64) error {
+//				panic("mock out the ArchiveConversation method")
+//			},
+//			AreConnectedFunc: func(ctx context.Context, userID int64, otherID int64) (bool, error) {
+//				panic("mock out the AreConnected method")
+//			},
+//			AuthenticateAPIKeyFunc: func(ctx context.Context, secret string, now time.Time) (*APIKey, error) {
+//				panic("mock out the AuthenticateAPIKey method")
+//			},
+//			AuthenticateBotFunc: func(ctx context.Context, key string) (*Bot, error) {
+//				panic("mock out the AuthenticateBot method")
+//			},
+//			AuthenticateUserFunc: func(ctx context.Context, email string, password string) (*User, error) {
+//				panic("mock out the AuthenticateUser method")
+//			},
+//			BookmarkEventFunc: func(ctx context.Context, userID int64, eventID int64) error {
+//				panic("mock out the BookmarkEvent method")
+//			},
+//			CanViewEventFunc: func(ctx context.Context, event *Event, viewerID int64) (bool, error) {
+//				panic("mock out the CanViewEvent method")
+//			},
+//			CancelScheduledMessageFunc: func(ctx context.Context, conversationID int64, senderID int64, scheduledID int64) error {
+//				panic("mock out the CancelScheduledMessage method")
+//			},
+//			CheckInToEventFunc: func(ctx context.Context, eventID int64, userID int64, now time.Time) (time.Time, error) {
+//				panic("mock out the CheckInToEvent method")
+//			},
+//			ClearAvailabilityFunc: func(ctx context.Context, userID int64) error {
+//				panic("mock out the ClearAvailability method")
+//			},
+//			ClosePollFunc: func(ctx context.Context, conversationID int64, pollID int64, userID int64) (*Poll, error) {
+//				panic("mock out the ClosePoll method")
+//			},
+//			ConfirmTimeSlotFunc: func(ctx context.Context, eventID int64, hostID int64, slotID int64, now time.Time) (*TimePoll, error) {
+//				panic("mock out the ConfirmTimeSlot method")
+//			},
+//			ConsumeMagicLinkFunc: func(ctx context.Context, claims magicLinkClaims, now time.Time) (*User, error) {
+//				panic("mock out the ConsumeMagicLink method")
+//			},
+//			CountRecentIdenticalMessagesFunc: func(ctx context.Context, senderID int64, body string, since time.Time) (int, error) {
+//				panic("mock out the CountRecentIdenticalMessages method")
+//			},
+//			CountUnreadFunc: func(ctx context.Context, userID int64) (int, int, error) {
+//				panic("mock out the CountUnread method")
+//			},
+//			CreateFunc: func(ctx context.Context, params CreateEventParams) (int64, error) {
+//				panic("mock out the Create method")
+//			},
+//			CreateBotFunc: func(ctx context.Context, ownerID int64, name string) (*Bot, string, error) {
+//				panic("mock out the CreateBot method")
+//			},
+//			CreateConversationFunc: func(ctx context.Context, title *string, createdBy int64, memberIDs []int64, eventID *int64) (*Conversation, error) {
+//				panic("mock out the CreateConversation method")
+//			},
+//			CreateEventFromTemplateFunc: func(ctx context.Context, templateID int64, userID int64, params DuplicateEventParams) (int64, error) {
+//				panic("mock out the CreateEventFromTemplate method")
+//			},
+//			CreateEventReviewFunc: func(ctx context.Context, eventID int64, reviewerID int64, params CreateEventReviewParams, now time.Time) (*EventReview, error) {
+//				panic("mock out the CreateEventReview method")
+//			},
+//			CreateEventTemplateFunc: func(ctx context.Context, hostID int64, params CreateEventTemplateParams) (*EventTemplate, error) {
+//				panic("mock out the CreateEventTemplate method")
+//			},
+//			CreateJoinRequestFunc: func(ctx context.Context, eventID int64, userID int64, note string) (*ConversationJoinRequest, error) {
+//				panic("mock out the CreateJoinRequest method")
+//			},
+//			CreateMagicLinkFunc: func(ctx context.Context, email string, now time.Time) (*magicLinkClaims, *User, error) {
+//				panic("mock out the CreateMagicLink method")
+//			},
+//			CreateMessageFunc: func(ctx context.Context, params CreateMessageParams) (*Message, error) {
+//				panic("mock out the CreateMessage method")
+//			},
+//			CreatePasswordResetTokenFunc: func(ctx context.Context, email string, now time.Time) (string, *User, error) {
+//				panic("mock out the CreatePasswordResetToken method")
+//			},
+//			CreatePhoneOTPFunc: func(ctx context.Context, phone string, now time.Time) (string, error) {
+//				panic("mock out the CreatePhoneOTP method")
+//			},
+//			CreatePollFunc: func(ctx context.Context, conversationID int64, userID int64, params CreatePollParams) (*Message, error) {
+//				panic("mock out the CreatePoll method")
+//			},
+//			CreateSessionFunc: func(ctx context.Context, userID int64, deviceName string, ip string, location string, expiresAt time.Time) (int64, error) {
+//				panic("mock out the CreateSession method")
+//			},
+//			CreateSystemMessageFunc: func(ctx context.Context, conversationID int64, actorID int64, body string) (*Message, error) {
+//				panic("mock out the CreateSystemMessage method")
+//			},
+//			CreateTimePollFunc: func(ctx context.Context, eventID int64, hostID int64, starts []time.Time) (*TimePoll, error) {
+//				panic("mock out the CreateTimePoll method")
+//			},
+//			CreateWebhookFunc: func(ctx context.Context, ownerID int64, params CreateWebhookParams) (*Webhook, string, error) {
+//				panic("mock out the CreateWebhook method")
+//			},
+//			DecideJoinRequestsFunc: func(ctx context.Context, eventID int64, approverID int64, decisions []JoinDecision) ([]JoinDecisionResult, int64, error) {
+//				panic("mock out the DecideJoinRequests method")
+//			},
+//			DecideVerificationRequestFunc: func(ctx context.Context, requestID int64, approve bool, reviewer string, reason string) (*VerificationRequest, error) {
+//				panic("mock out the DecideVerificationRequest method")
+//			},
+//			DeleteFunc: func(ctx context.Context, id int64, userID int64) error {
+//				panic("mock out the Delete method")
+//			},
+//			DeleteBotFunc: func(ctx context.Context, ownerID int64, botID int64) error {
+//				panic("mock out the DeleteBot method")
+//			},
+//			DeleteEventTemplateFunc: func(ctx context.Context, templateID int64, userID int64) error {
+//				panic("mock out the DeleteEventTemplate method")
+//			},
+//			DeleteFilteredWordFunc: func(ctx context.Context, word string) error {
+//				panic("mock out the DeleteFilteredWord method")
+//			},
+//			DeleteTimePollFunc: func(ctx context.Context, eventID int64, hostID int64) error {
+//				panic("mock out the DeleteTimePoll method")
+//			},
+//			DeleteUserFunc: func(ctx context.Context, userID int64) ([]int64, error) {
+//				panic("mock out the DeleteUser method")
+//			},
+//			DeleteWebhookFunc: func(ctx context.Context, ownerID int64, webhookID int64) error {
+//				panic("mock out the DeleteWebhook method")
+//			},
+//			DenyJoinRequestFunc: func(ctx context.Context, eventID int64, userID int64, approverID int64) (*ConversationJoinRequest, error) {
+//				panic("mock out the DenyJoinRequest method")
+//			},
+//			DuplicateEventFunc: func(ctx context.Context, eventID int64, hostID int64, params DuplicateEventParams) (int64, error) {
+//				panic("mock out the DuplicateEvent method")
+//			},
+//			FindOrCreateDirectConversationFunc: func(ctx context.Context, userID int64, otherID int64) (*Conversation, bool, error) {
+//				panic("mock out the FindOrCreateDirectConversation method")
+//			},
+//			FlagMessageFunc: func(ctx context.Context, messageID int64, filter string, action string, reason string) error {
+//				panic("mock out the FlagMessage method")
+//			},
+//			GetAvailabilityFunc: func(ctx context.Context, userID int64) (*Availability, error) {
+//				panic("mock out the GetAvailability method")
+//			},
+//			GetBotByUserIDFunc: func(ctx context.Context, userID int64) (*Bot, error) {
+//				panic("mock out the GetBotByUserID method")
+//			},
+//			GetConnectionFunc: func(ctx context.Context, connectionID int64, viewerID int64) (*Connection, error) {
+//				panic("mock out the GetConnection method")
+//			},
+//			GetConversationFunc: func(ctx context.Context, conversationID int64) (*Conversation, error) {
+//				panic("mock out the GetConversation method")
+//			},
+//			GetConversationByEventIDFunc: func(ctx context.Context, eventID int64) (*Conversation, error) {
+//				panic("mock out the GetConversationByEventID method")
+//			},
+//			GetConversationDetailFunc: func(ctx context.Context, conversationID int64, viewerID int64) (*ConversationDetail, error) {
+//				panic("mock out the GetConversationDetail method")
+//			},
+//			GetEmailRecipientFunc: func(ctx context.Context, userID int64, category notificationCategory) (*emailRecipient, error) {
+//				panic("mock out the GetEmailRecipient method")
+//			},
+//			GetEventByIDFunc: func(ctx context.Context, eventID int64) (*Event, error) {
+//				panic("mock out the GetEventByID method")
+//			},
+//			GetEventFeedVersionFunc: func(ctx context.Context, opts EventListOptions) (EventFeedVersion, error) {
+//				panic("mock out the GetEventFeedVersion method")
+//			},
+//			GetEventSettingsFunc: func(ctx context.Context, eventID int64) (*EventSettings, error) {
+//				panic("mock out the GetEventSettings method")
+//			},
+//			GetEventsByIDsFunc: func(ctx context.Context, ids []int64, viewerID int64) (map[int64]Event, error) {
+//				panic("mock out the GetEventsByIDs method")
+//			},
+//			GetMemberActivityFunc: func(ctx context.Context, conversationID int64, userID int64) (*MemberActivity, error) {
+//				panic("mock out the GetMemberActivity method")
+//			},
+//			GetMessageByIDFunc: func(ctx context.Context, id int64) (*Message, error) {
+//				panic("mock out the GetMessageByID method")
+//			},
+//			GetNotificationSettingsFunc: func(ctx context.Context, userID int64) (*NotificationSettings, error) {
+//				panic("mock out the GetNotificationSettings method")
+//			},
+//			GetPollFunc: func(ctx context.Context, pollID int64) (*Poll, error) {
+//				panic("mock out the GetPoll method")
+//			},
+//			GetReminderSettingsFunc: func(ctx context.Context, userID int64) (*ReminderSettings, error) {
+//				panic("mock out the GetReminderSettings method")
+//			},
+//			GetTimePollFunc: func(ctx context.Context, eventID int64) (*TimePoll, error) {
+//				panic("mock out the GetTimePoll method")
+//			},
+//			GetUserByPhoneFunc: func(ctx context.Context, phone string) (*User, error) {
+//				panic("mock out the GetUserByPhone method")
+//			},
+//			GetUserNamesFunc: func(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+//				panic("mock out the GetUserNames method")
+//			},
+//			GetUserProfileFunc: func(ctx context.Context, userID int64) (*UserProfile, error) {
+//				panic("mock out the GetUserProfile method")
+//			},
+//			GetVerificationStatusFunc: func(ctx context.Context, userID int64) (bool, *VerificationRequest, error) {
+//				panic("mock out the GetVerificationStatus method")
+//			},
+//			GetVisibleEventFunc: func(ctx context.Context, eventID int64, viewerID int64) (*Event, error) {
+//				panic("mock out the GetVisibleEvent method")
+//			},
+//			HydrateConversationSummaryFunc: func(ctx context.Context, convo Conversation, viewerID int64) (ConversationSummary, error) {
+//				panic("mock out the HydrateConversationSummary method")
+//			},
+//			IsBotInConversationFunc: func(ctx context.Context, botID int64, conversationID int64) (bool, error) {
+//				panic("mock out the IsBotInConversation method")
+//			},
+//			IsConversationLockedFunc: func(ctx context.Context, conversationID int64) (bool, error) {
+//				panic("mock out the IsConversationLocked method")
+//			},
+//			IsConversationMemberFunc: func(ctx context.Context, conversationID int64, userID int64) (bool, error) {
+//				panic("mock out the IsConversationMember method")
+//			},
+//			IsShadowBannedFunc: func(ctx context.Context, conversationID int64, userID int64) (bool, error) {
+//				panic("mock out the IsShadowBanned method")
+//			},
+//			IsUserDeletedFunc: func(ctx context.Context, userID int64) (bool, error) {
+//				panic("mock out the IsUserDeleted method")
+//			},
+//			LiftShadowBanFunc: func(ctx context.Context, conversationID int64, actorID int64, userID int64) error {
+//				panic("mock out the LiftShadowBan method")
+//			},
+//			ListFunc: func(ctx context.Context, opts EventListOptions) ([]Event, error) {
+//				panic("mock out the List method")
+//			},
+//			ListAvailabilityFeedFunc: func(ctx context.Context, viewerID int64) ([]Availability, error) {
+//				panic("mock out the ListAvailabilityFeed method")
+//			},
+//			ListBookmarkedEventsFunc: func(ctx context.Context, userID int64) ([]Event, error) {
+//				panic("mock out the ListBookmarkedEvents method")
+//			},
+//			ListBotsFunc: func(ctx context.Context, ownerID int64) ([]Bot, error) {
+//				panic("mock out the ListBots method")
+//			},
+//			ListConnectionsFunc: func(ctx context.Context, userID int64, status string) ([]Connection, error) {
+//				panic("mock out the ListConnections method")
+//			},
+//			ListConversationsFunc: func(ctx context.Context, userID int64, opts ConversationListOptions) ([]ConversationSummary, string, error) {
+//				panic("mock out the ListConversations method")
+//			},
+//			ListEventBansFunc: func(ctx context.Context, eventID int64, actorID int64) ([]EventBan, error) {
+//				panic("mock out the ListEventBans method")
+//			},
+//			ListEventChangesFunc: func(ctx context.Context, opts EventListOptions, since time.Time) ([]Event, []int64, error) {
+//				panic("mock out the ListEventChanges method")
+//			},
+//			ListEventCheckinsFunc: func(ctx context.Context, eventID int64, actorID int64) ([]EventCheckin, error) {
+//				panic("mock out the ListEventCheckins method")
+//			},
+//			ListEventMembersFunc: func(ctx context.Context, eventID int64, viewerID int64) ([]EventMember, error) {
+//				panic("mock out the ListEventMembers method")
+//			},
+//			ListEventReviewsFunc: func(ctx context.Context, eventID int64, viewerID int64) ([]EventReview, error) {
+//				panic("mock out the ListEventReviews method")
+//			},
+//			ListEventRevisionsFunc: func(ctx context.Context, eventID int64, hostID int64) ([]EventRevision, error) {
+//				panic("mock out the ListEventRevisions method")
+//			},
+//			ListEventTemplatesFunc: func(ctx context.Context, userID int64) ([]EventTemplate, error) {
+//				panic("mock out the ListEventTemplates method")
+//			},
+//			ListFilteredWordsFunc: func(ctx context.Context) ([]FilteredWord, error) {
+//				panic("mock out the ListFilteredWords method")
+//			},
+//			ListHostEventsFunc: func(ctx context.Context, hostID int64, viewerID int64, limit int, cursor string) ([]Event, string, error) {
+//				panic("mock out the ListHostEvents method")
+//			},
+//			ListJoinRequestsFunc: func(ctx context.Context, eventID int64, hostID int64, status string) ([]ConversationJoinRequest, error) {
+//				panic("mock out the ListJoinRequests method")
+//			},
+//			ListMemberEventIDsFunc: func(ctx context.Context, userID int64) (map[int64]struct{}, error) {
+//				panic("mock out the ListMemberEventIDs method")
+//			},
+//			ListMessageFlagsFunc: func(ctx context.Context) ([]MessageFlag, error) {
+//				panic("mock out the ListMessageFlags method")
+//			},
+//			ListMessagesFunc: func(ctx context.Context, conversationID int64, viewerID int64, limit int, offset int) ([]Message, error) {
+//				panic("mock out the ListMessages method")
+//			},
+//			ListPushTokensForConversationFunc: func(ctx context.Context, msg Message, now time.Time) ([]string, error) {
+//				panic("mock out the ListPushTokensForConversation method")
+//			},
+//			ListPushTokensForMentionsFunc: func(ctx context.Context, messageID int64) ([]string, error) {
+//				panic("mock out the ListPushTokensForMentions method")
+//			},
+//			ListPushTokensForUserFunc: func(ctx context.Context, userID int64, category notificationCategory) ([]string, error) {
+//				panic("mock out the ListPushTokensForUser method")
+//			},
+//			ListScheduledMessagesFunc: func(ctx context.Context, conversationID int64, senderID int64) ([]ScheduledMessage, error) {
+//				panic("mock out the ListScheduledMessages method")
+//			},
+//			ListSessionsFunc: func(ctx context.Context, userID int64, now time.Time) ([]Session, error) {
+//				panic("mock out the ListSessions method")
+//			},
+//			ListShadowBansFunc: func(ctx context.Context, conversationID int64, actorID int64) ([]ShadowBan, error) {
+//				panic("mock out the ListShadowBans method")
+//			},
+//			ListTagsFunc: func(ctx context.Context) ([]Tag, error) {
+//				panic("mock out the ListTags method")
+//			},
+//			ListVerificationRequestsFunc: func(ctx context.Context, status string) ([]VerificationRequest, error) {
+//				panic("mock out the ListVerificationRequests method")
+//			},
+//			ListWebhookDeliveriesFunc: func(ctx context.Context, ownerID int64, webhookID int64) ([]WebhookDelivery, error) {
+//				panic("mock out the ListWebhookDeliveries method")
+//			},
+//			ListWebhooksFunc: func(ctx context.Context, ownerID int64) ([]Webhook, error) {
+//				panic("mock out the ListWebhooks method")
+//			},
+//			LookupIdempotencyKeyFunc: func(ctx context.Context, userID int64, scope string, key string) (int64, bool, error) {
+//				panic("mock out the LookupIdempotencyKey method")
+//			},
+//			MarkConversationReadFunc: func(ctx context.Context, conversationID int64, userID int64, messageID int64) (int, error) {
+//				panic("mock out the MarkConversationRead method")
+//			},
+//			MuteConversationFunc: func(ctx context.Context, conversationID int64, userID int64, until *time.Time) (time.Time, error) {
+//				panic("mock out the MuteConversation method")
+//			},
+//			PromoteWaitlistedUserFunc: func(ctx context.Context, eventID int64, userID int64, hostID int64) (*ConversationJoinRequest, error) {
+//				panic("mock out the PromoteWaitlistedUser method")
+//			},
+//			RegisterPushTokenFunc: func(ctx context.Context, userID int64, token string, platform string) error {
+//				panic("mock out the RegisterPushToken method")
+//			},
+//			RemoveBookmarkFunc: func(ctx context.Context, userID int64, eventID int64) error {
+//				panic("mock out the RemoveBookmark method")
+//			},
+//			RemoveBotFromConversationFunc: func(ctx context.Context, conversationID int64, botID int64, actorID int64) (*Bot, error) {
+//				panic("mock out the RemoveBotFromConversation method")
+//			},
+//			RemoveConnectionFunc: func(ctx context.Context, userID int64, otherID int64) error {
+//				panic("mock out the RemoveConnection method")
+//			},
+//			RemoveEventMemberFunc: func(ctx context.Context, eventID int64, userID int64, actorID int64, ban bool) (*ConversationJoinRequest, error) {
+//				panic("mock out the RemoveEventMember method")
+//			},
+//			RemovePushTokenFunc: func(ctx context.Context, userID int64, token string) error {
+//				panic("mock out the RemovePushToken method")
+//			},
+//			RenameConversationFunc: func(ctx context.Context, conversationID int64, actorID int64, title string) error {
+//				panic("mock out the RenameConversation method")
+//			},
+//			RequireEventModeratorFunc: func(ctx context.Context, eventID int64, userID int64) error {
+//				panic("mock out the RequireEventModerator method")
+//			},
+//			ResetPasswordFunc: func(ctx context.Context, token string, password string, now time.Time) error {
+//				panic("mock out the ResetPassword method")
+//			},
+//			RespondToConnectionRequestFunc: func(ctx context.Context, userID int64, requesterID int64, accept bool) (*Connection, error) {
+//				panic("mock out the RespondToConnectionRequest method")
+//			},
+//			RetractPollVoteFunc: func(ctx context.Context, conversationID int64, pollID int64, userID int64) (*Poll, error) {
+//				panic("mock out the RetractPollVote method")
+//			},
+//			RevokeSessionFunc: func(ctx context.Context, userID int64, sessionID int64, now time.Time) error {
+//				panic("mock out the RevokeSession method")
+//			},
+//			ScheduleMessageFunc: func(ctx context.Context, conversationID int64, senderID int64, body string, sendAt time.Time) (*ScheduledMessage, error) {
+//				panic("mock out the ScheduleMessage method")
+//			},
+//			SendConnectionRequestFunc: func(ctx context.Context, userID int64, targetID int64) (*Connection, error) {
+//				panic("mock out the SendConnectionRequest method")
+//			},
+//			SetAvailabilityFunc: func(ctx context.Context, userID int64, params SetAvailabilityParams) (*Availability, error) {
+//				panic("mock out the SetAvailability method")
+//			},
+//			SetEventCoHostFunc: func(ctx context.Context, eventID int64, hostID int64, userID int64, coHost bool) (int64, error) {
+//				panic("mock out the SetEventCoHost method")
+//			},
+//			SetNotificationSettingsFunc: func(ctx context.Context, userID int64, settings NotificationSettings) error {
+//				panic("mock out the SetNotificationSettings method")
+//			},
+//			SetReminderSettingsFunc: func(ctx context.Context, userID int64, settings ReminderSettings) error {
+//				panic("mock out the SetReminderSettings method")
+//			},
+//			SetSlowModeFunc: func(ctx context.Context, conversationID int64, actorID int64, seconds int) error {
+//				panic("mock out the SetSlowMode method")
+//			},
+//			SetTimePollAvailabilityFunc: func(ctx context.Context, eventID int64, userID int64, slotIDs []int64) (*TimePoll, error) {
+//				panic("mock out the SetTimePollAvailability method")
+//			},
+//			SetUserInterestsFunc: func(ctx context.Context, userID int64, names []string) error {
+//				panic("mock out the SetUserInterests method")
+//			},
+//			SetUserPhoneFunc: func(ctx context.Context, userID int64, phone string) error {
+//				panic("mock out the SetUserPhone method")
+//			},
+//			ShadowBanUserFunc: func(ctx context.Context, conversationID int64, actorID int64, userID int64) error {
+//				panic("mock out the ShadowBanUser method")
+//			},
+//			SignInWithIdentityFunc: func(ctx context.Context, identity externalIdentity) (*User, error) {
+//				panic("mock out the SignInWithIdentity method")
+//			},
+//			SlowModeWaitFunc: func(ctx context.Context, conversationID int64, userID int64, now time.Time) (time.Duration, error) {
+//				panic("mock out the SlowModeWait method")
+//			},
+//			SubmitVerificationRequestFunc: func(ctx context.Context, userID int64, note string) (*VerificationRequest, error) {
+//				panic("mock out the SubmitVerificationRequest method")
+//			},
+//			TouchSessionFunc: func(ctx context.Context, claims *sessionClaims, ip string, now time.Time) (bool, error) {
+//				panic("mock out the TouchSession method")
+//			},
+//			TransferEventFunc: func(ctx context.Context, eventID int64, hostID int64, newHostID int64) (int64, error) {
+//				panic("mock out the TransferEvent method")
+//			},
+//			UnarchiveConversationFunc: func(ctx context.Context, conversationID int64, userID int64) error {
+//				panic("mock out the UnarchiveConversation method")
+//			},
+//			UnbanEventUserFunc: func(ctx context.Context, eventID int64, actorID int64, userID int64) error {
+//				panic("mock out the UnbanEventUser method")
+//			},
+//			UnmuteConversationFunc: func(ctx context.Context, conversationID int64, userID int64) error {
+//				panic("mock out the UnmuteConversation method")
+//			},
+//			UpdateFunc: func(ctx context.Context, id int64, userID int64, params UpdateEventParams) ([]EventFieldChange, error) {
+//				panic("mock out the Update method")
+//			},
+//			UpdateEventSettingsFunc: func(ctx context.Context, eventID int64, hostID int64, params UpdateEventSettingsParams) (*EventSettings, error) {
+//				panic("mock out the UpdateEventSettings method")
+//			},
+//			UpdateReadStateFunc: func(ctx context.Context, conversationID int64, userID int64, lastReadMessageID int64) error {
+//				panic("mock out the UpdateReadState method")
+//			},
+//			UpdateUserProfileFunc: func(ctx context.Context, userID int64, params UpdateProfileParams) error {
+//				panic("mock out the UpdateUserProfile method")
+//			},
+//			UserRolesFunc: func(ctx context.Context, userID int64) ([]string, error) {
+//				panic("mock out the UserRoles method")
+//			},
+//			VerifyPhoneOTPFunc: func(ctx context.Context, phone string, code string, now time.Time) error {
+//				panic("mock out the VerifyPhoneOTP method")
+//			},
+//			VotePollFunc: func(ctx context.Context, conversationID int64, pollID int64, userID int64, optionIDs []int64) (*Poll, error) {
+//				panic("mock out the VotePoll method")
+//			},
+//		}
+//
+//		// use mockedStore in code that requires Store
+//		// and then make assertions.
+//
+//	}
+type StoreMock struct {
+	// AcceptEventInviteFunc mocks the AcceptEventInvite method.
+	AcceptEventInviteFunc func(ctx context.Context, eventID int64, userID int64, invitedBy int64) (int64, error)
+
+	// AddBotToConversationFunc mocks the AddBotToConversation method.
+	AddBotToConversationFunc func(ctx context.Context, conversationID int64, botID int64, actorID int64) (*Bot, error)
+
+	// AddConversationMembersFunc mocks the AddConversationMembers method.
+	AddConversationMembersFunc func(ctx context.Context, conversationID int64, actorID int64, userIDs []int64) ([]int64, error)
+
+	// AddFilteredWordsFunc mocks the AddFilteredWords method.
+	AddFilteredWordsFunc func(ctx context.Context, words []string, action *string) error
+
+	// ApproveJoinRequestFunc mocks the ApproveJoinRequest method.
+	ApproveJoinRequestFunc func(ctx context.Context, eventID int64, userID int64, approverID int64) (*ConversationJoinRequest, error)
+
+	// ArchiveConversationFunc mocks the ArchiveConversation method.
+	ArchiveConversationFunc func(ctx context.Context, conversationID int64, userID int64) error
+
+	// AreConnectedFunc mocks the AreConnected method.
+	AreConnectedFunc func(ctx context.Context, userID int64, otherID int64) (bool, error)
+
+	// AuthenticateAPIKeyFunc mocks the AuthenticateAPIKey method.
+	AuthenticateAPIKeyFunc func(ctx context.Context, secret string, now time.Time) (*APIKey, error)
+
+	// AuthenticateBotFunc mocks the AuthenticateBot method.
+	AuthenticateBotFunc func(ctx context.Context, key string) (*Bot, error)
+
+	// AuthenticateUserFunc mocks the AuthenticateUser method.
+	AuthenticateUserFunc func(ctx context.Context, email string, password string) (*User, error)
+
+	// BookmarkEventFunc mocks the BookmarkEvent method.
+	BookmarkEventFunc func(ctx context.Context, userID int64, eventID int64) error
+
+	// CanViewEventFunc mocks the CanViewEvent method.
+	CanViewEventFunc func(ctx context.Context, event *Event, viewerID int64) (bool, error)
+
+	// CancelScheduledMessageFunc mocks the CancelScheduledMessage method.
+	CancelScheduledMessageFunc func(ctx context.Context, conversationID int64, senderID int64, scheduledID int64) error
+
+	// CheckInToEventFunc mocks the CheckInToEvent method.
+	CheckInToEventFunc func(ctx context.Context, eventID int64, userID int64, now time.Time) (time.Time, error)
+
+	// ClearAvailabilityFunc mocks the ClearAvailability method.
+	ClearAvailabilityFunc func(ctx context.Context, userID int64) error
+
+	// ClosePollFunc mocks the ClosePoll method.
+	ClosePollFunc func(ctx context.Context, conversationID int64, pollID int64, userID int64) (*Poll, error)
+
+	// ConfirmTimeSlotFunc mocks the ConfirmTimeSlot method.
+	ConfirmTimeSlotFunc func(ctx context.Context, eventID int64, hostID int64, slotID int64, now time.Time) (*TimePoll, error)
+
+	// ConsumeMagicLinkFunc mocks the ConsumeMagicLink method.
+	ConsumeMagicLinkFunc func(ctx context.Context, claims magicLinkClaims, now time.Time) (*User, error)
+
+	// CountRecentIdenticalMessagesFunc mocks the CountRecentIdenticalMessages method.
+	CountRecentIdenticalMessagesFunc func(ctx context.Context, senderID int64, body string, since time.Time) (int, error)
+
+	// CountUnreadFunc mocks the CountUnread method.
+	CountUnreadFunc func(ctx context.Context, userID int64) (int, int, error)
+
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, params CreateEventParams) (int64, error)
+
+	// CreateBotFunc mocks the CreateBot method.
+	CreateBotFunc func(ctx context.Context, ownerID int64, name string) (*Bot, string, error)
+
+	// CreateConversationFunc mocks the CreateConversation method.
+	CreateConversationFunc func(ctx context.Context, title *string, createdBy int64, memberIDs []int64, eventID *int64) (*Conversation, error)
+
+	// CreateEventFromTemplateFunc mocks the CreateEventFromTemplate method.
+	CreateEventFromTemplateFunc func(ctx context.Context, templateID int64, userID int64, params DuplicateEventParams) (int64, error)
+
+	// CreateEventReviewFunc mocks the CreateEventReview method.
+	CreateEventReviewFunc func(ctx context.Context, eventID int64, reviewerID int64, params CreateEventReviewParams, now time.Time) (*EventReview, error)
+
+	// CreateEventTemplateFunc mocks the CreateEventTemplate method.
+	CreateEventTemplateFunc func(ctx context.Context, hostID int64, params CreateEventTemplateParams) (*EventTemplate, error)
+
+	// CreateJoinRequestFunc mocks the CreateJoinRequest method.
+	CreateJoinRequestFunc func(ctx context.Context, eventID int64, userID int64, note string) (*ConversationJoinRequest, error)
+
+	// CreateMagicLinkFunc mocks the CreateMagicLink method.
+	CreateMagicLinkFunc func(ctx context.Context, email string, now time.Time) (*magicLinkClaims, *User, error)
+
+	// CreateMessageFunc mocks the CreateMessage method.
+	CreateMessageFunc func(ctx context.Context, params CreateMessageParams) (*Message, error)
+
+	// CreatePasswordResetTokenFunc mocks the CreatePasswordResetToken method.
+	CreatePasswordResetTokenFunc func(ctx context.Context, email string, now time.Time) (string, *User, error)
+
+	// CreatePhoneOTPFunc mocks the CreatePhoneOTP method.
+	CreatePhoneOTPFunc func(ctx context.Context, phone string, now time.Time) (string, error)
+
+	// CreatePollFunc mocks the CreatePoll method.
+	CreatePollFunc func(ctx context.Context, conversationID int64, userID int64, params CreatePollParams) (*Message, error)
+
+	// CreateSessionFunc mocks the CreateSession method.
+	CreateSessionFunc func(ctx context.Context, userID int64, deviceName string, ip string, location string, expiresAt time.Time) (int64, error)
+
+	// CreateSystemMessageFunc mocks the CreateSystemMessage method.
+	CreateSystemMessageFunc func(ctx context.Context, conversationID int64, actorID int64, body string) (*Message, error)
+
+	// CreateTimePollFunc mocks the CreateTimePoll method.
+	CreateTimePollFunc func(ctx context.Context, eventID int64, hostID int64, starts []time.Time) (*TimePoll, error)
+
+	// CreateWebhookFunc mocks the CreateWebhook method.
+	CreateWebhookFunc func(ctx context.Context, ownerID int64, params CreateWebhookParams) (*Webhook, string, error)
+
+	// DecideJoinRequestsFunc mocks the DecideJoinRequests method.
+	DecideJoinRequestsFunc func(ctx context.Context, eventID int64, approverID int64, decisions []JoinDecision) ([]JoinDecisionResult, int64, error)
+
+	// DecideVerificationRequestFunc mocks the DecideVerificationRequest method.
+	DecideVerificationRequestFunc func(ctx context.Context, requestID int64, approve bool, reviewer string, reason string) (*VerificationRequest, error)
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, id int64, userID int64) error
+
+	// DeleteBotFunc mocks the DeleteBot method.
+	DeleteBotFunc func(ctx context.Context, ownerID int64, botID int64) error
+
+	// DeleteEventTemplateFunc mocks the DeleteEventTemplate method.
+	DeleteEventTemplateFunc func(ctx context.Context, templateID int64, userID int64) error
+
+	// DeleteFilteredWordFunc mocks the DeleteFilteredWord method.
+	DeleteFilteredWordFunc func(ctx context.Context, word string) error
+
+	// DeleteTimePollFunc mocks the DeleteTimePoll method.
+	DeleteTimePollFunc func(ctx context.Context, eventID int64, hostID int64) error
+
+	// DeleteUserFunc mocks the DeleteUser method.
+	DeleteUserFunc func(ctx context.Context, userID int64) ([]int64, error)
+
+	// DeleteWebhookFunc mocks the DeleteWebhook method.
+	DeleteWebhookFunc func(ctx context.Context, ownerID int64, webhookID int64) error
+
+	// DenyJoinRequestFunc mocks the DenyJoinRequest method.
+	DenyJoinRequestFunc func(ctx context.Context, eventID int64, userID int64, approverID int64) (*ConversationJoinRequest, error)
+
+	// DuplicateEventFunc mocks the DuplicateEvent method.
+	DuplicateEventFunc func(ctx context.Context, eventID int64, hostID int64, params DuplicateEventParams) (int64, error)
+
+	// FindOrCreateDirectConversationFunc mocks the FindOrCreateDirectConversation method.
+	FindOrCreateDirectConversationFunc func(ctx context.Context, userID int64, otherID int64) (*Conversation, bool, error)
+
+	// FlagMessageFunc mocks the FlagMessage method.
+	FlagMessageFunc func(ctx context.Context, messageID int64, filter string, action string, reason string) error
+
+	// GetAvailabilityFunc mocks the GetAvailability method.
+	GetAvailabilityFunc func(ctx context.Context, userID int64) (*Availability, error)
+
+	// GetBotByUserIDFunc mocks the GetBotByUserID method.
+	GetBotByUserIDFunc func(ctx context.Context, userID int64) (*Bot, error)
+
+	// GetConnectionFunc mocks the GetConnection method.
+	GetConnectionFunc func(ctx context.Context, connectionID int64, viewerID int64) (*Connection, error)
+
+	// GetConversationFunc mocks the GetConversation method.
+	GetConversationFunc func(ctx context.Context, conversationID int64) (*Conversation, error)
+
+	// GetConversationByEventIDFunc mocks the GetConversationByEventID method.
+	GetConversationByEventIDFunc func(ctx context.Context, eventID int64) (*Conversation, error)
+
+	// GetConversationDetailFunc mocks the GetConversationDetail method.
+	GetConversationDetailFunc func(ctx context.Context, conversationID int64, viewerID int64) (*ConversationDetail, error)
+
+	// GetEmailRecipientFunc mocks the GetEmailRecipient method.
+	GetEmailRecipientFunc func(ctx context.Context, userID int64, category notificationCategory) (*emailRecipient, error)
+
+	// GetEventByIDFunc mocks the GetEventByID method.
+	GetEventByIDFunc func(ctx context.Context, eventID int64) (*Event, error)
+
+	// GetEventFeedVersionFunc mocks the GetEventFeedVersion method.
+	GetEventFeedVersionFunc func(ctx context.Context, opts EventListOptions) (EventFeedVersion, error)
+
+	// GetEventSettingsFunc mocks the GetEventSettings method.
+	GetEventSettingsFunc func(ctx context.Context, eventID int64) (*EventSettings, error)
+
+	// GetEventsByIDsFunc mocks the GetEventsByIDs method.
+	GetEventsByIDsFunc func(ctx context.Context, ids []int64, viewerID int64) (map[int64]Event, error)
+
+	// GetMemberActivityFunc mocks the GetMemberActivity method.
+	GetMemberActivityFunc func(ctx context.Context, conversationID int64, userID int64) (*MemberActivity, error)
+
+	// GetMessageByIDFunc mocks the GetMessageByID method.
+	GetMessageByIDFunc func(ctx context.Context, id int64) (*Message, error)
+
+	// GetNotificationSettingsFunc mocks the GetNotificationSettings method.
+	GetNotificationSettingsFunc func(ctx context.Context, userID int64) (*NotificationSettings, error)
+
+	// GetPollFunc mocks the GetPoll method.
+	GetPollFunc func(ctx context.Context, pollID int64) (*Poll, error)
+
+	// GetReminderSettingsFunc mocks the GetReminderSettings method.
+	GetReminderSettingsFunc func(ctx context.Context, userID int64) (*ReminderSettings, error)
+
+	// GetTimePollFunc mocks the GetTimePoll method.
+	GetTimePollFunc func(ctx context.Context, eventID int64) (*TimePoll, error)
+
+	// GetUserByPhoneFunc mocks the GetUserByPhone method.
+	GetUserByPhoneFunc func(ctx context.Context, phone string) (*User, error)
+
+	// GetUserNamesFunc mocks the GetUserNames method.
+	GetUserNamesFunc func(ctx context.Context, userIDs []int64) (map[int64]string, error)
+
+	// GetUserProfileFunc mocks the GetUserProfile method.
+	GetUserProfileFunc func(ctx context.Context, userID int64) (*UserProfile, error)
+
+	// GetVerificationStatusFunc mocks the GetVerificationStatus method.
+	GetVerificationStatusFunc func(ctx context.Context, userID int64) (bool, *VerificationRequest, error)
+
+	// GetVisibleEventFunc mocks the GetVisibleEvent method.
+	GetVisibleEventFunc func(ctx context.Context, eventID int64, viewerID int64) (*Event, error)
+
+	// HydrateConversationSummaryFunc mocks the HydrateConversationSummary method.
+	HydrateConversationSummaryFunc func(ctx context.Context, convo Conversation, viewerID int64) (ConversationSummary, error)
+
+	// IsBotInConversationFunc mocks the IsBotInConversation method.
+	IsBotInConversationFunc func(ctx context.Context, botID int64, conversationID int64) (bool, error)
+
+	// IsConversationLockedFunc mocks the IsConversationLocked method.
+	IsConversationLockedFunc func(ctx context.Context, conversationID int64) (bool, error)
+
+	// IsConversationMemberFunc mocks the IsConversationMember method.
+	IsConversationMemberFunc func(ctx context.Context, conversationID int64, userID int64) (bool, error)
+
+	// IsShadowBannedFunc mocks the IsShadowBanned method.
+	IsShadowBannedFunc func(ctx context.Context, conversationID int64, userID int64) (bool, error)
+
+	// IsUserDeletedFunc mocks the IsUserDeleted method.
+	IsUserDeletedFunc func(ctx context.Context, userID int64) (bool, error)
+
+	// LiftShadowBanFunc mocks the LiftShadowBan method.
+	LiftShadowBanFunc func(ctx context.Context, conversationID int64, actorID int64, userID int64) error
+
+	// ListFunc mocks the List method.
+	ListFunc func(ctx context.Context, opts EventListOptions) ([]Event, error)
+
+	// ListAvailabilityFeedFunc mocks the ListAvailabilityFeed method.
+	ListAvailabilityFeedFunc func(ctx context.Context, viewerID int64) ([]Availability, error)
+
+	// ListBookmarkedEventsFunc mocks the ListBookmarkedEvents method.
+	ListBookmarkedEventsFunc func(ctx context.Context, userID int64) ([]Event, error)
+
+	// ListBotsFunc mocks the ListBots method.
+	ListBotsFunc func(ctx context.Context, ownerID int64) ([]Bot, error)
+
+	// ListConnectionsFunc mocks the ListConnections method.
+	ListConnectionsFunc func(ctx context.Context, userID int64, status string) ([]Connection, error)
+
+	// ListConversationsFunc mocks the ListConversations method.
+	ListConversationsFunc func(ctx context.Context, userID int64, opts ConversationListOptions) ([]ConversationSummary, string, error)
+
+	// ListEventBansFunc mocks the ListEventBans method.
+	ListEventBansFunc func(ctx context.Context, eventID int64, actorID int64) ([]EventBan, error)
+
+	// ListEventChangesFunc mocks the ListEventChanges method.
+	ListEventChangesFunc func(ctx context.Context, opts EventListOptions, since time.Time) ([]Event, []int64, error)
+
+	// ListEventCheckinsFunc mocks the ListEventCheckins method.
+	ListEventCheckinsFunc func(ctx context.Context, eventID int64, actorID int64) ([]EventCheckin, error)
+
+	// ListEventMembersFunc mocks the ListEventMembers method.
+	ListEventMembersFunc func(ctx context.Context, eventID int64, viewerID int64) ([]EventMember, error)
+
+	// ListEventReviewsFunc mocks the ListEventReviews method.
+	ListEventReviewsFunc func(ctx context.Context, eventID int64, viewerID int64) ([]EventReview, error)
+
+	// ListEventRevisionsFunc mocks the ListEventRevisions method.
+	ListEventRevisionsFunc func(ctx context.Context, eventID int64, hostID int64) ([]EventRevision, error)
+
+	// ListEventTemplatesFunc mocks the ListEventTemplates method.
+	ListEventTemplatesFunc func(ctx context.Context, userID int64) ([]EventTemplate, error)
+
+	// ListFilteredWordsFunc mocks the ListFilteredWords method.
+	ListFilteredWordsFunc func(ctx context.Context) ([]FilteredWord, error)
+
+	// ListHostEventsFunc mocks the ListHostEvents method.
+	ListHostEventsFunc func(ctx context.Context, hostID int64, viewerID int64, limit int, cursor string) ([]Event, string, error)
+
+	// ListJoinRequestsFunc mocks the ListJoinRequests method.
+	ListJoinRequestsFunc func(ctx context.Context, eventID int64, hostID int64, status string) ([]ConversationJoinRequest, error)
+
+	// ListMemberEventIDsFunc mocks the ListMemberEventIDs method.
+	ListMemberEventIDsFunc func(ctx context.Context, userID int64) (map[int64]struct{}, error)
+
+	// ListMessageFlagsFunc mocks the ListMessageFlags method.
+	ListMessageFlagsFunc func(ctx context.Context) ([]MessageFlag, error)
+
+	// ListMessagesFunc mocks the ListMessages method.
+	ListMessagesFunc func(ctx context.Context, conversationID int64, viewerID int64, limit int, offset int) ([]Message, error)
+
+	// ListPushTokensForConversationFunc mocks the ListPushTokensForConversation method.
+	ListPushTokensForConversationFunc func(ctx context.Context, msg Message, now time.Time) ([]string, error)
+
+	// ListPushTokensForMentionsFunc mocks the ListPushTokensForMentions method.
+	ListPushTokensForMentionsFunc func(ctx context.Context, messageID int64) ([]string, error)
+
+	// ListPushTokensForUserFunc mocks the ListPushTokensForUser method.
+	ListPushTokensForUserFunc func(ctx context.Context, userID int64, category notificationCategory) ([]string, error)
+
+	// ListScheduledMessagesFunc mocks the ListScheduledMessages method.
+	ListScheduledMessagesFunc func(ctx context.Context, conversationID int64, senderID int64) ([]ScheduledMessage, error)
+
+	// ListSessionsFunc mocks the ListSessions method.
+	ListSessionsFunc func(ctx context.Context, userID int64, now time.Time) ([]Session, error)
+
+	// ListShadowBansFunc mocks the ListShadowBans method.
+	ListShadowBansFunc func(ctx context.Context, conversationID int64, actorID int64) ([]ShadowBan, error)
+
+	// ListTagsFunc mocks the ListTags method.
+	ListTagsFunc func(ctx context.Context) ([]Tag, error)
+
+	// ListVerificationRequestsFunc mocks the ListVerificationRequests method.
+	ListVerificationRequestsFunc func(ctx context.Context, status string) ([]VerificationRequest, error)
+
+	// ListWebhookDeliveriesFunc mocks the ListWebhookDeliveries method.
+	ListWebhookDeliveriesFunc func(ctx context.Context, ownerID int64, webhookID int64) ([]WebhookDelivery, error)
+
+	// ListWebhooksFunc mocks the ListWebhooks method.
+	ListWebhooksFunc func(ctx context.Context, ownerID int64) ([]Webhook, error)
+
+	// LookupIdempotencyKeyFunc mocks the LookupIdempotencyKey method.
+	LookupIdempotencyKeyFunc func(ctx context.Context, userID int64, scope string, key string) (int64, bool, error)
+
+	// MarkConversationReadFunc mocks the MarkConversationRead method.
+	MarkConversationReadFunc func(ctx context.Context, conversationID int64, userID int64, messageID int64) (int, error)
+
+	// MuteConversationFunc mocks the MuteConversation method.
+	MuteConversationFunc func(ctx context.Context, conversationID int64, userID int64, until *time.Time) (time.Time, error)
+
+	// PromoteWaitlistedUserFunc mocks the PromoteWaitlistedUser method.
+	PromoteWaitlistedUserFunc func(ctx context.Context, eventID int64, userID int64, hostID int64) (*ConversationJoinRequest, error)
+
+	// RegisterPushTokenFunc mocks the RegisterPushToken method.
+	RegisterPushTokenFunc func(ctx context.Context, userID int64, token string, platform string) error
+
+	// RemoveBookmarkFunc mocks the RemoveBookmark method.
+	RemoveBookmarkFunc func(ctx context.Context, userID int64, eventID int64) error
+
+	// RemoveBotFromConversationFunc mocks the RemoveBotFromConversation method.
+	RemoveBotFromConversationFunc func(ctx context.Context, conversationID int64, botID int64, actorID int64) (*Bot, error)
+
+	// RemoveConnectionFunc mocks the RemoveConnection method.
+	RemoveConnectionFunc func(ctx context.Context, userID int64, otherID int64) error
+
+	// RemoveEventMemberFunc mocks the RemoveEventMember method.
+	RemoveEventMemberFunc func(ctx context.Context, eventID int64, userID int64, actorID int64, ban bool) (*ConversationJoinRequest, error)
+
+	// RemovePushTokenFunc mocks the RemovePushToken method.
+	RemovePushTokenFunc func(ctx context.Context, userID int64, token string) error
+
+	// RenameConversationFunc mocks the RenameConversation method.
+	RenameConversationFunc func(ctx context.Context, conversationID int64, actorID int64, title string) error
+
+	// RequireEventModeratorFunc mocks the RequireEventModerator method.
+	RequireEventModeratorFunc func(ctx context.Context, eventID int64, userID int64) error
+
+	// ResetPasswordFunc mocks the ResetPassword method.
+	ResetPasswordFunc func(ctx context.Context, token string, password string, now time.Time) error
+
+	// RespondToConnectionRequestFunc mocks the RespondToConnectionRequest method.
+	RespondToConnectionRequestFunc func(ctx context.Context, userID int64, requesterID int64, accept bool) (*Connection, error)
+
+	// RetractPollVoteFunc mocks the RetractPollVote method.
+	RetractPollVoteFunc func(ctx context.Context, conversationID int64, pollID int64, userID int64) (*Poll, error)
+
+	// RevokeSessionFunc mocks the RevokeSession method.
+	RevokeSessionFunc func(ctx context.Context, userID int64, sessionID int64, now time.Time) error
+
+	// ScheduleMessageFunc mocks the ScheduleMessage method.
+	ScheduleMessageFunc func(ctx context.Context, conversationID int64, senderID int64, body string, sendAt time.Time) (*ScheduledMessage, error)
+
+	// SendConnectionRequestFunc mocks the SendConnectionRequest method.
+	SendConnectionRequestFunc func(ctx context.Context, userID int64, targetID int64) (*Connection, error)
+
+	// SetAvailabilityFunc mocks the SetAvailability method.
+	SetAvailabilityFunc func(ctx context.Context, userID int64, params SetAvailabilityParams) (*Availability, error)
+
+	// SetEventCoHostFunc mocks the SetEventCoHost method.
+	SetEventCoHostFunc func(ctx context.Context, eventID int64, hostID int64, userID int64, coHost bool) (int64, error)
+
+	// SetNotificationSettingsFunc mocks the SetNotificationSettings method.
+	SetNotificationSettingsFunc func(ctx context.Context, userID int64, settings NotificationSettings) error
+
+	// SetReminderSettingsFunc mocks the SetReminderSettings method.
+	SetReminderSettingsFunc func(ctx context.Context, userID int64, settings ReminderSettings) error
+
+	// SetSlowModeFunc mocks the SetSlowMode method.
+	SetSlowModeFunc func(ctx context.Context, conversationID int64, actorID int64, seconds int) error
+
+	// SetTimePollAvailabilityFunc mocks the SetTimePollAvailability method.
+	SetTimePollAvailabilityFunc func(ctx context.Context, eventID int64, userID int64, slotIDs []int64) (*TimePoll, error)
+
+	// SetUserInterestsFunc mocks the SetUserInterests method.
+	SetUserInterestsFunc func(ctx context.Context, userID int64, names []string) error
+
+	// SetUserPhoneFunc mocks the SetUserPhone method.
+	SetUserPhoneFunc func(ctx context.Context, userID int64, phone string) error
+
+	// ShadowBanUserFunc mocks the ShadowBanUser method.
+	ShadowBanUserFunc func(ctx context.Context, conversationID int64, actorID int64, userID int64) error
+
+	// SignInWithIdentityFunc mocks the SignInWithIdentity method.
+	SignInWithIdentityFunc func(ctx context.Context, identity externalIdentity) (*User, error)
+
+	// SlowModeWaitFunc mocks the SlowModeWait method.
+	SlowModeWaitFunc func(ctx context.Context, conversationID int64, userID int64, now time.Time) (time.Duration, error)
+
+	// SubmitVerificationRequestFunc mocks the SubmitVerificationRequest method.
+	SubmitVerificationRequestFunc func(ctx context.Context, userID int64, note string) (*VerificationRequest, error)
+
+	// TouchSessionFunc mocks the TouchSession method.
+	TouchSessionFunc func(ctx context.Context, claims *sessionClaims, ip string, now time.Time) (bool, error)
+
+	// TransferEventFunc mocks the TransferEvent method.
+	TransferEventFunc func(ctx context.Context, eventID int64, hostID int64, newHostID int64) (int64, error)
+
+	// UnarchiveConversationFunc mocks the UnarchiveConversation method.
+	UnarchiveConversationFunc func(ctx context.Context, conversationID int64, userID int64) error
+
+	// UnbanEventUserFunc mocks the UnbanEventUser method.
+	UnbanEventUserFunc func(ctx context.Context, eventID int64, actorID int64, userID int64) error
+
+	// UnmuteConversationFunc mocks the UnmuteConversation method.
+	UnmuteConversationFunc func(ctx context.Context, conversationID int64, userID int64) error
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, id int64, userID int64, params UpdateEventParams) ([]EventFieldChange, error)
+
+	// UpdateEventSettingsFunc mocks the UpdateEventSettings method.
+	UpdateEventSettingsFunc func(ctx context.Context, eventID int64, hostID int64, params UpdateEventSettingsParams) (*EventSettings, error)
+
+	// UpdateReadStateFunc mocks the UpdateReadState method.
+	UpdateReadStateFunc func(ctx context.Context, conversationID int64, userID int64, lastReadMessageID int64) error
+
+	// UpdateUserProfileFunc mocks the UpdateUserProfile method.
+	UpdateUserProfileFunc func(ctx context.Context, userID int64, params UpdateProfileParams) error
+
+	// UserRolesFunc mocks the UserRoles method.
+	UserRolesFunc func(ctx context.Context, userID int64) ([]string, error)
+
+	// VerifyPhoneOTPFunc mocks the VerifyPhoneOTP method.
+	VerifyPhoneOTPFunc func(ctx context.Context, phone string, code string, now time.Time) error
+
+	// VotePollFunc mocks the VotePoll method.
+	VotePollFunc func(ctx context.Context, conversationID int64, pollID int64, userID int64, optionIDs []int64) (*Poll, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AcceptEventInvite holds details about calls to the AcceptEventInvite method.
+		AcceptEventInvite []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// InvitedBy is the invitedBy argument value.
+			InvitedBy int64
+		}
+		// AddBotToConversation holds details about calls to the AddBotToConversation method.
+		AddBotToConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// BotID is the botID argument value.
+			BotID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+		}
+		// AddConversationMembers holds details about calls to the AddConversationMembers method.
+		AddConversationMembers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// UserIDs is the userIDs argument value.
+			UserIDs []int64
+		}
+		// AddFilteredWords holds details about calls to the AddFilteredWords method.
+		AddFilteredWords []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Words is the words argument value.
+			Words []string
+			// Action is the action argument value.
+			Action *string
+		}
+		// ApproveJoinRequest holds details about calls to the ApproveJoinRequest method.
+		ApproveJoinRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// ApproverID is the approverID argument value.
+			ApproverID int64
+		}
+		// ArchiveConversation holds details about calls to the ArchiveConversation method.
+		ArchiveConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// AreConnected holds details about calls to the AreConnected method.
+		AreConnected []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// OtherID is the otherID argument value.
+			OtherID int64
+		}
+		// AuthenticateAPIKey holds details about calls to the AuthenticateAPIKey method.
+		AuthenticateAPIKey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Secret is the secret argument value.
+			Secret string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// AuthenticateBot holds details about calls to the AuthenticateBot method.
+		AuthenticateBot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+		// AuthenticateUser holds details about calls to the AuthenticateUser method.
+		AuthenticateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+			// Password is the password argument value.
+			Password string
+		}
+		// BookmarkEvent holds details about calls to the BookmarkEvent method.
+		BookmarkEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// EventID is the eventID argument value.
+			EventID int64
+		}
+		// CanViewEvent holds details about calls to the CanViewEvent method.
+		CanViewEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Event is the event argument value.
+			Event *Event
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// CancelScheduledMessage holds details about calls to the CancelScheduledMessage method.
+		CancelScheduledMessage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// SenderID is the senderID argument value.
+			SenderID int64
+			// ScheduledID is the scheduledID argument value.
+			ScheduledID int64
+		}
+		// CheckInToEvent holds details about calls to the CheckInToEvent method.
+		CheckInToEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ClearAvailability holds details about calls to the ClearAvailability method.
+		ClearAvailability []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// ClosePoll holds details about calls to the ClosePoll method.
+		ClosePoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// PollID is the pollID argument value.
+			PollID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// ConfirmTimeSlot holds details about calls to the ConfirmTimeSlot method.
+		ConfirmTimeSlot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// SlotID is the slotID argument value.
+			SlotID int64
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ConsumeMagicLink holds details about calls to the ConsumeMagicLink method.
+		ConsumeMagicLink []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Claims is the claims argument value.
+			Claims magicLinkClaims
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// CountRecentIdenticalMessages holds details about calls to the CountRecentIdenticalMessages method.
+		CountRecentIdenticalMessages []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SenderID is the senderID argument value.
+			SenderID int64
+			// Body is the body argument value.
+			Body string
+			// Since is the since argument value.
+			Since time.Time
+		}
+		// CountUnread holds details about calls to the CountUnread method.
+		CountUnread []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params CreateEventParams
+		}
+		// CreateBot holds details about calls to the CreateBot method.
+		CreateBot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+			// Name is the name argument value.
+			Name string
+		}
+		// CreateConversation holds details about calls to the CreateConversation method.
+		CreateConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Title is the title argument value.
+			Title *string
+			// CreatedBy is the createdBy argument value.
+			CreatedBy int64
+			// MemberIDs is the memberIDs argument value.
+			MemberIDs []int64
+			// EventID is the eventID argument value.
+			EventID *int64
+		}
+		// CreateEventFromTemplate holds details about calls to the CreateEventFromTemplate method.
+		CreateEventFromTemplate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TemplateID is the templateID argument value.
+			TemplateID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Params is the params argument value.
+			Params DuplicateEventParams
+		}
+		// CreateEventReview holds details about calls to the CreateEventReview method.
+		CreateEventReview []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ReviewerID is the reviewerID argument value.
+			ReviewerID int64
+			// Params is the params argument value.
+			Params CreateEventReviewParams
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// CreateEventTemplate holds details about calls to the CreateEventTemplate method.
+		CreateEventTemplate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// HostID is the hostID argument value.
+			HostID int64
+			// Params is the params argument value.
+			Params CreateEventTemplateParams
+		}
+		// CreateJoinRequest holds details about calls to the CreateJoinRequest method.
+		CreateJoinRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Note is the note argument value.
+			Note string
+		}
+		// CreateMagicLink holds details about calls to the CreateMagicLink method.
+		CreateMagicLink []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// CreateMessage holds details about calls to the CreateMessage method.
+		CreateMessage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params CreateMessageParams
+		}
+		// CreatePasswordResetToken holds details about calls to the CreatePasswordResetToken method.
+		CreatePasswordResetToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// CreatePhoneOTP holds details about calls to the CreatePhoneOTP method.
+		CreatePhoneOTP []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Phone is the phone argument value.
+			Phone string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// CreatePoll holds details about calls to the CreatePoll method.
+		CreatePoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Params is the params argument value.
+			Params CreatePollParams
+		}
+		// CreateSession holds details about calls to the CreateSession method.
+		CreateSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// DeviceName is the deviceName argument value.
+			DeviceName string
+			// IP is the ip argument value.
+			IP string
+			// Location is the location argument value.
+			Location string
+			// ExpiresAt is the expiresAt argument value.
+			ExpiresAt time.Time
+		}
+		// CreateSystemMessage holds details about calls to the CreateSystemMessage method.
+		CreateSystemMessage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// Body is the body argument value.
+			Body string
+		}
+		// CreateTimePoll holds details about calls to the CreateTimePoll method.
+		CreateTimePoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// Starts is the starts argument value.
+			Starts []time.Time
+		}
+		// CreateWebhook holds details about calls to the CreateWebhook method.
+		CreateWebhook []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+			// Params is the params argument value.
+			Params CreateWebhookParams
+		}
+		// DecideJoinRequests holds details about calls to the DecideJoinRequests method.
+		DecideJoinRequests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ApproverID is the approverID argument value.
+			ApproverID int64
+			// Decisions is the decisions argument value.
+			Decisions []JoinDecision
+		}
+		// DecideVerificationRequest holds details about calls to the DecideVerificationRequest method.
+		DecideVerificationRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RequestID is the requestID argument value.
+			RequestID int64
+			// Approve is the approve argument value.
+			Approve bool
+			// Reviewer is the reviewer argument value.
+			Reviewer string
+			// Reason is the reason argument value.
+			Reason string
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// DeleteBot holds details about calls to the DeleteBot method.
+		DeleteBot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+			// BotID is the botID argument value.
+			BotID int64
+		}
+		// DeleteEventTemplate holds details about calls to the DeleteEventTemplate method.
+		DeleteEventTemplate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TemplateID is the templateID argument value.
+			TemplateID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// DeleteFilteredWord holds details about calls to the DeleteFilteredWord method.
+		DeleteFilteredWord []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Word is the word argument value.
+			Word string
+		}
+		// DeleteTimePoll holds details about calls to the DeleteTimePoll method.
+		DeleteTimePoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+		}
+		// DeleteUser holds details about calls to the DeleteUser method.
+		DeleteUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// DeleteWebhook holds details about calls to the DeleteWebhook method.
+		DeleteWebhook []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+			// WebhookID is the webhookID argument value.
+			WebhookID int64
+		}
+		// DenyJoinRequest holds details about calls to the DenyJoinRequest method.
+		DenyJoinRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// ApproverID is the approverID argument value.
+			ApproverID int64
+		}
+		// DuplicateEvent holds details about calls to the DuplicateEvent method.
+		DuplicateEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// Params is the params argument value.
+			Params DuplicateEventParams
+		}
+		// FindOrCreateDirectConversation holds details about calls to the FindOrCreateDirectConversation method.
+		FindOrCreateDirectConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// OtherID is the otherID argument value.
+			OtherID int64
+		}
+		// FlagMessage holds details about calls to the FlagMessage method.
+		FlagMessage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// MessageID is the messageID argument value.
+			MessageID int64
+			// Filter is the filter argument value.
+			Filter string
+			// Action is the action argument value.
+			Action string
+			// Reason is the reason argument value.
+			Reason string
+		}
+		// GetAvailability holds details about calls to the GetAvailability method.
+		GetAvailability []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetBotByUserID holds details about calls to the GetBotByUserID method.
+		GetBotByUserID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetConnection holds details about calls to the GetConnection method.
+		GetConnection []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConnectionID is the connectionID argument value.
+			ConnectionID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// GetConversation holds details about calls to the GetConversation method.
+		GetConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+		}
+		// GetConversationByEventID holds details about calls to the GetConversationByEventID method.
+		GetConversationByEventID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+		}
+		// GetConversationDetail holds details about calls to the GetConversationDetail method.
+		GetConversationDetail []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// GetEmailRecipient holds details about calls to the GetEmailRecipient method.
+		GetEmailRecipient []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Category is the category argument value.
+			Category notificationCategory
+		}
+		// GetEventByID holds details about calls to the GetEventByID method.
+		GetEventByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+		}
+		// GetEventFeedVersion holds details about calls to the GetEventFeedVersion method.
+		GetEventFeedVersion []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts EventListOptions
+		}
+		// GetEventSettings holds details about calls to the GetEventSettings method.
+		GetEventSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+		}
+		// GetEventsByIDs holds details about calls to the GetEventsByIDs method.
+		GetEventsByIDs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// GetMemberActivity holds details about calls to the GetMemberActivity method.
+		GetMemberActivity []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetMessageByID holds details about calls to the GetMessageByID method.
+		GetMessageByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetNotificationSettings holds details about calls to the GetNotificationSettings method.
+		GetNotificationSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetPoll holds details about calls to the GetPoll method.
+		GetPoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PollID is the pollID argument value.
+			PollID int64
+		}
+		// GetReminderSettings holds details about calls to the GetReminderSettings method.
+		GetReminderSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetTimePoll holds details about calls to the GetTimePoll method.
+		GetTimePoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+		}
+		// GetUserByPhone holds details about calls to the GetUserByPhone method.
+		GetUserByPhone []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Phone is the phone argument value.
+			Phone string
+		}
+		// GetUserNames holds details about calls to the GetUserNames method.
+		GetUserNames []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserIDs is the userIDs argument value.
+			UserIDs []int64
+		}
+		// GetUserProfile holds details about calls to the GetUserProfile method.
+		GetUserProfile []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetVerificationStatus holds details about calls to the GetVerificationStatus method.
+		GetVerificationStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetVisibleEvent holds details about calls to the GetVisibleEvent method.
+		GetVisibleEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// HydrateConversationSummary holds details about calls to the HydrateConversationSummary method.
+		HydrateConversationSummary []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Convo is the convo argument value.
+			Convo Conversation
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// IsBotInConversation holds details about calls to the IsBotInConversation method.
+		IsBotInConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BotID is the botID argument value.
+			BotID int64
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+		}
+		// IsConversationLocked holds details about calls to the IsConversationLocked method.
+		IsConversationLocked []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+		}
+		// IsConversationMember holds details about calls to the IsConversationMember method.
+		IsConversationMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// IsShadowBanned holds details about calls to the IsShadowBanned method.
+		IsShadowBanned []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// IsUserDeleted holds details about calls to the IsUserDeleted method.
+		IsUserDeleted []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// LiftShadowBan holds details about calls to the LiftShadowBan method.
+		LiftShadowBan []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// List holds details about calls to the List method.
+		List []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts EventListOptions
+		}
+		// ListAvailabilityFeed holds details about calls to the ListAvailabilityFeed method.
+		ListAvailabilityFeed []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// ListBookmarkedEvents holds details about calls to the ListBookmarkedEvents method.
+		ListBookmarkedEvents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// ListBots holds details about calls to the ListBots method.
+		ListBots []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+		}
+		// ListConnections holds details about calls to the ListConnections method.
+		ListConnections []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Status is the status argument value.
+			Status string
+		}
+		// ListConversations holds details about calls to the ListConversations method.
+		ListConversations []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Opts is the opts argument value.
+			Opts ConversationListOptions
+		}
+		// ListEventBans holds details about calls to the ListEventBans method.
+		ListEventBans []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+		}
+		// ListEventChanges holds details about calls to the ListEventChanges method.
+		ListEventChanges []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts EventListOptions
+			// Since is the since argument value.
+			Since time.Time
+		}
+		// ListEventCheckins holds details about calls to the ListEventCheckins method.
+		ListEventCheckins []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+		}
+		// ListEventMembers holds details about calls to the ListEventMembers method.
+		ListEventMembers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// ListEventReviews holds details about calls to the ListEventReviews method.
+		ListEventReviews []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+		}
+		// ListEventRevisions holds details about calls to the ListEventRevisions method.
+		ListEventRevisions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+		}
+		// ListEventTemplates holds details about calls to the ListEventTemplates method.
+		ListEventTemplates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// ListFilteredWords holds details about calls to the ListFilteredWords method.
+		ListFilteredWords []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ListHostEvents holds details about calls to the ListHostEvents method.
+		ListHostEvents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// HostID is the hostID argument value.
+			HostID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+			// Limit is the limit argument value.
+			Limit int
+			// Cursor is the cursor argument value.
+			Cursor string
+		}
+		// ListJoinRequests holds details about calls to the ListJoinRequests method.
+		ListJoinRequests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// Status is the status argument value.
+			Status string
+		}
+		// ListMemberEventIDs holds details about calls to the ListMemberEventIDs method.
+		ListMemberEventIDs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// ListMessageFlags holds details about calls to the ListMessageFlags method.
+		ListMessageFlags []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ListMessages holds details about calls to the ListMessages method.
+		ListMessages []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ViewerID is the viewerID argument value.
+			ViewerID int64
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// ListPushTokensForConversation holds details about calls to the ListPushTokensForConversation method.
+		ListPushTokensForConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Msg is the msg argument value.
+			Msg Message
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ListPushTokensForMentions holds details about calls to the ListPushTokensForMentions method.
+		ListPushTokensForMentions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// MessageID is the messageID argument value.
+			MessageID int64
+		}
+		// ListPushTokensForUser holds details about calls to the ListPushTokensForUser method.
+		ListPushTokensForUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Category is the category argument value.
+			Category notificationCategory
+		}
+		// ListScheduledMessages holds details about calls to the ListScheduledMessages method.
+		ListScheduledMessages []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// SenderID is the senderID argument value.
+			SenderID int64
+		}
+		// ListSessions holds details about calls to the ListSessions method.
+		ListSessions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ListShadowBans holds details about calls to the ListShadowBans method.
+		ListShadowBans []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+		}
+		// ListTags holds details about calls to the ListTags method.
+		ListTags []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ListVerificationRequests holds details about calls to the ListVerificationRequests method.
+		ListVerificationRequests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Status is the status argument value.
+			Status string
+		}
+		// ListWebhookDeliveries holds details about calls to the ListWebhookDeliveries method.
+		ListWebhookDeliveries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+			// WebhookID is the webhookID argument value.
+			WebhookID int64
+		}
+		// ListWebhooks holds details about calls to the ListWebhooks method.
+		ListWebhooks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID int64
+		}
+		// LookupIdempotencyKey holds details about calls to the LookupIdempotencyKey method.
+		LookupIdempotencyKey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Scope is the scope argument value.
+			Scope string
+			// Key is the key argument value.
+			Key string
+		}
+		// MarkConversationRead holds details about calls to the MarkConversationRead method.
+		MarkConversationRead []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// MessageID is the messageID argument value.
+			MessageID int64
+		}
+		// MuteConversation holds details about calls to the MuteConversation method.
+		MuteConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Until is the until argument value.
+			Until *time.Time
+		}
+		// PromoteWaitlistedUser holds details about calls to the PromoteWaitlistedUser method.
+		PromoteWaitlistedUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// HostID is the hostID argument value.
+			HostID int64
+		}
+		// RegisterPushToken holds details about calls to the RegisterPushToken method.
+		RegisterPushToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Token is the token argument value.
+			Token string
+			// Platform is the platform argument value.
+			Platform string
+		}
+		// RemoveBookmark holds details about calls to the RemoveBookmark method.
+		RemoveBookmark []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// EventID is the eventID argument value.
+			EventID int64
+		}
+		// RemoveBotFromConversation holds details about calls to the RemoveBotFromConversation method.
+		RemoveBotFromConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// BotID is the botID argument value.
+			BotID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+		}
+		// RemoveConnection holds details about calls to the RemoveConnection method.
+		RemoveConnection []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// OtherID is the otherID argument value.
+			OtherID int64
+		}
+		// RemoveEventMember holds details about calls to the RemoveEventMember method.
+		RemoveEventMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// Ban is the ban argument value.
+			Ban bool
+		}
+		// RemovePushToken holds details about calls to the RemovePushToken method.
+		RemovePushToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Token is the token argument value.
+			Token string
+		}
+		// RenameConversation holds details about calls to the RenameConversation method.
+		RenameConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// Title is the title argument value.
+			Title string
+		}
+		// RequireEventModerator holds details about calls to the RequireEventModerator method.
+		RequireEventModerator []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// ResetPassword holds details about calls to the ResetPassword method.
+		ResetPassword []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Token is the token argument value.
+			Token string
+			// Password is the password argument value.
+			Password string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// RespondToConnectionRequest holds details about calls to the RespondToConnectionRequest method.
+		RespondToConnectionRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// RequesterID is the requesterID argument value.
+			RequesterID int64
+			// Accept is the accept argument value.
+			Accept bool
+		}
+		// RetractPollVote holds details about calls to the RetractPollVote method.
+		RetractPollVote []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// PollID is the pollID argument value.
+			PollID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// RevokeSession holds details about calls to the RevokeSession method.
+		RevokeSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// SessionID is the sessionID argument value.
+			SessionID int64
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ScheduleMessage holds details about calls to the ScheduleMessage method.
+		ScheduleMessage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// SenderID is the senderID argument value.
+			SenderID int64
+			// Body is the body argument value.
+			Body string
+			// SendAt is the sendAt argument value.
+			SendAt time.Time
+		}
+		// SendConnectionRequest holds details about calls to the SendConnectionRequest method.
+		SendConnectionRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// TargetID is the targetID argument value.
+			TargetID int64
+		}
+		// SetAvailability holds details about calls to the SetAvailability method.
+		SetAvailability []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Params is the params argument value.
+			Params SetAvailabilityParams
+		}
+		// SetEventCoHost holds details about calls to the SetEventCoHost method.
+		SetEventCoHost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// CoHost is the coHost argument value.
+			CoHost bool
+		}
+		// SetNotificationSettings holds details about calls to the SetNotificationSettings method.
+		SetNotificationSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Settings is the settings argument value.
+			Settings NotificationSettings
+		}
+		// SetReminderSettings holds details about calls to the SetReminderSettings method.
+		SetReminderSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Settings is the settings argument value.
+			Settings ReminderSettings
+		}
+		// SetSlowMode holds details about calls to the SetSlowMode method.
+		SetSlowMode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// Seconds is the seconds argument value.
+			Seconds int
+		}
+		// SetTimePollAvailability holds details about calls to the SetTimePollAvailability method.
+		SetTimePollAvailability []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// SlotIDs is the slotIDs argument value.
+			SlotIDs []int64
+		}
+		// SetUserInterests holds details about calls to the SetUserInterests method.
+		SetUserInterests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Names is the names argument value.
+			Names []string
+		}
+		// SetUserPhone holds details about calls to the SetUserPhone method.
+		SetUserPhone []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Phone is the phone argument value.
+			Phone string
+		}
+		// ShadowBanUser holds details about calls to the ShadowBanUser method.
+		ShadowBanUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// SignInWithIdentity holds details about calls to the SignInWithIdentity method.
+		SignInWithIdentity []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Identity is the identity argument value.
+			Identity externalIdentity
+		}
+		// SlowModeWait holds details about calls to the SlowModeWait method.
+		SlowModeWait []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// SubmitVerificationRequest holds details about calls to the SubmitVerificationRequest method.
+		SubmitVerificationRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Note is the note argument value.
+			Note string
+		}
+		// TouchSession holds details about calls to the TouchSession method.
+		TouchSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Claims is the claims argument value.
+			Claims *sessionClaims
+			// IP is the ip argument value.
+			IP string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// TransferEvent holds details about calls to the TransferEvent method.
+		TransferEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// NewHostID is the newHostID argument value.
+			NewHostID int64
+		}
+		// UnarchiveConversation holds details about calls to the UnarchiveConversation method.
+		UnarchiveConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// UnbanEventUser holds details about calls to the UnbanEventUser method.
+		UnbanEventUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// ActorID is the actorID argument value.
+			ActorID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// UnmuteConversation holds details about calls to the UnmuteConversation method.
+		UnmuteConversation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// Params is the params argument value.
+			Params UpdateEventParams
+		}
+		// UpdateEventSettings holds details about calls to the UpdateEventSettings method.
+		UpdateEventSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// EventID is the eventID argument value.
+			EventID int64
+			// HostID is the hostID argument value.
+			HostID int64
+			// Params is the params argument value.
+			Params UpdateEventSettingsParams
+		}
+		// UpdateReadState holds details about calls to the UpdateReadState method.
+		UpdateReadState []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// LastReadMessageID is the lastReadMessageID argument value.
+			LastReadMessageID int64
+		}
+		// UpdateUserProfile holds details about calls to the UpdateUserProfile method.
+		UpdateUserProfile []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Params is the params argument value.
+			Params UpdateProfileParams
+		}
+		// UserRoles holds details about calls to the UserRoles method.
+		UserRoles []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// VerifyPhoneOTP holds details about calls to the VerifyPhoneOTP method.
+		VerifyPhoneOTP []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Phone is the phone argument value.
+			Phone string
+			// Code is the code argument value.
+			Code string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// VotePoll holds details about calls to the VotePoll method.
+		VotePoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ConversationID is the conversationID argument value.
+			ConversationID int64
+			// PollID is the pollID argument value.
+			PollID int64
+			// UserID is the userID argument value.
+			UserID int64
+			// OptionIDs is the optionIDs argument value.
+			OptionIDs []int64
+		}
+	}
+	lockAcceptEventInvite              sync.RWMutex
+	lockAddBotToConversation           sync.RWMutex
+	lockAddConversationMembers         sync.RWMutex
+	lockAddFilteredWords               sync.RWMutex
+	lockApproveJoinRequest             sync.RWMutex
+	lockArchiveConversation            sync.RWMutex
+	lockAreConnected                   sync.RWMutex
+	lockAuthenticateAPIKey             sync.RWMutex
+	lockAuthenticateBot                sync.RWMutex
+	lockAuthenticateUser               sync.RWMutex
+	lockBookmarkEvent                  sync.RWMutex
+	lockCanViewEvent                   sync.RWMutex
+	lockCancelScheduledMessage         sync.RWMutex
+	lockCheckInToEvent                 sync.RWMutex
+	lockClearAvailability              sync.RWMutex
+	lockClosePoll                      sync.RWMutex
+	lockConfirmTimeSlot                sync.RWMutex
+	lockConsumeMagicLink               sync.RWMutex
+	lockCountRecentIdenticalMessages   sync.RWMutex
+	lockCountUnread                    sync.RWMutex
+	lockCreate                         sync.RWMutex
+	lockCreateBot                      sync.RWMutex
+	lockCreateConversation             sync.RWMutex
+	lockCreateEventFromTemplate        sync.RWMutex
+	lockCreateEventReview              sync.RWMutex
+	lockCreateEventTemplate            sync.RWMutex
+	lockCreateJoinRequest              sync.RWMutex
+	lockCreateMagicLink                sync.RWMutex
+	lockCreateMessage                  sync.RWMutex
+	lockCreatePasswordResetToken       sync.RWMutex
+	lockCreatePhoneOTP                 sync.RWMutex
+	lockCreatePoll                     sync.RWMutex
+	lockCreateSession                  sync.RWMutex
+	lockCreateSystemMessage            sync.RWMutex
+	lockCreateTimePoll                 sync.RWMutex
+	lockCreateWebhook                  sync.RWMutex
+	lockDecideJoinRequests             sync.RWMutex
+	lockDecideVerificationRequest      sync.RWMutex
+	lockDelete                         sync.RWMutex
+	lockDeleteBot                      sync.RWMutex
+	lockDeleteEventTemplate            sync.RWMutex
+	lockDeleteFilteredWord             sync.RWMutex
+	lockDeleteTimePoll                 sync.RWMutex
+	lockDeleteUser                     sync.RWMutex
+	lockDeleteWebhook                  sync.RWMutex
+	lockDenyJoinRequest                sync.RWMutex
+	lockDuplicateEvent                 sync.RWMutex
+	lockFindOrCreateDirectConversation sync.RWMutex
+	lockFlagMessage                    sync.RWMutex
+	lockGetAvailability                sync.RWMutex
+	lockGetBotByUserID                 sync.RWMutex
+	lockGetConnection                  sync.RWMutex
+	lockGetConversation                sync.RWMutex
+	lockGetConversationByEventID       sync.RWMutex
+	lockGetConversationDetail          sync.RWMutex
+	lockGetEmailRecipient              sync.RWMutex
+	lockGetEventByID                   sync.RWMutex
+	lockGetEventFeedVersion            sync.RWMutex
+	lockGetEventSettings               sync.RWMutex
+	lockGetEventsByIDs                 sync.RWMutex
+	lockGetMemberActivity              sync.RWMutex
+	lockGetMessageByID                 sync.RWMutex
+	lockGetNotificationSettings        sync.RWMutex
+	lockGetPoll                        sync.RWMutex
+	lockGetReminderSettings            sync.RWMutex
+	lockGetTimePoll                    sync.RWMutex
+	lockGetUserByPhone                 sync.RWMutex
+	lockGetUserNames                   sync.RWMutex
+	lockGetUserProfile                 sync.RWMutex
+	lockGetVerificationStatus          sync.RWMutex
+	lockGetVisibleEvent                sync.RWMutex
+	lockHydrateConversationSummary     sync.RWMutex
+	lockIsBotInConversation            sync.RWMutex
+	lockIsConversationLocked           sync.RWMutex
+	lockIsConversationMember           sync.RWMutex
+	lockIsShadowBanned                 sync.RWMutex
+	lockIsUserDeleted                  sync.RWMutex
+	lockLiftShadowBan                  sync.RWMutex
+	lockList                           sync.RWMutex
+	lockListAvailabilityFeed           sync.RWMutex
+	lockListBookmarkedEvents           sync.RWMutex
+	lockListBots                       sync.RWMutex
+	lockListConnections                sync.RWMutex
+	lockListConversations              sync.RWMutex
+	lockListEventBans                  sync.RWMutex
+	lockListEventChanges               sync.RWMutex
+	lockListEventCheckins              sync.RWMutex
+	lockListEventMembers               sync.RWMutex
+	lockListEventReviews               sync.RWMutex
+	lockListEventRevisions             sync.RWMutex
+	lockListEventTemplates             sync.RWMutex
+	lockListFilteredWords              sync.RWMutex
+	lockListHostEvents                 sync.RWMutex
+	lockListJoinRequests               sync.RWMutex
+	lockListMemberEventIDs             sync.RWMutex
+	lockListMessageFlags               sync.RWMutex
+	lockListMessages                   sync.RWMutex
+	lockListPushTokensForConversation  sync.RWMutex
+	lockListPushTokensForMentions      sync.RWMutex
+	lockListPushTokensForUser          sync.RWMutex
+	lockListScheduledMessages          sync.RWMutex
+	lockListSessions                   sync.RWMutex
+	lockListShadowBans                 sync.RWMutex
+	lockListTags                       sync.RWMutex
+	lockListVerificationRequests       sync.RWMutex
+	lockListWebhookDeliveries          sync.RWMutex
+	lockListWebhooks                   sync.RWMutex
+	lockLookupIdempotencyKey           sync.RWMutex
+	lockMarkConversationRead           sync.RWMutex
+	lockMuteConversation               sync.RWMutex
+	lockPromoteWaitlistedUser          sync.RWMutex
+	lockRegisterPushToken              sync.RWMutex
+	lockRemoveBookmark                 sync.RWMutex
+	lockRemoveBotFromConversation      sync.RWMutex
+	lockRemoveConnection               sync.RWMutex
+	lockRemoveEventMember              sync.RWMutex
+	lockRemovePushToken                sync.RWMutex
+	lockRenameConversation             sync.RWMutex
+	lockRequireEventModerator          sync.RWMutex
+	lockResetPassword                  sync.RWMutex
+	lockRespondToConnectionRequest     sync.RWMutex
+	lockRetractPollVote                sync.RWMutex
+	lockRevokeSession                  sync.RWMutex
+	lockScheduleMessage                sync.RWMutex
+	lockSendConnectionRequest          sync.RWMutex
+	lockSetAvailability                sync.RWMutex
+	lockSetEventCoHost                 sync.RWMutex
+	lockSetNotificationSettings        sync.RWMutex
+	lockSetReminderSettings            sync.RWMutex
+	lockSetSlowMode                    sync.RWMutex
+	lockSetTimePollAvailability        sync.RWMutex
+	lockSetUserInterests               sync.RWMutex
+	lockSetUserPhone                   sync.RWMutex
+	lockShadowBanUser                  sync.RWMutex
+	lockSignInWithIdentity             sync.RWMutex
+	lockSlowModeWait                   sync.RWMutex
+	lockSubmitVerificationRequest      sync.RWMutex
+	lockTouchSession                   sync.RWMutex
+	lockTransferEvent                  sync.RWMutex
+	lockUnarchiveConversation          sync.RWMutex
+	lockUnbanEventUser                 sync.RWMutex
+	lockUnmuteConversation             sync.RWMutex
+	lockUpdate                         sync.RWMutex
+	lockUpdateEventSettings            sync.RWMutex
+	lockUpdateReadState                sync.RWMutex
+	lockUpdateUserProfile              sync.RWMutex
+	lockUserRoles                      sync.RWMutex
+	lockVerifyPhoneOTP                 sync.RWMutex
+	lockVotePoll                       sync.RWMutex
+}
+
+// AcceptEventInvite calls AcceptEventInviteFunc.
+func (mock *StoreMock) AcceptEventInvite(ctx context.Context, eventID int64, userID int64, invitedBy int64) (int64, error) {
+	if mock.AcceptEventInviteFunc == nil {
+		panic("StoreMock.AcceptEventInviteFunc: method is nil but Store.AcceptEventInvite was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		EventID   int64
+		UserID    int64
+		InvitedBy int64
+	}{
+		Ctx:       ctx,
+		EventID:   eventID,
+		UserID:    userID,
+		InvitedBy: invitedBy,
+	}
+	mock.lockAcceptEventInvite.Lock()
+	mock.calls.AcceptEventInvite = append(mock.calls.AcceptEventInvite, callInfo)
+	mock.lockAcceptEventInvite.Unlock()
+	return mock.AcceptEventInviteFunc(ctx, eventID, userID, invitedBy)
+}
+
+// AcceptEventInviteCalls gets all the calls that were made to AcceptEventInvite.
+// Check the length with:
+//
+//	len(mockedStore.AcceptEventInviteCalls())
+func (mock *StoreMock) AcceptEventInviteCalls() []struct {
+	Ctx       context.Context
+	EventID   int64
+	UserID    int64
+	InvitedBy int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		EventID   int64
+		UserID    int64
+		InvitedBy int64
+	}
+	mock.lockAcceptEventInvite.RLock()
+	calls = mock.calls.AcceptEventInvite
+	mock.lockAcceptEventInvite.RUnlock()
+	return calls
+}
+
+// AddBotToConversation calls AddBotToConversationFunc.
+func (mock *StoreMock) AddBotToConversation(ctx context.Context, conversationID int64, botID int64, actorID int64) (*Bot, error) {
+	if mock.AddBotToConversationFunc == nil {
+		panic("StoreMock.AddBotToConversationFunc: method is nil but Store.AddBotToConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		BotID          int64
+		ActorID        int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		BotID:          botID,
+		ActorID:        actorID,
+	}
+	mock.lockAddBotToConversation.Lock()
+	mock.calls.AddBotToConversation = append(mock.calls.AddBotToConversation, callInfo)
+	mock.lockAddBotToConversation.Unlock()
+	return mock.AddBotToConversationFunc(ctx, conversationID, botID, actorID)
+}
+
+// AddBotToConversationCalls gets all the calls that were made to AddBotToConversation.
+// Check the length with:
+//
+//	len(mockedStore.AddBotToConversationCalls())
+func (mock *StoreMock) AddBotToConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	BotID          int64
+	ActorID        int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		BotID          int64
+		ActorID        int64
+	}
+	mock.lockAddBotToConversation.RLock()
+	calls = mock.calls.AddBotToConversation
+	mock.lockAddBotToConversation.RUnlock()
+	return calls
+}
+
+// AddConversationMembers calls AddConversationMembersFunc.
+func (mock *StoreMock) AddConversationMembers(ctx context.Context, conversationID int64, actorID int64, userIDs []int64) ([]int64, error) {
+	if mock.AddConversationMembersFunc == nil {
+		panic("StoreMock.AddConversationMembersFunc: method is nil but Store.AddConversationMembers was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		UserIDs        []int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+		UserIDs:        userIDs,
+	}
+	mock.lockAddConversationMembers.Lock()
+	mock.calls.AddConversationMembers = append(mock.calls.AddConversationMembers, callInfo)
+	mock.lockAddConversationMembers.Unlock()
+	return mock.AddConversationMembersFunc(ctx, conversationID, actorID, userIDs)
+}
+
+// AddConversationMembersCalls gets all the calls that were made to AddConversationMembers.
+// Check the length with:
+//
+//	len(mockedStore.AddConversationMembersCalls())
+func (mock *StoreMock) AddConversationMembersCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+	UserIDs        []int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		UserIDs        []int64
+	}
+	mock.lockAddConversationMembers.RLock()
+	calls = mock.calls.AddConversationMembers
+	mock.lockAddConversationMembers.RUnlock()
+	return calls
+}
+
+// AddFilteredWords calls AddFilteredWordsFunc.
+func (mock *StoreMock) AddFilteredWords(ctx context.Context, words []string, action *string) error {
+	if mock.AddFilteredWordsFunc == nil {
+		panic("StoreMock.AddFilteredWordsFunc: method is nil but Store.AddFilteredWords was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Words  []string
+		Action *string
+	}{
+		Ctx:    ctx,
+		Words:  words,
+		Action: action,
+	}
+	mock.lockAddFilteredWords.Lock()
+	mock.calls.AddFilteredWords = append(mock.calls.AddFilteredWords, callInfo)
+	mock.lockAddFilteredWords.Unlock()
+	return mock.AddFilteredWordsFunc(ctx, words, action)
+}
+
+// AddFilteredWordsCalls gets all the calls that were made to AddFilteredWords.
+// Check the length with:
+//
+//	len(mockedStore.AddFilteredWordsCalls())
+func (mock *StoreMock) AddFilteredWordsCalls() []struct {
+	Ctx    context.Context
+	Words  []string
+	Action *string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Words  []string
+		Action *string
+	}
+	mock.lockAddFilteredWords.RLock()
+	calls = mock.calls.AddFilteredWords
+	mock.lockAddFilteredWords.RUnlock()
+	return calls
+}
+
+// ApproveJoinRequest calls ApproveJoinRequestFunc.
+func (mock *StoreMock) ApproveJoinRequest(ctx context.Context, eventID int64, userID int64, approverID int64) (*ConversationJoinRequest, error) {
+	if mock.ApproveJoinRequestFunc == nil {
+		panic("StoreMock.ApproveJoinRequestFunc: method is nil but Store.ApproveJoinRequest was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		EventID    int64
+		UserID     int64
+		ApproverID int64
+	}{
+		Ctx:        ctx,
+		EventID:    eventID,
+		UserID:     userID,
+		ApproverID: approverID,
+	}
+	mock.lockApproveJoinRequest.Lock()
+	mock.calls.ApproveJoinRequest = append(mock.calls.ApproveJoinRequest, callInfo)
+	mock.lockApproveJoinRequest.Unlock()
+	return mock.ApproveJoinRequestFunc(ctx, eventID, userID, approverID)
+}
+
+// ApproveJoinRequestCalls gets all the calls that were made to ApproveJoinRequest.
+// Check the length with:
+//
+//	len(mockedStore.ApproveJoinRequestCalls())
+func (mock *StoreMock) ApproveJoinRequestCalls() []struct {
+	Ctx        context.Context
+	EventID    int64
+	UserID     int64
+	ApproverID int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		EventID    int64
+		UserID     int64
+		ApproverID int64
+	}
+	mock.lockApproveJoinRequest.RLock()
+	calls = mock.calls.ApproveJoinRequest
+	mock.lockApproveJoinRequest.RUnlock()
+	return calls
+}
+
+// ArchiveConversation calls ArchiveConversationFunc.
+func (mock *StoreMock) ArchiveConversation(ctx context.Context, conversationID int64, userID int64) error {
+	if mock.ArchiveConversationFunc == nil {
+		panic("StoreMock.ArchiveConversationFunc: method is nil but Store.ArchiveConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	mock.lockArchiveConversation.Lock()
+	mock.calls.ArchiveConversation = append(mock.calls.ArchiveConversation, callInfo)
+	mock.lockArchiveConversation.Unlock()
+	return mock.ArchiveConversationFunc(ctx, conversationID, userID)
+}
+
+// ArchiveConversationCalls gets all the calls that were made to ArchiveConversation.
+// Check the length with:
+//
+//	len(mockedStore.ArchiveConversationCalls())
+func (mock *StoreMock) ArchiveConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}
+	mock.lockArchiveConversation.RLock()
+	calls = mock.calls.ArchiveConversation
+	mock.lockArchiveConversation.RUnlock()
+	return calls
+}
+
+// AreConnected calls AreConnectedFunc.
+func (mock *StoreMock) AreConnected(ctx context.Context, userID int64, otherID int64) (bool, error) {
+	if mock.AreConnectedFunc == nil {
+		panic("StoreMock.AreConnectedFunc: method is nil but Store.AreConnected was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  int64
+		OtherID int64
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		OtherID: otherID,
+	}
+	mock.lockAreConnected.Lock()
+	mock.calls.AreConnected = append(mock.calls.AreConnected, callInfo)
+	mock.lockAreConnected.Unlock()
+	return mock.AreConnectedFunc(ctx, userID, otherID)
+}
+
+// AreConnectedCalls gets all the calls that were made to AreConnected.
+// Check the length with:
+//
+//	len(mockedStore.AreConnectedCalls())
+func (mock *StoreMock) AreConnectedCalls() []struct {
+	Ctx     context.Context
+	UserID  int64
+	OtherID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  int64
+		OtherID int64
+	}
+	mock.lockAreConnected.RLock()
+	calls = mock.calls.AreConnected
+	mock.lockAreConnected.RUnlock()
+	return calls
+}
+
+// AuthenticateAPIKey calls AuthenticateAPIKeyFunc.
+func (mock *StoreMock) AuthenticateAPIKey(ctx context.Context, secret string, now time.Time) (*APIKey, error) {
+	if mock.AuthenticateAPIKeyFunc == nil {
+		panic("StoreMock.AuthenticateAPIKeyFunc: method is nil but Store.AuthenticateAPIKey was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Secret string
+		Now    time.Time
+	}{
+		Ctx:    ctx,
+		Secret: secret,
+		Now:    now,
+	}
+	mock.lockAuthenticateAPIKey.Lock()
+	mock.calls.AuthenticateAPIKey = append(mock.calls.AuthenticateAPIKey, callInfo)
+	mock.lockAuthenticateAPIKey.Unlock()
+	return mock.AuthenticateAPIKeyFunc(ctx, secret, now)
+}
+
+// AuthenticateAPIKeyCalls gets all the calls that were made to AuthenticateAPIKey.
+// Check the length with:
+//
+//	len(mockedStore.AuthenticateAPIKeyCalls())
+func (mock *StoreMock) AuthenticateAPIKeyCalls() []struct {
+	Ctx    context.Context
+	Secret string
+	Now    time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Secret string
+		Now    time.Time
+	}
+	mock.lockAuthenticateAPIKey.RLock()
+	calls = mock.calls.AuthenticateAPIKey
+	mock.lockAuthenticateAPIKey.RUnlock()
+	return calls
+}
+
+// AuthenticateBot calls AuthenticateBotFunc.
+func (mock *StoreMock) AuthenticateBot(ctx context.Context, key string) (*Bot, error) {
+	if mock.AuthenticateBotFunc == nil {
+		panic("StoreMock.AuthenticateBotFunc: method is nil but Store.AuthenticateBot was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockAuthenticateBot.Lock()
+	mock.calls.AuthenticateBot = append(mock.calls.AuthenticateBot, callInfo)
+	mock.lockAuthenticateBot.Unlock()
+	return mock.AuthenticateBotFunc(ctx, key)
+}
+
+// AuthenticateBotCalls gets all the calls that were made to AuthenticateBot.
+// Check the length with:
+//
+//	len(mockedStore.AuthenticateBotCalls())
+func (mock *StoreMock) AuthenticateBotCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	mock.lockAuthenticateBot.RLock()
+	calls = mock.calls.AuthenticateBot
+	mock.lockAuthenticateBot.RUnlock()
+	return calls
+}
+
+// AuthenticateUser calls AuthenticateUserFunc.
+func (mock *StoreMock) AuthenticateUser(ctx context.Context, email string, password string) (*User, error) {
+	if mock.AuthenticateUserFunc == nil {
+		panic("StoreMock.AuthenticateUserFunc: method is nil but Store.AuthenticateUser was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Email    string
+		Password string
+	}{
+		Ctx:      ctx,
+		Email:    email,
+		Password: password,
+	}
+	mock.lockAuthenticateUser.Lock()
+	mock.calls.AuthenticateUser = append(mock.calls.AuthenticateUser, callInfo)
+	mock.lockAuthenticateUser.Unlock()
+	return mock.AuthenticateUserFunc(ctx, email, password)
+}
+
+// AuthenticateUserCalls gets all the calls that were made to AuthenticateUser.
+// Check the length with:
+//
+//	len(mockedStore.AuthenticateUserCalls())
+func (mock *StoreMock) AuthenticateUserCalls() []struct {
+	Ctx      context.Context
+	Email    string
+	Password string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Email    string
+		Password string
+	}
+	mock.lockAuthenticateUser.RLock()
+	calls = mock.calls.AuthenticateUser
+	mock.lockAuthenticateUser.RUnlock()
+	return calls
+}
+
+// BookmarkEvent calls BookmarkEventFunc.
+func (mock *StoreMock) BookmarkEvent(ctx context.Context, userID int64, eventID int64) error {
+	if mock.BookmarkEventFunc == nil {
+		panic("StoreMock.BookmarkEventFunc: method is nil but Store.BookmarkEvent was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  int64
+		EventID int64
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		EventID: eventID,
+	}
+	mock.lockBookmarkEvent.Lock()
+	mock.calls.BookmarkEvent = append(mock.calls.BookmarkEvent, callInfo)
+	mock.lockBookmarkEvent.Unlock()
+	return mock.BookmarkEventFunc(ctx, userID, eventID)
+}
+
+// BookmarkEventCalls gets all the calls that were made to BookmarkEvent.
+// Check the length with:
+//
+//	len(mockedStore.BookmarkEventCalls())
+func (mock *StoreMock) BookmarkEventCalls() []struct {
+	Ctx     context.Context
+	UserID  int64
+	EventID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  int64
+		EventID int64
+	}
+	mock.lockBookmarkEvent.RLock()
+	calls = mock.calls.BookmarkEvent
+	mock.lockBookmarkEvent.RUnlock()
+	return calls
+}
+
+// CanViewEvent calls CanViewEventFunc.
+func (mock *StoreMock) CanViewEvent(ctx context.Context, event *Event, viewerID int64) (bool, error) {
+	if mock.CanViewEventFunc == nil {
+		panic("StoreMock.CanViewEventFunc: method is nil but Store.CanViewEvent was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Event    *Event
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		Event:    event,
+		ViewerID: viewerID,
+	}
+	mock.lockCanViewEvent.Lock()
+	mock.calls.CanViewEvent = append(mock.calls.CanViewEvent, callInfo)
+	mock.lockCanViewEvent.Unlock()
+	return mock.CanViewEventFunc(ctx, event, viewerID)
+}
+
+// CanViewEventCalls gets all the calls that were made to CanViewEvent.
+// Check the length with:
+//
+//	len(mockedStore.CanViewEventCalls())
+func (mock *StoreMock) CanViewEventCalls() []struct {
+	Ctx      context.Context
+	Event    *Event
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Event    *Event
+		ViewerID int64
+	}
+	mock.lockCanViewEvent.RLock()
+	calls = mock.calls.CanViewEvent
+	mock.lockCanViewEvent.RUnlock()
+	return calls
+}
+
+// CancelScheduledMessage calls CancelScheduledMessageFunc.
+func (mock *StoreMock) CancelScheduledMessage(ctx context.Context, conversationID int64, senderID int64, scheduledID int64) error {
+	if mock.CancelScheduledMessageFunc == nil {
+		panic("StoreMock.CancelScheduledMessageFunc: method is nil but Store.CancelScheduledMessage was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		SenderID       int64
+		ScheduledID    int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		ScheduledID:    scheduledID,
+	}
+	mock.lockCancelScheduledMessage.Lock()
+	mock.calls.CancelScheduledMessage = append(mock.calls.CancelScheduledMessage, callInfo)
+	mock.lockCancelScheduledMessage.Unlock()
+	return mock.CancelScheduledMessageFunc(ctx, conversationID, senderID, scheduledID)
+}
+
+// CancelScheduledMessageCalls gets all the calls that were made to CancelScheduledMessage.
+// Check the length with:
+//
+//	len(mockedStore.CancelScheduledMessageCalls())
+func (mock *StoreMock) CancelScheduledMessageCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	SenderID       int64
+	ScheduledID    int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		SenderID       int64
+		ScheduledID    int64
+	}
+	mock.lockCancelScheduledMessage.RLock()
+	calls = mock.calls.CancelScheduledMessage
+	mock.lockCancelScheduledMessage.RUnlock()
+	return calls
+}
+
+// CheckInToEvent calls CheckInToEventFunc.
+func (mock *StoreMock) CheckInToEvent(ctx context.Context, eventID int64, userID int64, now time.Time) (time.Time, error) {
+	if mock.CheckInToEventFunc == nil {
+		panic("StoreMock.CheckInToEventFunc: method is nil but Store.CheckInToEvent was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		Now     time.Time
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		UserID:  userID,
+		Now:     now,
+	}
+	mock.lockCheckInToEvent.Lock()
+	mock.calls.CheckInToEvent = append(mock.calls.CheckInToEvent, callInfo)
+	mock.lockCheckInToEvent.Unlock()
+	return mock.CheckInToEventFunc(ctx, eventID, userID, now)
+}
+
+// CheckInToEventCalls gets all the calls that were made to CheckInToEvent.
+// Check the length with:
+//
+//	len(mockedStore.CheckInToEventCalls())
+func (mock *StoreMock) CheckInToEventCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	UserID  int64
+	Now     time.Time
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		Now     time.Time
+	}
+	mock.lockCheckInToEvent.RLock()
+	calls = mock.calls.CheckInToEvent
+	mock.lockCheckInToEvent.RUnlock()
+	return calls
+}
+
+// ClearAvailability calls ClearAvailabilityFunc.
+func (mock *StoreMock) ClearAvailability(ctx context.Context, userID int64) error {
+	if mock.ClearAvailabilityFunc == nil {
+		panic("StoreMock.ClearAvailabilityFunc: method is nil but Store.ClearAvailability was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockClearAvailability.Lock()
+	mock.calls.ClearAvailability = append(mock.calls.ClearAvailability, callInfo)
+	mock.lockClearAvailability.Unlock()
+	return mock.ClearAvailabilityFunc(ctx, userID)
+}
+
+// ClearAvailabilityCalls gets all the calls that were made to ClearAvailability.
+// Check the length with:
+//
+//	len(mockedStore.ClearAvailabilityCalls())
+func (mock *StoreMock) ClearAvailabilityCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockClearAvailability.RLock()
+	calls = mock.calls.ClearAvailability
+	mock.lockClearAvailability.RUnlock()
+	return calls
+}
+
+// ClosePoll calls ClosePollFunc.
+func (mock *StoreMock) ClosePoll(ctx context.Context, conversationID int64, pollID int64, userID int64) (*Poll, error) {
+	if mock.ClosePollFunc == nil {
+		panic("StoreMock.ClosePollFunc: method is nil but Store.ClosePoll was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		PollID         int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		PollID:         pollID,
+		UserID:         userID,
+	}
+	mock.lockClosePoll.Lock()
+	mock.calls.ClosePoll = append(mock.calls.ClosePoll, callInfo)
+	mock.lockClosePoll.Unlock()
+	return mock.ClosePollFunc(ctx, conversationID, pollID, userID)
+}
+
+// ClosePollCalls gets all the calls that were made to ClosePoll.
+// Check the length with:
+//
+//	len(mockedStore.ClosePollCalls())
+func (mock *StoreMock) ClosePollCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	PollID         int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		PollID         int64
+		UserID         int64
+	}
+	mock.lockClosePoll.RLock()
+	calls = mock.calls.ClosePoll
+	mock.lockClosePoll.RUnlock()
+	return calls
+}
+
+// ConfirmTimeSlot calls ConfirmTimeSlotFunc.
+func (mock *StoreMock) ConfirmTimeSlot(ctx context.Context, eventID int64, hostID int64, slotID int64, now time.Time) (*TimePoll, error) {
+	if mock.ConfirmTimeSlotFunc == nil {
+		panic("StoreMock.ConfirmTimeSlotFunc: method is nil but Store.ConfirmTimeSlot was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		SlotID  int64
+		Now     time.Time
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+		SlotID:  slotID,
+		Now:     now,
+	}
+	mock.lockConfirmTimeSlot.Lock()
+	mock.calls.ConfirmTimeSlot = append(mock.calls.ConfirmTimeSlot, callInfo)
+	mock.lockConfirmTimeSlot.Unlock()
+	return mock.ConfirmTimeSlotFunc(ctx, eventID, hostID, slotID, now)
+}
+
+// ConfirmTimeSlotCalls gets all the calls that were made to ConfirmTimeSlot.
+// Check the length with:
+//
+//	len(mockedStore.ConfirmTimeSlotCalls())
+func (mock *StoreMock) ConfirmTimeSlotCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+	SlotID  int64
+	Now     time.Time
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		SlotID  int64
+		Now     time.Time
+	}
+	mock.lockConfirmTimeSlot.RLock()
+	calls = mock.calls.ConfirmTimeSlot
+	mock.lockConfirmTimeSlot.RUnlock()
+	return calls
+}
+
+// ConsumeMagicLink calls ConsumeMagicLinkFunc.
+func (mock *StoreMock) ConsumeMagicLink(ctx context.Context, claims magicLinkClaims, now time.Time) (*User, error) {
+	if mock.ConsumeMagicLinkFunc == nil {
+		panic("StoreMock.ConsumeMagicLinkFunc: method is nil but Store.ConsumeMagicLink was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Claims magicLinkClaims
+		Now    time.Time
+	}{
+		Ctx:    ctx,
+		Claims: claims,
+		Now:    now,
+	}
+	mock.lockConsumeMagicLink.Lock()
+	mock.calls.ConsumeMagicLink = append(mock.calls.ConsumeMagicLink, callInfo)
+	mock.lockConsumeMagicLink.Unlock()
+	return mock.ConsumeMagicLinkFunc(ctx, claims, now)
+}
+
+// ConsumeMagicLinkCalls gets all the calls that were made to ConsumeMagicLink.
+// Check the length with:
+//
+//	len(mockedStore.ConsumeMagicLinkCalls())
+func (mock *StoreMock) ConsumeMagicLinkCalls() []struct {
+	Ctx    context.Context
+	Claims magicLinkClaims
+	Now    time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Claims magicLinkClaims
+		Now    time.Time
+	}
+	mock.lockConsumeMagicLink.RLock()
+	calls = mock.calls.ConsumeMagicLink
+	mock.lockConsumeMagicLink.RUnlock()
+	return calls
+}
+
+// CountRecentIdenticalMessages calls CountRecentIdenticalMessagesFunc.
+func (mock *StoreMock) CountRecentIdenticalMessages(ctx context.Context, senderID int64, body string, since time.Time) (int, error) {
+	if mock.CountRecentIdenticalMessagesFunc == nil {
+		panic("StoreMock.CountRecentIdenticalMessagesFunc: method is nil but Store.CountRecentIdenticalMessages was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SenderID int64
+		Body     string
+		Since    time.Time
+	}{
+		Ctx:      ctx,
+		SenderID: senderID,
+		Body:     body,
+		Since:    since,
+	}
+	mock.lockCountRecentIdenticalMessages.Lock()
+	mock.calls.CountRecentIdenticalMessages = append(mock.calls.CountRecentIdenticalMessages, callInfo)
+	mock.lockCountRecentIdenticalMessages.Unlock()
+	return mock.CountRecentIdenticalMessagesFunc(ctx, senderID, body, since)
+}
+
+// CountRecentIdenticalMessagesCalls gets all the calls that were made to CountRecentIdenticalMessages.
+// Check the length with:
+//
+//	len(mockedStore.CountRecentIdenticalMessagesCalls())
+func (mock *StoreMock) CountRecentIdenticalMessagesCalls() []struct {
+	Ctx      context.Context
+	SenderID int64
+	Body     string
+	Since    time.Time
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SenderID int64
+		Body     string
+		Since    time.Time
+	}
+	mock.lockCountRecentIdenticalMessages.RLock()
+	calls = mock.calls.CountRecentIdenticalMessages
+	mock.lockCountRecentIdenticalMessages.RUnlock()
+	return calls
+}
+
+// CountUnread calls CountUnreadFunc.
+func (mock *StoreMock) CountUnread(ctx context.Context, userID int64) (int, int, error) {
+	if mock.CountUnreadFunc == nil {
+		panic("StoreMock.CountUnreadFunc: method is nil but Store.CountUnread was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockCountUnread.Lock()
+	mock.calls.CountUnread = append(mock.calls.CountUnread, callInfo)
+	mock.lockCountUnread.Unlock()
+	return mock.CountUnreadFunc(ctx, userID)
+}
+
+// CountUnreadCalls gets all the calls that were made to CountUnread.
+// Check the length with:
+//
+//	len(mockedStore.CountUnreadCalls())
+func (mock *StoreMock) CountUnreadCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockCountUnread.RLock()
+	calls = mock.calls.CountUnread
+	mock.lockCountUnread.RUnlock()
+	return calls
+}
+
+// Create calls CreateFunc.
+func (mock *StoreMock) Create(ctx context.Context, params CreateEventParams) (int64, error) {
+	if mock.CreateFunc == nil {
+		panic("StoreMock.CreateFunc: method is nil but Store.Create was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params CreateEventParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, params)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedStore.CreateCalls())
+func (mock *StoreMock) CreateCalls() []struct {
+	Ctx    context.Context
+	Params CreateEventParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params CreateEventParams
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// CreateBot calls CreateBotFunc.
+func (mock *StoreMock) CreateBot(ctx context.Context, ownerID int64, name string) (*Bot, string, error) {
+	if mock.CreateBotFunc == nil {
+		panic("StoreMock.CreateBotFunc: method is nil but Store.CreateBot was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		OwnerID int64
+		Name    string
+	}{
+		Ctx:     ctx,
+		OwnerID: ownerID,
+		Name:    name,
+	}
+	mock.lockCreateBot.Lock()
+	mock.calls.CreateBot = append(mock.calls.CreateBot, callInfo)
+	mock.lockCreateBot.Unlock()
+	return mock.CreateBotFunc(ctx, ownerID, name)
+}
+
+// CreateBotCalls gets all the calls that were made to CreateBot.
+// Check the length with:
+//
+//	len(mockedStore.CreateBotCalls())
+func (mock *StoreMock) CreateBotCalls() []struct {
+	Ctx     context.Context
+	OwnerID int64
+	Name    string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		OwnerID int64
+		Name    string
+	}
+	mock.lockCreateBot.RLock()
+	calls = mock.calls.CreateBot
+	mock.lockCreateBot.RUnlock()
+	return calls
+}
+
+// CreateConversation calls CreateConversationFunc.
+func (mock *StoreMock) CreateConversation(ctx context.Context, title *string, createdBy int64, memberIDs []int64, eventID *int64) (*Conversation, error) {
+	if mock.CreateConversationFunc == nil {
+		panic("StoreMock.CreateConversationFunc: method is nil but Store.CreateConversation was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Title     *string
+		CreatedBy int64
+		MemberIDs []int64
+		EventID   *int64
+	}{
+		Ctx:       ctx,
+		Title:     title,
+		CreatedBy: createdBy,
+		MemberIDs: memberIDs,
+		EventID:   eventID,
+	}
+	mock.lockCreateConversation.Lock()
+	mock.calls.CreateConversation = append(mock.calls.CreateConversation, callInfo)
+	mock.lockCreateConversation.Unlock()
+	return mock.CreateConversationFunc(ctx, title, createdBy, memberIDs, eventID)
+}
+
+// CreateConversationCalls gets all the calls that were made to CreateConversation.
+// Check the length with:
+//
+//	len(mockedStore.CreateConversationCalls())
+func (mock *StoreMock) CreateConversationCalls() []struct {
+	Ctx       context.Context
+	Title     *string
+	CreatedBy int64
+	MemberIDs []int64
+	EventID   *int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Title     *string
+		CreatedBy int64
+		MemberIDs []int64
+		EventID   *int64
+	}
+	mock.lockCreateConversation.RLock()
+	calls = mock.calls.CreateConversation
+	mock.lockCreateConversation.RUnlock()
+	return calls
+}
+
+// CreateEventFromTemplate calls CreateEventFromTemplateFunc.
+func (mock *StoreMock) CreateEventFromTemplate(ctx context.Context, templateID int64, userID int64, params DuplicateEventParams) (int64, error) {
+	if mock.CreateEventFromTemplateFunc == nil {
+		panic("StoreMock.CreateEventFromTemplateFunc: method is nil but Store.CreateEventFromTemplate was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		TemplateID int64
+		UserID     int64
+		Params     DuplicateEventParams
+	}{
+		Ctx:        ctx,
+		TemplateID: templateID,
+		UserID:     userID,
+		Params:     params,
+	}
+	mock.lockCreateEventFromTemplate.Lock()
+	mock.calls.CreateEventFromTemplate = append(mock.calls.CreateEventFromTemplate, callInfo)
+	mock.lockCreateEventFromTemplate.Unlock()
+	return mock.CreateEventFromTemplateFunc(ctx, templateID, userID, params)
+}
+
+// CreateEventFromTemplateCalls gets all the calls that were made to CreateEventFromTemplate.
+// Check the length with:
+//
+//	len(mockedStore.CreateEventFromTemplateCalls())
+func (mock *StoreMock) CreateEventFromTemplateCalls() []struct {
+	Ctx        context.Context
+	TemplateID int64
+	UserID     int64
+	Params     DuplicateEventParams
+} {
+	var calls []struct {
+		Ctx        context.Context
+		TemplateID int64
+		UserID     int64
+		Params     DuplicateEventParams
+	}
+	mock.lockCreateEventFromTemplate.RLock()
+	calls = mock.calls.CreateEventFromTemplate
+	mock.lockCreateEventFromTemplate.RUnlock()
+	return calls
+}
+
+// CreateEventReview calls CreateEventReviewFunc.
+func (mock *StoreMock) CreateEventReview(ctx context.Context, eventID int64, reviewerID int64, params CreateEventReviewParams, now time.Time) (*EventReview, error) {
+	if mock.CreateEventReviewFunc == nil {
+		panic("StoreMock.CreateEventReviewFunc: method is nil but Store.CreateEventReview was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		EventID    int64
+		ReviewerID int64
+		Params     CreateEventReviewParams
+		Now        time.Time
+	}{
+		Ctx:        ctx,
+		EventID:    eventID,
+		ReviewerID: reviewerID,
+		Params:     params,
+		Now:        now,
+	}
+	mock.lockCreateEventReview.Lock()
+	mock.calls.CreateEventReview = append(mock.calls.CreateEventReview, callInfo)
+	mock.lockCreateEventReview.Unlock()
+	return mock.CreateEventReviewFunc(ctx, eventID, reviewerID, params, now)
+}
+
+// CreateEventReviewCalls gets all the calls that were made to CreateEventReview.
+// Check the length with:
+//
+//	len(mockedStore.CreateEventReviewCalls())
+func (mock *StoreMock) CreateEventReviewCalls() []struct {
+	Ctx        context.Context
+	EventID    int64
+	ReviewerID int64
+	Params     CreateEventReviewParams
+	Now        time.Time
+} {
+	var calls []struct {
+		Ctx        context.Context
+		EventID    int64
+		ReviewerID int64
+		Params     CreateEventReviewParams
+		Now        time.Time
+	}
+	mock.lockCreateEventReview.RLock()
+	calls = mock.calls.CreateEventReview
+	mock.lockCreateEventReview.RUnlock()
+	return calls
+}
+
+// CreateEventTemplate calls CreateEventTemplateFunc.
+func (mock *StoreMock) CreateEventTemplate(ctx context.Context, hostID int64, params CreateEventTemplateParams) (*EventTemplate, error) {
+	if mock.CreateEventTemplateFunc == nil {
+		panic("StoreMock.CreateEventTemplateFunc: method is nil but Store.CreateEventTemplate was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		HostID int64
+		Params CreateEventTemplateParams
+	}{
+		Ctx:    ctx,
+		HostID: hostID,
+		Params: params,
+	}
+	mock.lockCreateEventTemplate.Lock()
+	mock.calls.CreateEventTemplate = append(mock.calls.CreateEventTemplate, callInfo)
+	mock.lockCreateEventTemplate.Unlock()
+	return mock.CreateEventTemplateFunc(ctx, hostID, params)
+}
+
+// CreateEventTemplateCalls gets all the calls that were made to CreateEventTemplate.
+// Check the length with:
+//
+//	len(mockedStore.CreateEventTemplateCalls())
+func (mock *StoreMock) CreateEventTemplateCalls() []struct {
+	Ctx    context.Context
+	HostID int64
+	Params CreateEventTemplateParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		HostID int64
+		Params CreateEventTemplateParams
+	}
+	mock.lockCreateEventTemplate.RLock()
+	calls = mock.calls.CreateEventTemplate
+	mock.lockCreateEventTemplate.RUnlock()
+	return calls
+}
+
+// CreateJoinRequest calls CreateJoinRequestFunc.
+func (mock *StoreMock) CreateJoinRequest(ctx context.Context, eventID int64, userID int64, note string) (*ConversationJoinRequest, error) {
+	if mock.CreateJoinRequestFunc == nil {
+		panic("StoreMock.CreateJoinRequestFunc: method is nil but Store.CreateJoinRequest was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		Note    string
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		UserID:  userID,
+		Note:    note,
+	}
+	mock.lockCreateJoinRequest.Lock()
+	mock.calls.CreateJoinRequest = append(mock.calls.CreateJoinRequest, callInfo)
+	mock.lockCreateJoinRequest.Unlock()
+	return mock.CreateJoinRequestFunc(ctx, eventID, userID, note)
+}
+
+// CreateJoinRequestCalls gets all the calls that were made to CreateJoinRequest.
+// Check the length with:
+//
+//	len(mockedStore.CreateJoinRequestCalls())
+func (mock *StoreMock) CreateJoinRequestCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	UserID  int64
+	Note    string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		Note    string
+	}
+	mock.lockCreateJoinRequest.RLock()
+	calls = mock.calls.CreateJoinRequest
+	mock.lockCreateJoinRequest.RUnlock()
+	return calls
+}
+
+// CreateMagicLink calls CreateMagicLinkFunc.
+func (mock *StoreMock) CreateMagicLink(ctx context.Context, email string, now time.Time) (*magicLinkClaims, *User, error) {
+	if mock.CreateMagicLinkFunc == nil {
+		panic("StoreMock.CreateMagicLinkFunc: method is nil but Store.CreateMagicLink was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Email string
+		Now   time.Time
+	}{
+		Ctx:   ctx,
+		Email: email,
+		Now:   now,
+	}
+	mock.lockCreateMagicLink.Lock()
+	mock.calls.CreateMagicLink = append(mock.calls.CreateMagicLink, callInfo)
+	mock.lockCreateMagicLink.Unlock()
+	return mock.CreateMagicLinkFunc(ctx, email, now)
+}
+
+// CreateMagicLinkCalls gets all the calls that were made to CreateMagicLink.
+// Check the length with:
+//
+//	len(mockedStore.CreateMagicLinkCalls())
+func (mock *StoreMock) CreateMagicLinkCalls() []struct {
+	Ctx   context.Context
+	Email string
+	Now   time.Time
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Email string
+		Now   time.Time
+	}
+	mock.lockCreateMagicLink.RLock()
+	calls = mock.calls.CreateMagicLink
+	mock.lockCreateMagicLink.RUnlock()
+	return calls
+}
+
+// CreateMessage calls CreateMessageFunc.
+func (mock *StoreMock) CreateMessage(ctx context.Context, params CreateMessageParams) (*Message, error) {
+	if mock.CreateMessageFunc == nil {
+		panic("StoreMock.CreateMessageFunc: method is nil but Store.CreateMessage was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params CreateMessageParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockCreateMessage.Lock()
+	mock.calls.CreateMessage = append(mock.calls.CreateMessage, callInfo)
+	mock.lockCreateMessage.Unlock()
+	return mock.CreateMessageFunc(ctx, params)
+}
+
+// CreateMessageCalls gets all the calls that were made to CreateMessage.
+// Check the length with:
+//
+//	len(mockedStore.CreateMessageCalls())
+func (mock *StoreMock) CreateMessageCalls() []struct {
+	Ctx    context.Context
+	Params CreateMessageParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params CreateMessageParams
+	}
+	mock.lockCreateMessage.RLock()
+	calls = mock.calls.CreateMessage
+	mock.lockCreateMessage.RUnlock()
+	return calls
+}
+
+// CreatePasswordResetToken calls CreatePasswordResetTokenFunc.
+func (mock *StoreMock) CreatePasswordResetToken(ctx context.Context, email string, now time.Time) (string, *User, error) {
+	if mock.CreatePasswordResetTokenFunc == nil {
+		panic("StoreMock.CreatePasswordResetTokenFunc: method is nil but Store.CreatePasswordResetToken was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Email string
+		Now   time.Time
+	}{
+		Ctx:   ctx,
+		Email: email,
+		Now:   now,
+	}
+	mock.lockCreatePasswordResetToken.Lock()
+	mock.calls.CreatePasswordResetToken = append(mock.calls.CreatePasswordResetToken, callInfo)
+	mock.lockCreatePasswordResetToken.Unlock()
+	return mock.CreatePasswordResetTokenFunc(ctx, email, now)
+}
+
+// CreatePasswordResetTokenCalls gets all the calls that were made to CreatePasswordResetToken.
+// Check the length with:
+//
+//	len(mockedStore.CreatePasswordResetTokenCalls())
+func (mock *StoreMock) CreatePasswordResetTokenCalls() []struct {
+	Ctx   context.Context
+	Email string
+	Now   time.Time
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Email string
+		Now   time.Time
+	}
+	mock.lockCreatePasswordResetToken.RLock()
+	calls = mock.calls.CreatePasswordResetToken
+	mock.lockCreatePasswordResetToken.RUnlock()
+	return calls
+}
+
+// CreatePhoneOTP calls CreatePhoneOTPFunc.
+func (mock *StoreMock) CreatePhoneOTP(ctx context.Context, phone string, now time.Time) (string, error) {
+	if mock.CreatePhoneOTPFunc == nil {
+		panic("StoreMock.CreatePhoneOTPFunc: method is nil but Store.CreatePhoneOTP was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Phone string
+		Now   time.Time
+	}{
+		Ctx:   ctx,
+		Phone: phone,
+		Now:   now,
+	}
+	mock.lockCreatePhoneOTP.Lock()
+	mock.calls.CreatePhoneOTP = append(mock.calls.CreatePhoneOTP, callInfo)
+	mock.lockCreatePhoneOTP.Unlock()
+	return mock.CreatePhoneOTPFunc(ctx, phone, now)
+}
+
+// CreatePhoneOTPCalls gets all the calls that were made to CreatePhoneOTP.
+// Check the length with:
+//
+//	len(mockedStore.CreatePhoneOTPCalls())
+func (mock *StoreMock) CreatePhoneOTPCalls() []struct {
+	Ctx   context.Context
+	Phone string
+	Now   time.Time
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Phone string
+		Now   time.Time
+	}
+	mock.lockCreatePhoneOTP.RLock()
+	calls = mock.calls.CreatePhoneOTP
+	mock.lockCreatePhoneOTP.RUnlock()
+	return calls
+}
+
+// CreatePoll calls CreatePollFunc.
+func (mock *StoreMock) CreatePoll(ctx context.Context, conversationID int64, userID int64, params CreatePollParams) (*Message, error) {
+	if mock.CreatePollFunc == nil {
+		panic("StoreMock.CreatePollFunc: method is nil but Store.CreatePoll was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		Params         CreatePollParams
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+		Params:         params,
+	}
+	mock.lockCreatePoll.Lock()
+	mock.calls.CreatePoll = append(mock.calls.CreatePoll, callInfo)
+	mock.lockCreatePoll.Unlock()
+	return mock.CreatePollFunc(ctx, conversationID, userID, params)
+}
+
+// CreatePollCalls gets all the calls that were made to CreatePoll.
+// Check the length with:
+//
+//	len(mockedStore.CreatePollCalls())
+func (mock *StoreMock) CreatePollCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+	Params         CreatePollParams
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		Params         CreatePollParams
+	}
+	mock.lockCreatePoll.RLock()
+	calls = mock.calls.CreatePoll
+	mock.lockCreatePoll.RUnlock()
+	return calls
+}
+
+// CreateSession calls CreateSessionFunc.
+func (mock *StoreMock) CreateSession(ctx context.Context, userID int64, deviceName string, ip string, location string, expiresAt time.Time) (int64, error) {
+	if mock.CreateSessionFunc == nil {
+		panic("StoreMock.CreateSessionFunc: method is nil but Store.CreateSession was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		UserID     int64
+		DeviceName string
+		IP         string
+		Location   string
+		ExpiresAt  time.Time
+	}{
+		Ctx:        ctx,
+		UserID:     userID,
+		DeviceName: deviceName,
+		IP:         ip,
+		Location:   location,
+		ExpiresAt:  expiresAt,
+	}
+	mock.lockCreateSession.Lock()
+	mock.calls.CreateSession = append(mock.calls.CreateSession, callInfo)
+	mock.lockCreateSession.Unlock()
+	return mock.CreateSessionFunc(ctx, userID, deviceName, ip, location, expiresAt)
+}
+
+// CreateSessionCalls gets all the calls that were made to CreateSession.
+// Check the length with:
+//
+//	len(mockedStore.CreateSessionCalls())
+func (mock *StoreMock) CreateSessionCalls() []struct {
+	Ctx        context.Context
+	UserID     int64
+	DeviceName string
+	IP         string
+	Location   string
+	ExpiresAt  time.Time
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     int64
+		DeviceName string
+		IP         string
+		Location   string
+		ExpiresAt  time.Time
+	}
+	mock.lockCreateSession.RLock()
+	calls = mock.calls.CreateSession
+	mock.lockCreateSession.RUnlock()
+	return calls
+}
+
+// CreateSystemMessage calls CreateSystemMessageFunc.
+func (mock *StoreMock) CreateSystemMessage(ctx context.Context, conversationID int64, actorID int64, body string) (*Message, error) {
+	if mock.CreateSystemMessageFunc == nil {
+		panic("StoreMock.CreateSystemMessageFunc: method is nil but Store.CreateSystemMessage was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		Body           string
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+		Body:           body,
+	}
+	mock.lockCreateSystemMessage.Lock()
+	mock.calls.CreateSystemMessage = append(mock.calls.CreateSystemMessage, callInfo)
+	mock.lockCreateSystemMessage.Unlock()
+	return mock.CreateSystemMessageFunc(ctx, conversationID, actorID, body)
+}
+
+// CreateSystemMessageCalls gets all the calls that were made to CreateSystemMessage.
+// Check the length with:
+//
+//	len(mockedStore.CreateSystemMessageCalls())
+func (mock *StoreMock) CreateSystemMessageCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+	Body           string
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		Body           string
+	}
+	mock.lockCreateSystemMessage.RLock()
+	calls = mock.calls.CreateSystemMessage
+	mock.lockCreateSystemMessage.RUnlock()
+	return calls
+}
+
+// CreateTimePoll calls CreateTimePollFunc.
+func (mock *StoreMock) CreateTimePoll(ctx context.Context, eventID int64, hostID int64, starts []time.Time) (*TimePoll, error) {
+	if mock.CreateTimePollFunc == nil {
+		panic("StoreMock.CreateTimePollFunc: method is nil but Store.CreateTimePoll was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Starts  []time.Time
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+		Starts:  starts,
+	}
+	mock.lockCreateTimePoll.Lock()
+	mock.calls.CreateTimePoll = append(mock.calls.CreateTimePoll, callInfo)
+	mock.lockCreateTimePoll.Unlock()
+	return mock.CreateTimePollFunc(ctx, eventID, hostID, starts)
+}
+
+// CreateTimePollCalls gets all the calls that were made to CreateTimePoll.
+// Check the length with:
+//
+//	len(mockedStore.CreateTimePollCalls())
+func (mock *StoreMock) CreateTimePollCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+	Starts  []time.Time
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Starts  []time.Time
+	}
+	mock.lockCreateTimePoll.RLock()
+	calls = mock.calls.CreateTimePoll
+	mock.lockCreateTimePoll.RUnlock()
+	return calls
+}
+
+// CreateWebhook calls CreateWebhookFunc.
+func (mock *StoreMock) CreateWebhook(ctx context.Context, ownerID int64, params CreateWebhookParams) (*Webhook, string, error) {
+	if mock.CreateWebhookFunc == nil {
+		panic("StoreMock.CreateWebhookFunc: method is nil but Store.CreateWebhook was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		OwnerID int64
+		Params  CreateWebhookParams
+	}{
+		Ctx:     ctx,
+		OwnerID: ownerID,
+		Params:  params,
+	}
+	mock.lockCreateWebhook.Lock()
+	mock.calls.CreateWebhook = append(mock.calls.CreateWebhook, callInfo)
+	mock.lockCreateWebhook.Unlock()
+	return mock.CreateWebhookFunc(ctx, ownerID, params)
+}
+
+// CreateWebhookCalls gets all the calls that were made to CreateWebhook.
+// Check the length with:
+//
+//	len(mockedStore.CreateWebhookCalls())
+func (mock *StoreMock) CreateWebhookCalls() []struct {
+	Ctx     context.Context
+	OwnerID int64
+	Params  CreateWebhookParams
+} {
+	var calls []struct {
+		Ctx     context.Context
+		OwnerID int64
+		Params  CreateWebhookParams
+	}
+	mock.lockCreateWebhook.RLock()
+	calls = mock.calls.CreateWebhook
+	mock.lockCreateWebhook.RUnlock()
+	return calls
+}
+
+// DecideJoinRequests calls DecideJoinRequestsFunc.
+func (mock *StoreMock) DecideJoinRequests(ctx context.Context, eventID int64, approverID int64, decisions []JoinDecision) ([]JoinDecisionResult, int64, error) {
+	if mock.DecideJoinRequestsFunc == nil {
+		panic("StoreMock.DecideJoinRequestsFunc: method is nil but Store.DecideJoinRequests was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		EventID    int64
+		ApproverID int64
+		Decisions  []JoinDecision
+	}{
+		Ctx:        ctx,
+		EventID:    eventID,
+		ApproverID: approverID,
+		Decisions:  decisions,
+	}
+	mock.lockDecideJoinRequests.Lock()
+	mock.calls.DecideJoinRequests = append(mock.calls.DecideJoinRequests, callInfo)
+	mock.lockDecideJoinRequests.Unlock()
+	return mock.DecideJoinRequestsFunc(ctx, eventID, approverID, decisions)
+}
+
+// DecideJoinRequestsCalls gets all the calls that were made to DecideJoinRequests.
+// Check the length with:
+//
+//	len(mockedStore.DecideJoinRequestsCalls())
+func (mock *StoreMock) DecideJoinRequestsCalls() []struct {
+	Ctx        context.Context
+	EventID    int64
+	ApproverID int64
+	Decisions  []JoinDecision
+} {
+	var calls []struct {
+		Ctx        context.Context
+		EventID    int64
+		ApproverID int64
+		Decisions  []JoinDecision
+	}
+	mock.lockDecideJoinRequests.RLock()
+	calls = mock.calls.DecideJoinRequests
+	mock.lockDecideJoinRequests.RUnlock()
+	return calls
+}
+
+// DecideVerificationRequest calls DecideVerificationRequestFunc.
+func (mock *StoreMock) DecideVerificationRequest(ctx context.Context, requestID int64, approve bool, reviewer string, reason string) (*VerificationRequest, error) {
+	if mock.DecideVerificationRequestFunc == nil {
+		panic("StoreMock.DecideVerificationRequestFunc: method is nil but Store.DecideVerificationRequest was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		RequestID int64
+		Approve   bool
+		Reviewer  string
+		Reason    string
+	}{
+		Ctx:       ctx,
+		RequestID: requestID,
+		Approve:   approve,
+		Reviewer:  reviewer,
+		Reason:    reason,
+	}
+	mock.lockDecideVerificationRequest.Lock()
+	mock.calls.DecideVerificationRequest = append(mock.calls.DecideVerificationRequest, callInfo)
+	mock.lockDecideVerificationRequest.Unlock()
+	return mock.DecideVerificationRequestFunc(ctx, requestID, approve, reviewer, reason)
+}
+
+// DecideVerificationRequestCalls gets all the calls that were made to DecideVerificationRequest.
+// Check the length with:
+//
+//	len(mockedStore.DecideVerificationRequestCalls())
+func (mock *StoreMock) DecideVerificationRequestCalls() []struct {
+	Ctx       context.Context
+	RequestID int64
+	Approve   bool
+	Reviewer  string
+	Reason    string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		RequestID int64
+		Approve   bool
+		Reviewer  string
+		Reason    string
+	}
+	mock.lockDecideVerificationRequest.RLock()
+	calls = mock.calls.DecideVerificationRequest
+	mock.lockDecideVerificationRequest.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *StoreMock) Delete(ctx context.Context, id int64, userID int64) error {
+	if mock.DeleteFunc == nil {
+		panic("StoreMock.DeleteFunc: method is nil but Store.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     int64
+		UserID int64
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		UserID: userID,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, id, userID)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedStore.DeleteCalls())
+func (mock *StoreMock) DeleteCalls() []struct {
+	Ctx    context.Context
+	ID     int64
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     int64
+		UserID int64
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// DeleteBot calls DeleteBotFunc.
+func (mock *StoreMock) DeleteBot(ctx context.Context, ownerID int64, botID int64) error {
+	if mock.DeleteBotFunc == nil {
+		panic("StoreMock.DeleteBotFunc: method is nil but Store.DeleteBot was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		OwnerID int64
+		BotID   int64
+	}{
+		Ctx:     ctx,
+		OwnerID: ownerID,
+		BotID:   botID,
+	}
+	mock.lockDeleteBot.Lock()
+	mock.calls.DeleteBot = append(mock.calls.DeleteBot, callInfo)
+	mock.lockDeleteBot.Unlock()
+	return mock.DeleteBotFunc(ctx, ownerID, botID)
+}
+
+// DeleteBotCalls gets all the calls that were made to DeleteBot.
+// Check the length with:
+//
+//	len(mockedStore.DeleteBotCalls())
+func (mock *StoreMock) DeleteBotCalls() []struct {
+	Ctx     context.Context
+	OwnerID int64
+	BotID   int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		OwnerID int64
+		BotID   int64
+	}
+	mock.lockDeleteBot.RLock()
+	calls = mock.calls.DeleteBot
+	mock.lockDeleteBot.RUnlock()
+	return calls
+}
+
+// DeleteEventTemplate calls DeleteEventTemplateFunc.
+func (mock *StoreMock) DeleteEventTemplate(ctx context.Context, templateID int64, userID int64) error {
+	if mock.DeleteEventTemplateFunc == nil {
+		panic("StoreMock.DeleteEventTemplateFunc: method is nil but Store.DeleteEventTemplate was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		TemplateID int64
+		UserID     int64
+	}{
+		Ctx:        ctx,
+		TemplateID: templateID,
+		UserID:     userID,
+	}
+	mock.lockDeleteEventTemplate.Lock()
+	mock.calls.DeleteEventTemplate = append(mock.calls.DeleteEventTemplate, callInfo)
+	mock.lockDeleteEventTemplate.Unlock()
+	return mock.DeleteEventTemplateFunc(ctx, templateID, userID)
+}
+
+// DeleteEventTemplateCalls gets all the calls that were made to DeleteEventTemplate.
+// Check the length with:
+//
+//	len(mockedStore.DeleteEventTemplateCalls())
+func (mock *StoreMock) DeleteEventTemplateCalls() []struct {
+	Ctx        context.Context
+	TemplateID int64
+	UserID     int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		TemplateID int64
+		UserID     int64
+	}
+	mock.lockDeleteEventTemplate.RLock()
+	calls = mock.calls.DeleteEventTemplate
+	mock.lockDeleteEventTemplate.RUnlock()
+	return calls
+}
+
+// DeleteFilteredWord calls DeleteFilteredWordFunc.
+func (mock *StoreMock) DeleteFilteredWord(ctx context.Context, word string) error {
+	if mock.DeleteFilteredWordFunc == nil {
+		panic("StoreMock.DeleteFilteredWordFunc: method is nil but Store.DeleteFilteredWord was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Word string
+	}{
+		Ctx:  ctx,
+		Word: word,
+	}
+	mock.lockDeleteFilteredWord.Lock()
+	mock.calls.DeleteFilteredWord = append(mock.calls.DeleteFilteredWord, callInfo)
+	mock.lockDeleteFilteredWord.Unlock()
+	return mock.DeleteFilteredWordFunc(ctx, word)
+}
+
+// DeleteFilteredWordCalls gets all the calls that were made to DeleteFilteredWord.
+// Check the length with:
+//
+//	len(mockedStore.DeleteFilteredWordCalls())
+func (mock *StoreMock) DeleteFilteredWordCalls() []struct {
+	Ctx  context.Context
+	Word string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Word string
+	}
+	mock.lockDeleteFilteredWord.RLock()
+	calls = mock.calls.DeleteFilteredWord
+	mock.lockDeleteFilteredWord.RUnlock()
+	return calls
+}
+
+// DeleteTimePoll calls DeleteTimePollFunc.
+func (mock *StoreMock) DeleteTimePoll(ctx context.Context, eventID int64, hostID int64) error {
+	if mock.DeleteTimePollFunc == nil {
+		panic("StoreMock.DeleteTimePollFunc: method is nil but Store.DeleteTimePoll was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+	}
+	mock.lockDeleteTimePoll.Lock()
+	mock.calls.DeleteTimePoll = append(mock.calls.DeleteTimePoll, callInfo)
+	mock.lockDeleteTimePoll.Unlock()
+	return mock.DeleteTimePollFunc(ctx, eventID, hostID)
+}
+
+// DeleteTimePollCalls gets all the calls that were made to DeleteTimePoll.
+// Check the length with:
+//
+//	len(mockedStore.DeleteTimePollCalls())
+func (mock *StoreMock) DeleteTimePollCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+	}
+	mock.lockDeleteTimePoll.RLock()
+	calls = mock.calls.DeleteTimePoll
+	mock.lockDeleteTimePoll.RUnlock()
+	return calls
+}
+
+// DeleteUser calls DeleteUserFunc.
+func (mock *StoreMock) DeleteUser(ctx context.Context, userID int64) ([]int64, error) {
+	if mock.DeleteUserFunc == nil {
+		panic("StoreMock.DeleteUserFunc: method is nil but Store.DeleteUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockDeleteUser.Lock()
+	mock.calls.DeleteUser = append(mock.calls.DeleteUser, callInfo)
+	mock.lockDeleteUser.Unlock()
+	return mock.DeleteUserFunc(ctx, userID)
+}
+
+// DeleteUserCalls gets all the calls that were made to DeleteUser.
+// Check the length with:
+//
+//	len(mockedStore.DeleteUserCalls())
+func (mock *StoreMock) DeleteUserCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockDeleteUser.RLock()
+	calls = mock.calls.DeleteUser
+	mock.lockDeleteUser.RUnlock()
+	return calls
+}
+
+// DeleteWebhook calls DeleteWebhookFunc.
+func (mock *StoreMock) DeleteWebhook(ctx context.Context, ownerID int64, webhookID int64) error {
+	if mock.DeleteWebhookFunc == nil {
+		panic("StoreMock.DeleteWebhookFunc: method is nil but Store.DeleteWebhook was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		OwnerID   int64
+		WebhookID int64
+	}{
+		Ctx:       ctx,
+		OwnerID:   ownerID,
+		WebhookID: webhookID,
+	}
+	mock.lockDeleteWebhook.Lock()
+	mock.calls.DeleteWebhook = append(mock.calls.DeleteWebhook, callInfo)
+	mock.lockDeleteWebhook.Unlock()
+	return mock.DeleteWebhookFunc(ctx, ownerID, webhookID)
+}
+
+// DeleteWebhookCalls gets all the calls that were made to DeleteWebhook.
+// Check the length with:
+//
+//	len(mockedStore.DeleteWebhookCalls())
+func (mock *StoreMock) DeleteWebhookCalls() []struct {
+	Ctx       context.Context
+	OwnerID   int64
+	WebhookID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		OwnerID   int64
+		WebhookID int64
+	}
+	mock.lockDeleteWebhook.RLock()
+	calls = mock.calls.DeleteWebhook
+	mock.lockDeleteWebhook.RUnlock()
+	return calls
+}
+
+// DenyJoinRequest calls DenyJoinRequestFunc.
+func (mock *StoreMock) DenyJoinRequest(ctx context.Context, eventID int64, userID int64, approverID int64) (*ConversationJoinRequest, error) {
+	if mock.DenyJoinRequestFunc == nil {
+		panic("StoreMock.DenyJoinRequestFunc: method is nil but Store.DenyJoinRequest was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		EventID    int64
+		UserID     int64
+		ApproverID int64
+	}{
+		Ctx:        ctx,
+		EventID:    eventID,
+		UserID:     userID,
+		ApproverID: approverID,
+	}
+	mock.lockDenyJoinRequest.Lock()
+	mock.calls.DenyJoinRequest = append(mock.calls.DenyJoinRequest, callInfo)
+	mock.lockDenyJoinRequest.Unlock()
+	return mock.DenyJoinRequestFunc(ctx, eventID, userID, approverID)
+}
+
+// DenyJoinRequestCalls gets all the calls that were made to DenyJoinRequest.
+// Check the length with:
+//
+//	len(mockedStore.DenyJoinRequestCalls())
+func (mock *StoreMock) DenyJoinRequestCalls() []struct {
+	Ctx        context.Context
+	EventID    int64
+	UserID     int64
+	ApproverID int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		EventID    int64
+		UserID     int64
+		ApproverID int64
+	}
+	mock.lockDenyJoinRequest.RLock()
+	calls = mock.calls.DenyJoinRequest
+	mock.lockDenyJoinRequest.RUnlock()
+	return calls
+}
+
+// DuplicateEvent calls DuplicateEventFunc.
+func (mock *StoreMock) DuplicateEvent(ctx context.Context, eventID int64, hostID int64, params DuplicateEventParams) (int64, error) {
+	if mock.DuplicateEventFunc == nil {
+		panic("StoreMock.DuplicateEventFunc: method is nil but Store.DuplicateEvent was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Params  DuplicateEventParams
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+		Params:  params,
+	}
+	mock.lockDuplicateEvent.Lock()
+	mock.calls.DuplicateEvent = append(mock.calls.DuplicateEvent, callInfo)
+	mock.lockDuplicateEvent.Unlock()
+	return mock.DuplicateEventFunc(ctx, eventID, hostID, params)
+}
+
+// DuplicateEventCalls gets all the calls that were made to DuplicateEvent.
+// Check the length with:
+//
+//	len(mockedStore.DuplicateEventCalls())
+func (mock *StoreMock) DuplicateEventCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+	Params  DuplicateEventParams
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Params  DuplicateEventParams
+	}
+	mock.lockDuplicateEvent.RLock()
+	calls = mock.calls.DuplicateEvent
+	mock.lockDuplicateEvent.RUnlock()
+	return calls
+}
+
+// FindOrCreateDirectConversation calls FindOrCreateDirectConversationFunc.
+func (mock *StoreMock) FindOrCreateDirectConversation(ctx context.Context, userID int64, otherID int64) (*Conversation, bool, error) {
+	if mock.FindOrCreateDirectConversationFunc == nil {
+		panic("StoreMock.FindOrCreateDirectConversationFunc: method is nil but Store.FindOrCreateDirectConversation was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  int64
+		OtherID int64
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		OtherID: otherID,
+	}
+	mock.lockFindOrCreateDirectConversation.Lock()
+	mock.calls.FindOrCreateDirectConversation = append(mock.calls.FindOrCreateDirectConversation, callInfo)
+	mock.lockFindOrCreateDirectConversation.Unlock()
+	return mock.FindOrCreateDirectConversationFunc(ctx, userID, otherID)
+}
+
+// FindOrCreateDirectConversationCalls gets all the calls that were made to FindOrCreateDirectConversation.
+// Check the length with:
+//
+//	len(mockedStore.FindOrCreateDirectConversationCalls())
+func (mock *StoreMock) FindOrCreateDirectConversationCalls() []struct {
+	Ctx     context.Context
+	UserID  int64
+	OtherID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  int64
+		OtherID int64
+	}
+	mock.lockFindOrCreateDirectConversation.RLock()
+	calls = mock.calls.FindOrCreateDirectConversation
+	mock.lockFindOrCreateDirectConversation.RUnlock()
+	return calls
+}
+
+// FlagMessage calls FlagMessageFunc.
+func (mock *StoreMock) FlagMessage(ctx context.Context, messageID int64, filter string, action string, reason string) error {
+	if mock.FlagMessageFunc == nil {
+		panic("StoreMock.FlagMessageFunc: method is nil but Store.FlagMessage was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		MessageID int64
+		Filter    string
+		Action    string
+		Reason    string
+	}{
+		Ctx:       ctx,
+		MessageID: messageID,
+		Filter:    filter,
+		Action:    action,
+		Reason:    reason,
+	}
+	mock.lockFlagMessage.Lock()
+	mock.calls.FlagMessage = append(mock.calls.FlagMessage, callInfo)
+	mock.lockFlagMessage.Unlock()
+	return mock.FlagMessageFunc(ctx, messageID, filter, action, reason)
+}
+
+// FlagMessageCalls gets all the calls that were made to FlagMessage.
+// Check the length with:
+//
+//	len(mockedStore.FlagMessageCalls())
+func (mock *StoreMock) FlagMessageCalls() []struct {
+	Ctx       context.Context
+	MessageID int64
+	Filter    string
+	Action    string
+	Reason    string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		MessageID int64
+		Filter    string
+		Action    string
+		Reason    string
+	}
+	mock.lockFlagMessage.RLock()
+	calls = mock.calls.FlagMessage
+	mock.lockFlagMessage.RUnlock()
+	return calls
+}
+
+// GetAvailability calls GetAvailabilityFunc.
+func (mock *StoreMock) GetAvailability(ctx context.Context, userID int64) (*Availability, error) {
+	if mock.GetAvailabilityFunc == nil {
+		panic("StoreMock.GetAvailabilityFunc: method is nil but Store.GetAvailability was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetAvailability.Lock()
+	mock.calls.GetAvailability = append(mock.calls.GetAvailability, callInfo)
+	mock.lockGetAvailability.Unlock()
+	return mock.GetAvailabilityFunc(ctx, userID)
+}
+
+// GetAvailabilityCalls gets all the calls that were made to GetAvailability.
+// Check the length with:
+//
+//	len(mockedStore.GetAvailabilityCalls())
+func (mock *StoreMock) GetAvailabilityCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetAvailability.RLock()
+	calls = mock.calls.GetAvailability
+	mock.lockGetAvailability.RUnlock()
+	return calls
+}
+
+// GetBotByUserID calls GetBotByUserIDFunc.
+func (mock *StoreMock) GetBotByUserID(ctx context.Context, userID int64) (*Bot, error) {
+	if mock.GetBotByUserIDFunc == nil {
+		panic("StoreMock.GetBotByUserIDFunc: method is nil but Store.GetBotByUserID was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetBotByUserID.Lock()
+	mock.calls.GetBotByUserID = append(mock.calls.GetBotByUserID, callInfo)
+	mock.lockGetBotByUserID.Unlock()
+	return mock.GetBotByUserIDFunc(ctx, userID)
+}
+
+// GetBotByUserIDCalls gets all the calls that were made to GetBotByUserID.
+// Check the length with:
+//
+//	len(mockedStore.GetBotByUserIDCalls())
+func (mock *StoreMock) GetBotByUserIDCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetBotByUserID.RLock()
+	calls = mock.calls.GetBotByUserID
+	mock.lockGetBotByUserID.RUnlock()
+	return calls
+}
+
+// GetConnection calls GetConnectionFunc.
+func (mock *StoreMock) GetConnection(ctx context.Context, connectionID int64, viewerID int64) (*Connection, error) {
+	if mock.GetConnectionFunc == nil {
+		panic("StoreMock.GetConnectionFunc: method is nil but Store.GetConnection was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		ConnectionID int64
+		ViewerID     int64
+	}{
+		Ctx:          ctx,
+		ConnectionID: connectionID,
+		ViewerID:     viewerID,
+	}
+	mock.lockGetConnection.Lock()
+	mock.calls.GetConnection = append(mock.calls.GetConnection, callInfo)
+	mock.lockGetConnection.Unlock()
+	return mock.GetConnectionFunc(ctx, connectionID, viewerID)
+}
+
+// GetConnectionCalls gets all the calls that were made to GetConnection.
+// Check the length with:
+//
+//	len(mockedStore.GetConnectionCalls())
+func (mock *StoreMock) GetConnectionCalls() []struct {
+	Ctx          context.Context
+	ConnectionID int64
+	ViewerID     int64
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ConnectionID int64
+		ViewerID     int64
+	}
+	mock.lockGetConnection.RLock()
+	calls = mock.calls.GetConnection
+	mock.lockGetConnection.RUnlock()
+	return calls
+}
+
+// GetConversation calls GetConversationFunc.
+func (mock *StoreMock) GetConversation(ctx context.Context, conversationID int64) (*Conversation, error) {
+	if mock.GetConversationFunc == nil {
+		panic("StoreMock.GetConversationFunc: method is nil but Store.GetConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+	}
+	mock.lockGetConversation.Lock()
+	mock.calls.GetConversation = append(mock.calls.GetConversation, callInfo)
+	mock.lockGetConversation.Unlock()
+	return mock.GetConversationFunc(ctx, conversationID)
+}
+
+// GetConversationCalls gets all the calls that were made to GetConversation.
+// Check the length with:
+//
+//	len(mockedStore.GetConversationCalls())
+func (mock *StoreMock) GetConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+	}
+	mock.lockGetConversation.RLock()
+	calls = mock.calls.GetConversation
+	mock.lockGetConversation.RUnlock()
+	return calls
+}
+
+// GetConversationByEventID calls GetConversationByEventIDFunc.
+func (mock *StoreMock) GetConversationByEventID(ctx context.Context, eventID int64) (*Conversation, error) {
+	if mock.GetConversationByEventIDFunc == nil {
+		panic("StoreMock.GetConversationByEventIDFunc: method is nil but Store.GetConversationByEventID was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+	}
+	mock.lockGetConversationByEventID.Lock()
+	mock.calls.GetConversationByEventID = append(mock.calls.GetConversationByEventID, callInfo)
+	mock.lockGetConversationByEventID.Unlock()
+	return mock.GetConversationByEventIDFunc(ctx, eventID)
+}
+
+// GetConversationByEventIDCalls gets all the calls that were made to GetConversationByEventID.
+// Check the length with:
+//
+//	len(mockedStore.GetConversationByEventIDCalls())
+func (mock *StoreMock) GetConversationByEventIDCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+	}
+	mock.lockGetConversationByEventID.RLock()
+	calls = mock.calls.GetConversationByEventID
+	mock.lockGetConversationByEventID.RUnlock()
+	return calls
+}
+
+// GetConversationDetail calls GetConversationDetailFunc.
+func (mock *StoreMock) GetConversationDetail(ctx context.Context, conversationID int64, viewerID int64) (*ConversationDetail, error) {
+	if mock.GetConversationDetailFunc == nil {
+		panic("StoreMock.GetConversationDetailFunc: method is nil but Store.GetConversationDetail was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ViewerID       int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ViewerID:       viewerID,
+	}
+	mock.lockGetConversationDetail.Lock()
+	mock.calls.GetConversationDetail = append(mock.calls.GetConversationDetail, callInfo)
+	mock.lockGetConversationDetail.Unlock()
+	return mock.GetConversationDetailFunc(ctx, conversationID, viewerID)
+}
+
+// GetConversationDetailCalls gets all the calls that were made to GetConversationDetail.
+// Check the length with:
+//
+//	len(mockedStore.GetConversationDetailCalls())
+func (mock *StoreMock) GetConversationDetailCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ViewerID       int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ViewerID       int64
+	}
+	mock.lockGetConversationDetail.RLock()
+	calls = mock.calls.GetConversationDetail
+	mock.lockGetConversationDetail.RUnlock()
+	return calls
+}
+
+// GetEmailRecipient calls GetEmailRecipientFunc.
+func (mock *StoreMock) GetEmailRecipient(ctx context.Context, userID int64, category notificationCategory) (*emailRecipient, error) {
+	if mock.GetEmailRecipientFunc == nil {
+		panic("StoreMock.GetEmailRecipientFunc: method is nil but Store.GetEmailRecipient was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		Category notificationCategory
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		Category: category,
+	}
+	mock.lockGetEmailRecipient.Lock()
+	mock.calls.GetEmailRecipient = append(mock.calls.GetEmailRecipient, callInfo)
+	mock.lockGetEmailRecipient.Unlock()
+	return mock.GetEmailRecipientFunc(ctx, userID, category)
+}
+
+// GetEmailRecipientCalls gets all the calls that were made to GetEmailRecipient.
+// Check the length with:
+//
+//	len(mockedStore.GetEmailRecipientCalls())
+func (mock *StoreMock) GetEmailRecipientCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	Category notificationCategory
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		Category notificationCategory
+	}
+	mock.lockGetEmailRecipient.RLock()
+	calls = mock.calls.GetEmailRecipient
+	mock.lockGetEmailRecipient.RUnlock()
+	return calls
+}
+
+// GetEventByID calls GetEventByIDFunc.
+func (mock *StoreMock) GetEventByID(ctx context.Context, eventID int64) (*Event, error) {
+	if mock.GetEventByIDFunc == nil {
+		panic("StoreMock.GetEventByIDFunc: method is nil but Store.GetEventByID was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+	}
+	mock.lockGetEventByID.Lock()
+	mock.calls.GetEventByID = append(mock.calls.GetEventByID, callInfo)
+	mock.lockGetEventByID.Unlock()
+	return mock.GetEventByIDFunc(ctx, eventID)
+}
+
+// GetEventByIDCalls gets all the calls that were made to GetEventByID.
+// Check the length with:
+//
+//	len(mockedStore.GetEventByIDCalls())
+func (mock *StoreMock) GetEventByIDCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+	}
+	mock.lockGetEventByID.RLock()
+	calls = mock.calls.GetEventByID
+	mock.lockGetEventByID.RUnlock()
+	return calls
+}
+
+// GetEventFeedVersion calls GetEventFeedVersionFunc.
+func (mock *StoreMock) GetEventFeedVersion(ctx context.Context, opts EventListOptions) (EventFeedVersion, error) {
+	if mock.GetEventFeedVersionFunc == nil {
+		panic("StoreMock.GetEventFeedVersionFunc: method is nil but Store.GetEventFeedVersion was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts EventListOptions
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+	}
+	mock.lockGetEventFeedVersion.Lock()
+	mock.calls.GetEventFeedVersion = append(mock.calls.GetEventFeedVersion, callInfo)
+	mock.lockGetEventFeedVersion.Unlock()
+	return mock.GetEventFeedVersionFunc(ctx, opts)
+}
+
+// GetEventFeedVersionCalls gets all the calls that were made to GetEventFeedVersion.
+// Check the length with:
+//
+//	len(mockedStore.GetEventFeedVersionCalls())
+func (mock *StoreMock) GetEventFeedVersionCalls() []struct {
+	Ctx  context.Context
+	Opts EventListOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts EventListOptions
+	}
+	mock.lockGetEventFeedVersion.RLock()
+	calls = mock.calls.GetEventFeedVersion
+	mock.lockGetEventFeedVersion.RUnlock()
+	return calls
+}
+
+// GetEventSettings calls GetEventSettingsFunc.
+func (mock *StoreMock) GetEventSettings(ctx context.Context, eventID int64) (*EventSettings, error) {
+	if mock.GetEventSettingsFunc == nil {
+		panic("StoreMock.GetEventSettingsFunc: method is nil but Store.GetEventSettings was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+	}
+	mock.lockGetEventSettings.Lock()
+	mock.calls.GetEventSettings = append(mock.calls.GetEventSettings, callInfo)
+	mock.lockGetEventSettings.Unlock()
+	return mock.GetEventSettingsFunc(ctx, eventID)
+}
+
+// GetEventSettingsCalls gets all the calls that were made to GetEventSettings.
+// Check the length with:
+//
+//	len(mockedStore.GetEventSettingsCalls())
+func (mock *StoreMock) GetEventSettingsCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+	}
+	mock.lockGetEventSettings.RLock()
+	calls = mock.calls.GetEventSettings
+	mock.lockGetEventSettings.RUnlock()
+	return calls
+}
+
+// GetEventsByIDs calls GetEventsByIDsFunc.
+func (mock *StoreMock) GetEventsByIDs(ctx context.Context, ids []int64, viewerID int64) (map[int64]Event, error) {
+	if mock.GetEventsByIDsFunc == nil {
+		panic("StoreMock.GetEventsByIDsFunc: method is nil but Store.GetEventsByIDs was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Ids      []int64
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		Ids:      ids,
+		ViewerID: viewerID,
+	}
+	mock.lockGetEventsByIDs.Lock()
+	mock.calls.GetEventsByIDs = append(mock.calls.GetEventsByIDs, callInfo)
+	mock.lockGetEventsByIDs.Unlock()
+	return mock.GetEventsByIDsFunc(ctx, ids, viewerID)
+}
+
+// GetEventsByIDsCalls gets all the calls that were made to GetEventsByIDs.
+// Check the length with:
+//
+//	len(mockedStore.GetEventsByIDsCalls())
+func (mock *StoreMock) GetEventsByIDsCalls() []struct {
+	Ctx      context.Context
+	Ids      []int64
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Ids      []int64
+		ViewerID int64
+	}
+	mock.lockGetEventsByIDs.RLock()
+	calls = mock.calls.GetEventsByIDs
+	mock.lockGetEventsByIDs.RUnlock()
+	return calls
+}
+
+// GetMemberActivity calls GetMemberActivityFunc.
+func (mock *StoreMock) GetMemberActivity(ctx context.Context, conversationID int64, userID int64) (*MemberActivity, error) {
+	if mock.GetMemberActivityFunc == nil {
+		panic("StoreMock.GetMemberActivityFunc: method is nil but Store.GetMemberActivity was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	mock.lockGetMemberActivity.Lock()
+	mock.calls.GetMemberActivity = append(mock.calls.GetMemberActivity, callInfo)
+	mock.lockGetMemberActivity.Unlock()
+	return mock.GetMemberActivityFunc(ctx, conversationID, userID)
+}
+
+// GetMemberActivityCalls gets all the calls that were made to GetMemberActivity.
+// Check the length with:
+//
+//	len(mockedStore.GetMemberActivityCalls())
+func (mock *StoreMock) GetMemberActivityCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}
+	mock.lockGetMemberActivity.RLock()
+	calls = mock.calls.GetMemberActivity
+	mock.lockGetMemberActivity.RUnlock()
+	return calls
+}
+
+// GetMessageByID calls GetMessageByIDFunc.
+func (mock *StoreMock) GetMessageByID(ctx context.Context, id int64) (*Message, error) {
+	if mock.GetMessageByIDFunc == nil {
+		panic("StoreMock.GetMessageByIDFunc: method is nil but Store.GetMessageByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetMessageByID.Lock()
+	mock.calls.GetMessageByID = append(mock.calls.GetMessageByID, callInfo)
+	mock.lockGetMessageByID.Unlock()
+	return mock.GetMessageByIDFunc(ctx, id)
+}
+
+// GetMessageByIDCalls gets all the calls that were made to GetMessageByID.
+// Check the length with:
+//
+//	len(mockedStore.GetMessageByIDCalls())
+func (mock *StoreMock) GetMessageByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetMessageByID.RLock()
+	calls = mock.calls.GetMessageByID
+	mock.lockGetMessageByID.RUnlock()
+	return calls
+}
+
+// GetNotificationSettings calls GetNotificationSettingsFunc.
+func (mock *StoreMock) GetNotificationSettings(ctx context.Context, userID int64) (*NotificationSettings, error) {
+	if mock.GetNotificationSettingsFunc == nil {
+		panic("StoreMock.GetNotificationSettingsFunc: method is nil but Store.GetNotificationSettings was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetNotificationSettings.Lock()
+	mock.calls.GetNotificationSettings = append(mock.calls.GetNotificationSettings, callInfo)
+	mock.lockGetNotificationSettings.Unlock()
+	return mock.GetNotificationSettingsFunc(ctx, userID)
+}
+
+// GetNotificationSettingsCalls gets all the calls that were made to GetNotificationSettings.
+// Check the length with:
+//
+//	len(mockedStore.GetNotificationSettingsCalls())
+func (mock *StoreMock) GetNotificationSettingsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetNotificationSettings.RLock()
+	calls = mock.calls.GetNotificationSettings
+	mock.lockGetNotificationSettings.RUnlock()
+	return calls
+}
+
+// GetPoll calls GetPollFunc.
+func (mock *StoreMock) GetPoll(ctx context.Context, pollID int64) (*Poll, error) {
+	if mock.GetPollFunc == nil {
+		panic("StoreMock.GetPollFunc: method is nil but Store.GetPoll was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		PollID int64
+	}{
+		Ctx:    ctx,
+		PollID: pollID,
+	}
+	mock.lockGetPoll.Lock()
+	mock.calls.GetPoll = append(mock.calls.GetPoll, callInfo)
+	mock.lockGetPoll.Unlock()
+	return mock.GetPollFunc(ctx, pollID)
+}
+
+// GetPollCalls gets all the calls that were made to GetPoll.
+// Check the length with:
+//
+//	len(mockedStore.GetPollCalls())
+func (mock *StoreMock) GetPollCalls() []struct {
+	Ctx    context.Context
+	PollID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		PollID int64
+	}
+	mock.lockGetPoll.RLock()
+	calls = mock.calls.GetPoll
+	mock.lockGetPoll.RUnlock()
+	return calls
+}
+
+// GetReminderSettings calls GetReminderSettingsFunc.
+func (mock *StoreMock) GetReminderSettings(ctx context.Context, userID int64) (*ReminderSettings, error) {
+	if mock.GetReminderSettingsFunc == nil {
+		panic("StoreMock.GetReminderSettingsFunc: method is nil but Store.GetReminderSettings was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetReminderSettings.Lock()
+	mock.calls.GetReminderSettings = append(mock.calls.GetReminderSettings, callInfo)
+	mock.lockGetReminderSettings.Unlock()
+	return mock.GetReminderSettingsFunc(ctx, userID)
+}
+
+// GetReminderSettingsCalls gets all the calls that were made to GetReminderSettings.
+// Check the length with:
+//
+//	len(mockedStore.GetReminderSettingsCalls())
+func (mock *StoreMock) GetReminderSettingsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetReminderSettings.RLock()
+	calls = mock.calls.GetReminderSettings
+	mock.lockGetReminderSettings.RUnlock()
+	return calls
+}
+
+// GetTimePoll calls GetTimePollFunc.
+func (mock *StoreMock) GetTimePoll(ctx context.Context, eventID int64) (*TimePoll, error) {
+	if mock.GetTimePollFunc == nil {
+		panic("StoreMock.GetTimePollFunc: method is nil but Store.GetTimePoll was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+	}
+	mock.lockGetTimePoll.Lock()
+	mock.calls.GetTimePoll = append(mock.calls.GetTimePoll, callInfo)
+	mock.lockGetTimePoll.Unlock()
+	return mock.GetTimePollFunc(ctx, eventID)
+}
+
+// GetTimePollCalls gets all the calls that were made to GetTimePoll.
+// Check the length with:
+//
+//	len(mockedStore.GetTimePollCalls())
+func (mock *StoreMock) GetTimePollCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+	}
+	mock.lockGetTimePoll.RLock()
+	calls = mock.calls.GetTimePoll
+	mock.lockGetTimePoll.RUnlock()
+	return calls
+}
+
+// GetUserByPhone calls GetUserByPhoneFunc.
+func (mock *StoreMock) GetUserByPhone(ctx context.Context, phone string) (*User, error) {
+	if mock.GetUserByPhoneFunc == nil {
+		panic("StoreMock.GetUserByPhoneFunc: method is nil but Store.GetUserByPhone was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Phone string
+	}{
+		Ctx:   ctx,
+		Phone: phone,
+	}
+	mock.lockGetUserByPhone.Lock()
+	mock.calls.GetUserByPhone = append(mock.calls.GetUserByPhone, callInfo)
+	mock.lockGetUserByPhone.Unlock()
+	return mock.GetUserByPhoneFunc(ctx, phone)
+}
+
+// GetUserByPhoneCalls gets all the calls that were made to GetUserByPhone.
+// Check the length with:
+//
+//	len(mockedStore.GetUserByPhoneCalls())
+func (mock *StoreMock) GetUserByPhoneCalls() []struct {
+	Ctx   context.Context
+	Phone string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Phone string
+	}
+	mock.lockGetUserByPhone.RLock()
+	calls = mock.calls.GetUserByPhone
+	mock.lockGetUserByPhone.RUnlock()
+	return calls
+}
+
+// GetUserNames calls GetUserNamesFunc.
+func (mock *StoreMock) GetUserNames(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	if mock.GetUserNamesFunc == nil {
+		panic("StoreMock.GetUserNamesFunc: method is nil but Store.GetUserNames was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserIDs []int64
+	}{
+		Ctx:     ctx,
+		UserIDs: userIDs,
+	}
+	mock.lockGetUserNames.Lock()
+	mock.calls.GetUserNames = append(mock.calls.GetUserNames, callInfo)
+	mock.lockGetUserNames.Unlock()
+	return mock.GetUserNamesFunc(ctx, userIDs)
+}
+
+// GetUserNamesCalls gets all the calls that were made to GetUserNames.
+// Check the length with:
+//
+//	len(mockedStore.GetUserNamesCalls())
+func (mock *StoreMock) GetUserNamesCalls() []struct {
+	Ctx     context.Context
+	UserIDs []int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserIDs []int64
+	}
+	mock.lockGetUserNames.RLock()
+	calls = mock.calls.GetUserNames
+	mock.lockGetUserNames.RUnlock()
+	return calls
+}
+
+// GetUserProfile calls GetUserProfileFunc.
+func (mock *StoreMock) GetUserProfile(ctx context.Context, userID int64) (*UserProfile, error) {
+	if mock.GetUserProfileFunc == nil {
+		panic("StoreMock.GetUserProfileFunc: method is nil but Store.GetUserProfile was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetUserProfile.Lock()
+	mock.calls.GetUserProfile = append(mock.calls.GetUserProfile, callInfo)
+	mock.lockGetUserProfile.Unlock()
+	return mock.GetUserProfileFunc(ctx, userID)
+}
+
+// GetUserProfileCalls gets all the calls that were made to GetUserProfile.
+// Check the length with:
+//
+//	len(mockedStore.GetUserProfileCalls())
+func (mock *StoreMock) GetUserProfileCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetUserProfile.RLock()
+	calls = mock.calls.GetUserProfile
+	mock.lockGetUserProfile.RUnlock()
+	return calls
+}
+
+// GetVerificationStatus calls GetVerificationStatusFunc.
+func (mock *StoreMock) GetVerificationStatus(ctx context.Context, userID int64) (bool, *VerificationRequest, error) {
+	if mock.GetVerificationStatusFunc == nil {
+		panic("StoreMock.GetVerificationStatusFunc: method is nil but Store.GetVerificationStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetVerificationStatus.Lock()
+	mock.calls.GetVerificationStatus = append(mock.calls.GetVerificationStatus, callInfo)
+	mock.lockGetVerificationStatus.Unlock()
+	return mock.GetVerificationStatusFunc(ctx, userID)
+}
+
+// GetVerificationStatusCalls gets all the calls that were made to GetVerificationStatus.
+// Check the length with:
+//
+//	len(mockedStore.GetVerificationStatusCalls())
+func (mock *StoreMock) GetVerificationStatusCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetVerificationStatus.RLock()
+	calls = mock.calls.GetVerificationStatus
+	mock.lockGetVerificationStatus.RUnlock()
+	return calls
+}
+
+// GetVisibleEvent calls GetVisibleEventFunc.
+func (mock *StoreMock) GetVisibleEvent(ctx context.Context, eventID int64, viewerID int64) (*Event, error) {
+	if mock.GetVisibleEventFunc == nil {
+		panic("StoreMock.GetVisibleEventFunc: method is nil but Store.GetVisibleEvent was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		EventID  int64
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		EventID:  eventID,
+		ViewerID: viewerID,
+	}
+	mock.lockGetVisibleEvent.Lock()
+	mock.calls.GetVisibleEvent = append(mock.calls.GetVisibleEvent, callInfo)
+	mock.lockGetVisibleEvent.Unlock()
+	return mock.GetVisibleEventFunc(ctx, eventID, viewerID)
+}
+
+// GetVisibleEventCalls gets all the calls that were made to GetVisibleEvent.
+// Check the length with:
+//
+//	len(mockedStore.GetVisibleEventCalls())
+func (mock *StoreMock) GetVisibleEventCalls() []struct {
+	Ctx      context.Context
+	EventID  int64
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		EventID  int64
+		ViewerID int64
+	}
+	mock.lockGetVisibleEvent.RLock()
+	calls = mock.calls.GetVisibleEvent
+	mock.lockGetVisibleEvent.RUnlock()
+	return calls
+}
+
+// HydrateConversationSummary calls HydrateConversationSummaryFunc.
+func (mock *StoreMock) HydrateConversationSummary(ctx context.Context, convo Conversation, viewerID int64) (ConversationSummary, error) {
+	if mock.HydrateConversationSummaryFunc == nil {
+		panic("StoreMock.HydrateConversationSummaryFunc: method is nil but Store.HydrateConversationSummary was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Convo    Conversation
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		Convo:    convo,
+		ViewerID: viewerID,
+	}
+	mock.lockHydrateConversationSummary.Lock()
+	mock.calls.HydrateConversationSummary = append(mock.calls.HydrateConversationSummary, callInfo)
+	mock.lockHydrateConversationSummary.Unlock()
+	return mock.HydrateConversationSummaryFunc(ctx, convo, viewerID)
+}
+
+// HydrateConversationSummaryCalls gets all the calls that were made to HydrateConversationSummary.
+// Check the length with:
+//
+//	len(mockedStore.HydrateConversationSummaryCalls())
+func (mock *StoreMock) HydrateConversationSummaryCalls() []struct {
+	Ctx      context.Context
+	Convo    Conversation
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Convo    Conversation
+		ViewerID int64
+	}
+	mock.lockHydrateConversationSummary.RLock()
+	calls = mock.calls.HydrateConversationSummary
+	mock.lockHydrateConversationSummary.RUnlock()
+	return calls
+}
+
+// IsBotInConversation calls IsBotInConversationFunc.
+func (mock *StoreMock) IsBotInConversation(ctx context.Context, botID int64, conversationID int64) (bool, error) {
+	if mock.IsBotInConversationFunc == nil {
+		panic("StoreMock.IsBotInConversationFunc: method is nil but Store.IsBotInConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		BotID          int64
+		ConversationID int64
+	}{
+		Ctx:            ctx,
+		BotID:          botID,
+		ConversationID: conversationID,
+	}
+	mock.lockIsBotInConversation.Lock()
+	mock.calls.IsBotInConversation = append(mock.calls.IsBotInConversation, callInfo)
+	mock.lockIsBotInConversation.Unlock()
+	return mock.IsBotInConversationFunc(ctx, botID, conversationID)
+}
+
+// IsBotInConversationCalls gets all the calls that were made to IsBotInConversation.
+// Check the length with:
+//
+//	len(mockedStore.IsBotInConversationCalls())
+func (mock *StoreMock) IsBotInConversationCalls() []struct {
+	Ctx            context.Context
+	BotID          int64
+	ConversationID int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		BotID          int64
+		ConversationID int64
+	}
+	mock.lockIsBotInConversation.RLock()
+	calls = mock.calls.IsBotInConversation
+	mock.lockIsBotInConversation.RUnlock()
+	return calls
+}
+
+// IsConversationLocked calls IsConversationLockedFunc.
+func (mock *StoreMock) IsConversationLocked(ctx context.Context, conversationID int64) (bool, error) {
+	if mock.IsConversationLockedFunc == nil {
+		panic("StoreMock.IsConversationLockedFunc: method is nil but Store.IsConversationLocked was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+	}
+	mock.lockIsConversationLocked.Lock()
+	mock.calls.IsConversationLocked = append(mock.calls.IsConversationLocked, callInfo)
+	mock.lockIsConversationLocked.Unlock()
+	return mock.IsConversationLockedFunc(ctx, conversationID)
+}
+
+// IsConversationLockedCalls gets all the calls that were made to IsConversationLocked.
+// Check the length with:
+//
+//	len(mockedStore.IsConversationLockedCalls())
+func (mock *StoreMock) IsConversationLockedCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+	}
+	mock.lockIsConversationLocked.RLock()
+	calls = mock.calls.IsConversationLocked
+	mock.lockIsConversationLocked.RUnlock()
+	return calls
+}
+
+// IsConversationMember calls IsConversationMemberFunc.
+func (mock *StoreMock) IsConversationMember(ctx context.Context, conversationID int64, userID int64) (bool, error) {
+	if mock.IsConversationMemberFunc == nil {
+		panic("StoreMock.IsConversationMemberFunc: method is nil but Store.IsConversationMember was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	mock.lockIsConversationMember.Lock()
+	mock.calls.IsConversationMember = append(mock.calls.IsConversationMember, callInfo)
+	mock.lockIsConversationMember.Unlock()
+	return mock.IsConversationMemberFunc(ctx, conversationID, userID)
+}
+
+// IsConversationMemberCalls gets all the calls that were made to IsConversationMember.
+// Check the length with:
+//
+//	len(mockedStore.IsConversationMemberCalls())
+func (mock *StoreMock) IsConversationMemberCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}
+	mock.lockIsConversationMember.RLock()
+	calls = mock.calls.IsConversationMember
+	mock.lockIsConversationMember.RUnlock()
+	return calls
+}
+
+// IsShadowBanned calls IsShadowBannedFunc.
+func (mock *StoreMock) IsShadowBanned(ctx context.Context, conversationID int64, userID int64) (bool, error) {
+	if mock.IsShadowBannedFunc == nil {
+		panic("StoreMock.IsShadowBannedFunc: method is nil but Store.IsShadowBanned was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	mock.lockIsShadowBanned.Lock()
+	mock.calls.IsShadowBanned = append(mock.calls.IsShadowBanned, callInfo)
+	mock.lockIsShadowBanned.Unlock()
+	return mock.IsShadowBannedFunc(ctx, conversationID, userID)
+}
+
+// IsShadowBannedCalls gets all the calls that were made to IsShadowBanned.
+// Check the length with:
+//
+//	len(mockedStore.IsShadowBannedCalls())
+func (mock *StoreMock) IsShadowBannedCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}
+	mock.lockIsShadowBanned.RLock()
+	calls = mock.calls.IsShadowBanned
+	mock.lockIsShadowBanned.RUnlock()
+	return calls
+}
+
+// IsUserDeleted calls IsUserDeletedFunc.
+func (mock *StoreMock) IsUserDeleted(ctx context.Context, userID int64) (bool, error) {
+	if mock.IsUserDeletedFunc == nil {
+		panic("StoreMock.IsUserDeletedFunc: method is nil but Store.IsUserDeleted was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockIsUserDeleted.Lock()
+	mock.calls.IsUserDeleted = append(mock.calls.IsUserDeleted, callInfo)
+	mock.lockIsUserDeleted.Unlock()
+	return mock.IsUserDeletedFunc(ctx, userID)
+}
+
+// IsUserDeletedCalls gets all the calls that were made to IsUserDeleted.
+// Check the length with:
+//
+//	len(mockedStore.IsUserDeletedCalls())
+func (mock *StoreMock) IsUserDeletedCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockIsUserDeleted.RLock()
+	calls = mock.calls.IsUserDeleted
+	mock.lockIsUserDeleted.RUnlock()
+	return calls
+}
+
+// LiftShadowBan calls LiftShadowBanFunc.
+func (mock *StoreMock) LiftShadowBan(ctx context.Context, conversationID int64, actorID int64, userID int64) error {
+	if mock.LiftShadowBanFunc == nil {
+		panic("StoreMock.LiftShadowBanFunc: method is nil but Store.LiftShadowBan was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+		UserID:         userID,
+	}
+	mock.lockLiftShadowBan.Lock()
+	mock.calls.LiftShadowBan = append(mock.calls.LiftShadowBan, callInfo)
+	mock.lockLiftShadowBan.Unlock()
+	return mock.LiftShadowBanFunc(ctx, conversationID, actorID, userID)
+}
+
+// LiftShadowBanCalls gets all the calls that were made to LiftShadowBan.
+// Check the length with:
+//
+//	len(mockedStore.LiftShadowBanCalls())
+func (mock *StoreMock) LiftShadowBanCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		UserID         int64
+	}
+	mock.lockLiftShadowBan.RLock()
+	calls = mock.calls.LiftShadowBan
+	mock.lockLiftShadowBan.RUnlock()
+	return calls
+}
+
+// List calls ListFunc.
+func (mock *StoreMock) List(ctx context.Context, opts EventListOptions) ([]Event, error) {
+	if mock.ListFunc == nil {
+		panic("StoreMock.ListFunc: method is nil but Store.List was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts EventListOptions
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+	}
+	mock.lockList.Lock()
+	mock.calls.List = append(mock.calls.List, callInfo)
+	mock.lockList.Unlock()
+	return mock.ListFunc(ctx, opts)
+}
+
+// ListCalls gets all the calls that were made to List.
+// Check the length with:
+//
+//	len(mockedStore.ListCalls())
+func (mock *StoreMock) ListCalls() []struct {
+	Ctx  context.Context
+	Opts EventListOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts EventListOptions
+	}
+	mock.lockList.RLock()
+	calls = mock.calls.List
+	mock.lockList.RUnlock()
+	return calls
+}
+
+// ListAvailabilityFeed calls ListAvailabilityFeedFunc.
+func (mock *StoreMock) ListAvailabilityFeed(ctx context.Context, viewerID int64) ([]Availability, error) {
+	if mock.ListAvailabilityFeedFunc == nil {
+		panic("StoreMock.ListAvailabilityFeedFunc: method is nil but Store.ListAvailabilityFeed was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		ViewerID: viewerID,
+	}
+	mock.lockListAvailabilityFeed.Lock()
+	mock.calls.ListAvailabilityFeed = append(mock.calls.ListAvailabilityFeed, callInfo)
+	mock.lockListAvailabilityFeed.Unlock()
+	return mock.ListAvailabilityFeedFunc(ctx, viewerID)
+}
+
+// ListAvailabilityFeedCalls gets all the calls that were made to ListAvailabilityFeed.
+// Check the length with:
+//
+//	len(mockedStore.ListAvailabilityFeedCalls())
+func (mock *StoreMock) ListAvailabilityFeedCalls() []struct {
+	Ctx      context.Context
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		ViewerID int64
+	}
+	mock.lockListAvailabilityFeed.RLock()
+	calls = mock.calls.ListAvailabilityFeed
+	mock.lockListAvailabilityFeed.RUnlock()
+	return calls
+}
+
+// ListBookmarkedEvents calls ListBookmarkedEventsFunc.
+func (mock *StoreMock) ListBookmarkedEvents(ctx context.Context, userID int64) ([]Event, error) {
+	if mock.ListBookmarkedEventsFunc == nil {
+		panic("StoreMock.ListBookmarkedEventsFunc: method is nil but Store.ListBookmarkedEvents was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockListBookmarkedEvents.Lock()
+	mock.calls.ListBookmarkedEvents = append(mock.calls.ListBookmarkedEvents, callInfo)
+	mock.lockListBookmarkedEvents.Unlock()
+	return mock.ListBookmarkedEventsFunc(ctx, userID)
+}
+
+// ListBookmarkedEventsCalls gets all the calls that were made to ListBookmarkedEvents.
+// Check the length with:
+//
+//	len(mockedStore.ListBookmarkedEventsCalls())
+func (mock *StoreMock) ListBookmarkedEventsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockListBookmarkedEvents.RLock()
+	calls = mock.calls.ListBookmarkedEvents
+	mock.lockListBookmarkedEvents.RUnlock()
+	return calls
+}
+
+// ListBots calls ListBotsFunc.
+func (mock *StoreMock) ListBots(ctx context.Context, ownerID int64) ([]Bot, error) {
+	if mock.ListBotsFunc == nil {
+		panic("StoreMock.ListBotsFunc: method is nil but Store.ListBots was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		OwnerID int64
+	}{
+		Ctx:     ctx,
+		OwnerID: ownerID,
+	}
+	mock.lockListBots.Lock()
+	mock.calls.ListBots = append(mock.calls.ListBots, callInfo)
+	mock.lockListBots.Unlock()
+	return mock.ListBotsFunc(ctx, ownerID)
+}
+
+// ListBotsCalls gets all the calls that were made to ListBots.
+// Check the length with:
+//
+//	len(mockedStore.ListBotsCalls())
+func (mock *StoreMock) ListBotsCalls() []struct {
+	Ctx     context.Context
+	OwnerID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		OwnerID int64
+	}
+	mock.lockListBots.RLock()
+	calls = mock.calls.ListBots
+	mock.lockListBots.RUnlock()
+	return calls
+}
+
+// ListConnections calls ListConnectionsFunc.
+func (mock *StoreMock) ListConnections(ctx context.Context, userID int64, status string) ([]Connection, error) {
+	if mock.ListConnectionsFunc == nil {
+		panic("StoreMock.ListConnectionsFunc: method is nil but Store.ListConnections was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Status string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Status: status,
+	}
+	mock.lockListConnections.Lock()
+	mock.calls.ListConnections = append(mock.calls.ListConnections, callInfo)
+	mock.lockListConnections.Unlock()
+	return mock.ListConnectionsFunc(ctx, userID, status)
+}
+
+// ListConnectionsCalls gets all the calls that were made to ListConnections.
+// Check the length with:
+//
+//	len(mockedStore.ListConnectionsCalls())
+func (mock *StoreMock) ListConnectionsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Status string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Status string
+	}
+	mock.lockListConnections.RLock()
+	calls = mock.calls.ListConnections
+	mock.lockListConnections.RUnlock()
+	return calls
+}
+
+// ListConversations calls ListConversationsFunc.
+func (mock *StoreMock) ListConversations(ctx context.Context, userID int64, opts ConversationListOptions) ([]ConversationSummary, string, error) {
+	if mock.ListConversationsFunc == nil {
+		panic("StoreMock.ListConversationsFunc: method is nil but Store.ListConversations was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Opts   ConversationListOptions
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Opts:   opts,
+	}
+	mock.lockListConversations.Lock()
+	mock.calls.ListConversations = append(mock.calls.ListConversations, callInfo)
+	mock.lockListConversations.Unlock()
+	return mock.ListConversationsFunc(ctx, userID, opts)
+}
+
+// ListConversationsCalls gets all the calls that were made to ListConversations.
+// Check the length with:
+//
+//	len(mockedStore.ListConversationsCalls())
+func (mock *StoreMock) ListConversationsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Opts   ConversationListOptions
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Opts   ConversationListOptions
+	}
+	mock.lockListConversations.RLock()
+	calls = mock.calls.ListConversations
+	mock.lockListConversations.RUnlock()
+	return calls
+}
+
+// ListEventBans calls ListEventBansFunc.
+func (mock *StoreMock) ListEventBans(ctx context.Context, eventID int64, actorID int64) ([]EventBan, error) {
+	if mock.ListEventBansFunc == nil {
+		panic("StoreMock.ListEventBansFunc: method is nil but Store.ListEventBans was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		ActorID int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		ActorID: actorID,
+	}
+	mock.lockListEventBans.Lock()
+	mock.calls.ListEventBans = append(mock.calls.ListEventBans, callInfo)
+	mock.lockListEventBans.Unlock()
+	return mock.ListEventBansFunc(ctx, eventID, actorID)
+}
+
+// ListEventBansCalls gets all the calls that were made to ListEventBans.
+// Check the length with:
+//
+//	len(mockedStore.ListEventBansCalls())
+func (mock *StoreMock) ListEventBansCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	ActorID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		ActorID int64
+	}
+	mock.lockListEventBans.RLock()
+	calls = mock.calls.ListEventBans
+	mock.lockListEventBans.RUnlock()
+	return calls
+}
+
+// ListEventChanges calls ListEventChangesFunc.
+func (mock *StoreMock) ListEventChanges(ctx context.Context, opts EventListOptions, since time.Time) ([]Event, []int64, error) {
+	if mock.ListEventChangesFunc == nil {
+		panic("StoreMock.ListEventChangesFunc: method is nil but Store.ListEventChanges was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Opts  EventListOptions
+		Since time.Time
+	}{
+		Ctx:   ctx,
+		Opts:  opts,
+		Since: since,
+	}
+	mock.lockListEventChanges.Lock()
+	mock.calls.ListEventChanges = append(mock.calls.ListEventChanges, callInfo)
+	mock.lockListEventChanges.Unlock()
+	return mock.ListEventChangesFunc(ctx, opts, since)
+}
+
+// ListEventChangesCalls gets all the calls that were made to ListEventChanges.
+// Check the length with:
+//
+//	len(mockedStore.ListEventChangesCalls())
+func (mock *StoreMock) ListEventChangesCalls() []struct {
+	Ctx   context.Context
+	Opts  EventListOptions
+	Since time.Time
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Opts  EventListOptions
+		Since time.Time
+	}
+	mock.lockListEventChanges.RLock()
+	calls = mock.calls.ListEventChanges
+	mock.lockListEventChanges.RUnlock()
+	return calls
+}
+
+// ListEventCheckins calls ListEventCheckinsFunc.
+func (mock *StoreMock) ListEventCheckins(ctx context.Context, eventID int64, actorID int64) ([]EventCheckin, error) {
+	if mock.ListEventCheckinsFunc == nil {
+		panic("StoreMock.ListEventCheckinsFunc: method is nil but Store.ListEventCheckins was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		ActorID int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		ActorID: actorID,
+	}
+	mock.lockListEventCheckins.Lock()
+	mock.calls.ListEventCheckins = append(mock.calls.ListEventCheckins, callInfo)
+	mock.lockListEventCheckins.Unlock()
+	return mock.ListEventCheckinsFunc(ctx, eventID, actorID)
+}
+
+// ListEventCheckinsCalls gets all the calls that were made to ListEventCheckins.
+// Check the length with:
+//
+//	len(mockedStore.ListEventCheckinsCalls())
+func (mock *StoreMock) ListEventCheckinsCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	ActorID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		ActorID int64
+	}
+	mock.lockListEventCheckins.RLock()
+	calls = mock.calls.ListEventCheckins
+	mock.lockListEventCheckins.RUnlock()
+	return calls
+}
+
+// ListEventMembers calls ListEventMembersFunc.
+func (mock *StoreMock) ListEventMembers(ctx context.Context, eventID int64, viewerID int64) ([]EventMember, error) {
+	if mock.ListEventMembersFunc == nil {
+		panic("StoreMock.ListEventMembersFunc: method is nil but Store.ListEventMembers was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		EventID  int64
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		EventID:  eventID,
+		ViewerID: viewerID,
+	}
+	mock.lockListEventMembers.Lock()
+	mock.calls.ListEventMembers = append(mock.calls.ListEventMembers, callInfo)
+	mock.lockListEventMembers.Unlock()
+	return mock.ListEventMembersFunc(ctx, eventID, viewerID)
+}
+
+// ListEventMembersCalls gets all the calls that were made to ListEventMembers.
+// Check the length with:
+//
+//	len(mockedStore.ListEventMembersCalls())
+func (mock *StoreMock) ListEventMembersCalls() []struct {
+	Ctx      context.Context
+	EventID  int64
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		EventID  int64
+		ViewerID int64
+	}
+	mock.lockListEventMembers.RLock()
+	calls = mock.calls.ListEventMembers
+	mock.lockListEventMembers.RUnlock()
+	return calls
+}
+
+// ListEventReviews calls ListEventReviewsFunc.
+func (mock *StoreMock) ListEventReviews(ctx context.Context, eventID int64, viewerID int64) ([]EventReview, error) {
+	if mock.ListEventReviewsFunc == nil {
+		panic("StoreMock.ListEventReviewsFunc: method is nil but Store.ListEventReviews was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		EventID  int64
+		ViewerID int64
+	}{
+		Ctx:      ctx,
+		EventID:  eventID,
+		ViewerID: viewerID,
+	}
+	mock.lockListEventReviews.Lock()
+	mock.calls.ListEventReviews = append(mock.calls.ListEventReviews, callInfo)
+	mock.lockListEventReviews.Unlock()
+	return mock.ListEventReviewsFunc(ctx, eventID, viewerID)
+}
+
+// ListEventReviewsCalls gets all the calls that were made to ListEventReviews.
+// Check the length with:
+//
+//	len(mockedStore.ListEventReviewsCalls())
+func (mock *StoreMock) ListEventReviewsCalls() []struct {
+	Ctx      context.Context
+	EventID  int64
+	ViewerID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		EventID  int64
+		ViewerID int64
+	}
+	mock.lockListEventReviews.RLock()
+	calls = mock.calls.ListEventReviews
+	mock.lockListEventReviews.RUnlock()
+	return calls
+}
+
+// ListEventRevisions calls ListEventRevisionsFunc.
+func (mock *StoreMock) ListEventRevisions(ctx context.Context, eventID int64, hostID int64) ([]EventRevision, error) {
+	if mock.ListEventRevisionsFunc == nil {
+		panic("StoreMock.ListEventRevisionsFunc: method is nil but Store.ListEventRevisions was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+	}
+	mock.lockListEventRevisions.Lock()
+	mock.calls.ListEventRevisions = append(mock.calls.ListEventRevisions, callInfo)
+	mock.lockListEventRevisions.Unlock()
+	return mock.ListEventRevisionsFunc(ctx, eventID, hostID)
+}
+
+// ListEventRevisionsCalls gets all the calls that were made to ListEventRevisions.
+// Check the length with:
+//
+//	len(mockedStore.ListEventRevisionsCalls())
+func (mock *StoreMock) ListEventRevisionsCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+	}
+	mock.lockListEventRevisions.RLock()
+	calls = mock.calls.ListEventRevisions
+	mock.lockListEventRevisions.RUnlock()
+	return calls
+}
+
+// ListEventTemplates calls ListEventTemplatesFunc.
+func (mock *StoreMock) ListEventTemplates(ctx context.Context, userID int64) ([]EventTemplate, error) {
+	if mock.ListEventTemplatesFunc == nil {
+		panic("StoreMock.ListEventTemplatesFunc: method is nil but Store.ListEventTemplates was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockListEventTemplates.Lock()
+	mock.calls.ListEventTemplates = append(mock.calls.ListEventTemplates, callInfo)
+	mock.lockListEventTemplates.Unlock()
+	return mock.ListEventTemplatesFunc(ctx, userID)
+}
+
+// ListEventTemplatesCalls gets all the calls that were made to ListEventTemplates.
+// Check the length with:
+//
+//	len(mockedStore.ListEventTemplatesCalls())
+func (mock *StoreMock) ListEventTemplatesCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockListEventTemplates.RLock()
+	calls = mock.calls.ListEventTemplates
+	mock.lockListEventTemplates.RUnlock()
+	return calls
+}
+
+// ListFilteredWords calls ListFilteredWordsFunc.
+func (mock *StoreMock) ListFilteredWords(ctx context.Context) ([]FilteredWord, error) {
+	if mock.ListFilteredWordsFunc == nil {
+		panic("StoreMock.ListFilteredWordsFunc: method is nil but Store.ListFilteredWords was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListFilteredWords.Lock()
+	mock.calls.ListFilteredWords = append(mock.calls.ListFilteredWords, callInfo)
+	mock.lockListFilteredWords.Unlock()
+	return mock.ListFilteredWordsFunc(ctx)
+}
+
+// ListFilteredWordsCalls gets all the calls that were made to ListFilteredWords.
+// Check the length with:
+//
+//	len(mockedStore.ListFilteredWordsCalls())
+func (mock *StoreMock) ListFilteredWordsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListFilteredWords.RLock()
+	calls = mock.calls.ListFilteredWords
+	mock.lockListFilteredWords.RUnlock()
+	return calls
+}
+
+// ListHostEvents calls ListHostEventsFunc.
+func (mock *StoreMock) ListHostEvents(ctx context.Context, hostID int64, viewerID int64, limit int, cursor string) ([]Event, string, error) {
+	if mock.ListHostEventsFunc == nil {
+		panic("StoreMock.ListHostEventsFunc: method is nil but Store.ListHostEvents was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		HostID   int64
+		ViewerID int64
+		Limit    int
+		Cursor   string
+	}{
+		Ctx:      ctx,
+		HostID:   hostID,
+		ViewerID: viewerID,
+		Limit:    limit,
+		Cursor:   cursor,
+	}
+	mock.lockListHostEvents.Lock()
+	mock.calls.ListHostEvents = append(mock.calls.ListHostEvents, callInfo)
+	mock.lockListHostEvents.Unlock()
+	return mock.ListHostEventsFunc(ctx, hostID, viewerID, limit, cursor)
+}
+
+// ListHostEventsCalls gets all the calls that were made to ListHostEvents.
+// Check the length with:
+//
+//	len(mockedStore.ListHostEventsCalls())
+func (mock *StoreMock) ListHostEventsCalls() []struct {
+	Ctx      context.Context
+	HostID   int64
+	ViewerID int64
+	Limit    int
+	Cursor   string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		HostID   int64
+		ViewerID int64
+		Limit    int
+		Cursor   string
+	}
+	mock.lockListHostEvents.RLock()
+	calls = mock.calls.ListHostEvents
+	mock.lockListHostEvents.RUnlock()
+	return calls
+}
+
+// ListJoinRequests calls ListJoinRequestsFunc.
+func (mock *StoreMock) ListJoinRequests(ctx context.Context, eventID int64, hostID int64, status string) ([]ConversationJoinRequest, error) {
+	if mock.ListJoinRequestsFunc == nil {
+		panic("StoreMock.ListJoinRequestsFunc: method is nil but Store.ListJoinRequests was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Status  string
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+		Status:  status,
+	}
+	mock.lockListJoinRequests.Lock()
+	mock.calls.ListJoinRequests = append(mock.calls.ListJoinRequests, callInfo)
+	mock.lockListJoinRequests.Unlock()
+	return mock.ListJoinRequestsFunc(ctx, eventID, hostID, status)
+}
+
+// ListJoinRequestsCalls gets all the calls that were made to ListJoinRequests.
+// Check the length with:
+//
+//	len(mockedStore.ListJoinRequestsCalls())
+func (mock *StoreMock) ListJoinRequestsCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+	Status  string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Status  string
+	}
+	mock.lockListJoinRequests.RLock()
+	calls = mock.calls.ListJoinRequests
+	mock.lockListJoinRequests.RUnlock()
+	return calls
+}
+
+// ListMemberEventIDs calls ListMemberEventIDsFunc.
+func (mock *StoreMock) ListMemberEventIDs(ctx context.Context, userID int64) (map[int64]struct{}, error) {
+	if mock.ListMemberEventIDsFunc == nil {
+		panic("StoreMock.ListMemberEventIDsFunc: method is nil but Store.ListMemberEventIDs was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockListMemberEventIDs.Lock()
+	mock.calls.ListMemberEventIDs = append(mock.calls.ListMemberEventIDs, callInfo)
+	mock.lockListMemberEventIDs.Unlock()
+	return mock.ListMemberEventIDsFunc(ctx, userID)
+}
+
+// ListMemberEventIDsCalls gets all the calls that were made to ListMemberEventIDs.
+// Check the length with:
+//
+//	len(mockedStore.ListMemberEventIDsCalls())
+func (mock *StoreMock) ListMemberEventIDsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockListMemberEventIDs.RLock()
+	calls = mock.calls.ListMemberEventIDs
+	mock.lockListMemberEventIDs.RUnlock()
+	return calls
+}
+
+// ListMessageFlags calls ListMessageFlagsFunc.
+func (mock *StoreMock) ListMessageFlags(ctx context.Context) ([]MessageFlag, error) {
+	if mock.ListMessageFlagsFunc == nil {
+		panic("StoreMock.ListMessageFlagsFunc: method is nil but Store.ListMessageFlags was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListMessageFlags.Lock()
+	mock.calls.ListMessageFlags = append(mock.calls.ListMessageFlags, callInfo)
+	mock.lockListMessageFlags.Unlock()
+	return mock.ListMessageFlagsFunc(ctx)
+}
+
+// ListMessageFlagsCalls gets all the calls that were made to ListMessageFlags.
+// Check the length with:
+//
+//	len(mockedStore.ListMessageFlagsCalls())
+func (mock *StoreMock) ListMessageFlagsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListMessageFlags.RLock()
+	calls = mock.calls.ListMessageFlags
+	mock.lockListMessageFlags.RUnlock()
+	return calls
+}
+
+// ListMessages calls ListMessagesFunc.
+func (mock *StoreMock) ListMessages(ctx context.Context, conversationID int64, viewerID int64, limit int, offset int) ([]Message, error) {
+	if mock.ListMessagesFunc == nil {
+		panic("StoreMock.ListMessagesFunc: method is nil but Store.ListMessages was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ViewerID       int64
+		Limit          int
+		Offset         int
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ViewerID:       viewerID,
+		Limit:          limit,
+		Offset:         offset,
+	}
+	mock.lockListMessages.Lock()
+	mock.calls.ListMessages = append(mock.calls.ListMessages, callInfo)
+	mock.lockListMessages.Unlock()
+	return mock.ListMessagesFunc(ctx, conversationID, viewerID, limit, offset)
+}
+
+// ListMessagesCalls gets all the calls that were made to ListMessages.
+// Check the length with:
+//
+//	len(mockedStore.ListMessagesCalls())
+func (mock *StoreMock) ListMessagesCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ViewerID       int64
+	Limit          int
+	Offset         int
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ViewerID       int64
+		Limit          int
+		Offset         int
+	}
+	mock.lockListMessages.RLock()
+	calls = mock.calls.ListMessages
+	mock.lockListMessages.RUnlock()
+	return calls
+}
+
+// ListPushTokensForConversation calls ListPushTokensForConversationFunc.
+func (mock *StoreMock) ListPushTokensForConversation(ctx context.Context, msg Message, now time.Time) ([]string, error) {
+	if mock.ListPushTokensForConversationFunc == nil {
+		panic("StoreMock.ListPushTokensForConversationFunc: method is nil but Store.ListPushTokensForConversation was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Msg Message
+		Now time.Time
+	}{
+		Ctx: ctx,
+		Msg: msg,
+		Now: now,
+	}
+	mock.lockListPushTokensForConversation.Lock()
+	mock.calls.ListPushTokensForConversation = append(mock.calls.ListPushTokensForConversation, callInfo)
+	mock.lockListPushTokensForConversation.Unlock()
+	return mock.ListPushTokensForConversationFunc(ctx, msg, now)
+}
+
+// ListPushTokensForConversationCalls gets all the calls that were made to ListPushTokensForConversation.
+// Check the length with:
+//
+//	len(mockedStore.ListPushTokensForConversationCalls())
+func (mock *StoreMock) ListPushTokensForConversationCalls() []struct {
+	Ctx context.Context
+	Msg Message
+	Now time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		Msg Message
+		Now time.Time
+	}
+	mock.lockListPushTokensForConversation.RLock()
+	calls = mock.calls.ListPushTokensForConversation
+	mock.lockListPushTokensForConversation.RUnlock()
+	return calls
+}
+
+// ListPushTokensForMentions calls ListPushTokensForMentionsFunc.
+func (mock *StoreMock) ListPushTokensForMentions(ctx context.Context, messageID int64) ([]string, error) {
+	if mock.ListPushTokensForMentionsFunc == nil {
+		panic("StoreMock.ListPushTokensForMentionsFunc: method is nil but Store.ListPushTokensForMentions was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		MessageID int64
+	}{
+		Ctx:       ctx,
+		MessageID: messageID,
+	}
+	mock.lockListPushTokensForMentions.Lock()
+	mock.calls.ListPushTokensForMentions = append(mock.calls.ListPushTokensForMentions, callInfo)
+	mock.lockListPushTokensForMentions.Unlock()
+	return mock.ListPushTokensForMentionsFunc(ctx, messageID)
+}
+
+// ListPushTokensForMentionsCalls gets all the calls that were made to ListPushTokensForMentions.
+// Check the length with:
+//
+//	len(mockedStore.ListPushTokensForMentionsCalls())
+func (mock *StoreMock) ListPushTokensForMentionsCalls() []struct {
+	Ctx       context.Context
+	MessageID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		MessageID int64
+	}
+	mock.lockListPushTokensForMentions.RLock()
+	calls = mock.calls.ListPushTokensForMentions
+	mock.lockListPushTokensForMentions.RUnlock()
+	return calls
+}
+
+// ListPushTokensForUser calls ListPushTokensForUserFunc.
+func (mock *StoreMock) ListPushTokensForUser(ctx context.Context, userID int64, category notificationCategory) ([]string, error) {
+	if mock.ListPushTokensForUserFunc == nil {
+		panic("StoreMock.ListPushTokensForUserFunc: method is nil but Store.ListPushTokensForUser was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		Category notificationCategory
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		Category: category,
+	}
+	mock.lockListPushTokensForUser.Lock()
+	mock.calls.ListPushTokensForUser = append(mock.calls.ListPushTokensForUser, callInfo)
+	mock.lockListPushTokensForUser.Unlock()
+	return mock.ListPushTokensForUserFunc(ctx, userID, category)
+}
+
+// ListPushTokensForUserCalls gets all the calls that were made to ListPushTokensForUser.
+// Check the length with:
+//
+//	len(mockedStore.ListPushTokensForUserCalls())
+func (mock *StoreMock) ListPushTokensForUserCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	Category notificationCategory
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		Category notificationCategory
+	}
+	mock.lockListPushTokensForUser.RLock()
+	calls = mock.calls.ListPushTokensForUser
+	mock.lockListPushTokensForUser.RUnlock()
+	return calls
+}
+
+// ListScheduledMessages calls ListScheduledMessagesFunc.
+func (mock *StoreMock) ListScheduledMessages(ctx context.Context, conversationID int64, senderID int64) ([]ScheduledMessage, error) {
+	if mock.ListScheduledMessagesFunc == nil {
+		panic("StoreMock.ListScheduledMessagesFunc: method is nil but Store.ListScheduledMessages was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		SenderID       int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		SenderID:       senderID,
+	}
+	mock.lockListScheduledMessages.Lock()
+	mock.calls.ListScheduledMessages = append(mock.calls.ListScheduledMessages, callInfo)
+	mock.lockListScheduledMessages.Unlock()
+	return mock.ListScheduledMessagesFunc(ctx, conversationID, senderID)
+}
+
+// ListScheduledMessagesCalls gets all the calls that were made to ListScheduledMessages.
+// Check the length with:
+//
+//	len(mockedStore.ListScheduledMessagesCalls())
+func (mock *StoreMock) ListScheduledMessagesCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	SenderID       int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		SenderID       int64
+	}
+	mock.lockListScheduledMessages.RLock()
+	calls = mock.calls.ListScheduledMessages
+	mock.lockListScheduledMessages.RUnlock()
+	return calls
+}
+
+// ListSessions calls ListSessionsFunc.
+func (mock *StoreMock) ListSessions(ctx context.Context, userID int64, now time.Time) ([]Session, error) {
+	if mock.ListSessionsFunc == nil {
+		panic("StoreMock.ListSessionsFunc: method is nil but Store.ListSessions was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Now    time.Time
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Now:    now,
+	}
+	mock.lockListSessions.Lock()
+	mock.calls.ListSessions = append(mock.calls.ListSessions, callInfo)
+	mock.lockListSessions.Unlock()
+	return mock.ListSessionsFunc(ctx, userID, now)
+}
+
+// ListSessionsCalls gets all the calls that were made to ListSessions.
+// Check the length with:
+//
+//	len(mockedStore.ListSessionsCalls())
+func (mock *StoreMock) ListSessionsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Now    time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Now    time.Time
+	}
+	mock.lockListSessions.RLock()
+	calls = mock.calls.ListSessions
+	mock.lockListSessions.RUnlock()
+	return calls
+}
+
+// ListShadowBans calls ListShadowBansFunc.
+func (mock *StoreMock) ListShadowBans(ctx context.Context, conversationID int64, actorID int64) ([]ShadowBan, error) {
+	if mock.ListShadowBansFunc == nil {
+		panic("StoreMock.ListShadowBansFunc: method is nil but Store.ListShadowBans was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+	}
+	mock.lockListShadowBans.Lock()
+	mock.calls.ListShadowBans = append(mock.calls.ListShadowBans, callInfo)
+	mock.lockListShadowBans.Unlock()
+	return mock.ListShadowBansFunc(ctx, conversationID, actorID)
+}
+
+// ListShadowBansCalls gets all the calls that were made to ListShadowBans.
+// Check the length with:
+//
+//	len(mockedStore.ListShadowBansCalls())
+func (mock *StoreMock) ListShadowBansCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+	}
+	mock.lockListShadowBans.RLock()
+	calls = mock.calls.ListShadowBans
+	mock.lockListShadowBans.RUnlock()
+	return calls
+}
+
+// ListTags calls ListTagsFunc.
+func (mock *StoreMock) ListTags(ctx context.Context) ([]Tag, error) {
+	if mock.ListTagsFunc == nil {
+		panic("StoreMock.ListTagsFunc: method is nil but Store.ListTags was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListTags.Lock()
+	mock.calls.ListTags = append(mock.calls.ListTags, callInfo)
+	mock.lockListTags.Unlock()
+	return mock.ListTagsFunc(ctx)
+}
+
+// ListTagsCalls gets all the calls that were made to ListTags.
+// Check the length with:
+//
+//	len(mockedStore.ListTagsCalls())
+func (mock *StoreMock) ListTagsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListTags.RLock()
+	calls = mock.calls.ListTags
+	mock.lockListTags.RUnlock()
+	return calls
+}
+
+// ListVerificationRequests calls ListVerificationRequestsFunc.
+func (mock *StoreMock) ListVerificationRequests(ctx context.Context, status string) ([]VerificationRequest, error) {
+	if mock.ListVerificationRequestsFunc == nil {
+		panic("StoreMock.ListVerificationRequestsFunc: method is nil but Store.ListVerificationRequests was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Status string
+	}{
+		Ctx:    ctx,
+		Status: status,
+	}
+	mock.lockListVerificationRequests.Lock()
+	mock.calls.ListVerificationRequests = append(mock.calls.ListVerificationRequests, callInfo)
+	mock.lockListVerificationRequests.Unlock()
+	return mock.ListVerificationRequestsFunc(ctx, status)
+}
+
+// ListVerificationRequestsCalls gets all the calls that were made to ListVerificationRequests.
+// Check the length with:
+//
+//	len(mockedStore.ListVerificationRequestsCalls())
+func (mock *StoreMock) ListVerificationRequestsCalls() []struct {
+	Ctx    context.Context
+	Status string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Status string
+	}
+	mock.lockListVerificationRequests.RLock()
+	calls = mock.calls.ListVerificationRequests
+	mock.lockListVerificationRequests.RUnlock()
+	return calls
+}
+
+// ListWebhookDeliveries calls ListWebhookDeliveriesFunc.
+func (mock *StoreMock) ListWebhookDeliveries(ctx context.Context, ownerID int64, webhookID int64) ([]WebhookDelivery, error) {
+	if mock.ListWebhookDeliveriesFunc == nil {
+		panic("StoreMock.ListWebhookDeliveriesFunc: method is nil but Store.ListWebhookDeliveries was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		OwnerID   int64
+		WebhookID int64
+	}{
+		Ctx:       ctx,
+		OwnerID:   ownerID,
+		WebhookID: webhookID,
+	}
+	mock.lockListWebhookDeliveries.Lock()
+	mock.calls.ListWebhookDeliveries = append(mock.calls.ListWebhookDeliveries, callInfo)
+	mock.lockListWebhookDeliveries.Unlock()
+	return mock.ListWebhookDeliveriesFunc(ctx, ownerID, webhookID)
+}
+
+// ListWebhookDeliveriesCalls gets all the calls that were made to ListWebhookDeliveries.
+// Check the length with:
+//
+//	len(mockedStore.ListWebhookDeliveriesCalls())
+func (mock *StoreMock) ListWebhookDeliveriesCalls() []struct {
+	Ctx       context.Context
+	OwnerID   int64
+	WebhookID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		OwnerID   int64
+		WebhookID int64
+	}
+	mock.lockListWebhookDeliveries.RLock()
+	calls = mock.calls.ListWebhookDeliveries
+	mock.lockListWebhookDeliveries.RUnlock()
+	return calls
+}
+
+// ListWebhooks calls ListWebhooksFunc.
+func (mock *StoreMock) ListWebhooks(ctx context.Context, ownerID int64) ([]Webhook, error) {
+	if mock.ListWebhooksFunc == nil {
+		panic("StoreMock.ListWebhooksFunc: method is nil but Store.ListWebhooks was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		OwnerID int64
+	}{
+		Ctx:     ctx,
+		OwnerID: ownerID,
+	}
+	mock.lockListWebhooks.Lock()
+	mock.calls.ListWebhooks = append(mock.calls.ListWebhooks, callInfo)
+	mock.lockListWebhooks.Unlock()
+	return mock.ListWebhooksFunc(ctx, ownerID)
+}
+
+// ListWebhooksCalls gets all the calls that were made to ListWebhooks.
+// Check the length with:
+//
+//	len(mockedStore.ListWebhooksCalls())
+func (mock *StoreMock) ListWebhooksCalls() []struct {
+	Ctx     context.Context
+	OwnerID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		OwnerID int64
+	}
+	mock.lockListWebhooks.RLock()
+	calls = mock.calls.ListWebhooks
+	mock.lockListWebhooks.RUnlock()
+	return calls
+}
+
+// LookupIdempotencyKey calls LookupIdempotencyKeyFunc.
+func (mock *StoreMock) LookupIdempotencyKey(ctx context.Context, userID int64, scope string, key string) (int64, bool, error) {
+	if mock.LookupIdempotencyKeyFunc == nil {
+		panic("StoreMock.LookupIdempotencyKeyFunc: method is nil but Store.LookupIdempotencyKey was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Scope  string
+		Key    string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Scope:  scope,
+		Key:    key,
+	}
+	mock.lockLookupIdempotencyKey.Lock()
+	mock.calls.LookupIdempotencyKey = append(mock.calls.LookupIdempotencyKey, callInfo)
+	mock.lockLookupIdempotencyKey.Unlock()
+	return mock.LookupIdempotencyKeyFunc(ctx, userID, scope, key)
+}
+
+// LookupIdempotencyKeyCalls gets all the calls that were made to LookupIdempotencyKey.
+// Check the length with:
+//
+//	len(mockedStore.LookupIdempotencyKeyCalls())
+func (mock *StoreMock) LookupIdempotencyKeyCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Scope  string
+	Key    string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Scope  string
+		Key    string
+	}
+	mock.lockLookupIdempotencyKey.RLock()
+	calls = mock.calls.LookupIdempotencyKey
+	mock.lockLookupIdempotencyKey.RUnlock()
+	return calls
+}
+
+// MarkConversationRead calls MarkConversationReadFunc.
+func (mock *StoreMock) MarkConversationRead(ctx context.Context, conversationID int64, userID int64, messageID int64) (int, error) {
+	if mock.MarkConversationReadFunc == nil {
+		panic("StoreMock.MarkConversationReadFunc: method is nil but Store.MarkConversationRead was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		MessageID      int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+		MessageID:      messageID,
+	}
+	mock.lockMarkConversationRead.Lock()
+	mock.calls.MarkConversationRead = append(mock.calls.MarkConversationRead, callInfo)
+	mock.lockMarkConversationRead.Unlock()
+	return mock.MarkConversationReadFunc(ctx, conversationID, userID, messageID)
+}
+
+// MarkConversationReadCalls gets all the calls that were made to MarkConversationRead.
+// Check the length with:
+//
+//	len(mockedStore.MarkConversationReadCalls())
+func (mock *StoreMock) MarkConversationReadCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+	MessageID      int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		MessageID      int64
+	}
+	mock.lockMarkConversationRead.RLock()
+	calls = mock.calls.MarkConversationRead
+	mock.lockMarkConversationRead.RUnlock()
+	return calls
+}
+
+// MuteConversation calls MuteConversationFunc.
+func (mock *StoreMock) MuteConversation(ctx context.Context, conversationID int64, userID int64, until *time.Time) (time.Time, error) {
+	if mock.MuteConversationFunc == nil {
+		panic("StoreMock.MuteConversationFunc: method is nil but Store.MuteConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		Until          *time.Time
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+		Until:          until,
+	}
+	mock.lockMuteConversation.Lock()
+	mock.calls.MuteConversation = append(mock.calls.MuteConversation, callInfo)
+	mock.lockMuteConversation.Unlock()
+	return mock.MuteConversationFunc(ctx, conversationID, userID, until)
+}
+
+// MuteConversationCalls gets all the calls that were made to MuteConversation.
+// Check the length with:
+//
+//	len(mockedStore.MuteConversationCalls())
+func (mock *StoreMock) MuteConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+	Until          *time.Time
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		Until          *time.Time
+	}
+	mock.lockMuteConversation.RLock()
+	calls = mock.calls.MuteConversation
+	mock.lockMuteConversation.RUnlock()
+	return calls
+}
+
+// PromoteWaitlistedUser calls PromoteWaitlistedUserFunc.
+func (mock *StoreMock) PromoteWaitlistedUser(ctx context.Context, eventID int64, userID int64, hostID int64) (*ConversationJoinRequest, error) {
+	if mock.PromoteWaitlistedUserFunc == nil {
+		panic("StoreMock.PromoteWaitlistedUserFunc: method is nil but Store.PromoteWaitlistedUser was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		HostID  int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		UserID:  userID,
+		HostID:  hostID,
+	}
+	mock.lockPromoteWaitlistedUser.Lock()
+	mock.calls.PromoteWaitlistedUser = append(mock.calls.PromoteWaitlistedUser, callInfo)
+	mock.lockPromoteWaitlistedUser.Unlock()
+	return mock.PromoteWaitlistedUserFunc(ctx, eventID, userID, hostID)
+}
+
+// PromoteWaitlistedUserCalls gets all the calls that were made to PromoteWaitlistedUser.
+// Check the length with:
+//
+//	len(mockedStore.PromoteWaitlistedUserCalls())
+func (mock *StoreMock) PromoteWaitlistedUserCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	UserID  int64
+	HostID  int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		HostID  int64
+	}
+	mock.lockPromoteWaitlistedUser.RLock()
+	calls = mock.calls.PromoteWaitlistedUser
+	mock.lockPromoteWaitlistedUser.RUnlock()
+	return calls
+}
+
+// RegisterPushToken calls RegisterPushTokenFunc.
+func (mock *StoreMock) RegisterPushToken(ctx context.Context, userID int64, token string, platform string) error {
+	if mock.RegisterPushTokenFunc == nil {
+		panic("StoreMock.RegisterPushTokenFunc: method is nil but Store.RegisterPushToken was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		Token    string
+		Platform string
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		Token:    token,
+		Platform: platform,
+	}
+	mock.lockRegisterPushToken.Lock()
+	mock.calls.RegisterPushToken = append(mock.calls.RegisterPushToken, callInfo)
+	mock.lockRegisterPushToken.Unlock()
+	return mock.RegisterPushTokenFunc(ctx, userID, token, platform)
+}
+
+// RegisterPushTokenCalls gets all the calls that were made to RegisterPushToken.
+// Check the length with:
+//
+//	len(mockedStore.RegisterPushTokenCalls())
+func (mock *StoreMock) RegisterPushTokenCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	Token    string
+	Platform string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		Token    string
+		Platform string
+	}
+	mock.lockRegisterPushToken.RLock()
+	calls = mock.calls.RegisterPushToken
+	mock.lockRegisterPushToken.RUnlock()
+	return calls
+}
+
+// RemoveBookmark calls RemoveBookmarkFunc.
+func (mock *StoreMock) RemoveBookmark(ctx context.Context, userID int64, eventID int64) error {
+	if mock.RemoveBookmarkFunc == nil {
+		panic("StoreMock.RemoveBookmarkFunc: method is nil but Store.RemoveBookmark was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  int64
+		EventID int64
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		EventID: eventID,
+	}
+	mock.lockRemoveBookmark.Lock()
+	mock.calls.RemoveBookmark = append(mock.calls.RemoveBookmark, callInfo)
+	mock.lockRemoveBookmark.Unlock()
+	return mock.RemoveBookmarkFunc(ctx, userID, eventID)
+}
+
+// RemoveBookmarkCalls gets all the calls that were made to RemoveBookmark.
+// Check the length with:
+//
+//	len(mockedStore.RemoveBookmarkCalls())
+func (mock *StoreMock) RemoveBookmarkCalls() []struct {
+	Ctx     context.Context
+	UserID  int64
+	EventID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  int64
+		EventID int64
+	}
+	mock.lockRemoveBookmark.RLock()
+	calls = mock.calls.RemoveBookmark
+	mock.lockRemoveBookmark.RUnlock()
+	return calls
+}
+
+// RemoveBotFromConversation calls RemoveBotFromConversationFunc.
+func (mock *StoreMock) RemoveBotFromConversation(ctx context.Context, conversationID int64, botID int64, actorID int64) (*Bot, error) {
+	if mock.RemoveBotFromConversationFunc == nil {
+		panic("StoreMock.RemoveBotFromConversationFunc: method is nil but Store.RemoveBotFromConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		BotID          int64
+		ActorID        int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		BotID:          botID,
+		ActorID:        actorID,
+	}
+	mock.lockRemoveBotFromConversation.Lock()
+	mock.calls.RemoveBotFromConversation = append(mock.calls.RemoveBotFromConversation, callInfo)
+	mock.lockRemoveBotFromConversation.Unlock()
+	return mock.RemoveBotFromConversationFunc(ctx, conversationID, botID, actorID)
+}
+
+// RemoveBotFromConversationCalls gets all the calls that were made to RemoveBotFromConversation.
+// Check the length with:
+//
+//	len(mockedStore.RemoveBotFromConversationCalls())
+func (mock *StoreMock) RemoveBotFromConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	BotID          int64
+	ActorID        int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		BotID          int64
+		ActorID        int64
+	}
+	mock.lockRemoveBotFromConversation.RLock()
+	calls = mock.calls.RemoveBotFromConversation
+	mock.lockRemoveBotFromConversation.RUnlock()
+	return calls
+}
+
+// RemoveConnection calls RemoveConnectionFunc.
+func (mock *StoreMock) RemoveConnection(ctx context.Context, userID int64, otherID int64) error {
+	if mock.RemoveConnectionFunc == nil {
+		panic("StoreMock.RemoveConnectionFunc: method is nil but Store.RemoveConnection was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  int64
+		OtherID int64
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		OtherID: otherID,
+	}
+	mock.lockRemoveConnection.Lock()
+	mock.calls.RemoveConnection = append(mock.calls.RemoveConnection, callInfo)
+	mock.lockRemoveConnection.Unlock()
+	return mock.RemoveConnectionFunc(ctx, userID, otherID)
+}
+
+// RemoveConnectionCalls gets all the calls that were made to RemoveConnection.
+// Check the length with:
+//
+//	len(mockedStore.RemoveConnectionCalls())
+func (mock *StoreMock) RemoveConnectionCalls() []struct {
+	Ctx     context.Context
+	UserID  int64
+	OtherID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  int64
+		OtherID int64
+	}
+	mock.lockRemoveConnection.RLock()
+	calls = mock.calls.RemoveConnection
+	mock.lockRemoveConnection.RUnlock()
+	return calls
+}
+
+// RemoveEventMember calls RemoveEventMemberFunc.
+func (mock *StoreMock) RemoveEventMember(ctx context.Context, eventID int64, userID int64, actorID int64, ban bool) (*ConversationJoinRequest, error) {
+	if mock.RemoveEventMemberFunc == nil {
+		panic("StoreMock.RemoveEventMemberFunc: method is nil but Store.RemoveEventMember was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		ActorID int64
+		Ban     bool
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		UserID:  userID,
+		ActorID: actorID,
+		Ban:     ban,
+	}
+	mock.lockRemoveEventMember.Lock()
+	mock.calls.RemoveEventMember = append(mock.calls.RemoveEventMember, callInfo)
+	mock.lockRemoveEventMember.Unlock()
+	return mock.RemoveEventMemberFunc(ctx, eventID, userID, actorID, ban)
+}
+
+// RemoveEventMemberCalls gets all the calls that were made to RemoveEventMember.
+// Check the length with:
+//
+//	len(mockedStore.RemoveEventMemberCalls())
+func (mock *StoreMock) RemoveEventMemberCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	UserID  int64
+	ActorID int64
+	Ban     bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		ActorID int64
+		Ban     bool
+	}
+	mock.lockRemoveEventMember.RLock()
+	calls = mock.calls.RemoveEventMember
+	mock.lockRemoveEventMember.RUnlock()
+	return calls
+}
+
+// RemovePushToken calls RemovePushTokenFunc.
+func (mock *StoreMock) RemovePushToken(ctx context.Context, userID int64, token string) error {
+	if mock.RemovePushTokenFunc == nil {
+		panic("StoreMock.RemovePushTokenFunc: method is nil but Store.RemovePushToken was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Token  string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Token:  token,
+	}
+	mock.lockRemovePushToken.Lock()
+	mock.calls.RemovePushToken = append(mock.calls.RemovePushToken, callInfo)
+	mock.lockRemovePushToken.Unlock()
+	return mock.RemovePushTokenFunc(ctx, userID, token)
+}
+
+// RemovePushTokenCalls gets all the calls that were made to RemovePushToken.
+// Check the length with:
+//
+//	len(mockedStore.RemovePushTokenCalls())
+func (mock *StoreMock) RemovePushTokenCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Token  string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Token  string
+	}
+	mock.lockRemovePushToken.RLock()
+	calls = mock.calls.RemovePushToken
+	mock.lockRemovePushToken.RUnlock()
+	return calls
+}
+
+// RenameConversation calls RenameConversationFunc.
+func (mock *StoreMock) RenameConversation(ctx context.Context, conversationID int64, actorID int64, title string) error {
+	if mock.RenameConversationFunc == nil {
+		panic("StoreMock.RenameConversationFunc: method is nil but Store.RenameConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		Title          string
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+		Title:          title,
+	}
+	mock.lockRenameConversation.Lock()
+	mock.calls.RenameConversation = append(mock.calls.RenameConversation, callInfo)
+	mock.lockRenameConversation.Unlock()
+	return mock.RenameConversationFunc(ctx, conversationID, actorID, title)
+}
+
+// RenameConversationCalls gets all the calls that were made to RenameConversation.
+// Check the length with:
+//
+//	len(mockedStore.RenameConversationCalls())
+func (mock *StoreMock) RenameConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+	Title          string
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		Title          string
+	}
+	mock.lockRenameConversation.RLock()
+	calls = mock.calls.RenameConversation
+	mock.lockRenameConversation.RUnlock()
+	return calls
+}
+
+// RequireEventModerator calls RequireEventModeratorFunc.
+func (mock *StoreMock) RequireEventModerator(ctx context.Context, eventID int64, userID int64) error {
+	if mock.RequireEventModeratorFunc == nil {
+		panic("StoreMock.RequireEventModeratorFunc: method is nil but Store.RequireEventModerator was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		UserID:  userID,
+	}
+	mock.lockRequireEventModerator.Lock()
+	mock.calls.RequireEventModerator = append(mock.calls.RequireEventModerator, callInfo)
+	mock.lockRequireEventModerator.Unlock()
+	return mock.RequireEventModeratorFunc(ctx, eventID, userID)
+}
+
+// RequireEventModeratorCalls gets all the calls that were made to RequireEventModerator.
+// Check the length with:
+//
+//	len(mockedStore.RequireEventModeratorCalls())
+func (mock *StoreMock) RequireEventModeratorCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	UserID  int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+	}
+	mock.lockRequireEventModerator.RLock()
+	calls = mock.calls.RequireEventModerator
+	mock.lockRequireEventModerator.RUnlock()
+	return calls
+}
+
+// ResetPassword calls ResetPasswordFunc.
+func (mock *StoreMock) ResetPassword(ctx context.Context, token string, password string, now time.Time) error {
+	if mock.ResetPasswordFunc == nil {
+		panic("StoreMock.ResetPasswordFunc: method is nil but Store.ResetPassword was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Token    string
+		Password string
+		Now      time.Time
+	}{
+		Ctx:      ctx,
+		Token:    token,
+		Password: password,
+		Now:      now,
+	}
+	mock.lockResetPassword.Lock()
+	mock.calls.ResetPassword = append(mock.calls.ResetPassword, callInfo)
+	mock.lockResetPassword.Unlock()
+	return mock.ResetPasswordFunc(ctx, token, password, now)
+}
+
+// ResetPasswordCalls gets all the calls that were made to ResetPassword.
+// Check the length with:
+//
+//	len(mockedStore.ResetPasswordCalls())
+func (mock *StoreMock) ResetPasswordCalls() []struct {
+	Ctx      context.Context
+	Token    string
+	Password string
+	Now      time.Time
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Token    string
+		Password string
+		Now      time.Time
+	}
+	mock.lockResetPassword.RLock()
+	calls = mock.calls.ResetPassword
+	mock.lockResetPassword.RUnlock()
+	return calls
+}
+
+// RespondToConnectionRequest calls RespondToConnectionRequestFunc.
+func (mock *StoreMock) RespondToConnectionRequest(ctx context.Context, userID int64, requesterID int64, accept bool) (*Connection, error) {
+	if mock.RespondToConnectionRequestFunc == nil {
+		panic("StoreMock.RespondToConnectionRequestFunc: method is nil but Store.RespondToConnectionRequest was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		UserID      int64
+		RequesterID int64
+		Accept      bool
+	}{
+		Ctx:         ctx,
+		UserID:      userID,
+		RequesterID: requesterID,
+		Accept:      accept,
+	}
+	mock.lockRespondToConnectionRequest.Lock()
+	mock.calls.RespondToConnectionRequest = append(mock.calls.RespondToConnectionRequest, callInfo)
+	mock.lockRespondToConnectionRequest.Unlock()
+	return mock.RespondToConnectionRequestFunc(ctx, userID, requesterID, accept)
+}
+
+// RespondToConnectionRequestCalls gets all the calls that were made to RespondToConnectionRequest.
+// Check the length with:
+//
+//	len(mockedStore.RespondToConnectionRequestCalls())
+func (mock *StoreMock) RespondToConnectionRequestCalls() []struct {
+	Ctx         context.Context
+	UserID      int64
+	RequesterID int64
+	Accept      bool
+} {
+	var calls []struct {
+		Ctx         context.Context
+		UserID      int64
+		RequesterID int64
+		Accept      bool
+	}
+	mock.lockRespondToConnectionRequest.RLock()
+	calls = mock.calls.RespondToConnectionRequest
+	mock.lockRespondToConnectionRequest.RUnlock()
+	return calls
+}
+
+// RetractPollVote calls RetractPollVoteFunc.
+func (mock *StoreMock) RetractPollVote(ctx context.Context, conversationID int64, pollID int64, userID int64) (*Poll, error) {
+	if mock.RetractPollVoteFunc == nil {
+		panic("StoreMock.RetractPollVoteFunc: method is nil but Store.RetractPollVote was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		PollID         int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		PollID:         pollID,
+		UserID:         userID,
+	}
+	mock.lockRetractPollVote.Lock()
+	mock.calls.RetractPollVote = append(mock.calls.RetractPollVote, callInfo)
+	mock.lockRetractPollVote.Unlock()
+	return mock.RetractPollVoteFunc(ctx, conversationID, pollID, userID)
+}
+
+// RetractPollVoteCalls gets all the calls that were made to RetractPollVote.
+// Check the length with:
+//
+//	len(mockedStore.RetractPollVoteCalls())
+func (mock *StoreMock) RetractPollVoteCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	PollID         int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		PollID         int64
+		UserID         int64
+	}
+	mock.lockRetractPollVote.RLock()
+	calls = mock.calls.RetractPollVote
+	mock.lockRetractPollVote.RUnlock()
+	return calls
+}
+
+// RevokeSession calls RevokeSessionFunc.
+func (mock *StoreMock) RevokeSession(ctx context.Context, userID int64, sessionID int64, now time.Time) error {
+	if mock.RevokeSessionFunc == nil {
+		panic("StoreMock.RevokeSessionFunc: method is nil but Store.RevokeSession was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    int64
+		SessionID int64
+		Now       time.Time
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		SessionID: sessionID,
+		Now:       now,
+	}
+	mock.lockRevokeSession.Lock()
+	mock.calls.RevokeSession = append(mock.calls.RevokeSession, callInfo)
+	mock.lockRevokeSession.Unlock()
+	return mock.RevokeSessionFunc(ctx, userID, sessionID, now)
+}
+
+// RevokeSessionCalls gets all the calls that were made to RevokeSession.
+// Check the length with:
+//
+//	len(mockedStore.RevokeSessionCalls())
+func (mock *StoreMock) RevokeSessionCalls() []struct {
+	Ctx       context.Context
+	UserID    int64
+	SessionID int64
+	Now       time.Time
+} {
+	var calls []struct {
+		Ctx       context.Context
+		UserID    int64
+		SessionID int64
+		Now       time.Time
+	}
+	mock.lockRevokeSession.RLock()
+	calls = mock.calls.RevokeSession
+	mock.lockRevokeSession.RUnlock()
+	return calls
+}
+
+// ScheduleMessage calls ScheduleMessageFunc.
+func (mock *StoreMock) ScheduleMessage(ctx context.Context, conversationID int64, senderID int64, body string, sendAt time.Time) (*ScheduledMessage, error) {
+	if mock.ScheduleMessageFunc == nil {
+		panic("StoreMock.ScheduleMessageFunc: method is nil but Store.ScheduleMessage was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		SenderID       int64
+		Body           string
+		SendAt         time.Time
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Body:           body,
+		SendAt:         sendAt,
+	}
+	mock.lockScheduleMessage.Lock()
+	mock.calls.ScheduleMessage = append(mock.calls.ScheduleMessage, callInfo)
+	mock.lockScheduleMessage.Unlock()
+	return mock.ScheduleMessageFunc(ctx, conversationID, senderID, body, sendAt)
+}
+
+// ScheduleMessageCalls gets all the calls that were made to ScheduleMessage.
+// Check the length with:
+//
+//	len(mockedStore.ScheduleMessageCalls())
+func (mock *StoreMock) ScheduleMessageCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	SenderID       int64
+	Body           string
+	SendAt         time.Time
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		SenderID       int64
+		Body           string
+		SendAt         time.Time
+	}
+	mock.lockScheduleMessage.RLock()
+	calls = mock.calls.ScheduleMessage
+	mock.lockScheduleMessage.RUnlock()
+	return calls
+}
+
+// SendConnectionRequest calls SendConnectionRequestFunc.
+func (mock *StoreMock) SendConnectionRequest(ctx context.Context, userID int64, targetID int64) (*Connection, error) {
+	if mock.SendConnectionRequestFunc == nil {
+		panic("StoreMock.SendConnectionRequestFunc: method is nil but Store.SendConnectionRequest was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		TargetID int64
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		TargetID: targetID,
+	}
+	mock.lockSendConnectionRequest.Lock()
+	mock.calls.SendConnectionRequest = append(mock.calls.SendConnectionRequest, callInfo)
+	mock.lockSendConnectionRequest.Unlock()
+	return mock.SendConnectionRequestFunc(ctx, userID, targetID)
+}
+
+// SendConnectionRequestCalls gets all the calls that were made to SendConnectionRequest.
+// Check the length with:
+//
+//	len(mockedStore.SendConnectionRequestCalls())
+func (mock *StoreMock) SendConnectionRequestCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	TargetID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		TargetID int64
+	}
+	mock.lockSendConnectionRequest.RLock()
+	calls = mock.calls.SendConnectionRequest
+	mock.lockSendConnectionRequest.RUnlock()
+	return calls
+}
+
+// SetAvailability calls SetAvailabilityFunc.
+func (mock *StoreMock) SetAvailability(ctx context.Context, userID int64, params SetAvailabilityParams) (*Availability, error) {
+	if mock.SetAvailabilityFunc == nil {
+		panic("StoreMock.SetAvailabilityFunc: method is nil but Store.SetAvailability was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Params SetAvailabilityParams
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Params: params,
+	}
+	mock.lockSetAvailability.Lock()
+	mock.calls.SetAvailability = append(mock.calls.SetAvailability, callInfo)
+	mock.lockSetAvailability.Unlock()
+	return mock.SetAvailabilityFunc(ctx, userID, params)
+}
+
+// SetAvailabilityCalls gets all the calls that were made to SetAvailability.
+// Check the length with:
+//
+//	len(mockedStore.SetAvailabilityCalls())
+func (mock *StoreMock) SetAvailabilityCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Params SetAvailabilityParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Params SetAvailabilityParams
+	}
+	mock.lockSetAvailability.RLock()
+	calls = mock.calls.SetAvailability
+	mock.lockSetAvailability.RUnlock()
+	return calls
+}
+
+// SetEventCoHost calls SetEventCoHostFunc.
+func (mock *StoreMock) SetEventCoHost(ctx context.Context, eventID int64, hostID int64, userID int64, coHost bool) (int64, error) {
+	if mock.SetEventCoHostFunc == nil {
+		panic("StoreMock.SetEventCoHostFunc: method is nil but Store.SetEventCoHost was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		UserID  int64
+		CoHost  bool
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+		UserID:  userID,
+		CoHost:  coHost,
+	}
+	mock.lockSetEventCoHost.Lock()
+	mock.calls.SetEventCoHost = append(mock.calls.SetEventCoHost, callInfo)
+	mock.lockSetEventCoHost.Unlock()
+	return mock.SetEventCoHostFunc(ctx, eventID, hostID, userID, coHost)
+}
+
+// SetEventCoHostCalls gets all the calls that were made to SetEventCoHost.
+// Check the length with:
+//
+//	len(mockedStore.SetEventCoHostCalls())
+func (mock *StoreMock) SetEventCoHostCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+	UserID  int64
+	CoHost  bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		UserID  int64
+		CoHost  bool
+	}
+	mock.lockSetEventCoHost.RLock()
+	calls = mock.calls.SetEventCoHost
+	mock.lockSetEventCoHost.RUnlock()
+	return calls
+}
+
+// SetNotificationSettings calls SetNotificationSettingsFunc.
+func (mock *StoreMock) SetNotificationSettings(ctx context.Context, userID int64, settings NotificationSettings) error {
+	if mock.SetNotificationSettingsFunc == nil {
+		panic("StoreMock.SetNotificationSettingsFunc: method is nil but Store.SetNotificationSettings was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		Settings NotificationSettings
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		Settings: settings,
+	}
+	mock.lockSetNotificationSettings.Lock()
+	mock.calls.SetNotificationSettings = append(mock.calls.SetNotificationSettings, callInfo)
+	mock.lockSetNotificationSettings.Unlock()
+	return mock.SetNotificationSettingsFunc(ctx, userID, settings)
+}
+
+// SetNotificationSettingsCalls gets all the calls that were made to SetNotificationSettings.
+// Check the length with:
+//
+//	len(mockedStore.SetNotificationSettingsCalls())
+func (mock *StoreMock) SetNotificationSettingsCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	Settings NotificationSettings
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		Settings NotificationSettings
+	}
+	mock.lockSetNotificationSettings.RLock()
+	calls = mock.calls.SetNotificationSettings
+	mock.lockSetNotificationSettings.RUnlock()
+	return calls
+}
+
+// SetReminderSettings calls SetReminderSettingsFunc.
+func (mock *StoreMock) SetReminderSettings(ctx context.Context, userID int64, settings ReminderSettings) error {
+	if mock.SetReminderSettingsFunc == nil {
+		panic("StoreMock.SetReminderSettingsFunc: method is nil but Store.SetReminderSettings was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		Settings ReminderSettings
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		Settings: settings,
+	}
+	mock.lockSetReminderSettings.Lock()
+	mock.calls.SetReminderSettings = append(mock.calls.SetReminderSettings, callInfo)
+	mock.lockSetReminderSettings.Unlock()
+	return mock.SetReminderSettingsFunc(ctx, userID, settings)
+}
+
+// SetReminderSettingsCalls gets all the calls that were made to SetReminderSettings.
+// Check the length with:
+//
+//	len(mockedStore.SetReminderSettingsCalls())
+func (mock *StoreMock) SetReminderSettingsCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	Settings ReminderSettings
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		Settings ReminderSettings
+	}
+	mock.lockSetReminderSettings.RLock()
+	calls = mock.calls.SetReminderSettings
+	mock.lockSetReminderSettings.RUnlock()
+	return calls
+}
+
+// SetSlowMode calls SetSlowModeFunc.
+func (mock *StoreMock) SetSlowMode(ctx context.Context, conversationID int64, actorID int64, seconds int) error {
+	if mock.SetSlowModeFunc == nil {
+		panic("StoreMock.SetSlowModeFunc: method is nil but Store.SetSlowMode was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		Seconds        int
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+		Seconds:        seconds,
+	}
+	mock.lockSetSlowMode.Lock()
+	mock.calls.SetSlowMode = append(mock.calls.SetSlowMode, callInfo)
+	mock.lockSetSlowMode.Unlock()
+	return mock.SetSlowModeFunc(ctx, conversationID, actorID, seconds)
+}
+
+// SetSlowModeCalls gets all the calls that were made to SetSlowMode.
+// Check the length with:
+//
+//	len(mockedStore.SetSlowModeCalls())
+func (mock *StoreMock) SetSlowModeCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+	Seconds        int
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		Seconds        int
+	}
+	mock.lockSetSlowMode.RLock()
+	calls = mock.calls.SetSlowMode
+	mock.lockSetSlowMode.RUnlock()
+	return calls
+}
+
+// SetTimePollAvailability calls SetTimePollAvailabilityFunc.
+func (mock *StoreMock) SetTimePollAvailability(ctx context.Context, eventID int64, userID int64, slotIDs []int64) (*TimePoll, error) {
+	if mock.SetTimePollAvailabilityFunc == nil {
+		panic("StoreMock.SetTimePollAvailabilityFunc: method is nil but Store.SetTimePollAvailability was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		SlotIDs []int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		UserID:  userID,
+		SlotIDs: slotIDs,
+	}
+	mock.lockSetTimePollAvailability.Lock()
+	mock.calls.SetTimePollAvailability = append(mock.calls.SetTimePollAvailability, callInfo)
+	mock.lockSetTimePollAvailability.Unlock()
+	return mock.SetTimePollAvailabilityFunc(ctx, eventID, userID, slotIDs)
+}
+
+// SetTimePollAvailabilityCalls gets all the calls that were made to SetTimePollAvailability.
+// Check the length with:
+//
+//	len(mockedStore.SetTimePollAvailabilityCalls())
+func (mock *StoreMock) SetTimePollAvailabilityCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	UserID  int64
+	SlotIDs []int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		UserID  int64
+		SlotIDs []int64
+	}
+	mock.lockSetTimePollAvailability.RLock()
+	calls = mock.calls.SetTimePollAvailability
+	mock.lockSetTimePollAvailability.RUnlock()
+	return calls
+}
+
+// SetUserInterests calls SetUserInterestsFunc.
+func (mock *StoreMock) SetUserInterests(ctx context.Context, userID int64, names []string) error {
+	if mock.SetUserInterestsFunc == nil {
+		panic("StoreMock.SetUserInterestsFunc: method is nil but Store.SetUserInterests was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Names  []string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Names:  names,
+	}
+	mock.lockSetUserInterests.Lock()
+	mock.calls.SetUserInterests = append(mock.calls.SetUserInterests, callInfo)
+	mock.lockSetUserInterests.Unlock()
+	return mock.SetUserInterestsFunc(ctx, userID, names)
+}
+
+// SetUserInterestsCalls gets all the calls that were made to SetUserInterests.
+// Check the length with:
+//
+//	len(mockedStore.SetUserInterestsCalls())
+func (mock *StoreMock) SetUserInterestsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Names  []string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Names  []string
+	}
+	mock.lockSetUserInterests.RLock()
+	calls = mock.calls.SetUserInterests
+	mock.lockSetUserInterests.RUnlock()
+	return calls
+}
+
+// SetUserPhone calls SetUserPhoneFunc.
+func (mock *StoreMock) SetUserPhone(ctx context.Context, userID int64, phone string) error {
+	if mock.SetUserPhoneFunc == nil {
+		panic("StoreMock.SetUserPhoneFunc: method is nil but Store.SetUserPhone was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Phone  string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Phone:  phone,
+	}
+	mock.lockSetUserPhone.Lock()
+	mock.calls.SetUserPhone = append(mock.calls.SetUserPhone, callInfo)
+	mock.lockSetUserPhone.Unlock()
+	return mock.SetUserPhoneFunc(ctx, userID, phone)
+}
+
+// SetUserPhoneCalls gets all the calls that were made to SetUserPhone.
+// Check the length with:
+//
+//	len(mockedStore.SetUserPhoneCalls())
+func (mock *StoreMock) SetUserPhoneCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Phone  string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Phone  string
+	}
+	mock.lockSetUserPhone.RLock()
+	calls = mock.calls.SetUserPhone
+	mock.lockSetUserPhone.RUnlock()
+	return calls
+}
+
+// ShadowBanUser calls ShadowBanUserFunc.
+func (mock *StoreMock) ShadowBanUser(ctx context.Context, conversationID int64, actorID int64, userID int64) error {
+	if mock.ShadowBanUserFunc == nil {
+		panic("StoreMock.ShadowBanUserFunc: method is nil but Store.ShadowBanUser was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		ActorID:        actorID,
+		UserID:         userID,
+	}
+	mock.lockShadowBanUser.Lock()
+	mock.calls.ShadowBanUser = append(mock.calls.ShadowBanUser, callInfo)
+	mock.lockShadowBanUser.Unlock()
+	return mock.ShadowBanUserFunc(ctx, conversationID, actorID, userID)
+}
+
+// ShadowBanUserCalls gets all the calls that were made to ShadowBanUser.
+// Check the length with:
+//
+//	len(mockedStore.ShadowBanUserCalls())
+func (mock *StoreMock) ShadowBanUserCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	ActorID        int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		ActorID        int64
+		UserID         int64
+	}
+	mock.lockShadowBanUser.RLock()
+	calls = mock.calls.ShadowBanUser
+	mock.lockShadowBanUser.RUnlock()
+	return calls
+}
+
+// SignInWithIdentity calls SignInWithIdentityFunc.
+func (mock *StoreMock) SignInWithIdentity(ctx context.Context, identity externalIdentity) (*User, error) {
+	if mock.SignInWithIdentityFunc == nil {
+		panic("StoreMock.SignInWithIdentityFunc: method is nil but Store.SignInWithIdentity was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Identity externalIdentity
+	}{
+		Ctx:      ctx,
+		Identity: identity,
+	}
+	mock.lockSignInWithIdentity.Lock()
+	mock.calls.SignInWithIdentity = append(mock.calls.SignInWithIdentity, callInfo)
+	mock.lockSignInWithIdentity.Unlock()
+	return mock.SignInWithIdentityFunc(ctx, identity)
+}
+
+// SignInWithIdentityCalls gets all the calls that were made to SignInWithIdentity.
+// Check the length with:
+//
+//	len(mockedStore.SignInWithIdentityCalls())
+func (mock *StoreMock) SignInWithIdentityCalls() []struct {
+	Ctx      context.Context
+	Identity externalIdentity
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Identity externalIdentity
+	}
+	mock.lockSignInWithIdentity.RLock()
+	calls = mock.calls.SignInWithIdentity
+	mock.lockSignInWithIdentity.RUnlock()
+	return calls
+}
+
+// SlowModeWait calls SlowModeWaitFunc.
+func (mock *StoreMock) SlowModeWait(ctx context.Context, conversationID int64, userID int64, now time.Time) (time.Duration, error) {
+	if mock.SlowModeWaitFunc == nil {
+		panic("StoreMock.SlowModeWaitFunc: method is nil but Store.SlowModeWait was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		Now            time.Time
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+		Now:            now,
+	}
+	mock.lockSlowModeWait.Lock()
+	mock.calls.SlowModeWait = append(mock.calls.SlowModeWait, callInfo)
+	mock.lockSlowModeWait.Unlock()
+	return mock.SlowModeWaitFunc(ctx, conversationID, userID, now)
+}
+
+// SlowModeWaitCalls gets all the calls that were made to SlowModeWait.
+// Check the length with:
+//
+//	len(mockedStore.SlowModeWaitCalls())
+func (mock *StoreMock) SlowModeWaitCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+	Now            time.Time
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+		Now            time.Time
+	}
+	mock.lockSlowModeWait.RLock()
+	calls = mock.calls.SlowModeWait
+	mock.lockSlowModeWait.RUnlock()
+	return calls
+}
+
+// SubmitVerificationRequest calls SubmitVerificationRequestFunc.
+func (mock *StoreMock) SubmitVerificationRequest(ctx context.Context, userID int64, note string) (*VerificationRequest, error) {
+	if mock.SubmitVerificationRequestFunc == nil {
+		panic("StoreMock.SubmitVerificationRequestFunc: method is nil but Store.SubmitVerificationRequest was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Note   string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Note:   note,
+	}
+	mock.lockSubmitVerificationRequest.Lock()
+	mock.calls.SubmitVerificationRequest = append(mock.calls.SubmitVerificationRequest, callInfo)
+	mock.lockSubmitVerificationRequest.Unlock()
+	return mock.SubmitVerificationRequestFunc(ctx, userID, note)
+}
+
+// SubmitVerificationRequestCalls gets all the calls that were made to SubmitVerificationRequest.
+// Check the length with:
+//
+//	len(mockedStore.SubmitVerificationRequestCalls())
+func (mock *StoreMock) SubmitVerificationRequestCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Note   string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Note   string
+	}
+	mock.lockSubmitVerificationRequest.RLock()
+	calls = mock.calls.SubmitVerificationRequest
+	mock.lockSubmitVerificationRequest.RUnlock()
+	return calls
+}
+
+// TouchSession calls TouchSessionFunc.
+func (mock *StoreMock) TouchSession(ctx context.Context, claims *sessionClaims, ip string, now time.Time) (bool, error) {
+	if mock.TouchSessionFunc == nil {
+		panic("StoreMock.TouchSessionFunc: method is nil but Store.TouchSession was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Claims *sessionClaims
+		IP     string
+		Now    time.Time
+	}{
+		Ctx:    ctx,
+		Claims: claims,
+		IP:     ip,
+		Now:    now,
+	}
+	mock.lockTouchSession.Lock()
+	mock.calls.TouchSession = append(mock.calls.TouchSession, callInfo)
+	mock.lockTouchSession.Unlock()
+	return mock.TouchSessionFunc(ctx, claims, ip, now)
+}
+
+// TouchSessionCalls gets all the calls that were made to TouchSession.
+// Check the length with:
+//
+//	len(mockedStore.TouchSessionCalls())
+func (mock *StoreMock) TouchSessionCalls() []struct {
+	Ctx    context.Context
+	Claims *sessionClaims
+	IP     string
+	Now    time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Claims *sessionClaims
+		IP     string
+		Now    time.Time
+	}
+	mock.lockTouchSession.RLock()
+	calls = mock.calls.TouchSession
+	mock.lockTouchSession.RUnlock()
+	return calls
+}
+
+// TransferEvent calls TransferEventFunc.
+func (mock *StoreMock) TransferEvent(ctx context.Context, eventID int64, hostID int64, newHostID int64) (int64, error) {
+	if mock.TransferEventFunc == nil {
+		panic("StoreMock.TransferEventFunc: method is nil but Store.TransferEvent was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		EventID   int64
+		HostID    int64
+		NewHostID int64
+	}{
+		Ctx:       ctx,
+		EventID:   eventID,
+		HostID:    hostID,
+		NewHostID: newHostID,
+	}
+	mock.lockTransferEvent.Lock()
+	mock.calls.TransferEvent = append(mock.calls.TransferEvent, callInfo)
+	mock.lockTransferEvent.Unlock()
+	return mock.TransferEventFunc(ctx, eventID, hostID, newHostID)
+}
+
+// TransferEventCalls gets all the calls that were made to TransferEvent.
+// Check the length with:
+//
+//	len(mockedStore.TransferEventCalls())
+func (mock *StoreMock) TransferEventCalls() []struct {
+	Ctx       context.Context
+	EventID   int64
+	HostID    int64
+	NewHostID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		EventID   int64
+		HostID    int64
+		NewHostID int64
+	}
+	mock.lockTransferEvent.RLock()
+	calls = mock.calls.TransferEvent
+	mock.lockTransferEvent.RUnlock()
+	return calls
+}
+
+// UnarchiveConversation calls UnarchiveConversationFunc.
+func (mock *StoreMock) UnarchiveConversation(ctx context.Context, conversationID int64, userID int64) error {
+	if mock.UnarchiveConversationFunc == nil {
+		panic("StoreMock.UnarchiveConversationFunc: method is nil but Store.UnarchiveConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	mock.lockUnarchiveConversation.Lock()
+	mock.calls.UnarchiveConversation = append(mock.calls.UnarchiveConversation, callInfo)
+	mock.lockUnarchiveConversation.Unlock()
+	return mock.UnarchiveConversationFunc(ctx, conversationID, userID)
+}
+
+// UnarchiveConversationCalls gets all the calls that were made to UnarchiveConversation.
+// Check the length with:
+//
+//	len(mockedStore.UnarchiveConversationCalls())
+func (mock *StoreMock) UnarchiveConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}
+	mock.lockUnarchiveConversation.RLock()
+	calls = mock.calls.UnarchiveConversation
+	mock.lockUnarchiveConversation.RUnlock()
+	return calls
+}
+
+// UnbanEventUser calls UnbanEventUserFunc.
+func (mock *StoreMock) UnbanEventUser(ctx context.Context, eventID int64, actorID int64, userID int64) error {
+	if mock.UnbanEventUserFunc == nil {
+		panic("StoreMock.UnbanEventUserFunc: method is nil but Store.UnbanEventUser was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		ActorID int64
+		UserID  int64
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		ActorID: actorID,
+		UserID:  userID,
+	}
+	mock.lockUnbanEventUser.Lock()
+	mock.calls.UnbanEventUser = append(mock.calls.UnbanEventUser, callInfo)
+	mock.lockUnbanEventUser.Unlock()
+	return mock.UnbanEventUserFunc(ctx, eventID, actorID, userID)
+}
+
+// UnbanEventUserCalls gets all the calls that were made to UnbanEventUser.
+// Check the length with:
+//
+//	len(mockedStore.UnbanEventUserCalls())
+func (mock *StoreMock) UnbanEventUserCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	ActorID int64
+	UserID  int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		ActorID int64
+		UserID  int64
+	}
+	mock.lockUnbanEventUser.RLock()
+	calls = mock.calls.UnbanEventUser
+	mock.lockUnbanEventUser.RUnlock()
+	return calls
+}
+
+// UnmuteConversation calls UnmuteConversationFunc.
+func (mock *StoreMock) UnmuteConversation(ctx context.Context, conversationID int64, userID int64) error {
+	if mock.UnmuteConversationFunc == nil {
+		panic("StoreMock.UnmuteConversationFunc: method is nil but Store.UnmuteConversation was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		UserID:         userID,
+	}
+	mock.lockUnmuteConversation.Lock()
+	mock.calls.UnmuteConversation = append(mock.calls.UnmuteConversation, callInfo)
+	mock.lockUnmuteConversation.Unlock()
+	return mock.UnmuteConversationFunc(ctx, conversationID, userID)
+}
+
+// UnmuteConversationCalls gets all the calls that were made to UnmuteConversation.
+// Check the length with:
+//
+//	len(mockedStore.UnmuteConversationCalls())
+func (mock *StoreMock) UnmuteConversationCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	UserID         int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		UserID         int64
+	}
+	mock.lockUnmuteConversation.RLock()
+	calls = mock.calls.UnmuteConversation
+	mock.lockUnmuteConversation.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *StoreMock) Update(ctx context.Context, id int64, userID int64, params UpdateEventParams) ([]EventFieldChange, error) {
+	if mock.UpdateFunc == nil {
+		panic("StoreMock.UpdateFunc: method is nil but Store.Update was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     int64
+		UserID int64
+		Params UpdateEventParams
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		UserID: userID,
+		Params: params,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, id, userID, params)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedStore.UpdateCalls())
+func (mock *StoreMock) UpdateCalls() []struct {
+	Ctx    context.Context
+	ID     int64
+	UserID int64
+	Params UpdateEventParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     int64
+		UserID int64
+		Params UpdateEventParams
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
+
+// UpdateEventSettings calls UpdateEventSettingsFunc.
+func (mock *StoreMock) UpdateEventSettings(ctx context.Context, eventID int64, hostID int64, params UpdateEventSettingsParams) (*EventSettings, error) {
+	if mock.UpdateEventSettingsFunc == nil {
+		panic("StoreMock.UpdateEventSettingsFunc: method is nil but Store.UpdateEventSettings was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Params  UpdateEventSettingsParams
+	}{
+		Ctx:     ctx,
+		EventID: eventID,
+		HostID:  hostID,
+		Params:  params,
+	}
+	mock.lockUpdateEventSettings.Lock()
+	mock.calls.UpdateEventSettings = append(mock.calls.UpdateEventSettings, callInfo)
+	mock.lockUpdateEventSettings.Unlock()
+	return mock.UpdateEventSettingsFunc(ctx, eventID, hostID, params)
+}
+
+// UpdateEventSettingsCalls gets all the calls that were made to UpdateEventSettings.
+// Check the length with:
+//
+//	len(mockedStore.UpdateEventSettingsCalls())
+func (mock *StoreMock) UpdateEventSettingsCalls() []struct {
+	Ctx     context.Context
+	EventID int64
+	HostID  int64
+	Params  UpdateEventSettingsParams
+} {
+	var calls []struct {
+		Ctx     context.Context
+		EventID int64
+		HostID  int64
+		Params  UpdateEventSettingsParams
+	}
+	mock.lockUpdateEventSettings.RLock()
+	calls = mock.calls.UpdateEventSettings
+	mock.lockUpdateEventSettings.RUnlock()
+	return calls
+}
+
+// UpdateReadState calls UpdateReadStateFunc.
+func (mock *StoreMock) UpdateReadState(ctx context.Context, conversationID int64, userID int64, lastReadMessageID int64) error {
+	if mock.UpdateReadStateFunc == nil {
+		panic("StoreMock.UpdateReadStateFunc: method is nil but Store.UpdateReadState was just called")
+	}
+	callInfo := struct {
+		Ctx               context.Context
+		ConversationID    int64
+		UserID            int64
+		LastReadMessageID int64
+	}{
+		Ctx:               ctx,
+		ConversationID:    conversationID,
+		UserID:            userID,
+		LastReadMessageID: lastReadMessageID,
+	}
+	mock.lockUpdateReadState.Lock()
+	mock.calls.UpdateReadState = append(mock.calls.UpdateReadState, callInfo)
+	mock.lockUpdateReadState.Unlock()
+	return mock.UpdateReadStateFunc(ctx, conversationID, userID, lastReadMessageID)
+}
+
+// UpdateReadStateCalls gets all the calls that were made to UpdateReadState.
+// Check the length with:
+//
+//	len(mockedStore.UpdateReadStateCalls())
+func (mock *StoreMock) UpdateReadStateCalls() []struct {
+	Ctx               context.Context
+	ConversationID    int64
+	UserID            int64
+	LastReadMessageID int64
+} {
+	var calls []struct {
+		Ctx               context.Context
+		ConversationID    int64
+		UserID            int64
+		LastReadMessageID int64
+	}
+	mock.lockUpdateReadState.RLock()
+	calls = mock.calls.UpdateReadState
+	mock.lockUpdateReadState.RUnlock()
+	return calls
+}
+
+// UpdateUserProfile calls UpdateUserProfileFunc.
+func (mock *StoreMock) UpdateUserProfile(ctx context.Context, userID int64, params UpdateProfileParams) error {
+	if mock.UpdateUserProfileFunc == nil {
+		panic("StoreMock.UpdateUserProfileFunc: method is nil but Store.UpdateUserProfile was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Params UpdateProfileParams
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Params: params,
+	}
+	mock.lockUpdateUserProfile.Lock()
+	mock.calls.UpdateUserProfile = append(mock.calls.UpdateUserProfile, callInfo)
+	mock.lockUpdateUserProfile.Unlock()
+	return mock.UpdateUserProfileFunc(ctx, userID, params)
+}
+
+// UpdateUserProfileCalls gets all the calls that were made to UpdateUserProfile.
+// Check the length with:
+//
+//	len(mockedStore.UpdateUserProfileCalls())
+func (mock *StoreMock) UpdateUserProfileCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Params UpdateProfileParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Params UpdateProfileParams
+	}
+	mock.lockUpdateUserProfile.RLock()
+	calls = mock.calls.UpdateUserProfile
+	mock.lockUpdateUserProfile.RUnlock()
+	return calls
+}
+
+// UserRoles calls UserRolesFunc.
+func (mock *StoreMock) UserRoles(ctx context.Context, userID int64) ([]string, error) {
+	if mock.UserRolesFunc == nil {
+		panic("StoreMock.UserRolesFunc: method is nil but Store.UserRoles was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockUserRoles.Lock()
+	mock.calls.UserRoles = append(mock.calls.UserRoles, callInfo)
+	mock.lockUserRoles.Unlock()
+	return mock.UserRolesFunc(ctx, userID)
+}
+
+// UserRolesCalls gets all the calls that were made to UserRoles.
+// Check the length with:
+//
+//	len(mockedStore.UserRolesCalls())
+func (mock *StoreMock) UserRolesCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockUserRoles.RLock()
+	calls = mock.calls.UserRoles
+	mock.lockUserRoles.RUnlock()
+	return calls
+}
+
+// VerifyPhoneOTP calls VerifyPhoneOTPFunc.
+func (mock *StoreMock) VerifyPhoneOTP(ctx context.Context, phone string, code string, now time.Time) error {
+	if mock.VerifyPhoneOTPFunc == nil {
+		panic("StoreMock.VerifyPhoneOTPFunc: method is nil but Store.VerifyPhoneOTP was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Phone string
+		Code  string
+		Now   time.Time
+	}{
+		Ctx:   ctx,
+		Phone: phone,
+		Code:  code,
+		Now:   now,
+	}
+	mock.lockVerifyPhoneOTP.Lock()
+	mock.calls.VerifyPhoneOTP = append(mock.calls.VerifyPhoneOTP, callInfo)
+	mock.lockVerifyPhoneOTP.Unlock()
+	return mock.VerifyPhoneOTPFunc(ctx, phone, code, now)
+}
+
+// VerifyPhoneOTPCalls gets all the calls that were made to VerifyPhoneOTP.
+// Check the length with:
+//
+//	len(mockedStore.VerifyPhoneOTPCalls())
+func (mock *StoreMock) VerifyPhoneOTPCalls() []struct {
+	Ctx   context.Context
+	Phone string
+	Code  string
+	Now   time.Time
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Phone string
+		Code  string
+		Now   time.Time
+	}
+	mock.lockVerifyPhoneOTP.RLock()
+	calls = mock.calls.VerifyPhoneOTP
+	mock.lockVerifyPhoneOTP.RUnlock()
+	return calls
+}
+
+// VotePoll calls VotePollFunc.
+func (mock *StoreMock) VotePoll(ctx context.Context, conversationID int64, pollID int64, userID int64, optionIDs []int64) (*Poll, error) {
+	if mock.VotePollFunc == nil {
+		panic("StoreMock.VotePollFunc: method is nil but Store.VotePoll was just called")
+	}
+	callInfo := struct {
+		Ctx            context.Context
+		ConversationID int64
+		PollID         int64
+		UserID         int64
+		OptionIDs      []int64
+	}{
+		Ctx:            ctx,
+		ConversationID: conversationID,
+		PollID:         pollID,
+		UserID:         userID,
+		OptionIDs:      optionIDs,
+	}
+	mock.lockVotePoll.Lock()
+	mock.calls.VotePoll = append(mock.calls.VotePoll, callInfo)
+	mock.lockVotePoll.Unlock()
+	return mock.VotePollFunc(ctx, conversationID, pollID, userID, optionIDs)
+}
+
+// VotePollCalls gets all the calls that were made to VotePoll.
+// Check the length with:
+//
+//	len(mockedStore.VotePollCalls())
+func (mock *StoreMock) VotePollCalls() []struct {
+	Ctx            context.Context
+	ConversationID int64
+	PollID         int64
+	UserID         int64
+	OptionIDs      []int64
+} {
+	var calls []struct {
+		Ctx            context.Context
+		ConversationID int64
+		PollID         int64
+		UserID         int64
+		OptionIDs      []int64
+	}
+	mock.lockVotePoll.RLock()
+	calls = mock.calls.VotePoll
+	mock.lockVotePoll.RUnlock()
+	return calls
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownTag = errors.New("unknown tag")
+
+// defaultTags seeds the tag picker catalog on startup.
+var defaultTags = []string{
+	"Sports",
+	"Music",
+	"Food",
+	"Outdoors",
+	"Arts",
+	"Games",
+	"Nightlife",
+	"Learning",
+	"Wellness",
+	"Social",
+}
+
+const createTableTags = `
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE
+);
+`
+
+const createTableEventTags = `
+CREATE TABLE IF NOT EXISTS event_tags (
+    event_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (event_id, tag_id),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+`
+
+const createEventTagsTagIndex = `
+CREATE INDEX IF NOT EXISTS event_tags_tag_idx
+ON event_tags (tag_id);
+`
+
+const insertTag = `
+INSERT OR IGNORE INTO tags (name)
+VALUES (?);
+`
+
+const selectTags = `
+SELECT id, name
+FROM tags
+ORDER BY name ASC;
+`
+
+const selectTagIDByName = `
+SELECT id
+FROM tags
+WHERE name = ?;
+`
+
+const deleteEventTags = `
+DELETE FROM event_tags
+WHERE event_id = ?;
+`
+
+const insertEventTag = `
+INSERT OR IGNORE INTO event_tags (event_id, tag_id)
+VALUES (?, ?);
+`
+
+// selectTagsForEvents is completed with an IN (...) placeholder list.
+const selectTagsForEvents = `
+SELECT et.event_id, t.name
+FROM event_tags et
+JOIN tags t ON t.id = et.tag_id
+WHERE et.event_id IN (%s)
+ORDER BY t.name ASC;
+`
+
+// eventTagFilter restricts an event query to events carrying any of the tags.
+const eventTagFilter = `
+ AND e.id IN (
+    SELECT et.event_id
+    FROM event_tags et
+    JOIN tags t ON t.id = et.tag_id
+    WHERE t.name IN (%s)
+)`
+
+// initTags creates the tag tables and makes sure the default catalog exists.
+func (r *EventRepository) initTags(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableTags); err != nil {
+		return fmt.Errorf("create tags table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableEventTags); err != nil {
+		return fmt.Errorf("create event tags table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createEventTagsTagIndex); err != nil {
+		return fmt.Errorf("create event tags index: %w", err)
+	}
+	for _, name := range defaultTags {
+		if _, err := r.db.ExecContext(ctx, insertTag, name); err != nil {
+			return fmt.Errorf("seed tag %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ListTags returns the tag catalog for the client's picker.
+func (r *EventRepository) ListTags(ctx context.Context) ([]Tag, error) {
+	rows, err := r.db.QueryContext(ctx, selectTags)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tags: %w", err)
+	}
+	return tags, nil
+}
+
+// setEventTags replaces an event's tags inside the caller's transaction.
+// Names must already exist in the catalog (matched case-insensitively).
+func setEventTags(ctx context.Context, tx *sql.Tx, eventID int64, names []string) error {
+	if _, err := tx.ExecContext(ctx, deleteEventTags, eventID); err != nil {
+		return fmt.Errorf("clear event tags: %w", err)
+	}
+	for _, name := range names {
+		var tagID int64
+		if err := tx.QueryRowContext(ctx, selectTagIDByName, strings.TrimSpace(name)).Scan(&tagID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrUnknownTag, name)
+			}
+			return fmt.Errorf("lookup tag: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertEventTag, eventID, tagID); err != nil {
+			return fmt.Errorf("insert event tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// attachEventTags loads tags for a page of events in one query.
+func (r *EventRepository) attachEventTags(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]int, len(events))
+	args := make([]any, 0, len(events))
+	for i := range events {
+		index[events[i].ID] = i
+		events[i].Tags = []string{}
+		args = append(args, events[i].ID)
+	}
+
+	query := fmt.Sprintf(selectTagsForEvents, placeholders(len(args)))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query event tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventID int64
+		var name string
+		if err := rows.Scan(&eventID, &name); err != nil {
+			return fmt.Errorf("scan event tag: %w", err)
+		}
+		if i, ok := index[eventID]; ok {
+			events[i].Tags = append(events[i].Tags, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate event tags: %w", err)
+	}
+	return nil
+}
+
+// placeholders returns "?, ?, ..." for an IN clause of n values.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
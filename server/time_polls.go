@@ -0,0 +1,707 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	minTimePollSlots = 2
+	maxTimePollSlots = 10
+)
+
+var ErrTimePollNotFound = errors.New("time poll not found")
+var ErrTimePollExists = errors.New("event already has an open time poll")
+var ErrTimePollClosed = errors.New("time poll is already confirmed")
+var ErrInvalidTimeSlot = errors.New("invalid time slot")
+
+// An event has at most one time poll. Once the host confirms a slot the poll
+// stays readable but takes no more answers, and a new one replaces it.
+const createTableEventTimePolls = `
+CREATE TABLE IF NOT EXISTS event_time_polls (
+    event_id INTEGER PRIMARY KEY,
+    created_by INTEGER NOT NULL,
+    confirmed_slot_id INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+`
+
+const createTableEventTimeSlots = `
+CREATE TABLE IF NOT EXISTS event_time_slots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL,
+    starts_at DATETIME NOT NULL,
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+`
+
+const createTableEventTimeSlotVotes = `
+CREATE TABLE IF NOT EXISTS event_time_slot_votes (
+    slot_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (slot_id, user_id),
+    FOREIGN KEY (slot_id) REFERENCES event_time_slots(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const selectEventTimePoll = `
+SELECT event_id, created_by, confirmed_slot_id, created_at
+FROM event_time_polls
+WHERE event_id = ?;
+`
+
+const selectEventTimeSlotResults = `
+SELECT s.id, s.starts_at, v.user_id
+FROM event_time_slots s
+LEFT JOIN event_time_slot_votes v ON v.slot_id = s.id
+WHERE s.event_id = ?
+ORDER BY s.starts_at, s.id, v.created_at, v.user_id;
+`
+
+const insertEventTimePoll = `
+INSERT INTO event_time_polls (event_id, created_by)
+VALUES (?, ?);
+`
+
+const insertEventTimeSlot = `
+INSERT INTO event_time_slots (event_id, starts_at)
+VALUES (?, ?);
+`
+
+const selectEventTimeSlotStart = `
+SELECT starts_at FROM event_time_slots WHERE id = ? AND event_id = ?;
+`
+
+const selectEventTimeSlotIDs = `
+SELECT id FROM event_time_slots WHERE event_id = ?;
+`
+
+const deleteEventTimeSlotVotesForUser = `
+DELETE FROM event_time_slot_votes WHERE event_id = ? AND user_id = ?;
+`
+
+const insertEventTimeSlotVote = `
+INSERT INTO event_time_slot_votes (slot_id, event_id, user_id)
+VALUES (?, ?, ?);
+`
+
+const confirmEventTimePoll = `
+UPDATE event_time_polls SET confirmed_slot_id = ? WHERE event_id = ?;
+`
+
+const moveEventStart = `
+UPDATE events
+SET time = ?, date_label = ?, starts_at = ?
+WHERE id = ?;
+`
+
+// deleteEventRemindersSent lets the reminders go out again for a new start.
+const deleteEventRemindersSent = `
+DELETE FROM event_reminders_sent WHERE event_id = ?;
+`
+
+// deleteEventTimePollStatements clear an event's poll, slots and answers.
+var deleteEventTimePollStatements = []string{
+	`DELETE FROM event_time_slot_votes WHERE event_id = ?`,
+	`DELETE FROM event_time_slots WHERE event_id = ?`,
+	`DELETE FROM event_time_polls WHERE event_id = ?`,
+}
+
+const deleteEventTimeSlotVotesForAccount = `
+DELETE FROM event_time_slot_votes WHERE user_id = ?;
+`
+
+func (r *EventRepository) initTimePolls(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableEventTimePolls); err != nil {
+		return fmt.Errorf("create event time polls table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableEventTimeSlots); err != nil {
+		return fmt.Errorf("create event time slots table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableEventTimeSlotVotes); err != nil {
+		return fmt.Errorf("create event time slot votes table: %w", err)
+	}
+	return nil
+}
+
+// eventDateLabel is the date_label for an event starting at start, seen from
+// now. Events only run today or tomorrow, so any other start reports false.
+func eventDateLabel(now, start time.Time) (string, bool) {
+	today := now.In(time.Local)
+	day := start.In(time.Local)
+	switch {
+	case !start.After(now):
+		return "", false
+	case day.Year() == today.Year() && day.YearDay() == today.YearDay():
+		return "Today", true
+	}
+	tomorrow := today.AddDate(0, 0, 1)
+	if day.Year() == tomorrow.Year() && day.YearDay() == tomorrow.YearDay() {
+		return "Tmrw", true
+	}
+	return "", false
+}
+
+// requireEventHost loads the event and checks that userID hosts it.
+func requireEventHost(ctx context.Context, q rowQuery, eventID, userID int64) error {
+	var hostID int64
+	if err := q.QueryRowContext(ctx, `SELECT user_id FROM events WHERE id = ?`, eventID).Scan(&hostID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEventNotFound
+		}
+		return fmt.Errorf("fetch event host: %w", err)
+	}
+	if hostID != userID {
+		return ErrNotEventHost
+	}
+	return nil
+}
+
+// CreateTimePoll proposes start times for the event. Only the host may, and
+// only while no unconfirmed poll is open; a confirmed one is replaced.
+func (r *EventRepository) CreateTimePoll(ctx context.Context, eventID, hostID int64, starts []time.Time) (*TimePoll, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin time poll tx: %w", err)
+	}
+	if err := requireEventHost(ctx, tx, eventID, hostID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var confirmed sql.NullInt64
+	err = tx.QueryRowContext(ctx, `SELECT confirmed_slot_id FROM event_time_polls WHERE event_id = ?`, eventID).Scan(&confirmed)
+	switch {
+	case err == nil && !confirmed.Valid:
+		tx.Rollback()
+		return nil, ErrTimePollExists
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		tx.Rollback()
+		return nil, fmt.Errorf("fetch time poll: %w", err)
+	}
+	for _, stmt := range deleteEventTimePollStatements {
+		if _, err := tx.ExecContext(ctx, stmt, eventID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("clear previous time poll: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, insertEventTimePoll, eventID, hostID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert time poll: %w", err)
+	}
+	for _, start := range starts {
+		if _, err := tx.ExecContext(ctx, insertEventTimeSlot, eventID, sqliteTime(start)); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert time slot: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit time poll: %w", err)
+	}
+	return r.GetTimePoll(ctx, eventID)
+}
+
+// GetTimePoll returns the event's time poll with who is free for each slot.
+func (r *EventRepository) GetTimePoll(ctx context.Context, eventID int64) (*TimePoll, error) {
+	var poll TimePoll
+	var confirmed sql.NullInt64
+	err := r.db.QueryRowContext(ctx, selectEventTimePoll, eventID).Scan(&poll.EventID, &poll.CreatedBy, &confirmed, &poll.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTimePollNotFound
+		}
+		return nil, fmt.Errorf("fetch time poll: %w", err)
+	}
+	if confirmed.Valid {
+		value := confirmed.Int64
+		poll.ConfirmedSlotID = &value
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectEventTimeSlotResults, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("query time slots: %w", err)
+	}
+	defer rows.Close()
+
+	poll.Slots = []TimeSlot{}
+	for rows.Next() {
+		var slotID int64
+		var startsAt time.Time
+		var userID sql.NullInt64
+		if err := rows.Scan(&slotID, &startsAt, &userID); err != nil {
+			return nil, fmt.Errorf("scan time slot: %w", err)
+		}
+		if n := len(poll.Slots); n == 0 || poll.Slots[n-1].ID != slotID {
+			poll.Slots = append(poll.Slots, TimeSlot{ID: slotID, StartsAt: startsAt, AvailableUserIDs: []int64{}})
+		}
+		if userID.Valid {
+			slot := &poll.Slots[len(poll.Slots)-1]
+			slot.AvailableUserIDs = append(slot.AvailableUserIDs, userID.Int64)
+			slot.Available++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate time slots: %w", err)
+	}
+	return &poll, nil
+}
+
+// SetTimePollAvailability replaces the slots the user says they are free
+// for; an empty list clears their answer.
+func (r *EventRepository) SetTimePollAvailability(ctx context.Context, eventID, userID int64, slotIDs []int64) (*TimePoll, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin availability tx: %w", err)
+	}
+
+	var confirmed sql.NullInt64
+	if err := tx.QueryRowContext(ctx, `SELECT confirmed_slot_id FROM event_time_polls WHERE event_id = ?`, eventID).Scan(&confirmed); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTimePollNotFound
+		}
+		return nil, fmt.Errorf("fetch time poll: %w", err)
+	}
+	if confirmed.Valid {
+		tx.Rollback()
+		return nil, ErrTimePollClosed
+	}
+
+	rows, err := tx.QueryContext(ctx, selectEventTimeSlotIDs, eventID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("list time slots: %w", err)
+	}
+	valid := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, fmt.Errorf("scan time slot: %w", err)
+		}
+		valid[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("iterate time slots: %w", err)
+	}
+	rows.Close()
+
+	chosen := make(map[int64]bool, len(slotIDs))
+	for _, id := range slotIDs {
+		if !valid[id] {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: slot %d is not part of this poll", ErrInvalidTimeSlot, id)
+		}
+		chosen[id] = true
+	}
+	if _, err := tx.ExecContext(ctx, deleteEventTimeSlotVotesForUser, eventID, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("clear availability: %w", err)
+	}
+	for id := range chosen {
+		if _, err := tx.ExecContext(ctx, insertEventTimeSlotVote, id, eventID, userID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("insert availability: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit availability: %w", err)
+	}
+	return r.GetTimePoll(ctx, eventID)
+}
+
+// ConfirmTimeSlot closes the poll on slotID and moves the event to start
+// then. The slot must still be in the future, today or tomorrow.
+func (r *EventRepository) ConfirmTimeSlot(ctx context.Context, eventID, hostID, slotID int64, now time.Time) (*TimePoll, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin confirm slot tx: %w", err)
+	}
+	if err := requireEventHost(ctx, tx, eventID, hostID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var confirmed sql.NullInt64
+	if err := tx.QueryRowContext(ctx, `SELECT confirmed_slot_id FROM event_time_polls WHERE event_id = ?`, eventID).Scan(&confirmed); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTimePollNotFound
+		}
+		return nil, fmt.Errorf("fetch time poll: %w", err)
+	}
+	if confirmed.Valid {
+		tx.Rollback()
+		return nil, ErrTimePollClosed
+	}
+
+	var start time.Time
+	if err := tx.QueryRowContext(ctx, selectEventTimeSlotStart, slotID, eventID).Scan(&start); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: slot %d is not part of this poll", ErrInvalidTimeSlot, slotID)
+		}
+		return nil, fmt.Errorf("fetch time slot: %w", err)
+	}
+	label, ok := eventDateLabel(now, start)
+	if !ok {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: the slot is no longer today or tomorrow", ErrInvalidTimeSlot)
+	}
+
+	if _, err := tx.ExecContext(ctx, moveEventStart, start.In(time.Local).Format("15:04"), label, sqliteTime(start), eventID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("update event start: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteEventRemindersSent, eventID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("reset event reminders: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, confirmEventTimePoll, slotID, eventID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("confirm time slot: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit confirm slot: %w", err)
+	}
+	return r.GetTimePoll(ctx, eventID)
+}
+
+// DeleteTimePoll drops the event's poll and every answer to it.
+func (r *EventRepository) DeleteTimePoll(ctx context.Context, eventID, hostID int64) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin delete time poll tx: %w", err)
+	}
+	if err := requireEventHost(ctx, tx, eventID, hostID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, stmt := range deleteEventTimePollStatements {
+		if _, err := tx.ExecContext(ctx, stmt, eventID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("delete time poll: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete time poll: %w", err)
+	}
+	return nil
+}
+
+// timePollUpdateEvent carries the time poll to the event chat after anyone
+// answers it or the host confirms a slot.
+type timePollUpdateEvent struct {
+	Type           string   `json:"type"`
+	ConversationID int64    `json:"conversationId"`
+	EventID        int64    `json:"eventId"`
+	Poll           TimePoll `json:"poll"`
+}
+
+// BroadcastTimePollUpdate sends `time_poll:update` to the event chat.
+func (h *ChatHub) BroadcastTimePollUpdate(conversationID int64, poll TimePoll) {
+	payload, err := json.Marshal(timePollUpdateEvent{Type: "time_poll:update", ConversationID: conversationID, EventID: poll.EventID, Poll: poll})
+	if err != nil {
+		log.Printf("marshal time poll update failed: %v", err)
+		return
+	}
+	h.broadcast <- chatBroadcast{conversationID: conversationID, payload: payload}
+}
+
+// createTimePoll lets the host propose 2-10 start times, each later today or
+// tomorrow, for the event chat to say when they are free. A confirmed poll is
+// replaced; an open one has to be confirmed or deleted first.
+//
+// Responses:
+//  - 201 with `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or event id, too few or too many slots, repeated
+//    slots, or slots that aren't later today or tomorrow
+//  - 403 if the caller isn't the host
+//  - 404 if the event doesn't exist
+//  - 409 if an unconfirmed poll is open
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) createTimePoll(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload CreateTimePollParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(payload.Slots) < minTimePollSlots || len(payload.Slots) > maxTimePollSlots {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a time poll needs %d-%d slots", minTimePollSlots, maxTimePollSlots)})
+		return
+	}
+	now := time.Now()
+	seen := make(map[int64]bool, len(payload.Slots))
+	starts := make([]time.Time, 0, len(payload.Slots))
+	for _, start := range payload.Slots {
+		start = start.Truncate(time.Minute)
+		if _, ok := eventDateLabel(now, start); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "slots must be later today or tomorrow"})
+			return
+		}
+		if seen[start.Unix()] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "slots must be different"})
+			return
+		}
+		seen[start.Unix()] = true
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	poll, err := h.repo.CreateTimePoll(ctx, eventID, claims.UserID, starts)
+	if err != nil {
+		respondTimePollError(c, err)
+		return
+	}
+
+	if convo, err := h.repo.GetConversationByEventID(ctx, eventID); err == nil {
+		h.hub.Announce(ctx, convo.ID, claims.UserID, "%s asked when everyone is free", claims.UserID)
+		h.hub.BroadcastTimePollUpdate(convo.ID, *poll)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"poll": poll})
+}
+
+// getTimePoll returns the event's time poll and who is free for each slot.
+//
+// Responses:
+//  - 200 with `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid event id
+//  - 403 if the caller isn't in the event chat
+//  - 404 if the event or its poll doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) getTimePoll(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if _, ok := h.requireEventChatMember(c, ctx, eventID, claims.UserID); !ok {
+		return
+	}
+
+	poll, err := h.repo.GetTimePoll(ctx, eventID)
+	if err != nil {
+		respondTimePollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// setTimePollAvailability replaces the slots the caller is free for with
+// `slot_ids`; an empty list clears their answer.
+//
+// Responses:
+//  - 200 with the updated `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON, event id, or slots not in this poll
+//  - 403 if the caller isn't in the event chat
+//  - 404 if the event or its poll doesn't exist
+//  - 409 once the host has confirmed a slot
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) setTimePollAvailability(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload TimePollAvailabilityParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	convo, ok := h.requireEventChatMember(c, ctx, eventID, claims.UserID)
+	if !ok {
+		return
+	}
+
+	poll, err := h.repo.SetTimePollAvailability(ctx, eventID, claims.UserID, payload.SlotIDs)
+	if err != nil {
+		respondTimePollError(c, err)
+		return
+	}
+
+	h.hub.BroadcastTimePollUpdate(convo.ID, *poll)
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// confirmTimeSlot closes the poll on `slot_id` and moves the event's start
+// to it; the chat gets a system message and event reminders are re-armed.
+//
+// Responses:
+//  - 200 with the confirmed `poll`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON, event id, a slot not in this poll, or one that
+//    has passed
+//  - 403 if the caller isn't the host
+//  - 404 if the event or its poll doesn't exist
+//  - 409 if a slot was already confirmed
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) confirmTimeSlot(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var payload ConfirmTimeSlotParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	poll, err := h.repo.ConfirmTimeSlot(ctx, eventID, claims.UserID, payload.SlotID, time.Now())
+	if err != nil {
+		respondTimePollError(c, err)
+		return
+	}
+
+	if convo, err := h.repo.GetConversationByEventID(ctx, eventID); err == nil {
+		for _, slot := range poll.Slots {
+			if slot.ID == payload.SlotID {
+				label, _ := eventDateLabel(time.Now(), slot.StartsAt)
+				h.hub.Announce(ctx, convo.ID, claims.UserID, fmt.Sprintf("%%s set the time: %s at %s", label, slot.StartsAt.In(time.Local).Format("15:04")), claims.UserID)
+			}
+		}
+		h.hub.BroadcastTimePollUpdate(convo.ID, *poll)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// deleteTimePoll drops the event's time poll and every answer to it.
+//
+// Responses:
+//  - 204 on success (also when there was no poll)
+//  - 401 if the caller has no session
+//  - 400 for invalid event id
+//  - 403 if the caller isn't the host
+//  - 404 if the event doesn't exist
+//  - 500 for repository/database failures
+func (h *ChatHTTPHandler) deleteTimePoll(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.DeleteTimePoll(ctx, eventID, claims.UserID); err != nil {
+		respondTimePollError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireEventChatMember loads the event's chat and checks the user is in
+// it, answering the request itself when not.
+func (h *ChatHTTPHandler) requireEventChatMember(c *gin.Context, ctx context.Context, eventID, userID int64) (*Conversation, bool) {
+	convo, err := h.repo.GetConversationByEventID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load event chat"})
+		return nil, false
+	}
+	if !h.requireMember(c, ctx, convo.ID, userID) {
+		return nil, false
+	}
+	return convo, true
+}
+
+func respondTimePollError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+	case errors.Is(err, ErrTimePollNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "time poll not found"})
+	case errors.Is(err, ErrNotEventHost):
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the event host can manage the time poll"})
+	case errors.Is(err, ErrTimePollExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "confirm or delete the open time poll first"})
+	case errors.Is(err, ErrTimePollClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": "a time has already been confirmed"})
+	case errors.Is(err, ErrInvalidTimeSlot):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update time poll"})
+	}
+}
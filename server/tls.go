@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	defaultTLSAddr          = ":443"
+	defaultTLSRedirectAddr  = ":80"
+	defaultAutocertCacheDir = "autocert-cache"
+)
+
+// TLSConfig selects how the server terminates TLS. With neither certificate
+// files nor autocert domains set it serves plain HTTP, as before.
+type TLSConfig struct {
+	CertFile        string   // TLS_CERT
+	KeyFile         string   // TLS_KEY
+	AutocertDomains []string // TLS_AUTOCERT_DOMAINS; Let's Encrypt certificates
+	AutocertCache   string   // TLS_AUTOCERT_CACHE; where issued certificates are kept
+	Addr            string   // TLS_ADDR; HTTPS listen address
+	RedirectAddr    string   // TLS_REDIRECT_ADDR; plain HTTP listener that redirects ("off" disables)
+}
+
+// newTLSConfigFromEnv reads the TLS_* variables. Certificate files and
+// autocert are mutually exclusive.
+func newTLSConfigFromEnv() (TLSConfig, error) {
+	config := TLSConfig{
+		CertFile:        strings.TrimSpace(os.Getenv("TLS_CERT")),
+		KeyFile:         strings.TrimSpace(os.Getenv("TLS_KEY")),
+		AutocertDomains: envList("TLS_AUTOCERT_DOMAINS"),
+		AutocertCache:   strings.TrimSpace(os.Getenv("TLS_AUTOCERT_CACHE")),
+		Addr:            strings.TrimSpace(os.Getenv("TLS_ADDR")),
+		RedirectAddr:    strings.TrimSpace(os.Getenv("TLS_REDIRECT_ADDR")),
+	}
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return TLSConfig{}, errors.New("TLS_CERT and TLS_KEY must be set together")
+	}
+	if config.CertFile != "" && len(config.AutocertDomains) > 0 {
+		return TLSConfig{}, errors.New("set either TLS_CERT/TLS_KEY or TLS_AUTOCERT_DOMAINS, not both")
+	}
+	if config.AutocertCache == "" {
+		config.AutocertCache = defaultAutocertCacheDir
+	}
+	if config.Addr == "" {
+		config.Addr = defaultTLSAddr
+	}
+	if config.RedirectAddr == "" {
+		config.RedirectAddr = defaultTLSRedirectAddr
+	}
+	return config, nil
+}
+
+// enabled reports whether the server should speak HTTPS.
+func (c TLSConfig) enabled() bool {
+	return c.CertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// serveTLS serves handler over HTTPS until the listener fails, with a plain
+// HTTP listener alongside that redirects to it. In autocert mode that listener
+// also answers Let's Encrypt's HTTP-01 challenges, so it must be reachable on
+// port 80.
+func serveTLS(config TLSConfig, httpConfig HTTPConfig, handler http.Handler) error {
+	server := newHTTPServer(config.Addr, httpConfig, handler)
+
+	redirect := httpsRedirectHandler(config.Addr)
+	if len(config.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(config.AutocertDomains...),
+			Cache:      autocert.DirCache(config.AutocertCache),
+		}
+		server.TLSConfig = manager.TLSConfig()
+		redirect = manager.HTTPHandler(redirect)
+	}
+
+	if config.RedirectAddr != "off" {
+		go func() {
+			redirectServer := newHTTPServer(config.RedirectAddr, httpConfig, redirect)
+			log.Printf("redirecting HTTP on %s to HTTPS", config.RedirectAddr)
+			if err := redirectServer.ListenAndServe(); err != nil {
+				log.Printf("HTTP redirect listener stopped: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("serving HTTPS on %s", config.Addr)
+	if err := server.ListenAndServeTLS(config.CertFile, config.KeyFile); err != nil {
+		return fmt.Errorf("serve https: %w", err)
+	}
+	return nil
+}
+
+// httpsRedirectHandler permanently redirects to the same URL over HTTPS,
+// keeping the port when the HTTPS listener isn't on 443.
+func httpsRedirectHandler(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// defaultTrendingInterval is how often trending scores are recomputed.
+	defaultTrendingInterval = 10 * time.Minute
+	// defaultTrendingHalfLife is how long it takes a signal to count half as
+	// much.
+	defaultTrendingHalfLife = 24 * time.Hour
+	// trendingHorizon is how many half-lives of history are scored; older
+	// signals would add under half a percent.
+	trendingHorizon = 8
+)
+
+// How much each signal adds to an event's trending score before decay. A
+// join request is the strongest sign of interest, a bookmark the weakest.
+var trendingWeights = map[string]float64{
+	"request":  3,
+	"member":   2,
+	"bookmark": 1,
+}
+
+const createIndexEventsTrending = `
+CREATE INDEX IF NOT EXISTS idx_events_trending ON events(status, score DESC);
+`
+
+// selectTrendingSignals returns each recent join request, member join and
+// bookmark on active events, with its age in seconds at the given time.
+// Hosts joining their own chats and withdrawn requests don't count.
+const selectTrendingSignals = `
+SELECT r.event_id, 'request', (julianday(?) - julianday(r.created_at)) * 86400.0
+FROM conversation_join_requests r
+JOIN events e ON e.id = r.event_id
+WHERE e.status = 'active' AND r.status != 'cancelled' AND r.created_at >= ?
+UNION ALL
+SELECT c.event_id, 'member', (julianday(?) - julianday(cm.joined_at)) * 86400.0
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+JOIN events e ON e.id = c.event_id
+WHERE e.status = 'active' AND cm.role != 'owner' AND cm.joined_at >= ?
+UNION ALL
+SELECT b.event_id, 'bookmark', (julianday(?) - julianday(b.created_at)) * 86400.0
+FROM event_bookmarks b
+JOIN events e ON e.id = b.event_id
+WHERE e.status = 'active' AND b.created_at >= ?;
+`
+
+const clearEventScores = `
+UPDATE events SET score = 0 WHERE score != 0;
+`
+
+const updateEventScore = `
+UPDATE events SET score = ? WHERE id = ?;
+`
+
+func (r *EventRepository) initEventScores(ctx context.Context) error {
+	if err := r.ensureColumn(ctx, "events", "score", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexEventsTrending); err != nil {
+		return fmt.Errorf("create events trending index: %w", err)
+	}
+	return nil
+}
+
+// RecomputeEventScores rewrites every event's trending score as of now: the
+// sum of its recent signals, each weighted and halved every halfLife.
+// Events that aren't active score 0. It returns how many events scored.
+func (r *EventRepository) RecomputeEventScores(ctx context.Context, now time.Time, halfLife time.Duration) (int, error) {
+	at := sqliteTime(now)
+	since := sqliteTime(now.Add(-trendingHorizon * halfLife))
+	rows, err := r.db.QueryContext(ctx, selectTrendingSignals, at, since, at, since, at, since)
+	if err != nil {
+		return 0, fmt.Errorf("query trending signals: %w", err)
+	}
+	scores := make(map[int64]float64)
+	for rows.Next() {
+		var eventID int64
+		var kind string
+		var age *float64
+		if err := rows.Scan(&eventID, &kind, &age); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan trending signal: %w", err)
+		}
+		if age == nil {
+			continue
+		}
+		scores[eventID] += trendingWeights[kind] * math.Pow(0.5, max(*age, 0)/halfLife.Seconds())
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate trending signals: %w", err)
+	}
+	rows.Close()
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin trending tx: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, clearEventScores); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("clear event scores: %w", err)
+	}
+	for eventID, score := range scores {
+		if _, err := tx.ExecContext(ctx, updateEventScore, score, eventID); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("update event score: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit event scores: %w", err)
+	}
+	return len(scores), nil
+}
+
+// TrendingJob periodically recomputes the scores `?sort=trending` orders by,
+// so the feed query stays a plain ORDER BY.
+type TrendingJob struct {
+	repo     *EventRepository
+	interval time.Duration
+	halfLife time.Duration
+}
+
+// newTrendingJob schedules the recompute from config's EVENT_TRENDING_INTERVAL
+// and EVENT_TRENDING_HALF_LIFE.
+func newTrendingJob(repo *EventRepository, config JobsConfig) *TrendingJob {
+	return &TrendingJob{repo: repo, interval: config.TrendingInterval, halfLife: config.TrendingHalfLife}
+}
+
+// Register schedules the recompute on runner every interval.
+func (j *TrendingJob) Register(runner *JobRunner) {
+	runner.Register("event_trending", j.interval, j.recompute)
+}
+
+func (j *TrendingJob) recompute(ctx context.Context) error {
+	recomputeCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	if _, err := j.repo.RecomputeEventScores(recomputeCtx, time.Now(), j.halfLife); err != nil {
+		return fmt.Errorf("recompute event scores: %w", err)
+	}
+	return nil
+}
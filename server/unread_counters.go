@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// unread_counters caches countUnreadMessages per member. Triggers keep it in
+// step inside whatever transaction inserts a message or moves a read cursor;
+// a missing or implausible row is recounted on read.
+const createTableUnreadCounters = `
+CREATE TABLE IF NOT EXISTS unread_counters (
+    conversation_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    unread_count INTEGER NOT NULL,
+    PRIMARY KEY (conversation_id, user_id)
+);
+`
+
+// The read triggers delete and re-insert rather than INSERT OR REPLACE: a
+// trigger's conflict clause is overridden by the firing statement's, and
+// upsertReadState's would turn the replace into an abort.
+var unreadCounterTriggers = []string{
+	// A new message has the highest id, so it is past everyone's cursor.
+	// Hidden messages only reach their sender, whose cursor moves past them.
+	// The first version of this trigger counted them too.
+	`DROP TRIGGER IF EXISTS unread_counters_on_message;`,
+	`CREATE TRIGGER IF NOT EXISTS unread_counters_on_visible_message
+AFTER INSERT ON messages
+WHEN NEW.hidden = 0
+BEGIN
+    UPDATE unread_counters SET unread_count = unread_count + 1
+    WHERE conversation_id = NEW.conversation_id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS unread_counters_on_message_delete
+AFTER DELETE ON messages
+BEGIN
+    DELETE FROM unread_counters WHERE conversation_id = OLD.conversation_id;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS unread_counters_on_read
+AFTER INSERT ON conversation_read_state
+BEGIN
+    DELETE FROM unread_counters
+    WHERE conversation_id = NEW.conversation_id AND user_id = NEW.user_id;
+    INSERT INTO unread_counters (conversation_id, user_id, unread_count)
+    SELECT NEW.conversation_id, NEW.user_id, COUNT(1)
+    FROM messages
+    WHERE conversation_id = NEW.conversation_id AND id > NEW.last_read_message_id AND hidden = 0;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS unread_counters_on_read_advance
+AFTER UPDATE OF last_read_message_id ON conversation_read_state
+BEGIN
+    DELETE FROM unread_counters
+    WHERE conversation_id = NEW.conversation_id AND user_id = NEW.user_id;
+    INSERT INTO unread_counters (conversation_id, user_id, unread_count)
+    SELECT NEW.conversation_id, NEW.user_id, COUNT(1)
+    FROM messages
+    WHERE conversation_id = NEW.conversation_id AND id > NEW.last_read_message_id AND hidden = 0;
+END;`,
+	`CREATE TRIGGER IF NOT EXISTS unread_counters_on_read_reset
+AFTER DELETE ON conversation_read_state
+BEGIN
+    DELETE FROM unread_counters
+    WHERE conversation_id = OLD.conversation_id AND user_id = OLD.user_id;
+END;`,
+}
+
+const selectUnreadCounter = `
+SELECT unread_count
+FROM unread_counters
+WHERE conversation_id = ? AND user_id = ?;
+`
+
+// recountUnreadCounter recounts from the stored cursor and saves the result
+// in one statement, so a message or read landing meanwhile can't be lost.
+const recountUnreadCounter = `
+INSERT INTO unread_counters (conversation_id, user_id, unread_count)
+SELECT :conversation_id, :user_id, COUNT(1)
+FROM messages
+WHERE conversation_id = :conversation_id
+  AND hidden = 0
+  AND id > COALESCE((
+      SELECT last_read_message_id
+      FROM conversation_read_state
+      WHERE conversation_id = :conversation_id AND user_id = :user_id
+  ), 0)
+ON CONFLICT(conversation_id, user_id) DO UPDATE SET unread_count = excluded.unread_count
+RETURNING unread_count;
+`
+
+func (r *EventRepository) initUnreadCounters(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableUnreadCounters); err != nil {
+		return fmt.Errorf("create unread counters table: %w", err)
+	}
+	for _, trigger := range unreadCounterTriggers {
+		if _, err := r.db.ExecContext(ctx, trigger); err != nil {
+			return fmt.Errorf("create unread counter trigger: %w", err)
+		}
+	}
+	return nil
+}
+
+// cachedUnreadCount returns the maintained counter when it can be right:
+// with lastMessage past the cursor at least one message is unread, and no
+// more than the id range allows. Anything else is drift (or no row yet).
+func (r *EventRepository) cachedUnreadCount(ctx context.Context, conversationID, userID, lastReadID, lastMessageID int64) (int, bool, error) {
+	stmt, err := r.prepared(ctx, selectUnreadCounter)
+	if err != nil {
+		return 0, false, err
+	}
+	var count int
+	err = stmt.QueryRowContext(ctx, conversationID, userID).Scan(&count)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("fetch unread counter: %w", err)
+	}
+	if count < 1 || int64(count) > lastMessageID-lastReadID {
+		return 0, false, nil
+	}
+	return count, true, nil
+}
+
+// recountUnread counts the member's unread messages the slow way and resets
+// their counter to the result.
+func (r *EventRepository) recountUnread(ctx context.Context, conversationID, userID int64) (int, error) {
+	stmt, err := r.prepared(ctx, recountUnreadCounter)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if err := stmt.QueryRowContext(ctx, sql.Named("conversation_id", conversationID), sql.Named("user_id", userID)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count unread messages: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler serves account-level endpoints for the signed-in user.
+type UserHandler struct {
+	repo Store
+	hub  *ChatHub
+}
+
+func NewUserHandler(repo Store, hub *ChatHub) *UserHandler {
+	return &UserHandler{repo: repo, hub: hub}
+}
+
+func (h *UserHandler) RegisterProtectedRoutes(group *gin.RouterGroup) {
+	group.GET("/users/me", h.getProfile)
+	group.PUT("/users/me/profile", h.updateProfile)
+	group.PUT("/users/me/interests", h.updateInterests)
+	group.DELETE("/users/me", h.deleteAccount)
+	group.POST("/users/me/phone", h.setPhone)
+	group.GET("/users/me/verification", h.getVerification)
+	group.POST("/users/me/verification", h.requestVerification)
+	group.GET("/availability/me", h.getAvailability)
+	group.PUT("/availability/me", h.setAvailability)
+	group.DELETE("/availability/me", h.clearAvailability)
+	group.GET("/availability/friends", h.listAvailability)
+	group.GET("/users/me/reminders", h.getReminderSettings)
+	group.PUT("/users/me/reminders", h.setReminderSettings)
+	group.GET("/users/me/notification-settings", h.getNotificationSettings)
+	group.PUT("/users/me/notification-settings", h.setNotificationSettings)
+	group.POST("/users/me/push-tokens", h.registerPushToken)
+	group.DELETE("/users/me/push-tokens/:token", h.removePushToken)
+	group.GET("/sessions", h.listSessions)
+	group.DELETE("/sessions/:id", h.revokeSession)
+	group.GET("/connections", h.listConnections)
+	group.GET("/connections/requests", h.listConnectionRequests)
+	group.POST("/connections", h.sendConnectionRequest)
+	group.POST("/connections/:userId/accept", h.acceptConnection)
+	group.POST("/connections/:userId/decline", h.declineConnection)
+	group.DELETE("/connections/:userId", h.removeConnection)
+}
+
+// getProfile returns the caller's profile including interest tags.
+//
+// Responses:
+//  - 200 with the profile
+//  - 401 if the caller has no session
+//  - 404 if the account no longer exists
+//  - 500 for repository/database failures
+func (h *UserHandler) getProfile(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	profile, err := h.repo.GetUserProfile(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": profile})
+}
+
+// updateProfile stores the caller's gender and birth date (YYYY-MM-DD), which
+// drive event eligibility. Omitted fields are cleared.
+//
+// Responses:
+//  - 200 with the updated profile
+//  - 400 for invalid JSON or field values
+//  - 401 if the caller has no session
+//  - 404 if the account no longer exists
+//  - 500 for repository/database failures
+func (h *UserHandler) updateProfile(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload UpdateProfileParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.UpdateUserProfile(ctx, claims.UserID, payload); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update profile"})
+		return
+	}
+
+	h.respondWithProfile(c, ctx, claims.UserID)
+}
+
+// updateInterests replaces the caller's interest tags (names from /api/tags).
+//
+// Responses:
+//  - 200 with the updated profile
+//  - 400 for invalid JSON or unknown tags
+//  - 401 if the caller has no session
+//  - 500 for repository/database failures
+func (h *UserHandler) updateInterests(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload UpdateInterestsParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.SetUserInterests(ctx, claims.UserID, payload.Tags); err != nil {
+		if errors.Is(err, ErrUnknownTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update interests"})
+		return
+	}
+
+	h.respondWithProfile(c, ctx, claims.UserID)
+}
+
+func (h *UserHandler) respondWithProfile(c *gin.Context, ctx context.Context, userID int64) {
+	profile, err := h.repo.GetUserProfile(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load profile"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user": profile})
+}
+
+// deleteAccount erases the caller's account. Their messages stay in history
+// under "Deleted user", memberships and pending join requests are dropped, and
+// every issued token stops working. Live sockets are told about the removal
+// and then closed.
+//
+// Responses:
+//  - 204 on success
+//  - 401 if the caller has no session
+//  - 404 if the account is already gone
+//  - 500 for repository/database failures
+func (h *UserHandler) deleteAccount(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	conversationIDs, err := h.repo.DeleteUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account"})
+		return
+	}
+
+	for _, conversationID := range conversationIDs {
+		h.hub.NotifyMembership(conversationID, claims.UserID, "removed")
+	}
+	h.hub.DisconnectUser(claims.UserID, "account deleted")
+
+	c.Status(http.StatusNoContent)
+}
+
+// getReminderSettings returns which event reminders the caller gets pushed.
+// Everything is on until the caller saves settings.
+func (h *UserHandler) getReminderSettings(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	settings, err := h.repo.GetReminderSettings(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load reminder settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminders": settings})
+}
+
+// setReminderSettings replaces the caller's reminder preferences. Reminders
+// are still posted in event chats; these only control push notifications.
+func (h *UserHandler) setReminderSettings(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload ReminderSettingsParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	settings := ReminderSettings{DayBefore: *payload.DayBefore, HourBefore: *payload.HourBefore}
+	if err := h.repo.SetReminderSettings(ctx, claims.UserID, settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save reminder settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminders": settings})
+}
+
+// getAvailability returns the caller's current or upcoming window.
+//
+// Responses:
+//  - 200 with the window
+//  - 401 if the caller has no session
+//  - 404 if the caller hasn't said they're free (or the window has passed)
+//  - 500 for repository/database failures
+func (h *UserHandler) getAvailability(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	availability, err := h.repo.GetAvailability(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrAvailabilityNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no availability set"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"availability": availability})
+}
+
+// setAvailability broadcasts that the caller is free between starts_at and
+// ends_at (RFC 3339), replacing any previous window.
+//
+// Responses:
+//  - 200 with the stored window
+//  - 400 for invalid JSON, an empty or past window, or one longer than 24h
+//  - 401 if the caller has no session
+//  - 500 for repository/database failures
+func (h *UserHandler) setAvailability(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload SetAvailabilityParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !payload.EndsAt.After(payload.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+	if !payload.EndsAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "availability window has already ended"})
+		return
+	}
+	if payload.EndsAt.Sub(payload.StartsAt) > maxAvailabilityWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "availability window cannot exceed 24 hours"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	availability, err := h.repo.SetAvailability(ctx, claims.UserID, payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"availability": availability})
+}
+
+// clearAvailability withdraws the caller's window. Clearing when nothing is
+// set is not an error.
+func (h *UserHandler) clearAvailability(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.ClearAvailability(ctx, claims.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to clear availability"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// listAvailability is the "who else is free" feed: windows from the caller's
+// connections that haven't ended yet, soonest first.
+func (h *UserHandler) listAvailability(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	feed, err := h.repo.ListAvailabilityFeed(ctx, claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": feed})
+}
+
+// registerPushToken stores a device push token (e.g. ExponentPushToken[...])
+// for the caller. Registering a token already owned by another account moves
+// it to the caller.
+//
+// Responses:
+//  - 204 on success
+//  - 400 for invalid JSON
+//  - 401 if the caller has no session
+//  - 500 for repository/database failures
+func (h *UserHandler) registerPushToken(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload RegisterPushTokenParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RegisterPushToken(ctx, claims.UserID, payload.Token, payload.Platform); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register push token"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// removePushToken unregisters one of the caller's device tokens, typically on
+// sign-out.
+func (h *UserHandler) removePushToken(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.RemovePushToken(ctx, claims.UserID, c.Param("token")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove push token"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func userRoutes(store Store) func(*gin.RouterGroup) {
+	handler := NewUserHandler(store, newTestHub(store))
+	return handler.RegisterProtectedRoutes
+}
+
+func TestGetProfileStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"found", nil, http.StatusOK},
+		{"account gone", ErrUserNotFound, http.StatusNotFound},
+		{"store failure", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.GetUserProfileFunc = func(_ context.Context, userID int64) (*UserProfile, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &UserProfile{}, nil
+			}
+			rec := serveAs(t, userRoutes(store), testMemberID, http.MethodGet, "/api/users/me", "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestUpdateProfileStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"updated", `{"gender":"Female","birth_date":"1995-04-02"}`, nil, http.StatusOK},
+		{"unknown gender", `{"gender":"Robot"}`, nil, http.StatusBadRequest},
+		{"bad birth date", `{"birth_date":"02/04/1995"}`, nil, http.StatusBadRequest},
+		{"account gone", `{"gender":"Female"}`, ErrUserNotFound, http.StatusNotFound},
+		{"store failure", `{"gender":"Female"}`, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.UpdateUserProfileFunc = func(context.Context, int64, UpdateProfileParams) error { return tt.err }
+			store.GetUserProfileFunc = func(context.Context, int64) (*UserProfile, error) { return &UserProfile{}, nil }
+			rec := serveAs(t, userRoutes(store), testMemberID, http.MethodPut, "/api/users/me/profile", tt.body)
+			assertStatus(t, rec, tt.want)
+			if tt.want == http.StatusBadRequest && len(store.UpdateUserProfileCalls()) != 0 {
+				t.Fatal("UpdateUserProfile called for an invalid request")
+			}
+		})
+	}
+}
+
+func TestDeleteAccountStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"already gone", ErrUserNotFound, http.StatusNotFound},
+		{"store failure", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.DeleteUserFunc = func(context.Context, int64) ([]int64, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return []int64{1, 10}, nil
+			}
+			rec := serveAs(t, userRoutes(store), testMemberID, http.MethodDelete, "/api/users/me", "")
+			assertStatus(t, rec, tt.want)
+			if calls := store.DeleteUserCalls(); len(calls) != 1 || calls[0].UserID != testMemberID {
+				t.Fatalf("DeleteUser calls = %+v, want one for the caller", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Users ask to be verified with a short note (who they are, where to check),
+// an admin reviews the request, and approval sets users.verified_at. The
+// resulting `verified` flag shows on event hosts, chat participants and
+// profiles so strangers meeting up know who has been checked.
+
+const (
+	verificationPending  = "pending"
+	verificationApproved = "approved"
+	verificationRejected = "rejected"
+)
+
+var ErrAlreadyVerified = errors.New("user is already verified")
+var ErrVerificationPending = errors.New("a verification request is already pending")
+var ErrVerificationRequestNotFound = errors.New("verification request not found")
+var ErrVerificationDecided = errors.New("verification request was already decided")
+
+const createTableVerificationRequests = `
+CREATE TABLE IF NOT EXISTS user_verification_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    reason TEXT,
+    reviewed_by TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+// One pending request per user.
+const createIndexVerificationRequestsPending = `
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_requests_pending
+ON user_verification_requests(user_id) WHERE status = 'pending';
+`
+
+const insertVerificationRequest = `
+INSERT INTO user_verification_requests (user_id, note) VALUES (?, ?)
+RETURNING id;
+`
+
+const selectVerificationRequestColumns = `
+SELECT vr.id, vr.user_id, u.name, vr.note, vr.status, vr.reason, vr.created_at, vr.decided_at
+FROM user_verification_requests vr
+JOIN users u ON u.id = vr.user_id
+`
+
+const selectVerificationRequestByID = selectVerificationRequestColumns + `
+WHERE vr.id = ?;
+`
+
+const selectLatestVerificationRequest = selectVerificationRequestColumns + `
+WHERE vr.user_id = ?
+ORDER BY vr.created_at DESC, vr.id DESC
+LIMIT 1;
+`
+
+const selectVerificationRequestsByStatus = selectVerificationRequestColumns + `
+WHERE vr.status = ? AND u.deleted_at IS NULL
+ORDER BY vr.created_at, vr.id;
+`
+
+const checkPendingVerificationRequest = `
+SELECT 1 FROM user_verification_requests WHERE user_id = ? AND status = 'pending' LIMIT 1;
+`
+
+const selectUserVerified = `
+SELECT verified_at IS NOT NULL FROM users WHERE id = ? AND deleted_at IS NULL;
+`
+
+const decideVerificationRequest = `
+UPDATE user_verification_requests
+SET status = ?, reason = ?, reviewed_by = ?, decided_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+`
+
+const markUserVerified = `
+UPDATE users SET verified_at = CURRENT_TIMESTAMP WHERE id = ? AND verified_at IS NULL;
+`
+
+const deleteVerificationRequestsForUser = `
+DELETE FROM user_verification_requests WHERE user_id = ?;
+`
+
+func (r *EventRepository) initVerificationRequests(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableVerificationRequests); err != nil {
+		return fmt.Errorf("create verification requests table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexVerificationRequestsPending); err != nil {
+		return fmt.Errorf("create pending verification index: %w", err)
+	}
+	return nil
+}
+
+func scanVerificationRequest(row rowScanner) (*VerificationRequest, error) {
+	var req VerificationRequest
+	var reason sql.NullString
+	var decidedAt sql.NullTime
+	if err := row.Scan(&req.ID, &req.UserID, &req.UserName, &req.Note, &req.Status, &reason, &req.CreatedAt, &decidedAt); err != nil {
+		return nil, err
+	}
+	if reason.Valid {
+		req.Reason = &reason.String
+	}
+	if decidedAt.Valid {
+		req.DecidedAt = &decidedAt.Time
+	}
+	return &req, nil
+}
+
+// SubmitVerificationRequest asks admins to verify the user. Users who are
+// already verified, or already waiting, can't ask again.
+func (r *EventRepository) SubmitVerificationRequest(ctx context.Context, userID int64, note string) (*VerificationRequest, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin verification request tx: %w", err)
+	}
+
+	var verified bool
+	if err := tx.QueryRowContext(ctx, selectUserVerified, userID).Scan(&verified); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("check user verified: %w", err)
+	}
+	if verified {
+		tx.Rollback()
+		return nil, ErrAlreadyVerified
+	}
+	var pending int
+	if err := tx.QueryRowContext(ctx, checkPendingVerificationRequest, userID).Scan(&pending); err == nil {
+		tx.Rollback()
+		return nil, ErrVerificationPending
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return nil, fmt.Errorf("check pending verification request: %w", err)
+	}
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, insertVerificationRequest, userID, strings.TrimSpace(note)).Scan(&id); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("insert verification request: %w", err)
+	}
+	req, err := scanVerificationRequest(tx.QueryRowContext(ctx, selectVerificationRequestByID, id))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("fetch verification request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit verification request: %w", err)
+	}
+	return req, nil
+}
+
+// GetVerificationStatus reports whether the user is verified, and returns
+// their latest request, if any.
+func (r *EventRepository) GetVerificationStatus(ctx context.Context, userID int64) (bool, *VerificationRequest, error) {
+	var verified bool
+	if err := r.db.QueryRowContext(ctx, selectUserVerified, userID).Scan(&verified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil, ErrUserNotFound
+		}
+		return false, nil, fmt.Errorf("check user verified: %w", err)
+	}
+	req, err := scanVerificationRequest(r.db.QueryRowContext(ctx, selectLatestVerificationRequest, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return verified, nil, nil
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("fetch latest verification request: %w", err)
+	}
+	return verified, req, nil
+}
+
+// ListVerificationRequests returns requests with the given status, oldest
+// first, so admins work through the queue in order.
+func (r *EventRepository) ListVerificationRequests(ctx context.Context, status string) ([]VerificationRequest, error) {
+	rows, err := r.db.QueryContext(ctx, selectVerificationRequestsByStatus, status)
+	if err != nil {
+		return nil, fmt.Errorf("list verification requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []VerificationRequest{}
+	for rows.Next() {
+		req, err := scanVerificationRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan verification request: %w", err)
+		}
+		requests = append(requests, *req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate verification requests: %w", err)
+	}
+	return requests, nil
+}
+
+// DecideVerificationRequest approves or rejects a pending request on behalf
+// of the admin called reviewer. Approval marks the user verified.
+func (r *EventRepository) DecideVerificationRequest(ctx context.Context, requestID int64, approve bool, reviewer, reason string) (*VerificationRequest, error) {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin decide verification tx: %w", err)
+	}
+
+	req, err := scanVerificationRequest(tx.QueryRowContext(ctx, selectVerificationRequestByID, requestID))
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVerificationRequestNotFound
+		}
+		return nil, fmt.Errorf("fetch verification request: %w", err)
+	}
+	if req.Status != verificationPending {
+		tx.Rollback()
+		return nil, ErrVerificationDecided
+	}
+
+	status := verificationRejected
+	if approve {
+		status = verificationApproved
+	}
+	storedReason := sql.NullString{}
+	if trimmed := strings.TrimSpace(reason); trimmed != "" {
+		storedReason = sql.NullString{String: trimmed, Valid: true}
+	}
+	if _, err := tx.ExecContext(ctx, decideVerificationRequest, status, storedReason, reviewer, requestID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("decide verification request: %w", err)
+	}
+	if approve {
+		if _, err := tx.ExecContext(ctx, markUserVerified, req.UserID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("mark user verified: %w", err)
+		}
+	}
+
+	req, err = scanVerificationRequest(tx.QueryRowContext(ctx, selectVerificationRequestByID, requestID))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("fetch decided verification request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit verification decision: %w", err)
+	}
+	return req, nil
+}
+
+// NotifyVerificationDecision sends `verification:decided` to the user's
+// sockets.
+func (h *ChatHub) NotifyVerificationDecision(req VerificationRequest) {
+	payload, err := json.Marshal(gin.H{"type": "verification:decided", "request": req, "decision": req.Status})
+	if err != nil {
+		log.Printf("marshal verification decision failed: %v", err)
+		return
+	}
+	h.NotifyUser(req.UserID, payload)
+}
+
+// getVerification returns whether the caller is verified and their latest
+// verification request.
+//
+// Responses:
+//  - 200 with `verified` and `request` (null if they never asked)
+//  - 401 if the caller has no session
+//  - 404 if the account no longer exists
+//  - 500 for repository/database failures
+func (h *UserHandler) getVerification(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	verified, req, err := h.repo.GetVerificationStatus(ctx, claims.UserID)
+	if err != nil {
+		respondVerificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"verified": verified, "request": req})
+}
+
+// requestVerification asks admins to verify the caller. The optional note
+// tells the reviewer who they are and how to check.
+//
+// Responses:
+//  - 201 with the pending `request`
+//  - 401 if the caller has no session
+//  - 400 for invalid JSON or a note over 500 characters
+//  - 404 if the account no longer exists
+//  - 409 if the caller is already verified or has a request pending
+//  - 500 for repository/database failures
+func (h *UserHandler) requestVerification(c *gin.Context) {
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload RequestVerificationParams
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	req, err := h.repo.SubmitVerificationRequest(ctx, claims.UserID, payload.Note)
+	if err != nil {
+		respondVerificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"request": req})
+}
+
+// VerificationHandler serves the admin review queue for verification
+// requests.
+type VerificationHandler struct {
+	repo VerificationStore
+	hub  *ChatHub
+}
+
+func NewVerificationHandler(repo VerificationStore, hub *ChatHub) *VerificationHandler {
+	return &VerificationHandler{repo: repo, hub: hub}
+}
+
+// registerAdminVerificationRoutes mounts the review queue under /admin behind
+// admin auth.
+func registerAdminVerificationRoutes(r *gin.Engine, adminAuth gin.HandlerFunc, handler *VerificationHandler) {
+	admin := r.Group("/admin", adminAuth)
+	admin.GET("/verification-requests", handler.listVerificationRequests)
+	admin.POST("/verification-requests/:id/approve", handler.approveVerification)
+	admin.POST("/verification-requests/:id/reject", handler.rejectVerification)
+}
+
+// listVerificationRequests returns the queue; `?status=` picks approved or
+// rejected requests instead of pending ones.
+func (h *VerificationHandler) listVerificationRequests(c *gin.Context) {
+	status := c.DefaultQuery("status", verificationPending)
+	switch status {
+	case verificationPending, verificationApproved, verificationRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved or rejected"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	requests, err := h.repo.ListVerificationRequests(ctx, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list verification requests"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
+
+// approveVerification marks the requester verified and tells them.
+func (h *VerificationHandler) approveVerification(c *gin.Context) {
+	h.decideVerification(c, true)
+}
+
+// rejectVerification turns the request down, with an optional `reason` the
+// requester sees. They may ask again.
+func (h *VerificationHandler) rejectVerification(c *gin.Context) {
+	h.decideVerification(c, false)
+}
+
+func (h *VerificationHandler) decideVerification(c *gin.Context, approve bool) {
+	requestID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || requestID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request id"})
+		return
+	}
+
+	var payload DecideVerificationParams
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	req, err := h.repo.DecideVerificationRequest(ctx, requestID, approve, c.GetString(gin.AuthUserKey), payload.Reason)
+	if err != nil {
+		respondVerificationError(c, err)
+		return
+	}
+	h.hub.NotifyVerificationDecision(*req)
+
+	c.JSON(http.StatusOK, gin.H{"request": req})
+}
+
+func respondVerificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, ErrVerificationRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "verification request not found"})
+	case errors.Is(err, ErrAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": "you're already verified"})
+	case errors.Is(err, ErrVerificationPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "your verification request is still being reviewed"})
+	case errors.Is(err, ErrVerificationDecided):
+		c.JSON(http.StatusConflict, gin.H{"error": "verification request was already decided"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update verification"})
+	}
+}
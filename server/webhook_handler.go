@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler manages webhook registrations. The same handlers serve hosts
+// under /api/webhooks and admins under /admin/webhooks.
+type WebhookHandler struct {
+	repo WebhookStore
+}
+
+func NewWebhookHandler(repo WebhookStore) *WebhookHandler {
+	return &WebhookHandler{repo: repo}
+}
+
+func (h *WebhookHandler) RegisterProtectedRoutes(group *gin.RouterGroup) {
+	group.GET("/webhooks", h.listWebhooks)
+	group.POST("/webhooks", h.createWebhook)
+	group.DELETE("/webhooks/:id", h.deleteWebhook)
+	group.GET("/webhooks/:id/deliveries", h.listDeliveries)
+}
+
+// registerAdminWebhookRoutes mounts the handlers under /admin behind admin
+// auth. Admin webhooks receive every event, not just one host's.
+func registerAdminWebhookRoutes(r *gin.Engine, adminAuth gin.HandlerFunc, handler *WebhookHandler) {
+	handler.RegisterProtectedRoutes(r.Group("/admin", adminAuth))
+}
+
+// webhookOwner is whose webhooks the request manages: 0 for an admin, else
+// the signed-in host.
+func webhookOwner(c *gin.Context) (int64, bool) {
+	if _, ok := c.Get(gin.AuthUserKey); ok {
+		return 0, true
+	}
+	claims, ok := sessionFromContext(c)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// listWebhooks returns the caller's webhooks. Secrets are not included.
+func (h *WebhookHandler) listWebhooks(c *gin.Context) {
+	ownerID, ok := webhookOwner(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	hooks, err := h.repo.ListWebhooks(ctx, ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": hooks})
+}
+
+// createWebhook registers `url` for the listed `events` (event.created,
+// join_request.created, message.created). A host's webhook fires for events
+// they host and their chats. Each POST is signed with the optional `secret`,
+// or a generated one; the secret is returned only here.
+//
+// Responses:
+//  - 201 with the webhook and its secret
+//  - 400 for invalid JSON, an unknown event type, or a URL that isn't HTTPS
+//  - 401 if the caller has no session
+//  - 409 if the caller already has the maximum number of webhooks
+//  - 500 for repository/database failures
+func (h *WebhookHandler) createWebhook(c *gin.Context) {
+	ownerID, ok := webhookOwner(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	var payload CreateWebhookParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !webhookURLAllowed(payload.URL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "webhook url must use https"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	hook, secret, err := h.repo.CreateWebhook(ctx, ownerID, payload)
+	if err != nil {
+		if errors.Is(err, ErrWebhookLimitReached) {
+			c.JSON(http.StatusConflict, gin.H{"error": "webhook limit reached", "limit": maxWebhooksPerOwner})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create webhook"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"webhook": hook, "secret": secret})
+}
+
+// deleteWebhook removes one of the caller's webhooks; queued deliveries to it
+// are dropped.
+//
+// Responses:
+//  - 204 on success
+//  - 400 for an invalid id
+//  - 401 if the caller has no session
+//  - 404 if the caller has no such webhook
+//  - 500 for repository/database failures
+func (h *WebhookHandler) deleteWebhook(c *gin.Context) {
+	ownerID, ok := webhookOwner(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	webhookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || webhookID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	if err := h.repo.DeleteWebhook(ctx, ownerID, webhookID); err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete webhook"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// listDeliveries returns the delivery log of one of the caller's webhooks:
+// the latest 50 payloads with their status, attempts and last response.
+//
+// Responses:
+//  - 200 with the deliveries, newest first
+//  - 400 for an invalid id
+//  - 401 if the caller has no session
+//  - 404 if the caller has no such webhook
+//  - 500 for repository/database failures
+func (h *WebhookHandler) listDeliveries(c *gin.Context) {
+	ownerID, ok := webhookOwner(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing session"})
+		return
+	}
+
+	webhookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || webhookID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout)
+	defer cancel()
+
+	deliveries, err := h.repo.ListWebhookDeliveries(ctx, ownerID, webhookID)
+	if err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load webhook deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": deliveries})
+}
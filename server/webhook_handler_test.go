@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func webhookRoutes(store WebhookStore) func(*gin.RouterGroup) {
+	return NewWebhookHandler(store).RegisterProtectedRoutes
+}
+
+func TestCreateWebhookStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"created", `{"url":"https://hooks.example.com/in","events":["message.created"]}`, nil, http.StatusCreated},
+		{"unknown event", `{"url":"https://hooks.example.com/in","events":["user.created"]}`, nil, http.StatusBadRequest},
+		{"no events", `{"url":"https://hooks.example.com/in","events":[]}`, nil, http.StatusBadRequest},
+		{"plain http", `{"url":"http://hooks.example.com/in","events":["message.created"]}`, nil, http.StatusBadRequest},
+		{"limit reached", `{"url":"https://hooks.example.com/in","events":["message.created"]}`, ErrWebhookLimitReached, http.StatusConflict},
+		{"store failure", `{"url":"https://hooks.example.com/in","events":["message.created"]}`, errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WEBHOOK_ALLOW_PRIVATE", "")
+			store := newTestStore()
+			store.CreateWebhookFunc = func(_ context.Context, ownerID int64, params CreateWebhookParams) (*Webhook, string, error) {
+				if tt.err != nil {
+					return nil, "", tt.err
+				}
+				return &Webhook{ID: 1, URL: params.URL, Events: params.Events}, "generated-secret", nil
+			}
+			rec := serveAs(t, webhookRoutes(store), testHostID, http.MethodPost, "/api/webhooks", tt.body)
+			assertStatus(t, rec, tt.want)
+			if tt.want == http.StatusCreated {
+				if calls := store.CreateWebhookCalls(); len(calls) != 1 || calls[0].OwnerID != testHostID {
+					t.Fatalf("CreateWebhook calls = %+v, want one owned by the host", calls)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteWebhookStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"deleted", "/api/webhooks/1", nil, http.StatusNoContent},
+		{"invalid id", "/api/webhooks/0", nil, http.StatusBadRequest},
+		{"not the caller's", "/api/webhooks/1", ErrWebhookNotFound, http.StatusNotFound},
+		{"store failure", "/api/webhooks/1", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.DeleteWebhookFunc = func(context.Context, int64, int64) error { return tt.err }
+			rec := serveAs(t, webhookRoutes(store), testHostID, http.MethodDelete, tt.path, "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+func TestListWebhookDeliveriesStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"listed", "/api/webhooks/1/deliveries", nil, http.StatusOK},
+		{"invalid id", "/api/webhooks/abc/deliveries", nil, http.StatusBadRequest},
+		{"not the caller's", "/api/webhooks/1/deliveries", ErrWebhookNotFound, http.StatusNotFound},
+		{"store failure", "/api/webhooks/1/deliveries", errStoreDown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore()
+			store.ListWebhookDeliveriesFunc = func(context.Context, int64, int64) ([]WebhookDelivery, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return []WebhookDelivery{}, nil
+			}
+			rec := serveAs(t, webhookRoutes(store), testHostID, http.MethodGet, tt.path, "")
+			assertStatus(t, rec, tt.want)
+		})
+	}
+}
@@ -0,0 +1,756 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Webhook event types.
+const (
+	webhookEventCreated       = "event.created"
+	webhookJoinRequestCreated = "join_request.created"
+	webhookMessageCreated     = "message.created"
+)
+
+const (
+	// maxWebhooksPerOwner caps registrations per host, and for admins.
+	maxWebhooksPerOwner = 10
+	// webhookDeliveryLogLimit is how many recent deliveries the log returns.
+	webhookDeliveryLogLimit = 50
+	// webhookSignatureHeader carries `t=<unix seconds>,v1=<hex HMAC-SHA256>`
+	// of "<t>.<body>" under the webhook's secret.
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+var (
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrWebhookLimitReached  = errors.New("webhook limit reached")
+	errWebhookAddressDenied = errors.New("webhook address is not public")
+)
+
+const createTableWebhooks = `
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+`
+
+const createTableWebhookDeliveries = `
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    outbox_id INTEGER NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    delivered_at DATETIME,
+    UNIQUE (outbox_id, webhook_id),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id)
+);
+`
+
+const createIndexWebhookDeliveries = `
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
+`
+
+// webhookSubscribed matches a webhook's comma-separated events against a
+// `%,type,%` pattern.
+const webhookSubscribed = `(',' || events || ',') LIKE ?`
+
+const insertWebhook = `
+INSERT INTO webhooks (user_id, url, secret, events)
+VALUES (?, ?, ?, ?)
+RETURNING id, user_id, url, events, created_at;
+`
+
+const countWebhooksByOwner = `
+SELECT COUNT(*) FROM webhooks WHERE user_id IS ?;
+`
+
+const selectWebhooksByOwner = `
+SELECT id, user_id, url, events, created_at
+FROM webhooks
+WHERE user_id IS ?
+ORDER BY id;
+`
+
+const selectWebhookByOwner = `
+SELECT id, user_id, url, events, created_at
+FROM webhooks
+WHERE id = ? AND user_id IS ?;
+`
+
+const deleteWebhook = `
+DELETE FROM webhooks WHERE id = ? AND user_id IS ?;
+`
+
+const deleteDeliveriesForWebhook = `
+DELETE FROM webhook_deliveries WHERE webhook_id = ?;
+`
+
+const deleteWebhookDeliveriesForOwner = `
+DELETE FROM webhook_deliveries
+WHERE webhook_id IN (SELECT id FROM webhooks WHERE user_id = ?);
+`
+
+const deleteWebhooksForOwner = `
+DELETE FROM webhooks WHERE user_id = ?;
+`
+
+const selectWebhookDeliveries = `
+SELECT id, webhook_id, event_type, payload, status, attempts, response_status, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE webhook_id = ?
+ORDER BY id DESC
+LIMIT ?;
+`
+
+// insertWebhookEventIfSubscribed queues a fan-out only when some webhook
+// listens for the type, so ordinary writes don't pay for unused webhooks.
+const insertWebhookEventIfSubscribed = `
+INSERT INTO outbox (kind, payload)
+SELECT ?, ?
+WHERE EXISTS (SELECT 1 FROM webhooks WHERE ` + webhookSubscribed + `);
+`
+
+// selectWebhookSubscribers skips webhooks whose owner has been erased, in
+// case one outlives its account.
+const selectWebhookSubscribers = `
+SELECT w.id FROM webhooks w
+LEFT JOIN users u ON u.id = w.user_id
+WHERE (w.user_id IS NULL OR (w.user_id = ? AND u.deleted_at IS NULL)) AND ` + webhookSubscribed + `;
+`
+
+const insertWebhookDelivery = `
+INSERT OR IGNORE INTO webhook_deliveries (webhook_id, outbox_id, event_type, payload)
+VALUES (?, ?, ?, ?);
+`
+
+const selectWebhookDeliveryTarget = `
+SELECT d.event_type, d.payload, w.url, w.secret
+FROM webhook_deliveries d
+JOIN webhooks w ON w.id = d.webhook_id
+WHERE d.id = ?;
+`
+
+const updateWebhookDeliveryAttempt = `
+UPDATE webhook_deliveries
+SET status = ?, attempts = ?, response_status = ?, last_error = ?,
+    delivered_at = CASE WHEN ? = 'delivered' THEN CURRENT_TIMESTAMP ELSE delivered_at END
+WHERE id = ?;
+`
+
+const deleteFinishedWebhookDeliveries = `
+DELETE FROM webhook_deliveries
+WHERE status IN ('delivered', 'failed') AND created_at < ?;
+`
+
+// outboxWebhookEventPayload is the payload of a webhook:event entry: what
+// happened, resolved into subscriber deliveries by the dispatcher.
+type outboxWebhookEventPayload struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+// outboxWebhookDeliveryPayload is the payload of a webhook:deliver entry.
+type outboxWebhookDeliveryPayload struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+// webhookEnvelope is the JSON body POSTed to webhooks.
+type webhookEnvelope struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// webhookTarget is what a delivery attempt needs to send.
+type webhookTarget struct {
+	eventType string
+	payload   []byte
+	url       string
+	secret    string
+}
+
+func (r *EventRepository) initWebhooks(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, createTableWebhooks); err != nil {
+		return fmt.Errorf("create webhooks table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createTableWebhookDeliveries); err != nil {
+		return fmt.Errorf("create webhook deliveries table: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, createIndexWebhookDeliveries); err != nil {
+		return fmt.Errorf("create webhook deliveries index: %w", err)
+	}
+	return nil
+}
+
+func webhookEventPattern(eventType string) string {
+	return "%," + eventType + ",%"
+}
+
+// webhookOwnerArg is a webhook owner as stored: the host's user ID, or NULL
+// for admin webhooks (ownerID 0).
+func webhookOwnerArg(ownerID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: ownerID, Valid: ownerID > 0}
+}
+
+func scanWebhook(row rowScanner) (Webhook, error) {
+	var hook Webhook
+	var userID sql.NullInt64
+	var events string
+	if err := row.Scan(&hook.ID, &userID, &hook.URL, &events, &hook.CreatedAt); err != nil {
+		return Webhook{}, err
+	}
+	if userID.Valid {
+		hook.UserID = &userID.Int64
+	}
+	hook.Events = strings.Split(events, ",")
+	return hook, nil
+}
+
+// webhookEventList drops repeated event types, keeping the given order.
+func webhookEventList(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	list := make([]string, 0, len(events))
+	for _, eventType := range events {
+		if !seen[eventType] {
+			seen[eventType] = true
+			list = append(list, eventType)
+		}
+	}
+	return list
+}
+
+// newWebhookSecret returns 32 random bytes, hex encoded.
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// CreateWebhook registers a webhook for ownerID (0 for an admin webhook) and
+// returns it with its secret, which is never shown again.
+func (r *EventRepository) CreateWebhook(ctx context.Context, ownerID int64, params CreateWebhookParams) (*Webhook, string, error) {
+	secret := params.Secret
+	if secret == "" {
+		generated, err := newWebhookSecret()
+		if err != nil {
+			return nil, "", err
+		}
+		secret = generated
+	}
+
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("begin create webhook tx: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, countWebhooksByOwner, webhookOwnerArg(ownerID)).Scan(&count); err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("count webhooks: %w", err)
+	}
+	if count >= maxWebhooksPerOwner {
+		tx.Rollback()
+		return nil, "", ErrWebhookLimitReached
+	}
+
+	hook, err := scanWebhook(tx.QueryRowContext(ctx, insertWebhook, webhookOwnerArg(ownerID), params.URL, secret, strings.Join(webhookEventList(params.Events), ",")))
+	if err != nil {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("insert webhook: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("commit webhook: %w", err)
+	}
+	return &hook, secret, nil
+}
+
+// ListWebhooks returns ownerID's webhooks, oldest first.
+func (r *EventRepository) ListWebhooks(ctx context.Context, ownerID int64) ([]Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, selectWebhooksByOwner, webhookOwnerArg(ownerID))
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook: %w", err)
+		}
+		hooks = append(hooks, hook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes one of ownerID's webhooks and its delivery log.
+// Deliveries still queued are dropped when they come up.
+func (r *EventRepository) DeleteWebhook(ctx context.Context, ownerID, webhookID int64) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin delete webhook tx: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, deleteWebhook, webhookID, webhookOwnerArg(ownerID))
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete webhook rows affected: %w", err)
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return ErrWebhookNotFound
+	}
+	if _, err := tx.ExecContext(ctx, deleteDeliveriesForWebhook, webhookID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete webhook: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the latest deliveries to one of ownerID's
+// webhooks, newest first.
+func (r *EventRepository) ListWebhookDeliveries(ctx context.Context, ownerID, webhookID int64) ([]WebhookDelivery, error) {
+	if _, err := scanWebhook(r.db.QueryRowContext(ctx, selectWebhookByOwner, webhookID, webhookOwnerArg(ownerID))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("fetch webhook: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectWebhookDeliveries, webhookID, webhookDeliveryLogLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []WebhookDelivery{}
+	for rows.Next() {
+		var delivery WebhookDelivery
+		var responseStatus sql.NullInt64
+		var lastError sql.NullString
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventType, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &responseStatus, &lastError, &delivery.CreatedAt, &deliveredAt); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		if responseStatus.Valid {
+			code := int(responseStatus.Int64)
+			delivery.ResponseStatus = &code
+		}
+		if lastError.Valid {
+			delivery.LastError = &lastError.String
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// enqueueWebhookEvent announces a change to webhooks in tx, like
+// enqueueOutbox. It reports whether anything was queued; if so, call
+// signalOutbox once tx commits.
+func enqueueWebhookEvent(ctx context.Context, tx *sql.Tx, eventType string, id int64) (bool, error) {
+	encoded, err := json.Marshal(outboxWebhookEventPayload{Type: eventType, ID: id})
+	if err != nil {
+		return false, fmt.Errorf("encode webhook event: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, insertWebhookEventIfSubscribed, outboxWebhookEvent, string(encoded), webhookEventPattern(eventType))
+	if err != nil {
+		return false, fmt.Errorf("insert webhook event: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("insert webhook event rows affected: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// FanOutWebhookEvent records a delivery of body to each webhook subscribed to
+// eventType that may see it: admin webhooks, and hostID's own. Each delivery
+// gets its own outbox entry so webhooks retry independently. Replaying the
+// same outboxID adds nothing.
+func (r *EventRepository) FanOutWebhookEvent(ctx context.Context, outboxID int64, eventType string, hostID int64, body []byte) error {
+	tx, err := r.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin webhook fan-out tx: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, selectWebhookSubscribers, hostID, webhookEventPattern(eventType))
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("list webhook subscribers: %w", err)
+	}
+	var webhookIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return fmt.Errorf("scan webhook subscriber: %w", err)
+		}
+		webhookIDs = append(webhookIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		tx.Rollback()
+		return fmt.Errorf("iterate webhook subscribers: %w", err)
+	}
+	rows.Close()
+
+	queued := false
+	for _, webhookID := range webhookIDs {
+		res, err := tx.ExecContext(ctx, insertWebhookDelivery, webhookID, outboxID, eventType, string(body))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("insert webhook delivery: %w", err)
+		}
+		deliveryID, err := res.LastInsertId()
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("fetch webhook delivery id: %w", err)
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			continue
+		}
+		if err := enqueueOutbox(ctx, tx, outboxWebhookDelivery, outboxWebhookDeliveryPayload{DeliveryID: deliveryID}); err != nil {
+			tx.Rollback()
+			return err
+		}
+		queued = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit webhook fan-out: %w", err)
+	}
+	if queued {
+		r.signalOutbox()
+	}
+	return nil
+}
+
+// getWebhookDeliveryTarget loads a queued delivery with its webhook's URL and
+// secret. ErrWebhookNotFound means the webhook was deleted meanwhile.
+func (r *EventRepository) getWebhookDeliveryTarget(ctx context.Context, deliveryID int64) (*webhookTarget, error) {
+	var target webhookTarget
+	var payload string
+	err := r.db.QueryRowContext(ctx, selectWebhookDeliveryTarget, deliveryID).Scan(&target.eventType, &payload, &target.url, &target.secret)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("fetch webhook delivery: %w", err)
+	}
+	target.payload = []byte(payload)
+	return &target, nil
+}
+
+// recordWebhookAttempt updates the delivery log after an attempt.
+// responseStatus is 0 when no response arrived.
+func (r *EventRepository) recordWebhookAttempt(ctx context.Context, deliveryID int64, status string, attempts, responseStatus int, deliveryErr error) error {
+	var lastError sql.NullString
+	if deliveryErr != nil {
+		lastError = sql.NullString{String: deliveryErr.Error(), Valid: true}
+	}
+	code := sql.NullInt64{Int64: int64(responseStatus), Valid: responseStatus > 0}
+	if _, err := r.db.ExecContext(ctx, updateWebhookDeliveryAttempt, status, attempts, code, lastError, status, deliveryID); err != nil {
+		return fmt.Errorf("record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// PruneWebhookDeliveries deletes finished deliveries created before cutoff.
+func (r *EventRepository) PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteFinishedWebhookDeliveries, sqliteTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("prune webhook deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// registerWebhookDeliveryPruning keeps the delivery log as long as the outbox
+// keeps its rows.
+func registerWebhookDeliveryPruning(runner *JobRunner, repo *EventRepository) {
+	runner.Register("webhook_delivery_prune", 24*time.Hour, func(ctx context.Context) error {
+		pruneCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+
+		pruned, err := repo.PruneWebhookDeliveries(pruneCtx, time.Now().Add(-outboxRetention))
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Printf("pruned %d webhook deliveries", pruned)
+		}
+		return nil
+	})
+}
+
+// webhookSender POSTs signed payloads. Unless private addresses are allowed,
+// it only connects to public addresses, so a host can't point a webhook at
+// the server's own network.
+type webhookSender struct {
+	client *http.Client
+}
+
+// webhookAllowPrivate reads WEBHOOK_ALLOW_PRIVATE. Set to true for local
+// development, it permits plain-HTTP webhooks on private addresses.
+func webhookAllowPrivate() bool {
+	return os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true"
+}
+
+// webhookURLAllowed reports whether webhooks may be registered for raw.
+func webhookURLAllowed(raw string) bool {
+	return strings.HasPrefix(raw, "https://") || (webhookAllowPrivate() && strings.HasPrefix(raw, "http://"))
+}
+
+func newWebhookSenderFromEnv() *webhookSender {
+	dialer := &net.Dialer{Timeout: pushTimeout}
+	if !webhookAllowPrivate() {
+		dialer.Control = publicAddressControl(errWebhookAddressDenied)
+	}
+	return &webhookSender{
+		client: &http.Client{
+			Timeout:   pushTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: pushTimeout},
+			// A redirect would sidestep the URL check, so it counts as a
+			// failed delivery.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// nonPublicNetworks are special-purpose ranges the net.IP predicates miss:
+// carrier-grade NAT, IETF protocol assignments, benchmarking, and NAT64,
+// which would reach any IPv4 address, private ones included, through a
+// gateway.
+var nonPublicNetworks = mustParseCIDRs("100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "64:ff9b::/96")
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicAddressControl is a net.Dialer Control hook that fails with denied
+// unless the address is public. It runs after DNS resolution, so a public
+// name that resolves to a private address is refused too.
+func publicAddressControl(denied error) func(network, address string, c syscall.RawConn) error {
+	return func(_, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+			return denied
+		}
+		return nil
+	}
+}
+
+// signWebhook returns the webhookSignatureHeader value for body sent at ts.
+func signWebhook(secret string, ts time.Time, body []byte) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send makes one delivery attempt, returning the response status (0 if none)
+// and an error unless the webhook answered 2xx.
+func (s *webhookSender) send(ctx context.Context, deliveryID int64, target *webhookTarget) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.url, bytes.NewReader(target.payload))
+	if err != nil {
+		return 0, fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "who-else-is-free-webhooks/1")
+	req.Header.Set("X-Webhook-Event", target.eventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(webhookSignatureHeader, signWebhook(target.secret, time.Now(), target.payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// fanOutWebhookEvent snapshots what a webhook:event entry announces and
+// queues a delivery for each subscriber.
+func (d *OutboxDispatcher) fanOutWebhookEvent(ctx context.Context, entry outboxEntry) error {
+	var payload outboxWebhookEventPayload
+	if err := json.Unmarshal(entry.payload, &payload); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+
+	var data any
+	var hostID int64
+	switch payload.Type {
+	case webhookEventCreated:
+		events, err := d.repo.GetEventsByIDs(ctx, []int64{payload.ID}, 0)
+		if err != nil {
+			return err
+		}
+		evt, ok := events[payload.ID]
+		if !ok {
+			return nil
+		}
+		data, hostID = evt, evt.UserID
+	case webhookJoinRequestCreated:
+		req, err := d.repo.GetJoinRequestByID(ctx, payload.ID)
+		if errors.Is(err, ErrJoinRequestNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		evt, err := d.repo.GetEventByID(ctx, req.EventID)
+		if errors.Is(err, ErrEventNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data, hostID = req, evt.UserID
+	case webhookMessageCreated:
+		msg, err := d.repo.GetMessageByID(ctx, payload.ID)
+		if errors.Is(err, ErrMessageNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data = msg
+		// Only event chats have a host; other conversations reach admin
+		// webhooks alone.
+		convo, err := d.repo.GetConversation(ctx, msg.ConversationID)
+		if err != nil {
+			return err
+		}
+		if convo.EventID != nil {
+			evt, err := d.repo.GetEventByID(ctx, *convo.EventID)
+			if err != nil && !errors.Is(err, ErrEventNotFound) {
+				return err
+			}
+			if evt != nil {
+				hostID = evt.UserID
+			}
+		}
+	default:
+		return fmt.Errorf("unknown webhook event %q", payload.Type)
+	}
+
+	body, err := json.Marshal(webhookEnvelope{Type: payload.Type, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encode webhook body: %w", err)
+	}
+	return d.repo.FanOutWebhookEvent(ctx, entry.id, payload.Type, hostID, body)
+}
+
+// deliverWebhook sends a webhook:deliver entry and logs the attempt. The
+// outbox decides on retries; the log just mirrors them.
+func (d *OutboxDispatcher) deliverWebhook(ctx context.Context, entry outboxEntry) error {
+	var payload outboxWebhookDeliveryPayload
+	if err := json.Unmarshal(entry.payload, &payload); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	target, err := d.repo.getWebhookDeliveryTarget(ctx, payload.DeliveryID)
+	if errors.Is(err, ErrWebhookNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	responseStatus, deliveryErr := d.webhooks.send(ctx, payload.DeliveryID, target)
+	status := "delivered"
+	if deliveryErr != nil {
+		status = "pending"
+		if entry.attempts >= outboxMaxAttempts {
+			status = "failed"
+		}
+	}
+
+	// A fresh context: the attempt may have used up ctx.
+	logCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	if err := d.repo.recordWebhookAttempt(logCtx, payload.DeliveryID, status, entry.attempts, responseStatus, deliveryErr); err != nil {
+		log.Printf("webhook delivery %d: %v", payload.DeliveryID, err)
+	}
+	return deliveryErr
+}
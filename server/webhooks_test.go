@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+// A webhook whose owner was soft-deleted without going through DeleteUser
+// must not receive the owner's events.
+func TestFanOutWebhookEventSkipsDeletedOwners(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	hostID := mustCreateUser(t, repo, "ava")
+
+	params := CreateWebhookParams{URL: "https://hooks.example.com/in", Events: []string{webhookMessageCreated}}
+	if _, _, err := repo.CreateWebhook(ctx, hostID, params); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	if _, _, err := repo.CreateWebhook(ctx, 0, params); err != nil {
+		t.Fatalf("create admin webhook: %v", err)
+	}
+	if _, err := repo.db.Exec(`UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, hostID); err != nil {
+		t.Fatalf("soft-delete user: %v", err)
+	}
+
+	if err := repo.FanOutWebhookEvent(ctx, 1, webhookMessageCreated, hostID, []byte(`{}`)); err != nil {
+		t.Fatalf("fan out: %v", err)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id WHERE w.user_id IS NULL`); got != 1 {
+		t.Errorf("admin deliveries = %d, want 1", got)
+	}
+	if got := countRows(t, repo, `SELECT COUNT(*) FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id WHERE w.user_id = ?`, hostID); got != 0 {
+		t.Errorf("deliveries to deleted owner = %d, want 0", got)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"100.63.255.255", true},
+		{"100.128.0.0", true},
+		{"198.20.0.0", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"ff02::1", false},
+		{"100.64.0.1", false},          // carrier-grade NAT
+		{"100.127.255.255", false},     // carrier-grade NAT
+		{"192.0.0.8", false},           // IETF protocol assignments
+		{"198.18.0.1", false},          // benchmarking
+		{"198.19.255.255", false},      // benchmarking
+		{"64:ff9b::a00:1", false},      // NAT64 for 10.0.0.1
+		{"64:ff9b::5db8:d822", false},  // NAT64 for a public address is refused too
+		{"::ffff:100.64.0.1", false},   // IPv4-mapped carrier-grade NAT
+		{"::ffff:192.168.1.1", false},  // IPv4-mapped private
+		{"::ffff:93.184.216.34", true}, // IPv4-mapped public
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if ip == nil {
+			t.Fatalf("bad test address %q", tt.ip)
+		}
+		if got := isPublicIP(ip); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}